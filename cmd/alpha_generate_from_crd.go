@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/internal"
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crdimport"
+)
+
+type generateFromCRDError struct {
+	err error
+}
+
+func (e generateFromCRDError) Error() string {
+	return fmt.Sprintf("failed to generate the APIs from the CRDs: %v", e.err)
+}
+
+func newGenerateFromCRDCmd() *cobra.Command {
+	options := &generateFromCRDOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "generate-from-crd",
+		Short: "Scaffold the APIs of existing CustomResourceDefinitions",
+		Long: `Scaffold the APIs of existing CustomResourceDefinitions, e.g. from another project, so that they are
+reconciled by the controllers of this project.
+
+The Go types of every version of the CRDs are generated from their OpenAPI schema: the fields have the json tags
+of the properties, and the validations, defaults and list types of the schema are written as markers, so that
+make manifests regenerates an equivalent CRD. The APIs are then registered like the ones created with
+kubebuilder create api, and a controller is scaffolded for the storage version of each Kind.
+
+The API group of the CRDs has to be in the domain of the project, as the generated types are registered with it.
+The Kinds of the groups of other domains are reconciled with the Go types of the project that defines them, by
+kubebuilder create controller with --external-api-path and --external-api-domain, or have their types generated
+in a project initialized with their domain.
+
+The parts of the schema that can't be written as Go types or markers, e.g. the default values that are objects,
+are reported as warnings.
+`,
+		Example: `	# Scaffold the API and the controller of the Frigate CRD
+	kubebuilder alpha generate-from-crd --crd frigates.ship.example.com.yaml
+
+	# Scaffold the APIs of the CRDs of a cluster without their controllers
+	kubectl get crd frigates.ship.example.com destroyers.ship.example.com -o yaml > crds.yaml
+	kubebuilder alpha generate-from-crd --crd crds.yaml --controller=false
+
+	# Reconcile the Certificates of cert-manager.io, outside of the domain of the project, with their own types
+	kubebuilder create controller --group cert-manager --version v1alpha2 --kind Certificate \
+		--external-api-path github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2 --external-api-domain io
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(generateFromCRDError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &generateFromCRDOptions{}
+
+type generateFromCRDOptions struct {
+	// file is the path to the YAML file of the CRDs
+	file string
+	crds []*crdimport.CRD
+	// resources are the validated resources of the versions of each CRD
+	resources [][]*resource.Resource
+
+	// doController indicates whether to scaffold the controller of the storage version of each Kind
+	doController bool
+
+	// runMake indicates whether to run make or not after scaffolding
+	runMake bool
+}
+
+func (o *generateFromCRDOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.file, "crd", "",
+		"path to a YAML file with the CustomResourceDefinitions, e.g. the output of kubectl get crd -o yaml")
+	cmd.Flags().BoolVar(&o.doController, "controller", true,
+		"if set, scaffold a controller for the storage version of each Kind")
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after generating files")
+}
+
+func (o *generateFromCRDOptions) loadConfig() (*config.Config, error) {
+	if o.file == "" {
+		return nil, errors.New("a file with the CustomResourceDefinitions must be provided with --crd")
+	}
+
+	content, err := ioutil.ReadFile(o.file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the CRDs: %v", err)
+	}
+	if o.crds, err = crdimport.Load(content); err != nil {
+		return nil, fmt.Errorf("unable to parse the CRDs of %s: %v", o.file, err)
+	}
+
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *generateFromCRDOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("generating APIs from CRDs is not supported for project version %s", c.Version)
+	}
+	if c.APIServer {
+		return errors.New("APIs can't be generated from CRDs in aggregated API server projects")
+	}
+	if len(o.crds) == 0 {
+		return fmt.Errorf("%s has no CustomResourceDefinition", o.file)
+	}
+
+	groups := make(map[string]struct{})
+	for _, group := range c.ResourceGroups() {
+		groups[group] = struct{}{}
+	}
+	o.resources = make([][]*resource.Resource, 0, len(o.crds))
+	for _, crd := range o.crds {
+		resources, err := crd.Resources(c.Domain)
+		if err != nil {
+			return err
+		}
+		for _, res := range resources {
+			if err := c.SetGroupPackage(res); err != nil {
+				return err
+			}
+			if err := res.Validate(); err != nil {
+				return fmt.Errorf("invalid resource %s/%s, Kind=%s: %v", res.Group, res.Version, res.Kind, err)
+			}
+			if c.HasResource(res) {
+				return fmt.Errorf("%s/%s, Kind=%s already exists", res.Group, res.Version, res.Kind)
+			}
+
+			// Check the group is the same for single-group projects
+			groups[strings.ToLower(res.Group)] = struct{}{}
+			if !c.MultiGroup && len(groups) > 1 {
+				return fmt.Errorf("multiple groups are not allowed by default, to enable multi-group visit %s",
+					"kubebuilder.io/migration/multi-group.html")
+			}
+		}
+		o.resources = append(o.resources, resources)
+	}
+
+	return nil
+}
+
+func (o *generateFromCRDOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) {
+	chain, err := projectPluginChain(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var scaffolders []scaffold.Scaffolder
+	for i, crd := range o.crds {
+		// The types generated from the schema replace the ones of the project plugins
+		plugins := append(append([]scaffold.Plugin{}, chain.APIPlugins()...), crdimport.Plugin{CRD: crd})
+		for _, res := range o.resources[i] {
+			doController := o.doController && (res.StorageVersion == "" || res.Version == res.StorageVersion)
+			scaffolders = append(scaffolders,
+				scaffold.NewAPIScaffolder(c, res, true, doController, false, plugins, "", nil, nil))
+		}
+	}
+
+	return scaffold.NewBatchScaffolder(c, nil, scaffolders...), nil
+}
+
+func (o *generateFromCRDOptions) postScaffold(_ *config.Config) error {
+	if o.runMake {
+		return internal.RunCmd("Running make", "make")
+	}
+
+	return nil
+}
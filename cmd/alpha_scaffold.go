@@ -0,0 +1,344 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/cmd/internal"
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+type projectSpecError struct {
+	err error
+}
+
+func (e projectSpecError) Error() string {
+	return fmt.Sprintf("failed to scaffold project from spec: %v", e.err)
+}
+
+// projectSpec describes the project and the APIs to scaffold
+type projectSpec struct {
+	// Domain is the domain associated with the project and used for API groups
+	Domain string `json:"domain,omitempty"`
+
+	// Repo is the go package name of the project root
+	Repo string `json:"repo,omitempty"`
+
+	// MultiGroup enables the multi-group layout
+	MultiGroup bool `json:"multigroup,omitempty"`
+
+	// License and Owner are used to write the boilerplate of new projects
+	License string `json:"license,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+
+	// Resources is the list of APIs to scaffold
+	Resources []resourceSpec `json:"resources,omitempty"`
+}
+
+// resourceSpec describes an API to scaffold
+type resourceSpec struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+
+	// Plural defaults to the lowercase Kind pluralized
+	Plural string `json:"plural,omitempty"`
+
+	// Namespaced, Resource and Controller default to true
+	Namespaced *bool `json:"namespaced,omitempty"`
+	Resource   *bool `json:"resource,omitempty"`
+	Controller *bool `json:"controller,omitempty"`
+
+	// Webhooks selects the webhooks to scaffold for the API
+	Webhooks struct {
+		Defaulting bool `json:"defaulting,omitempty"`
+		Validation bool `json:"validation,omitempty"`
+		Conversion bool `json:"conversion,omitempty"`
+	} `json:"webhooks,omitempty"`
+}
+
+func boolOrDefault(value *bool, defaultValue bool) bool {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func newProjectSpecCmd() *cobra.Command {
+	options := &projectSpecOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Scaffold a project and its APIs from a YAML spec",
+		Long: `Scaffold a project and its APIs from a YAML spec.
+
+The project is initialized if there is no PROJECT file in the current directory. Then every API in the spec
+is created, together with its webhooks, unless it is already tracked in the PROJECT file, so running the
+command again with new APIs in the spec only scaffolds the new ones.
+`,
+		Example: `	# Scaffold the project described in project.yaml
+	kubebuilder alpha scaffold -f project.yaml
+
+	# project.yaml
+	domain: my.domain
+	repo: github.com/example/operator
+	license: apache2
+	owner: The Example authors
+	resources:
+	- group: ship
+	  version: v1beta1
+	  kind: Frigate
+	  webhooks:
+	    defaulting: true
+	    validation: true
+	- group: ship
+	  version: v1
+	  kind: Destroyer
+	  namespaced: false
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(projectSpecError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &projectSpecOptions{}
+
+type projectSpecOptions struct {
+	// file is the path to the spec
+	file string
+	spec projectSpec
+	// resources are the validated resources of the spec
+	resources []*resource.Resource
+
+	// initialize indicates that the project does not exist yet
+	initialize bool
+
+	// runMake indicates whether to run make or not after scaffolding
+	runMake bool
+
+	// skipGoVersionCheck skips checking the Go version when initializing the project
+	skipGoVersionCheck bool
+}
+
+func (o *projectSpecOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "path to the YAML spec of the project")
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after generating files")
+	cmd.Flags().BoolVar(&o.skipGoVersionCheck, "skip-go-version-check",
+		false, "if specified, skip checking the Go version")
+}
+
+func (o *projectSpecOptions) loadConfig() (*config.Config, error) {
+	if o.file == "" {
+		return nil, errors.New("a spec file must be provided with --file")
+	}
+
+	content, err := ioutil.ReadFile(o.file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read spec: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(content, &o.spec); err != nil {
+		return nil, fmt.Errorf("unable to parse spec %s: %v", o.file, err)
+	}
+
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		o.initialize = true
+		projectConfig = config.New(config.DefaultPath)
+		return projectConfig, nil
+	}
+
+	return projectConfig, err
+}
+
+func (o *projectSpecOptions) validate(c *config.Config) error {
+	if o.initialize {
+		// Requires go1.11+
+		if !o.skipGoVersionCheck {
+			if err := internal.ValidateGoVersion(); err != nil {
+				return err
+			}
+		}
+
+		if err := validateProjectName(); err != nil {
+			return err
+		}
+
+		if o.spec.Domain == "" {
+			return errors.New("domain cannot be empty")
+		}
+		c.Domain = o.spec.Domain
+
+		// Try to guess repository if it is not set
+		c.Repo = o.spec.Repo
+		if c.Repo == "" {
+			repoPath, err := internal.FindCurrentRepo()
+			if err != nil {
+				return fmt.Errorf("error finding current repository: %v", err)
+			}
+			c.Repo = repoPath
+		}
+
+		if o.spec.License == "" {
+			o.spec.License = project.LicenseApache2
+		}
+		if err := project.ValidateLicense(o.spec.License); err != nil {
+			return err
+		}
+	} else {
+		if !c.IsV2() {
+			return fmt.Errorf("scaffolding from a spec is not supported for project version %s", c.Version)
+		}
+
+		// The spec needs to describe the existing project
+		if o.spec.Domain != "" && o.spec.Domain != c.Domain {
+			return fmt.Errorf("spec domain %s does not match the project domain %s", o.spec.Domain, c.Domain)
+		}
+		if o.spec.Repo != "" && o.spec.Repo != c.Repo {
+			return fmt.Errorf("spec repo %s does not match the project repo %s", o.spec.Repo, c.Repo)
+		}
+	}
+
+	multiGroup := c.MultiGroup || o.spec.MultiGroup
+	groups := make(map[string]struct{})
+	for _, group := range c.ResourceGroups() {
+		groups[group] = struct{}{}
+	}
+	gvks := make(map[string]struct{})
+	o.resources = make([]*resource.Resource, 0, len(o.spec.Resources))
+	for _, spec := range o.spec.Resources {
+		res := &resource.Resource{
+			Group:      spec.Group,
+			Version:    spec.Version,
+			Kind:       spec.Kind,
+			Resource:   spec.Plural,
+			Namespaced: boolOrDefault(spec.Namespaced, true),
+			// Same default as the --subresource-status flag of create api
+			StatusSubresource: true,
+		}
+		if err := res.Validate(); err != nil {
+			return fmt.Errorf("invalid resource %s/%s, Kind=%s: %v", res.Group, res.Version, res.Kind, err)
+		}
+
+		gvk := strings.Join([]string{res.Group, res.Version, res.Kind}, "/")
+		if _, duplicated := gvks[gvk]; duplicated {
+			return fmt.Errorf("resource %s/%s, Kind=%s is duplicated", res.Group, res.Version, res.Kind)
+		}
+		gvks[gvk] = struct{}{}
+
+		// Check the group is the same for single-group projects
+		groups[res.Group] = struct{}{}
+		if !multiGroup && len(groups) > 1 {
+			return fmt.Errorf("multiple groups are not allowed by default, set multigroup in the spec or visit %s",
+				"kubebuilder.io/migration/multi-group.html")
+		}
+
+		o.resources = append(o.resources, res)
+	}
+
+	return nil
+}
+
+func (o *projectSpecOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) {
+	if o.initialize {
+		c.Plugins = []string{modelconfig.PluginGoV2}
+	}
+	chain, err := projectPluginChain(c)
+	if err != nil {
+		return nil, err
+	}
+
+	scaffolders := make(sequentialScaffolder, 0, 2*len(o.spec.Resources)+1)
+
+	switch {
+	case o.initialize:
+		c.MultiGroup = o.spec.MultiGroup
+		scaffolders = append(scaffolders, scaffold.NewInitScaffolder(c, o.spec.License, o.spec.Owner,
+			chain.InitPlugins(), ""))
+	case o.spec.MultiGroup && !c.MultiGroup:
+		scaffolders = append(scaffolders, scaffold.NewEditScaffolder(c, true, "", nil, false))
+	}
+
+	for i, spec := range o.spec.Resources {
+		res := o.resources[i]
+
+		// APIs that already exist are skipped so that the spec can be re-applied
+		if c.HasResource(res) {
+			fmt.Printf("Skipping %s/%s, Kind=%s as it already exists\n", res.Group, res.Version, res.Kind)
+			continue
+		}
+
+		scaffolders = append(scaffolders, scaffold.NewAPIScaffolder(c, res,
+			boolOrDefault(spec.Resource, true), boolOrDefault(spec.Controller, true), false, chain.APIPlugins(), "",
+			nil, nil))
+
+		if spec.Webhooks.Defaulting || spec.Webhooks.Validation || spec.Webhooks.Conversion {
+			scaffolders = append(scaffolders, scaffold.NewV2WebhookScaffolder(c, res,
+				spec.Webhooks.Defaulting, spec.Webhooks.Validation, spec.Webhooks.Conversion, "", "",
+				webhookv2.AdmissionOptions{}, chain.WebhookPlugins(), "", nil, nil))
+		}
+	}
+
+	return scaffolders, nil
+}
+
+func (o *projectSpecOptions) postScaffold(c *config.Config) error {
+	if o.initialize {
+		if err := fetchGoDependencies(c); err != nil {
+			return err
+		}
+	}
+
+	if o.runMake {
+		return internal.RunCmd("Running make", "make")
+	}
+
+	return nil
+}
+
+// sequentialScaffolder runs several scaffolders one after the other
+type sequentialScaffolder []scaffold.Scaffolder
+
+func (s sequentialScaffolder) Scaffold() error {
+	for _, scaffolder := range s {
+		if err := scaffolder.Scaffold(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
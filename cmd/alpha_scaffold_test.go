@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestBoolOrDefault(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		value        *bool
+		defaultValue bool
+		expected     bool
+	}{
+		{value: nil, defaultValue: true, expected: true},
+		{value: nil, defaultValue: false, expected: false},
+		{value: &no, defaultValue: true, expected: false},
+		{value: &yes, defaultValue: false, expected: true},
+	}
+
+	for _, test := range tests {
+		if actual := boolOrDefault(test.value, test.defaultValue); actual != test.expected {
+			t.Errorf("boolOrDefault(%v, %v): expected %v, got %v", test.value, test.defaultValue, test.expected, actual)
+		}
+	}
+}
+
+// writeSpec writes the spec to a temporary file and returns its path
+func writeSpec(t *testing.T, spec string) string {
+	dir, err := ioutil.TempDir("", "kubebuilder-spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "project.yaml")
+	if err := ioutil.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProjectSpecOptionsLoadConfig(t *testing.T) {
+	file := writeSpec(t, `domain: example.com
+multigroup: true
+resources:
+- group: ship
+  version: v1
+  kind: Frigate
+  namespaced: false
+  controller: false
+  webhooks:
+    defaulting: true
+`)
+	defer os.RemoveAll(filepath.Dir(file))
+
+	options := &projectSpecOptions{file: file}
+	// There is no PROJECT file in the working directory of the tests
+	if _, err := options.loadConfig(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !options.initialize {
+		t.Errorf("expected the project to be initialized")
+	}
+	if options.spec.Domain != "example.com" || !options.spec.MultiGroup || len(options.spec.Resources) != 1 {
+		t.Fatalf("unexpected spec: %+v", options.spec)
+	}
+	res := options.spec.Resources[0]
+	if res.Kind != "Frigate" || boolOrDefault(res.Namespaced, true) || boolOrDefault(res.Controller, true) ||
+		!boolOrDefault(res.Resource, true) || !res.Webhooks.Defaulting || res.Webhooks.Validation {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+}
+
+func TestProjectSpecOptionsLoadConfigErrors(t *testing.T) {
+	file := writeSpec(t, "domain: example.com\nresources:\n- group: ship\n  version: v1\n  kind: Frigate\n  scope: Cluster\n")
+	defer os.RemoveAll(filepath.Dir(file))
+
+	tests := []struct {
+		file string
+		err  string
+	}{
+		{err: "a spec file must be provided"},
+		{file: filepath.Join(filepath.Dir(file), "missing.yaml"), err: "unable to read spec"},
+		{file: file, err: "unknown field \"scope\""},
+	}
+
+	for _, test := range tests {
+		options := &projectSpecOptions{file: test.file}
+		if _, err := options.loadConfig(); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected an error containing %q, got %v", test.file, test.err, err)
+		}
+	}
+}
+
+func TestProjectSpecOptionsValidate(t *testing.T) {
+	frigate := resourceSpec{Group: "ship", Version: "v1", Kind: "Frigate"}
+	captain := resourceSpec{Group: "crew", Version: "v1", Kind: "Captain"}
+
+	tests := []struct {
+		spec projectSpec
+		err  string
+	}{
+		{spec: projectSpec{Domain: "example.com", Resources: []resourceSpec{frigate}}},
+		{spec: projectSpec{MultiGroup: true, Resources: []resourceSpec{frigate, captain}}},
+		{spec: projectSpec{Resources: []resourceSpec{frigate, captain}}, err: "multiple groups are not allowed"},
+		{spec: projectSpec{Resources: []resourceSpec{frigate, frigate}}, err: "is duplicated"},
+		{spec: projectSpec{Resources: []resourceSpec{{Group: "ship", Version: "1", Kind: "Frigate"}}},
+			err: "invalid resource"},
+		{spec: projectSpec{Domain: "example.org"}, err: "does not match the project domain"},
+		{spec: projectSpec{Repo: "example.com/other"}, err: "does not match the project repo"},
+	}
+
+	for _, test := range tests {
+		options := &projectSpecOptions{spec: test.spec}
+		err := options.validate(newTestConfig())
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%+v: unexpected error: %v", test.spec, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%+v: expected an error containing %q, got %v", test.spec, test.err, err)
+		}
+	}
+}
+
+func TestProjectSpecOptionsValidateDefaults(t *testing.T) {
+	no := false
+	options := &projectSpecOptions{spec: projectSpec{Resources: []resourceSpec{
+		{Group: "ship", Version: "v1", Kind: "Frigate"},
+		{Group: "ship", Version: "v1", Kind: "Destroyer", Plural: "destroyeres", Namespaced: &no},
+	}}}
+	if err := options.validate(newTestConfig()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	frigate, destroyer := options.resources[0], options.resources[1]
+	if !frigate.Namespaced || !frigate.StatusSubresource || frigate.Resource != "frigates" {
+		t.Errorf("unexpected defaults of Frigate: %+v", frigate)
+	}
+	if destroyer.Namespaced || destroyer.Resource != "destroyeres" {
+		t.Errorf("unexpected Destroyer: %+v", destroyer)
+	}
+}
+
+func TestProjectSpecOptionsScaffolder(t *testing.T) {
+	c := newTestConfig()
+	if err := scaffold.NewInitScaffolder(c, "none", "", nil, "").Scaffold(); err != nil {
+		t.Fatal(err)
+	}
+	frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+	if err := scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold(); err != nil {
+		t.Fatal(err)
+	}
+
+	no := false
+	destroyer := resourceSpec{Group: "ship", Version: "v1", Kind: "Destroyer"}
+	destroyer.Webhooks.Defaulting = true
+	destroyer.Webhooks.Validation = true
+	cruiser := resourceSpec{Group: "ship", Version: "v1", Kind: "Cruiser", Controller: &no}
+	// Frigate already exists, so its webhooks are not scaffolded with the spec
+	existing := resourceSpec{Group: "ship", Version: "v1", Kind: "Frigate"}
+	existing.Webhooks.Defaulting = true
+
+	options := &projectSpecOptions{spec: projectSpec{Resources: []resourceSpec{existing, destroyer, cruiser}}}
+	if err := options.validate(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scaffolder, err := options.scaffolder(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The API of Destroyer and Cruiser and the webhooks of Destroyer
+	if scaffolders := scaffolder.(sequentialScaffolder); len(scaffolders) != 3 {
+		t.Fatalf("expected 3 scaffolders, got %d", len(scaffolders))
+	}
+	if err := scaffolder.Scaffold(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for path, expected := range map[string]bool{
+		filepath.Join("api", "v1", "destroyer_types.go"):        true,
+		filepath.Join("api", "v1", "destroyer_webhook.go"):      true,
+		filepath.Join("controllers", "destroyer_controller.go"): true,
+		filepath.Join("api", "v1", "cruiser_types.go"):          true,
+		filepath.Join("api", "v1", "cruiser_webhook.go"):        false,
+		filepath.Join("controllers", "cruiser_controller.go"):   false,
+		filepath.Join("api", "v1", "frigate_webhook.go"):        false,
+	} {
+		if exists, err := afero.Exists(c.Fs(), path); err != nil {
+			t.Fatal(err)
+		} else if exists != expected {
+			t.Errorf("%s: expected it to exist: %v", path, expected)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type universeError struct {
+	err error
+}
+
+func (e universeError) Error() string {
+	return fmt.Sprintf("failed to export project model: %v", e.err)
+}
+
+func newUniverseCmd() *cobra.Command {
+	options := &universeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "universe",
+		Short: "Print the scaffolding model of the project",
+		Long: `Print the scaffolding model of the project, the same one that the exec plugins receive, for editors
+and code generators that need to introspect the project without parsing the PROJECT file and the
+filesystem themselves.
+
+The model is made of:
+- config: the project configuration of the PROJECT file.
+- boilerplate: the license header of the Go files.
+- resources: the resources tracked in the PROJECT file, with their Go package and API group.
+- files: the files scaffolded by init with the templates of this version of kubebuilder, with their
+  path and what is done when they already exist. Their rendered contents are only printed with --contents.
+
+Nothing is written to the project.
+`,
+		Example: `	# Print the model of the project as JSON
+	kubebuilder alpha universe --output json
+
+	# List the Go packages of the resources of the project
+	kubebuilder alpha universe | jq -r '.resources[].goPackage'
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(universeError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &universeOptions{}
+
+type universeOptions struct {
+	output string
+	// contents indicates that the rendered contents of the files should be printed
+	contents bool
+}
+
+func (o *universeOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.output, "output", outputJSON,
+		fmt.Sprintf("format of the model, only %s is supported", outputJSON))
+	cmd.Flags().BoolVar(&o.contents, "contents", false, "if set, print the rendered contents of the files")
+}
+
+func (o *universeOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *universeOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("exporting the project model is not supported for version %s", c.Version)
+	}
+
+	if o.output != outputJSON {
+		return fmt.Errorf("--output must be %s (was %s)", outputJSON, o.output)
+	}
+
+	return nil
+}
+
+func (o *universeOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewUniverseScaffolder(c, o.contents, nil), nil
+}
+
+func (o *universeOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type templateUpdateError struct {
+	err error
+}
+
+func (e templateUpdateError) Error() string {
+	return fmt.Sprintf("failed to update the project files: %v", e.err)
+}
+
+func newTemplateUpdateCmd() *cobra.Command {
+	options := &templateUpdateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the project files to the templates of this version of kubebuilder",
+		Long: `Update the project files scaffolded by init to the templates of this version of kubebuilder.
+
+Every scaffolded file is recorded in .kubebuilder/manifest.yaml with the hashes of the template and of
+the contents it was scaffolded with. The files are scaffolded again, and:
+- the files that were not modified since they were scaffolded are updated to their new scaffold.
+- the files that were added to the templates since the project was initialized are created.
+- the files that were removed from the project are not restored.
+- the files that were modified are listed for review, with the changes of their new scaffold if --diff is
+  set. With --merge, the ones scaffolded for you to edit, like the Makefile, are three-way merged
+  with their new scaffold instead, leaving the changes that can't be merged as conflicts.
+
+The files of the APIs and webhooks are not updated, as they depend on the flags they were created with.
+`,
+		Example: `	# Update the files that were not modified and list the others
+	kubebuilder alpha update
+
+	# Also show the changes of the new scaffold of the modified files
+	kubebuilder alpha update --diff
+
+	# Also three-way merge the modified files with their new scaffold
+	kubebuilder alpha update --merge
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(templateUpdateError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &templateUpdateOptions{}
+
+type templateUpdateOptions struct {
+	// merge indicates that the modified files should be three-way merged with their new scaffold
+	merge bool
+	// diff indicates that the changes of the new scaffold of the modified files should be shown
+	diff bool
+}
+
+func (o *templateUpdateOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.merge, "merge", false,
+		"if set, three-way merge the modified files with their new scaffold, leaving conflicts between markers")
+	cmd.Flags().BoolVar(&o.diff, "diff", false,
+		"if set, show the changes of the new scaffold of the modified files")
+}
+
+func (o *templateUpdateOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *templateUpdateOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("updating the project files is not supported for project version %s", c.Version)
+	}
+
+	return nil
+}
+
+func (o *templateUpdateOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	var diff io.Writer
+	if o.diff {
+		diff = os.Stdout
+	}
+	return scaffold.NewTemplateUpdateScaffolder(c, o.merge, diff), nil
+}
+
+func (o *templateUpdateOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
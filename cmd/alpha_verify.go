@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type verifyError struct {
+	err error
+}
+
+func (e verifyError) Error() string {
+	return fmt.Sprintf("failed to verify project: %v", e.err)
+}
+
+func newVerifyCmd() *cobra.Command {
+	options := &verifyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that the project files are consistent with the PROJECT file",
+		Long: `Check that the project files are consistent with the resources tracked in the PROJECT file.
+
+For every resource, the command checks that:
+- its types exist under api/<version> (or apis/<group>/<version> for multi-group projects).
+- its API version is registered in the scheme of the manager in main.go.
+- its controller, if it has one, is set up with the manager in main.go.
+- its CRD is listed in config/crd/kustomization.yaml.
+
+The inconsistencies are reported, and with --fix the wiring in main.go and config/crd/kustomization.yaml
+is restored. Missing types have to be fixed by hand, by creating the API again or by removing the
+resource from the PROJECT file. The command fails if any inconsistency is left.
+`,
+		Example: `	# Report the inconsistencies between the project files and the PROJECT file
+	kubebuilder alpha verify
+
+	# Restore the missing wiring of the resources in main.go and config/crd/kustomization.yaml
+	kubebuilder alpha verify --fix
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(verifyError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &verifyOptions{}
+
+type verifyOptions struct {
+	// fix indicates that the inconsistencies should be fixed instead of only reported
+	fix bool
+}
+
+func (o *verifyOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.fix, "fix", false,
+		"if set, fix the inconsistencies in main.go and config/crd/kustomization.yaml")
+}
+
+func (o *verifyOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *verifyOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("verifying the project is not supported for project version %s", c.Version)
+	}
+
+	return nil
+}
+
+func (o *verifyOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewVerifyScaffolder(c, o.fix), nil
+}
+
+func (o *verifyOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -30,7 +31,10 @@ import (
 	"sigs.k8s.io/kubebuilder/cmd/internal"
 	"sigs.k8s.io/kubebuilder/internal/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	scaffolderrors "sigs.k8s.io/kubebuilder/pkg/scaffold/errors"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 	"sigs.k8s.io/kubebuilder/plugins/addon"
 )
 
@@ -52,12 +56,130 @@ func newAPICmd() *cobra.Command {
 
 kubebuilder create api will prompt the user asking if it should scaffold the Resource and / or Controller. To only
 scaffold a Controller for an existing Resource, select "n" for Resource.  To only define
-the schema for a Resource without writing a Controller, select "n" for Controller.
+the schema for a Resource without writing a Controller, select "n" for Controller. The kubebuilder create resource
+and kubebuilder create controller commands scaffold each of them on its own without prompting.
 
 After the scaffold is written, api will run make on the project.
 `,
 		Example: `	# Create a frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
 	kubebuilder create api --group ship --version v1beta1 --kind Frigate
+
+	# Create an API being prompted for the Group, Version, Kind and scope
+	kubebuilder create api --interactive
+
+	# Create the APIs listed in gvks.yaml, with conditions unless an entry sets conditions: false
+	kubebuilder create api --from-file gvks.yaml --conditions
+
+	# gvks.yaml
+	- group: ship
+	  version: v1beta1
+	  kind: Frigate
+	  owns: [apps/v1/Deployment]
+	- group: ship
+	  version: v1beta1
+	  kind: Destroyer
+	  namespaced: false
+	  controller: false
+	  conditions: false
+
+	# Create an API whose controller reports its state through status conditions
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --conditions
+
+	# Create an API whose conditions are set with the helpers of the pkg/conditions package of the project and whose
+	# controller patches the status instead of updating it
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --conditions-package
+
+	# Create an API whose reconciliation is paused while the spec.suspend field of a Frigate is true
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --suspend --conditions
+
+	# Create an API with an apply configuration to patch Frigates with server-side apply from the controller
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --apply-configuration
+
+	# Create an API whose controller cleans up external resources before the object is deleted
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --with-finalizer
+
+	# Create an API whose controller exposes Prometheus metrics about its reconciliations
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --metrics
+
+	# Create an API whose controller records events, shown by kubectl describe, about the Frigates
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --events
+
+	# Create an API whose concurrent reconciles and backoff are set with the flags of the manager,
+	# e.g. --max-concurrent-reconciles=4 --reconcile-max-delay=5m
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --controller-options
+
+	# Create an API whose controller is unit tested against a fake client instead of envtest
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --test-style fake
+
+	# Create an API whose controller only reconciles the Frigates labeled fleet=north
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --watch-label-selector fleet=north
+
+	# Create an API whose controller polls the Frigates every 5 minutes, set with --frigate-reconcile-period
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --reconcile-period 5m
+
+	# Create an API whose controller manages a Deployment and a Service for each Frigate
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --owns apps/v1/Deployment,core/v1/Service
+
+	# Create an API whose controller deploys nginx for each Frigate with a Deployment and a Service, reporting
+	# whether its replicas are available with the Ready condition
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --image nginx:1.19 --image-container-port 80
+
+	# Create an API whose controller reconciles the Frigates referencing a Secret when it changes
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --watches-external core/v1/Secret
+
+	# Create a controller for Deployments, whose types are defined in an external package
+	kubebuilder create api --group apps --version v1 --kind Deployment --external-api-path k8s.io/api/apps/v1
+
+	# Create an API served as octopuses instead of the default octopi plural
+	kubebuilder create api --group ship --version v1 --kind Octopus --plural octopuses
+
+	# Create an API in the my-app.my.domain group, whose packages are imported as app instead of myapp
+	kubebuilder create api --group My-App.my.domain --version v1 --kind Foo --group-package app
+
+	# Create an API without a group, served in the API group named after the domain, e.g. foos.my.domain
+	kubebuilder create api --group "" --version v1 --kind Foo
+
+	# Create an API that can be listed with kubectl get fr and, along with the rest of the fleet, kubectl get fleet
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --short-name fr --categories fleet
+
+	# Create an API whose objects are listed by kubectl get with their Ready condition, phase, replicas and age
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --conditions \
+		--print-columns ready,phase,Replicas:integer=.spec.replicas,age
+
+	# Create an API that can be scaled by kubectl scale and the HorizontalPodAutoscaler, through its spec.replicas,
+	# status.replicas and status.selector fields
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --subresource-scale
+
+	# Create an API whose status is updated along with the rest of the object, without the status subresource
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --subresource-status=false
+
+	# Create a new version of an existing API and persist the Frigates in it
+	kubebuilder create api --group ship --version v1 --kind Frigate --storage-version v1
+
+	# Create an API whose editor and viewer roles are aggregated to the default admin, edit and view roles
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --rbac-mode aggregate
+
+	# Create an API whose default values are set by +kubebuilder:default markers in the CRD schema
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --defaults markers
+
+	# Create an API whose types show enums, patterns, ranges, required fields and CEL validation rules
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --example-fields rich
+
+	# Create an API together with its defaulting and validating webhooks
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --defaulting --validation
+
+	# Regenerate an existing API with the current templates, merging the changes made to its files since they were
+	# recorded by kubebuilder edit --merge-bases
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --force
+
+	# Create an API without the controller tests, samples and editor and viewer roles, which the next APIs skip too
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --skip-tests --skip-samples --skip-rbac
+
+	# Show the changes that creating an API would make without writing them
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --dry-run
+
+	# Create an API and print the files that were created, updated or skipped as JSON
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --make=false --output json
 	
 	# Edit the API Scheme
 	nano api/v1beta1/frigate_types.go
@@ -75,8 +197,8 @@ After the scaffold is written, api will run make on the project.
 	make run
 `,
 		Run: func(_ *cobra.Command, _ []string) {
-			if err := run(options); err != nil {
-				log.Fatal(apiError{err})
+			if err := runAPI(options, os.Stdout); err != nil {
+				log.Fatal(err)
 			}
 		},
 	}
@@ -86,13 +208,56 @@ After the scaffold is written, api will run make on the project.
 	return cmd
 }
 
+// runAPI creates the API and, with --output=json, writes the report of the scaffolded files to stdout whether it
+// succeeded or not, with the error that stopped it if any
+func runAPI(o *apiOptions, stdout io.Writer) error {
+	if o.output == outputJSON {
+		o.reporter = &scaffold.JSONReporter{}
+	}
+
+	err := run(o)
+	if err != nil {
+		if errors.Is(err, scaffolderrors.ErrFileExists) && !o.force {
+			err = fmt.Errorf("%v, rerun with --force to merge the existing files with the new scaffold", err)
+		}
+		err = apiError{err}
+	}
+
+	if o.reporter != nil {
+		if err != nil {
+			o.reporter.Error = err.Error()
+		}
+		if writeErr := o.reporter.Write(stdout); err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+// out returns where the messages for the user are printed, the standard output is reserved to the JSON report
+func (o *apiOptions) out() io.Writer {
+	if o.output == outputJSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// patterns are the scaffolding patterns that an API can follow instead of the default one
+var patterns = scaffold.Patterns{addon.Pattern{}}
+
 var _ commandOptions = &apiOptions{}
 
 type apiOptions struct {
-	// pattern indicates that we should use a plugin to build according to a pattern
+	// pattern is the name of the scaffolding pattern that the API follows, the default one if empty
 	pattern string
+	// webhookPlugins are the plugins of the project that transform the scaffold of the webhooks
+	webhookPlugins []scaffold.Plugin
 
 	resource *resource.Resource
+	// groupFlag is used to allow an empty group only if it was set explicitly
+	groupFlag *flag.Flag
+	// namespacedFlag is used to default the scope to the one of the other versions of the Kind
+	namespacedFlag *flag.Flag
 
 	// Check if we have to scaffold resource and/or controller
 	resourceFlag   *flag.Flag
@@ -105,10 +270,65 @@ type apiOptions struct {
 
 	// runMake indicates whether to run make or not after scaffolding APIs
 	runMake bool
+	// generate indicates whether to generate the deep-copy methods of the new types when make isn't run
+	generate bool
+
+	// interactive indicates that the values should be prompted using the flags as defaults
+	interactive bool
+
+	// fromFile is the path to a YAML file listing the APIs to create, whose options default to the flags
+	fromFile string
+	// batch are the options of each API listed in fromFile
+	batch []*apiOptions
+
+	// dryRun indicates that the changes should be printed as a diff instead of written
+	dryRun bool
+	// keepPartial indicates that the changes should be written as they are made instead of once scaffolding succeeded
+	keepPartial bool
+
+	// templatesDir is a directory with templates that replace the built-in ones
+	templatesDir string
+
+	// skipTests, skipSamples and skipRBAC indicate that the tests, samples and editor and viewer roles shouldn't be
+	// scaffolded, the choices are remembered in the project configuration when the flags are set
+	skipTests       bool
+	skipTestsFlag   *flag.Flag
+	skipSamples     bool
+	skipSamplesFlag *flag.Flag
+	skipRBAC        bool
+	skipRBACFlag    *flag.Flag
+
+	// defaulting and validation indicate that the admission webhooks should be scaffolded too
+	defaulting bool
+	validation bool
+
+	// owns are the resources owned by the controller in the group/version/kind format
+	owns []string
+	// watchesExternal are the resources watched by the controller in the group/version/kind format
+	watchesExternal []string
+	// printColumns are the printer columns of the resource, common ones or in the name[:type]=JSONPath format
+	printColumns []string
+
+	// subresourceScale are the paths of the scale subresource in the specpath,statuspath[,selectorpath] format
+	subresourceScale string
+
+	// output is the format used to report the scaffolded files
+	output string
+	// reporter collects the scaffolded files when they are reported as JSON
+	reporter *scaffold.JSONReporter
 }
 
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
 func (o *apiOptions) bindFlags(cmd *cobra.Command) {
-	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after generating files")
+	o.bindCommonFlags(cmd)
+	cmd.Flags().BoolVar(&o.interactive, "interactive", false,
+		"if specified, prompt for the API values using the flags as defaults")
+	cmd.Flags().StringVar(&o.fromFile, "from-file", "",
+		"path to a YAML file listing the APIs to create in a batch, whose options default to the flags")
 
 	cmd.Flags().BoolVar(&o.doResource, "resource", true,
 		"if set, generate the resource without prompting the user")
@@ -117,21 +337,147 @@ func (o *apiOptions) bindFlags(cmd *cobra.Command) {
 		"if set, generate the controller without prompting the user")
 	o.controllerFlag = cmd.Flag("controller")
 
-	if os.Getenv("KUBEBUILDER_ENABLE_PLUGINS") != "" {
-		cmd.Flags().StringVar(&o.pattern, "pattern", "",
-			"generates an API following an extension pattern (addon)")
+	patternUsage := "generates an API following an alternative scaffolding pattern, one of:"
+	for _, pattern := range patterns {
+		patternUsage += fmt.Sprintf("\n  %s: %s", pattern.Name(), pattern.Description())
 	}
+	cmd.Flags().StringVar(&o.pattern, "pattern", "", patternUsage)
+	cmd.Flags().StringVar(&o.resource.Image, "image", "",
+		"if set, generate an operator deploying this container image, e.g. nginx:1.19, whose controller manages "+
+			"a Deployment and a Service for each object and reports their availability with the Ready condition")
+	cmd.Flags().Int32Var(&o.resource.ImageContainerPort, "image-container-port", 8080,
+		"port the container image of --image listens on, exposed by the Service")
+
+	o.bindTypeFlags(cmd)
+	o.bindControllerFlags(cmd)
+}
 
+// bindCommonFlags binds the flags shared by the commands that create the resource and (or) the controller of an API
+func (o *apiOptions) bindCommonFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after generating files")
+	cmd.Flags().BoolVar(&o.generate, "generate", true,
+		"if true and make isn't run, generate the deep-copy methods of the types after creating a resource so that "+
+			"the project compiles, without running controller-gen")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false,
+		"if specified, print the changes as a diff without writing any file")
+	cmd.Flags().BoolVar(&o.keepPartial, "keep-partial", false,
+		"if specified, write the files as they are scaffolded, keeping them if scaffolding fails halfway")
+	cmd.Flags().StringVar(&o.output, "output", outputText,
+		fmt.Sprintf("format used to report the scaffolded files (%s or %s, which prints any other message to stderr)",
+			outputText, outputJSON))
+	cmd.Flags().StringVar(&o.templatesDir, "templates-dir", "",
+		"directory with templates that replace the built-in ones, "+
+			"looked up as <dir>/<package>/<type>.tmpl (e.g. v2/controller/Controller.tmpl)")
 	cmd.Flags().BoolVar(&o.force, "force", false,
-		"attempt to create resource even if it already exists")
+		"attempt to create resource even if it already exists, three-way merging the existing files with the new "+
+			"scaffold (conflicts are left between markers), which requires kubebuilder edit --merge-bases")
+	cmd.Flags().BoolVar(&o.skipTests, "skip-tests", false,
+		"if set, don't scaffold the tests of the controllers and webhooks, remembered for the next APIs")
+	o.skipTestsFlag = cmd.Flag("skip-tests")
+	cmd.Flags().BoolVar(&o.skipSamples, "skip-samples", false,
+		"if set, don't scaffold the samples of the resources, remembered for the next APIs")
+	o.skipSamplesFlag = cmd.Flag("skip-samples")
+	cmd.Flags().BoolVar(&o.skipRBAC, "skip-rbac", false,
+		"if set, don't scaffold the editor and viewer roles of the resources, remembered for the next APIs")
+	o.skipRBACFlag = cmd.Flag("skip-rbac")
 
 	o.resource = &resource.Resource{}
-	cmd.Flags().StringVar(&o.resource.Kind, "kind", "", "resource Kind")
-	cmd.Flags().StringVar(&o.resource.Group, "group", "", "resource Group")
-	cmd.Flags().StringVar(&o.resource.Version, "version", "", "resource Version")
+	bindResourceFlags(cmd, o.resource)
+	o.groupFlag = cmd.Flag("group")
 	cmd.Flags().BoolVar(&o.resource.Namespaced, "namespaced", true, "resource is namespaced")
+	o.namespacedFlag = cmd.Flag("namespaced")
+	cmd.Flags().StringVar(&o.resource.Resource, "plural", "",
+		"resource plural, e.g. for Kinds with irregular plurals, defaults to the lowercase Kind pluralized")
+	cmd.Flags().StringVar(&o.resource.GroupPackage, "group-package", "",
+		"name the packages of the API group are imported as, defaults to the group without its dashes and dots, "+
+			"e.g. myapp for my-app")
+}
+
+// bindTypeFlags binds the flags of the resource of an API, which define its types, CRD and webhooks
+func (o *apiOptions) bindTypeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.resource.ShortNames, "short-name", nil,
+		"short names of the resource, e.g. fr for kubectl get fr")
+	cmd.Flags().StringSliceVar(&o.resource.Categories, "categories", nil,
+		"categories the resource belongs to, e.g. all or the name of the project, for kubectl get <category>")
+	cmd.Flags().BoolVar(&o.resource.Conditions, "conditions", false,
+		"if set, add conditions to the resource status and update them from the controller")
+	cmd.Flags().BoolVar(&o.resource.ConditionsPackage, "conditions-package", false,
+		"if set, implies --conditions with the Condition type of a pkg/conditions package of the project, whose "+
+			"helpers mark and summarize the conditions, and patch the status from the controller")
+	cmd.Flags().BoolVar(&o.resource.Suspend, "suspend", false,
+		"if set, add a spec.suspend field to the resource that pauses its reconciliation by the controller")
+	cmd.Flags().BoolVar(&o.resource.StatusSubresource, "subresource-status", true,
+		"if true, serve the status of the resource through the status subresource, required by --conditions")
+	cmd.Flags().StringVar(&o.subresourceScale, "subresource-scale", "",
+		"if set, add the scale subresource to the resource, for kubectl scale and the HorizontalPodAutoscaler, "+
+			"in the specpath,statuspath[,selectorpath] format, the replicas and selector fields are added to the "+
+			"types. Defaults to the paths of a Deployment if set without a value")
+	cmd.Flags().Lookup("subresource-scale").NoOptDefVal = strings.Join([]string{
+		resource.DefaultScaleSubresource.SpecReplicasPath,
+		resource.DefaultScaleSubresource.StatusReplicasPath,
+		resource.DefaultScaleSubresource.LabelSelectorPath,
+	}, ",")
+	cmd.Flags().BoolVar(&o.resource.ApplyConfiguration, "apply-configuration", false,
+		"if set, scaffold the apply configuration of the resource to patch its objects with server-side apply, "+
+			"and an example of its use in the controller")
+	cmd.Flags().StringSliceVar(&o.printColumns, "print-columns", nil,
+		"additional columns shown by kubectl get, ready (requires --conditions), phase, age or custom columns in "+
+			"the name[:type]=JSONPath format, e.g. Replicas:integer=.spec.replicas. "+
+			"kubectl only shows the default Age column if there are no additional columns, add age to keep it")
+	cmd.Flags().StringVar(&o.resource.RBACMode, "rbac-mode", resource.RBACModeCluster,
+		fmt.Sprintf("how the editor and viewer roles of the resource are scaffolded (%s, %s or %s)",
+			resource.RBACModeCluster, resource.RBACModeAggregate, resource.RBACModeNamespaced))
+	cmd.Flags().StringVar(&o.resource.StorageVersion, "storage-version", "",
+		"version of the Kind that is persisted when it is served in multiple versions, "+
+			"defaults to the current storage version")
+	cmd.Flags().StringVar(&o.resource.DefaultsMode, "defaults", "",
+		fmt.Sprintf("how the default values of the resource are set, %s (+kubebuilder:default markers in the "+
+			"CRD schema) or %s (same as --defaulting)", resource.DefaultsModeMarkers, resource.DefaultsModeWebhook))
+	cmd.Flags().StringVar(&o.resource.ExampleFields, "example-fields", resource.ExampleFieldsBasic,
+		fmt.Sprintf("example fields scaffolded in the spec of the resource, %s (a single field) or %s (fields "+
+			"showing the OpenAPI validation markers)", resource.ExampleFieldsBasic, resource.ExampleFieldsRich))
+	cmd.Flags().BoolVar(&o.defaulting, "defaulting", false,
+		"if set, scaffold the defaulting webhook for the resource")
+	cmd.Flags().BoolVar(&o.validation, "validation", false,
+		"if set, scaffold the validating webhook for the resource")
+}
+
+// bindControllerFlags binds the flags of the controller of an API
+func (o *apiOptions) bindControllerFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.resource.CreateExampleReconcileBody, "example", true,
 		"if true an example reconcile body should be written while scaffolding a resource.")
+	cmd.Flags().BoolVar(&o.resource.Finalizer, "with-finalizer", false,
+		"if set, manage a finalizer from the controller to clean up external resources on deletion")
+	cmd.Flags().BoolVar(&o.resource.Metrics, "metrics", false,
+		"if set, instrument the controller with Prometheus metrics and deploy a ServiceMonitor to scrape them")
+	cmd.Flags().BoolVar(&o.resource.Events, "events", false,
+		"if set, record Kubernetes events about the reconciled objects from the controller")
+	cmd.Flags().BoolVar(&o.resource.ControllerOptions, "controller-options", false,
+		"if set, configure the concurrent reconciles and the exponential backoff of the controller "+
+			"from flags of the manager")
+	cmd.Flags().BoolVar(&o.resource.FeatureGate, "feature-gate", false,
+		"if set, scaffold an experimental reconcile path in the controller, behind a feature gate enabled with "+
+			"the --feature-gates flag of the manager")
+	cmd.Flags().StringVar(&o.resource.ExternalAPIPath, "external-api-path", "",
+		"Go package of the types of a resource that is not defined in the project (e.g. k8s.io/api/apps/v1), "+
+			"only the controller is scaffolded")
+	cmd.Flags().StringVar(&o.resource.ExternalAPIDomain, "external-api-domain", "",
+		"domain of the API group of the external resource, defaults to the one of the Kubernetes group if any")
+	cmd.Flags().StringVar(&o.resource.TestStyle, "test-style", resource.TestStyleEnvtest,
+		fmt.Sprintf("how the controller tests are scaffolded (%s or %s, table-driven tests against a fake client)",
+			resource.TestStyleEnvtest, resource.TestStyleFake))
+	cmd.Flags().DurationVar(&o.resource.ReconcilePeriod, "reconcile-period", 0,
+		"if set, reconcile the objects again after this period, e.g. 5m, to poll a state that doesn't trigger "+
+			"watch events, the period is set with a flag of the manager")
+	cmd.Flags().StringVar(&o.resource.WatchLabelSelector, "watch-label-selector", "",
+		"label selector, e.g. foo=bar, filtering the objects reconciled by the controller, which can't own or "+
+			"watch other resources")
+	cmd.Flags().StringSliceVar(&o.owns, "owns", nil,
+		"resources whose objects are created and owned by the controller in the group/version/kind format, "+
+			"e.g. apps/v1/Deployment,core/v1/Service")
+	cmd.Flags().StringSliceVar(&o.watchesExternal, "watches-external", nil,
+		"resources, neither owned nor defined by the controller, whose objects are watched to reconcile "+
+			"the objects referencing them, in the group/version/kind format, e.g. core/v1/Secret")
 }
 
 func (o *apiOptions) loadConfig() (*config.Config, error) {
@@ -144,20 +490,441 @@ func (o *apiOptions) loadConfig() (*config.Config, error) {
 }
 
 func (o *apiOptions) validate(c *config.Config) error {
-	if err := o.resource.Validate(); err != nil {
+	if o.dryRun && c.IsV1() {
+		return fmt.Errorf("--dry-run is not supported for project version %s", c.Version)
+	}
+
+	switch o.output {
+	case outputText:
+	case outputJSON:
+		if c.IsV1() {
+			return fmt.Errorf("--output=%s is not supported for project version %s", outputJSON, c.Version)
+		}
+		if o.dryRun {
+			return fmt.Errorf("--output=%s can't be used with --dry-run", outputJSON)
+		}
+	default:
+		return fmt.Errorf("unknown output format %q, supported formats are %s and %s", o.output, outputText, outputJSON)
+	}
+
+	if o.keepPartial && o.dryRun {
+		return errors.New("--keep-partial can't be used with --dry-run")
+	}
+
+	if o.templatesDir != "" {
+		if err := internal.ValidateTemplatesDir(o.templatesDir); err != nil {
+			return err
+		}
+	}
+
+	if o.pattern != "" {
+		if c.IsV1() {
+			return fmt.Errorf("--pattern is not supported for project version %s", c.Version)
+		}
+		if _, err := patterns.Find(o.pattern); err != nil {
+			return err
+		}
+	}
+
+	if err := o.validateSkippedArtifacts(c); err != nil {
 		return err
 	}
 
 	reader := bufio.NewReader(os.Stdin)
-	if !o.resourceFlag.Changed {
-		fmt.Println("Create Resource [y/n]")
+	if o.fromFile != "" {
+		return o.validateBatch(c, reader)
+	}
+
+	return o.validateResource(c, reader)
+}
+
+// validateSkippedArtifacts records the artifacts skipped with the set flags in the project configuration, so that
+// they are skipped for the next APIs too
+func (o *apiOptions) validateSkippedArtifacts(c *config.Config) error {
+	skips := []struct {
+		flag  *flag.Flag
+		value bool
+		field *bool
+	}{
+		{o.skipTestsFlag, o.skipTests, &c.SkipTests},
+		{o.skipSamplesFlag, o.skipSamples, &c.SkipSamples},
+		{o.skipRBACFlag, o.skipRBAC, &c.SkipRBAC},
+	}
+	for _, skip := range skips {
+		if skip.flag == nil || !skip.flag.Changed {
+			continue
+		}
+		if c.IsV1() {
+			return fmt.Errorf("--%s is not supported for project version %s", skip.flag.Name, c.Version)
+		}
+		*skip.field = skip.value
+	}
+	return nil
+}
+
+// validateResource checks the options of the API, prompting for the missing ones
+func (o *apiOptions) validateResource(c *config.Config, reader *bufio.Reader) error {
+	if o.interactive {
+		o.prompt(reader)
+	}
+
+	if o.resource.GroupPackage != "" && c.IsV1() {
+		return fmt.Errorf("--group-package is not supported for project version %s", c.Version)
+	}
+	if err := internal.ValidateResourceGroup(o.groupFlag, c, o.resource); err != nil {
+		return err
+	}
+
+	if err := o.validatePlural(c); err != nil {
+		return err
+	}
+
+	if err := o.validateScope(c); err != nil {
+		return err
+	}
+
+	for _, owned := range o.owns {
+		ownedResource, err := resource.ParseResourceRef(owned)
+		if err != nil {
+			return err
+		}
+		o.resource.Owns = append(o.resource.Owns, ownedResource)
+	}
+	if o.resource.ConditionsPackage {
+		if c.IsV1() {
+			return fmt.Errorf("--conditions-package is not supported for project version %s", c.Version)
+		}
+		o.resource.Conditions = true
+	}
+	if o.resource.Image != "" {
+		// The controller of the image manages its Deployment and Service and reports whether it is ready
+		o.resource.Conditions = true
+		for _, imageResource := range resource.ImageResources {
+			owned := false
+			for _, ownedResource := range o.resource.Owns {
+				owned = owned || ownedResource == imageResource
+			}
+			if !owned {
+				o.resource.Owns = append(o.resource.Owns, imageResource)
+			}
+		}
+	}
+	for _, watched := range o.watchesExternal {
+		watchedResource, err := resource.ParseResourceRef(watched)
+		if err != nil {
+			return err
+		}
+		o.resource.WatchesExternal = append(o.resource.WatchesExternal, watchedResource)
+	}
+	if o.subresourceScale != "" {
+		scale, err := resource.ParseScaleSubresource(o.subresourceScale)
+		if err != nil {
+			return err
+		}
+		o.resource.Scale = &scale
+	}
+	for _, value := range o.printColumns {
+		column, err := resource.ParsePrintColumn(value)
+		if err != nil {
+			return err
+		}
+		o.resource.PrintColumns = append(o.resource.PrintColumns, column)
+	}
+
+	if err := o.resource.Validate(); err != nil {
+		return err
+	}
+
+	if o.resource.ExternalAPIPath != "" {
+		if c.IsV1() {
+			return fmt.Errorf("--external-api-path is not supported for project version %s", c.Version)
+		}
+		// The types of external resources are defined in their own package
+		if o.doResource && (o.resourceFlag == nil || o.resourceFlag.Changed) {
+			return errors.New("--external-api-path can't be used to create the resource")
+		}
+		o.doResource = false
+	} else if o.resource.ExternalAPIDomain != "" {
+		return errors.New("--external-api-domain requires --external-api-path")
+	}
+
+	// The create resource and create controller commands don't have the flags, as they don't prompt for them
+	if o.resourceFlag != nil && !o.resourceFlag.Changed && !o.interactive && o.resource.ExternalAPIPath == "" {
+		fmt.Fprintln(o.out(), "Create Resource [y/n]")
 		o.doResource = internal.YesNo(reader)
 	}
-	if !o.controllerFlag.Changed {
-		fmt.Println("Create Controller [y/n]")
+	if o.controllerFlag != nil && !o.controllerFlag.Changed && !o.interactive && !c.APIServer {
+		fmt.Fprintln(o.out(), "Create Controller [y/n]")
 		o.doController = internal.YesNo(reader)
 	}
 
+	// The resources of aggregated API servers are served from their storage instead of being reconciled
+	if c.APIServer {
+		if o.doController && (o.controllerFlag == nil || o.controllerFlag.Changed) {
+			return errors.New("controllers can't be created in aggregated API server projects")
+		}
+		o.doController = false
+
+		// The objects are served as they are stored, without conversion between versions
+		for _, version := range c.KindVersions(o.resource.Group, o.resource.Kind) {
+			if version != o.resource.Version {
+				return fmt.Errorf("%s is already served in %s, aggregated API servers serve a Kind in one version only",
+					o.resource.Kind, version)
+			}
+		}
+	}
+
+	// The types of the reconciled resource are defined by the project, Kubernetes or an external package
+	if c.IsV2() && o.doController && !o.doResource && !c.HasResource(o.resource) &&
+		o.resource.ExternalAPIPath == "" && !util.IsCoreGroup(o.resource.Group) {
+		return fmt.Errorf("resource %s/%s, Kind=%s doesn't exist, create it first with kubebuilder create resource "+
+			"or set --external-api-path if its types are defined in another package",
+			o.resource.Group, o.resource.Version, o.resource.Kind)
+	}
+
+	if o.resource.Conditions {
+		if c.IsV1() {
+			return fmt.Errorf("--conditions is not supported for project version %s", c.Version)
+		}
+		// The controller of an existing resource updates the conditions it was created with
+		if !o.doResource && !c.HasResource(o.resource) {
+			return errors.New("--conditions requires the resource to be created")
+		}
+	}
+
+	// The controller of the resources with conditions updates them through the status subresource
+	if o.resource.Conditions && !o.resource.StatusSubresource && o.doResource {
+		return errors.New("--conditions can't be used with --subresource-status=false, " +
+			"the controller updates the conditions through the status subresource")
+	}
+
+	if o.resource.Scale != nil {
+		if c.IsV1() {
+			return fmt.Errorf("--subresource-scale is not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--subresource-scale requires the resource to be created")
+		}
+		// The registry of aggregated API servers serves the objects without subresources
+		if c.APIServer {
+			return errors.New("--subresource-scale can't be used in aggregated API server projects")
+		}
+	}
+
+	if o.resource.Suspend {
+		if c.IsV1() {
+			return fmt.Errorf("--suspend is not supported for project version %s", c.Version)
+		}
+		// The controller of an existing resource honors the spec.suspend field it was created with
+		if !o.doResource && !c.HasResource(o.resource) {
+			return errors.New("--suspend requires the resource to be created")
+		}
+	}
+
+	if o.resource.ApplyConfiguration {
+		if c.IsV1() {
+			return fmt.Errorf("--apply-configuration is not supported for project version %s", c.Version)
+		}
+		// The apply configuration is scaffolded next to the types of the resource
+		if !o.doResource && !c.HasResource(o.resource) {
+			return errors.New("--apply-configuration requires the resource to be created")
+		}
+	}
+
+	if len(o.resource.ShortNames) != 0 || len(o.resource.Categories) != 0 {
+		if c.IsV1() {
+			return fmt.Errorf("--short-name and --categories are not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--short-name and --categories require the resource to be created")
+		}
+	}
+
+	if len(o.resource.PrintColumns) != 0 {
+		if c.IsV1() {
+			return fmt.Errorf("--print-columns is not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--print-columns requires the resource to be created")
+		}
+		for _, column := range o.resource.PrintColumns {
+			if column == resource.PrintColumnReady && !o.resource.Conditions {
+				return errors.New("the ready printer column requires --conditions")
+			}
+		}
+	}
+
+	if o.resource.ControllerOptions {
+		if c.IsV1() {
+			return fmt.Errorf("--controller-options is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--controller-options requires the controller to be created")
+		}
+	}
+
+	if o.resource.FeatureGate {
+		if c.IsV1() {
+			return fmt.Errorf("--feature-gate is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--feature-gate requires the controller to be created")
+		}
+	}
+
+	if o.resource.Finalizer {
+		if c.IsV1() {
+			return fmt.Errorf("--with-finalizer is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--with-finalizer requires the controller to be created")
+		}
+	}
+
+	if o.resource.Metrics {
+		if c.IsV1() {
+			return fmt.Errorf("--metrics is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--metrics requires the controller to be created")
+		}
+	}
+
+	if len(o.resource.Owns) != 0 {
+		if c.IsV1() {
+			return fmt.Errorf("--owns is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--owns requires the controller to be created")
+		}
+	}
+
+	if o.resource.Image != "" {
+		if c.IsV1() {
+			return fmt.Errorf("--image is not supported for project version %s", c.Version)
+		}
+		// The types define the fields of the image and the controller deploys it
+		if !o.doResource || !o.doController {
+			return errors.New("--image requires both the resource and the controller to be created")
+		}
+		// The Deployment and the Service are created in the namespace of the object
+		if !o.resource.Namespaced {
+			return errors.New("--image requires a namespaced resource")
+		}
+		if o.pattern != "" {
+			return errors.New("--image can't be used with --pattern")
+		}
+	}
+
+	if len(o.resource.WatchesExternal) != 0 {
+		if c.IsV1() {
+			return fmt.Errorf("--watches-external is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--watches-external requires the controller to be created")
+		}
+	}
+
+	if o.resource.ReconcilePeriod != 0 {
+		if c.IsV1() {
+			return fmt.Errorf("--reconcile-period is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--reconcile-period requires the controller to be created")
+		}
+	}
+
+	if o.resource.WatchLabelSelector != "" {
+		if c.IsV1() {
+			return fmt.Errorf("--watch-label-selector is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--watch-label-selector requires the controller to be created")
+		}
+		// The event filters of the controller apply to all its watches, so they would also drop the events of the
+		// owned and watched objects, which have neither the labels nor a generation changed by status updates
+		if len(o.resource.Owns) != 0 || len(o.resource.WatchesExternal) != 0 {
+			return errors.New("--watch-label-selector can't be combined with --owns, --image or --watches-external")
+		}
+	}
+
+	if o.resource.Events {
+		if c.IsV1() {
+			return fmt.Errorf("--events is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--events requires the controller to be created")
+		}
+	}
+
+	if o.resource.TestStyle != "" && o.resource.TestStyle != resource.TestStyleEnvtest {
+		if c.IsV1() {
+			return fmt.Errorf("--test-style is not supported for project version %s", c.Version)
+		}
+		if !o.doController {
+			return errors.New("--test-style requires the controller to be created")
+		}
+	}
+
+	if o.resource.RBACMode != "" && o.resource.RBACMode != resource.RBACModeCluster {
+		if c.IsV1() {
+			return fmt.Errorf("--rbac-mode is not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--rbac-mode requires the resource to be created")
+		}
+		// Roles only grant access to the objects of their namespace
+		if o.resource.RBACMode == resource.RBACModeNamespaced && !o.resource.Namespaced {
+			return fmt.Errorf("--rbac-mode %s is not supported for cluster-scoped resources", resource.RBACModeNamespaced)
+		}
+	}
+
+	if o.resource.StorageVersion != "" {
+		if c.IsV1() {
+			return fmt.Errorf("--storage-version is not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--storage-version requires the resource to be created")
+		}
+	}
+
+	if o.resource.DefaultsMode != "" {
+		if c.IsV1() {
+			return fmt.Errorf("--defaults is not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--defaults requires the resource to be created")
+		}
+		switch o.resource.DefaultsMode {
+		case resource.DefaultsModeWebhook:
+			o.defaulting = true
+		case resource.DefaultsModeMarkers:
+			if o.defaulting {
+				fmt.Fprintf(o.out(), "Warning: both the default markers and the defaulting webhook set the defaults of the %s "+
+					"spec. The markers are applied first, so the webhook only sees the fields without a default "+
+					"marker unset, set the default of each field in only one of them.\n", o.resource.Kind)
+			}
+		}
+	}
+
+	if o.resource.ExampleFields != "" && o.resource.ExampleFields != resource.ExampleFieldsBasic {
+		if c.IsV1() {
+			return fmt.Errorf("--example-fields is not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--example-fields requires the resource to be created")
+		}
+	}
+
+	if o.defaulting || o.validation {
+		if c.IsV1() {
+			return fmt.Errorf("--defaulting and --validation are not supported for project version %s", c.Version)
+		}
+		if !o.doResource {
+			return errors.New("--defaulting and --validation require the resource to be created")
+		}
+	}
+
 	// In case we want to scaffold a resource API we need to do some checks
 	if o.doResource {
 		// Skip the following check for v1 as resources aren't tracked
@@ -195,28 +962,227 @@ func (o *apiOptions) validate(c *config.Config) error {
 						"kubebuilder.io/migration/multi-group.html")
 				}
 			}
+
+			if err := o.validateStorageVersion(c, reader); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// validateStorageVersion checks that the storage version is one of the versions of the Kind, prompting for it in
+// interactive mode when the Kind is served in multiple versions
+func (o *apiOptions) validateStorageVersion(c *config.Config, reader *bufio.Reader) error {
+	versions := []string{o.resource.Version}
+	for _, version := range c.KindVersions(o.resource.Group, o.resource.Kind) {
+		if version != o.resource.Version {
+			versions = append(versions, version)
+		}
+	}
+
+	validate := func(version string) error {
+		for _, v := range versions {
+			if version == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("storage version must be one of the versions of %s (%s)",
+			o.resource.Kind, strings.Join(versions, ", "))
+	}
+
+	if o.interactive && o.resource.StorageVersion == "" && len(versions) > 1 {
+		// An empty storage version keeps the current one
+		o.resource.StorageVersion = internal.Prompt(reader,
+			fmt.Sprintf("Storage version (%s), empty keeps the current one", strings.Join(versions, ", ")), "",
+			func(version string) error {
+				if version == "" {
+					return nil
+				}
+				return validate(version)
+			})
+	}
+
+	if o.resource.StorageVersion != "" {
+		return validate(o.resource.StorageVersion)
+	}
+
+	return nil
+}
+
+// validatePlural checks that every version of a Kind is served under the same plural, defaulting it to the
+// plural of the versions that were already created
+func (o *apiOptions) validatePlural(c *config.Config) error {
+	if c.IsV1() {
+		if o.resource.Resource != "" {
+			return fmt.Errorf("--plural is not supported for project version %s", c.Version)
+		}
+		return nil
+	}
+
+	if len(c.KindVersions(o.resource.Group, o.resource.Kind)) == 0 {
+		return nil
+	}
+	plural := c.KindPlural(o.resource.Group, o.resource.Kind)
+	if plural == "" {
+		plural = resource.DefaultPlural(o.resource.Kind)
+	}
+	if o.resource.Resource == "" {
+		o.resource.Resource = plural
+	} else if o.resource.Resource != plural {
+		return fmt.Errorf("plural must be the same in every version of %s (%s)", o.resource.Kind, plural)
+	}
+
+	return nil
+}
+
+// validateScope checks that every version of a Kind has the same scope, defaulting it to the scope of the
+// versions that were already created
+func (o *apiOptions) validateScope(c *config.Config) error {
+	namespaced, found := c.KindNamespaced(o.resource.Group, o.resource.Kind)
+	if !found {
+		return nil
+	}
+	if !o.interactive && o.namespacedFlag != nil && !o.namespacedFlag.Changed {
+		o.resource.Namespaced = namespaced
+	} else if o.resource.Namespaced != namespaced {
+		scope := "cluster-scoped"
+		if namespaced {
+			scope = "namespaced"
+		}
+		return fmt.Errorf("scope must be the same in every version of %s (%s)", o.resource.Kind, scope)
+	}
+
+	return nil
+}
+
+// prompt asks the user for the API values, validating them before continuing
+func (o *apiOptions) prompt(reader *bufio.Reader) {
+	o.resource.Group = internal.Prompt(reader, "Group", o.resource.Group, resource.ValidateGroup)
+	o.resource.Version = internal.Prompt(reader, "Version", o.resource.Version, resource.ValidateVersion)
+	o.resource.Kind = internal.Prompt(reader, "Kind", o.resource.Kind, resource.ValidateKind)
+
+	fmt.Println("Namespaced resource (cluster-scoped otherwise) [y/n]")
+	o.resource.Namespaced = internal.YesNo(reader)
+	fmt.Println("Create Resource [y/n]")
+	o.doResource = internal.YesNo(reader)
+	fmt.Println("Create Controller [y/n]")
+	o.doController = internal.YesNo(reader)
+}
+
 func (o *apiOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) {
-	plugins := make([]scaffold.Plugin, 0)
-	switch strings.ToLower(o.pattern) {
-	case "":
-		// Default pattern
+	chain, err := projectPluginChain(c)
+	if err != nil {
+		return nil, err
+	}
+	o.webhookPlugins = chain.WebhookPlugins()
+
+	// The plugins of the project are run first, then the ones of the pattern
+	plugins := chain.APIPlugins()
+	if o.pattern != "" {
+		pattern, err := patterns.Find(o.pattern)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, pattern.Plugins()...)
+	}
+
+	// External plugins are run after the pattern, so that they can adapt the files it generates
+	if os.Getenv("KUBEBUILDER_ENABLE_PLUGINS") != "" {
+		execPlugins, err := scaffold.DiscoverExecPlugins(os.Getenv("PATH"))
+		if err != nil {
+			return nil, fmt.Errorf("error discovering plugins: %v", err)
+		}
+		for _, plugin := range execPlugins {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	// A nil reporter prints the files as text
+	var reporter scaffold.Reporter
+	if o.reporter != nil {
+		reporter = o.reporter
+	}
+
+	var scaffolder scaffold.Scaffolder
+	if o.fromFile != "" {
+		scaffolders := make([]scaffold.Scaffolder, 0, 2*len(o.batch))
+		for _, api := range o.batch {
+			scaffolders = append(scaffolders, api.apiScaffolders(c, plugins, reporter, o.out())...)
+		}
+		scaffolder = scaffold.NewBatchScaffolder(c, reporter, scaffolders...)
+	} else if scaffolders := o.apiScaffolders(c, plugins, reporter, o.out()); len(scaffolders) == 1 {
+		scaffolder = scaffolders[0]
+	} else {
+		scaffolder = sequentialScaffolder(scaffolders)
+	}
 
-	case "addon":
-		plugins = append(plugins, &addon.Plugin{})
+	return stage(c, scaffolder, o.dryRun, o.keepPartial), nil
+}
 
+// stage returns the scaffolder that previews the changes of scaffolder with --dry-run or, unless --keep-partial is
+// set, writes them at once after scaffolding succeeded so that a failure leaves the project untouched
+func stage(c *config.Config, scaffolder scaffold.Scaffolder, dryRun, keepPartial bool) scaffold.Scaffolder {
+	switch {
+	case dryRun:
+		return scaffold.NewDryRunScaffolder(c, scaffolder, os.Stdout)
+	case keepPartial:
+		return scaffolder
 	default:
-		return nil, fmt.Errorf("unknown pattern %q", o.pattern)
+		return scaffold.NewStagedScaffolder(c, scaffolder)
 	}
+}
 
-	return scaffold.NewAPIScaffolder(c, o.resource, o.doResource, o.doController, plugins), nil
+// createsResource returns true if the resource of the API, or of any API of the batch, is created
+func (o *apiOptions) createsResource() bool {
+	if o.doResource {
+		return true
+	}
+	for _, api := range o.batch {
+		if api.doResource {
+			return true
+		}
+	}
+	return false
 }
 
-func (o *apiOptions) postScaffold(_ *config.Config) error {
-	return internal.RunCmd("Running make", "make")
+// apiScaffolders returns the scaffolder of the API, followed by the one of its webhooks if any
+func (o *apiOptions) apiScaffolders(c *config.Config, plugins []scaffold.Plugin,
+	reporter scaffold.Reporter, out io.Writer) []scaffold.Scaffolder {
+	scaffolders := []scaffold.Scaffolder{
+		scaffold.NewAPIScaffolder(c, o.resource, o.doResource, o.doController, o.force, plugins, o.templatesDir,
+			reporter, out),
+	}
+	if o.defaulting || o.validation {
+		scaffolders = append(scaffolders, scaffold.NewV2WebhookScaffolder(c, o.resource, o.defaulting, o.validation,
+			false, "", "", webhookv2.AdmissionOptions{}, o.webhookPlugins, o.templatesDir, reporter, out))
+	}
+
+	return scaffolders
+}
+
+func (o *apiOptions) postScaffold(c *config.Config) error {
+	if o.dryRun {
+		return nil
+	}
+
+	if o.runMake {
+		if err := internal.RunCmdTo(o.out(), "Running make", "make"); err != nil {
+			return err
+		}
+	} else if o.generate && !c.IsV1() && o.createsResource() {
+		// The types don't compile until their DeepCopyObject methods are generated
+		fmt.Fprintln(o.out(), "Generating the deep-copy methods...")
+		paths, err := scaffold.GenerateDeepCopy(c.Fs())
+		if err != nil {
+			return fmt.Errorf("error generating the deep-copy methods: %v, run make generate once the types compile",
+				err)
+		}
+		for _, path := range paths {
+			fmt.Fprintln(o.out(), path)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+// apiSpec describes an API listed in the file of create api --from-file. The options that aren't set default to
+// the flags of the command.
+type apiSpec struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+
+	// Plural defaults to the lowercase Kind pluralized
+	Plural string `json:"plural,omitempty"`
+	// GroupPackage defaults to the package recorded for the group, or the group without its dashes and dots
+	GroupPackage string `json:"groupPackage,omitempty"`
+
+	Namespaced *bool `json:"namespaced,omitempty"`
+	Resource   *bool `json:"resource,omitempty"`
+	Controller *bool `json:"controller,omitempty"`
+
+	// Options of the resource, see the flags of the same name
+	ShortNames         []string `json:"shortNames,omitempty"`
+	Categories         []string `json:"categories,omitempty"`
+	Conditions         *bool    `json:"conditions,omitempty"`
+	ConditionsPackage  *bool    `json:"conditionsPackage,omitempty"`
+	Suspend            *bool    `json:"suspend,omitempty"`
+	SubresourceStatus  *bool    `json:"subresourceStatus,omitempty"`
+	SubresourceScale   string   `json:"subresourceScale,omitempty"`
+	ApplyConfiguration *bool    `json:"applyConfiguration,omitempty"`
+	PrintColumns       []string `json:"printColumns,omitempty"`
+	RBACMode           string   `json:"rbacMode,omitempty"`
+	StorageVersion     string   `json:"storageVersion,omitempty"`
+	Defaults           string   `json:"defaults,omitempty"`
+	ExampleFields      string   `json:"exampleFields,omitempty"`
+	Defaulting         *bool    `json:"defaulting,omitempty"`
+	Validation         *bool    `json:"validation,omitempty"`
+
+	// Options of the controller, see the flags of the same name
+	Example            *bool    `json:"example,omitempty"`
+	WithFinalizer      *bool    `json:"withFinalizer,omitempty"`
+	Metrics            *bool    `json:"metrics,omitempty"`
+	Events             *bool    `json:"events,omitempty"`
+	ControllerOptions  *bool    `json:"controllerOptions,omitempty"`
+	FeatureGate        *bool    `json:"featureGate,omitempty"`
+	ExternalAPIPath    string   `json:"externalAPIPath,omitempty"`
+	ExternalAPIDomain  string   `json:"externalAPIDomain,omitempty"`
+	TestStyle          string   `json:"testStyle,omitempty"`
+	WatchLabelSelector string   `json:"watchLabelSelector,omitempty"`
+	ReconcilePeriod    string   `json:"reconcilePeriod,omitempty"`
+	Owns               []string `json:"owns,omitempty"`
+	Image              string   `json:"image,omitempty"`
+	ImageContainerPort int32    `json:"imageContainerPort,omitempty"`
+	WatchesExternal    []string `json:"watchesExternal,omitempty"`
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func stringsOrDefault(values, defaultValues []string) []string {
+	if values == nil {
+		return defaultValues
+	}
+	return values
+}
+
+// validateBatch reads the APIs listed in the file and checks their options in the order they are created
+func (o *apiOptions) validateBatch(c *config.Config, reader *bufio.Reader) error {
+	if c.IsV1() {
+		return fmt.Errorf("--from-file is not supported for project version %s", c.Version)
+	}
+	if o.interactive {
+		return errors.New("--from-file can't be used with --interactive")
+	}
+	if o.resource.Group != "" || o.resource.Version != "" || o.resource.Kind != "" || o.resource.Resource != "" ||
+		o.resource.GroupPackage != "" {
+		return errors.New("--group, --version, --kind, --plural and --group-package are set for each API in the " +
+			"file of --from-file")
+	}
+
+	content, err := ioutil.ReadFile(o.fromFile)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %v", o.fromFile, err)
+	}
+	var specs []apiSpec
+	if err := yaml.UnmarshalStrict(content, &specs); err != nil {
+		return fmt.Errorf("unable to parse %s: %v", o.fromFile, err)
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("%s doesn't list any API", o.fromFile)
+	}
+
+	// Each API is checked against the project as it is once the previous ones are created
+	batchConfig := *c
+	batchConfig.Resources = append([]modelconfig.GVK(nil), c.Resources...)
+	gvks := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		gvk := fmt.Sprintf("%s/%s, Kind=%s", spec.Group, spec.Version, spec.Kind)
+		if _, duplicated := gvks[gvk]; duplicated {
+			return fmt.Errorf("%s is listed more than once in %s", gvk, o.fromFile)
+		}
+		gvks[gvk] = struct{}{}
+
+		api, err := o.batchOptions(c, spec)
+		if err != nil {
+			return fmt.Errorf("%s: %v", gvk, err)
+		}
+		if err := api.validateResource(&batchConfig, reader); err != nil {
+			return fmt.Errorf("%s: %v", gvk, err)
+		}
+		if api.doResource {
+			batchConfig.AddResource(api.resource, modelconfig.ResourceState{})
+		}
+		o.batch = append(o.batch, api)
+	}
+
+	return nil
+}
+
+// batchOptions returns the options of an API listed in the file, defaulting to the flags of the command
+func (o *apiOptions) batchOptions(c *config.Config, spec apiSpec) (*apiOptions, error) {
+	api := *o
+	api.fromFile, api.batch = "", nil
+
+	// The APIs of the file are created without prompting
+	api.resourceFlag, api.controllerFlag = nil, nil
+	api.doResource = boolOrDefault(spec.Resource, o.doResource)
+	doController := o.doController
+	if c.APIServer && o.controllerFlag != nil && !o.controllerFlag.Changed {
+		doController = false
+	}
+	api.doController = boolOrDefault(spec.Controller, doController)
+
+	res := *o.resource
+	res.Group, res.Version, res.Kind, res.Resource = spec.Group, spec.Version, spec.Kind, spec.Plural
+	res.GroupPackage = spec.GroupPackage
+	res.Namespaced = boolOrDefault(spec.Namespaced, res.Namespaced)
+	if spec.Namespaced != nil {
+		api.namespacedFlag = nil
+	}
+	res.ShortNames = stringsOrDefault(spec.ShortNames, res.ShortNames)
+	res.Categories = stringsOrDefault(spec.Categories, res.Categories)
+	res.Conditions = boolOrDefault(spec.Conditions, res.Conditions)
+	res.ConditionsPackage = boolOrDefault(spec.ConditionsPackage, res.ConditionsPackage)
+	res.Suspend = boolOrDefault(spec.Suspend, res.Suspend)
+	res.StatusSubresource = boolOrDefault(spec.SubresourceStatus, res.StatusSubresource)
+	res.ApplyConfiguration = boolOrDefault(spec.ApplyConfiguration, res.ApplyConfiguration)
+	res.RBACMode = stringOrDefault(spec.RBACMode, res.RBACMode)
+	res.StorageVersion = stringOrDefault(spec.StorageVersion, res.StorageVersion)
+	res.DefaultsMode = stringOrDefault(spec.Defaults, res.DefaultsMode)
+	res.ExampleFields = stringOrDefault(spec.ExampleFields, res.ExampleFields)
+	res.CreateExampleReconcileBody = boolOrDefault(spec.Example, res.CreateExampleReconcileBody)
+	res.Finalizer = boolOrDefault(spec.WithFinalizer, res.Finalizer)
+	res.Metrics = boolOrDefault(spec.Metrics, res.Metrics)
+	res.Events = boolOrDefault(spec.Events, res.Events)
+	res.ControllerOptions = boolOrDefault(spec.ControllerOptions, res.ControllerOptions)
+	res.FeatureGate = boolOrDefault(spec.FeatureGate, res.FeatureGate)
+	res.ExternalAPIPath = stringOrDefault(spec.ExternalAPIPath, res.ExternalAPIPath)
+	res.ExternalAPIDomain = stringOrDefault(spec.ExternalAPIDomain, res.ExternalAPIDomain)
+	res.TestStyle = stringOrDefault(spec.TestStyle, res.TestStyle)
+	res.WatchLabelSelector = stringOrDefault(spec.WatchLabelSelector, res.WatchLabelSelector)
+	if spec.ReconcilePeriod != "" {
+		period, err := time.ParseDuration(spec.ReconcilePeriod)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reconcilePeriod: %v", err)
+		}
+		res.ReconcilePeriod = period
+	}
+	res.Image = stringOrDefault(spec.Image, res.Image)
+	if spec.ImageContainerPort != 0 {
+		res.ImageContainerPort = spec.ImageContainerPort
+	}
+	api.resource = &res
+
+	// The types of external resources are defined in their own package
+	if res.ExternalAPIPath != "" && spec.Resource == nil {
+		api.doResource = false
+	}
+
+	api.printColumns = stringsOrDefault(spec.PrintColumns, o.printColumns)
+	api.subresourceScale = stringOrDefault(spec.SubresourceScale, o.subresourceScale)
+	api.owns = stringsOrDefault(spec.Owns, o.owns)
+	api.watchesExternal = stringsOrDefault(spec.WatchesExternal, o.watchesExternal)
+	api.defaulting = boolOrDefault(spec.Defaulting, o.defaulting)
+	api.validation = boolOrDefault(spec.Validation, o.validation)
+
+	return &api, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestBatchOptionsAPIServerController(t *testing.T) {
+	tests := []struct {
+		name string
+		// options returns the options the file is read with
+		options  func() *apiOptions
+		expected bool
+	}{
+		{
+			name: "default --controller",
+			options: func() *apiOptions {
+				options := &apiOptions{}
+				parseFlags(t, options, "--group", "ship", "--version", "v1", "--kind", "Frigate")
+				return options
+			},
+			expected: false,
+		},
+		{
+			name: "explicit --controller",
+			options: func() *apiOptions {
+				options := &apiOptions{}
+				parseFlags(t, options, "--group", "ship", "--version", "v1", "--kind", "Frigate", "--controller")
+				return options
+			},
+			expected: true,
+		},
+		{
+			name: "without the --controller flag",
+			options: func() *apiOptions {
+				options := &apiOptions{doController: true, resource: &resource.Resource{}}
+				options.bindCommonFlags(&cobra.Command{})
+				return options
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		c := newTestConfig()
+		c.APIServer = true
+
+		api, err := test.options().batchOptions(c, apiSpec{Group: "ship", Version: "v1", Kind: "Destroyer"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if api.doController != test.expected {
+			t.Errorf("%s: expected the controller to be created: %v", test.name, test.expected)
+		}
+	}
+}
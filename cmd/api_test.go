@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+// newTestConfig returns the configuration of an initialized v2 project in an in-memory filesystem
+func newTestConfig() *config.Config {
+	c := config.New(config.DefaultPath)
+	c.SetFs(afero.NewMemMapFs())
+	c.Domain = "example.com"
+	c.Repo = "example.com/project"
+	return c
+}
+
+// parseFlags binds the flags of the options to a command and parses the arguments
+func parseFlags(t *testing.T, options interface{ bindFlags(*cobra.Command) }, args ...string) {
+	cmd := &cobra.Command{}
+	options.bindFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("unable to parse %v: %v", args, err)
+	}
+}
+
+func TestAPIOptionsWatchLabelSelector(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"--watch-label-selector", "fleet=north"}},
+		{args: []string{"--watch-label-selector", "fleet=north", "--controller=false"},
+			err: "requires the controller"},
+		{args: []string{"--watch-label-selector", "fleet=north", "--owns", "apps/v1/Deployment"},
+			err: "can't be combined with --owns"},
+		{args: []string{"--watch-label-selector", "fleet=north", "--image", "nginx:1.19"},
+			err: "can't be combined with --owns"},
+		{args: []string{"--watch-label-selector", "fleet=north", "--watches-external", "core/v1/ConfigMap"},
+			err: "can't be combined with --owns"},
+	}
+
+	for _, test := range tests {
+		options := &apiOptions{}
+		args := []string{"--group", "ship", "--version", "v1", "--kind", "Frigate", "--resource", "--controller"}
+		parseFlags(t, options, append(args, test.args...)...)
+
+		err := options.validate(newTestConfig())
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%v: unexpected error: %v", test.args, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%v: expected an error containing %q, got %v", test.args, test.err, err)
+		}
+	}
+}
+
+func TestRunAPIJSONReportError(t *testing.T) {
+	tests := []struct {
+		name string
+		// project is the content of the PROJECT file, none if empty
+		project string
+		args    []string
+		err     string
+	}{
+		{
+			name: "uninitialized project",
+			err:  "project must be initialized",
+		},
+		{
+			name:    "--dry-run",
+			project: "domain: example.com\nrepo: example.com/project\nversion: \"2\"\n",
+			args:    []string{"--dry-run"},
+			err:     "can't be used with --dry-run",
+		},
+	}
+
+	for _, test := range tests {
+		func() {
+			defer chdirTemp(t)()
+			if test.project != "" {
+				if err := ioutil.WriteFile("PROJECT", []byte(test.project), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			options := &apiOptions{}
+			args := []string{"--group", "ship", "--version", "v1", "--kind", "Frigate", "--resource", "--controller",
+				"--make=false", "--output", "json"}
+			parseFlags(t, options, append(args, test.args...)...)
+
+			stdout := os.Stdout
+			out := &bytes.Buffer{}
+			err := runAPI(options, out)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("%s: expected an error containing %q, got %v", test.name, test.err, err)
+			}
+			if os.Stdout != stdout {
+				t.Errorf("%s: the standard output was replaced", test.name)
+			}
+
+			// The report is written even though the API wasn't created
+			report := &scaffold.JSONReporter{}
+			if err := json.Unmarshal(out.Bytes(), report); err != nil {
+				t.Fatalf("%s: unable to parse the report %q: %v", test.name, out.String(), err)
+			}
+			if report.Error != err.Error() {
+				t.Errorf("%s: expected the report to contain the error %q, got %q", test.name, err, report.Error)
+			}
+			if len(report.Files) != 0 {
+				t.Errorf("%s: expected no file to be reported, got %v", test.name, report.Files)
+			}
+		}()
+	}
+}
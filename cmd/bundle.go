@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type bundleError struct {
+	err error
+}
+
+func (e bundleError) Error() string {
+	return fmt.Sprintf("failed to create bundle: %v", e.err)
+}
+
+func newBundleCmd() *cobra.Command {
+	options := &bundleOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Generate an Operator Lifecycle Manager bundle",
+		Long: `Generate an Operator Lifecycle Manager (OLM) bundle in the bundle directory, made of:
+- a ClusterServiceVersion that owns the tracked APIs, with their samples as examples and the manager RBAC rules.
+- the CRDs generated in config/crd/bases.
+- the bundle metadata and a bundle.Dockerfile to build the bundle image.
+
+Run "make manifests" before generating the bundle so that it includes the latest CRDs and RBAC rules.
+The bundle is regenerated every time this command is run.
+`,
+		Example: `	# Generate the bundle for version 0.1.0 of the operator
+	kubebuilder create bundle --version 0.1.0 --image example.com/memcached-operator:v0.1.0
+
+	# Publish the bundle in the alpha and stable channels, stable being the default one
+	kubebuilder create bundle --version 1.0.0 --channels stable,alpha
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(bundleError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &bundleOptions{}
+
+type bundleOptions struct {
+	version  string
+	image    string
+	channels []string
+}
+
+func (o *bundleOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.version, "version", "0.0.1", "version of the operator")
+	cmd.Flags().StringVar(&o.image, "image", "controller:latest", "controller manager image")
+	cmd.Flags().StringSliceVar(&o.channels, "channels", []string{"alpha"},
+		"channels the bundle is published in, the first one is the default channel")
+}
+
+func (o *bundleOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *bundleOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("OLM bundles are not supported for version %s", c.Version)
+	}
+
+	if o.version == "" {
+		return errors.New("version cannot be empty")
+	}
+	if strings.HasPrefix(o.version, "v") {
+		return fmt.Errorf("version must not start with \"v\" (was %s)", o.version)
+	}
+
+	if len(o.channels) == 0 {
+		return errors.New("at least one channel is required")
+	}
+
+	return nil
+}
+
+func (o *bundleOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewBundleScaffolder(c, o.version, o.image, o.channels), nil
+}
+
+func (o *bundleOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+const (
+	shellBash = "bash"
+	shellZsh  = "zsh"
+	shellFish = "fish"
+)
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish]",
+		Short: "Generate the completion script for the specified shell",
+		Long: `Generate the completion script of kubebuilder for the specified shell.
+
+Besides the subcommands and their flags, the script completes the values of the --group, --version
+and --kind flags with the resources tracked in the PROJECT file of the current directory. Versions
+and Kinds are narrowed down to the group and version already given, if any.
+`,
+		Example: `	# Load the completion in the current bash session
+	source <(kubebuilder completion bash)
+
+	# Load the completion for every bash session (Linux)
+	kubebuilder completion bash > /etc/bash_completion.d/kubebuilder
+
+	# Load the completion for every zsh session, "compinit" must be enabled in ~/.zshrc
+	kubebuilder completion zsh > "${fpath[1]}/_kubebuilder"
+
+	# Load the completion for every fish session
+	kubebuilder completion fish > ~/.config/fish/completions/kubebuilder.fish
+`,
+		ValidArgs:             []string{shellBash, shellZsh, shellFish},
+		Args:                  cobra.ExactValidArgs(1),
+		DisableFlagsInUseLine: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := genCompletion(cmd.Root(), args[0]); err != nil {
+				log.Fatal(fmt.Errorf("failed to generate the completion script: %v", err))
+			}
+		},
+	}
+}
+
+// genCompletion writes the completion script of the command tree for the shell to the standard output
+func genCompletion(root *cobra.Command, shell string) error {
+	switch shell {
+	case shellBash:
+		return root.GenBashCompletion(os.Stdout)
+	case shellZsh:
+		return root.GenZshCompletion(os.Stdout)
+	case shellFish:
+		return root.GenFishCompletion(os.Stdout, true)
+	default:
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+}
+
+// bindResourceFlags binds the flags that identify the resource of a command, completing their values with the
+// resources tracked in the PROJECT file
+func bindResourceFlags(cmd *cobra.Command, r *resource.Resource) {
+	cmd.Flags().StringVar(&r.Group, "group", "", "resource Group")
+	cmd.Flags().StringVar(&r.Version, "version", "", "resource Version")
+	cmd.Flags().StringVar(&r.Kind, "kind", "", "resource Kind")
+
+	for _, flag := range []string{"group", "version", "kind"} {
+		_ = cmd.RegisterFlagCompletionFunc(flag, completeResourceFlag(flag))
+	}
+}
+
+// completeResourceFlag returns the completion function of a resource flag, suggesting the values of the tracked
+// resources that match the rest of resource flags already set
+func completeResourceFlag(flag string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		projectConfig, err := config.Load()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		seen := make(map[string]bool)
+		values := make([]string, 0, len(projectConfig.Resources))
+		for _, gvk := range projectConfig.Resources {
+			fields := map[string]string{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind}
+			if !matchesFlags(cmd, fields, flag) {
+				continue
+			}
+
+			value := fields[flag]
+			if seen[value] || !strings.HasPrefix(value, toComplete) {
+				continue
+			}
+			seen[value] = true
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// matchesFlags returns true if the fields match the values of the flags already set, ignoring the completed one
+func matchesFlags(cmd *cobra.Command, fields map[string]string, completed string) bool {
+	for name, field := range fields {
+		if name == completed {
+			continue
+		}
+		if value, _ := cmd.Flags().GetString(name); value != "" && value != field {
+			return false
+		}
+	}
+	return true
+}
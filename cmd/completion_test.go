@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// chdirTemp changes the working directory to a new temporary directory, the returned function restores it
+func chdirTemp(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "kubebuilder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		_ = os.Chdir(wd)
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestCompleteResourceFlag(t *testing.T) {
+	defer chdirTemp(t)()
+
+	// The resources are completed from the PROJECT file of the working directory
+	if err := ioutil.WriteFile("PROJECT", []byte(`domain: example.com
+repo: example.com/project
+resources:
+- group: ship
+  kind: Frigate
+  version: v1
+- group: ship
+  kind: Frigate
+  version: v1beta1
+- group: ship
+  kind: Destroyer
+  version: v1
+- group: crew
+  kind: Captain
+  version: v1
+schemaVersion: v2
+version: "2"
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		flag       string
+		args       []string
+		toComplete string
+		expected   []string
+	}{
+		{flag: "group", expected: []string{"crew", "ship"}},
+		{flag: "group", toComplete: "s", expected: []string{"ship"}},
+		{flag: "version", expected: []string{"v1", "v1beta1"}},
+		{flag: "version", args: []string{"--kind", "Destroyer"}, expected: []string{"v1"}},
+		{flag: "kind", args: []string{"--group", "ship"}, expected: []string{"Destroyer", "Frigate"}},
+		{flag: "kind", args: []string{"--group", "ship", "--version", "v1beta1"}, expected: []string{"Frigate"}},
+		{flag: "kind", args: []string{"--group", "fleet"}, expected: []string{}},
+		// The value of the completed flag itself doesn't narrow down the suggestions
+		{flag: "kind", args: []string{"--kind", "Frigate"}, toComplete: "C", expected: []string{"Captain"}},
+	}
+
+	for _, test := range tests {
+		cmd := &cobra.Command{}
+		bindResourceFlags(cmd, &resource.Resource{})
+		if err := cmd.ParseFlags(test.args); err != nil {
+			t.Fatalf("unable to parse %v: %v", test.args, err)
+		}
+
+		values, directive := completeResourceFlag(test.flag)(cmd, nil, test.toComplete)
+		if !reflect.DeepEqual(values, test.expected) {
+			t.Errorf("--%s %v %q: expected %v, got %v", test.flag, test.args, test.toComplete, test.expected, values)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("--%s %v %q: expected no file completion, got %v", test.flag, test.args, test.toComplete, directive)
+		}
+	}
+}
+
+func TestCompleteResourceFlagWithoutProject(t *testing.T) {
+	defer chdirTemp(t)()
+
+	cmd := &cobra.Command{}
+	bindResourceFlags(cmd, &resource.Resource{})
+	values, directive := completeResourceFlag("group")(cmd, nil, "")
+	if len(values) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("expected no suggestions, got %v (%v)", values, directive)
+	}
+}
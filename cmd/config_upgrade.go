@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type configUpgradeError struct {
+	err error
+}
+
+func (e configUpgradeError) Error() string {
+	return fmt.Sprintf("failed to upgrade the configuration file: %v", e.err)
+}
+
+func newConfigUpgradeCmd() *cobra.Command {
+	options := &configUpgradeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Rewrite the PROJECT file with the latest configuration schema",
+		Long: fmt.Sprintf(`Rewrite the PROJECT file with the latest configuration schema (%s).
+
+The PROJECT file keeps the schema it was written with, so that older versions of kubebuilder can still
+work on the project, until it is upgraded. Newer versions of kubebuilder read every previous schema.
+
+The controllers, webhooks and samples of the resources, which are tracked since the schema v2, are
+found in the project files when upgrading from the schema v1.
+`, config.LatestSchema),
+		Example: `	# Upgrade the PROJECT file in the current directory
+	kubebuilder config upgrade
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(configUpgradeError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &configUpgradeOptions{}
+
+type configUpgradeOptions struct{}
+
+func (o *configUpgradeOptions) bindFlags(_ *cobra.Command) {}
+
+func (o *configUpgradeOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *configUpgradeOptions) validate(_ *config.Config) error {
+	return nil
+}
+
+func (o *configUpgradeOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewConfigUpgradeScaffolder(c), nil
+}
+
+func (o *configUpgradeOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
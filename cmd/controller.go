@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+type controllerError struct {
+	err error
+}
+
+func (e controllerError) Error() string {
+	return fmt.Sprintf("failed to create controller: %v", e.err)
+}
+
+func newControllerCmd() *cobra.Command {
+	options := &controllerOptions{apiOptions{doController: true}}
+
+	cmd := &cobra.Command{
+		Use:   "controller",
+		Short: "Scaffold the controller of a Kubernetes API",
+		Long: `Scaffold the controller of a Kubernetes API and register it in main.go, without creating the resource.
+
+The resource has to be created by kubebuilder create resource or create api, be a Kubernetes built-in type such as
+apps/v1 Deployment, or have its types defined in the package set by --external-api-path.
+
+After the scaffold is written, controller will run make on the project.
+`,
+		Example: `	# Create the controller of the frigates resource with Group: ship, Version: v1beta1 and Kind: Frigate
+	kubebuilder create controller --group ship --version v1beta1 --kind Frigate
+
+	# Create a controller that owns Deployments, records events about the Frigates and updates their conditions
+	kubebuilder create controller --group ship --version v1beta1 --kind Frigate --owns apps/v1/Deployment --events \
+		--conditions
+
+	# Create a controller for the Deployments
+	kubebuilder create controller --group apps --version v1 --kind Deployment --example=false
+
+	# Create a controller for a resource whose types are defined in an external package
+	kubebuilder create controller --group cert-manager --version v1alpha2 --kind Certificate \
+		--external-api-path github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2 --external-api-domain io
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(controllerError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &controllerOptions{}
+
+// controllerOptions are the options of an API that only scaffold its controller
+type controllerOptions struct {
+	apiOptions
+}
+
+func (o *controllerOptions) bindFlags(cmd *cobra.Command) {
+	o.bindCommonFlags(cmd)
+	o.bindControllerFlags(cmd)
+	cmd.Flags().BoolVar(&o.resource.Conditions, "conditions", false,
+		"if set, update the conditions of the resource, which has to be created with --conditions, from the controller")
+	cmd.Flags().BoolVar(&o.resource.ConditionsPackage, "conditions-package", false,
+		"if set, implies --conditions with the helpers of the pkg/conditions package, the resource has to be "+
+			"created with --conditions-package")
+	cmd.Flags().BoolVar(&o.resource.Suspend, "suspend", false,
+		"if set, skip the reconciliation while spec.suspend is true, the resource has to be created with --suspend")
+}
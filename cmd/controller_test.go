@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestControllerOptionsFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	(&controllerOptions{}).bindFlags(cmd)
+
+	for _, name := range []string{"group", "version", "kind", "owns", "external-api-path", "conditions", "suspend"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected the --%s flag", name)
+		}
+	}
+	// The controller is created without prompting and the type flags belong to create resource
+	for _, name := range []string{"resource", "controller", "defaulting", "short-name", "subresource-scale"} {
+		if cmd.Flags().Lookup(name) != nil {
+			t.Errorf("unexpected --%s flag", name)
+		}
+	}
+}
+
+func TestControllerOptionsValidate(t *testing.T) {
+	frigate := []string{"--group", "ship", "--version", "v1", "--kind", "Frigate"}
+	destroyer := []string{"--group", "ship", "--version", "v1", "--kind", "Destroyer"}
+	certificate := []string{"--group", "cert-manager", "--version", "v1alpha2", "--kind", "Certificate"}
+
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: frigate},
+		{args: append(frigate, "--owns", "apps/v1/Deployment", "--conditions")},
+		// The types of the Kubernetes built-in types and of external packages aren't defined by the project
+		{args: []string{"--group", "apps", "--version", "v1", "--kind", "Deployment"}},
+		{args: append(certificate, "--external-api-path", "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2",
+			"--external-api-domain", "io")},
+		{args: destroyer, err: "doesn't exist, create it first with kubebuilder create resource"},
+		{args: append(destroyer, "--conditions"), err: "doesn't exist"},
+		{args: append(certificate, "--external-api-domain", "io"), err: "requires --external-api-path"},
+	}
+
+	for _, test := range tests {
+		c := newTestConfig()
+		c.AddResource(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}, modelconfig.ResourceState{})
+
+		options := &controllerOptions{apiOptions{doController: true}}
+		parseFlags(t, options, test.args...)
+
+		err := options.validate(c)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%v: unexpected error: %v", test.args, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%v: expected an error containing %q, got %v", test.args, test.err, err)
+		case test.err == "" && (options.doResource || !options.doController):
+			t.Errorf("%v: expected only the controller to be created, got resource=%v controller=%v",
+				test.args, options.doResource, options.doController)
+		}
+	}
+}
+
+func TestControllerOptionsScaffold(t *testing.T) {
+	c := newTestConfig()
+	if err := scaffold.NewInitScaffolder(c, "none", "", nil, "").Scaffold(); err != nil {
+		t.Fatal(err)
+	}
+	frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+	if err := scaffold.NewAPIScaffolder(c, frigate, true, false, false, nil, "", nil, nil).Scaffold(); err != nil {
+		t.Fatal(err)
+	}
+	types, err := afero.ReadFile(c.Fs(), filepath.Join("api", "v1", "frigate_types.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args       []string
+		controller string
+		imports    string
+	}{
+		{
+			args:       []string{"--group", "ship", "--version", "v1", "--kind", "Frigate"},
+			controller: filepath.Join("controllers", "frigate_controller.go"),
+			imports:    `shipv1 "example.com/project/api/v1"`,
+		},
+		{
+			args: []string{"--group", "cert-manager", "--version", "v1alpha2", "--kind", "Certificate",
+				"--external-api-path", "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2",
+				"--external-api-domain", "io"},
+			controller: filepath.Join("controllers", "certificate_controller.go"),
+			imports:    `"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"`,
+		},
+	}
+
+	for _, test := range tests {
+		options := &controllerOptions{apiOptions{doController: true}}
+		parseFlags(t, options, test.args...)
+		if err := options.validate(c); err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.args, err)
+		}
+		for _, scaffolder := range options.apiScaffolders(c, nil, nil, nil) {
+			if err := scaffolder.Scaffold(); err != nil {
+				t.Fatalf("%v: unexpected error: %v", test.args, err)
+			}
+		}
+
+		content, err := afero.ReadFile(c.Fs(), test.controller)
+		if err != nil {
+			t.Fatalf("%v: %v", test.args, err)
+		}
+		if !strings.Contains(string(content), test.imports) {
+			t.Errorf("%v: expected %s to import %s", test.args, test.controller, test.imports)
+		}
+	}
+
+	// The types of the existing resource are left as they are
+	if updated, err := afero.ReadFile(c.Fs(), filepath.Join("api", "v1", "frigate_types.go")); err != nil {
+		t.Fatal(err)
+	} else if string(updated) != string(types) {
+		t.Errorf("expected the types of Frigate to be left unchanged")
+	}
+	expectFiles(t, c.Fs(), map[string]bool{
+		filepath.Join("api", "v1alpha2", "certificate_types.go"): false,
+	})
+}
@@ -23,7 +23,7 @@ import (
 func newCreateCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "create",
-		Short: "Scaffold a Kubernetes API or webhook.",
-		Long:  `Scaffold a Kubernetes API or webhook.`,
+		Short: "Scaffold a Kubernetes API, its resource or controller, a webhook, an OLM bundle or a kubectl plugin.",
+		Long:  `Scaffold a Kubernetes API, its resource or controller, a webhook, an OLM bundle or a kubectl plugin.`,
 	}
 }
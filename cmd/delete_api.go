@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/cmd/internal"
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+type deleteAPIError struct {
+	err error
+}
+
+func (e deleteAPIError) Error() string {
+	return fmt.Sprintf("failed to delete API: %v", e.err)
+}
+
+func newDeleteAPICmd() *cobra.Command {
+	options := &deleteAPIOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Remove a scaffolded Kubernetes API",
+		Long: `Remove a scaffolded Kubernetes API by deleting its Resource definition, Controller, Webhook,
+samples, RBAC roles and CRD patches, and un-wiring it from main.go, the CRD and samples kustomizations and
+the PROJECT file.
+
+After the files are removed, api will run make on the project.
+`,
+		Example: `	# Delete the frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
+	kubebuilder delete api --group ship --version v1beta1 --kind Frigate
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(deleteAPIError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &deleteAPIOptions{}
+
+type deleteAPIOptions struct {
+	resource *resource.Resource
+	// groupFlag is used to allow an empty group only if it was set explicitly
+	groupFlag *flag.Flag
+
+	// runMake indicates whether to run make or not after deleting APIs
+	runMake bool
+}
+
+func (o *deleteAPIOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after removing files")
+
+	o.resource = &resource.Resource{}
+	bindResourceFlags(cmd, o.resource)
+	o.groupFlag = cmd.Flag("group")
+}
+
+func (o *deleteAPIOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *deleteAPIOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("deleting APIs is not supported for version %s", c.Version)
+	}
+	if c.APIServer {
+		return errors.New("deleting APIs is not supported for aggregated API server projects")
+	}
+
+	if err := internal.ValidateResourceGroup(o.groupFlag, c, o.resource); err != nil {
+		return err
+	}
+
+	// The files of resources created with a custom plural are named after it
+	o.resource.Resource = c.KindPlural(o.resource.Group, o.resource.Kind)
+
+	if err := o.resource.Validate(); err != nil {
+		return err
+	}
+
+	if !c.HasResource(o.resource) {
+		return errors.New("API resource does not exist")
+	}
+
+	return nil
+}
+
+func (o *deleteAPIOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	// The files are removed and un-wired at once, so that a failure leaves the project untouched
+	return scaffold.NewStagedScaffolder(c, scaffold.NewDeleteAPIScaffolder(c, o.resource)), nil
+}
+
+func (o *deleteAPIOptions) postScaffold(_ *config.Config) error {
+	if o.runMake {
+		return internal.RunCmd("Running make", "make")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+)
+
+type docsError struct {
+	err error
+}
+
+func (e docsError) Error() string {
+	return fmt.Sprintf("failed to generate API reference: %v", e.err)
+}
+
+func newDocsCmd() *cobra.Command {
+	options := &docsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate the reference documentation of the APIs",
+		Long: `Generate the reference documentation of the APIs tracked in the PROJECT file.
+
+The Go types of every API are read from the api (or apis/<group> for multi-group projects) packages.
+Each Kind gets a page listing its fields with their type, whether they are required, their comments
+and their validation markers, along with an index page linking to every Kind.
+
+The reference is regenerated every time this command is run, so re-run it after changing or adding APIs.
+`,
+		Example: `	# Generate the Markdown reference in docs/api
+	kubebuilder docs
+
+	# Generate the HTML reference in the site directory
+	kubebuilder docs --format html --output-dir site
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(docsError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &docsOptions{}
+
+type docsOptions struct {
+	format    string
+	outputDir string
+}
+
+func (o *docsOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.format, "format", docs.FormatMarkdown,
+		fmt.Sprintf("format of the reference, either %s or %s", docs.FormatMarkdown, docs.FormatHTML))
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", docs.DefaultDir, "directory where the reference is generated")
+}
+
+func (o *docsOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *docsOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("API reference generation is not supported for version %s", c.Version)
+	}
+
+	if o.format != docs.FormatMarkdown && o.format != docs.FormatHTML {
+		return fmt.Errorf("--format must be either %s or %s (was %s)", docs.FormatMarkdown, docs.FormatHTML, o.format)
+	}
+
+	if o.outputDir == "" {
+		return errors.New("--output-dir cannot be empty")
+	}
+
+	return nil
+}
+
+func (o *docsOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewDocsScaffolder(c, o.format, o.outputDir), nil
+}
+
+func (o *docsOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
@@ -23,9 +23,12 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
 )
 
 type editError struct {
@@ -42,12 +45,49 @@ func newEditCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "edit",
 		Short: "This command will edit the project configuration",
-		Long:  `This command will edit the project configuration`,
-		Example: `	# Enable the multigroup layout
+		Long: `This command will edit the project configuration.
+
+Enabling the multigroup layout moves the API packages from api/<version> to apis/<group>/<version>
+and updates their imports in the project.
+
+Enabling a component adds it to config/default/kustomization.yaml. The available components are
+webhook, certmanager (which requires and enables webhook), prometheus, production (a PriorityClass,
+a PodDisruptionBudget, replicas spread across the nodes and larger resources for the manager) and
+webhookca (which generates the webhook certificate with a Job instead of certmanager, and requires and
+enables webhook), networkpolicy and observability (Grafana dashboards and Prometheus alerts for the metrics
+of the manager, which requires and enables prometheus).
+
+Enabling the generation of typed clients adds the +genclient markers to the types of the APIs, next to which
+it scaffolds the register.go file the generated code refers to, as well as hack/update-codegen.sh and the
+generate-clients make target. make generate-clients generates the typed clientset, listers and informers
+of the APIs under pkg/client with k8s.io/code-generator. The APIs created next are marked as well.
+
+Recording the merge bases keeps a copy of the files owned by the user, e.g. the types and the controllers,
+under .kubebuilder/scaffolds as they are scaffolded next. create api --force three-way merges the new
+scaffold of such a file with the changes made to it since.`,
+		Example: `	# Enable the multigroup layout, moving the existing API packages to it
 	kubebuilder edit --multigroup
 
 	# Disable the multigroup layout
-	kubebuilder edit --multigroup=false`,
+	kubebuilder edit --multigroup=false
+
+	# Scaffold a Helm chart to deploy the project
+	kubebuilder edit --deploy=helm
+
+	# Deploy the webhooks with a cert-manager issued certificate and a prometheus ServiceMonitor
+	kubebuilder edit --enable=webhook,certmanager,prometheus
+
+	# Harden the manager Deployment for production clusters
+	kubebuilder edit --enable=production
+
+	# Provision Grafana dashboards and Prometheus alerts for the metrics of the manager
+	kubebuilder edit --enable=observability
+
+	# Generate typed clientsets, listers and informers for the APIs with make generate-clients
+	kubebuilder edit --client-gen
+
+	# Record the files scaffolded next to merge their new scaffold with create api --force
+	kubebuilder edit --merge-bases`,
 		Run: func(_ *cobra.Command, _ []string) {
 			if err := run(options); err != nil {
 				log.Fatal(editError{err})
@@ -64,10 +104,33 @@ var _ commandOptions = &editOptions{}
 
 type editOptions struct {
 	multigroup bool
+	deploy     string
+	components []string
+	clientGen  bool
+
+	// multigroupFlag is used to check if the multigroup flag was provided
+	multigroupFlag *pflag.Flag
+
+	// mergeBases enables or disables recording the scaffolded files owned by the user, if mergeBasesFlag was
+	// provided
+	mergeBases     bool
+	mergeBasesFlag *pflag.Flag
 }
 
 func (o *editOptions) bindFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.multigroup, "multigroup", false, "enable or disable multigroup layout")
+	o.multigroupFlag = cmd.Flags().Lookup("multigroup")
+	cmd.Flags().StringVar(&o.deploy, "deploy", "",
+		fmt.Sprintf("how the project is deployed, one of %q or %q (scaffolds a Helm chart)",
+			modelconfig.DeployKustomize, modelconfig.DeployHelm))
+	cmd.Flags().StringSliceVar(&o.components, "enable", nil,
+		fmt.Sprintf("kustomize components to enable in the default overlay, among %q", scaffoldv2.Components))
+	cmd.Flags().BoolVar(&o.clientGen, "client-gen", false,
+		"if specified, generate typed clientsets, listers and informers for the APIs with k8s.io/code-generator")
+	cmd.Flags().BoolVar(&o.mergeBases, "merge-bases", false,
+		"enable or disable recording the files owned by the user as they are scaffolded, to three-way merge them "+
+			"with create api --force")
+	o.mergeBasesFlag = cmd.Flags().Lookup("merge-bases")
 }
 
 func (o *editOptions) loadConfig() (*config.Config, error) {
@@ -84,15 +147,94 @@ func (o *editOptions) validate(c *config.Config) error {
 		if c.MultiGroup {
 			return fmt.Errorf("multiple group support can't be enabled for version %s", c.Version)
 		}
+		if o.deploy != "" {
+			return fmt.Errorf("deployment method can't be changed for version %s", c.Version)
+		}
+		if len(o.components) != 0 {
+			return fmt.Errorf("--enable is not supported for project version %s", c.Version)
+		}
+		if o.clientGen {
+			return fmt.Errorf("--client-gen is not supported for project version %s", c.Version)
+		}
+		if o.mergeBasesFlag.Changed {
+			return fmt.Errorf("--merge-bases is not supported for project version %s", c.Version)
+		}
+	}
+	if o.mergeBasesFlag.Changed {
+		c.MergeBases = o.mergeBases
+	}
+
+	// The deployment methods and the components are built around the manager
+	if c.APIServer && (o.deploy != "" || len(o.components) != 0) {
+		return errors.New("--deploy and --enable are not supported for aggregated API server projects")
+	}
+
+	switch o.deploy {
+	case "", modelconfig.DeployKustomize, modelconfig.DeployHelm:
+	default:
+		return fmt.Errorf("unknown deployment method %q, must be one of %q or %q",
+			o.deploy, modelconfig.DeployKustomize, modelconfig.DeployHelm)
+	}
+
+	for _, component := range o.components {
+		if !hasComponent(component) {
+			return fmt.Errorf("unknown component %q, must be one of %q", component, scaffoldv2.Components)
+		}
+		if certProvider, found := componentCertProviders[component]; found {
+			if err := scaffold.ValidateCertProvider(&c.Config, certProvider); err != nil {
+				return fmt.Errorf("%s can't be enabled: %v", component, err)
+			}
+		}
+	}
+
+	if len(o.certProviders()) > 1 {
+		return fmt.Errorf("only one of %s and %s can provide the webhook certificate",
+			scaffoldv2.ComponentCertManager, scaffoldv2.ComponentWebhookCA)
 	}
 
 	return nil
 }
 
 func (o *editOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
-	return scaffold.NewEditScaffolder(c, o.multigroup), nil
+	// Keep the current layout unless the flag was provided
+	multigroup := c.MultiGroup
+	if o.multigroupFlag.Changed {
+		multigroup = o.multigroup
+	}
+
+	editScaffolder := scaffold.NewEditScaffolder(c, multigroup, o.deploy, o.components, o.clientGen)
+	if o.deploy == modelconfig.DeployHelm && !c.IsHelm() {
+		return sequentialScaffolder{editScaffolder, scaffold.NewHelmChartScaffolder(c)}, nil
+	}
+	return editScaffolder, nil
 }
 
 func (o *editOptions) postScaffold(_ *config.Config) error {
 	return nil
 }
+
+// componentCertProviders are the certificate providers of the webhook server of the components providing it
+var componentCertProviders = map[string]string{
+	scaffoldv2.ComponentCertManager: modelconfig.CertProviderCertManager,
+	scaffoldv2.ComponentWebhookCA:   modelconfig.CertProviderWebhookCA,
+}
+
+// certProviders returns the components to enable that provide the certificate of the webhook server
+func (o *editOptions) certProviders() []string {
+	providers := make([]string, 0, len(o.components))
+	for _, component := range o.components {
+		if _, found := componentCertProviders[component]; found {
+			providers = append(providers, component)
+		}
+	}
+	return providers
+}
+
+func hasComponent(name string) bool {
+	for _, component := range scaffoldv2.Components {
+		if component == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+func newGenerateCmd() *cobra.Command {
+	options := &generateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate the CRD, RBAC and webhook manifests of the project",
+		Long: fmt.Sprintf(`Generate the CRD, RBAC and webhook manifests of the project with the controller-tools generators.
+
+The manifests are generated from the markers of the Go packages of the project, like the manifests target
+of the Makefile: the CRDs are written in config/crd/bases, the RBAC Role in config/rbac and the webhook
+configurations in config/webhook.
+
+The hashes of the packages and the generated manifests are recorded in %s, and the generation is
+skipped when none of the packages changed since the last one and none of its manifests were removed. Use --force
+to generate the manifests anyway, e.g. after editing them.
+
+The generators are run by kubebuilder itself, controller-gen doesn't need to be installed.
+`, scaffold.GenerateCachePath),
+		Example: `	# Generate the manifests if the Go packages changed
+	kubebuilder generate
+
+	# Generate the manifests even if the packages didn't change
+	kubebuilder generate --force
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(fmt.Errorf("failed to generate the manifests: %v", err))
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &generateOptions{}
+
+type generateOptions struct {
+	force bool
+}
+
+func (o *generateOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.force, "force", false,
+		"if specified, generate the manifests even if the Go packages didn't change since the last generation")
+}
+
+func (o *generateOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *generateOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("manifest generation is not supported for version %s", c.Version)
+	}
+	if c.APIServer {
+		return errors.New("manifest generation is not supported for aggregated API servers")
+	}
+
+	return nil
+}
+
+func (o *generateOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewGenerateScaffolder(c, o.force), nil
+}
+
+func (o *generateOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -31,7 +32,10 @@ import (
 
 	"sigs.k8s.io/kubebuilder/cmd/internal"
 	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 )
 
 type initError struct {
@@ -61,9 +65,73 @@ Writes the following files:
 - a cmd/manager/main.go to run
 
 project will prompt the user to run 'dep ensure' after writing the project files.
+
+If the directory has a go.mod with a module other than the repository, the imports of its packages in the
+existing Go files are rewritten to the repository once confirmed. If the directory is in a subdirectory of a
+module, e.g. of a monorepo, the project shares its go.mod and its packages are imported with the path of the
+subdirectory in the module.
 `,
 		Example: `# Scaffold a project using the apache2 license with "The Kubernetes authors" as owners
 kubebuilder init --domain example.org --license apache2 --owner "The Kubernetes authors"
+
+# Scaffold a project whose files have a header with the copyright and the SPDX license identifier only
+kubebuilder init --domain example.org --license spdx:Apache-2.0 --owner "The Kubernetes authors"
+
+# Scaffold a project whose files have the header of hack/header.txt, which may reference {{ .Owner }} and {{ .Year }}
+kubebuilder init --domain example.org --license hack/header.txt --owner "Example Corp"
+
+# Scaffold a project being prompted for the domain, repository, license and owner
+kubebuilder init --interactive
+
+# Scaffold a project whose manager only watches and is granted permissions in its own namespace
+kubebuilder init --domain example.org --namespace-scoped
+
+# Scaffold a project whose manager pods run with a restricted security context
+kubebuilder init --domain example.org --secure-defaults
+
+# Scaffold a project whose manager image is based on the Red Hat Universal Base Image
+kubebuilder init --domain example.org --base-image ubi
+
+# Scaffold a project whose manager loads its options from a ControllerManagerConfiguration file in a ConfigMap
+kubebuilder init --domain example.org --component-config
+
+# Scaffold a project whose webhook server listens on 10250, e.g. for clusters running the manager on the host network
+kubebuilder init --domain example.org --webhook-port 10250
+
+# Scaffold a project whose manager serves its metrics and webhooks on IPv6 and dual-stack clusters
+kubebuilder init --domain example.org --metrics-bind-address [::]:8080 --webhook-host ::
+
+# Scaffold an aggregated API server keeping its resources in memory instead of a manager reconciling CRDs
+kubebuilder init --domain example.org --apiserver --storage memory
+
+# Scaffold a project in a directory with an existing go.mod, renaming its module and the imports of its packages
+kubebuilder init --domain example.org --repo github.com/example/project --rewrite-imports
+
+# Scaffold a project whose APIs follow the declarative addon pattern
+kubebuilder init --domain example.org --plugins go/v2,declarative/v1
+
+# Scaffold a project with Grafana dashboards and Prometheus alerts for the metrics of the manager
+kubebuilder init --domain example.org --plugins go/v2,observability/v1
+
+# Scaffold a project in the operators/foo directory of a monorepo, sharing the go.mod of its root
+kubebuilder init --domain example.org --project-dir operators/foo
+
+# Adopt an existing Go module, only scaffolding the project files it doesn't have yet
+kubebuilder init --domain example.org --adopt
+
+# Scaffold a project whose controllers split their objects between the shards of a StatefulSet, deployed with
+# make deploy-sharding, for APIs with more objects than a single manager can keep up with
+kubebuilder init --domain example.org --sharding
+
+# Scaffold a project whose controller-runtime, Kubernetes libraries and controller-gen are consistent with each
+# other for Kubernetes 1.18
+kubebuilder init --domain example.org --kubernetes-version 1.18
+
+# Scaffold a project in an air-gapped environment, vendoring its dependencies from the local module cache
+kubebuilder init --domain example.org --vendor --offline
+
+# Same, from a copy of the module download cache of a connected machine ($(go env GOPATH)/pkg/mod/cache/download)
+kubebuilder init --domain example.org --vendor --offline --module-cache /mnt/modcache
 `,
 		Run: func(_ *cobra.Command, _ []string) {
 			if err := run(options); err != nil {
@@ -94,12 +162,37 @@ type initOptions struct {
 	// flags
 	fetchDeps          bool
 	skipGoVersionCheck bool
+	interactive        bool
+	templatesDir       string
+	crdVersionFlag     *flag.Flag
+	rewriteImports     bool
+	rewriteImportsFlag *flag.Flag
+	adopt              bool
+	plugins            []string
+	pluginsFlag        *flag.Flag
+	// pluginChain are the plugins the project is scaffolded with
+	pluginChain scaffold.PluginChain
+
+	// oldModulePath is the module of the existing go.mod whose imports are rewritten to the repository
+	oldModulePath string
+
+	// offline is true if the dependencies are resolved from a module cache instead of the network
+	offline bool
+	// moduleCache is the directory of a module cache with the GOPROXY layout the dependencies are resolved from,
+	// the local module cache if empty
+	moduleCache string
 }
 
 func (o *initOptions) bindFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.skipGoVersionCheck, "skip-go-version-check",
 		false, "if specified, skip checking the Go version")
 
+	cmd.Flags().BoolVar(&o.interactive, "interactive", false,
+		"if specified, prompt for the project values using the flags as defaults")
+	cmd.Flags().StringVar(&o.templatesDir, "templates-dir", "",
+		"directory with templates that replace the built-in ones, "+
+			"looked up as <dir>/<package>/<type>.tmpl (e.g. v2/controller/Controller.tmpl)")
+
 	// dependency args
 	cmd.Flags().BoolVar(&o.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded")
 
@@ -116,8 +209,10 @@ func (o *initOptions) bindFlags(cmd *cobra.Command) {
 	}
 
 	// boilerplate args
-	cmd.Flags().StringVar(&o.license, "license", "apache2",
-		"license to use to boilerplate, may be one of 'apache2', 'none'")
+	cmd.Flags().StringVar(&o.license, "license", project.LicenseApache2,
+		"license to use to boilerplate, may be one of 'apache2', 'none', 'spdx:<identifier>' for a header with the "+
+			"SPDX license identifier only (e.g. spdx:MIT), or the path of a file with a custom header, "+
+			"which may reference {{ .Owner }} and {{ .Year }}")
 	cmd.Flags().StringVar(&o.owner, "owner", "", "owner to add to the copyright")
 
 	// project args
@@ -126,6 +221,64 @@ func (o *initOptions) bindFlags(cmd *cobra.Command) {
 		"defaults to the go package of the current working directory.")
 	cmd.Flags().StringVar(&o.config.Domain, "domain", "my.domain", "domain for groups")
 	cmd.Flags().StringVar(&o.config.Version, "project-version", config.DefaultVersion, "project version")
+	cmd.Flags().BoolVar(&o.config.NamespaceScoped, "namespace-scoped", false,
+		"if specified, the manager watches the namespaces set with WATCH_NAMESPACE (its own by default) "+
+			"and is granted Roles instead of ClusterRoles")
+	cmd.Flags().StringVar(&o.config.CRDVersion, "crd-version", modelconfig.CRDVersionV1,
+		fmt.Sprintf("API version of the generated CustomResourceDefinitions, may be one of '%s', '%s'",
+			modelconfig.CRDVersionV1, modelconfig.CRDVersionV1beta1))
+	o.crdVersionFlag = cmd.Flag("crd-version")
+	cmd.Flags().BoolVar(&o.config.SecureDefaults, "secure-defaults", false,
+		"if specified, run the manager pods as non-root with a read-only root filesystem, "+
+			"the RuntimeDefault seccomp profile and no capabilities")
+	cmd.Flags().BoolVar(&o.config.ComponentConfig, "component-config", false,
+		"if specified, the manager loads its options from a ControllerManagerConfiguration file, set with "+
+			"--config and mounted from a ConfigMap, instead of the other flags")
+	cmd.Flags().IntVar(&o.config.WebhookPort, "webhook-port", 0,
+		fmt.Sprintf("port the webhook server of the manager listens on, %d by default, set in the manifests and "+
+			"as the default of the --webhook-port flag of the manager", modelconfig.DefaultWebhookPort))
+	cmd.Flags().StringVar(&o.config.WebhookHost, "webhook-host", "",
+		"IP address the webhook server of the manager binds to, e.g. :: for all the IPv4 and IPv6 addresses of the "+
+			"pod, all of them by default, set as the default of the --webhook-host flag of the manager")
+	cmd.Flags().StringVar(&o.config.MetricsBindAddress, "metrics-bind-address", "",
+		fmt.Sprintf("address the metrics endpoint of the manager binds to, e.g. [::]:8080 for IPv6 and dual-stack "+
+			"clusters, %s by default, set as the default of the --metrics-bind-address flag of the manager",
+			modelconfig.DefaultMetricsBindAddress))
+	cmd.Flags().BoolVar(&o.rewriteImports, "rewrite-imports", false,
+		"if specified, rewrite the imports of the existing Go files when the module of the existing go.mod "+
+			"differs from the repository, prompted if not set")
+	o.rewriteImportsFlag = cmd.Flag("rewrite-imports")
+	cmd.Flags().BoolVar(&o.adopt, "adopt", false,
+		"if specified, adopt the existing Go module of the directory: only the missing project files are "+
+			"scaffolded and the existing ones, e.g. go.mod, main.go or the Makefile, are kept")
+	cmd.Flags().StringSliceVar(&o.plugins, "plugins", []string{modelconfig.PluginGoV2}, pluginsUsage())
+	o.pluginsFlag = cmd.Flag("plugins")
+	cmd.Flags().BoolVar(&o.config.APIServer, "apiserver", false,
+		"if specified, scaffold an aggregated API server serving the resources from its own storage "+
+			"instead of a manager reconciling CRDs")
+	cmd.Flags().StringVar(&o.config.Storage, "storage", "",
+		fmt.Sprintf("storage of the resources of the aggregated API server, may be one of '%s' (default), "+
+			"'%s' (lost on restart, single replica)", modelconfig.StorageEtcd, modelconfig.StorageMemory))
+	cmd.Flags().StringVar(&o.config.BaseImage, "base-image", "",
+		fmt.Sprintf("base image of the manager image built by the Dockerfile, may be one of '%s' (default), "+
+			"'%s', '%s' (Red Hat Universal Base Image)",
+			modelconfig.BaseImageDistroless, modelconfig.BaseImageScratch, modelconfig.BaseImageUBI))
+	cmd.Flags().StringVar(&o.config.KubernetesVersion, "kubernetes-version", "",
+		fmt.Sprintf("Kubernetes minor version the versions of controller-runtime, the Kubernetes libraries and "+
+			"controller-gen are selected for and pinned, may be one of %s (default %s)",
+			strings.Join(scaffold.KubernetesVersions(), ", "), scaffold.DefaultKubernetesVersion))
+	cmd.Flags().BoolVar(&o.config.Sharding, "sharding", false,
+		"if specified, the controllers only reconcile the objects of the shard of the manager, whose pods are the "+
+			"shards of the StatefulSet of the config/sharding overlay")
+	cmd.Flags().BoolVar(&o.config.Vendor, "vendor", false,
+		"if specified, vendor the dependencies in vendor/, which the builds and the Dockerfile use "+
+			"instead of downloading modules")
+	cmd.Flags().BoolVar(&o.offline, "offline", false,
+		"if specified, resolve the dependencies from the module cache without network access, "+
+			"e.g. in air-gapped environments")
+	cmd.Flags().StringVar(&o.moduleCache, "module-cache", "",
+		"directory of a module download cache the dependencies are resolved from with --offline, "+
+			"e.g. a copy of $(go env GOPATH)/pkg/mod/cache/download, defaults to the local module cache")
 }
 
 func (o *initOptions) loadConfig() (*config.Config, error) {
@@ -145,14 +298,33 @@ func (o *initOptions) validate(c *config.Config) error {
 		}
 	}
 
-	// Check if the project name is a valid namespace according to k8s
-	dir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error to get the current path: %v", err)
+	if err := validateProjectName(); err != nil {
+		return err
 	}
-	projectName := filepath.Base(dir)
-	if err := internal.IsDNS1123Label(strings.ToLower(projectName)); err != nil {
-		return fmt.Errorf("project name (%s) is invalid: %v", projectName, err)
+
+	if o.templatesDir != "" {
+		if err := internal.ValidateTemplatesDir(o.templatesDir); err != nil {
+			return err
+		}
+	}
+
+	if o.interactive {
+		o.prompt(c)
+	}
+
+	if err := project.ValidateLicense(o.license); err != nil {
+		return err
+	}
+	if project.IsCustomLicense(o.license) {
+		if _, err := os.Stat(o.license); err != nil {
+			return fmt.Errorf("error reading the header of the license: %v", err)
+		}
+	}
+
+	if o.adopt {
+		if err := o.validateAdopt(c); err != nil {
+			return err
+		}
 	}
 
 	// Try to guess repository if flag is not set
@@ -164,8 +336,113 @@ func (o *initOptions) validate(c *config.Config) error {
 		c.Repo = repoPath
 	}
 
+	if !c.IsV1() && !o.adopt {
+		if err := o.validateModulePath(c); err != nil {
+			return err
+		}
+	}
+
+	switch c.CRDVersion {
+	case modelconfig.CRDVersionV1, modelconfig.CRDVersionV1beta1:
+	default:
+		return fmt.Errorf("unknown CRD version %q, must be one of %q or %q",
+			c.CRDVersion, modelconfig.CRDVersionV1, modelconfig.CRDVersionV1beta1)
+	}
+
+	switch c.Storage {
+	case "", modelconfig.StorageEtcd, modelconfig.StorageMemory:
+	default:
+		return fmt.Errorf("unknown storage %q, must be one of %q or %q",
+			c.Storage, modelconfig.StorageEtcd, modelconfig.StorageMemory)
+	}
+	switch c.BaseImage {
+	case "", modelconfig.BaseImageDistroless, modelconfig.BaseImageScratch, modelconfig.BaseImageUBI:
+	default:
+		return fmt.Errorf("unknown base image %q, must be one of %q, %q or %q", c.BaseImage,
+			modelconfig.BaseImageDistroless, modelconfig.BaseImageScratch, modelconfig.BaseImageUBI)
+	}
+
+	if c.WebhookPort < 0 || c.WebhookPort > 65535 {
+		return fmt.Errorf("invalid webhook port %d, must be between 1 and 65535", c.WebhookPort)
+	}
+	if c.WebhookHost != "" && net.ParseIP(c.WebhookHost) == nil {
+		return fmt.Errorf("invalid webhook host %q, must be an IP address, e.g. 0.0.0.0 or ::", c.WebhookHost)
+	}
+	if c.MetricsBindAddress != "" {
+		if err := modelconfig.ValidateBindAddress(c.MetricsBindAddress); err != nil {
+			return err
+		}
+	}
+
+	if c.Storage != "" && !c.APIServer {
+		return errors.New("--storage requires --apiserver")
+	}
+	if c.APIServer {
+		// The pods of the aggregated API server are not the ones of the manager
+		if c.NamespaceScoped {
+			return errors.New("--namespace-scoped can't be used with --apiserver")
+		}
+		if c.SecureDefaults {
+			return errors.New("--secure-defaults can't be used with --apiserver")
+		}
+		if c.WebhookPort != 0 {
+			return errors.New("--webhook-port can't be used with --apiserver")
+		}
+		if c.WebhookHost != "" || c.MetricsBindAddress != "" {
+			return errors.New("--webhook-host and --metrics-bind-address can't be used with --apiserver")
+		}
+		if c.ComponentConfig {
+			return errors.New("--component-config can't be used with --apiserver")
+		}
+		if c.Sharding {
+			return errors.New("--sharding can't be used with --apiserver")
+		}
+		if o.pluginsFlag.Changed {
+			return errors.New("--plugins can't be used with --apiserver")
+		}
+	}
+
 	// v1 only checks
 	if c.IsV1() {
+		if c.APIServer {
+			return fmt.Errorf("--apiserver is not supported for project version %s", c.Version)
+		}
+		if c.NamespaceScoped {
+			return fmt.Errorf("--namespace-scoped is not supported for project version %s", c.Version)
+		}
+		if c.SecureDefaults {
+			return fmt.Errorf("--secure-defaults is not supported for project version %s", c.Version)
+		}
+		if c.WebhookPort != 0 {
+			return fmt.Errorf("--webhook-port is not supported for project version %s", c.Version)
+		}
+		if c.WebhookHost != "" || c.MetricsBindAddress != "" {
+			return fmt.Errorf("--webhook-host and --metrics-bind-address are not supported for project version %s",
+				c.Version)
+		}
+		if c.ComponentConfig {
+			return fmt.Errorf("--component-config is not supported for project version %s", c.Version)
+		}
+		if c.BaseImage != "" {
+			return fmt.Errorf("--base-image is not supported for project version %s", c.Version)
+		}
+		if o.crdVersionFlag.Changed {
+			return fmt.Errorf("--crd-version is not supported for project version %s", c.Version)
+		}
+		if o.pluginsFlag.Changed {
+			return fmt.Errorf("--plugins is not supported for project version %s", c.Version)
+		}
+		if c.KubernetesVersion != "" {
+			return fmt.Errorf("--kubernetes-version is not supported for project version %s", c.Version)
+		}
+		if c.Vendor || o.offline {
+			return fmt.Errorf("--vendor and --offline are not supported for project version %s", c.Version)
+		}
+		if c.Sharding {
+			return fmt.Errorf("--sharding is not supported for project version %s", c.Version)
+		}
+		c.CRDVersion = ""
+
 		// v1 is deprecated
 		internal.PrintV1DeprecationWarning()
 
@@ -176,11 +453,161 @@ func (o *initOptions) validate(c *config.Config) error {
 		}
 	}
 
+	if c.IsV2() && !c.APIServer {
+		if err := o.validatePlugins(c); err != nil {
+			return err
+		}
+	}
+
+	// The shards all run the controllers, the ControllerManagerConfiguration file enables the leader election
+	if c.Sharding && c.ComponentConfig {
+		return errors.New("--sharding can't be used with --component-config")
+	}
+
+	if c.KubernetesVersion != "" {
+		c.KubernetesVersion = strings.TrimPrefix(c.KubernetesVersion, "v")
+		if _, err := scaffold.KubernetesDependencyVersions(c.KubernetesVersion); err != nil {
+			return err
+		}
+	}
+
+	if o.moduleCache != "" {
+		if !o.offline {
+			return errors.New("--module-cache requires --offline")
+		}
+		if info, err := os.Stat(o.moduleCache); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid module cache %q, must be a directory", o.moduleCache)
+		}
+	}
+
 	return nil
 }
 
+// validatePlugins resolves the chain of plugins the project is scaffolded with and records it in the project
+func (o *initOptions) validatePlugins(c *config.Config) error {
+	chain, err := projectPlugins.Resolve(o.plugins)
+	if err != nil {
+		return err
+	}
+	if len(chain) == 0 || scaffold.PluginKey(chain[0]) != modelconfig.PluginGoV2 {
+		return fmt.Errorf("the chain of --plugins must start with %s", modelconfig.PluginGoV2)
+	}
+
+	o.pluginChain = chain
+	c.Plugins = chain.Keys()
+	return nil
+}
+
+// validateModulePath checks that the module of an existing go.mod is the repository, asking to rewrite the
+// imports of the existing Go files to the repository otherwise
+func (o *initOptions) validateModulePath(c *config.Config) error {
+	modulePath, err := internal.FindExistingModulePath()
+	if err != nil {
+		return fmt.Errorf("error reading the module of go.mod: %v", err)
+	}
+	if modulePath == "" {
+		return validateSharedModule(c)
+	}
+	if modulePath == c.Repo {
+		return nil
+	}
+
+	if !o.rewriteImportsFlag.Changed {
+		reader := bufio.NewReader(os.Stdin)
+		fmt.Printf("The module of go.mod, %s, differs from the repository %s.\n", modulePath, c.Repo)
+		fmt.Println("Rewrite the module and the imports of the existing Go files [y/n]?")
+		o.rewriteImports = internal.YesNo(reader)
+	}
+	if !o.rewriteImports {
+		return fmt.Errorf("the module of go.mod, %s, differs from the repository %s, "+
+			"set --repo to the module or --rewrite-imports to rewrite it", modulePath, c.Repo)
+	}
+
+	o.oldModulePath = modulePath
+	return nil
+}
+
+// validateSharedModule checks that the repository of a project in a subdirectory of a module, which shares its
+// go.mod, is the path of the subdirectory in the module, so that the packages of the project are imported with it
+func validateSharedModule(c *config.Config) error {
+	modulePath, dir, err := internal.FindSharedModule()
+	if err != nil {
+		return err
+	}
+	if modulePath == "" {
+		return nil
+	}
+	if c.Repo != modulePath+"/"+dir {
+		return fmt.Errorf("the project is in the %s directory of the module %s, whose go.mod is shared, "+
+			"the repository must be %s/%s (was %s)", dir, modulePath, modulePath, dir, c.Repo)
+	}
+
+	c.ModulePath = modulePath
+	return nil
+}
+
+// validateAdopt checks that the directory has a go.mod to adopt, whose module is the repository
+func (o *initOptions) validateAdopt(c *config.Config) error {
+	if c.IsV1() {
+		return fmt.Errorf("--adopt is not supported for project version %s", c.Version)
+	}
+	if o.rewriteImportsFlag.Changed {
+		return errors.New("--rewrite-imports can't be used with --adopt, adopted projects keep their module")
+	}
+
+	modulePath, err := internal.FindExistingModulePath()
+	if err != nil {
+		return fmt.Errorf("error reading the module of go.mod: %v", err)
+	}
+	if modulePath == "" {
+		return errors.New("--adopt requires a go.mod in the current directory")
+	}
+	if c.Repo == "" {
+		c.Repo = modulePath
+	}
+	if c.Repo != modulePath {
+		return fmt.Errorf("the module of go.mod, %s, differs from the repository %s, "+
+			"adopted projects keep their module", modulePath, c.Repo)
+	}
+
+	return nil
+}
+
+// prompt asks the user for the project values, validating them before continuing
+func (o *initOptions) prompt(c *config.Config) {
+	reader := bufio.NewReader(os.Stdin)
+
+	c.Domain = internal.Prompt(reader, "Domain for groups", c.Domain, func(domain string) error {
+		if errs := resource.IsDNS1123Subdomain(domain); errs != nil {
+			return fmt.Errorf("%v", errs)
+		}
+		return nil
+	})
+
+	// Use the guessed repository as default if possible
+	if c.Repo == "" {
+		if repoPath, err := internal.FindCurrentRepo(); err == nil {
+			c.Repo = repoPath
+		}
+	}
+	c.Repo = internal.Prompt(reader, "Go module name of the repository", c.Repo, func(repo string) error {
+		if repo == "" {
+			return errors.New("repository cannot be empty")
+		}
+		return nil
+	})
+
+	o.license = internal.Prompt(reader, "License (apache2, none, spdx:<identifier> or the path of a header file)",
+		o.license, project.ValidateLicense)
+
+	o.owner = internal.Prompt(reader, "Copyright owner", o.owner, nil)
+}
+
 func (o *initOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
-	return scaffold.NewInitScaffolder(c, o.license, o.owner), nil
+	if o.adopt {
+		return scaffold.NewAdoptScaffolder(c, o.license, o.owner, o.pluginChain.InitPlugins(), o.templatesDir), nil
+	}
+	return scaffold.NewInitScaffolder(c, o.license, o.owner, o.pluginChain.InitPlugins(), o.templatesDir), nil
 }
 
 func (o *initOptions) postScaffold(c *config.Config) error {
@@ -202,16 +629,26 @@ func (o *initOptions) postScaffold(c *config.Config) error {
 		}
 
 	case c.IsV2():
-		// Ensure that we are pinning controller-runtime version
-		// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
-		err := internal.RunCmd("Get controller runtime", "go", "get",
-			"sigs.k8s.io/controller-runtime@"+scaffold.ControllerRuntimeVersion)
-		if err != nil {
-			return err
+		if o.offline {
+			if err := o.setOfflineEnv(); err != nil {
+				return err
+			}
 		}
 
-		err = internal.RunCmd("Update go.mod", "go", "mod", "tidy")
-		if err != nil {
+		// go.mod has been scaffolded with the repository as module, the existing packages are imported from it
+		if o.oldModulePath != "" {
+			files, err := internal.RewriteImports(".", o.oldModulePath, c.Repo)
+			if err != nil {
+				return fmt.Errorf("error rewriting the imports of %s: %v", o.oldModulePath, err)
+			}
+			fmt.Printf("Rewrote the imports of %s to %s in %d files\n", o.oldModulePath, c.Repo, len(files))
+		}
+
+		if o.adopt {
+			return o.adoptGoDependencies(c)
+		}
+
+		if err := fetchGoDependencies(c); err != nil {
 			return err
 		}
 
@@ -227,3 +664,105 @@ func (o *initOptions) postScaffold(c *config.Config) error {
 	fmt.Println("Next: define a resource with:\n$ kubebuilder create api")
 	return nil
 }
+
+// validateProjectName checks that the project name, taken from the current directory,
+// is a valid namespace according to k8s
+func validateProjectName() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error to get the current path: %v", err)
+	}
+	projectName := filepath.Base(dir)
+	if err := internal.IsDNS1123Label(strings.ToLower(projectName)); err != nil {
+		return fmt.Errorf("project name (%s) is invalid: %v", projectName, err)
+	}
+
+	return nil
+}
+
+// fetchGoDependencies pins the controller-runtime version and updates go.mod
+// adoptGoDependencies keeps the controller-runtime version already required by the adopted module,
+// and skips running make as the existing Makefile may not have the scaffolded targets
+func (o *initOptions) adoptGoDependencies(c *config.Config) error {
+	version, err := internal.FindRequiredVersion("sigs.k8s.io/controller-runtime")
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		if err := fetchGoDependencies(c); err != nil {
+			return err
+		}
+	} else {
+		versions, err := scaffold.KubernetesDependencyVersions(c.KubernetesVersion)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Keeping the required sigs.k8s.io/controller-runtime %s, the scaffolded code expects %s\n",
+			version, versions.ControllerRuntime)
+		if err := internal.RunCmd("Update go.mod", "go", "mod", "tidy"); err != nil {
+			return err
+		}
+		if err := vendorGoDependencies(c); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Next: review the kept files, then define a resource with:\n$ kubebuilder create api")
+	return nil
+}
+
+func fetchGoDependencies(c *config.Config) error {
+	versions, err := scaffold.KubernetesDependencyVersions(c.KubernetesVersion)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that we are pinning controller-runtime version
+	// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
+	err = internal.RunCmd("Get controller runtime", "go", "get",
+		"sigs.k8s.io/controller-runtime@"+versions.ControllerRuntime)
+	if err != nil {
+		return err
+	}
+
+	// k8s.io/apiserver has to match the Kubernetes libraries required by controller-runtime
+	if c.APIServer {
+		err := internal.RunCmd("Get apiserver", "go", "get", "k8s.io/apiserver@"+versions.Kubernetes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := internal.RunCmd("Update go.mod", "go", "mod", "tidy"); err != nil {
+		return err
+	}
+
+	return vendorGoDependencies(c)
+}
+
+// vendorGoDependencies updates vendor/ with the dependencies of go.mod if they are vendored
+func vendorGoDependencies(c *config.Config) error {
+	if !c.Vendor {
+		return nil
+	}
+	return internal.RunCmd("Vendor dependencies", "go", "mod", "vendor")
+}
+
+// setOfflineEnv makes the go commands run by init and make resolve the modules from the module cache only, the
+// checksums of go.sum are not looked up in the checksum database either
+func (o *initOptions) setOfflineEnv() error {
+	proxy := "off"
+	if o.moduleCache != "" {
+		dir, err := filepath.Abs(o.moduleCache)
+		if err != nil {
+			return err
+		}
+		proxy = "file://" + filepath.ToSlash(dir)
+	}
+
+	fmt.Printf("Resolving the dependencies offline with GOPROXY=%s\n", proxy)
+	if err := os.Setenv("GOPROXY", proxy); err != nil {
+		return err
+	}
+	return os.Setenv("GOSUMDB", "off")
+}
@@ -36,6 +36,14 @@ func ConfiguredAndV1() bool {
 		return false
 	}
 
+	// Commands report the errors in the configuration file themselves when they load it
+	switch err.(type) {
+	case config.UnknownFieldsError:
+		return projectConfig.IsV1()
+	case config.UnsupportedSchemaError:
+		return false
+	}
+
 	if err != nil {
 		log.Fatalf("failed to read the configuration file: %v", err)
 	}
@@ -45,5 +53,6 @@ func ConfiguredAndV1() bool {
 
 func PrintV1DeprecationWarning() {
 	fmt.Printf(noticeColor, "[Deprecation Notice] The v1 projects are deprecated and will not be supported beyond "+
-		"Feb 1, 2020.\nSee how to upgrade your project to v2: https://book.kubebuilder.io/migration/guide.html\n")
+		"Feb 1, 2020.\nRun `kubebuilder migrate` to upgrade your project to v2, see more details at: "+
+		"https://book.kubebuilder.io/migration/guide.html\n")
 }
@@ -18,15 +18,21 @@ package internal
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 )
 
 func RunCmd(msg, cmd string, args ...string) error {
+	return RunCmdTo(os.Stdout, msg, cmd, args...)
+}
+
+// RunCmdTo runs the command like RunCmd, printing the message and the output of the command to out
+func RunCmdTo(out io.Writer, msg, cmd string, args ...string) error {
 	c := exec.Command(cmd, args...) // #nolint:gosec
-	c.Stdout = os.Stdout
+	c.Stdout = out
 	c.Stderr = os.Stderr
-	fmt.Println(msg + ":\n$ " + strings.Join(c.Args, " "))
+	fmt.Fprintln(out, msg+":\n$ "+strings.Join(c.Args, " "))
 	return c.Run()
 }
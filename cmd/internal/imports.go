@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RewriteImports replaces the module oldPath by newPath in the imports of the Go files under root, skipping the
+// directories ignored by the go tool, and returns the paths of the files that were rewritten.
+func RewriteImports(root, oldPath, newPath string) ([]string, error) {
+	var rewritten []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		changed, err := rewriteFileImports(path, info.Mode(), oldPath, newPath)
+		if err != nil {
+			return err
+		}
+		if changed {
+			rewritten = append(rewritten, path)
+		}
+		return nil
+	})
+
+	return rewritten, err
+}
+
+// rewriteFileImports replaces the module oldPath by newPath in the imports of a Go file,
+// returning whether the file was changed
+func rewriteFileImports(path string, mode os.FileMode, oldPath, newPath string) (bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+
+	changed := false
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return false, fmt.Errorf("unable to parse %s: %v", path, err)
+		}
+		// Only the packages of the module are rewritten, not the ones of modules sharing a prefix
+		if importPath == oldPath || strings.HasPrefix(importPath, oldPath+"/") {
+			imp.Path.Value = strconv.Quote(newPath + strings.TrimPrefix(importPath, oldPath))
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	// The rewritten imports may no longer be sorted within their group
+	ast.SortImports(fset, file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return false, fmt.Errorf("unable to format %s: %v", path, err)
+	}
+
+	return true, ioutil.WriteFile(path, buf.Bytes(), mode)
+}
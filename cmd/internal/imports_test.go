@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewriteImports(t *testing.T) {
+	root, err := ioutil.TempDir("", "rewrite-imports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"old.io/project/pkg/util"
+	"old.io/projectx/other"
+	"example.com/lib"
+)
+
+func main() { fmt.Println(util.Hello(), other.X, lib.Y) }
+`,
+		"pkg/util/util.go": `package util
+
+import "old.io/project"
+
+var _ = project.Version
+`,
+		"vendor/old.io/project/project.go": `package project
+
+import _ "old.io/project/internal"
+`,
+	}
+	for path, content := range files {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rewritten, err := RewriteImports(root, "old.io/project", "example.com/project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rewritten) != 2 {
+		t.Errorf("expected 2 rewritten files, got %v", rewritten)
+	}
+
+	expected := map[string]string{
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/lib"
+	"example.com/project/pkg/util"
+	"old.io/projectx/other"
+)
+
+func main() { fmt.Println(util.Hello(), other.X, lib.Y) }
+`,
+		"pkg/util/util.go": `package util
+
+import "example.com/project"
+
+var _ = project.Version
+`,
+		// The vendored packages are not rewritten
+		"vendor/old.io/project/project.go": files["vendor/old.io/project/project.go"],
+	}
+	for path, content := range expected {
+		actual, err := ioutil.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != content {
+			t.Errorf("unexpected content of %s:\n%s", path, actual)
+		}
+	}
+}
@@ -21,20 +21,27 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
-// module and goMod arg just enough of the output of `go mod edit -json` for our purposes
+// module, require and goMod are just enough of the output of `go mod edit -json` for our purposes
 type goMod struct {
-	Module module
+	Module  module
+	Require []require
 }
 type module struct {
 	Path string
 }
+type require struct {
+	Path    string
+	Version string
+}
 
-// findGoModulePath finds the path of the current module, if present.
-func findGoModulePath(forceModules bool) (string, error) {
+// readGoMod reads the go.mod file of the current module, if present.
+func readGoMod(forceModules bool) (*goMod, error) {
 	cmd := exec.Command("go", "mod", "edit", "-json")
 	cmd.Env = append(cmd.Env, os.Environ()...)
 	if forceModules {
@@ -45,21 +52,97 @@ func findGoModulePath(forceModules bool) (string, error) {
 		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
 			err = fmt.Errorf("%s", string(exitErr.Stderr))
 		}
-		return "", err
+		return nil, err
+	}
+	mod := &goMod{}
+	if err := json.Unmarshal(out, mod); err != nil {
+		return nil, err
 	}
-	mod := goMod{}
-	if err := json.Unmarshal(out, &mod); err != nil {
+	return mod, nil
+}
+
+// findGoModulePath finds the path of the current module, if present.
+func findGoModulePath(forceModules bool) (string, error) {
+	mod, err := readGoMod(forceModules)
+	if err != nil {
 		return "", err
 	}
 	return mod.Module.Path, nil
 }
 
+// FindRequiredVersion returns the version of the module path required by the go.mod file of the current
+// directory, or an empty string if it is not required.
+func FindRequiredVersion(path string) (string, error) {
+	mod, err := readGoMod(true)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range mod.Require {
+		if r.Path == path {
+			return r.Version, nil
+		}
+	}
+	return "", nil
+}
+
+// FindExistingModulePath returns the path of the module declared by the go.mod file of the current directory,
+// or an empty string if there is none.
+func FindExistingModulePath() (string, error) {
+	if _, err := os.Stat("go.mod"); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return findGoModulePath(true)
+}
+
+// FindSharedModule returns the path of the module of the go.mod in a parent directory, shared with the rest of a
+// monorepo, and the directory of the current one in the module, e.g. operators/foo. It returns empty strings if the
+// go.mod is in the current directory or there is none.
+func FindSharedModule() (string, string, error) {
+	cmd := exec.Command("go", "env", "GOMOD")
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("error finding go.mod: %v", err)
+	}
+	goModPath := strings.TrimSpace(string(out))
+	// The path is empty or os.DevNull outside of a module
+	if goModPath == "" || goModPath == os.DevNull {
+		return "", "", nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	dir, err := filepath.Rel(filepath.Dir(goModPath), wd)
+	if err != nil {
+		return "", "", err
+	}
+	if dir == "." {
+		return "", "", nil
+	}
+
+	modulePath, err := findGoModulePath(false)
+	if err != nil {
+		return "", "", err
+	}
+	return modulePath, filepath.ToSlash(dir), nil
+}
+
 // FindCurrentRepo attempts to determine the current repository
 // though a combination of go/packages and `go mod` commands/tricks.
 func FindCurrentRepo() (string, error) {
 	// easiest case: existing go module
 	path, err := findGoModulePath(false)
 	if err == nil {
+		// The packages of a subdirectory of the module are imported with its path
+		if modulePath, dir, err := FindSharedModule(); err == nil && modulePath != "" {
+			return modulePath + "/" + dir, nil
+		}
 		return path, nil
 	}
 
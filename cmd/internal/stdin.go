@@ -39,6 +39,33 @@ func YesNo(reader *bufio.Reader) bool {
 	}
 }
 
+// Prompt prints the provided message and reads a value from stdin, returning
+// defaultValue if the answer is empty. The question is repeated until the value
+// is accepted by validate, if provided.
+func Prompt(reader *bufio.Reader, msg, defaultValue string, validate func(string) error) string {
+	for {
+		if defaultValue != "" {
+			fmt.Printf("%s [%s]: ", msg, defaultValue)
+		} else {
+			fmt.Printf("%s: ", msg)
+		}
+
+		text := readstdin(reader)
+		if text == "" {
+			text = defaultValue
+		}
+
+		if validate != nil {
+			if err := validate(text); err != nil {
+				fmt.Printf("invalid input %q: %v\n", text, err)
+				continue
+			}
+		}
+
+		return text
+	}
+}
+
 // Readstdin reads a line from stdin trimming spaces, and returns the value.
 // log.Fatal's if there is an error.
 func readstdin(reader *bufio.Reader) string {
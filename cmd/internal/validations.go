@@ -17,8 +17,14 @@ limitations under the License.
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 )
 
 // The following code came from "k8s.io/apimachinery/pkg/util/validation/validation.go"
@@ -65,3 +71,26 @@ func regexError(msg string, fmt string, examples ...string) string {
 func maxLenError(length int) string {
 	return fmt.Sprintf("must be no more than %d characters", length)
 }
+
+// ---------------------------------------
+
+// ValidateResourceGroup normalizes the group of the resource, e.g. my-app for My-App.example.com in the example.com
+// domain, and checks that it is only empty when --group="" was set explicitly, which serves the API in the API group
+// named after the domain. The resource is imported as the package recorded for its group, if it was overridden.
+func ValidateResourceGroup(groupFlag *flag.Flag, c *config.Config, r *resource.Resource) error {
+	r.Group = resource.NormalizeGroup(r.Group, c.Domain)
+	if err := c.SetGroupPackage(r); err != nil {
+		return err
+	}
+
+	if r.Group != "" {
+		return nil
+	}
+	if groupFlag == nil || !groupFlag.Changed {
+		return errors.New(`group cannot be empty, set --group="" to serve the API in the API group named after the domain`)
+	}
+	if c.IsV1() {
+		return fmt.Errorf("APIs without a group are not supported for project version %s", c.Version)
+	}
+	return nil
+}
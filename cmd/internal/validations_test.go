@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestValidateResourceGroup(t *testing.T) {
+	tests := []struct {
+		group    string
+		changed  bool
+		version  string
+		expected string
+		err      bool
+	}{
+		{group: "ship", changed: true, expected: "ship"},
+		{group: "My-App.Example.com", changed: true, expected: "my-app"},
+		{group: "", changed: true, expected: ""},
+		{group: "", changed: false, err: true},
+		{group: "", changed: true, version: modelconfig.Version1, err: true},
+	}
+
+	for _, test := range tests {
+		c := config.New(config.DefaultPath)
+		c.Domain = "example.com"
+		if test.version != "" {
+			c.Version = test.version
+		}
+		groupFlag := &flag.Flag{Name: "group", Changed: test.changed}
+		r := &resource.Resource{Group: test.group, Version: "v1", Kind: "Frigate"}
+
+		err := ValidateResourceGroup(groupFlag, c, r)
+		switch {
+		case test.err && err == nil:
+			t.Errorf("%q: expected an error", test.group)
+		case !test.err && err != nil:
+			t.Errorf("%q: unexpected error: %v", test.group, err)
+		case !test.err && r.Group != test.expected:
+			t.Errorf("%q: expected group %q, got %q", test.group, test.expected, r.Group)
+		}
+	}
+}
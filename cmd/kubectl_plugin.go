@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/internal"
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/kubectlplugin"
+)
+
+type kubectlPluginError struct {
+	err error
+}
+
+func (e kubectlPluginError) Error() string {
+	return fmt.Sprintf("failed to create kubectl plugin: %v", e.err)
+}
+
+func newKubectlPluginCmd() *cobra.Command {
+	options := &kubectlPluginOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "kubectl-plugin",
+		Short: "Scaffold a kubectl plugin reading the objects of the project APIs",
+		Long: `Scaffold a kubectl plugin, run as kubectl <name>, whose get and describe commands read the objects of the
+tracked APIs into their Go types:
+- cmd/kubectl-<name>/main.go defines the commands, it is only scaffolded once and can be extended.
+- cmd/kubectl-<name>/kinds.go registers the APIs in the scheme of the plugin, it is regenerated every time this
+  command is run.
+
+A kubectl-plugin target building bin/kubectl-<name> is added to the Makefile.
+Dashes in the name are replaced by underscores in the binary name, kubectl maps them back.
+`,
+		Example: `	# Scaffold the plugin named after the project directory, e.g. kubectl memcached-operator
+	kubebuilder create kubectl-plugin
+
+	# Scaffold the plugin run as kubectl fleet, then list the Frigates of all the namespaces
+	kubebuilder create kubectl-plugin --name fleet
+	make kubectl-plugin
+	PATH=$PATH:$(pwd)/bin kubectl fleet get frigates -A
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(kubectlPluginError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &kubectlPluginOptions{}
+
+type kubectlPluginOptions struct {
+	name string
+}
+
+func (o *kubectlPluginOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.name, "name", "",
+		"name of the plugin, run as kubectl <name>, defaults to the project directory name")
+}
+
+func (o *kubectlPluginOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *kubectlPluginOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("kubectl plugins are not supported for version %s", c.Version)
+	}
+
+	// use directory name as plugin name, like the kustomize prefix
+	if o.name == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		o.name = strings.ToLower(filepath.Base(dir))
+	}
+
+	return kubectlplugin.ValidateName(o.name)
+}
+
+func (o *kubectlPluginOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewKubectlPluginScaffolder(c, o.name), nil
+}
+
+func (o *kubectlPluginOptions) postScaffold(c *config.Config) error {
+	// The commands of the plugin are built with cobra, which controller-runtime doesn't require
+	if err := internal.RunCmd("Get cobra", "go", "get", "github.com/spf13/cobra@"+scaffold.CobraVersion); err != nil {
+		return err
+	}
+	return vendorGoDependencies(c)
+}
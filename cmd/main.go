@@ -18,6 +18,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -91,6 +92,18 @@ func buildCmdTree() *cobra.Command {
 	if internal.ConfiguredAndV1() {
 		alphaCmd.AddCommand(newWebhookCmd())
 	}
+	// kubebuilder alpha scaffold, verify, update, universe and generate-from-crd (v2 only)
+	if !internal.ConfiguredAndV1() {
+		alphaCmd.AddCommand(newProjectSpecCmd())
+		// kubebuilder alpha verify
+		alphaCmd.AddCommand(newVerifyCmd())
+		// kubebuilder alpha update
+		alphaCmd.AddCommand(newTemplateUpdateCmd())
+		// kubebuilder alpha universe
+		alphaCmd.AddCommand(newUniverseCmd())
+		// kubebuilder alpha generate-from-crd
+		alphaCmd.AddCommand(newGenerateFromCRDCmd())
+	}
 	// Only add alpha group if it has subcommands
 	if alphaCmd.HasSubCommands() {
 		rootCmd.AddCommand(alphaCmd)
@@ -103,18 +116,59 @@ func buildCmdTree() *cobra.Command {
 	// kubebuilder create webhook (v2 only)
 	if !internal.ConfiguredAndV1() {
 		createCmd.AddCommand(newWebhookV2Cmd())
+		// kubebuilder create resource and controller
+		createCmd.AddCommand(newResourceCmd())
+		createCmd.AddCommand(newControllerCmd())
+		// kubebuilder create bundle
+		createCmd.AddCommand(newBundleCmd())
+		// kubebuilder create policy
+		createCmd.AddCommand(newPolicyCmd())
+		// kubebuilder create kubectl-plugin
+		createCmd.AddCommand(newKubectlPluginCmd())
 	}
 	// Only add create group if it has subcommands
 	if createCmd.HasSubCommands() {
 		rootCmd.AddCommand(createCmd)
 	}
 
+	// kubebuilder completion
+	rootCmd.AddCommand(newCompletionCmd())
+
+	// kubebuilder config
+	configCmd := newConfigCmd()
+	// kubebuilder config upgrade
+	configCmd.AddCommand(newConfigUpgradeCmd())
+	rootCmd.AddCommand(configCmd)
+
+	// kubebuilder delete (v2 only)
+	if !internal.ConfiguredAndV1() {
+		deleteCmd := newDeleteCmd()
+		// kubebuilder delete api
+		deleteCmd.AddCommand(newDeleteAPICmd())
+		rootCmd.AddCommand(deleteCmd)
+	}
+
+	// kubebuilder docs (v2 only)
+	if !internal.ConfiguredAndV1() {
+		rootCmd.AddCommand(newDocsCmd())
+	}
+
 	// kubebuilder edit
 	rootCmd.AddCommand(newEditCmd())
 
+	// kubebuilder generate (v2 only)
+	if !internal.ConfiguredAndV1() {
+		rootCmd.AddCommand(newGenerateCmd())
+	}
+
 	// kubebuilder init
 	rootCmd.AddCommand(newInitCmd())
 
+	// kubebuilder migrate (v1 only)
+	if internal.ConfiguredAndV1() {
+		rootCmd.AddCommand(newMigrateCmd())
+	}
+
 	// kubebuilder update (v1 only)
 	if internal.ConfiguredAndV1() {
 		rootCmd.AddCommand(newUpdateCmd())
@@ -127,6 +181,13 @@ func buildCmdTree() *cobra.Command {
 }
 
 func main() {
+	// The scaffolded files are recorded with the version of kubebuilder that scaffolded them
+	scaffold.KubebuilderVersion = version.KubeBuilderVersion()
+
+	if err := changeProjectDir(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+
 	if err := buildCmdTree().Execute(); err != nil {
 		log.Fatal(err)
 	}
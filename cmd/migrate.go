@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/internal"
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type migrateError struct {
+	err error
+}
+
+func (e migrateError) Error() string {
+	return fmt.Sprintf("failed to migrate project: %v", e.err)
+}
+
+func newMigrateCmd() *cobra.Command {
+	options := &migrateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate a v1 project to the v2 layout",
+		Long: `Migrate a v1 project to the v2 layout.
+
+The v2 project structure (main.go, api/, controllers/, config/, Makefile, Dockerfile and go.mod) is scaffolded
+for the APIs found under pkg/apis. The type definitions are moved to their v2 packages and the imports of the
+moved packages are rewritten in every Go file of the project.
+
+The v1 files that are replaced by the v2 layout are moved to the backup directory, as the reconcile logic of the
+controllers and the webhooks need to be ported by hand.
+`,
+		Example: `	# Migrate the project keeping the v1 files in _v1-backup/
+	kubebuilder migrate
+
+	# Migrate the project keeping the v1 files in a different directory
+	kubebuilder migrate --backup-dir ../project-v1
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(migrateError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &migrateOptions{}
+
+type migrateOptions struct {
+	// backupDir is the directory where the replaced v1 files are moved to
+	backupDir string
+
+	// runMake indicates whether to run make or not after migrating
+	runMake bool
+
+	skipGoVersionCheck bool
+}
+
+func (o *migrateOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.backupDir, "backup-dir", "_v1-backup",
+		"directory where the v1 files that are replaced by the v2 layout are moved to, "+
+			"it must start with '_' or '.' if it is inside the project so that go ignores it")
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after migrating")
+	cmd.Flags().BoolVar(&o.skipGoVersionCheck, "skip-go-version-check",
+		false, "if specified, skip checking the Go version")
+}
+
+func (o *migrateOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *migrateOptions) validate(c *config.Config) error {
+	if !c.IsV1() {
+		return fmt.Errorf("only v1 projects can be migrated, this project is %s", c.Version)
+	}
+
+	// v2 projects require go modules
+	if !o.skipGoVersionCheck {
+		if err := internal.ValidateGoVersion(); err != nil {
+			return err
+		}
+	}
+
+	o.backupDir = filepath.Clean(o.backupDir)
+	// Go files in the backup directory would be built as part of the project unless go ignores the directory
+	if !filepath.IsAbs(o.backupDir) && !strings.HasPrefix(o.backupDir, "..") {
+		if first := strings.Split(filepath.ToSlash(o.backupDir), "/")[0]; !strings.HasPrefix(first, "_") &&
+			!strings.HasPrefix(first, ".") {
+			return fmt.Errorf("backup directory %s is inside the project and must start with '_' or '.'", o.backupDir)
+		}
+	}
+	if _, err := os.Stat(o.backupDir); err == nil {
+		return fmt.Errorf("backup directory %s already exists", o.backupDir)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (o *migrateOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewMigrateScaffolder(c, o.backupDir), nil
+}
+
+func (o *migrateOptions) postScaffold(c *config.Config) error {
+	if err := fetchGoDependencies(c); err != nil {
+		return err
+	}
+
+	if o.runMake {
+		return internal.RunCmd("Running make", "make")
+	}
+
+	return nil
+}
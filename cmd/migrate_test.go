@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+func TestMigrateOptionsValidate(t *testing.T) {
+	existing, err := ioutil.TempDir("", "kubebuilder-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(existing)
+
+	tests := []struct {
+		version string
+		args    []string
+		err     string
+	}{
+		{version: modelconfig.Version1},
+		{version: modelconfig.Version1, args: []string{"--backup-dir", ".v1"}},
+		{version: modelconfig.Version1, args: []string{"--backup-dir", "../project-v1"}},
+		{version: modelconfig.Version1, args: []string{"--backup-dir", "backup"},
+			err: "must start with '_' or '.'"},
+		{version: modelconfig.Version1, args: []string{"--backup-dir", "_v1-backup/../backup"},
+			err: "must start with '_' or '.'"},
+		{version: modelconfig.Version1, args: []string{"--backup-dir", existing},
+			err: "already exists"},
+		{version: modelconfig.Version2, err: "only v1 projects can be migrated"},
+	}
+
+	for _, test := range tests {
+		options := &migrateOptions{}
+		parseFlags(t, options, append([]string{"--skip-go-version-check"}, test.args...)...)
+		c := newTestConfig()
+		c.Version = test.version
+
+		err := options.validate(c)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%v: unexpected error: %v", test.args, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%v: expected an error containing %q, got %v", test.args, test.err, err)
+		}
+	}
+}
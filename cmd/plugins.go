@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/plugins/addon"
+)
+
+// projectPlugins are the versioned plugins that v2 projects can be scaffolded with
+var projectPlugins = scaffold.ProjectPlugins{
+	scaffold.GoPlugin{},
+	addon.DeclarativePlugin{},
+	scaffold.ObservabilityPlugin{},
+}
+
+// projectPluginChain returns the chain of plugins recorded in the project, which this binary has to support
+func projectPluginChain(c *config.Config) (scaffold.PluginChain, error) {
+	chain, err := projectPlugins.Resolve(c.PluginKeys())
+	if err != nil {
+		return nil, fmt.Errorf("the project is scaffolded with the plugins %s, not supported by this version of "+
+			"kubebuilder: %v", strings.Join(c.PluginKeys(), ", "), err)
+	}
+	return chain, nil
+}
+
+// pluginsUsage is the help of the --plugins flag, listing the plugins that can be selected
+func pluginsUsage() string {
+	usage := "comma-separated chain of the plugins the project is scaffolded with, recorded in the PROJECT file, " +
+		"starting with the Go plugin, one of:"
+	for _, plugin := range projectPlugins {
+		usage += fmt.Sprintf("\n  %s: %s", scaffold.PluginKey(plugin), plugin.Description())
+	}
+	return usage
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/cmd/internal"
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+type policyError struct {
+	err error
+}
+
+func (e policyError) Error() string {
+	return fmt.Sprintf("failed to create policy: %v", e.err)
+}
+
+func newPolicyCmd() *cobra.Command {
+	options := &policyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Scaffold a ValidatingAdmissionPolicy for an API resource",
+		Long: `Scaffold a ValidatingAdmissionPolicy and its binding for an API resource, validating its objects
+in the API server with CEL instead of a validating webhook, without running a webhook server.
+
+The CEL rules of the policy are derived from the validation markers of the spec of the Kind, e.g.
+Enum, Pattern, Minimum, MaxLength or XValidation, and can be extended with the rules that the CRD
+schema can't express. The policies are written under config/policy and deployed by the policy
+component of config/default.
+
+ValidatingAdmissionPolicies require Kubernetes 1.30 or newer.
+`,
+		Example: `	# Create the policy of the Frigate Kind of group ship, version v1beta1
+	kubebuilder create policy --group ship --version v1beta1 --kind Frigate
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(policyError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &policyOptions{}
+
+type policyOptions struct {
+	resource  *resource.Resource
+	groupFlag *flag.Flag
+}
+
+func (o *policyOptions) bindFlags(cmd *cobra.Command) {
+	o.resource = &resource.Resource{}
+	bindResourceFlags(cmd, o.resource)
+	o.groupFlag = cmd.Flag("group")
+}
+
+func (o *policyOptions) loadConfig() (*config.Config, error) {
+	projectConfig, err := config.Load()
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+
+	return projectConfig, err
+}
+
+func (o *policyOptions) validate(c *config.Config) error {
+	if !c.IsV2() {
+		return fmt.Errorf("admission policies are not supported for version %s", c.Version)
+	}
+	if c.APIServer {
+		return errors.New("admission policies can't be created in aggregated API server projects")
+	}
+
+	if err := internal.ValidateResourceGroup(o.groupFlag, c, o.resource); err != nil {
+		return err
+	}
+
+	o.resource.Resource = c.KindPlural(o.resource.Group, o.resource.Kind)
+	if err := o.resource.Validate(); err != nil {
+		return err
+	}
+
+	// The rules are derived from the Go types of the resource, which have to be in the project
+	if !c.HasResource(o.resource) {
+		return fmt.Errorf("%s/%s, Kind=%s is not a resource of the project, create it with kubebuilder create api",
+			o.resource.Group, o.resource.Version, o.resource.Kind)
+	}
+
+	return nil
+}
+
+func (o *policyOptions) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
+	return scaffold.NewPolicyScaffolder(c, o.resource), nil
+}
+
+func (o *policyOptions) postScaffold(_ *config.Config) error {
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+type resourceError struct {
+	err error
+}
+
+func (e resourceError) Error() string {
+	return fmt.Sprintf("failed to create resource: %v", e.err)
+}
+
+func newResourceCmd() *cobra.Command {
+	options := &resourceOptions{apiOptions{doResource: true}}
+
+	cmd := &cobra.Command{
+		Use:   "resource",
+		Short: "Scaffold the resource of a Kubernetes API",
+		Long: `Scaffold the resource of a Kubernetes API: its types, CRD, sample and RBAC roles, and optionally its
+defaulting and validating webhooks, without a controller.
+
+Unlike kubebuilder create api, it doesn't prompt whether to scaffold the resource and the controller.
+Scaffold the controller of the resource with kubebuilder create controller.
+
+After the scaffold is written, resource will run make on the project.
+`,
+		Example: `	# Create the frigates resource with Group: ship, Version: v1beta1 and Kind: Frigate
+	kubebuilder create resource --group ship --version v1beta1 --kind Frigate
+
+	# Create a resource with conditions and its defaulting and validating webhooks
+	kubebuilder create resource --group ship --version v1beta1 --kind Frigate --conditions --defaulting --validation
+
+	# Create the controller of the previous resource
+	kubebuilder create controller --group ship --version v1beta1 --kind Frigate
+`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(options); err != nil {
+				log.Fatal(resourceError{err})
+			}
+		},
+	}
+
+	options.bindFlags(cmd)
+
+	return cmd
+}
+
+var _ commandOptions = &resourceOptions{}
+
+// resourceOptions are the options of an API that only scaffold its resource
+type resourceOptions struct {
+	apiOptions
+}
+
+func (o *resourceOptions) bindFlags(cmd *cobra.Command) {
+	o.bindCommonFlags(cmd)
+	o.bindTypeFlags(cmd)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+// expectFiles checks whether each of the files of the project exists or not
+func expectFiles(t *testing.T, fs afero.Fs, files map[string]bool) {
+	for path, expected := range files {
+		if exists, err := afero.Exists(fs, path); err != nil {
+			t.Fatal(err)
+		} else if exists != expected {
+			t.Errorf("%s: expected it to exist: %v", path, expected)
+		}
+	}
+}
+
+func TestResourceOptionsFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	(&resourceOptions{}).bindFlags(cmd)
+
+	for _, name := range []string{"group", "version", "kind", "conditions", "defaulting", "validation"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected the --%s flag", name)
+		}
+	}
+	// The resource is created without prompting and the controller flags belong to create controller
+	for _, name := range []string{"resource", "controller", "owns", "external-api-path", "example"} {
+		if cmd.Flags().Lookup(name) != nil {
+			t.Errorf("unexpected --%s flag", name)
+		}
+	}
+}
+
+func TestResourceOptionsScaffold(t *testing.T) {
+	c := newTestConfig()
+	if err := scaffold.NewInitScaffolder(c, "none", "", nil, "").Scaffold(); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &resourceOptions{apiOptions{doResource: true}}
+	parseFlags(t, options, "--group", "ship", "--version", "v1", "--kind", "Frigate", "--conditions", "--defaulting")
+	if err := options.validate(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !options.doResource || options.doController {
+		t.Fatalf("expected only the resource to be created, got resource=%v controller=%v",
+			options.doResource, options.doController)
+	}
+
+	for _, scaffolder := range options.apiScaffolders(c, nil, nil, nil) {
+		if err := scaffolder.Scaffold(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expectFiles(t, c.Fs(), map[string]bool{
+		filepath.Join("api", "v1", "frigate_types.go"):        true,
+		filepath.Join("api", "v1", "frigate_webhook.go"):      true,
+		filepath.Join("controllers", "frigate_controller.go"): false,
+	})
+}
@@ -17,11 +17,18 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
+// projectDirFlag is the flag of the directory of the project, the commands run as if they were started in it
+const projectDirFlag = "project-dir"
+
 func newRootCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "kubebuilder",
 		Short: "Development kit for building Kubernetes extensions and tools.",
 		Long: `
@@ -45,6 +52,50 @@ scaffold a Controller for an existing Resource, select "n" for Resource. To only
 the schema for a Resource without writing a Controller, select "n" for Controller.
 
 After the scaffold is written, api will run make on the project.
+
+The project can be in a subdirectory of a monorepo, set --project-dir to run the commands from its root:
+
+  kubebuilder create api --project-dir operators/foo --group <group> --version <version> --kind <Kind>
 `,
 	}
+
+	// The flag is applied by changeProjectDir before the command tree is built
+	cmd.PersistentFlags().String(projectDirFlag, "",
+		"directory of the project, e.g. operators/foo of a monorepo, created by init if it doesn't exist")
+
+	return cmd
+}
+
+// changeProjectDir changes the working directory to the one of the --project-dir flag, if set, so that the
+// PROJECT file, main.go and the other files are read and written in it. It is done before the command tree is
+// built, as the available commands depend on the configuration of the project.
+func changeProjectDir(args []string) error {
+	dir, command := "", ""
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--":
+			i = len(args)
+		case arg == "--"+projectDirFlag && i+1 < len(args):
+			i++
+			dir = args[i]
+		case strings.HasPrefix(arg, "--"+projectDirFlag+"="):
+			dir = strings.TrimPrefix(arg, "--"+projectDirFlag+"=")
+		case command == "" && !strings.HasPrefix(arg, "-"):
+			command = arg
+		}
+	}
+	if dir == "" {
+		return nil
+	}
+
+	// A project is initialized in a new directory
+	if command == "init" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("unable to create the project directory %s: %v", dir, err)
+		}
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("unable to change to the project directory %s: %v", dir, err)
+	}
+	return nil
 }
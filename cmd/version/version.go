@@ -18,8 +18,14 @@ package version
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 )
 
 // var needs to be used instead of const as ldflags is used to fill this
@@ -54,20 +60,62 @@ func getVersion() Version {
 	}
 }
 
+// KubeBuilderVersion returns the version of kubebuilder set in the release process
+func KubeBuilderVersion() string {
+	return kubeBuilderVersion
+}
+
 func (v Version) Print() {
 	fmt.Printf("Version: %#v\n", v)
 }
 
 func NewVersionCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "version",
-		Short:   "Print the kubebuilder version",
-		Long:    `Print the kubebuilder version`,
-		Example: `kubebuilder version`,
-		Run:     runVersion,
+	var files bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the kubebuilder version",
+		Long: `Print the kubebuilder version.
+
+With --files, list the version of kubebuilder, the plugin and the template that each file of the project in
+the current directory was scaffolded with, as stamped in the provenance comment at the end of the files.`,
+		Example: `	# Print the kubebuilder version
+	kubebuilder version
+
+	# List the versions of kubebuilder the files of the project were scaffolded with
+	kubebuilder version --files`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if !files {
+				getVersion().Print()
+				return
+			}
+			if err := printFiles(); err != nil {
+				log.Fatal(err)
+			}
+		},
 	}
+
+	cmd.Flags().BoolVar(&files, "files", false,
+		"list the version of kubebuilder, the plugin and the template each file of the project was scaffolded with")
+
+	return cmd
 }
 
-func runVersion(_ *cobra.Command, _ []string) {
-	getVersion().Print()
+// printFiles prints the provenance of the scaffolded files of the project in the current directory
+func printFiles() error {
+	provenances, err := scaffold.ReadProvenance(afero.NewOsFs())
+	if err != nil {
+		return fmt.Errorf("error reading the provenance of the project files: %v", err)
+	}
+	if len(provenances) == 0 {
+		fmt.Println("No file of the project is stamped with its provenance.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tPLUGIN\tTEMPLATE\tFILE")
+	for _, p := range provenances {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Version, p.Plugin, p.Template, p.Path)
+	}
+	return w.Flush()
 }
@@ -23,11 +23,15 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/cmd/internal"
 	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
 type webhookError struct {
@@ -68,6 +72,7 @@ var _ commandOptions = &webhookV1Options{}
 
 type webhookV1Options struct {
 	resource    *resource.Resource
+	groupFlag   *flag.Flag
 	server      string
 	webhookType string
 	operations  []string
@@ -83,9 +88,8 @@ func (o *webhookV1Options) bindFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.doMake, "make", true, "if true, run make after generating files")
 
 	o.resource = &resource.Resource{}
-	cmd.Flags().StringVar(&o.resource.Group, "group", "", "resource Group")
-	cmd.Flags().StringVar(&o.resource.Version, "version", "", "resource Version")
-	cmd.Flags().StringVar(&o.resource.Kind, "kind", "", "resource Kind")
+	bindResourceFlags(cmd, o.resource)
+	o.groupFlag = cmd.Flag("group")
 	cmd.Flags().StringVar(&o.resource.Resource, "resource", "", "resource Resource")
 }
 
@@ -103,6 +107,10 @@ func (o *webhookV1Options) validate(c *config.Config) error {
 		return fmt.Errorf("webhook scaffolding is no longer alpha for version %s", c.Version)
 	}
 
+	if err := internal.ValidateResourceGroup(o.groupFlag, c, o.resource); err != nil {
+		return err
+	}
+
 	if err := o.resource.Validate(); err != nil {
 		return err
 	}
@@ -132,12 +140,32 @@ func newWebhookV2Cmd() *cobra.Command {
 		Use:   "webhook",
 		Short: "Scaffold a webhook for an API resource.",
 		Long: `Scaffold a webhook for an API resource. You can choose to scaffold defaulting, ` +
-			`validating and (or) conversion webhooks.`,
+			`validating and (or) conversion webhooks.
+
+Defaulting and validating webhooks can also be scaffolded for Kubernetes built-in types that the project ` +
+			`doesn't own, such as Pods. They are written under webhooks/<group>/<version> and registered in main.go.`,
 		Example: `	# Create defaulting and validating webhooks for CRD of group crew, version v1 and kind FirstMate.
 	kubebuilder create webhook --group crew --version v1 --kind FirstMate --defaulting --programmatic-validation
 
 	# Create conversion webhook for CRD of group crew, version v1 and kind FirstMate.
 	kubebuilder create webhook --group crew --version v1 --kind FirstMate --conversion
+
+	# Create conversion webhook for version v2 of the previous CRD using v1 as the hub version.
+	kubebuilder create webhook --group crew --version v2 --kind FirstMate --conversion --hub-version v1
+
+	# Create defaulting webhook whose certificate is generated by a Job instead of cert-manager.
+	kubebuilder create webhook --group crew --version v1 --kind FirstMate --defaulting --cert-provider webhookca
+
+	# Create validating webhook that admits the requests when the webhook server is unavailable and has no side
+	# effects, so that it is called for dry-run requests, with a timeout of 5 seconds.
+	kubebuilder create webhook --group crew --version v1 --kind FirstMate --programmatic-validation \
+		--failure-policy ignore --side-effects None --timeout 5
+
+	# Create defaulting webhook for the Kubernetes built-in Pod type.
+	kubebuilder create webhook --group core --version v1 --kind Pod --defaulting
+
+	# Show the changes that creating the previous webhook would make without writing them
+	kubebuilder create webhook --group crew --version v1 --kind FirstMate --conversion --dry-run
 `,
 		Run: func(_ *cobra.Command, _ []string) {
 			if err := run(options); err != nil {
@@ -155,17 +183,35 @@ var _ commandOptions = &webhookV2Options{}
 
 type webhookV2Options struct {
 	resource   *resource.Resource
+	groupFlag  *flag.Flag
 	defaulting bool
 	validation bool
 	conversion bool
+	hubVersion string
+	// deprecatedResource is the plural set by the deprecated --resource flag
+	deprecatedResource string
+	// certProvider is how the certificate of the webhook server is provided
+	certProvider string
+	// admission are the failure policy, side effects and timeout of the defaulting and validating webhooks
+	admission webhookv2.AdmissionOptions
+
+	// dryRun indicates that the changes should be printed as a diff instead of written
+	dryRun bool
+	// keepPartial indicates that the changes should be written as they are made instead of once scaffolding succeeded
+	keepPartial bool
+
+	// templatesDir is a directory with templates that replace the built-in ones
+	templatesDir string
 }
 
 func (o *webhookV2Options) bindFlags(cmd *cobra.Command) {
 	o.resource = &resource.Resource{}
-	cmd.Flags().StringVar(&o.resource.Group, "group", "", "resource Group")
-	cmd.Flags().StringVar(&o.resource.Version, "version", "", "resource Version")
-	cmd.Flags().StringVar(&o.resource.Kind, "kind", "", "resource Kind")
-	cmd.Flags().StringVar(&o.resource.Resource, "resource", "", "resource Resource")
+	bindResourceFlags(cmd, o.resource)
+	o.groupFlag = cmd.Flag("group")
+	cmd.Flags().StringVar(&o.resource.Resource, "plural", "",
+		"resource plural, defaults to the plural the API was created with")
+	cmd.Flags().StringVar(&o.deprecatedResource, "resource", "", "resource Resource")
+	_ = cmd.Flags().MarkDeprecated("resource", "use --plural instead")
 
 	cmd.Flags().BoolVar(&o.defaulting, "defaulting", false,
 		"if set, scaffold the defaulting webhook")
@@ -173,6 +219,44 @@ func (o *webhookV2Options) bindFlags(cmd *cobra.Command) {
 		"if set, scaffold the validating webhook")
 	cmd.Flags().BoolVar(&o.conversion, "conversion", false,
 		"if set, scaffold the conversion webhook")
+	cmd.Flags().StringVar(&o.hubVersion, "hub-version", "",
+		"version of the resource that the rest of versions convert to and from, "+
+			"defaults to the first version of the resource that was created")
+	cmd.Flags().StringVar(&o.certProvider, "cert-provider", "",
+		fmt.Sprintf("provider of the certificate of the webhook server, one of %q, %q (a Secret created by the user) "+
+			"or %q (generated by a Job), defaults to the one of the existing webhooks or %q",
+			modelconfig.CertProviderCertManager, modelconfig.CertProviderManual, modelconfig.CertProviderWebhookCA,
+			modelconfig.CertProviderCertManager))
+	_ = cmd.RegisterFlagCompletionFunc("cert-provider",
+		func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return []string{
+				modelconfig.CertProviderCertManager, modelconfig.CertProviderManual, modelconfig.CertProviderWebhookCA,
+			}, cobra.ShellCompDirectiveNoFileComp
+		})
+	cmd.Flags().StringVar(&o.admission.FailurePolicy, "failure-policy", "",
+		fmt.Sprintf("whether the requests are rejected (%s) or admitted (%s) when the defaulting and validating "+
+			"webhooks can't be called, defaults to %s, or %s for the Kubernetes built-in types",
+			webhookv2.FailurePolicyFail, webhookv2.FailurePolicyIgnore, webhookv2.FailurePolicyFail,
+			webhookv2.FailurePolicyIgnore))
+	_ = cmd.RegisterFlagCompletionFunc("failure-policy",
+		func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return []string{webhookv2.FailurePolicyFail, webhookv2.FailurePolicyIgnore}, cobra.ShellCompDirectiveNoFileComp
+		})
+	cmd.Flags().StringVar(&o.admission.SideEffects, "side-effects", "",
+		fmt.Sprintf("side effects of the defaulting and validating webhooks, one of %q", webhookv2.SideEffects))
+	_ = cmd.RegisterFlagCompletionFunc("side-effects",
+		func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return webhookv2.SideEffects, cobra.ShellCompDirectiveNoFileComp
+		})
+	cmd.Flags().IntVar(&o.admission.TimeoutSeconds, "timeout", 0,
+		"seconds the API server waits for the defaulting and validating webhooks, between 1 and 30")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false,
+		"if specified, print the changes as a diff without writing any file")
+	cmd.Flags().BoolVar(&o.keepPartial, "keep-partial", false,
+		"if specified, write the files as they are scaffolded, keeping them if scaffolding fails halfway")
+	cmd.Flags().StringVar(&o.templatesDir, "templates-dir", "",
+		"directory with templates that replace the built-in ones, "+
+			"looked up as <dir>/<package>/<type>.tmpl (e.g. v2/controller/Controller.tmpl)")
 }
 
 func (o *webhookV2Options) loadConfig() (*config.Config, error) {
@@ -188,6 +272,24 @@ func (o *webhookV2Options) validate(c *config.Config) error {
 	if c.IsV1() {
 		return fmt.Errorf("webhook scaffolding is alpha for version %s", c.Version)
 	}
+	if c.APIServer {
+		return errors.New("webhooks can't be created in aggregated API server projects")
+	}
+
+	if err := internal.ValidateResourceGroup(o.groupFlag, c, o.resource); err != nil {
+		return err
+	}
+
+	if o.deprecatedResource != "" {
+		if o.resource.Resource != "" && o.resource.Resource != o.deprecatedResource {
+			return fmt.Errorf("--plural %s and the deprecated --resource %s are different plurals",
+				o.resource.Resource, o.deprecatedResource)
+		}
+		o.resource.Resource = o.deprecatedResource
+	}
+	if o.resource.Resource == "" {
+		o.resource.Resource = c.KindPlural(o.resource.Group, o.resource.Kind)
+	}
 
 	if err := o.resource.Validate(); err != nil {
 		return err
@@ -198,11 +300,56 @@ func (o *webhookV2Options) validate(c *config.Config) error {
 			" --defaulting, --programmatic-validation and --conversion to be true")
 	}
 
+	if err := o.admission.Validate(); err != nil {
+		return err
+	}
+	if o.admission.IsSet() && !o.defaulting && !o.validation {
+		return errors.New("--failure-policy, --side-effects and --timeout can only be used together with " +
+			"--defaulting or --programmatic-validation")
+	}
+
+	if o.keepPartial && o.dryRun {
+		return errors.New("--keep-partial can't be used with --dry-run")
+	}
+
+	if o.templatesDir != "" {
+		if err := internal.ValidateTemplatesDir(o.templatesDir); err != nil {
+			return err
+		}
+	}
+
+	if err := scaffold.ValidateCertProvider(&c.Config, o.certProvider); err != nil {
+		return err
+	}
+
+	if o.conversion && !c.HasResource(o.resource) && util.IsCoreGroup(o.resource.Group) {
+		return fmt.Errorf("conversion webhooks can only be created for the types of the project, "+
+			"%s/%s is a Kubernetes built-in type", o.resource.Group, o.resource.Kind)
+	}
+
+	if o.hubVersion != "" {
+		if !o.conversion {
+			return errors.New("--hub-version can only be used together with --conversion")
+		}
+
+		hub := &resource.Resource{Group: o.resource.Group, Version: o.hubVersion, Kind: o.resource.Kind}
+		if !c.HasResource(hub) {
+			return fmt.Errorf("hub version %s of %s does not exist", o.hubVersion, o.resource.Kind)
+		}
+	}
+
 	return nil
 }
 
-func (o *webhookV2Options) scaffolder(c *config.Config) (scaffold.Scaffolder, error) { // nolint:unparam
-	return scaffold.NewV2WebhookScaffolder(&c.Config, o.resource, o.defaulting, o.validation, o.conversion), nil
+func (o *webhookV2Options) scaffolder(c *config.Config) (scaffold.Scaffolder, error) {
+	chain, err := projectPluginChain(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return stage(c, scaffold.NewV2WebhookScaffolder(c, o.resource, o.defaulting, o.validation, o.conversion,
+		o.hubVersion, o.certProvider, o.admission, chain.WebhookPlugins(), o.templatesDir, nil, nil),
+		o.dryRun, o.keepPartial), nil
 }
 
 func (o *webhookV2Options) postScaffold(_ *config.Config) error {
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWebhookV2OptionsPlural(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		err      string
+	}{
+		{args: []string{}, expected: "frigates"},
+		{args: []string{"--plural", "fleet"}, expected: "fleet"},
+		{args: []string{"--resource", "fleet"}, expected: "fleet"},
+		{args: []string{"--resource", "fleet", "--plural", "fleet"}, expected: "fleet"},
+		{args: []string{"--resource", "fleet", "--plural", "frigates"}, err: "are different plurals"},
+		{args: []string{"--plural", "frigates", "--resource", "fleet"}, err: "are different plurals"},
+	}
+
+	for _, test := range tests {
+		options := &webhookV2Options{}
+		args := []string{"--group", "ship", "--version", "v1", "--kind", "Frigate", "--defaulting"}
+		parseFlags(t, options, append(args, test.args...)...)
+
+		err := options.validate(newTestConfig())
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%v: unexpected error: %v", test.args, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%v: expected an error containing %q, got %v", test.args, test.err, err)
+		case test.err == "" && options.resource.Resource != test.expected:
+			t.Errorf("%v: expected the plural %q, got %q", test.args, test.expected, options.resource.Resource)
+		}
+	}
+}
@@ -3,18 +3,13 @@ module sigs.k8s.io/kubebuilder
 go 1.13
 
 require (
-	github.com/gobuffalo/flect v0.1.5
-	github.com/golang/protobuf v1.3.1 // indirect
-	github.com/kr/pretty v0.1.0 // indirect
-	github.com/onsi/ginkgo v1.8.0
-	github.com/onsi/gomega v1.5.0
+	github.com/gobuffalo/flect v0.2.0
+	github.com/onsi/ginkgo v1.10.1
+	github.com/onsi/gomega v1.7.0
 	github.com/spf13/afero v1.2.2
-	github.com/spf13/cobra v0.0.5
-	github.com/spf13/pflag v1.0.3
-	golang.org/x/net v0.0.0-20190812203447-cdfb69ac37fc // indirect
-	golang.org/x/sys v0.0.0-20190621203818-d432491b9138 // indirect
-	golang.org/x/text v0.3.2 // indirect
-	golang.org/x/tools v0.0.0-20190621195816-6e04913cbbac
-	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
+	github.com/spf13/cobra v1.1.1
+	github.com/spf13/pflag v1.0.5
+	golang.org/x/tools v0.0.0-20191112195655-aa38f8e97acc
+	sigs.k8s.io/controller-tools v0.2.5
 	sigs.k8s.io/yaml v1.1.0
 )
@@ -19,8 +19,10 @@ package config
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
+	"reflect"
+
+	"github.com/spf13/afero"
 
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/yaml"
@@ -34,9 +36,9 @@ const (
 	DefaultVersion = config.Version2
 )
 
-func exists(path string) (bool, error) {
+func exists(fs afero.Fs, path string) (bool, error) {
 	// Look up the file
-	_, err := os.Stat(path)
+	_, err := fs.Stat(path)
 
 	// If we could find it the file exists
 	if err == nil || os.IsExist(err) {
@@ -50,23 +52,51 @@ func exists(path string) (bool, error) {
 	return false, err
 }
 
-func readFrom(path string) (c config.Config, err error) {
+func readFrom(fs afero.Fs, path string) (c config.Config, schemaVersion string, err error) {
 	// Read the file
-	in, err := ioutil.ReadFile(path) // nolint: gosec
+	in, err := afero.ReadFile(fs, path)
 	if err != nil {
 		return
 	}
 
+	// Find out the schema of the file, files written before it was versioned don't have it
+	var header struct {
+		SchemaVersion string `json:"schemaVersion,omitempty"`
+	}
+	if err = yaml.Unmarshal(in, &header); err != nil {
+		return
+	}
+	schemaVersion = header.SchemaVersion
+	if schemaVersion == "" {
+		schemaVersion = SchemaV1
+	}
+	newSchema, supported := schemas[schemaVersion]
+	if !supported {
+		err = UnsupportedSchemaError{Path: path, Schema: schemaVersion}
+		return
+	}
+
 	// Unmarshal the file content
-	if err = yaml.Unmarshal(in, &c); err != nil {
+	file := newSchema()
+	if err = yaml.Unmarshal(in, file); err != nil {
 		return
 	}
+	c = file.toModel()
 
 	// kubebuilder v1 omitted version, so default to v1
 	if c.Version == "" {
 		c.Version = config.Version1
 	}
 
+	// Unknown fields are reported but the rest of the configuration is still returned
+	var raw interface{}
+	if err = yaml.Unmarshal(in, &raw); err != nil {
+		return
+	}
+	if fields := unknownFields(raw, reflect.TypeOf(file), ""); len(fields) != 0 {
+		err = UnknownFieldsError{Path: path, Schema: schemaVersion, Fields: fields}
+	}
+
 	return
 }
 
@@ -77,7 +107,12 @@ func Read() (*config.Config, error) {
 
 // ReadFrom obtains the configuration from the provided path but doesn't allow to persist changes
 func ReadFrom(path string) (*config.Config, error) {
-	c, err := readFrom(path)
+	return ReadFromFs(afero.NewOsFs(), path)
+}
+
+// ReadFromFs obtains the configuration from the provided path in fs but doesn't allow to persist changes
+func ReadFromFs(fs afero.Fs, path string) (*config.Config, error) {
+	c, _, err := readFrom(fs, path)
 
 	return &c, err
 }
@@ -92,6 +127,11 @@ type Config struct {
 	path string
 	// mustNotExist requires the file not to exist when saving it
 	mustNotExist bool
+	// schemaVersion is the schema the file is written with, files keep the schema they were read with until
+	// they are upgraded so that older versions of kubebuilder can still read them
+	schemaVersion string
+	// fs is the filesystem where the project files are written, defaults to the OS filesystem
+	fs afero.Fs
 }
 
 // New creates a new configuration that will be stored at the provided path
@@ -100,8 +140,9 @@ func New(path string) *Config {
 		Config: config.Config{
 			Version: config.Version2,
 		},
-		path:         path,
-		mustNotExist: true,
+		path:          path,
+		mustNotExist:  true,
+		schemaVersion: LatestSchema,
 	}
 }
 
@@ -112,13 +153,21 @@ func Load() (*Config, error) {
 
 // LoadFrom obtains the configuration from the provided path allowing to persist changes (Save method)
 func LoadFrom(path string) (*Config, error) {
-	c, err := readFrom(path)
+	c, schemaVersion, err := readFrom(afero.NewOsFs(), path)
+
+	return &Config{Config: c, path: path, schemaVersion: schemaVersion}, err
+}
+
+// LoadFromFs obtains the configuration from the provided path in fs allowing to persist changes (Save method),
+// the rest of the project files are written to fs too
+func LoadFromFs(fs afero.Fs, path string) (*Config, error) {
+	c, schemaVersion, err := readFrom(fs, path)
 
-	return &Config{Config: c, path: path}, err
+	return &Config{Config: c, path: path, fs: fs, schemaVersion: schemaVersion}, err
 }
 
 // Save saves the configuration information
-func (c Config) Save() error {
+func (c *Config) Save() error {
 	// If path is unset, it was created directly with `Config{}`
 	if c.path == "" {
 		return saveError{errors.New("no information where it should be stored, " +
@@ -128,7 +177,7 @@ func (c Config) Save() error {
 	// If it is a new configuration, the path should not exist yet
 	if c.mustNotExist {
 		// Lets check that the file doesn't exist
-		alreadyExists, err := exists(c.path)
+		alreadyExists, err := exists(c.Fs(), c.path)
 		if err != nil {
 			return saveError{err}
 		}
@@ -137,25 +186,65 @@ func (c Config) Save() error {
 		}
 	}
 
-	// Marshall into YAML
-	content, err := yaml.Marshal(c)
+	// Marshall into YAML with the schema of the file
+	newSchema, supported := schemas[c.SchemaVersion()]
+	if !supported {
+		return saveError{fmt.Errorf("unsupported configuration schema %s", c.SchemaVersion())}
+	}
+	file := newSchema()
+	file.fromModel(c.Config)
+	content, err := yaml.Marshal(file)
 	if err != nil {
 		return saveError{fmt.Errorf("error marshalling project configuration: %v", err)}
 	}
 
 	// Write the marshalled configuration
-	err = ioutil.WriteFile(c.path, content, 0600)
+	err = afero.WriteFile(c.Fs(), c.path, content, 0600)
 	if err != nil {
 		return saveError{fmt.Errorf("failed to save configuration to %s: %v", c.path, err)}
 	}
 
+	// Once saved, further changes update the existing file
+	c.mustNotExist = false
+
 	return nil
 }
 
+// SchemaVersion returns the schema the configuration file is written with
+func (c Config) SchemaVersion() string {
+	if c.schemaVersion == "" {
+		return LatestSchema
+	}
+	return c.schemaVersion
+}
+
+// Upgrade makes the configuration file be written with the latest schema when saved, returning false if it
+// already was
+func (c *Config) Upgrade() bool {
+	if c.SchemaVersion() == LatestSchema {
+		return false
+	}
+	c.schemaVersion = LatestSchema
+	return true
+}
+
 func (c Config) Path() string {
 	return c.path
 }
 
+// Fs returns the filesystem where the configuration and the rest of the project files are written
+func (c Config) Fs() afero.Fs {
+	if c.fs == nil {
+		return afero.NewOsFs()
+	}
+	return c.fs
+}
+
+// SetFs sets the filesystem where the configuration and the rest of the project files are written
+func (c *Config) SetFs(fs afero.Fs) {
+	c.fs = fs
+}
+
 type saveError struct {
 	err error
 }
@@ -0,0 +1,363 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	// SchemaV1 is the format of the configuration files written before the schema was versioned, which don't
+	// have a schemaVersion field
+	SchemaV1 = "v1"
+	// SchemaV2 records the schema in the schemaVersion field, writes every field in lower camel case and tracks
+	// the controller, webhooks and sample of each resource
+	SchemaV2 = "v2"
+
+	// LatestSchema is the schema of the configuration files of new projects
+	LatestSchema = SchemaV2
+)
+
+// schema is a format of the configuration file, each version of the schema being marshalled from its own type
+type schema interface {
+	// toModel returns the configuration stored in the file
+	toModel() config.Config
+	// fromModel sets the fields of the file from the configuration
+	fromModel(config.Config)
+}
+
+// schemas returns an empty value of the type marshalled for each version of the schema
+var schemas = map[string]func() schema{
+	SchemaV1: func() schema { return &configV1{} },
+	SchemaV2: func() schema { return &configV2{} },
+}
+
+// supportedSchemas returns the versions of the schema that can be read, sorted
+func supportedSchemas() []string {
+	versions := make([]string, 0, len(schemas))
+	for version := range schemas {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// UnsupportedSchemaError is returned when the configuration file was written with a schema newer than the ones
+// that this version of kubebuilder reads
+type UnsupportedSchemaError struct {
+	Path   string
+	Schema string
+}
+
+func (e UnsupportedSchemaError) Error() string {
+	return fmt.Sprintf("%s uses the configuration schema %s, which isn't supported by this version of kubebuilder "+
+		"(%s), upgrade kubebuilder to work on the project", e.Path, e.Schema, strings.Join(supportedSchemas(), ", "))
+}
+
+// UnknownFieldsError is returned when the configuration file has fields that are not part of its schema, e.g. a
+// typo or a field written by a newer version of kubebuilder
+type UnknownFieldsError struct {
+	Path   string
+	Schema string
+	// Fields are the paths of the unknown fields, e.g. resources[0].foo
+	Fields []string
+}
+
+func (e UnknownFieldsError) Error() string {
+	return fmt.Sprintf("%s has fields that are not part of the configuration schema %s: %s",
+		e.Path, e.Schema, strings.Join(e.Fields, ", "))
+}
+
+// unknownFields returns the paths of the fields of value, unmarshalled from the configuration file, that are not
+// fields of t
+func unknownFields(value interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	unknown := make([]string, 0)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			return unknown
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			fields[name] = t.Field(i).Type
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			fieldType, found := fields[key]
+			if !found {
+				unknown = append(unknown, fieldPath)
+				continue
+			}
+			unknown = append(unknown, unknownFields(v[key], fieldType, fieldPath)...)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			return unknown
+		}
+		for i, item := range v {
+			unknown = append(unknown, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return unknown
+}
+
+// configV1 is the format of SchemaV1
+type configV1 struct {
+	Version         string  `json:"version,omitempty"`
+	Domain          string  `json:"domain,omitempty"`
+	Repo            string  `json:"repo,omitempty"`
+	Resources       []gvkV1 `json:"resources,omitempty"`
+	MultiGroup      bool    `json:"multigroup,omitempty"`
+	Deploy          string  `json:"deploy,omitempty"`
+	NamespaceScoped bool    `json:"namespacescoped,omitempty"`
+	SecureDefaults  bool    `json:"secureDefaults,omitempty"`
+	CRDVersion      string  `json:"crdVersion,omitempty"`
+	CertProvider    string  `json:"certProvider,omitempty"`
+	APIServer       bool    `json:"apiserver,omitempty"`
+	Storage         string  `json:"storage,omitempty"`
+}
+
+type gvkV1 struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Plural  string `json:"plural,omitempty"`
+}
+
+func (f *configV1) toModel() config.Config {
+	c := config.Config{
+		Version:         f.Version,
+		Domain:          f.Domain,
+		Repo:            f.Repo,
+		MultiGroup:      f.MultiGroup,
+		Deploy:          f.Deploy,
+		NamespaceScoped: f.NamespaceScoped,
+		SecureDefaults:  f.SecureDefaults,
+		CRDVersion:      f.CRDVersion,
+		CertProvider:    f.CertProvider,
+		APIServer:       f.APIServer,
+		Storage:         f.Storage,
+	}
+	for _, r := range f.Resources {
+		c.Resources = append(c.Resources, config.GVK{Group: r.Group, Version: r.Version, Kind: r.Kind, Plural: r.Plural})
+	}
+	return c
+}
+
+func (f *configV1) fromModel(c config.Config) {
+	*f = configV1{
+		Version:         c.Version,
+		Domain:          c.Domain,
+		Repo:            c.Repo,
+		MultiGroup:      c.MultiGroup,
+		Deploy:          c.Deploy,
+		NamespaceScoped: c.NamespaceScoped,
+		SecureDefaults:  c.SecureDefaults,
+		CRDVersion:      c.CRDVersion,
+		CertProvider:    c.CertProvider,
+		APIServer:       c.APIServer,
+		Storage:         c.Storage,
+	}
+	for _, r := range c.Resources {
+		f.Resources = append(f.Resources, gvkV1{Group: r.Group, Version: r.Version, Kind: r.Kind, Plural: r.Plural})
+	}
+}
+
+// configV2 is the format of SchemaV2
+type configV2 struct {
+	SchemaVersion      string       `json:"schemaVersion"`
+	Version            string       `json:"version"`
+	Domain             string       `json:"domain,omitempty"`
+	Repo               string       `json:"repo,omitempty"`
+	ModulePath         string       `json:"modulePath,omitempty"`
+	Resources          []resourceV2 `json:"resources,omitempty"`
+	MultiGroup         bool         `json:"multiGroup,omitempty"`
+	Deploy             string       `json:"deploy,omitempty"`
+	NamespaceScoped    bool         `json:"namespaceScoped,omitempty"`
+	SecureDefaults     bool         `json:"secureDefaults,omitempty"`
+	ComponentConfig    bool         `json:"componentConfig,omitempty"`
+	CRDVersion         string       `json:"crdVersion,omitempty"`
+	CertProvider       string       `json:"certProvider,omitempty"`
+	WebhookPort        int          `json:"webhookPort,omitempty"`
+	WebhookHost        string       `json:"webhookHost,omitempty"`
+	MetricsBindAddress string       `json:"metricsBindAddress,omitempty"`
+	APIServer          bool         `json:"apiServer,omitempty"`
+	Storage            string       `json:"storage,omitempty"`
+	BaseImage          string       `json:"baseImage,omitempty"`
+	ClientGen          bool         `json:"clientGen,omitempty"`
+	Plugins            []string     `json:"plugins,omitempty"`
+	Vendor             bool         `json:"vendor,omitempty"`
+	KubernetesVersion  string       `json:"kubernetesVersion,omitempty"`
+	Sharding           bool         `json:"sharding,omitempty"`
+	SkipTests          bool         `json:"skipTests,omitempty"`
+	SkipSamples        bool         `json:"skipSamples,omitempty"`
+	SkipRBAC           bool         `json:"skipRBAC,omitempty"`
+	MergeBases         bool         `json:"mergeBases,omitempty"`
+}
+
+type resourceV2 struct {
+	Group         string      `json:"group"`
+	Version       string      `json:"version"`
+	Kind          string      `json:"kind"`
+	Plural        string      `json:"plural,omitempty"`
+	ClusterScoped bool        `json:"clusterScoped,omitempty"`
+	GroupPackage  string      `json:"groupPackage,omitempty"`
+	Controller    bool        `json:"controller,omitempty"`
+	Webhooks      *webhooksV2 `json:"webhooks,omitempty"`
+	Sample        bool        `json:"sample,omitempty"`
+}
+
+type webhooksV2 struct {
+	Defaulting bool `json:"defaulting,omitempty"`
+	Validation bool `json:"validation,omitempty"`
+	Conversion bool `json:"conversion,omitempty"`
+}
+
+func (r resourceV2) toModel() config.GVK {
+	gvk := config.GVK{
+		Group:         r.Group,
+		Version:       r.Version,
+		Kind:          r.Kind,
+		Plural:        r.Plural,
+		ClusterScoped: r.ClusterScoped,
+		GroupPackage:  r.GroupPackage,
+		ResourceState: config.ResourceState{
+			Controller: r.Controller,
+			Sample:     r.Sample,
+		},
+	}
+	if r.Webhooks != nil {
+		gvk.Webhooks = config.Webhooks{
+			Defaulting: r.Webhooks.Defaulting,
+			Validation: r.Webhooks.Validation,
+			Conversion: r.Webhooks.Conversion,
+		}
+	}
+	return gvk
+}
+
+func (r *resourceV2) fromModel(gvk config.GVK) {
+	*r = resourceV2{
+		Group:         gvk.Group,
+		Version:       gvk.Version,
+		Kind:          gvk.Kind,
+		Plural:        gvk.Plural,
+		ClusterScoped: gvk.ClusterScoped,
+		GroupPackage:  gvk.GroupPackage,
+		Controller:    gvk.Controller,
+		Sample:        gvk.Sample,
+	}
+	// Resources without webhooks omit the field
+	if gvk.Webhooks != (config.Webhooks{}) {
+		r.Webhooks = &webhooksV2{
+			Defaulting: gvk.Webhooks.Defaulting,
+			Validation: gvk.Webhooks.Validation,
+			Conversion: gvk.Webhooks.Conversion,
+		}
+	}
+}
+
+func (f *configV2) toModel() config.Config {
+	c := config.Config{
+		Version:            f.Version,
+		Domain:             f.Domain,
+		Repo:               f.Repo,
+		ModulePath:         f.ModulePath,
+		MultiGroup:         f.MultiGroup,
+		Deploy:             f.Deploy,
+		NamespaceScoped:    f.NamespaceScoped,
+		SecureDefaults:     f.SecureDefaults,
+		ComponentConfig:    f.ComponentConfig,
+		CRDVersion:         f.CRDVersion,
+		CertProvider:       f.CertProvider,
+		WebhookPort:        f.WebhookPort,
+		WebhookHost:        f.WebhookHost,
+		MetricsBindAddress: f.MetricsBindAddress,
+		APIServer:          f.APIServer,
+		Storage:            f.Storage,
+		BaseImage:          f.BaseImage,
+		ClientGen:          f.ClientGen,
+		Plugins:            f.Plugins,
+		Vendor:             f.Vendor,
+		KubernetesVersion:  f.KubernetesVersion,
+		Sharding:           f.Sharding,
+		SkipTests:          f.SkipTests,
+		SkipSamples:        f.SkipSamples,
+		SkipRBAC:           f.SkipRBAC,
+		MergeBases:         f.MergeBases,
+	}
+	for _, r := range f.Resources {
+		c.Resources = append(c.Resources, r.toModel())
+	}
+	return c
+}
+
+func (f *configV2) fromModel(c config.Config) {
+	*f = configV2{
+		SchemaVersion:      SchemaV2,
+		Version:            c.Version,
+		Domain:             c.Domain,
+		Repo:               c.Repo,
+		ModulePath:         c.ModulePath,
+		MultiGroup:         c.MultiGroup,
+		Deploy:             c.Deploy,
+		NamespaceScoped:    c.NamespaceScoped,
+		SecureDefaults:     c.SecureDefaults,
+		ComponentConfig:    c.ComponentConfig,
+		CRDVersion:         c.CRDVersion,
+		CertProvider:       c.CertProvider,
+		WebhookPort:        c.WebhookPort,
+		WebhookHost:        c.WebhookHost,
+		MetricsBindAddress: c.MetricsBindAddress,
+		APIServer:          c.APIServer,
+		Storage:            c.Storage,
+		BaseImage:          c.BaseImage,
+		ClientGen:          c.ClientGen,
+		Plugins:            c.Plugins,
+		Vendor:             c.Vendor,
+		KubernetesVersion:  c.KubernetesVersion,
+		Sharding:           c.Sharding,
+		SkipTests:          c.SkipTests,
+		SkipSamples:        c.SkipSamples,
+		SkipRBAC:           c.SkipRBAC,
+		MergeBases:         c.MergeBases,
+	}
+	f.Resources = make([]resourceV2, len(c.Resources))
+	for i, r := range c.Resources {
+		f.Resources[i].fromModel(r)
+	}
+}
@@ -17,6 +17,11 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 )
 
@@ -26,6 +31,49 @@ const (
 	Version2 = "2"
 )
 
+const (
+	// Deployment methods
+	DeployKustomize = "kustomize"
+	DeployHelm      = "helm"
+)
+
+const (
+	// Providers of the certificate of the webhook server
+	CertProviderCertManager = "cert-manager"
+	CertProviderManual      = "manual"
+	CertProviderWebhookCA   = "webhookca"
+)
+
+// DefaultWebhookPort is the port the webhook server of the manager listens on by default
+const DefaultWebhookPort = 9443
+
+// DefaultMetricsBindAddress is the address the metrics endpoint of the manager binds to by default
+const DefaultMetricsBindAddress = ":8080"
+
+const (
+	// API versions of the generated CustomResourceDefinitions
+	CRDVersionV1      = "v1"
+	CRDVersionV1beta1 = "v1beta1"
+)
+
+const (
+	// Base images of the manager image
+	BaseImageDistroless = "distroless"
+	BaseImageScratch    = "scratch"
+	BaseImageUBI        = "ubi"
+)
+
+const (
+	// Storages of the resources of aggregated API servers
+	StorageEtcd   = "etcd"
+	StorageMemory = "memory"
+)
+
+const (
+	// PluginGoV2 is the key of the plugin scaffolding the Go operators of v2 projects, the base of their plugin chain
+	PluginGoV2 = "go.kubebuilder.io/v2"
+)
+
 // Config is the unmarshalled representation of the configuration file
 type Config struct {
 	// Version is the project version, defaults to "1" (backwards compatibility)
@@ -37,12 +85,88 @@ type Config struct {
 	// Repo is the go package name of the project root
 	Repo string `json:"repo,omitempty"`
 
+	// ModulePath tracks the module of the go.mod shared with the rest of a monorepo when the project is in one of its
+	// subdirectories, e.g. example.com/monorepo for the example.com/monorepo/operators/foo Repo. The project has
+	// its own go.mod if empty.
+	ModulePath string `json:"modulePath,omitempty"`
+
 	// Resources tracks scaffolded resources in the project
 	// This info is tracked only in project with version 2
 	Resources []GVK `json:"resources,omitempty"`
 
 	// Multigroup tracks if the project has more than one group
 	MultiGroup bool `json:"multigroup,omitempty"`
+
+	// Deploy tracks how the project is deployed, defaults to kustomize
+	Deploy string `json:"deploy,omitempty"`
+
+	// NamespaceScoped tracks if the manager watches and is granted permissions in its namespace only
+	NamespaceScoped bool `json:"namespacescoped,omitempty"`
+
+	// SecureDefaults tracks if the manager pods run with a restricted security context
+	SecureDefaults bool `json:"secureDefaults,omitempty"`
+
+	// ComponentConfig tracks if the manager loads its options from a ControllerManagerConfiguration file mounted
+	// from a ConfigMap
+	ComponentConfig bool `json:"componentConfig,omitempty"`
+
+	// CRDVersion is the API version of the generated CustomResourceDefinitions, defaults to "v1beta1"
+	// (backwards compatibility)
+	CRDVersion string `json:"crdVersion,omitempty"`
+
+	// CertProvider tracks how the certificate of the webhook server is provided, defaults to cert-manager
+	CertProvider string `json:"certProvider,omitempty"`
+
+	// WebhookPort tracks the port the webhook server of the manager listens on, defaults to 9443
+	WebhookPort int `json:"webhookPort,omitempty"`
+
+	// WebhookHost tracks the address the webhook server of the manager binds to, e.g. :: for all the IPv4 and IPv6
+	// addresses of the pod, defaults to all of them
+	WebhookHost string `json:"webhookHost,omitempty"`
+
+	// MetricsBindAddress tracks the address the metrics endpoint of the manager binds to, e.g. [::]:8080,
+	// defaults to :8080
+	MetricsBindAddress string `json:"metricsBindAddress,omitempty"`
+
+	// APIServer tracks if the project is an aggregated API server serving its resources instead of a manager
+	// reconciling CRDs
+	APIServer bool `json:"apiserver,omitempty"`
+
+	// Storage tracks where the aggregated API server stores its resources, defaults to etcd
+	Storage string `json:"storage,omitempty"`
+
+	// BaseImage tracks the base image of the manager image built by the Dockerfile, defaults to distroless
+	BaseImage string `json:"baseImage,omitempty"`
+
+	// ClientGen tracks if typed clientsets, listers and informers are generated for the APIs with
+	// k8s.io/code-generator
+	ClientGen bool `json:"clientGen,omitempty"`
+
+	// Plugins tracks the keys of the versioned plugins the project is scaffolded with, e.g. go.kubebuilder.io/v2,
+	// in the order they are run
+	Plugins []string `json:"plugins,omitempty"`
+
+	// Vendor tracks if the dependencies of the project are vendored in vendor/, which go.mod, the Dockerfile and
+	// the commands fetching dependencies use instead of the network
+	Vendor bool `json:"vendor,omitempty"`
+
+	// KubernetesVersion tracks the Kubernetes minor version, e.g. 1.18, the versions of controller-runtime, the
+	// Kubernetes libraries and controller-gen are selected for, defaults to the ones of the kubebuilder release
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Sharding tracks if the objects reconciled by the controllers are split between the replicas of the manager,
+	// run as a StatefulSet whose pods are the shards
+	Sharding bool `json:"sharding,omitempty"`
+
+	// SkipTests, SkipSamples and SkipRBAC track the artifacts that aren't scaffolded for the APIs: the controller
+	// and webhook tests, the samples, and the editor and viewer roles
+	SkipTests   bool `json:"skipTests,omitempty"`
+	SkipSamples bool `json:"skipSamples,omitempty"`
+	SkipRBAC    bool `json:"skipRBAC,omitempty"`
+
+	// MergeBases tracks if the files owned by the user are recorded under .kubebuilder/scaffolds as they are
+	// scaffolded, the bases that create api --force three-way merges their new scaffold with
+	MergeBases bool `json:"mergeBases,omitempty"`
 }
 
 // IsV1 returns true if it is a v1 project
@@ -55,6 +179,138 @@ func (config Config) IsV2() bool {
 	return config.Version == Version2
 }
 
+// IsHelm returns true if the project is deployed with a Helm chart
+func (config Config) IsHelm() bool {
+	return config.Deploy == DeployHelm
+}
+
+// WebhookCertProvider returns how the certificate of the webhook server is provided
+func (config Config) WebhookCertProvider() string {
+	if config.CertProvider == "" {
+		return CertProviderCertManager
+	}
+	return config.CertProvider
+}
+
+// WebhookServerPort returns the port the webhook server of the manager listens on
+func (config Config) WebhookServerPort() int {
+	if config.WebhookPort == 0 {
+		return DefaultWebhookPort
+	}
+	return config.WebhookPort
+}
+
+// MetricsAddress returns the address the metrics endpoint of the manager binds to
+func (config Config) MetricsAddress() string {
+	if config.MetricsBindAddress == "" {
+		return DefaultMetricsBindAddress
+	}
+	return config.MetricsBindAddress
+}
+
+// MetricsLoopbackAddress returns the address the metrics endpoint of the manager binds to behind the auth proxy,
+// the loopback address of the family of the metrics bind address on its port, e.g. [::1]:8080 for [::]:8080
+func (config Config) MetricsLoopbackAddress() string {
+	host, port, err := net.SplitHostPort(config.MetricsAddress())
+	if err != nil {
+		host, port = "", "8080"
+	}
+	if isIPv6(host) {
+		return net.JoinHostPort("::1", port)
+	}
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+// IPv6 returns true if the manager binds its metrics endpoint or its webhook server to IPv6 addresses, in which
+// case the Services of the manager are dual-stack
+func (config Config) IPv6() bool {
+	host, _, err := net.SplitHostPort(config.MetricsAddress())
+	return (err == nil && isIPv6(host)) || isIPv6(config.WebhookHost)
+}
+
+// isIPv6 returns true if the host is an IPv6 address
+func isIPv6(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.To4() == nil
+}
+
+// ValidateBindAddress checks that the address is a host and a port a server can bind to, e.g. :8080, 0.0.0.0:8080
+// or [::]:8080, the host being empty for all the addresses of the pod or an IP address
+func ValidateBindAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid bind address %q, must be a host and a port, e.g. :8080 or [::]:8080: %v",
+			address, err)
+	}
+	if host != "" && net.ParseIP(host) == nil {
+		return fmt.Errorf("invalid bind address %q, the host must be empty or an IP address", address)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid bind address %q, the port must be between 1 and 65535", address)
+	}
+	return nil
+}
+
+// ModuleDir returns the directory of the project relative to the one of the shared go.mod, e.g. operators/foo,
+// or an empty string if the project has its own go.mod
+func (config Config) ModuleDir() string {
+	if config.ModulePath == "" {
+		return ""
+	}
+	return strings.TrimPrefix(config.Repo, config.ModulePath+"/")
+}
+
+// IsCRDV1 returns true if the CustomResourceDefinitions are generated as apiextensions.k8s.io/v1
+func (config Config) IsCRDV1() bool {
+	return config.CRDVersion == CRDVersionV1
+}
+
+// DockerBaseImage returns the base image of the manager image built by the Dockerfile
+func (config Config) DockerBaseImage() string {
+	if config.BaseImage == "" {
+		return BaseImageDistroless
+	}
+	return config.BaseImage
+}
+
+// IsMemoryStorage returns true if the aggregated API server keeps its resources in memory instead of etcd
+func (config Config) IsMemoryStorage() bool {
+	return config.Storage == StorageMemory
+}
+
+// PluginKeys returns the keys of the plugins the project is scaffolded with, v2 projects that don't track them
+// are scaffolded with the Go plugin only
+func (config Config) PluginKeys() []string {
+	if len(config.Plugins) == 0 && config.IsV2() && !config.APIServer {
+		return []string{PluginGoV2}
+	}
+	return config.Plugins
+}
+
+// GroupPackage returns the name the packages of the API group are imported as if it was overridden for any of its
+// tracked resources, empty otherwise
+func (config Config) GroupPackage(group string) string {
+	for _, r := range config.Resources {
+		if r.Group == group && len(r.GroupPackage) != 0 {
+			return r.GroupPackage
+		}
+	}
+	return ""
+}
+
+// SetGroupPackage imports the resource as the package recorded for its group, it fails if the resource overrides
+// it with another one
+func (config Config) SetGroupPackage(r *resource.Resource) error {
+	recorded := config.GroupPackage(r.Group)
+	switch {
+	case r.GroupPackage == "":
+		r.GroupPackage = recorded
+	case recorded != "" && r.GroupPackage != recorded:
+		return fmt.Errorf("the packages of group %s are already imported as %s", r.Group, recorded)
+	}
+	return nil
+}
+
 // ResourceGroups returns unique groups of scaffolded resources in the project
 func (config Config) ResourceGroups() []string {
 	groupSet := map[string]struct{}{}
@@ -89,31 +345,180 @@ func (config Config) HasResource(target *resource.Resource) bool {
 	return false
 }
 
-// AddResource appends the provided resource to the tracked ones
+// AddResource appends the provided resource to the tracked ones and records what was scaffolded for it
 // It returns if the configuration was modified
 // NOTE: this works only for v2, since in v1 resources are not tracked
-func (config *Config) AddResource(r *resource.Resource) bool {
+func (config *Config) AddResource(r *resource.Resource, state ResourceState) bool {
 	// Short-circuit v1
 	if config.Version == Version1 {
 		return false
 	}
 
-	// No-op if the resource was already tracked, return false
+	// Only record the state if the resource was already tracked
 	if config.HasResource(r) {
-		return false
+		return config.UpdateResource(r, state)
 	}
 
 	// Append the resource to the tracked ones, return true
-	config.Resources = append(config.Resources,
-		GVK{Group: r.Group, Version: r.Version, Kind: r.Kind})
+	gvk := GVK{Group: r.Group, Version: r.Version, Kind: r.Kind, ResourceState: state}
+	if !r.HasDefaultPlural() {
+		gvk.Plural = r.Resource
+	}
+	gvk.ClusterScoped = !r.Namespaced
+	gvk.GroupPackage = r.GroupPackage
+	config.Resources = append(config.Resources, gvk)
 	return true
 }
 
+// UpdateResource records what was scaffolded for the provided resource if it is tracked, keeping what had
+// been scaffolded before
+// It returns if the configuration was modified
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config *Config) UpdateResource(r *resource.Resource, state ResourceState) bool {
+	for i, tracked := range config.Resources {
+		if tracked.isEqualTo(r) {
+			merged := tracked.ResourceState.merge(state)
+			if merged == tracked.ResourceState {
+				return false
+			}
+			config.Resources[i].ResourceState = merged
+			return true
+		}
+	}
+
+	// No-op if the resource was not tracked, return false
+	return false
+}
+
+// GetResource returns the tracked resource and whether it was found
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config Config) GetResource(r *resource.Resource) (GVK, bool) {
+	for _, tracked := range config.Resources {
+		if tracked.isEqualTo(r) {
+			return tracked, true
+		}
+	}
+
+	return GVK{}, false
+}
+
+// RemoveResource removes the provided resource from the tracked ones
+// It returns if the configuration was modified
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config *Config) RemoveResource(r *resource.Resource) bool {
+	// Short-circuit v1
+	if config.Version == Version1 {
+		return false
+	}
+
+	for i, tracked := range config.Resources {
+		if tracked.isEqualTo(r) {
+			config.Resources = append(config.Resources[:i], config.Resources[i+1:]...)
+			return true
+		}
+	}
+
+	// No-op if the resource was not tracked, return false
+	return false
+}
+
+// HasGroupVersion returns true if any of the tracked resources belongs to the provided group and version
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config Config) HasGroupVersion(group, version string) bool {
+	for _, r := range config.Resources {
+		if r.Group == group && r.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KindVersions returns the versions of the tracked resources with the provided group and kind
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config Config) KindVersions(group, kind string) []string {
+	versions := make([]string, 0)
+	for _, r := range config.Resources {
+		if r.Group == group && r.Kind == kind {
+			versions = append(versions, r.Version)
+		}
+	}
+
+	return versions
+}
+
+// KindPlural returns the plural that the tracked versions of the provided kind are served under if it is a
+// custom one, or an empty string if it is the default plural or the kind is not tracked
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config Config) KindPlural(group, kind string) string {
+	for _, r := range config.Resources {
+		if r.Group == group && r.Kind == kind {
+			return r.Plural
+		}
+	}
+	return ""
+}
+
+// KindNamespaced returns whether the tracked versions of the provided kind are namespaced and whether the kind
+// is tracked at all
+// NOTE: this works only for v2, since in v1 resources are not tracked
+func (config Config) KindNamespaced(group, kind string) (namespaced bool, found bool) {
+	for _, r := range config.Resources {
+		if r.Group == group && r.Kind == kind {
+			return !r.ClusterScoped, true
+		}
+	}
+	return false, false
+}
+
 // GVK contains information about scaffolded resources
 type GVK struct {
 	Group   string `json:"group,omitempty"`
 	Version string `json:"version,omitempty"`
 	Kind    string `json:"kind,omitempty"`
+
+	// Plural is the API Resource, only tracked if it is not the default plural of the Kind
+	Plural string `json:"plural,omitempty"`
+
+	// ClusterScoped tracks if the objects of the resource don't belong to a namespace
+	ClusterScoped bool `json:"clusterScoped,omitempty"`
+
+	// GroupPackage is the name the packages of the API group are imported as, only tracked if it was overridden
+	GroupPackage string `json:"groupPackage,omitempty"`
+
+	ResourceState
+}
+
+// ResourceState contains information about what was scaffolded for a resource
+type ResourceState struct {
+	// Controller tracks if the resource has a controller
+	Controller bool `json:"controller,omitempty"`
+
+	// Webhooks tracks the webhooks of the resource
+	Webhooks Webhooks `json:"webhooks,omitempty"`
+
+	// Sample tracks if the resource has a sample in config/samples
+	Sample bool `json:"sample,omitempty"`
+}
+
+// Webhooks contains information about the webhooks scaffolded for a resource
+type Webhooks struct {
+	Defaulting bool `json:"defaulting,omitempty"`
+	Validation bool `json:"validation,omitempty"`
+	Conversion bool `json:"conversion,omitempty"`
+}
+
+// merge returns the state with what was scaffolded in either of them
+func (s ResourceState) merge(other ResourceState) ResourceState {
+	return ResourceState{
+		Controller: s.Controller || other.Controller,
+		Webhooks: Webhooks{
+			Defaulting: s.Webhooks.Defaulting || other.Webhooks.Defaulting,
+			Validation: s.Webhooks.Validation || other.Webhooks.Validation,
+			Conversion: s.Webhooks.Conversion || other.Webhooks.Conversion,
+		},
+		Sample: s.Sample || other.Sample,
+	}
 }
 
 // isEqualTo compares it with another resource
@@ -31,4 +31,10 @@ type File struct {
 	// TODO: Move input.IfExistsAction into model
 	// IfExistsAction determines what to do if the file exists
 	IfExistsAction input.IfExistsAction `json:"ifExistsAction,omitempty"`
+
+	// Template identifies the template the file was rendered from, e.g. v2.Main
+	Template string `json:"template,omitempty"`
+
+	// Plugin is the key of the project plugin that scaffolded the file
+	Plugin string `json:"plugin,omitempty"`
 }
@@ -17,10 +17,10 @@ limitations under the License.
 package model
 
 import (
-	"io/ioutil"
+	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
+	"github.com/spf13/afero"
 
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
@@ -38,6 +38,9 @@ type Universe struct {
 	// Resource contains the information of the API that is being scaffolded
 	Resource *Resource `json:"resource,omitempty"`
 
+	// Resources contains the information of the APIs tracked in the project configuration
+	Resources []*Resource `json:"resources,omitempty"`
+
 	// Files contains the model of the files that are being scaffolded
 	Files []*File `json:"files,omitempty"`
 }
@@ -69,8 +72,13 @@ func WithConfig(projectConfig *config.Config) UniverseOption {
 
 // WithBoilerplateFrom loads the boilerplate from the provided path
 func WithBoilerplateFrom(path string) UniverseOption {
+	return WithBoilerplateFromFs(afero.NewOsFs(), path)
+}
+
+// WithBoilerplateFromFs loads the boilerplate from the provided path in fs
+func WithBoilerplateFromFs(fs afero.Fs, path string) UniverseOption {
 	return func(universe *Universe) error {
-		boilerplate, err := ioutil.ReadFile(path)
+		boilerplate, err := afero.ReadFile(fs, path)
 		if err != nil {
 			return err
 		}
@@ -103,7 +111,7 @@ func WithResource(resource *resource.Resource, project *config.Config) UniverseO
 			Version:    resource.Version,
 			Kind:       resource.Kind,
 			Resource:   resource.Resource,
-			Plural:     flect.Pluralize(strings.ToLower(resource.Kind)),
+			Plural:     resource.Plural(),
 		}
 
 		resourceModel.GoPackage, resourceModel.GroupDomain = util.GetResourceInfo(
@@ -117,3 +125,44 @@ func WithResource(resource *resource.Resource, project *config.Config) UniverseO
 		return nil
 	}
 }
+
+// WithResources stores the resources tracked in the project configuration
+func WithResources(project *config.Config) UniverseOption {
+	return func(universe *Universe) error {
+		for _, gvk := range project.Resources {
+			r := &resource.Resource{
+				Group:        gvk.Group,
+				Version:      gvk.Version,
+				Kind:         gvk.Kind,
+				Resource:     gvk.Plural,
+				Namespaced:   !gvk.ClusterScoped,
+				GroupPackage: project.GroupPackage(gvk.Group),
+			}
+			// Reuse WithResource to build the model of each resource
+			single := &Universe{}
+			if err := WithResource(r, project)(single); err != nil {
+				return err
+			}
+			universe.Resources = append(universe.Resources, single.Resource)
+		}
+		return nil
+	}
+}
+
+// TypesPath returns the path of the types file of the Resource in the layout of the project
+func (u Universe) TypesPath() string {
+	kind := strings.ToLower(u.Resource.Kind)
+	if u.Config != nil && u.Config.MultiGroup {
+		return filepath.Join("apis", u.Resource.Group, u.Resource.Version, kind+"_types.go")
+	}
+	return filepath.Join("api", u.Resource.Version, kind+"_types.go")
+}
+
+// ControllerPath returns the path of the controller file of the Resource in the layout of the project
+func (u Universe) ControllerPath() string {
+	kind := strings.ToLower(u.Resource.Kind)
+	if u.Config != nil && u.Config.MultiGroup {
+		return filepath.Join("controllers", u.Resource.Group, kind+"_controller.go")
+	}
+	return filepath.Join("controllers", kind+"_controller.go")
+}
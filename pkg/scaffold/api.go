@@ -18,18 +18,27 @@ package scaffold
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/spf13/afero"
+
 	"sigs.k8s.io/kubebuilder/internal/config"
 	"sigs.k8s.io/kubebuilder/pkg/model"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	scaffolderrors "sigs.k8s.io/kubebuilder/pkg/scaffold/errors"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	controllerv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/controller"
 	crdv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/crd"
 	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	apiserverv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/apiserver"
 	controllerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/controller"
 	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	e2ev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/e2e"
+	prometheusv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/prometheus"
 )
 
 // apiScaffolder contains configuration for generating scaffolding for Go type
@@ -43,57 +52,91 @@ type apiScaffolder struct {
 	doResource bool
 	// doController indicates whether to scaffold controller files or not
 	doController bool
+	// force indicates whether to three-way merge the files that already exist instead of failing
+	force bool
+	// reporter is notified of the files that are written
+	reporter Reporter
+	// out is where the messages for the user are printed
+	out io.Writer
+	// templatesDir is a directory with templates that replace the built-in ones
+	templatesDir string
+	// insertions are the code fragments wiring the API in the shared files, e.g. main.go, which are shared with
+	// the other scaffolders of a batch
+	insertions *scaffoldv2.Insertions
 }
 
 func NewAPIScaffolder(
 	config *config.Config,
 	res *resource.Resource,
 	doResource, doController bool,
+	force bool,
 	plugins []Plugin,
+	templatesDir string,
+	reporter Reporter,
+	out io.Writer,
 ) Scaffolder {
+	if reporter == nil {
+		reporter = &TextReporter{}
+	}
+	if out == nil {
+		out = os.Stdout
+	}
 	return &apiScaffolder{
 		plugins:      plugins,
 		resource:     res,
 		config:       config,
 		doResource:   doResource,
 		doController: doController,
+		force:        force,
+		reporter:     reporter,
+		out:          out,
+		templatesDir: templatesDir,
 	}
 }
 
 func (s *apiScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	fmt.Fprintln(s.out, "Writing scaffold for you to edit...")
+
+	// The code fragments are inserted once the API was scaffolded, or by the batch it belongs to
+	batched := s.insertions != nil
+	if !batched {
+		s.insertions = &scaffoldv2.Insertions{}
+	}
 
+	var err error
 	switch {
+	case s.config.IsV2() && s.config.APIServer:
+		err = s.scaffoldAPIServer()
 	case s.config.IsV1():
-		return s.scaffoldV1()
+		err = s.scaffoldV1()
 	case s.config.IsV2():
-		return s.scaffoldV2()
+		err = s.scaffoldV2()
 	default:
-		return fmt.Errorf("unknown project version %v", s.config.Version)
+		err = fmt.Errorf("unknown project version %v", s.config.Version)
+	}
+	if err != nil || batched {
+		return err
 	}
+
+	return applyInsertions(s.config.Fs(), s.insertions, s.reporter)
 }
 
 func (s *apiScaffolder) buildUniverse() (*model.Universe, error) {
 	return model.NewUniverse(
 		model.WithConfig(&s.config.Config),
-		// TODO: missing model.WithBoilerplate[From], needs boilerplate or path
+		model.WithBoilerplateFromFs(s.config.Fs(), boilerplatePath),
 		model.WithResource(s.resource, &s.config.Config),
 	)
 }
 
 func (s *apiScaffolder) scaffoldV1() error {
 	if s.doResource {
-		fmt.Println(filepath.Join("pkg", "apis", s.resource.Group, s.resource.Version,
-			fmt.Sprintf("%s_types.go", strings.ToLower(s.resource.Kind))))
-		fmt.Println(filepath.Join("pkg", "apis", s.resource.Group, s.resource.Version,
-			fmt.Sprintf("%s_types_test.go", strings.ToLower(s.resource.Kind))))
-
 		universe, err := s.buildUniverse()
 		if err != nil {
-			return fmt.Errorf("error building API scaffold: %v", err)
+			return scaffolderrors.ModelBuild("building API scaffold", err)
 		}
 
-		if err := (&Scaffold{}).Execute(
+		if err := (&Scaffold{TemplatesDir: s.templatesDir, Reporter: s.reporter, Out: s.out}).Execute(
 			universe,
 			input.Options{},
 			&crdv1.Register{Resource: s.resource},
@@ -105,7 +148,7 @@ func (s *apiScaffolder) scaffoldV1() error {
 			&crdv1.AddToScheme{Resource: s.resource},
 			&crdv1.CRDSample{Resource: s.resource},
 		); err != nil {
-			return fmt.Errorf("error scaffolding APIs: %v", err)
+			return fmt.Errorf("error scaffolding APIs: %w", err)
 		}
 	} else {
 		// disable generation of example reconcile body if not scaffolding resource
@@ -116,17 +159,12 @@ func (s *apiScaffolder) scaffoldV1() error {
 	}
 
 	if s.doController {
-		fmt.Println(filepath.Join("pkg", "controller", strings.ToLower(s.resource.Kind),
-			fmt.Sprintf("%s_controller.go", strings.ToLower(s.resource.Kind))))
-		fmt.Println(filepath.Join("pkg", "controller", strings.ToLower(s.resource.Kind),
-			fmt.Sprintf("%s_controller_test.go", strings.ToLower(s.resource.Kind))))
-
 		universe, err := s.buildUniverse()
 		if err != nil {
-			return fmt.Errorf("error building controller scaffold: %v", err)
+			return scaffolderrors.ModelBuild("building controller scaffold", err)
 		}
 
-		if err := (&Scaffold{}).Execute(
+		if err := (&Scaffold{TemplatesDir: s.templatesDir, Reporter: s.reporter, Out: s.out}).Execute(
 			universe,
 			input.Options{},
 			&controllerv1.Controller{Resource: s.resource},
@@ -134,7 +172,7 @@ func (s *apiScaffolder) scaffoldV1() error {
 			&controllerv1.Test{Resource: s.resource},
 			&controllerv1.SuiteTest{Resource: s.resource},
 		); err != nil {
-			return fmt.Errorf("error scaffolding controller: %v", err)
+			return fmt.Errorf("error scaffolding controller: %w", err)
 		}
 	}
 
@@ -142,62 +180,126 @@ func (s *apiScaffolder) scaffoldV1() error {
 }
 
 func (s *apiScaffolder) scaffoldV2() error {
+	// Only save the resource in the config file if it didn't exist or new files were scaffolded for it
+	state := modelconfig.ResourceState{Controller: s.doController, Sample: s.doResource && !s.config.SkipSamples}
+	var tracked bool
 	if s.doResource {
-		// Only save the resource in the config file if it didn't exist
-		if s.config.AddResource(s.resource) {
-			if err := s.config.Save(); err != nil {
-				return fmt.Errorf("error updating project file with resource information : %v", err)
-			}
+		tracked = s.config.AddResource(s.resource, state)
+	} else {
+		tracked = s.config.UpdateResource(s.resource, state)
+	}
+	if tracked {
+		if err := s.config.Save(); err != nil {
+			return scaffolderrors.PostUpdate(config.DefaultPath,
+				"updating project file with resource information", err)
 		}
+		s.reporter.ReportFile(config.DefaultPath, FileUpdated)
+	}
 
-		var path string
-		if s.config.MultiGroup {
-			path = filepath.Join("apis", s.resource.Group, s.resource.Version,
-				fmt.Sprintf("%s_types.go", strings.ToLower(s.resource.Kind)))
-		} else {
-			path = filepath.Join("api", s.resource.Version,
-				fmt.Sprintf("%s_types.go", strings.ToLower(s.resource.Kind)))
+	if s.doResource {
+
+		// Kinds served in multiple versions need one of them to be persisted
+		otherVersions := s.otherVersions()
+		if len(otherVersions) != 0 && s.resource.StorageVersion == "" {
+			s.resource.StorageVersion = s.currentStorageVersion(otherVersions)
 		}
-		fmt.Println(path)
 
 		universe, err := s.buildUniverse()
 		if err != nil {
-			return fmt.Errorf("error building API scaffold: %v", err)
+			return scaffolderrors.ModelBuild("building API scaffold", err)
 		}
 
-		if err := (&Scaffold{Plugins: s.plugins}).Execute(
+		files := []input.File{
+			&scaffoldv2.Types{
+				Input:     input.Input{Path: s.typesPath(s.resource.Version)},
+				Resource:  s.resource,
+				ClientGen: s.config.ClientGen,
+			},
+			&scaffoldv2.Group{Resource: s.resource},
+			&crdv2.EnableWebhookPatch{Resource: s.resource, CRDVersion: s.config.CRDVersion},
+			&crdv2.EnableCAInjectionPatch{Resource: s.resource, CRDVersion: s.config.CRDVersion},
+		}
+		files = append(files, s.optionalResourceFiles()...)
+		if s.resource.ConditionsPackage {
+			files = append(files, &scaffoldv2.ConditionsPackage{})
+		} else if s.resource.Conditions {
+			files = append(files, &scaffoldv2.Conditions{Resource: s.resource})
+		}
+		if s.resource.ApplyConfiguration {
+			files = append(files, &scaffoldv2.ApplyConfiguration{Resource: s.resource})
+		}
+		if s.config.ClientGen {
+			files = append(files, &scaffoldv2.ClientRegister{Resource: s.resource})
+		}
+		if s.config.MultiGroup {
+			// main.go registers the API versions of multigroup projects through the apis package
+			files = append(files, &scaffoldv2.APIs{}, &scaffoldv2.AddToScheme{Resource: s.resource})
+		}
+
+		if err := (&Scaffold{
+			Plugins:      s.plugins,
+			Fs:           s.config.Fs(),
+			TemplatesDir: s.templatesDir,
+			Merge:        s.force,
+			Reporter:     s.reporter,
+			Out:          s.out,
+		}).Execute(
 			universe,
 			input.Options{},
-			&scaffoldv2.Types{Input: input.Input{Path: path}, Resource: s.resource},
-			&scaffoldv2.Group{Resource: s.resource},
-			&scaffoldv2.CRDSample{Resource: s.resource},
-			&scaffoldv2.CRDEditorRole{Resource: s.resource},
-			&scaffoldv2.CRDViewerRole{Resource: s.resource},
-			&crdv2.EnableWebhookPatch{Resource: s.resource},
-			&crdv2.EnableCAInjectionPatch{Resource: s.resource},
+			files...,
 		); err != nil {
-			return fmt.Errorf("error scaffolding APIs: %v", err)
+			return fmt.Errorf("error scaffolding APIs: %w", err)
+		}
+		if s.resource.ConditionsPackage {
+			dockerfile := &scaffoldv2.Dockerfile{}
+			changed, err := dockerfile.CopyPackages(s.config.Fs(), filepath.Join("pkg", "conditions"))
+			if err != nil {
+				return scaffolderrors.PostUpdate(dockerfile.Path, "copying the conditions package", err)
+			}
+			if changed {
+				s.reporter.ReportFile(dockerfile.Path, FileUpdated)
+			}
+		}
+
+		for _, version := range otherVersions {
+			typesFile := &scaffoldv2.Types{
+				Input:    input.Input{Path: s.typesPath(version)},
+				Resource: &resource.Resource{Kind: s.resource.Kind},
+			}
+			changed, err := typesFile.SetStorageVersion(s.config.Fs(), version == s.resource.StorageVersion)
+			if err != nil {
+				fmt.Fprintf(s.out, "Warning: %v\nAdd the +kubebuilder:storageversion marker to the %s version of %s.\n",
+					err, s.resource.StorageVersion, s.resource.Kind)
+				continue
+			}
+			if changed {
+				s.reporter.ReportFile(typesFile.Path, FileUpdated)
+			}
 		}
 
 		universe, err = s.buildUniverse()
 		if err != nil {
-			return fmt.Errorf("error building kustomization scaffold: %v", err)
+			return scaffolderrors.ModelBuild("building kustomization scaffold", err)
 		}
 
 		kustomizationFile := &crdv2.Kustomization{Resource: s.resource}
-		if err := (&Scaffold{}).Execute(
+		samplesKustomizationFiles := s.samplesKustomizations()
+		kustomizationFiles := []input.File{kustomizationFile, &crdv2.KustomizeConfig{CRDVersion: s.config.CRDVersion}}
+		for _, f := range samplesKustomizationFiles {
+			kustomizationFiles = append(kustomizationFiles, f)
+		}
+		if err := (&Scaffold{Fs: s.config.Fs(), TemplatesDir: s.templatesDir, Reporter: s.reporter, Out: s.out}).Execute(
 			universe,
 			input.Options{},
-			kustomizationFile,
-			&crdv2.KustomizeConfig{},
+			kustomizationFiles...,
 		); err != nil {
-			return fmt.Errorf("error scaffolding kustomization: %v", err)
+			return fmt.Errorf("error scaffolding kustomization: %w", err)
 		}
 
-		if err := kustomizationFile.Update(); err != nil {
-			return fmt.Errorf("error updating kustomization.yaml: %v", err)
+		s.insertions.Add(kustomizationFile.Path, kustomizationFile.Fragments())
+		for _, f := range samplesKustomizationFiles {
+			s.insertions.Add(f.Path, f.Fragments(s.resource))
 		}
-
 	} else {
 		// disable generation of example reconcile body if not scaffolding resource
 		// because this could result in a fork-bomb of k8s resources where watching a
@@ -207,44 +309,314 @@ func (s *apiScaffolder) scaffoldV2() error {
 	}
 
 	if s.doController {
-		if s.config.MultiGroup {
-			fmt.Println(filepath.Join("controllers", s.resource.Group,
-				fmt.Sprintf("%s_controller.go", strings.ToLower(s.resource.Kind))))
-		} else {
-			fmt.Println(filepath.Join("controllers",
-				fmt.Sprintf("%s_controller.go", strings.ToLower(s.resource.Kind))))
-		}
-
 		universe, err := s.buildUniverse()
 		if err != nil {
-			return fmt.Errorf("error building controller scaffold: %v", err)
+			return scaffolderrors.ModelBuild("building controller scaffold", err)
 		}
 
 		suiteTestFile := &controllerv2.SuiteTest{Resource: s.resource}
-		if err := (&Scaffold{Plugins: s.plugins}).Execute(
+		files := []input.File{
+			&controllerv2.Controller{
+				Resource:        s.resource,
+				NamespaceScoped: s.config.NamespaceScoped,
+				Sharding:        s.config.Sharding,
+			},
+		}
+		if !s.config.SkipTests {
+			files = append(files, suiteTestFile)
+		}
+		if s.resource.Finalizer {
+			files = append(files, &controllerv2.Finalizers{Resource: s.resource})
+		}
+		if s.resource.ControllerOptions {
+			files = append(files, &controllerv2.Options{})
+		}
+		featureGatesFile := &controllerv2.FeatureGates{}
+		if s.resource.FeatureGate {
+			files = append(files, featureGatesFile)
+		}
+		if !s.config.SkipTests && (s.resource.Finalizer || s.resource.ReconcilePeriod != 0 ||
+			s.resource.Image != "" || s.resource.TestStyle == resource.TestStyleFake) {
+			files = append(files, &controllerv2.ControllerTest{Resource: s.resource})
+		}
+		if s.resource.Metrics {
+			// The ServiceMonitor is scaffolded on init, but older projects may be missing it
+			files = append(files,
+				&controllerv2.Metrics{Resource: s.resource},
+				&prometheusv2.Kustomization{},
+				&prometheusv2.ServiceMonitor{},
+			)
+		}
+		// The permissions on cluster-scoped resources can only be granted by the ClusterRole of the manager
+		clusterRoleBinding := &scaffoldv2.ManagerClusterRoleBinding{}
+		bindClusterRole := s.config.NamespaceScoped && !s.resource.Namespaced
+		if bindClusterRole {
+			files = append(files, clusterRoleBinding)
+		}
+
+		if err := (&Scaffold{
+			Plugins:      s.plugins,
+			Fs:           s.config.Fs(),
+			TemplatesDir: s.templatesDir,
+			Merge:        s.force,
+			Reporter:     s.reporter,
+			Out:          s.out,
+		}).Execute(
 			universe,
 			input.Options{},
-			suiteTestFile,
-			&controllerv2.Controller{Resource: s.resource},
+			files...,
 		); err != nil {
-			return fmt.Errorf("error scaffolding controller: %v", err)
+			return fmt.Errorf("error scaffolding controller: %w", err)
+		}
+
+		if !s.config.SkipTests {
+			s.insertions.Add(suiteTestFile.Path, suiteTestFile.Fragments())
+		}
+		if s.resource.FeatureGate {
+			s.insertions.Add(featureGatesFile.Path, featureGatesFile.Fragments(s.resource))
+			dockerfile := &scaffoldv2.Dockerfile{}
+			changed, err := dockerfile.CopyPackages(s.config.Fs(), filepath.Dir(featureGatesFile.Path))
+			if err != nil {
+				return scaffolderrors.PostUpdate(dockerfile.Path, "copying the featuregates package", err)
+			}
+			if changed {
+				s.reporter.ReportFile(dockerfile.Path, FileUpdated)
+			}
 		}
 
-		if err := suiteTestFile.Update(); err != nil {
-			return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+		if bindClusterRole {
+			if err := clusterRoleBinding.AddToKustomization(s.config.Fs()); err != nil {
+				return scaffolderrors.PostUpdate(filepath.Join("config", "rbac", "kustomization.yaml"),
+					fmt.Sprintf("adding %s to the kustomization", clusterRoleBinding.Path), err)
+			}
+			s.reporter.ReportFile(filepath.Join("config", "rbac", "kustomization.yaml"), FileUpdated)
+		}
+
+		if s.resource.Metrics {
+			kustomizeFile := &scaffoldv2.Kustomize{}
+			if err := kustomizeFile.EnablePrometheus(s.config.Fs()); err != nil {
+				fmt.Fprintf(s.out, "Warning: %v\nUncomment the [PROMETHEUS] section in %s to deploy the ServiceMonitor.\n",
+					err, filepath.Join("config", "default", "kustomization.yaml"))
+			} else {
+				s.reporter.ReportFile(kustomizeFile.Path, FileUpdated)
+			}
 		}
 	}
 
-	if err := (&scaffoldv2.Main{}).Update(
+	if s.doResource && !s.config.SkipTests {
+		if err := s.scaffoldE2ETest(); err != nil {
+			return err
+		}
+	}
+
+	mainFragments, err := (&scaffoldv2.Main{}).Fragments(
 		&scaffoldv2.MainUpdateOptions{
 			Config:         &s.config.Config,
 			WireResource:   s.doResource,
 			WireController: s.doController,
 			Resource:       s.resource,
+			Fs:             s.config.Fs(),
+		},
+	)
+	if err != nil {
+		return scaffolderrors.PostUpdate("main.go", "updating main.go", err)
+	}
+	s.insertions.Add("main.go", mainFragments)
+
+	// Multiple versions of the same Kind need to be converted between them
+	if versions := s.config.KindVersions(s.resource.Group, s.resource.Kind); s.doResource && len(versions) > 1 {
+		fmt.Fprintf(s.out, "%s is served in multiple versions (%s) and persisted in %s, "+
+			"scaffold the conversion between them with:\n"+
+			"$ kubebuilder create webhook --group %s --version <version> --kind %s --conversion\n",
+			s.resource.Kind, strings.Join(versions, ", "), s.resource.StorageVersion, s.resource.Group, s.resource.Kind)
+	}
+
+	// The API server only applies the defaults of v1beta1 CRDs that prune the unknown fields
+	if s.doResource && s.resource.DefaultsMode == resource.DefaultsModeMarkers && !s.config.IsCRDV1() {
+		fmt.Fprintln(s.out, "The default markers require Kubernetes 1.16+ and, as the CRDs are generated as "+
+			"apiextensions.k8s.io/v1beta1, preserveUnknownFields=false in the CRD_OPTIONS of the Makefile, "+
+			`e.g. CRD_OPTIONS ?= "crd:trivialVersions=true,preserveUnknownFields=false".`)
+	}
+
+	if s.config.IsHelm() {
+		fmt.Fprintln(s.out, "Run `make chart` to update the Helm chart.")
+	}
+
+	return nil
+}
+
+// scaffoldAPIServer scaffolds the types of a resource served by an aggregated API server and registers it
+func (s *apiScaffolder) scaffoldAPIServer() error {
+	if !s.doResource {
+		return nil
+	}
+
+	// Only save the resource in the config file if it didn't exist
+	if s.config.AddResource(s.resource, modelconfig.ResourceState{Sample: !s.config.SkipSamples}) {
+		if err := s.config.Save(); err != nil {
+			return scaffolderrors.PostUpdate(config.DefaultPath,
+				"updating project file with resource information", err)
+		}
+		s.reporter.ReportFile(config.DefaultPath, FileUpdated)
+	}
+
+	universe, err := s.buildUniverse()
+	if err != nil {
+		return scaffolderrors.ModelBuild("building API scaffold", err)
+	}
+
+	files := []input.File{
+		&scaffoldv2.Types{
+			Input:     input.Input{Path: s.typesPath(s.resource.Version)},
+			Resource:  s.resource,
+			ClientGen: s.config.ClientGen,
 		},
+		&scaffoldv2.Group{Resource: s.resource},
+		&apiserverv2.APIService{Resource: s.resource},
+	}
+	files = append(files, s.optionalResourceFiles()...)
+	if s.resource.Conditions {
+		files = append(files, &scaffoldv2.Conditions{Resource: s.resource})
+	}
+	if s.resource.ApplyConfiguration {
+		files = append(files, &scaffoldv2.ApplyConfiguration{Resource: s.resource})
+	}
+	if s.config.ClientGen {
+		files = append(files, &scaffoldv2.ClientRegister{Resource: s.resource})
+	}
+
+	if err := (&Scaffold{
+		Plugins:      s.plugins,
+		Fs:           s.config.Fs(),
+		TemplatesDir: s.templatesDir,
+		Merge:        s.force,
+		Reporter:     s.reporter,
+		Out:          s.out,
+	}).Execute(
+		universe,
+		input.Options{},
+		files...,
 	); err != nil {
-		return fmt.Errorf("error updating main.go: %v", err)
+		return fmt.Errorf("error scaffolding APIs: %w", err)
+	}
+
+	if !s.config.SkipSamples {
+		samplesKustomizationFile := &scaffoldv2.SamplesKustomization{}
+		if err := (&Scaffold{Fs: s.config.Fs(), TemplatesDir: s.templatesDir, Reporter: s.reporter, Out: s.out}).Execute(
+			universe,
+			input.Options{},
+			samplesKustomizationFile,
+		); err != nil {
+			return fmt.Errorf("error scaffolding kustomization: %w", err)
+		}
+		s.insertions.Add(samplesKustomizationFile.Path, samplesKustomizationFile.Fragments(s.resource))
 	}
 
+	kustomizationFile := &apiserverv2.Kustomization{Resource: s.resource}
+	kustomizationFragments, err := kustomizationFile.Fragments()
+	if err != nil {
+		return scaffolderrors.PostUpdate(kustomizationFile.Path, "updating kustomization.yaml", err)
+	}
+	s.insertions.Add(kustomizationFile.Path, kustomizationFragments)
+
+	mainFile := &apiserverv2.Main{}
+	s.insertions.Add("main.go", mainFile.Fragments(&s.config.Config, s.resource))
+
 	return nil
 }
+
+// scaffoldE2ETest scaffolds the e2e test of the API, which creates its sample in the cluster the manager is
+// deployed in, for the projects with e2e tests
+func (s *apiScaffolder) scaffoldE2ETest() error {
+	hasE2ETests, err := afero.Exists(s.config.Fs(), filepath.Join("test", "e2e", "e2e_suite_test.go"))
+	if err != nil || !hasE2ETests {
+		return err
+	}
+
+	universe, err := s.buildUniverse()
+	if err != nil {
+		return scaffolderrors.ModelBuild("building e2e test scaffold", err)
+	}
+
+	if err := (&Scaffold{
+		Plugins:      s.plugins,
+		Fs:           s.config.Fs(),
+		TemplatesDir: s.templatesDir,
+		Merge:        s.force,
+		Reporter:     s.reporter,
+		Out:          s.out,
+	}).Execute(
+		universe,
+		input.Options{},
+		&e2ev2.APITest{Resource: s.resource, Controller: s.doController},
+		&e2ev2.Utils{},
+	); err != nil {
+		return fmt.Errorf("error scaffolding e2e test: %w", err)
+	}
+
+	return nil
+}
+
+// optionalResourceFiles returns the sample and the editor and viewer roles of the resource, unless the project
+// skips them
+func (s *apiScaffolder) optionalResourceFiles() []input.File {
+	files := make([]input.File, 0, 3)
+	if !s.config.SkipSamples {
+		files = append(files, &scaffoldv2.CRDSample{Resource: s.resource})
+	}
+	if !s.config.SkipRBAC {
+		files = append(files,
+			&scaffoldv2.CRDEditorRole{Resource: s.resource},
+			&scaffoldv2.CRDViewerRole{Resource: s.resource},
+		)
+	}
+	return files
+}
+
+// samplesKustomizations returns the kustomizations listing the sample of the resource: the one of config/samples,
+// and the one of the directory of its group in multigroup projects. There are none if the project skips samples.
+func (s *apiScaffolder) samplesKustomizations() []*scaffoldv2.SamplesKustomization {
+	if s.config.SkipSamples {
+		return nil
+	}
+	files := []*scaffoldv2.SamplesKustomization{{}}
+	if s.config.MultiGroup && s.resource.Group != "" {
+		files = append(files, &scaffoldv2.SamplesKustomization{Group: s.resource.Group})
+	}
+	return files
+}
+
+// typesPath returns the path of the types file of the Kind in the provided version
+func (s *apiScaffolder) typesPath(version string) string {
+	if s.config.MultiGroup {
+		return filepath.Join("apis", s.resource.Group, version,
+			fmt.Sprintf("%s_types.go", strings.ToLower(s.resource.Kind)))
+	}
+	return filepath.Join("api", version, fmt.Sprintf("%s_types.go", strings.ToLower(s.resource.Kind)))
+}
+
+// otherVersions returns the tracked versions of the Kind other than the one being scaffolded
+func (s *apiScaffolder) otherVersions() []string {
+	versions := make([]string, 0)
+	for _, version := range s.config.KindVersions(s.resource.Group, s.resource.Kind) {
+		if version != s.resource.Version {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// currentStorageVersion returns the version of the Kind with the +kubebuilder:storageversion marker,
+// defaulting to the first one that was created
+func (s *apiScaffolder) currentStorageVersion(versions []string) string {
+	for _, version := range versions {
+		typesFile := &scaffoldv2.Types{
+			Input:    input.Input{Path: s.typesPath(version)},
+			Resource: &resource.Resource{Kind: s.resource.Kind},
+		}
+		if storage, err := typesFile.IsStorageVersion(s.config.Fs()); err == nil && storage {
+			return version
+		}
+	}
+	return versions[0]
+}
@@ -0,0 +1,387 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crdimport"
+)
+
+var _ = Describe("APIScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		fs, c = scaffoldtest.NewProject(func(c *config.Config) {
+			c.APIServer = true
+			c.Storage = modelconfig.StorageMemory
+		})
+	})
+
+	It("should serve the resources of aggregated API server projects from the registry", func() {
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Resource: "frigates", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`shipv1 "example.com/project/api/v1"`))
+		Expect(content).To(ContainSubstring("_ = shipv1.AddToScheme(scheme)"))
+		Expect(content).To(ContainSubstring(`GroupVersionResource: shipv1.GroupVersion.WithResource("frigates")`))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "apiserver", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("- ship_v1_apiservice.yaml\n"))
+
+		Expect(afero.Exists(fs, filepath.Join("config", "apiserver", "ship_v1_apiservice.yaml"))).To(BeTrue())
+		Expect(afero.DirExists(fs, "controllers")).To(BeFalse())
+	})
+})
+
+var _ = Describe("APIScaffolder merging the existing files", func() {
+	var res *resource.Resource
+
+	BeforeEach(func() {
+		res = &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+	})
+
+	It("should not record the files unless the project asks for it", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+		Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(afero.DirExists(fs, filepath.Join(".kubebuilder", "scaffolds"))).To(BeFalse())
+
+		err := scaffold.NewAPIScaffolder(c, res, true, true, true, nil, "", nil, nil).Scaffold()
+		Expect(err).To(MatchError(ContainSubstring(filepath.Join("api", "v1", "frigate_types.go") +
+			" already exists and can't be merged")))
+		Expect(err).To(MatchError(ContainSubstring("kubebuilder edit --merge-bases")))
+	})
+
+	It("should merge the changes made to the files recorded as they were scaffolded", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.MergeBases = true
+		})
+		Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		typesPath := filepath.Join("api", "v1", "frigate_types.go")
+		base := scaffoldtest.ReadFile(fs, filepath.Join(".kubebuilder", "scaffolds", typesPath))
+		changed := strings.Replace(base, "\tFoo string `json:\"foo,omitempty\"`\n",
+			"\tFoo string `json:\"foo,omitempty\"`\n\tBar string `json:\"bar,omitempty\"`\n", 1)
+		Expect(changed).NotTo(Equal(base))
+		Expect(afero.WriteFile(fs, typesPath, []byte(changed), 0600)).To(Succeed())
+
+		Expect(scaffold.NewAPIScaffolder(c, res, true, true, true, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(scaffoldtest.ReadFile(fs, typesPath)).To(Equal(changed))
+	})
+})
+
+var _ = Describe("APIScaffolder without a group", func() {
+	It("should serve the resource in the API group named after the domain", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.MultiGroup = true
+		})
+
+		foo := &resource.Resource{Version: "v1", Kind: "Foo", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, foo, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("apis", "v1", "groupversion_info.go"))
+		Expect(content).To(ContainSubstring("// +groupName=example.com\n"))
+		Expect(content).To(ContainSubstring(`schema.GroupVersion{Group: "example.com", Version: "v1"}`))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("controllers", "foo_controller.go"))
+		Expect(content).To(ContainSubstring("// +kubebuilder:rbac:groups=example.com,resources=foos,"))
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`controller "example.com/project/controllers"`))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "crd", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("- bases/example.com_foos.yaml\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "crd", "patches", "webhook_in_foos.yaml"))
+		Expect(content).To(ContainSubstring("name: foos.example.com\n"))
+	})
+})
+
+var _ = Describe("APIScaffolder with an apply configuration", func() {
+	It("should scaffold the apply configuration along with the types", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Suspend: true,
+			ApplyConfiguration: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "frigate_applyconfiguration.go"))).To(BeTrue())
+	})
+})
+
+var _ = Describe("APIScaffolder with a feature gate", func() {
+	It("should register a feature gate per controller and bind the --feature-gates flag once", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		for _, kind := range []string{"Frigate", "Destroyer"} {
+			res := &resource.Resource{Group: "ship", Version: "v1", Kind: kind, FeatureGate: true}
+			Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		}
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("featuregates", "featuregates.go"))
+		Expect(content).To(ContainSubstring(`ExperimentalFrigateReconcile Feature = "ExperimentalFrigateReconcile"`))
+		Expect(content).To(ContainSubstring(`ExperimentalDestroyerReconcile: {Default: false, PreRelease: Alpha},`))
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(strings.Count(content, `flag.Var(featuregates.Gates, "feature-gates", featuregates.Usage())`)).
+			To(Equal(1))
+
+		content = scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(strings.Count(content, "COPY main.go main.go\nCOPY featuregates/ featuregates/\n")).To(Equal(1))
+	})
+})
+
+var _ = Describe("APIScaffolder with a reconcile period", func() {
+	It("should set the period of the controller with a flag of the manager", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		res := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", ReconcilePeriod: 5 * time.Minute}
+		Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(
+			`flag.DurationVar(&frigateReconcilePeriod, "frigate-reconcile-period", 5*time.Minute,`))
+		Expect(content).To(ContainSubstring("ReconcilePeriod: frigateReconcilePeriod,"))
+
+		Expect(afero.Exists(fs, filepath.Join("controllers", "frigate_controller_test.go"))).To(BeTrue())
+	})
+})
+
+var _ = Describe("APIScaffolder with an image", func() {
+	It("should scaffold the test of the controller deploying the image", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		res := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			Image: "nginx:1.19", ImageContainerPort: 80, Conditions: true, Owns: resource.ImageResources}
+		Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(afero.Exists(fs, filepath.Join("controllers", "frigate_controller_test.go"))).To(BeTrue())
+	})
+})
+
+var _ = Describe("APIScaffolder with the conditions package", func() {
+	It("should share the conditions package between the resources and patch their status", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		for _, kind := range []string{"Frigate", "Sloop"} {
+			res := &resource.Resource{Group: "ship", Version: "v1", Kind: kind, Namespaced: true,
+				Conditions: true, ConditionsPackage: true}
+			Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		}
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("pkg", "conditions", "conditions.go"))
+		Expect(content).To(ContainSubstring("func SetSummary(to Setter, conditionTypes ...string) {"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("api", "v1", "frigate_types.go"))
+		Expect(content).To(ContainSubstring("\"example.com/project/pkg/conditions\""))
+		Expect(content).To(ContainSubstring("Conditions []conditions.Condition `json:\"conditions,omitempty\""))
+		Expect(content).To(ContainSubstring("func (in *Frigate) SetConditions("))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("controllers", "frigate_controller.go"))
+		Expect(content).To(ContainSubstring("conditions.MarkTrue(instance, conditions.ReadyCondition"))
+		Expect(content).To(ContainSubstring("r.Status().Patch(ctx, instance, statusPatch)"))
+
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "condition_types.go"))).To(BeFalse())
+
+		content = scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(strings.Count(content, "COPY pkg/conditions/ pkg/conditions/\n")).To(Equal(1))
+	})
+})
+
+var _ = Describe("APIScaffolder in a namespace-scoped project", func() {
+	It("should grant the permissions on cluster-scoped resources through the ClusterRole of the manager", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.NamespaceScoped = true
+		})
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring("options.NewCache = newMultiNamespaceCache(namespaces)"))
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer"}
+		Expect(scaffold.NewAPIScaffolder(c, destroyer, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("controllers", "frigate_controller.go"))
+		Expect(content).To(ContainSubstring(
+			"resources=frigates,verbs=get;list;watch;create;update;patch;delete,namespace=system\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("controllers", "destroyer_controller.go"))
+		Expect(content).To(ContainSubstring(
+			"resources=destroyers,verbs=get;list;watch;create;update;patch;delete\n"))
+		Expect(content).To(ContainSubstring("resources=destroyers/status,verbs=get;update;patch\n"))
+
+		Expect(afero.Exists(fs, filepath.Join("config", "rbac", "cluster_role_binding.yaml"))).To(BeTrue())
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "rbac", "kustomization.yaml"))
+		Expect(strings.Count(content, "- cluster_role_binding.yaml\n")).To(Equal(1))
+
+		gvk, found := c.GetResource(destroyer)
+		Expect(found).To(BeTrue())
+		Expect(gvk.ClusterScoped).To(BeTrue())
+	})
+})
+
+var _ = Describe("APIScaffolder samples kustomization", func() {
+	It("should list the samples in the kustomization of config/samples", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+		for _, kind := range []string{"Frigate", "Destroyer"} {
+			res := &resource.Resource{Group: "ship", Version: "v1", Kind: kind, Namespaced: true}
+			Expect(scaffold.NewAPIScaffolder(c, res, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		}
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("resources:\n- ship_v1_frigate.yaml\n- ship_v1_destroyer.yaml\n"))
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		Expect(scaffold.NewDeleteAPIScaffolder(c, frigate).Scaffold()).To(Succeed())
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("resources:\n- ship_v1_destroyer.yaml\n"))
+		Expect(afero.Exists(fs, filepath.Join("config", "samples", "ship_v1_frigate.yaml"))).To(BeFalse())
+	})
+
+	It("should group the samples of multigroup projects in the directories of their group", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.MultiGroup = true
+		})
+		for _, res := range []*resource.Resource{
+			{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true},
+			{Group: "ship", Version: "v1beta1", Kind: "Frigate", Namespaced: true},
+			{Group: "crew", Version: "v1", Kind: "Captain", Namespaced: true},
+		} {
+			Expect(scaffold.NewAPIScaffolder(c, res, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		}
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("resources:\n- ship\n- crew\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "ship", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("resources:\n- v1_frigate.yaml\n- v1beta1_frigate.yaml\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "crew", "v1_captain.yaml"))
+		Expect(content).To(ContainSubstring("kind: Captain\n"))
+
+		captain := &resource.Resource{Group: "crew", Version: "v1", Kind: "Captain"}
+		Expect(scaffold.NewDeleteAPIScaffolder(c, captain).Scaffold()).To(Succeed())
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("resources:\n- ship\n"))
+		Expect(content).NotTo(ContainSubstring("- crew\n"))
+		Expect(afero.Exists(fs, filepath.Join("config", "samples", "crew", "kustomization.yaml"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("APIScaffolder with skipped artifacts", func() {
+	It("should skip the tests, samples and roles of the APIs of the project", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+		c.SkipTests = true
+		c.SkipSamples = true
+		c.SkipRBAC = true
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		for _, path := range []string{
+			filepath.Join("api", "v1", "frigate_types.go"),
+			filepath.Join("controllers", "frigate_controller.go"),
+		} {
+			Expect(afero.Exists(fs, path)).To(BeTrue(), path)
+		}
+		for _, path := range []string{
+			filepath.Join("controllers", "suite_test.go"),
+			filepath.Join("config", "samples", "ship_v1_frigate.yaml"),
+			filepath.Join("config", "samples", "kustomization.yaml"),
+			filepath.Join("config", "rbac", "frigate_editor_role.yaml"),
+			filepath.Join("config", "rbac", "frigate_viewer_role.yaml"),
+		} {
+			Expect(afero.Exists(fs, path)).To(BeFalse(), path)
+		}
+
+		content := scaffoldtest.ReadFile(fs, "PROJECT")
+		Expect(content).To(ContainSubstring("skipTests: true\n"))
+		Expect(content).To(ContainSubstring("skipSamples: true\n"))
+		Expect(content).To(ContainSubstring("skipRBAC: true\n"))
+	})
+})
+
+var _ = Describe("APIScaffolder with the types of a CRD", func() {
+	It("should generate the types and the sample of the API from the schema of the CRD", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		crds, err := crdimport.Load([]byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ship.example.com
+  names:
+    kind: Frigate
+    plural: frigates
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: [captain]
+            properties:
+              captain:
+                type: string
+                minLength: 1
+`))
+		Expect(err).NotTo(HaveOccurred())
+		resources, err := crds[0].Resources(c.Domain)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(1))
+
+		plugins := []scaffold.Plugin{crdimport.Plugin{CRD: crds[0]}}
+		Expect(scaffold.NewAPIScaffolder(c, resources[0], true, true, false, plugins, "", nil, nil).Scaffold()).
+			To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("api", "v1", "frigate_types.go"))
+		Expect(content).To(ContainSubstring(
+			"\t// +kubebuilder:validation:MinLength=1\n\tCaptain string `json:\"captain\"`\n"))
+		Expect(content).NotTo(ContainSubstring("Foo string"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "ship_v1_frigate.yaml"))
+		Expect(content).To(ContainSubstring("spec:\n  captain: \"\"\n"))
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring("shipv1.AddToScheme(scheme)"))
+		Expect(c.HasResource(resources[0])).To(BeTrue())
+	})
+})
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+// batchScaffolder runs the scaffolders of several APIs one after the other, inserting the code fragments that
+// wire them in the shared files, e.g. main.go or the kustomization of the CRDs, once all of them succeeded
+type batchScaffolder struct {
+	config      *config.Config
+	scaffolders []Scaffolder
+	insertions  *scaffoldv2.Insertions
+	reporter    Reporter
+}
+
+// NewBatchScaffolder returns a scaffolder running the provided scaffolders, returned by NewAPIScaffolder or
+// NewV2WebhookScaffolder, so that each of the shared files is updated, and formatted, once for the whole batch
+func NewBatchScaffolder(config *config.Config, reporter Reporter, scaffolders ...Scaffolder) Scaffolder {
+	if reporter == nil {
+		reporter = &TextReporter{}
+	}
+
+	insertions := &scaffoldv2.Insertions{}
+	for _, scaffolder := range scaffolders {
+		switch s := scaffolder.(type) {
+		case *apiScaffolder:
+			s.insertions = insertions
+		case *webhookScaffolder:
+			s.insertions = insertions
+		}
+	}
+
+	return &batchScaffolder{
+		config:      config,
+		scaffolders: scaffolders,
+		insertions:  insertions,
+		reporter:    reporter,
+	}
+}
+
+func (s *batchScaffolder) Scaffold() error {
+	for _, scaffolder := range s.scaffolders {
+		if err := scaffolder.Scaffold(); err != nil {
+			return err
+		}
+	}
+
+	return applyInsertions(s.config.Fs(), s.insertions, s.reporter)
+}
+
+// applyInsertions inserts the recorded code fragments and reports the updated files
+func applyInsertions(fs afero.Fs, insertions *scaffoldv2.Insertions, reporter Reporter) error {
+	paths, err := insertions.Apply(fs)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		reporter.ReportFile(path, FileUpdated)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var _ = Describe("BatchScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		fs, c = scaffoldtest.NewProject(nil)
+	})
+
+	It("should update the files shared by the APIs once they were all scaffolded", func() {
+		out := &bytes.Buffer{}
+		reporter := &scaffold.TextReporter{Out: out}
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer", Namespaced: true}
+		Expect(scaffold.NewBatchScaffolder(c, reporter,
+			scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", reporter, nil),
+			scaffold.NewAPIScaffolder(c, destroyer, true, false, false, nil, "", reporter, nil),
+			scaffold.NewV2WebhookScaffolder(c, destroyer, true, false, false, "", "", webhookv2.AdmissionOptions{}, nil, "",
+				reporter, nil),
+		).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(strings.Count(content, "_ = shipv1.AddToScheme(scheme)")).To(Equal(1))
+		Expect(content).To(ContainSubstring("(&controllers.FrigateReconciler{"))
+		Expect(content).To(ContainSubstring("(&shipv1.Destroyer{}).SetupWebhookWithManager(mgr)"))
+		Expect(content).NotTo(ContainSubstring("DestroyerReconciler"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "crd", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("- bases/ship.example.com_frigates.yaml\n"))
+		Expect(content).To(ContainSubstring("- bases/ship.example.com_destroyers.yaml\n"))
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "destroyer_webhook_test.go"))).To(BeTrue())
+
+		Expect(strings.Count(out.String(), "main.go\n")).To(Equal(1))
+
+		Expect(c.Resources).To(Equal([]modelconfig.GVK{
+			{
+				Group: "ship", Version: "v1", Kind: "Frigate",
+				ResourceState: modelconfig.ResourceState{Controller: true, Sample: true},
+			},
+			{
+				Group: "ship", Version: "v1", Kind: "Destroyer",
+				ResourceState: modelconfig.ResourceState{
+					Webhooks: modelconfig.Webhooks{Defaulting: true},
+					Sample:   true,
+				},
+			},
+		}))
+	})
+})
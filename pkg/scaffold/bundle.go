@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/olm"
+)
+
+// bundleScaffolder generates an OLM bundle from the tracked resources and the generated manifests
+type bundleScaffolder struct {
+	config *config.Config
+	// version is the version of the operator, without the leading "v"
+	version string
+	// image is the controller manager image
+	image string
+	// channels are the channels the bundle is published in
+	channels []string
+}
+
+func NewBundleScaffolder(config *config.Config, version, image string, channels []string) Scaffolder {
+	return &bundleScaffolder{
+		config:   config,
+		version:  version,
+		image:    image,
+		channels: channels,
+	}
+}
+
+func (s *bundleScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("OLM bundles are not supported for project version %v", s.config.Version)
+	}
+
+	// use directory name as package name, like the kustomize prefix
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	packageName := strings.ToLower(filepath.Base(dir))
+
+	resources := make([]*resource.Resource, 0, len(s.config.Resources))
+	for _, gvk := range s.config.Resources {
+		r := &resource.Resource{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Resource: gvk.Plural}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("invalid resource %s/%s, Kind=%s: %v", gvk.Group, gvk.Version, gvk.Kind, err)
+		}
+		resources = append(resources, r)
+	}
+
+	examples, err := s.almExamples(resources)
+	if err != nil {
+		return err
+	}
+	rules, err := s.clusterRules()
+	if err != nil {
+		return err
+	}
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(s.config.Fs(), boilerplatePath),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := (&Scaffold{Fs: s.config.Fs()}).Execute(
+		universe,
+		input.Options{},
+		&olm.ClusterServiceVersion{
+			PackageName:  packageName,
+			Version:      s.version,
+			Image:        s.image,
+			Resources:    resources,
+			ALMExamples:  examples,
+			ClusterRules: rules,
+		},
+		&olm.Annotations{PackageName: packageName, Channels: s.channels},
+		&olm.Dockerfile{PackageName: packageName, Channels: s.channels},
+	); err != nil {
+		return err
+	}
+
+	if err := s.copyCRDs(); err != nil {
+		return err
+	}
+
+	if err := (&scaffoldv2.Makefile{}).AddBundleTarget(s.config.Fs()); err != nil {
+		return fmt.Errorf("error adding the bundle-build target to the Makefile: %v", err)
+	}
+
+	fmt.Printf(`OLM bundle generated in %s.
+Fill in the TODO(user) fields of the ClusterServiceVersion and run "make bundle-build" to build the bundle image.
+Re-run this command after adding APIs or changing RBAC markers, manual changes to %s are overwritten.
+`, olm.BundleDir, olm.BundleDir)
+
+	return nil
+}
+
+// almExamples returns the samples of the resources as a JSON list
+func (s *bundleScaffolder) almExamples(resources []*resource.Resource) (string, error) {
+	examples := make([]json.RawMessage, 0, len(resources))
+	for _, r := range resources {
+		path, content, err := s.readSample(r)
+		if err != nil {
+			return "", err
+		}
+		if path == "" {
+			continue
+		}
+		example, err := yaml.YAMLToJSON(content)
+		if err != nil {
+			return "", fmt.Errorf("error parsing sample %s: %v", path, err)
+		}
+		examples = append(examples, example)
+	}
+
+	out, err := json.MarshalIndent(examples, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// readSample returns the path and content of the sample of a resource, an empty path if it has none
+func (s *bundleScaffolder) readSample(r *resource.Resource) (string, []byte, error) {
+	for _, path := range scaffoldv2.SamplePaths(r, s.config.MultiGroup) {
+		content, err := afero.ReadFile(s.config.Fs(), path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		return path, content, err
+	}
+	return "", nil, nil
+}
+
+// clusterRules returns the rules of the manager role generated from the RBAC markers
+func (s *bundleScaffolder) clusterRules() (string, error) {
+	path := filepath.Join("config", "rbac", "role.yaml")
+	content, err := afero.ReadFile(s.config.Fs(), path)
+	if os.IsNotExist(err) {
+		fmt.Printf("%s not found, run \"make manifests\" first to include the RBAC rules in the bundle.\n", path)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	role := struct {
+		Rules []interface{} `json:"rules"`
+	}{}
+	if err := yaml.Unmarshal(content, &role); err != nil {
+		return "", fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	if len(role.Rules) == 0 {
+		return "", nil
+	}
+
+	out, err := yaml.Marshal(role.Rules)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// copyCRDs copies the generated CRDs into the bundle manifests
+func (s *bundleScaffolder) copyCRDs() error {
+	crdDir := filepath.Join("config", "crd", "bases")
+	files, err := afero.ReadDir(s.config.Fs(), crdDir)
+	if os.IsNotExist(err) {
+		fmt.Printf("%s not found, run \"make manifests\" first to include the CRDs in the bundle.\n", crdDir)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+		content, err := afero.ReadFile(s.config.Fs(), filepath.Join(crdDir, file.Name()))
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(olm.BundleDir, "manifests", file.Name())
+		if err := afero.WriteFile(s.config.Fs(), path, content, os.ModePerm); err != nil {
+			return fmt.Errorf("error copying %s: %v", path, err)
+		}
+		fmt.Println(path)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("BundleScaffolder", func() {
+	// The package is named after the working directory of the tests
+	csvPath := filepath.Join("bundle", "manifests", "scaffold.clusterserviceversion.yaml")
+
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		fs, c = scaffoldtest.NewProject(nil)
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+	})
+
+	It("should populate the ClusterServiceVersion from the resources of the project and the manager role", func() {
+		files := map[string]string{
+			"config/rbac/role.yaml": "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\n" +
+				"metadata:\n  name: manager-role\nrules:\n- apiGroups:\n  - ship.example.com\n" +
+				"  resources:\n  - frigates\n  verbs:\n  - get\n",
+			"config/crd/bases/ship.example.com_frigates.yaml": "kind: CustomResourceDefinition\n",
+		}
+		for path, content := range files {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0600)).To(Succeed())
+		}
+
+		Expect(scaffold.NewBundleScaffolder(c, "0.1.0", "fleet:v0.1.0", []string{"alpha"}).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, csvPath)
+		Expect(content).To(ContainSubstring("    owned:\n    - name: frigates.ship.example.com\n"))
+		Expect(content).To(ContainSubstring(`"kind": "Frigate"`))
+		Expect(content).To(ContainSubstring("        rules:\n        - apiGroups:\n          - ship.example.com\n"))
+		Expect(content).To(ContainSubstring("                image: fleet:v0.1.0\n"))
+
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("bundle", "manifests", "ship.example.com_frigates.yaml"))).
+			To(Equal(files["config/crd/bases/ship.example.com_frigates.yaml"]))
+		Expect(afero.Exists(fs, filepath.Join("bundle", "metadata", "annotations.yaml"))).To(BeTrue())
+		Expect(afero.Exists(fs, "bundle.Dockerfile")).To(BeTrue())
+		Expect(scaffoldtest.ReadFile(fs, "Makefile")).To(ContainSubstring("\nbundle-build:"))
+	})
+
+	It("should generate the bundle before the manifests are generated", func() {
+		Expect(scaffold.NewBundleScaffolder(c, "0.1.0", "", []string{"alpha"}).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, csvPath)
+		Expect(content).To(ContainSubstring("    - name: frigates.ship.example.com\n"))
+		Expect(content).To(ContainSubstring("        rules: []\n"))
+	})
+
+	It("should regenerate the ClusterServiceVersion after adding an API", func() {
+		Expect(scaffold.NewBundleScaffolder(c, "0.1.0", "", []string{"alpha"}).Scaffold()).To(Succeed())
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer"}
+		Expect(scaffold.NewAPIScaffolder(c, destroyer, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(scaffold.NewBundleScaffolder(c, "0.2.0", "", []string{"alpha"}).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, csvPath)
+		Expect(content).To(ContainSubstring("  name: scaffold.v0.2.0\n"))
+		Expect(content).To(ContainSubstring("    - name: destroyers.ship.example.com\n"))
+	})
+
+	It("should not generate bundles for version 1 projects", func() {
+		c.Version = modelconfig.Version1
+
+		Expect(scaffold.NewBundleScaffolder(c, "0.1.0", "", []string{"alpha"}).Scaffold()).NotTo(Succeed())
+		Expect(afero.Exists(fs, "bundle")).To(BeFalse())
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+// configUpgradeScaffolder rewrites the configuration file with the latest schema
+type configUpgradeScaffolder struct {
+	config *config.Config
+}
+
+func NewConfigUpgradeScaffolder(config *config.Config) Scaffolder {
+	return &configUpgradeScaffolder{
+		config: config,
+	}
+}
+
+func (s *configUpgradeScaffolder) Scaffold() error {
+	previous := s.config.SchemaVersion()
+	if !s.config.Upgrade() {
+		fmt.Printf("%s already uses the latest configuration schema %s\n", s.config.Path(), previous)
+		return nil
+	}
+
+	// The first schema doesn't track what was scaffolded for each resource, so it is found in the project files
+	if previous == config.SchemaV1 {
+		for i, gvk := range s.config.Resources {
+			state, err := s.detectResourceState(gvk)
+			if err != nil {
+				return err
+			}
+			s.config.Resources[i].ResourceState = state
+		}
+	}
+
+	if err := s.config.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgraded %s from the configuration schema %s to %s\n",
+		s.config.Path(), previous, s.config.SchemaVersion())
+	return nil
+}
+
+// detectResourceState finds the controller, webhooks and sample of a resource in the project files
+func (s *configUpgradeScaffolder) detectResourceState(gvk modelconfig.GVK) (modelconfig.ResourceState, error) {
+	fs := s.config.Fs()
+	kind := strings.ToLower(gvk.Kind)
+
+	var apiDir, controllersDir string
+	if s.config.MultiGroup {
+		apiDir = filepath.Join("apis", gvk.Group, gvk.Version)
+		controllersDir = filepath.Join("controllers", gvk.Group)
+	} else {
+		apiDir = filepath.Join("api", gvk.Version)
+		controllersDir = "controllers"
+	}
+
+	state := modelconfig.ResourceState{}
+	var err error
+	if state.Controller, err = afero.Exists(fs, filepath.Join(controllersDir, kind+"_controller.go")); err != nil {
+		return state, err
+	}
+	if state.Webhooks.Conversion, err = afero.Exists(fs, filepath.Join(apiDir, kind+"_conversion.go")); err != nil {
+		return state, err
+	}
+	res := &resource.Resource{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}
+	for _, path := range scaffoldv2.SamplePaths(res, s.config.MultiGroup) {
+		if state.Sample, err = afero.Exists(fs, path); err != nil {
+			return state, err
+		}
+		if state.Sample {
+			break
+		}
+	}
+
+	// The webhook file implements the interfaces of the webhooks that were scaffolded
+	content, err := afero.ReadFile(fs, filepath.Join(apiDir, kind+"_webhook.go"))
+	if err != nil && !os.IsNotExist(err) {
+		return state, err
+	}
+	state.Webhooks.Defaulting = strings.Contains(string(content), "webhook.Defaulter")
+	state.Webhooks.Validation = strings.Contains(string(content), "webhook.Validator")
+
+	return state, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("ConfigUpgradeScaffolder", func() {
+	const legacy = `domain: example.com
+multigroup: true
+repo: example.com/project
+resources:
+- group: ship
+  kind: Frigate
+  version: v1
+version: "2"
+`
+
+	var fs afero.Fs
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		Expect(afero.WriteFile(fs, "PROJECT", []byte(legacy), 0600)).To(Succeed())
+	})
+
+	It("should keep the schema of the file when saving it", func() {
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.SchemaVersion()).To(Equal(config.SchemaV1))
+		Expect(c.MultiGroup).To(BeTrue())
+
+		Expect(c.Save()).To(Succeed())
+		content := scaffoldtest.ReadFile(fs, "PROJECT")
+		Expect(content).To(Equal(legacy))
+	})
+
+	It("should rewrite the file with the latest schema", func() {
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scaffold.NewConfigUpgradeScaffolder(c).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, "PROJECT")
+		Expect(content).To(ContainSubstring("schemaVersion: v2\n"))
+		Expect(content).To(ContainSubstring("multiGroup: true\n"))
+
+		c, err = config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.SchemaVersion()).To(Equal(config.LatestSchema))
+		Expect(c.MultiGroup).To(BeTrue())
+		Expect(c.Resources).To(Equal([]modelconfig.GVK{{Group: "ship", Version: "v1", Kind: "Frigate"}}))
+		Expect(c.Upgrade()).To(BeFalse())
+	})
+
+	It("should find what was scaffolded for the resources when upgrading from the first schema", func() {
+		for path, content := range map[string]string{
+			"apis/ship/v1/frigate_types.go":          "package v1\n",
+			"apis/ship/v1/frigate_webhook.go":        "package v1\n\nvar _ webhook.Validator = &Frigate{}\n",
+			"apis/ship/v1/frigate_conversion.go":     "package v1\n",
+			"controllers/ship/frigate_controller.go": "package ship\n",
+			"config/samples/ship_v1_frigate.yaml":    "kind: Frigate\n",
+		} {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0600)).To(Succeed())
+		}
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scaffold.NewConfigUpgradeScaffolder(c).Scaffold()).To(Succeed())
+
+		c, err = config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		gvk, found := c.GetResource(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"})
+		Expect(found).To(BeTrue())
+		Expect(gvk.ResourceState).To(Equal(modelconfig.ResourceState{
+			Controller: true,
+			Webhooks:   modelconfig.Webhooks{Validation: true, Conversion: true},
+			Sample:     true,
+		}))
+	})
+
+	It("should list the unknown fields", func() {
+		Expect(afero.WriteFile(fs, "PROJECT", []byte(`schemaVersion: v2
+version: "2"
+multigroup: true
+resources:
+- group: ship
+  kind: Frigate
+  version: v1
+  owner: fleet
+`), 0600)).To(Succeed())
+
+		_, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).To(Equal(config.UnknownFieldsError{
+			Path:   "PROJECT",
+			Schema: config.SchemaV2,
+			Fields: []string{"multigroup", "resources[0].owner"},
+		}))
+	})
+
+	It("should reject newer schemas", func() {
+		Expect(afero.WriteFile(fs, "PROJECT", []byte("schemaVersion: v99\nversion: \"2\"\n"), 0600)).
+			To(Succeed())
+
+		_, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).To(Equal(config.UnsupportedSchemaError{Path: "PROJECT", Schema: "v99"}))
+	})
+})
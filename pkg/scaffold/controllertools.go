@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/controller-tools/pkg/deepcopy"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+)
+
+// generator is a controller-tools generator and the directory its configuration artifacts are written to
+type generator struct {
+	genall.Generator
+	// dir is the directory of the configuration artifacts, e.g. the manifests
+	dir string
+}
+
+// generatorOutput writes the artifacts of a generator to the filesystem of the project, the configuration in its
+// directory and the code next to the Go files of the package it is generated for
+type generatorOutput struct {
+	fs  afero.Fs
+	dir string
+	// written collects the paths of the artifacts
+	written *[]string
+}
+
+// Open implements genall.OutputRule
+func (o generatorOutput) Open(pkg *loader.Package, itemPath string) (io.WriteCloser, error) {
+	dir := o.dir
+	if pkg != nil {
+		if len(pkg.CompiledGoFiles) == 0 {
+			return nil, fmt.Errorf("unable to write %s to package %s, which has no Go file", itemPath, pkg.PkgPath)
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		if dir, err = filepath.Rel(wd, filepath.Dir(pkg.CompiledGoFiles[0])); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := o.fs.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, itemPath)
+	*o.written = append(*o.written, path)
+	return o.fs.Create(path)
+}
+
+// runGenerators runs the controller-tools generators in-process over the Go packages of the project in the working
+// directory, like controller-gen with paths=./..., and returns the paths of the artifacts they wrote to fs
+func runGenerators(fs afero.Fs, generators ...generator) ([]string, error) {
+	var written []string
+	gens := make(genall.Generators, 0, len(generators))
+	rules := genall.OutputRules{
+		Default:     genall.OutputToNothing,
+		ByGenerator: make(map[*genall.Generator]genall.OutputRule, len(generators)),
+	}
+	for _, g := range generators {
+		gen := g.Generator
+		gens = append(gens, &gen)
+		rules.ByGenerator[&gen] = generatorOutput{fs: fs, dir: g.dir, written: &written}
+	}
+
+	runtime, err := gens.ForRoots("./...")
+	if err != nil {
+		return nil, fmt.Errorf("error loading the Go packages: %v", err)
+	}
+	runtime.OutputRules = rules
+	// The errors of the generators and of the packages are printed to stderr
+	if failed := runtime.Run(); failed {
+		return written, errors.New("not all generators ran successfully")
+	}
+	return written, nil
+}
+
+// GenerateDeepCopy generates the deep-copy methods of the types of the Go packages of the project in the working
+// directory, like the generate target of the Makefile, and returns the paths of the generated files
+func GenerateDeepCopy(fs afero.Fs) ([]string, error) {
+	return runGenerators(fs, generator{Generator: deepcopy.Generator{HeaderFile: boilerplatePath}})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+// writeProject writes a Go module with the files to the working directory
+func writeProject(files map[string]string) {
+	files["go.mod"] = "module example.com/project\n"
+	for path, content := range files {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+}
+
+var _ = Describe("GenerateDeepCopy", func() {
+	var wd, dir string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-deepcopy")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should generate the deep-copy methods of the types next to them", func() {
+		writeProject(map[string]string{
+			filepath.Join("hack", "boilerplate.go.txt"): "/*\nCopyright 2020 The Project Authors.\n*/\n",
+			filepath.Join("api", "v1", "frigate_types.go"): "// +kubebuilder:object:generate=true\n" +
+				"package v1\n\ntype FrigateSpec struct {\n\tCrew []string `json:\"crew\"`\n}\n",
+		})
+
+		paths, err := scaffold.GenerateDeepCopy(afero.NewOsFs())
+		Expect(err).NotTo(HaveOccurred())
+		generated := filepath.Join("api", "v1", "zz_generated.deepcopy.go")
+		Expect(paths).To(Equal([]string{generated}))
+		content, err := ioutil.ReadFile(generated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("/*\nCopyright 2020 The Project Authors.\n*/\n"))
+		Expect(string(content)).To(ContainSubstring("func (in *FrigateSpec) DeepCopyInto(out *FrigateSpec) {"))
+	})
+
+	It("should fail if the types can't be parsed", func() {
+		writeProject(map[string]string{
+			filepath.Join("hack", "boilerplate.go.txt"): "",
+			filepath.Join("api", "v1", "frigate_types.go"): "// +kubebuilder:object:generate=true\n" +
+				"package v1\n\ntype FrigateSpec struct {\n\tCrew []string\n",
+		})
+
+		_, err := scaffold.GenerateDeepCopy(afero.NewOsFs())
+		Expect(err).To(HaveOccurred())
+	})
+})
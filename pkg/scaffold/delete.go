@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	controllerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/controller"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// deleteAPIScaffolder removes the files scaffolded for an API and un-wires it from the project
+type deleteAPIScaffolder struct {
+	config   *config.Config
+	resource *resource.Resource
+}
+
+func NewDeleteAPIScaffolder(config *config.Config, res *resource.Resource) Scaffolder {
+	return &deleteAPIScaffolder{
+		config:   config,
+		resource: res,
+	}
+}
+
+func (s *deleteAPIScaffolder) Scaffold() error {
+	fmt.Println("Removing scaffold...")
+
+	switch {
+	case s.config.IsV2():
+		return s.scaffoldV2()
+	default:
+		return fmt.Errorf("deleting APIs is not supported for project version %v", s.config.Version)
+	}
+}
+
+func (s *deleteAPIScaffolder) scaffoldV2() error {
+	kind := strings.ToLower(s.resource.Kind)
+
+	var apiDir, controllersDir string
+	if s.config.MultiGroup {
+		apiDir = filepath.Join("apis", s.resource.Group, s.resource.Version)
+		controllersDir = filepath.Join("controllers", s.resource.Group)
+	} else {
+		apiDir = filepath.Join("api", s.resource.Version)
+		controllersDir = "controllers"
+	}
+
+	// An untracked resource is rejected before any file is changed
+	if !s.config.RemoveResource(s.resource) {
+		return fmt.Errorf("resource %s/%s, Kind=%s is not tracked in the project configuration",
+			s.resource.Group, s.resource.Version, s.resource.Kind)
+	}
+
+	// Un-wire the resource before removing the files so that the removed code fragments are computed
+	// with the same information that was used to insert them
+	if err := s.unwire(apiDir, controllersDir); err != nil {
+		return err
+	}
+
+	if err := s.config.Save(); err != nil {
+		return fmt.Errorf("error updating project file with resource information : %v", err)
+	}
+
+	paths := []string{
+		filepath.Join(apiDir, fmt.Sprintf("%s_types.go", kind)),
+		filepath.Join(apiDir, fmt.Sprintf("%s_applyconfiguration.go", kind)),
+		filepath.Join(apiDir, fmt.Sprintf("%s_webhook.go", kind)),
+		filepath.Join(apiDir, fmt.Sprintf("%s_webhook_test.go", kind)),
+		filepath.Join(controllersDir, fmt.Sprintf("%s_controller.go", kind)),
+		filepath.Join(controllersDir, fmt.Sprintf("%s_controller_test.go", kind)),
+		filepath.Join(controllersDir, fmt.Sprintf("%s_metrics.go", kind)),
+		filepath.Join("test", "e2e", fmt.Sprintf("%s_%s_%s_test.go",
+			s.resource.Group, s.resource.Version, kind)),
+		filepath.Join("config", "rbac", fmt.Sprintf("%s_editor_role.yaml", kind)),
+		filepath.Join("config", "rbac", fmt.Sprintf("%s_viewer_role.yaml", kind)),
+		filepath.Join("config", "crd", "bases", fmt.Sprintf("%s_%s.yaml",
+			s.resource.QualifiedGroup(s.config.Domain), s.resource.Resource)),
+		filepath.Join("config", "crd", "patches", fmt.Sprintf("webhook_in_%s.yaml", s.resource.Resource)),
+		filepath.Join("config", "crd", "patches", fmt.Sprintf("cainjection_in_%s.yaml", s.resource.Resource)),
+	}
+	paths = append(paths, scaffoldv2.SamplePaths(s.resource, s.config.MultiGroup)...)
+	// The samples of the groups of multigroup projects are listed by the kustomization of their directory
+	if s.config.MultiGroup && s.resource.Group != "" && !s.groupHasOtherResources() {
+		paths = append(paths, filepath.Join("config", "samples", s.resource.Group, "kustomization.yaml"))
+	}
+	// The group-version files are shared with the rest of the kinds in the same group and version
+	if !s.config.HasGroupVersion(s.resource.Group, s.resource.Version) {
+		paths = append(paths,
+			filepath.Join(apiDir, "groupversion_info.go"),
+			filepath.Join(apiDir, "condition_types.go"),
+			filepath.Join(apiDir, "register.go"),
+			filepath.Join(apiDir, "webhook_suite_test.go"),
+		)
+		if s.config.MultiGroup {
+			paths = append(paths, filepath.Join("apis", fmt.Sprintf("addtoscheme_%s_%s.go",
+				s.resource.GroupImportSafe, s.resource.Version)))
+		}
+	}
+
+	m, err := readManifest(s.config.Fs())
+	if err != nil {
+		return err
+	}
+	forgotten := false
+	for _, path := range paths {
+		// The merge base and manifest entry recorded when the file was scaffolded are not needed anymore
+		if err := s.config.Fs().Remove(mergeBasePath(path)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing %s: %v", mergeBasePath(path), err)
+		}
+		if m.remove(path) {
+			forgotten = true
+		}
+		if err := s.config.Fs().Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("error removing %s: %v", path, err)
+		}
+		fmt.Println(path)
+	}
+	if forgotten {
+		return m.write(s.config.Fs())
+	}
+
+	return nil
+}
+
+// unwire removes the code fragments that were added to the project files when the API was created
+func (s *deleteAPIScaffolder) unwire(apiDir, controllersDir string) error {
+	// The scheme registration is shared with the rest of the kinds in the same group and version
+	lastInGroupVersion := true
+	for _, r := range s.config.Resources {
+		if r.Group == s.resource.Group && r.Version == s.resource.Version && r.Kind != s.resource.Kind {
+			lastInGroupVersion = false
+			break
+		}
+	}
+
+	if err := (&scaffoldv2.Main{}).Remove(
+		&scaffoldv2.MainUpdateOptions{
+			Config:         &s.config.Config,
+			WireResource:   lastInGroupVersion,
+			WireController: true,
+			WireWebhook:    true,
+			Resource:       s.resource,
+			Fs:             s.config.Fs(),
+		},
+	); err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+
+	if err := (&crdv2.Kustomization{
+		Input:    input.Input{Domain: s.config.Domain},
+		Resource: s.resource,
+	}).Remove(s.config.Fs()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error updating kustomization.yaml: %v", err)
+	}
+
+	// The directory of the group is listed by the samples kustomization of multigroup projects until its last kind
+	groupDir := s.config.MultiGroup && s.resource.Group != ""
+	samplesKustomizations := []*scaffoldv2.SamplesKustomization{}
+	if !groupDir || !s.groupHasOtherResources() {
+		samplesKustomizations = append(samplesKustomizations, &scaffoldv2.SamplesKustomization{})
+	}
+	if groupDir {
+		samplesKustomizations = append(samplesKustomizations, &scaffoldv2.SamplesKustomization{Group: s.resource.Group})
+	}
+	for _, f := range samplesKustomizations {
+		f.MultiGroup = s.config.MultiGroup
+		if err := f.Remove(s.config.Fs(), s.resource); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error updating the kustomization of the samples: %v", err)
+		}
+	}
+
+	if err := (&webhookv2.SuiteTest{
+		Input:    input.Input{Path: filepath.Join(apiDir, "webhook_suite_test.go")},
+		Resource: s.resource,
+	}).Remove(s.config.Fs()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error updating webhook_suite_test.go under %s: %v", apiDir, err)
+	}
+
+	if lastInGroupVersion {
+		suiteTestFile := &controllerv2.SuiteTest{
+			Input: input.Input{
+				Path:       filepath.Join(controllersDir, "suite_test.go"),
+				Repo:       s.config.Repo,
+				Domain:     s.config.Domain,
+				MultiGroup: s.config.MultiGroup,
+			},
+			Resource: s.resource,
+		}
+		if err := suiteTestFile.Remove(s.config.Fs()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// groupHasOtherResources returns whether other resources of the group of the resource are tracked, in any version
+func (s *deleteAPIScaffolder) groupHasOtherResources() bool {
+	for _, r := range s.config.Resources {
+		if r.Group == s.resource.Group && (r.Version != s.resource.Version || r.Kind != s.resource.Kind) {
+			return true
+		}
+	}
+	return false
+}
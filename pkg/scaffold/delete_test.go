@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("DeleteAPIScaffolder", func() {
+	var (
+		fs      afero.Fs
+		c       *config.Config
+		frigate *resource.Resource
+		main    string
+	)
+
+	BeforeEach(func() {
+		fs, c = scaffoldtest.NewProject(nil)
+		frigate = &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		main = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(main).To(ContainSubstring("controllers.FrigateReconciler{"))
+	})
+
+	It("should not change any file if the resource is not tracked", func() {
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer"}
+		Expect(scaffold.NewDeleteAPIScaffolder(c, destroyer).Scaffold()).
+			To(MatchError(ContainSubstring("is not tracked in the project configuration")))
+
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(Equal(main))
+	})
+
+	It("should leave the project untouched if a file fails to be removed", func() {
+		controller := filepath.Join("controllers", "frigate_controller.go")
+		c.SetFs(failingFs{Fs: fs, path: controller, remove: true})
+		Expect(scaffold.NewStagedScaffolder(c, scaffold.NewDeleteAPIScaffolder(c, frigate)).Scaffold()).
+			To(MatchError(ContainSubstring("failed to remove " + controller)))
+
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(Equal(main))
+		Expect(afero.Exists(fs, controller)).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "frigate_types.go"))).To(BeTrue())
+		saved, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved.HasResource(frigate)).To(BeTrue())
+	})
+
+	It("should remove the files and un-wire the resource at once", func() {
+		Expect(scaffold.NewStagedScaffolder(c, scaffold.NewDeleteAPIScaffolder(c, frigate)).Scaffold()).To(Succeed())
+
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).NotTo(ContainSubstring("FrigateReconciler"))
+		Expect(afero.Exists(fs, filepath.Join("controllers", "frigate_controller.go"))).To(BeFalse())
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "frigate_types.go"))).To(BeFalse())
+		saved, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved.HasResource(frigate)).To(BeFalse())
+	})
+})
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+)
+
+// docsScaffolder generates the API reference of the tracked resources from their Go types
+type docsScaffolder struct {
+	config *config.Config
+	// format is either docs.FormatMarkdown or docs.FormatHTML
+	format string
+	// dir is the directory where the reference is generated
+	dir string
+}
+
+func NewDocsScaffolder(config *config.Config, format, dir string) Scaffolder {
+	if dir == "" {
+		dir = docs.DefaultDir
+	}
+	return &docsScaffolder{
+		config: config,
+		format: format,
+		dir:    dir,
+	}
+}
+
+func (s *docsScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("API reference generation is not supported for project version %v", s.config.Version)
+	}
+
+	// The tracked resources are documented, so that newly created APIs are included automatically
+	kindDocs := make([]*docs.KindDoc, 0, len(s.config.Resources))
+	files := make([]input.File, 0, len(s.config.Resources)+1)
+	for _, gvk := range s.config.Resources {
+		r := &resource.Resource{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Resource: gvk.Plural}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("invalid resource %s/%s, Kind=%s: %v", gvk.Group, gvk.Version, gvk.Kind, err)
+		}
+
+		_, groupDomain := util.GetResourceInfo(r, s.config.Repo, s.config.Domain, s.config.MultiGroup)
+		kindDoc, err := docs.LoadKind(s.config.Fs(), s.apiDir(r), r.Group, r.Version, r.Kind,
+			fmt.Sprintf("%s/%s", groupDomain, r.Version))
+		if err != nil {
+			return fmt.Errorf("error reading the types of %s: %v", r.Kind, err)
+		}
+		kindDocs = append(kindDocs, kindDoc)
+		files = append(files, &docs.Reference{Dir: s.dir, Format: s.format, Doc: kindDoc})
+	}
+	files = append(files, &docs.Index{Dir: s.dir, Format: s.format, Docs: kindDocs})
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(s.config.Fs(), boilerplatePath),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := (&Scaffold{Fs: s.config.Fs()}).Execute(universe, input.Options{}, files...); err != nil {
+		return err
+	}
+
+	fmt.Printf("API reference generated in %s.\n"+
+		"Re-run this command after changing or adding APIs, manual changes to %s are overwritten.\n", s.dir, s.dir)
+
+	return nil
+}
+
+// apiDir returns the directory of the Go package of the API version of the resource
+func (s *docsScaffolder) apiDir(r *resource.Resource) string {
+	if s.config.MultiGroup {
+		return filepath.Join("apis", r.Group, r.Version)
+	}
+	return filepath.Join("api", r.Version)
+}
@@ -0,0 +1,573 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/afero"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// DryRunFs is a filesystem that keeps every write and removal in memory on top of a base filesystem,
+// so that the result of scaffolding can be previewed without touching the project, or
+// committed at once after it succeeded so that a failure doesn't leave it half scaffolded
+type DryRunFs struct {
+	afero.Fs
+
+	base afero.Fs
+	// layer is the memory filesystem that holds the written files
+	layer afero.Fs
+	// written tracks the paths that were opened for writing
+	written map[string]struct{}
+	// removed tracks the paths of the base filesystem that were removed, which are hidden from the reads
+	removed map[string]struct{}
+}
+
+var _ afero.Fs = &DryRunFs{}
+
+// NewDryRunFs returns a filesystem that reads from base and writes to memory
+func NewDryRunFs(base afero.Fs) *DryRunFs {
+	layer := afero.NewMemMapFs()
+	return &DryRunFs{
+		Fs:      afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(base), layer),
+		base:    base,
+		layer:   layer,
+		written: make(map[string]struct{}),
+		removed: make(map[string]struct{}),
+	}
+}
+
+// Create implements afero.Fs
+func (fs *DryRunFs) Create(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0666)
+}
+
+// OpenFile implements afero.Fs
+func (fs *DryRunFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	name = filepath.Clean(name)
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) == 0 {
+		if fs.isRemoved(name) {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return fs.openFile(name)
+	}
+
+	fs.written[name] = struct{}{}
+	// A removed file is written from scratch instead of from its content in the base filesystem
+	if fs.isRemoved(name) {
+		if flag&os.O_CREATE == 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		fs.restore(name)
+		if err := fs.layer.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			return nil, err
+		}
+		return fs.layer.OpenFile(name, flag, perm)
+	}
+	fs.restore(name)
+	return fs.Fs.OpenFile(name, flag, perm)
+}
+
+// Open implements afero.Fs
+func (fs *DryRunFs) Open(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// openFile opens the file for reading, hiding the removed files from the listings of directories. The directories
+// are opened with Open, as only it lists the files of both the memory and the base filesystem.
+func (fs *DryRunFs) openFile(name string) (afero.File, error) {
+	f, err := fs.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &dryRunFile{File: f, fs: fs, dir: name}, nil
+}
+
+// Stat implements afero.Fs
+func (fs *DryRunFs) Stat(name string) (os.FileInfo, error) {
+	name = filepath.Clean(name)
+	if fs.isRemoved(name) {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return fs.Fs.Stat(name)
+}
+
+// Mkdir implements afero.Fs
+func (fs *DryRunFs) Mkdir(name string, perm os.FileMode) error {
+	name = filepath.Clean(name)
+	fs.restore(name)
+	return fs.Fs.Mkdir(name, perm)
+}
+
+// MkdirAll implements afero.Fs
+func (fs *DryRunFs) MkdirAll(name string, perm os.FileMode) error {
+	name = filepath.Clean(name)
+	fs.restore(name)
+	return fs.Fs.MkdirAll(name, perm)
+}
+
+// Remove implements afero.Fs
+func (fs *DryRunFs) Remove(name string) error {
+	name = filepath.Clean(name)
+	info, err := fs.Stat(name)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	if info.IsDir() {
+		if empty, err := afero.IsEmpty(fs, name); err != nil {
+			return err
+		} else if !empty {
+			return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+	return fs.remove(name)
+}
+
+// RemoveAll implements afero.Fs
+func (fs *DryRunFs) RemoveAll(path string) error {
+	path = filepath.Clean(path)
+	if _, err := fs.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	var paths []string
+	if err := afero.Walk(fs, path, func(p string, _ os.FileInfo, err error) error {
+		paths = append(paths, p)
+		return err
+	}); err != nil {
+		return err
+	}
+	// The files are removed before their directories
+	for i := len(paths) - 1; i >= 0; i-- {
+		if err := fs.remove(paths[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rename implements afero.Fs, moving the files of a directory one after the other
+func (fs *DryRunFs) Rename(oldname, newname string) error {
+	oldname, newname = filepath.Clean(oldname), filepath.Clean(newname)
+	if _, err := fs.Stat(oldname); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrNotExist}
+	}
+	if strings.HasPrefix(newname, oldname+string(filepath.Separator)) {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EINVAL}
+	}
+
+	err := afero.Walk(fs, oldname, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(oldname, path)
+		if err != nil {
+			return err
+		}
+		newPath := filepath.Join(newname, rel)
+		if info.IsDir() {
+			return fs.MkdirAll(newPath, info.Mode().Perm())
+		}
+
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		return afero.WriteFile(fs, newPath, content, info.Mode())
+	})
+	if err != nil {
+		return err
+	}
+	return fs.RemoveAll(oldname)
+}
+
+// remove removes the file or the empty directory at the cleaned path from the memory, and hides it if it is
+// in the base filesystem
+func (fs *DryRunFs) remove(name string) error {
+	if err := fs.layer.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(fs.written, name)
+
+	if _, err := fs.base.Stat(name); err == nil {
+		fs.removed[name] = struct{}{}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// isRemoved returns whether the cleaned path was removed
+func (fs *DryRunFs) isRemoved(name string) bool {
+	_, found := fs.removed[name]
+	return found
+}
+
+// restore makes the cleaned path and its parent directories visible again, as they are written
+func (fs *DryRunFs) restore(name string) {
+	for path := name; ; path = filepath.Dir(path) {
+		delete(fs.removed, path)
+		if parent := filepath.Dir(path); parent == path {
+			return
+		}
+	}
+}
+
+// dryRunFile is a file of a DryRunFs, whose directory listings leave out the removed files
+type dryRunFile struct {
+	afero.File
+
+	fs  *DryRunFs
+	dir string
+}
+
+// Readdir implements afero.File
+func (f *dryRunFile) Readdir(count int) ([]os.FileInfo, error) {
+	infos, err := f.File.Readdir(count)
+	result := infos[:0]
+	for _, info := range infos {
+		if !f.fs.isRemoved(filepath.Join(f.dir, info.Name())) {
+			result = append(result, info)
+		}
+	}
+	return result, err
+}
+
+// Readdirnames implements afero.File
+func (f *dryRunFile) Readdirnames(n int) ([]string, error) {
+	names, err := f.File.Readdirnames(n)
+	result := names[:0]
+	for _, name := range names {
+		if !f.fs.isRemoved(filepath.Join(f.dir, name)) {
+			result = append(result, name)
+		}
+	}
+	return result, err
+}
+
+// Diff writes a unified diff for every file whose content would change or that would be removed
+func (fs *DryRunFs) Diff(w io.Writer) error {
+	paths := make([]string, 0, len(fs.written)+len(fs.removed))
+	for _, changed := range []map[string]struct{}{fs.written, fs.removed} {
+		for path := range changed {
+			// The recorded merge bases and manifest are not part of the project itself
+			if strings.HasPrefix(path, mergeBaseDir+string(filepath.Separator)) || path == manifestPath {
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		newPath := "b/" + path
+		var newContent []byte
+		if fs.isRemoved(path) {
+			newPath = os.DevNull
+			if info, err := fs.base.Stat(path); err != nil {
+				return err
+			} else if info.IsDir() {
+				continue
+			}
+		} else {
+			var err error
+			if newContent, err = afero.ReadFile(fs.Fs, path); err != nil {
+				return err
+			}
+		}
+
+		oldPath := "a/" + path
+		oldContent, err := afero.ReadFile(fs.base, path)
+		if os.IsNotExist(err) {
+			oldPath = os.DevNull
+		} else if err != nil {
+			return err
+		}
+
+		if bytes.Equal(oldContent, newContent) && newPath != os.DevNull {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldPath, newPath); err != nil {
+			return err
+		}
+		if err := writeHunks(w, splitLines(oldContent), splitLines(newContent)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Commit writes every file that was written in memory to the base filesystem, and removes the ones that were
+// removed. The files are changed one after the other, so if changing one of them fails the ones changed before are
+// restored to their previous content, or removed if they didn't exist, leaving the base filesystem as it was but
+// for the directories that were created.
+func (fs *DryRunFs) Commit() error {
+	paths := make([]string, 0, len(fs.written))
+	for path := range fs.written {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	removed := make([]string, 0, len(fs.removed))
+	for path := range fs.removed {
+		removed = append(removed, path)
+	}
+	// The files are removed before their directories
+	sort.Sort(sort.Reverse(sort.StringSlice(removed)))
+
+	committed := make([]baseFile, 0, len(paths)+len(removed))
+	for _, path := range paths {
+		previous, err := readBaseFile(fs.base, path)
+		if err != nil {
+			return fs.rollback(committed, err)
+		}
+		committed = append(committed, previous)
+
+		if err := fs.commitFile(path); err != nil {
+			return fs.rollback(committed, fmt.Errorf("failed to write %s: %v", path, err))
+		}
+	}
+	for _, path := range removed {
+		previous, err := readBaseFile(fs.base, path)
+		if err != nil {
+			return fs.rollback(committed, err)
+		}
+		committed = append(committed, previous)
+
+		if err := fs.base.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fs.rollback(committed, fmt.Errorf("failed to remove %s: %v", path, err))
+		}
+	}
+
+	fs.written = make(map[string]struct{})
+	fs.removed = make(map[string]struct{})
+	return nil
+}
+
+// commitFile writes the file at path of the memory to the base filesystem
+func (fs *DryRunFs) commitFile(path string) error {
+	info, err := fs.Fs.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := afero.ReadFile(fs.Fs, path)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.base.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs.base, path, content, info.Mode())
+}
+
+// rollback restores the committed files of the base filesystem after the commit failed with err
+func (fs *DryRunFs) rollback(committed []baseFile, err error) error {
+	for i := len(committed) - 1; i >= 0; i-- {
+		if restoreErr := committed[i].restore(fs.base); restoreErr != nil {
+			return fmt.Errorf("%v, and restoring %s failed, the files may be partially written: %v",
+				err, committed[i].path, restoreErr)
+		}
+	}
+	return fmt.Errorf("%v, no file was written", err)
+}
+
+// baseFile is a file or a directory of the base filesystem as it was before committing
+type baseFile struct {
+	path    string
+	content []byte
+	mode    os.FileMode
+	// exists is false for the files that are created by the commit
+	exists bool
+	dir    bool
+}
+
+func readBaseFile(fs afero.Fs, path string) (baseFile, error) {
+	info, err := fs.Stat(path)
+	if os.IsNotExist(err) {
+		return baseFile{path: path}, nil
+	}
+	if err != nil {
+		return baseFile{}, err
+	}
+	if info.IsDir() {
+		return baseFile{path: path, mode: info.Mode().Perm(), exists: true, dir: true}, nil
+	}
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return baseFile{}, err
+	}
+	return baseFile{path: path, content: content, mode: info.Mode(), exists: true}, nil
+}
+
+// restore writes back the previous content of the file, or removes it if it didn't exist
+func (f baseFile) restore(fs afero.Fs) error {
+	if !f.exists {
+		if err := fs.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if f.dir {
+		return fs.MkdirAll(f.path, f.mode)
+	}
+	return afero.WriteFile(fs, f.path, f.content, f.mode)
+}
+
+// diffLine is a line of a diff, prefixed by ' ', '-' or '+'
+type diffLine struct {
+	op   byte
+	text string
+	// oldLine and newLine are the 1-based positions of the line in each file
+	oldLine, newLine int
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	parts := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+	lines := make([]string, 0, len(parts))
+	for _, part := range parts {
+		lines = append(lines, string(part))
+	}
+	return lines
+}
+
+// diffLines computes the line-based edit script between a and b using their longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{op: ' ', text: a[i], oldLine: i + 1, newLine: j + 1})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{op: '-', text: a[i], oldLine: i + 1, newLine: j})
+			i++
+		default:
+			lines = append(lines, diffLine{op: '+', text: b[j], oldLine: i, newLine: j + 1})
+			j++
+		}
+	}
+
+	return lines
+}
+
+// writeHunks writes the changes between a and b grouped in hunks with diffContext lines of context
+func writeHunks(w io.Writer, a, b []string) error {
+	lines := diffLines(a, b)
+
+	for start := 0; start < len(lines); {
+		// Find the next change
+		for start < len(lines) && lines[start].op == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk while the changes are close enough to share their context
+		end := start
+		for unchanged := 0; end < len(lines) && unchanged <= 2*diffContext; end++ {
+			if lines[end].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for end > start && lines[end-1].op == ' ' {
+			end--
+		}
+
+		first := start - diffContext
+		if first < 0 {
+			first = 0
+		}
+		last := end + diffContext
+		if last > len(lines) {
+			last = len(lines)
+		}
+
+		if err := writeHunk(w, lines[first:last]); err != nil {
+			return err
+		}
+		start = last
+	}
+
+	return nil
+}
+
+func writeHunk(w io.Writer, lines []diffLine) error {
+	var oldCount, newCount int
+	for _, line := range lines {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+	}
+	oldStart, newStart := lines[0].oldLine, lines[0].newLine
+	if lines[0].op == '+' && oldCount != 0 {
+		oldStart++
+	}
+	if lines[0].op == '-' && newCount != 0 {
+		newStart++
+	}
+
+	if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(w, "%c%s\n", line.op, line.text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("DryRunFs", func() {
+	var (
+		base afero.Fs
+		fs   *scaffold.DryRunFs
+	)
+
+	BeforeEach(func() {
+		base = afero.NewMemMapFs()
+		Expect(afero.WriteFile(base, "main.go", []byte("a\nb\nc\nd\ne\nf\ng\nh\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(base, "PROJECT", []byte("version: \"2\"\n"), 0600)).To(Succeed())
+		fs = scaffold.NewDryRunFs(base)
+	})
+
+	It("should not modify the base filesystem", func() {
+		Expect(afero.WriteFile(fs, "main.go", []byte("a\n"), 0600)).To(Succeed())
+		Expect(fs.MkdirAll("api/v1", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs, "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+
+		content := scaffoldtest.ReadFile(base, "main.go")
+		Expect(content).To(Equal("a\nb\nc\nd\ne\nf\ng\nh\n"))
+		Expect(afero.Exists(base, "api/v1/types.go")).To(BeFalse())
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(Equal("a\n"))
+
+		// The directories list the files of both filesystems
+		infos, err := afero.ReadDir(fs, ".")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infos).To(HaveLen(3))
+	})
+
+	It("should write the changed files to the base filesystem on commit", func() {
+		Expect(afero.WriteFile(fs, "main.go", []byte("a\n"), 0600)).To(Succeed())
+		Expect(fs.MkdirAll("api/v1", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs, "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+
+		Expect(fs.Commit()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(base, "main.go")
+		Expect(content).To(Equal("a\n"))
+		content = scaffoldtest.ReadFile(base, "api/v1/types.go")
+		Expect(content).To(Equal("package v1\n"))
+		content = scaffoldtest.ReadFile(base, "PROJECT")
+		Expect(content).To(Equal("version: \"2\"\n"))
+	})
+
+	It("should restore the files written before one that fails to be committed", func() {
+		fs = scaffold.NewDryRunFs(failingFs{Fs: base, path: "config/manager.yaml"})
+		Expect(fs.MkdirAll("api/v1", 0700)).To(Succeed())
+		Expect(fs.MkdirAll("config", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs, "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs, "config/manager.yaml", []byte("kind: Deployment\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs, "PROJECT", []byte("version: \"3\"\n"), 0600)).To(Succeed())
+
+		err := fs.Commit()
+		Expect(err).To(MatchError(ContainSubstring("failed to write config/manager.yaml")))
+		Expect(err).To(MatchError(ContainSubstring("no file was written")))
+
+		Expect(scaffoldtest.ReadFile(base, "PROJECT")).To(Equal("version: \"2\"\n"))
+		Expect(afero.Exists(base, "api/v1/types.go")).To(BeFalse())
+		Expect(afero.Exists(base, "config/manager.yaml")).To(BeFalse())
+	})
+
+	It("should hide the removed and renamed files until they are committed", func() {
+		Expect(afero.WriteFile(base, "api/v1/frigate_types.go", []byte("package v1\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(base, "api/v1/groupversion_info.go", []byte("package v1\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(base, "controllers/frigate_controller.go", []byte("package controllers\n"), 0600)).
+			To(Succeed())
+
+		Expect(fs.Remove("main.go")).To(Succeed())
+		Expect(fs.RemoveAll("api")).To(Succeed())
+		Expect(fs.Rename("controllers", filepath.Join("controllers", "ship"))).NotTo(Succeed())
+		Expect(fs.MkdirAll(filepath.Join("controllers", "ship"), 0700)).To(Succeed())
+		Expect(fs.Rename(filepath.Join("controllers", "frigate_controller.go"),
+			filepath.Join("controllers", "ship", "frigate_controller.go"))).To(Succeed())
+
+		Expect(afero.Exists(fs, "main.go")).To(BeFalse())
+		Expect(afero.Exists(fs, "api")).To(BeFalse())
+		Expect(afero.Exists(fs, "api/v1/frigate_types.go")).To(BeFalse())
+		infos, err := afero.ReadDir(fs, "controllers")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infos).To(HaveLen(1))
+		Expect(infos[0].Name()).To(Equal("ship"))
+		Expect(afero.Exists(base, "main.go")).To(BeTrue())
+		Expect(afero.Exists(base, "api/v1/frigate_types.go")).To(BeTrue())
+
+		Expect(fs.Commit()).To(Succeed())
+
+		Expect(afero.Exists(base, "main.go")).To(BeFalse())
+		Expect(afero.Exists(base, "api")).To(BeFalse())
+		Expect(afero.Exists(base, "controllers/frigate_controller.go")).To(BeFalse())
+		Expect(scaffoldtest.ReadFile(base, "controllers/ship/frigate_controller.go")).To(Equal("package controllers\n"))
+	})
+
+	It("should write a removed file from scratch", func() {
+		Expect(fs.Remove("main.go")).To(Succeed())
+		f, err := fs.OpenFile("main.go", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write([]byte("z\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		Expect(fs.Commit()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(base, "main.go")).To(Equal("z\n"))
+	})
+
+	It("should restore the files removed before one that fails to be removed", func() {
+		fs = scaffold.NewDryRunFs(failingFs{Fs: base, path: "PROJECT", remove: true})
+		Expect(fs.MkdirAll("api/v1", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs, "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+		Expect(fs.Remove("main.go")).To(Succeed())
+		Expect(fs.Remove("PROJECT")).To(Succeed())
+
+		err := fs.Commit()
+		Expect(err).To(MatchError(ContainSubstring("failed to remove PROJECT")))
+		Expect(err).To(MatchError(ContainSubstring("no file was written")))
+
+		Expect(scaffoldtest.ReadFile(base, "main.go")).To(Equal("a\nb\nc\nd\ne\nf\ng\nh\n"))
+		Expect(scaffoldtest.ReadFile(base, "PROJECT")).To(Equal("version: \"2\"\n"))
+		Expect(afero.Exists(base, "api/v1/types.go")).To(BeFalse())
+	})
+
+	It("should print the removed files in the diff", func() {
+		Expect(fs.Remove("PROJECT")).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(fs.Diff(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- a/PROJECT
++++ /dev/null
+@@ -1,1 +0,0 @@
+-version: "2"
+`))
+	})
+
+	It("should print a unified diff of the changed files", func() {
+		Expect(afero.WriteFile(fs, "main.go", []byte("a\nb\nc\nd\nx\ne\nf\ng\nh\n"), 0600)).To(Succeed())
+		Expect(fs.MkdirAll("api/v1", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs, "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs, "PROJECT", []byte("version: \"2\"\n"), 0600)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(fs.Diff(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- /dev/null
++++ b/api/v1/types.go
+@@ -0,0 +1,1 @@
++package v1
+--- a/main.go
++++ b/main.go
+@@ -2,6 +2,7 @@
+ b
+ c
+ d
++x
+ e
+ f
+ g
+`))
+	})
+})
+
+// failingFs fails to open the file at path for writing, or to remove it if remove is set
+type failingFs struct {
+	afero.Fs
+	path   string
+	remove bool
+}
+
+func (fs failingFs) Remove(name string) error {
+	if name == fs.path && fs.remove {
+		return errors.New("permission denied")
+	}
+	return fs.Fs.Remove(name)
+}
+
+func (fs failingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if name == fs.path && !fs.remove && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		return nil, errors.New("no space left on device")
+	}
+	return fs.Fs.OpenFile(name, flag, perm)
+}
@@ -17,23 +17,262 @@ limitations under the License.
 package scaffold
 
 import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
 	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	managerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+	networkpolicyv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/networkpolicy"
+	webhookcav2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhookca"
 )
 
 type editScaffolder struct {
 	config     *config.Config
 	multigroup bool
+	// deploy is the deployment method, it is kept unchanged if empty
+	deploy string
+	// components are the kustomize components to enable in the default overlay
+	components []string
+	// clientGen enables the generation of typed clientsets, listers and informers, it is kept unchanged if false
+	clientGen bool
 }
 
-func NewEditScaffolder(config *config.Config, multigroup bool) Scaffolder {
+func NewEditScaffolder(
+	config *config.Config,
+	multigroup bool,
+	deploy string,
+	components []string,
+	clientGen bool,
+) Scaffolder {
 	return &editScaffolder{
 		config:     config,
 		multigroup: multigroup,
+		deploy:     deploy,
+		components: components,
+		clientGen:  clientGen,
 	}
 }
 
 func (s *editScaffolder) Scaffold() error {
+	// Projects that enable the multigroup layout have their API packages moved to it
+	if s.config.IsV2() && !s.config.MultiGroup && s.multigroup {
+		fmt.Println("Moving the API packages to the multigroup layout...")
+		migration, err := newMultiGroupMigration(s.config)
+		if err != nil {
+			return err
+		}
+		if err := migration.migrate(); err != nil {
+			return fmt.Errorf("error moving the API packages to the multigroup layout: %v", err)
+		}
+	}
+
+	if len(s.components) != 0 {
+		// The certificate is issued for the webhook server, so certmanager requires the webhook component
+		components := s.components
+		certified := hasString(components, scaffoldv2.ComponentCertManager) ||
+			hasString(components, scaffoldv2.ComponentWebhookCA)
+		if certified && !hasString(components, scaffoldv2.ComponentWebhook) {
+			components = append([]string{scaffoldv2.ComponentWebhook}, components...)
+		}
+		// The dashboards and the alerts are built on the metrics scraped by the prometheus component
+		observability := hasString(components, scaffoldv2.ComponentObservability)
+		if observability && !hasString(components, scaffoldv2.ComponentPrometheus) {
+			components = append([]string{scaffoldv2.ComponentPrometheus}, components...)
+		}
+		if hasString(components, scaffoldv2.ComponentProduction) {
+			if err := scaffoldComponent(s.config.Fs(), &s.config.Config, scaffoldv2.ComponentProduction,
+				&managerv2.PriorityClass{},
+				&managerv2.PodDisruptionBudget{},
+				&managerv2.ProductionPatch{},
+			); err != nil {
+				return err
+			}
+		}
+		if hasString(components, scaffoldv2.ComponentWebhookCA) {
+			if err := scaffoldComponent(s.config.Fs(), &s.config.Config, scaffoldv2.ComponentWebhookCA,
+				&webhookcav2.CertGenJob{},
+				&webhookcav2.CertGenRBAC{},
+				&webhookcav2.ManagerCertPatch{},
+			); err != nil {
+				return err
+			}
+		}
+		if hasString(components, scaffoldv2.ComponentNetworkPolicy) {
+			if err := scaffoldComponent(s.config.Fs(), &s.config.Config, scaffoldv2.ComponentNetworkPolicy,
+				&networkpolicyv2.WebhookIngress{},
+				&networkpolicyv2.APIServerEgress{},
+			); err != nil {
+				return err
+			}
+		}
+		if observability {
+			if err := scaffoldComponent(s.config.Fs(), &s.config.Config, scaffoldv2.ComponentObservability,
+				observabilityFiles()...,
+			); err != nil {
+				return err
+			}
+		}
+		kustomizeFile := &scaffoldv2.Kustomize{}
+		if err := kustomizeFile.EnableComponents(s.config.Fs(), components...); err != nil {
+			return err
+		}
+		fmt.Printf("Enabled %s in %s\n", strings.Join(components, ", "), kustomizeFile.Path)
+
+		// The next webhooks are created with the same certificate provider
+		if hasString(components, scaffoldv2.ComponentWebhookCA) {
+			s.config.CertProvider = modelconfig.CertProviderWebhookCA
+		}
+	}
+
 	s.config.MultiGroup = s.multigroup
+	if s.clientGen && !s.config.ClientGen {
+		if err := s.enableClientGen(); err != nil {
+			return fmt.Errorf("error enabling the generation of typed clients: %v", err)
+		}
+		s.config.ClientGen = true
+	}
+
+	// The Helm chart scaffolder records the helm deployment method once the chart has been scaffolded
+	if s.deploy == modelconfig.DeployKustomize {
+		s.config.Deploy = ""
+	}
 
 	return s.config.Save()
 }
+
+// enableClientGen marks the Kinds of the project for the generators of k8s.io/code-generator and scaffolds the
+// script and the make target that run them
+func (s *editScaffolder) enableClientGen() error {
+	fs := s.config.Fs()
+	c := s.config.Config
+
+	// The templates read the layout of the project from the project file, which may have just been migrated to
+	// the multigroup layout
+	if err := s.config.Save(); err != nil {
+		return err
+	}
+
+	files := []input.File{&scaffoldv2.UpdateCodegen{}}
+	for _, gvk := range c.Resources {
+		r := &resource.Resource{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Resource: gvk.Plural,
+			GroupPackage: c.GroupPackage(gvk.Group)}
+		apiDir := filepath.Join("api", r.Version)
+		if c.MultiGroup {
+			apiDir = filepath.Join("apis", r.Group, r.Version)
+		}
+		typesPath := filepath.Join(apiDir, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
+		typesFile := &scaffoldv2.Types{Input: input.Input{Path: typesPath}, Resource: r}
+		exists, err := afero.Exists(fs, typesFile.Path)
+		if err != nil {
+			return err
+		}
+		// The types of the resources served from other packages are not generated
+		if !exists {
+			continue
+		}
+
+		// The scope of the Kind is only known from its markers
+		r.Namespaced, err = isNamespaced(fs, typesFile.Path, r.Kind)
+		if err != nil {
+			return err
+		}
+		if _, err := typesFile.SetClientGen(fs); err != nil {
+			return err
+		}
+		fmt.Printf("Added the +genclient markers to %s\n", typesFile.Path)
+		files = append(files, &scaffoldv2.ClientRegister{Resource: r})
+	}
+
+	universe, err := model.NewUniverse(model.WithConfig(&c))
+	if err != nil {
+		return err
+	}
+	if err := (&Scaffold{Fs: fs}).Execute(universe, input.Options{}, files...); err != nil {
+		return err
+	}
+
+	// k8s.io/code-generator is released with the Kubernetes libraries required by controller-runtime
+	versions, err := KubernetesDependencyVersions(c.KubernetesVersion)
+	if err != nil {
+		return err
+	}
+	return (&scaffoldv2.Makefile{}).AddClientGenTarget(fs, versions.Kubernetes)
+}
+
+// isNamespaced returns whether the Kind declared in the types file has none of the markers of cluster-scoped
+// resources, which are in the comment blocks between the previous declaration and the one of the Kind
+func isNamespaced(fs afero.Fs, path, kind string) (bool, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return false, err
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), path, content, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+
+	var previousEnd, declPos token.Pos
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+			for _, spec := range genDecl.Specs {
+				if spec.(*ast.TypeSpec).Name.Name == kind {
+					declPos = genDecl.Pos()
+				}
+			}
+		}
+		if declPos.IsValid() {
+			break
+		}
+		previousEnd = decl.End()
+	}
+	if !declPos.IsValid() {
+		return false, fmt.Errorf("unable to find the declaration of type %s in %s", kind, path)
+	}
+
+	for _, comments := range file.Comments {
+		if comments.Pos() > previousEnd && comments.End() < declPos && isClusterScoped(comments) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// scaffoldComponent scaffolds a component and its files in the projects initialized before it was added
+func scaffoldComponent(fs afero.Fs, c *modelconfig.Config, name string, files ...input.File) error {
+	exists, err := afero.DirExists(fs, filepath.Join("config", "components", name))
+	if err != nil || exists {
+		return err
+	}
+
+	universe, err := model.NewUniverse(model.WithConfig(c))
+	if err != nil {
+		return err
+	}
+
+	// The yaml files don't need the boilerplate, and the configuration is provided through the universe
+	return (&Scaffold{Fs: fs, ConfigOptional: true, BoilerplateOptional: true}).Execute(
+		universe,
+		input.Options{},
+		append([]input.File{&scaffoldv2.Component{Name: name}}, files...)...,
+	)
+}
+
+func hasString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("EditScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		c = scaffoldtest.NewConfig()
+		fs = c.Fs()
+		c.Resources = []modelconfig.GVK{{Group: "crew", Version: "v1", Kind: "Captain"}}
+
+		files := map[string]string{
+			"api/v1/captain_types.go": "package v1\n",
+			"api/v1/webhook_suite_test.go": "package v1\n\n" +
+				`var crds = filepath.Join("..", "..", "config", "crd", "bases")` + "\n",
+			".kubebuilder/scaffolds/api/v1/captain_types.go": "package v1\n",
+			"controllers/captain_controller.go": "package controllers\n\n" +
+				`import crewv1 "example.com/project/api/v1"` + "\n",
+			"main.go": "package main\n\n" +
+				`import crewv1 "example.com/project/api/v1"` + "\n",
+			"Dockerfile": "COPY main.go main.go\nCOPY api/ api/\n",
+		}
+		for path, content := range files {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0600)).To(Succeed())
+		}
+	})
+
+	It("should move the API packages when enabling the multigroup layout", func() {
+		Expect(scaffold.NewEditScaffolder(c, true, "", nil, false).Scaffold()).To(Succeed())
+
+		Expect(c.MultiGroup).To(BeTrue())
+		Expect(afero.Exists(fs, "api")).To(BeFalse())
+		Expect(scaffoldtest.ReadFile(fs, "apis/crew/v1/captain_types.go")).To(Equal("package v1\n"))
+		Expect(scaffoldtest.ReadFile(fs, ".kubebuilder/scaffolds/apis/crew/v1/captain_types.go")).To(Equal("package v1\n"))
+		Expect(scaffoldtest.ReadFile(fs, "apis/crew/v1/webhook_suite_test.go")).To(ContainSubstring(
+			`filepath.Join("..", "..", "..", "config", "crd", "bases")`))
+		Expect(afero.Exists(fs, "controllers/captain_controller.go")).To(BeFalse())
+		Expect(scaffoldtest.ReadFile(fs, "controllers/crew/captain_controller.go")).To(ContainSubstring(
+			`crewv1 "example.com/project/apis/crew/v1"`))
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(ContainSubstring(`crewv1 "example.com/project/apis/crew/v1"`))
+		Expect(scaffoldtest.ReadFile(fs, "Dockerfile")).To(Equal("COPY main.go main.go\nCOPY apis/ apis/\n"))
+	})
+
+	It("should move the controllers to the package of their group", func() {
+		Expect(afero.WriteFile(fs, "controllers/suite_test.go", []byte("package controllers\n\n"+
+			`var crds = filepath.Join("..", "config", "crd", "bases")`+"\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs, "main.go", []byte("package main\n\nimport (\n"+
+			"\t\"example.com/project/controllers\"\n)\n\n"+
+			"\tif err = (&controllers.CaptainReconciler{\n"+
+			"\t\tLog: ctrl.Log.WithName(\"controllers\").WithName(\"Captain\"),\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, true, "", nil, false).Scaffold()).To(Succeed())
+
+		Expect(scaffoldtest.ReadFile(fs, "controllers/crew/suite_test.go")).To(ContainSubstring(
+			`filepath.Join("..", "..", "config", "crd", "bases")`))
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`controllercrew "example.com/project/controllers/crew"`))
+		Expect(content).To(ContainSubstring("(&controllercrew.CaptainReconciler{\n"))
+		Expect(content).To(ContainSubstring(`ctrl.Log.WithName("controllers").WithName("Captain")`))
+	})
+
+	It("should leave the project untouched if moving a file fails", func() {
+		c.SetFs(failingFs{Fs: fs, path: "Dockerfile"})
+
+		Expect(scaffold.NewEditScaffolder(c, true, "", nil, false).Scaffold()).NotTo(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, "api/v1/captain_types.go")).To(Equal("package v1\n"))
+		Expect(scaffoldtest.ReadFile(fs, "controllers/captain_controller.go")).To(ContainSubstring(
+			`crewv1 "example.com/project/api/v1"`))
+		Expect(afero.Exists(fs, "apis")).To(BeFalse())
+		Expect(afero.Exists(fs, "controllers/crew")).To(BeFalse())
+	})
+
+	It("should not move the API packages if the target directory exists", func() {
+		Expect(afero.WriteFile(fs, "apis/crew/v1/captain_types.go", []byte("package v1\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, true, "", nil, false).Scaffold()).NotTo(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, "api/v1/captain_types.go")).To(Equal("package v1\n"))
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(ContainSubstring(`crewv1 "example.com/project/api/v1"`))
+	})
+
+	It("should not move the API packages of a project with resources in several groups", func() {
+		c.Resources = append(c.Resources, modelconfig.GVK{Group: "ship", Version: "v1", Kind: "Frigate"})
+
+		Expect(scaffold.NewEditScaffolder(c, true, "", nil, false).Scaffold()).NotTo(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, "api/v1/captain_types.go")).To(Equal("package v1\n"))
+	})
+
+	It("should mark the Kinds and scaffold the code generation when enabling typed clients", func() {
+		files := map[string]string{
+			"api/v1/captain_types.go": "package v1\n\n// +kubebuilder:object:root=true\n" +
+				"// +kubebuilder:resource:path=captains,scope=Cluster\n\n" +
+				"// Captain is the Schema for the captains API\ntype Captain struct {\n}\n",
+			"hack/boilerplate.go.txt": "",
+			"Makefile":                "all: manager\n",
+		}
+		for path, content := range files {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0600)).To(Succeed())
+		}
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", nil, true).Scaffold()).To(Succeed())
+
+		Expect(c.ClientGen).To(BeTrue())
+		Expect(scaffoldtest.ReadFile(fs, "api/v1/captain_types.go")).To(ContainSubstring("// +kubebuilder:object:root=true\n" +
+			"// +genclient\n// +genclient:nonNamespaced\n// +kubebuilder:resource:path=captains,scope=Cluster\n"))
+		Expect(scaffoldtest.ReadFile(fs, "api/v1/register.go")).To(ContainSubstring("var SchemeGroupVersion = GroupVersion"))
+		Expect(scaffoldtest.ReadFile(fs, "hack/update-codegen.sh")).To(ContainSubstring("REPO=example.com/project\n"))
+		Expect(scaffoldtest.ReadFile(fs, "Makefile")).To(ContainSubstring("\ngenerate-clients: code-generator\n"))
+		Expect(scaffoldtest.ReadFile(fs, "Makefile")).To(ContainSubstring("k8s.io/code-generator/cmd/client-gen@" +
+			scaffold.CodeGeneratorVersion))
+	})
+
+	It("should add the enabled components to the default overlay", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path,
+			[]byte("components:\n# +kubebuilder:scaffold:components\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"certmanager"}, false).Scaffold()).To(Succeed())
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"prometheus", "webhook"}, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, path)).To(Equal(`components:
+- ../components/webhook
+- ../components/certmanager
+- ../components/prometheus
+# +kubebuilder:scaffold:components
+`))
+	})
+
+	It("should uncomment the sections of the enabled components in older projects", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path,
+			[]byte("bases:\n- ../crd\n#- ../webhook\n#- ../prometheus\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"prometheus"}, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, path)).To(Equal("bases:\n- ../crd\n#- ../webhook\n- ../prometheus\n"))
+	})
+
+	It("should scaffold the production component when enabling it in older projects", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path,
+			[]byte("components:\n# +kubebuilder:scaffold:components\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"production"}, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, path)).To(Equal(`components:
+- ../components/production
+# +kubebuilder:scaffold:components
+`))
+		files := []string{"kustomization.yaml", "priorityclass.yaml", "pdb.yaml", "manager_production_patch.yaml"}
+		for _, file := range files {
+			Expect(afero.Exists(fs, filepath.Join("config", "components", "production", file))).To(BeTrue())
+		}
+	})
+
+	It("should scaffold the networkpolicy component when enabling it in older projects", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path,
+			[]byte("components:\n# +kubebuilder:scaffold:components\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"networkpolicy"}, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, path)).To(Equal(`components:
+- ../components/networkpolicy
+# +kubebuilder:scaffold:components
+`))
+		for _, file := range []string{"kustomization.yaml", "webhook_ingress.yaml", "apiserver_egress.yaml"} {
+			Expect(afero.Exists(fs, filepath.Join("config", "components", "networkpolicy", file))).To(BeTrue())
+		}
+	})
+
+	It("should scaffold the observability component along with the prometheus one", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path,
+			[]byte("components:\n# +kubebuilder:scaffold:components\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"observability"}, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, path)).To(Equal(`components:
+- ../components/prometheus
+- ../components/observability
+# +kubebuilder:scaffold:components
+`))
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("config", "components", "observability", "alerts.yaml"))).To(
+			ContainSubstring("kind: PrometheusRule\n"))
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("config", "grafana", "kustomization.yaml"))).To(
+			ContainSubstring("- controller-runtime-metrics.json\n"))
+	})
+
+	It("should not enable the production component in projects without a components section", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path, []byte("bases:\n- ../crd\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"production"}, false).Scaffold()).NotTo(Succeed())
+	})
+
+	It("should scaffold the webhookca component and record it as the certificate provider", func() {
+		path := filepath.Join("config", "default", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, path,
+			[]byte("components:\n# +kubebuilder:scaffold:components\n"), 0600)).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, false, "", []string{"webhookca"}, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, path)).To(Equal(`components:
+- ../components/webhook
+- ../components/webhookca
+# +kubebuilder:scaffold:components
+`))
+		files := []string{"kustomization.yaml", "certgen_job.yaml", "certgen_rbac.yaml", "manager_webhook_cert_patch.yaml"}
+		for _, file := range files {
+			Expect(afero.Exists(fs, filepath.Join("config", "components", "webhookca", file))).To(BeTrue())
+		}
+		Expect(c.CertProvider).To(Equal(modelconfig.CertProviderWebhookCA))
+	})
+})
+
+var _ = Describe("EditScaffolder multigroup migration", func() {
+	It("should let the migrated APIs be created and deleted like the ones of multigroup projects", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+		captain := &resource.Resource{Group: "crew", Version: "v1", Kind: "Captain", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, captain, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(scaffold.NewEditScaffolder(c, true, "", nil, false).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(ContainSubstring("(&controllercrew.CaptainReconciler{\n"))
+
+		// The next API of the group joins the controllers package of the group
+		firstMate := &resource.Resource{Group: "crew", Version: "v1", Kind: "FirstMate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, firstMate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		Expect(afero.Exists(fs, filepath.Join("controllers", "crew", "firstmate_controller.go"))).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join("controllers", "suite_test.go"))).To(BeFalse())
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("controllers", "crew", "suite_test.go"))).To(
+			ContainSubstring("crewv1.AddToScheme"))
+
+		Expect(scaffold.NewDeleteAPIScaffolder(c, captain).Scaffold()).To(Succeed())
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).NotTo(ContainSubstring("CaptainReconciler"))
+		Expect(content).To(ContainSubstring("(&controllercrew.FirstMateReconciler{\n"))
+		Expect(afero.Exists(fs, filepath.Join("controllers", "crew", "captain_controller.go"))).To(BeFalse())
+		Expect(afero.Exists(fs, filepath.Join("apis", "crew", "v1", "captain_types.go"))).To(BeFalse())
+	})
+})
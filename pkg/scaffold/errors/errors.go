@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors defines the errors returned by the scaffolders. Their code tells the tools running the scaffolders
+// what failed, e.g. to offer to merge the files that already exist:
+//
+//	if errors.Is(err, scaffolderrors.ErrFileExists) {
+//		// retry with --force
+//	}
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code identifies what a scaffolder failed to do
+type Code string
+
+const (
+	// CodeFileExists is the code of the failures to scaffold a file that already exists and is owned by the user
+	CodeFileExists Code = "FileExists"
+	// CodeModelBuild is the code of the failures to build the model of the project the files are scaffolded from
+	CodeModelBuild Code = "ModelBuild"
+	// CodePostUpdate is the code of the failures to update the existing files once the new ones are scaffolded,
+	// e.g. the PROJECT file, main.go or the kustomizations
+	CodePostUpdate Code = "PostUpdate"
+)
+
+var (
+	// ErrFileExists matches the errors with CodeFileExists
+	ErrFileExists = &Error{Code: CodeFileExists, Err: errors.New("file already exists")}
+	// ErrModelBuild matches the errors with CodeModelBuild
+	ErrModelBuild = &Error{Code: CodeModelBuild, Err: errors.New("unable to build the model of the project")}
+	// ErrPostUpdate matches the errors with CodePostUpdate
+	ErrPostUpdate = &Error{Code: CodePostUpdate, Err: errors.New("unable to update the project")}
+)
+
+// Error is a scaffolding failure, errors.Is matches it with the Err variable of its code
+type Error struct {
+	// Code tells what failed
+	Code Code
+	// Path is the file the failure is about, if any
+	Path string
+	// Op describes what was being done, it prefixes the message of the cause
+	Op string
+	// Err is the cause of the failure
+	Err error
+}
+
+// FileExists returns the error of scaffolding a file that already exists
+func FileExists(path string) error {
+	return &Error{Code: CodeFileExists, Path: path, Err: fmt.Errorf("%s already exists", path)}
+}
+
+// ModelBuild returns the error of failing to build the model of the project while doing op
+func ModelBuild(op string, err error) error {
+	return &Error{Code: CodeModelBuild, Op: op, Err: err}
+}
+
+// PostUpdate returns the error of failing to update the file of the path while doing op
+func PostUpdate(path, op string, err error) error {
+	return &Error{Code: CodePostUpdate, Path: path, Op: op, Err: err}
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("error %s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the cause of the failure
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is an Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// CodeOf returns the code of the first Error in the chain of err, if any
+func CodeOf(err error) (Code, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return "", false
+	}
+	return e.Code, true
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		err    error
+		target error
+		code   Code
+	}{
+		{FileExists("main.go"), ErrFileExists, CodeFileExists},
+		{fmt.Errorf("error scaffolding APIs: %w", FileExists("main.go")), ErrFileExists, CodeFileExists},
+		{ModelBuild("building API scaffold", errors.New("boom")), ErrModelBuild, CodeModelBuild},
+		{PostUpdate("main.go", "updating main.go", errors.New("boom")), ErrPostUpdate, CodePostUpdate},
+	}
+
+	for _, test := range tests {
+		if !errors.Is(test.err, test.target) {
+			t.Errorf("%q should match %q", test.err, test.target)
+		}
+		for _, other := range []error{ErrFileExists, ErrModelBuild, ErrPostUpdate} {
+			if other != test.target && errors.Is(test.err, other) {
+				t.Errorf("%q shouldn't match %q", test.err, other)
+			}
+		}
+		if code, ok := CodeOf(test.err); !ok || code != test.code {
+			t.Errorf("expected the code of %q to be %s, got %s", test.err, test.code, code)
+		}
+	}
+
+	if _, ok := CodeOf(errors.New("boom")); ok {
+		t.Error("expected errors other than Error not to have a code")
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err     error
+		message string
+	}{
+		{FileExists("main.go"), "main.go already exists"},
+		{ModelBuild("building API scaffold", errors.New("boom")), "error building API scaffold: boom"},
+		{PostUpdate("main.go", "updating main.go", errors.New("boom")), "error updating main.go: boom"},
+	}
+
+	for _, test := range tests {
+		if actual := test.err.Error(); actual != test.message {
+			t.Errorf("expected %q, got %q", test.message, actual)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+// ExecPluginPrefix is the prefix of the name of the binaries that are discovered as plugins
+const ExecPluginPrefix = "kubebuilder-plugin-"
+
+var _ Plugin = &ExecPlugin{}
+
+// ExecPlugin is a Plugin implemented by a separate binary. The binary is sent the Universe as JSON on its
+// stdin and must print the Universe as JSON on its stdout after changing, adding or removing its Files.
+// Anything the binary prints on its stderr is shown to the user, and a non-zero exit code fails scaffolding.
+type ExecPlugin struct {
+	// Name is the name of the plugin, i.e. the name of the binary without ExecPluginPrefix
+	Name string
+
+	// Path is the path to the binary
+	Path string
+}
+
+// Pipe implements Plugin
+func (p *ExecPlugin) Pipe(universe *model.Universe) error {
+	in, err := json.Marshal(universe)
+	if err != nil {
+		return err
+	}
+
+	out := &bytes.Buffer{}
+	cmd := exec.Command(p.Path) // nolint:gosec
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s failed: %v", p.Name, err)
+	}
+
+	transformed := &model.Universe{}
+	if err := json.Unmarshal(out.Bytes(), transformed); err != nil {
+		return fmt.Errorf("plugin %s returned an invalid universe: %v", p.Name, err)
+	}
+
+	// Only the files are taken from the plugin, the inputs of the generation can't be changed
+	for _, file := range transformed.Files {
+		if err := validatePluginFile(file); err != nil {
+			return fmt.Errorf("plugin %s returned an invalid file: %v", p.Name, err)
+		}
+	}
+	universe.Files = transformed.Files
+
+	return nil
+}
+
+// validatePluginFile checks that a file returned by a plugin is written inside the project
+func validatePluginFile(file *model.File) error {
+	if file == nil || file.Path == "" {
+		return fmt.Errorf("path must be set")
+	}
+	if filepath.IsAbs(file.Path) {
+		return fmt.Errorf("path %s must be relative to the project root", file.Path)
+	}
+	if clean := filepath.Clean(file.Path); clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %s must be inside the project", file.Path)
+	}
+	return nil
+}
+
+// DiscoverExecPlugins returns the plugins found in the directories of path, a list of directories
+// like the PATH environment variable. Plugins are the executables named ExecPluginPrefix<name>, and a
+// plugin found in a directory hides the plugins with the same name in the following ones.
+// They are sorted by name, which is the order they should be run in.
+func DiscoverExecPlugins(path string) ([]*ExecPlugin, error) {
+	found := make(map[string]*ExecPlugin)
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		infos, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range infos {
+			name := strings.TrimPrefix(info.Name(), ExecPluginPrefix)
+			if name == info.Name() || name == "" || !info.Mode().IsRegular() || info.Mode()&0111 == 0 {
+				continue
+			}
+			if _, hidden := found[name]; !hidden {
+				found[name] = &ExecPlugin{Name: name, Path: filepath.Join(dir, info.Name())}
+			}
+		}
+	}
+
+	plugins := make([]*ExecPlugin, 0, len(found))
+	for _, plugin := range found {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+
+	return plugins, nil
+}
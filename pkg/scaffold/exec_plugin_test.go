@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+var _ = Describe("ExecPlugin", func() {
+	var dirs []string
+
+	BeforeEach(func() {
+		dirs = nil
+		for i := 0; i < 2; i++ {
+			dir, err := ioutil.TempDir("", "kubebuilder-plugins")
+			Expect(err).NotTo(HaveOccurred())
+			dirs = append(dirs, dir)
+		}
+	})
+
+	AfterEach(func() {
+		for _, dir := range dirs {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		}
+	})
+
+	writePlugin := func(dir, name, script string) {
+		Expect(ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0700)).To(Succeed())
+	}
+
+	It("should discover the plugins in the order of their names", func() {
+		writePlugin(dirs[0], "kubebuilder-plugin-b", "cat\n")
+		writePlugin(dirs[1], "kubebuilder-plugin-b", "cat\n")
+		writePlugin(dirs[1], "kubebuilder-plugin-a", "cat\n")
+		writePlugin(dirs[1], "kubebuilder-other", "cat\n")
+		Expect(ioutil.WriteFile(filepath.Join(dirs[1], "kubebuilder-plugin-c"), []byte("cat\n"), 0600)).To(Succeed())
+
+		plugins, err := scaffold.DiscoverExecPlugins(strings.Join(append(dirs, "/does/not/exist"),
+			string(filepath.ListSeparator)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plugins).To(Equal([]*scaffold.ExecPlugin{
+			{Name: "a", Path: filepath.Join(dirs[1], "kubebuilder-plugin-a")},
+			{Name: "b", Path: filepath.Join(dirs[0], "kubebuilder-plugin-b")},
+		}))
+	})
+
+	It("should replace the files of the universe with the ones returned by the plugin", func() {
+		writePlugin(dirs[0], "kubebuilder-plugin-nofoo", "exec sed 's/Foo/Bar/g'\n")
+		universe := &model.Universe{Files: []*model.File{{Path: "main.go", Contents: "Foo"}}}
+
+		plugin := &scaffold.ExecPlugin{Name: "nofoo", Path: filepath.Join(dirs[0], "kubebuilder-plugin-nofoo")}
+		Expect(plugin.Pipe(universe)).To(Succeed())
+		Expect(universe.Files).To(Equal([]*model.File{{Path: "main.go", Contents: "Bar"}}))
+	})
+
+	It("should fail if the plugin fails", func() {
+		writePlugin(dirs[0], "kubebuilder-plugin-fail", "exit 1\n")
+
+		plugin := &scaffold.ExecPlugin{Name: "fail", Path: filepath.Join(dirs[0], "kubebuilder-plugin-fail")}
+		Expect(plugin.Pipe(&model.Universe{})).NotTo(Succeed())
+	})
+
+	It("should fail if the plugin returns a file outside of the project", func() {
+		writePlugin(dirs[0], "kubebuilder-plugin-escape", `echo '{"files": [{"path": "../main.go"}]}'\n`)
+
+		plugin := &scaffold.ExecPlugin{Name: "escape", Path: filepath.Join(dirs[0], "kubebuilder-plugin-escape")}
+		Expect(plugin.Pipe(&model.Universe{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/controller-tools/pkg/crd"
+	"sigs.k8s.io/controller-tools/pkg/rbac"
+	"sigs.k8s.io/controller-tools/pkg/webhook"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+// GenerateCachePath is the file recording the hashes of the packages the manifests were last generated from,
+// under the bin directory ignored by git
+var GenerateCachePath = filepath.Join("bin", ".generate-cache.json")
+
+// CRDBasesDir is the directory where the CustomResourceDefinitions are generated
+var CRDBasesDir = filepath.Join("config", "crd", "bases")
+
+// generateScaffolder generates the CRD, RBAC and webhook manifests of the project with the controller-tools
+// generators, skipping them when none of the Go packages changed since the last generation
+type generateScaffolder struct {
+	config *config.Config
+	// force regenerates the manifests even if the packages didn't change
+	force bool
+}
+
+// generateCache is the content of GenerateCachePath
+type generateCache struct {
+	// Options are the options of the generators, as the arguments of controller-gen in the manifests target
+	Options []string `json:"options"`
+	// Packages are the hashes of the Go files of each package, keyed by the directory of the package
+	Packages map[string]string `json:"packages"`
+	// Outputs are the manifests written by the generation
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+func NewGenerateScaffolder(config *config.Config, force bool) Scaffolder {
+	return &generateScaffolder{
+		config: config,
+		force:  force,
+	}
+}
+
+func (s *generateScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("manifest generation is not supported for project version %v", s.config.Version)
+	}
+	if s.config.APIServer {
+		return fmt.Errorf("manifest generation is not supported for aggregated API servers, " +
+			"their resources are served without CRDs")
+	}
+
+	packages, err := hashPackages(s.config.Fs())
+	if err != nil {
+		return fmt.Errorf("error hashing the Go packages: %v", err)
+	}
+	current := generateCache{Options: s.generatorOptions(), Packages: packages}
+
+	if !s.force {
+		previous, err := s.readCache()
+		if err != nil {
+			return err
+		}
+		if previous != nil && reflect.DeepEqual(previous.Options, current.Options) &&
+			reflect.DeepEqual(previous.Packages, current.Packages) && s.hasOutputs(previous.Outputs) {
+			fmt.Println("The manifests are up to date, none of the Go packages changed since the last generation.")
+			return nil
+		}
+	}
+
+	fmt.Println("Generating the manifests...")
+	outputs, err := runGenerators(s.config.Fs(), s.generators()...)
+	if err != nil {
+		return fmt.Errorf("error generating the manifests: %v", err)
+	}
+	sort.Strings(outputs)
+	for _, path := range outputs {
+		fmt.Println(path)
+	}
+	current.Outputs = outputs
+
+	return s.writeCache(current)
+}
+
+// hasOutputs returns true unless some of the manifests written by the last generation were removed since
+func (s *generateScaffolder) hasOutputs(outputs []string) bool {
+	for _, path := range outputs {
+		if exists, err := afero.Exists(s.config.Fs(), path); err != nil || !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// generators returns the generators of the manifests target of the Makefile
+func (s *generateScaffolder) generators() []generator {
+	crdGenerator := crd.Generator{TrivialVersions: true}
+	if s.config.CRDVersion == modelconfig.CRDVersionV1 {
+		crdGenerator = crd.Generator{CRDVersions: []string{modelconfig.CRDVersionV1}}
+	}
+	return []generator{
+		{Generator: crdGenerator, dir: CRDBasesDir},
+		{Generator: rbac.Generator{RoleName: "manager-role"}, dir: filepath.Join("config", "rbac")},
+		{Generator: webhook.Generator{}, dir: filepath.Join("config", "webhook")},
+	}
+}
+
+// generatorOptions returns the options of the generators, as the arguments of controller-gen
+func (s *generateScaffolder) generatorOptions() []string {
+	crdOptions := "crd:trivialVersions=true"
+	if s.config.CRDVersion == modelconfig.CRDVersionV1 {
+		crdOptions = "crd:crdVersions=v1"
+	}
+	return []string{
+		crdOptions,
+		"rbac:roleName=manager-role",
+		"webhook",
+		"paths=./...",
+		"output:crd:artifacts:config=" + filepath.ToSlash(CRDBasesDir),
+	}
+}
+
+func (s *generateScaffolder) readCache() (*generateCache, error) {
+	content, err := afero.ReadFile(s.config.Fs(), GenerateCachePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A corrupted cache only causes the manifests to be generated again
+	cache := &generateCache{}
+	if err := json.Unmarshal(content, cache); err != nil {
+		return nil, nil
+	}
+	return cache, nil
+}
+
+func (s *generateScaffolder) writeCache(cache generateCache) error {
+	content, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := s.config.Fs().MkdirAll(filepath.Dir(GenerateCachePath), 0755); err != nil {
+		return err
+	}
+	return afero.WriteFile(s.config.Fs(), GenerateCachePath, content, 0644)
+}
+
+// hashPackages returns the hashes of the non-test Go files of each package of the project, keyed by the directory
+// of the package. The generated deep-copy methods, which the manifests don't depend on, are ignored.
+func hashPackages(fs afero.Fs) (map[string]string, error) {
+	files := map[string][]string{}
+	err := afero.Walk(fs, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != "." && (strings.HasPrefix(name, ".") || name == "bin" || name == "vendor" ||
+				name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") ||
+			strings.HasPrefix(filepath.Base(path), "zz_generated.") {
+			return nil
+		}
+		dir := filepath.ToSlash(filepath.Dir(path))
+		files[dir] = append(files[dir], path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]string, len(files))
+	for dir, paths := range files {
+		sort.Strings(paths)
+		hash := sha256.New()
+		for _, path := range paths {
+			content, err := afero.ReadFile(fs, path)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(hash, "%s\x00%d\x00", filepath.Base(path), len(content))
+			_, _ = hash.Write(content)
+		}
+		packages[dir] = hex.EncodeToString(hash.Sum(nil))
+	}
+	return packages, nil
+}
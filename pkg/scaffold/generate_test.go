@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+var _ = Describe("GenerateScaffolder", func() {
+	var wd, dir string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-generate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should generate the manifests only when the Go packages changed or the manifests were removed", func() {
+		controller := filepath.Join("controllers", "frigate_controller.go")
+		writeProject(map[string]string{
+			controller: "package controllers\n\n" +
+				"// +kubebuilder:rbac:groups=ship.testproject.org,resources=frigates,verbs=get;list\n",
+		})
+		role := filepath.Join("config", "rbac", "role.yaml")
+
+		c := config.New("PROJECT")
+		c.SetFs(afero.NewOsFs())
+		c.CRDVersion = modelconfig.CRDVersionV1
+		// generate generates the manifests and returns whether the RBAC Role was generated
+		generate := func(force bool) bool {
+			Expect(ioutil.WriteFile(role, []byte("edited\n"), 0644)).To(Succeed())
+			Expect(scaffold.NewGenerateScaffolder(c, force).Scaffold()).To(Succeed())
+			content, err := ioutil.ReadFile(role)
+			Expect(err).NotTo(HaveOccurred())
+			return string(content) != "edited\n"
+		}
+
+		Expect(scaffold.NewGenerateScaffolder(c, false).Scaffold()).To(Succeed())
+		content, err := ioutil.ReadFile(role)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("name: manager-role"))
+		Expect(string(content)).To(ContainSubstring("- frigates"))
+		Expect(generate(false)).To(BeFalse())
+
+		// Neither the test files nor the generated code are inputs of the manifests
+		Expect(ioutil.WriteFile(filepath.Join("controllers", "frigate_controller_test.go"),
+			[]byte("package controllers\n"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join("controllers", "zz_generated.deepcopy.go"),
+			[]byte("package controllers\n"), 0644)).To(Succeed())
+		Expect(generate(false)).To(BeFalse())
+
+		Expect(generate(true)).To(BeTrue())
+
+		// Any of the generated manifests is generated again once removed
+		Expect(os.Remove(role)).To(Succeed())
+		Expect(scaffold.NewGenerateScaffolder(c, false).Scaffold()).To(Succeed())
+		Expect(role).To(BeAnExistingFile())
+
+		Expect(ioutil.WriteFile(controller, []byte("package controllers\n\n"+
+			"// +kubebuilder:rbac:groups=ship.testproject.org,resources=frigates,verbs=get;list;watch\n"), 0644)).
+			To(Succeed())
+		Expect(generate(false)).To(BeTrue())
+		content, err = ioutil.ReadFile(role)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("- watch"))
+	})
+})
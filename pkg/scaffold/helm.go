@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/helm"
+)
+
+// helmChartScaffolder scaffolds a Helm chart equivalent to the config/default kustomization
+type helmChartScaffolder struct {
+	config *internalconfig.Config
+}
+
+func NewHelmChartScaffolder(config *internalconfig.Config) Scaffolder {
+	return &helmChartScaffolder{
+		config: config,
+	}
+}
+
+func (s *helmChartScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("helm charts are not supported for project version %v", s.config.Version)
+	}
+
+	chartName, err := helm.DefaultChartName()
+	if err != nil {
+		return err
+	}
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(s.config.Fs(), boilerplatePath),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := (&Scaffold{Fs: s.config.Fs()}).Execute(
+		universe,
+		input.Options{},
+		&helm.Chart{ChartName: chartName},
+		&helm.Values{ChartName: chartName},
+		&helm.HelmIgnore{ChartName: chartName},
+		&helm.Helpers{ChartName: chartName},
+		&helm.Deployment{
+			ChartName:       chartName,
+			NamespaceScoped: s.config.NamespaceScoped,
+			SecureDefaults:  s.config.SecureDefaults,
+			WebhookPort:     s.config.WebhookServerPort(),
+			MetricsAddress:  s.config.MetricsLoopbackAddress(),
+			IPv6:            s.config.IPv6(),
+		},
+		&helm.RBAC{ChartName: chartName},
+		&helm.MetricsService{ChartName: chartName, DualStack: s.config.IPv6()},
+		&helm.Webhook{
+			ChartName:   chartName,
+			WebhookPort: s.config.WebhookServerPort(),
+			DualStack:   s.config.IPv6(),
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := (&scaffoldv2.Makefile{}).AddChartTarget(s.config.Fs(), helm.ChartDir(chartName)); err != nil {
+		return fmt.Errorf("error adding the chart target to the Makefile: %v", err)
+	}
+
+	s.config.Deploy = config.DeployHelm
+	if err := s.config.Save(); err != nil {
+		return fmt.Errorf("error updating project file: %v", err)
+	}
+
+	fmt.Printf("Helm chart scaffolded in %s.\nRun `make chart` to copy the generated manifests into it.\n",
+		helm.ChartDir(chartName))
+
+	return nil
+}
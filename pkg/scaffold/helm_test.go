@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/helm"
+)
+
+var _ = Describe("HelmChartScaffolder", func() {
+	var (
+		fs       afero.Fs
+		c        *config.Config
+		chartDir string
+	)
+
+	BeforeEach(func() {
+		fs, c = scaffoldtest.NewProject(nil)
+
+		chartName, err := helm.DefaultChartName()
+		Expect(err).NotTo(HaveOccurred())
+		chartDir = helm.ChartDir(chartName)
+	})
+
+	It("should scaffold the chart and the make target that copies the generated manifests into it", func() {
+		Expect(scaffold.NewHelmChartScaffolder(c).Scaffold()).To(Succeed())
+
+		for _, file := range []string{"Chart.yaml", "values.yaml", ".helmignore",
+			filepath.Join("templates", "_helpers.tpl"), filepath.Join("templates", "deployment.yaml"),
+			filepath.Join("templates", "rbac.yaml"), filepath.Join("templates", "metrics_service.yaml"),
+			filepath.Join("templates", "webhook.yaml")} {
+			Expect(afero.Exists(fs, filepath.Join(chartDir, file))).To(BeTrue(), file)
+		}
+
+		content := scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(ContainSubstring("\nchart: manifests\n"))
+		Expect(content).To(ContainSubstring("cp config/crd/bases/*.yaml " + chartDir + "/crds/"))
+
+		saved, err := config.ReadFromFs(fs, config.DefaultPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved.IsHelm()).To(BeTrue())
+	})
+
+	It("should keep the chart when an API is added, as make chart copies its CRD", func() {
+		Expect(scaffold.NewHelmChartScaffolder(c).Scaffold()).To(Succeed())
+		chart := scaffoldtest.ReadFile(fs, filepath.Join(chartDir, "templates", "rbac.yaml"))
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		Expect(c.IsHelm()).To(BeTrue())
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join(chartDir, "templates", "rbac.yaml"))).To(Equal(chart))
+		Expect(strings.Count(scaffoldtest.ReadFile(fs, "Makefile"), "\nchart: manifests\n")).To(Equal(1))
+	})
+
+	It("should not overwrite an existing chart", func() {
+		Expect(scaffold.NewHelmChartScaffolder(c).Scaffold()).To(Succeed())
+		Expect(scaffold.NewHelmChartScaffolder(c).Scaffold()).NotTo(Succeed())
+	})
+
+	It("should not scaffold charts for version 1 projects", func() {
+		c.Version = modelconfig.Version1
+
+		Expect(scaffold.NewHelmChartScaffolder(c).Scaffold()).NotTo(Succeed())
+		Expect(afero.Exists(fs, chartDir)).To(BeFalse())
+	})
+})
@@ -18,7 +18,9 @@ package scaffold
 
 import (
 	"fmt"
-	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
 
 	"sigs.k8s.io/kubebuilder/internal/config"
 	"sigs.k8s.io/kubebuilder/pkg/model"
@@ -28,18 +30,31 @@ import (
 	managerv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/manager"
 	metricsauthv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/metricsauth"
 	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	apiserverv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/apiserver"
 	certmanagerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/certmanager"
+	e2ev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/e2e"
 	managerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+	managerconfigv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/managerconfig"
 	metricsauthv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/metricsauth"
+	networkpolicyv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/networkpolicy"
 	prometheusv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/prometheus"
+	shardingv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/sharding"
 	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+	webhookcav2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhookca"
 )
 
 const (
 	// controller runtime version to be used in the project
-	ControllerRuntimeVersion = "v0.4.0"
+	ControllerRuntimeVersion = "v0.5.2"
 	// ControllerTools version to be used in the project
 	ControllerToolsVersion = "v0.2.4"
+	// k8s.io/apiserver version of the aggregated API servers, matching the Kubernetes libraries of controller runtime
+	APIServerVersion = "v0.17.2"
+	// k8s.io/code-generator version of the typed clientsets, listers and informers, matching the Kubernetes libraries
+	// of controller runtime
+	CodeGeneratorVersion = "v0.17.2"
+	// github.com/spf13/cobra version of the kubectl plugins
+	CobraVersion = "v1.1.1"
 
 	ImageName = "controller:latest"
 )
@@ -49,20 +64,81 @@ type initScaffolder struct {
 	boilerplatePath string
 	license         string
 	owner           string
+	// plugins transform the scaffolded files, e.g. the ones of the plugin chain of the project
+	plugins []Plugin
+	// templatesDir is a directory with templates that replace the built-in ones
+	templatesDir string
+	// adopt indicates that the existing files of the directory are kept, only the missing ones are scaffolded
+	adopt bool
+	// kept are the existing files that were not scaffolded when adopting the directory
+	kept keptFiles
+	// versions are the versions of the dependencies for the Kubernetes version of the project
+	versions DependencyVersions
 }
 
-func NewInitScaffolder(config *config.Config, license, owner string) Scaffolder {
+func NewInitScaffolder(config *config.Config, license, owner string, plugins []Plugin, templatesDir string) Scaffolder {
 	return &initScaffolder{
 		config:          config,
-		boilerplatePath: filepath.Join("hack", "boilerplate.go.txt"),
+		boilerplatePath: boilerplatePath,
 		license:         license,
 		owner:           owner,
+		plugins:         plugins,
+		templatesDir:    templatesDir,
+	}
+}
+
+// NewAdoptScaffolder returns a Scaffolder initializing a project in a directory with an existing Go module,
+// which only writes the project files that are missing and keeps the existing ones untouched
+func NewAdoptScaffolder(
+	config *config.Config,
+	license, owner string,
+	plugins []Plugin,
+	templatesDir string,
+) Scaffolder {
+	return &initScaffolder{
+		config:          config,
+		boilerplatePath: boilerplatePath,
+		license:         license,
+		owner:           owner,
+		plugins:         plugins,
+		templatesDir:    templatesDir,
+		adopt:           true,
+	}
+}
+
+// keptFiles collects the existing files that were skipped
+type keptFiles []string
+
+// ReportFile implements Reporter
+func (k *keptFiles) ReportFile(path string, action FileAction) {
+	if action == FileSkipped {
+		*k = append(*k, path)
 	}
 }
 
+// newScaffold returns the Scaffold writing the project files, which keeps the existing ones when adopting
+func (s *initScaffolder) newScaffold() *Scaffold {
+	if s.adopt {
+		return &Scaffold{
+			Plugins:      s.plugins,
+			Fs:           s.config.Fs(),
+			TemplatesDir: s.templatesDir,
+			SkipExisting: true,
+			Reporter:     &s.kept,
+		}
+	}
+	return &Scaffold{Plugins: s.plugins, Fs: s.config.Fs(), TemplatesDir: s.templatesDir}
+}
+
 func (s *initScaffolder) Scaffold() error {
 	fmt.Println("Writing scaffold for you to edit...")
 
+	versions, err := KubernetesDependencyVersions(s.config.KubernetesVersion)
+	if err != nil {
+		return err
+	}
+	s.versions = versions
+
 	if err := s.config.Save(); err != nil {
 		return err
 	}
@@ -75,56 +151,109 @@ func (s *initScaffolder) Scaffold() error {
 		return fmt.Errorf("error initializing project: %v", err)
 	}
 
-	if err := (&Scaffold{BoilerplateOptional: true}).Execute(
+	boilerplateFile := &project.Boilerplate{
+		Input:   input.Input{Path: s.boilerplatePath},
+		License: s.license,
+		Owner:   s.owner,
+	}
+	// The header of custom licenses is read from a file, it may reference the owner and the year
+	if project.IsCustomLicense(s.license) {
+		header, err := afero.ReadFile(s.config.Fs(), s.license)
+		if err != nil {
+			return fmt.Errorf("error reading the header of the license: %v", err)
+		}
+		boilerplateFile.Boilerplate = strings.TrimSpace(string(header))
+	}
+	boilerplateScaffold := s.newScaffold()
+	boilerplateScaffold.BoilerplateOptional = true
+	if err := boilerplateScaffold.Execute(
 		universe,
 		input.Options{ProjectPath: s.config.Path(), BoilerplatePath: s.boilerplatePath},
-		&project.Boilerplate{
-			Input:   input.Input{Path: s.boilerplatePath},
-			License: s.license,
-			Owner:   s.owner,
-		},
+		boilerplateFile,
 	); err != nil {
 		return err
 	}
 
 	universe, err = model.NewUniverse(
 		model.WithConfig(&s.config.Config),
-		model.WithBoilerplateFrom(s.boilerplatePath),
+		model.WithBoilerplateFromFs(s.config.Fs(), s.boilerplatePath),
 	)
 	if err != nil {
 		return fmt.Errorf("error initializing project: %v", err)
 	}
 
-	if err := (&Scaffold{}).Execute(
+	if err := s.newScaffold().Execute(
 		universe,
 		input.Options{ProjectPath: s.config.Path(), BoilerplatePath: s.boilerplatePath},
-		&project.GitIgnore{},
-		&project.AuthProxyRole{},
-		&project.AuthProxyRoleBinding{},
+		s.projectFiles()...,
 	); err != nil {
 		return err
 	}
 
 	switch {
+	case s.config.IsV2() && s.config.APIServer:
+		err = s.scaffoldAPIServer()
 	case s.config.IsV1():
-		return s.scaffoldV1()
+		err = s.scaffoldV1()
 	case s.config.IsV2():
-		return s.scaffoldV2()
+		err = s.scaffoldV2()
 	default:
-		return fmt.Errorf("unknown project version %v", s.config.Version)
+		err = fmt.Errorf("unknown project version %v", s.config.Version)
+	}
+	if err != nil || !s.adopt {
+		return err
+	}
+
+	if len(s.kept) != 0 {
+		fmt.Println("Kept the existing files, compare them with the kubebuilder scaffold and merge what they miss:")
+		for _, path := range s.kept {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+	for _, path := range s.kept {
+		if path == "main.go" {
+			fmt.Println("Add the +kubebuilder:scaffold markers (imports, scheme, flags and builder) to main.go " +
+				"so that kubebuilder create api can wire the APIs in it.")
+		}
+	}
+	return nil
+}
+
+// layoutFiles returns the files scaffolded by init for the layout of a v2 project, either a manager or an
+// aggregated API server
+func (s *initScaffolder) layoutFiles() ([]input.File, error) {
+	versions, err := KubernetesDependencyVersions(s.config.KubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	s.versions = versions
+
+	if s.config.APIServer {
+		return append(s.projectFiles(), s.apiServerFiles()...), nil
+	}
+	return append(s.projectFiles(), s.v2Files()...), nil
+}
+
+// projectFiles returns the files scaffolded for every project version
+func (s *initScaffolder) projectFiles() []input.File {
+	files := []input.File{&project.GitIgnore{}}
+	// The metrics of aggregated API servers aren't served behind the auth proxy
+	if !s.config.APIServer {
+		files = append(files, &project.AuthProxyRole{}, &project.AuthProxyRoleBinding{})
 	}
+	return files
 }
 
 func (s *initScaffolder) scaffoldV1() error {
 	universe, err := model.NewUniverse(
 		model.WithConfig(&s.config.Config),
-		model.WithBoilerplateFrom(s.boilerplatePath),
+		model.WithBoilerplateFromFs(s.config.Fs(), s.boilerplatePath),
 	)
 	if err != nil {
 		return fmt.Errorf("error initializing project: %v", err)
 	}
 
-	return (&Scaffold{}).Execute(
+	return s.newScaffold().Execute(
 		universe,
 		input.Options{ProjectPath: s.config.Path(), BoilerplatePath: s.boilerplatePath},
 		&project.KustomizeRBAC{},
@@ -148,38 +277,197 @@ func (s *initScaffolder) scaffoldV1() error {
 func (s *initScaffolder) scaffoldV2() error {
 	universe, err := model.NewUniverse(
 		model.WithConfig(&s.config.Config),
-		model.WithBoilerplateFrom(s.boilerplatePath),
+		model.WithBoilerplateFromFs(s.config.Fs(), s.boilerplatePath),
 	)
 	if err != nil {
 		return fmt.Errorf("error initializing project: %v", err)
 	}
 
-	return (&Scaffold{}).Execute(
+	return s.newScaffold().Execute(
 		universe,
 		input.Options{ProjectPath: s.config.Path(), BoilerplatePath: s.boilerplatePath},
-		&metricsauthv2.AuthProxyPatch{},
-		&metricsauthv2.AuthProxyService{},
+		s.v2Files()...,
+	)
+}
+
+// v2Files returns the files scaffolded for v2 projects
+func (s *initScaffolder) v2Files() []input.File {
+	files := []input.File{
+		&metricsauthv2.AuthProxyPatch{
+			SecureDefaults:  s.config.SecureDefaults,
+			ComponentConfig: s.config.ComponentConfig,
+			MetricsAddress:  s.config.MetricsLoopbackAddress(),
+			IPv6:            s.config.IPv6(),
+		},
+		&metricsauthv2.AuthProxyService{DualStack: s.config.IPv6()},
 		&metricsauthv2.ClientClusterRole{},
-		&managerv2.Config{Image: ImageName},
-		&scaffoldv2.Main{},
-		&scaffoldv2.GoMod{ControllerRuntimeVersion: ControllerRuntimeVersion},
-		&scaffoldv2.Makefile{Image: ImageName, ControllerToolsVersion: ControllerToolsVersion},
-		&scaffoldv2.Dockerfile{},
-		&scaffoldv2.Kustomize{},
-		&scaffoldv2.ManagerWebhookPatch{},
-		&scaffoldv2.ManagerRoleBinding{},
+		&managerv2.Config{
+			Image:           ImageName,
+			SecureDefaults:  s.config.SecureDefaults,
+			ComponentConfig: s.config.ComponentConfig,
+		},
+		&managerv2.PriorityClass{},
+		&managerv2.PodDisruptionBudget{},
+		&managerv2.ProductionPatch{},
+		&webhookcav2.CertGenJob{},
+		&webhookcav2.CertGenRBAC{},
+		&webhookcav2.ManagerCertPatch{},
+		&networkpolicyv2.WebhookIngress{WebhookPort: s.config.WebhookServerPort()},
+		&networkpolicyv2.APIServerEgress{},
+		&scaffoldv2.Main{
+			NamespaceScoped: s.config.NamespaceScoped,
+			WebhookPort:     s.config.WebhookServerPort(),
+			WebhookHost:     s.config.WebhookHost,
+			MetricsAddress:  s.config.MetricsAddress(),
+			ComponentConfig: s.config.ComponentConfig,
+			Sharding:        s.config.Sharding,
+		},
+		&scaffoldv2.Makefile{
+			Image:                  ImageName,
+			ControllerToolsVersion: s.versions.ControllerTools,
+			CRDVersion:             s.config.CRDVersion,
+			ModuleDir:              s.config.ModuleDir(),
+			Sharding:               s.config.Sharding,
+		},
+		&scaffoldv2.Dockerfile{
+			BaseImage:       s.config.BaseImage,
+			ComponentConfig: s.config.ComponentConfig,
+			ModuleDir:       s.config.ModuleDir(),
+			Vendor:          s.config.Vendor,
+			Sharding:        s.config.Sharding,
+		},
+		&scaffoldv2.Kustomize{NamespaceScoped: s.config.NamespaceScoped, SecureDefaults: s.config.SecureDefaults},
+		&scaffoldv2.Component{Name: scaffoldv2.ComponentWebhook},
+		&scaffoldv2.Component{Name: scaffoldv2.ComponentCertManager},
+		&scaffoldv2.Component{Name: scaffoldv2.ComponentPrometheus},
+		&scaffoldv2.Component{Name: scaffoldv2.ComponentProduction},
+		&scaffoldv2.Component{Name: scaffoldv2.ComponentWebhookCA},
+		&scaffoldv2.Component{Name: scaffoldv2.ComponentNetworkPolicy},
+		&scaffoldv2.ManagerWebhookPatch{WebhookPort: s.config.WebhookServerPort()},
+		&scaffoldv2.ManagerRoleBinding{NamespaceScoped: s.config.NamespaceScoped},
 		&scaffoldv2.LeaderElectionRole{},
 		&scaffoldv2.LeaderElectionRoleBinding{},
 		&scaffoldv2.KustomizeRBAC{},
-		&managerv2.Kustomization{},
+		&managerv2.Kustomization{ComponentConfig: s.config.ComponentConfig},
 		&webhookv2.Kustomization{},
 		&webhookv2.KustomizeConfigWebhook{},
-		&webhookv2.Service{},
+		&webhookv2.Service{WebhookPort: s.config.WebhookServerPort(), DualStack: s.config.IPv6()},
 		&webhookv2.InjectCAPatch{},
 		&prometheusv2.Kustomization{},
 		&prometheusv2.ServiceMonitor{},
 		&certmanagerv2.CertManager{},
 		&certmanagerv2.Kustomization{},
 		&certmanagerv2.KustomizeConfig{},
+		&e2ev2.SuiteTest{},
+		&e2ev2.SmokeTest{},
+		&e2ev2.Utils{},
+	}
+	// The projects in a subdirectory of a monorepo share its go.mod
+	if s.config.ModulePath == "" {
+		files = append(files, &scaffoldv2.GoMod{
+			ControllerRuntimeVersion: s.versions.ControllerRuntime,
+			KubernetesVersion:        s.pinnedKubernetesVersion(),
+			Vendor:                   s.config.Vendor,
+		})
+	}
+	if s.config.NamespaceScoped {
+		files = append(files, &scaffoldv2.ManagerNamespacePatch{})
+	}
+	if s.config.SecureDefaults {
+		files = append(files, &scaffoldv2.ManagerRelaxSecurityPatch{})
+	}
+	if s.config.ComponentConfig {
+		files = append(files,
+			&managerconfigv2.Types{},
+			&managerconfigv2.ConfigFile{
+				WebhookPort:    s.config.WebhookServerPort(),
+				WebhookHost:    s.config.WebhookHost,
+				MetricsAddress: s.config.MetricsLoopbackAddress(),
+			},
+		)
+	}
+	if s.config.Sharding {
+		files = append(files,
+			&shardingv2.Package{},
+			&shardingv2.Kustomization{},
+			&shardingv2.StatefulSet{
+				Image:           ImageName,
+				NamespaceScoped: s.config.NamespaceScoped,
+				SecureDefaults:  s.config.SecureDefaults,
+				MetricsAddress:  s.config.MetricsLoopbackAddress(),
+				IPv6:            s.config.IPv6(),
+			},
+			&shardingv2.DeploymentPatch{},
+		)
+	}
+
+	return files
+}
+
+// scaffoldAPIServer scaffolds an aggregated API server instead of a controller manager
+func (s *initScaffolder) scaffoldAPIServer() error {
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(s.config.Fs(), s.boilerplatePath),
 	)
+	if err != nil {
+		return fmt.Errorf("error initializing project: %v", err)
+	}
+
+	return s.newScaffold().Execute(
+		universe,
+		input.Options{ProjectPath: s.config.Path(), BoilerplatePath: s.boilerplatePath},
+		s.apiServerFiles()...,
+	)
+}
+
+// apiServerFiles returns the files scaffolded for aggregated API server projects
+func (s *initScaffolder) apiServerFiles() []input.File {
+	memoryStorage := s.config.IsMemoryStorage()
+	files := []input.File{
+		&apiserverv2.Main{MemoryStorage: memoryStorage},
+		&apiserverv2.Registry{MemoryStorage: memoryStorage},
+		&apiserverv2.Makefile{
+			Image:                  ImageName,
+			ControllerToolsVersion: s.versions.ControllerTools,
+			MemoryStorage:          memoryStorage,
+			ModuleDir:              s.config.ModuleDir(),
+		},
+		&scaffoldv2.Dockerfile{
+			APIServer: true,
+			BaseImage: s.config.BaseImage,
+			ModuleDir: s.config.ModuleDir(),
+			Vendor:    s.config.Vendor,
+		},
+		&apiserverv2.Kustomize{},
+		&apiserverv2.Kustomization{},
+		&apiserverv2.KustomizeConfig{},
+		&apiserverv2.Deployment{Image: ImageName, MemoryStorage: memoryStorage},
+		&apiserverv2.Service{},
+		&apiserverv2.Certificate{},
+		&apiserverv2.Role{},
+		&apiserverv2.RoleBinding{},
+		&apiserverv2.AuthDelegatorRoleBinding{},
+	}
+	// The projects in a subdirectory of a monorepo share its go.mod
+	if s.config.ModulePath == "" {
+		files = append(files,
+			&scaffoldv2.GoMod{
+				ControllerRuntimeVersion: s.versions.ControllerRuntime,
+				APIServerVersion:         s.versions.Kubernetes,
+				KubernetesVersion:        s.pinnedKubernetesVersion(),
+				Vendor:                   s.config.Vendor,
+			})
+	}
+
+	return files
+}
+
+// pinnedKubernetesVersion returns the version the Kubernetes libraries are pinned to in go.mod, only if the project
+// selected a Kubernetes version, otherwise controller-runtime requires them
+func (s *initScaffolder) pinnedKubernetesVersion() string {
+	if s.config.KubernetesVersion == "" {
+		return ""
+	}
+	return s.versions.Kubernetes
 }
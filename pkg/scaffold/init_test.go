@@ -0,0 +1,320 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("InitScaffolder", func() {
+	It("should package the manager with the base image recorded in the project configuration", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.BaseImage = modelconfig.BaseImageUBI
+		})
+
+		content := scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(content).To(ContainSubstring("\nFROM registry.access.redhat.com/ubi8/ubi-minimal:latest\n"))
+		Expect(content).NotTo(ContainSubstring("distroless"))
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.DockerBaseImage()).To(Equal(modelconfig.BaseImageUBI))
+	})
+
+	It("should serve the webhooks on the port recorded in the project configuration", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.WebhookPort = 10250
+		})
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`flag.IntVar(&webhookPort, "webhook-port", 10250,`))
+		Expect(content).To(ContainSubstring("Port:                   webhookPort,"))
+
+		for path, port := range map[string]string{
+			filepath.Join("config", "components", "webhook", "manager_webhook_patch.yaml"): "containerPort: 10250\n",
+			filepath.Join("config", "webhook", "service.yaml"):                             "targetPort: 10250\n",
+			filepath.Join("config", "components", "networkpolicy", "webhook_ingress.yaml"): "port: 10250\n",
+		} {
+			content = scaffoldtest.ReadFile(fs, path)
+			Expect(content).To(ContainSubstring(port), path)
+			Expect(content).NotTo(ContainSubstring("9443"), path)
+		}
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.WebhookServerPort()).To(Equal(10250))
+	})
+
+	It("should bind the manager to the IPv6 addresses recorded in the project configuration", func() {
+		fs, _ := scaffoldtest.NewProject(func(c *config.Config) {
+			c.MetricsBindAddress = "[::]:8080"
+			c.WebhookHost = "::"
+		})
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`flag.StringVar(&metricsAddr, "metrics-bind-address", "[::]:8080",`))
+		Expect(content).To(ContainSubstring(`flag.StringVar(&webhookHost, "webhook-host", "::",`))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "default", "manager_auth_proxy_patch.yaml"))
+		Expect(content).To(ContainSubstring(`"--secure-listen-address=[::]:8443"`))
+		Expect(content).To(ContainSubstring(`"--upstream=http://[::1]:8080/"`))
+		Expect(content).To(ContainSubstring(`"--metrics-bind-address=[::1]:8080"`))
+
+		for _, path := range []string{
+			filepath.Join("config", "rbac", "auth_proxy_service.yaml"),
+			filepath.Join("config", "webhook", "service.yaml"),
+		} {
+			content = scaffoldtest.ReadFile(fs, path)
+			Expect(content).To(ContainSubstring("ipFamilyPolicy: PreferDualStack\n"), path)
+		}
+	})
+
+	It("should share the go.mod of a monorepo and build the image from its root", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.Repo = "example.com/monorepo/operators/foo"
+			c.ModulePath = "example.com/monorepo"
+		})
+		Expect(c.ModuleDir()).To(Equal("operators/foo"))
+
+		Expect(afero.Exists(fs, "go.mod")).To(BeFalse())
+
+		content := scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(content).To(ContainSubstring("COPY . .\n"))
+		Expect(content).To(ContainSubstring("go build -a -o manager ./operators/foo\n"))
+
+		content = scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(ContainSubstring("docker build -f Dockerfile -t ${IMG} ../..\n"))
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.ModulePath).To(Equal("example.com/monorepo"))
+	})
+
+	It("should build with the vendored dependencies recorded in the project configuration", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.Vendor = true
+		})
+
+		content := scaffoldtest.ReadFile(fs, "go.mod")
+		Expect(content).To(ContainSubstring("\ngo 1.14\n"))
+
+		content = scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(content).To(ContainSubstring("\nCOPY vendor/ vendor/\n"))
+		Expect(content).To(ContainSubstring(" go build -mod=vendor -a -o manager main.go\n"))
+		Expect(content).NotTo(ContainSubstring("go mod download"))
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Vendor).To(BeTrue())
+	})
+
+	It("should pin the dependency versions of the Kubernetes version recorded in the project configuration", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.KubernetesVersion = "1.18"
+		})
+
+		content := scaffoldtest.ReadFile(fs, "go.mod")
+		Expect(content).To(ContainSubstring("\tk8s.io/apimachinery v0.18.2\n"))
+		Expect(content).To(ContainSubstring("\tk8s.io/client-go v0.18.2\n"))
+		Expect(content).To(ContainSubstring("\tsigs.k8s.io/controller-runtime v0.6.0\n"))
+
+		content = scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(ContainSubstring("sigs.k8s.io/controller-tools/cmd/controller-gen@v0.3.0"))
+
+		c = scaffoldtest.NewConfig()
+		c.KubernetesVersion = "1.10"
+		Expect(scaffold.NewInitScaffolder(c, "none", "", nil, "").Scaffold()).To(
+			MatchError(ContainSubstring(`unsupported Kubernetes version "1.10"`)))
+	})
+
+	It("should scaffold the sharding package and the StatefulSet of the shards", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.Sharding = true
+		})
+
+		res := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		Expect(scaffold.NewAPIScaffolder(c, res, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("sharding", "sharding.go"))
+		Expect(content).To(ContainSubstring(`const Label = "sharding.example.com/shard"`))
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring("\tshard, err := sharding.FromEnv()\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "sharding", "manager_statefulset.yaml"))
+		Expect(content).To(ContainSubstring("  replicas: 2\n"))
+		Expect(content).To(ContainSubstring("        - name: SHARD_COUNT\n          value: \"2\"\n"))
+		Expect(content).NotTo(ContainSubstring("--enable-leader-election"))
+
+		content = scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(content).To(ContainSubstring("\nCOPY sharding/ sharding/\n"))
+
+		content = scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(ContainSubstring("\tkustomize build config/sharding | kubectl apply -f -\n"))
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sharding).To(BeTrue())
+	})
+
+	It("should stamp the files with their provenance", func() {
+		fs, _ := scaffoldtest.NewProject(nil)
+
+		content := scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(HaveSuffix("}\n\n// kubebuilder:provenance version=unknown " +
+			"plugin=go.kubebuilder.io/v2 template=v2.Main\n"))
+
+		provenances, err := scaffold.ReadProvenance(fs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provenances).To(ContainElement(scaffold.Provenance{
+			Path:     "config/manager/manager.yaml",
+			Version:  "unknown",
+			Plugin:   "go.kubebuilder.io/v2",
+			Template: "v2/manager.Config",
+		}))
+		for _, p := range provenances {
+			Expect(p.Path).NotTo(Equal("go.mod"))
+		}
+
+		// The provenance is only stamped in the files, the manifest records their hashes
+		content = scaffoldtest.ReadFile(fs, filepath.Join(".kubebuilder", "manifest.yaml"))
+		Expect(content).NotTo(ContainSubstring("template: v2.Main"))
+	})
+
+	It("should provision the dashboards and the alerts with the observability plugin", func() {
+		c := scaffoldtest.NewConfig()
+		fs := c.Fs()
+		plugins := scaffold.ObservabilityPlugin{}.InitPlugins()
+		Expect(scaffold.NewInitScaffolder(c, "none", "", plugins, "").Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("components:\n- ../components/prometheus\n" +
+			"- ../components/observability\n# +kubebuilder:scaffold:components\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "components", "observability", "alerts.yaml"))
+		Expect(content).To(ContainSubstring("kind: PrometheusRule\n"))
+		Expect(content).To(ContainSubstring("{{ $labels.controller }}"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "grafana", "controller-runtime-metrics.json"))
+		Expect(content).To(ContainSubstring(`"legendFormat": "{{controller}} p99"`))
+
+		for _, path := range []string{
+			filepath.Join("config", "components", "observability", "kustomization.yaml"),
+			filepath.Join("config", "grafana", "kustomization.yaml"),
+		} {
+			Expect(afero.Exists(fs, path)).To(BeTrue())
+		}
+	})
+
+	It("should load the options of the manager from the ConfigMap of the ControllerManagerConfiguration", func() {
+		fs, c := scaffoldtest.NewProject(func(c *config.Config) {
+			c.ComponentConfig = true
+		})
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("managerconfig", "v1alpha1",
+			"controllermanagerconfiguration_types.go"))
+		Expect(content).To(ContainSubstring(
+			`GroupVersion = schema.GroupVersion{Group: "config.example.com", Version: "v1alpha1"}`))
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`configv1alpha1 "example.com/project/managerconfig/v1alpha1"`))
+		Expect(content).To(ContainSubstring("options = managerConfig.AndFrom(options)"))
+		Expect(content).To(ContainSubstring("mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "manager", "controller_manager_config.yaml"))
+		Expect(content).To(ContainSubstring("apiVersion: config.example.com/v1alpha1\n"))
+		Expect(content).To(ContainSubstring("resourceName: project.example.com\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "manager", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("- name: manager-config\n  files:\n  - controller_manager_config.yaml\n"))
+
+		for _, path := range []string{
+			filepath.Join("config", "manager", "manager.yaml"),
+			filepath.Join("config", "default", "manager_auth_proxy_patch.yaml"),
+		} {
+			content = scaffoldtest.ReadFile(fs, path)
+			Expect(content).To(ContainSubstring("--config=/etc/manager/controller_manager_config.yaml"), path)
+			Expect(content).NotTo(ContainSubstring("--enable-leader-election"), path)
+		}
+
+		content = scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(content).To(ContainSubstring("COPY managerconfig/ managerconfig/\n"))
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.ComponentConfig).To(BeTrue())
+	})
+
+	It("should add the header of a custom license to the scaffolded Go files", func() {
+		c := scaffoldtest.NewConfig()
+		fs := c.Fs()
+		Expect(afero.WriteFile(fs, "header.txt", []byte("// Copyright {{ .Owner }}\n// Proprietary\n"), 0644)).
+			To(Succeed())
+		Expect(scaffold.NewInitScaffolder(c, "header.txt", "Example Owners", nil, "").Scaffold()).To(Succeed())
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, true, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+
+		for _, path := range []string{
+			"main.go",
+			filepath.Join("api", "v1", "frigate_types.go"),
+			filepath.Join("controllers", "frigate_controller.go"),
+			filepath.Join("controllers", "suite_test.go"),
+		} {
+			Expect(scaffoldtest.ReadFile(fs, path)).To(HavePrefix("// Copyright Example Owners\n// Proprietary\n\npackage "), path)
+		}
+	})
+
+	It("should only scaffold the missing files when adopting an existing module", func() {
+		c := scaffoldtest.NewConfig()
+		fs := c.Fs()
+		existing := map[string]string{
+			"go.mod":   "module example.com/project\n",
+			"main.go":  "package main\n\nfunc main() {}\n",
+			"Makefile": "all:\n\tgo build ./...\n",
+		}
+		for path, content := range existing {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0644)).To(Succeed())
+		}
+		Expect(scaffold.NewAdoptScaffolder(c, "none", "", nil, "").Scaffold()).To(Succeed())
+
+		for path, expected := range existing {
+			Expect(scaffoldtest.ReadFile(fs, path)).To(Equal(expected), path)
+		}
+		for _, path := range []string{
+			"Dockerfile",
+			filepath.Join("config", "default", "kustomization.yaml"),
+			filepath.Join("config", "rbac", "role_binding.yaml"),
+		} {
+			Expect(afero.Exists(fs, path)).To(BeTrue(), path)
+		}
+
+		c, err := config.LoadFromFs(fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Repo).To(Equal("example.com/project"))
+	})
+})
@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inject inserts code fragments at the injection points of the scaffolded files, the lines with a
+// +kubebuilder:scaffold:<name> marker, e.g. to wire a new resource in main.go:
+//
+//	err := inject.Update(fs, "main.go", inject.Fragments{
+//		"// +kubebuilder:scaffold:scheme": {"_ = shipv1.AddToScheme(scheme)\n"},
+//	})
+//
+// The fragments are inserted right before their marker, so that the next ones are inserted after them, and only
+// once: the fragments already in the file are skipped. Any line may be used as marker, e.g. a resource of a
+// kustomization the fragments are listed before. The fragments that can't be inserted, e.g. because the
+// user removed their marker, are reported as conflicts.
+package inject
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/tools/imports"
+)
+
+// MarkerPrefix is the prefix of the markers of the injection points, after the comment delimiter
+const MarkerPrefix = "+kubebuilder:scaffold:"
+
+// Fragments are the code fragments inserted before each marker, in order
+type Fragments map[string][]string
+
+// Merge appends the fragments of src to the ones of dst for each marker, skipping the duplicated ones
+func Merge(dst, src Fragments) {
+	for marker, values := range src {
+		for _, value := range values {
+			if !containsString(dst[marker], value) {
+				dst[marker] = append(dst[marker], value)
+			}
+		}
+	}
+}
+
+// ConflictReason tells why fragments couldn't be inserted at an injection point
+type ConflictReason string
+
+const (
+	// MarkerNotFound is the reason of the fragments whose marker isn't in the file, they aren't inserted
+	MarkerNotFound ConflictReason = "not found"
+	// MarkerDuplicated is the reason of the markers found more than once, the fragments are only inserted
+	// before the first one
+	MarkerDuplicated ConflictReason = "found more than once"
+)
+
+// Conflict is an injection point the fragments couldn't be inserted at as expected
+type Conflict struct {
+	// Path is the file of the injection point
+	Path string
+	// Marker is the marker of the injection point
+	Marker string
+	// Reason tells what is wrong with the injection point
+	Reason ConflictReason
+	// Fragments are the fragments of the marker that weren't inserted, if any
+	Fragments []string
+}
+
+// String implements fmt.Stringer
+func (c Conflict) String() string {
+	msg := fmt.Sprintf("%s: the marker %q was %s", c.Path, c.Marker, c.Reason)
+	if len(c.Fragments) != 0 {
+		msg += fmt.Sprintf(", insert manually:\n%s", strings.Join(c.Fragments, ""))
+	}
+	return msg
+}
+
+// ConflictError is returned by Result.Err when there were conflicts
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+// Error implements error
+func (e *ConflictError) Error() string {
+	msgs := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		msgs = append(msgs, c.String())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Result is the outcome of inserting fragments
+type Result struct {
+	// Inserted are the fragments inserted before each marker, without the ones already there
+	Inserted Fragments
+	// Conflicts are the injection points the fragments couldn't be inserted at as expected
+	Conflicts []Conflict
+}
+
+// Changed returns whether fragments were inserted
+func (r Result) Changed() bool {
+	return len(r.Inserted) != 0
+}
+
+// Err returns a ConflictError if there were conflicts, nil otherwise
+func (r Result) Err() error {
+	if len(r.Conflicts) == 0 {
+		return nil
+	}
+	return &ConflictError{Conflicts: r.Conflicts}
+}
+
+// Markers returns the markers of the injection points of the content, in order
+func Markers(content []byte) []string {
+	var markers []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if isMarker(line) {
+			markers = append(markers, strings.TrimSpace(line))
+		}
+	}
+	return markers
+}
+
+// Insert inserts the fragments before their marker in the content and returns the new content. The markers are
+// matched ignoring the indentation, and the fragments already in the content are skipped, the multi-line ones
+// ignoring whitespace as they may have been formatted after being inserted.
+func Insert(content []byte, fragments Fragments) ([]byte, Result) {
+	result := Result{Inserted: Fragments{}}
+	pending := missingFragments(content, fragments)
+
+	markers := make(map[string]string, len(pending))
+	for marker := range pending {
+		markers[strings.TrimSpace(marker)] = marker
+	}
+
+	found := make(map[string]int, len(pending))
+	out := new(bytes.Buffer)
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if marker, ok := markers[strings.TrimSpace(line)]; ok {
+			if found[marker]++; found[marker] == 1 && len(pending[marker]) != 0 {
+				for _, value := range pending[marker] {
+					out.WriteString(value)
+				}
+				result.Inserted[marker] = pending[marker]
+			}
+		}
+		out.WriteString(line)
+	}
+
+	for _, marker := range sortedKeys(pending) {
+		switch {
+		case found[marker] == 0 && len(pending[marker]) != 0:
+			result.Conflicts = append(result.Conflicts,
+				Conflict{Marker: marker, Reason: MarkerNotFound, Fragments: pending[marker]})
+		case found[marker] > 1 && len(pending[marker]) != 0:
+			result.Conflicts = append(result.Conflicts, Conflict{Marker: marker, Reason: MarkerDuplicated})
+		}
+	}
+	if !result.Changed() {
+		return content, result
+	}
+	return out.Bytes(), result
+}
+
+// File inserts the fragments in the file of the path, which is only written if fragments were inserted. Go files
+// are formatted and their imports sorted.
+func File(fs afero.Fs, path string, fragments Fragments) (Result, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	content, result := Insert(content, fragments)
+	for i := range result.Conflicts {
+		result.Conflicts[i].Path = path
+	}
+	if !result.Changed() {
+		return result, nil
+	}
+
+	if filepath.Ext(path) == ".go" {
+		content, err = imports.Process(path, content, nil)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, afero.WriteFile(fs, path, content, os.ModePerm)
+}
+
+// Update inserts the fragments in the file of the path like File, the conflicts are printed as warnings so that
+// the user inserts the fragments that weren't
+func Update(fs afero.Fs, path string, fragments Fragments) error {
+	result, err := File(fs, path, fragments)
+	if err != nil {
+		return err
+	}
+	for _, c := range result.Conflicts {
+		fmt.Printf("Warning: %s\n", c)
+	}
+	return nil
+}
+
+// missingFragments returns the fragments that aren't in the content yet, without the duplicates
+func missingFragments(content []byte, fragments Fragments) Fragments {
+	normalized := normalizeSpace(string(content))
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		lines[strings.TrimSpace(line)] = true
+	}
+
+	missing := make(Fragments, len(fragments))
+	for marker, values := range fragments {
+		missing[marker] = make([]string, 0, len(values))
+		for _, value := range values {
+			if strings.Contains(strings.TrimSpace(value), "\n") {
+				if strings.Contains(normalized, normalizeSpace(value)) {
+					continue
+				}
+			} else if lines[strings.TrimSpace(value)] {
+				continue
+			}
+			if !containsString(missing[marker], value) {
+				missing[marker] = append(missing[marker], value)
+			}
+		}
+	}
+	return missing
+}
+
+// isMarker returns whether the line is the marker of an injection point
+func isMarker(line string) bool {
+	return strings.Contains(line, MarkerPrefix)
+}
+
+// normalizeSpace replaces every sequence of whitespace with a single space
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func sortedKeys(fragments Fragments) []string {
+	keys := make([]string, 0, len(fragments))
+	for key := range fragments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
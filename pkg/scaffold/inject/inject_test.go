@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+type insertTest struct {
+	input     string
+	fragments Fragments
+	expected  string
+}
+
+func TestInsert(t *testing.T) {
+	tests := []insertTest{
+		{
+			input: `
+v1beta1.AddToScheme(scheme)
+// +kubebuilder:scaffold:apis-add-scheme
+`,
+			fragments: Fragments{
+				"// +kubebuilder:scaffold:apis-add-scheme": {
+					"v1.AddToScheme(scheme)\n", "somefunc()\n",
+				},
+			},
+			expected: `
+v1beta1.AddToScheme(scheme)
+v1.AddToScheme(scheme)
+somefunc()
+// +kubebuilder:scaffold:apis-add-scheme
+`,
+		},
+		{ // avoid duplicates
+			input: `
+v1beta1.AddToScheme(scheme)
+// +kubebuilder:scaffold:apis-add-scheme
+`,
+			fragments: Fragments{
+				"// +kubebuilder:scaffold:apis-add-scheme": {
+					"v1beta1.AddToScheme(scheme)\n", "v1.AddToScheme(scheme)\n",
+				},
+			},
+			expected: `
+v1beta1.AddToScheme(scheme)
+v1.AddToScheme(scheme)
+// +kubebuilder:scaffold:apis-add-scheme
+`,
+		},
+		{
+			// string with literal format
+			input: `
+v1beta1.AddToScheme(scheme)
+// +kubebuilder:scaffold:apis-add-scheme
+`,
+			fragments: Fragments{
+				"// +kubebuilder:scaffold:apis-add-scheme": {
+					`v1.AddToScheme(scheme)
+`,
+				},
+			},
+			expected: `
+v1beta1.AddToScheme(scheme)
+v1.AddToScheme(scheme)
+// +kubebuilder:scaffold:apis-add-scheme
+`,
+		},
+		{ // avoid duplicates of multi-line values that were formatted after being inserted
+			input: `
+	if err = (&controllers.FrigateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log,
+	}).SetupWithManager(mgr); err != nil {
+		os.Exit(1)
+	}
+	// +kubebuilder:scaffold:builder
+`,
+			fragments: Fragments{
+				"// +kubebuilder:scaffold:builder": {
+					`if err = (&controllers.FrigateReconciler{
+		Client: mgr.GetClient(),
+		Log: ctrl.Log,
+	}).SetupWithManager(mgr); err != nil {
+		os.Exit(1)
+	}
+`,
+				},
+			},
+			expected: `
+	if err = (&controllers.FrigateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log,
+	}).SetupWithManager(mgr); err != nil {
+		os.Exit(1)
+	}
+	// +kubebuilder:scaffold:builder
+`,
+		},
+	}
+
+	for _, test := range tests {
+		result, _ := Insert([]byte(test.input), test.fragments)
+		if string(result) != test.expected {
+			t.Errorf("got: %s and wanted: %s", string(result), test.expected)
+		}
+	}
+}
+
+func TestInsertConflicts(t *testing.T) {
+	input := `
+// +kubebuilder:scaffold:imports
+// +kubebuilder:scaffold:builder
+// +kubebuilder:scaffold:builder
+`
+	output, result := Insert([]byte(input), Fragments{
+		"// +kubebuilder:scaffold:scheme":  {"_ = v1.AddToScheme(scheme)\n"},
+		"// +kubebuilder:scaffold:builder": {"setup()\n"},
+		"// +kubebuilder:scaffold:imports": {},
+	})
+
+	expected := `
+// +kubebuilder:scaffold:imports
+setup()
+// +kubebuilder:scaffold:builder
+// +kubebuilder:scaffold:builder
+`
+	if string(output) != expected {
+		t.Errorf("got: %s and wanted: %s", string(output), expected)
+	}
+	if !reflect.DeepEqual(result.Inserted, Fragments{"// +kubebuilder:scaffold:builder": {"setup()\n"}}) {
+		t.Errorf("unexpected inserted fragments: %v", result.Inserted)
+	}
+
+	expectedConflicts := []Conflict{
+		{Marker: "// +kubebuilder:scaffold:builder", Reason: MarkerDuplicated},
+		{
+			Marker:    "// +kubebuilder:scaffold:scheme",
+			Reason:    MarkerNotFound,
+			Fragments: []string{"_ = v1.AddToScheme(scheme)\n"},
+		},
+	}
+	if !reflect.DeepEqual(result.Conflicts, expectedConflicts) {
+		t.Errorf("got conflicts: %v and wanted: %v", result.Conflicts, expectedConflicts)
+	}
+	if result.Err() == nil {
+		t.Errorf("expected a conflict error")
+	}
+
+	// Nothing is reported once the fragments are inserted
+	_, result = Insert(output, Fragments{"// +kubebuilder:scaffold:builder": {"setup()\n"}})
+	if result.Changed() || result.Err() != nil {
+		t.Errorf("expected no changes nor conflicts, got: %v", result)
+	}
+}
+
+func TestFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	input := `package main
+
+import (
+	// +kubebuilder:scaffold:imports
+)
+
+func main() {
+	// +kubebuilder:scaffold:builder
+}
+`
+	if err := afero.WriteFile(fs, "main.go", []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fragments := Fragments{
+		"// +kubebuilder:scaffold:imports": {"\"fmt\"\n"},
+		"// +kubebuilder:scaffold:builder": {"fmt.Println(\"hello\")\n"},
+	}
+	result, err := File(fs, "main.go", fragments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed() {
+		t.Errorf("expected main.go to be changed")
+	}
+
+	content, err := afero.ReadFile(fs, "main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `package main
+
+import (
+	"fmt"
+	// +kubebuilder:scaffold:imports
+)
+
+func main() {
+	fmt.Println("hello")
+	// +kubebuilder:scaffold:builder
+}
+`
+	if string(content) != expected {
+		t.Errorf("got: %s and wanted: %s", string(content), expected)
+	}
+
+	// The formatted fragments are found, the file is left untouched
+	result, err = File(fs, "main.go", fragments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed() {
+		t.Errorf("expected main.go to be unchanged, inserted: %v", result.Inserted)
+	}
+
+	result, err = File(fs, "main.go", Fragments{"// +kubebuilder:scaffold:scheme": {"_ = v1.AddToScheme(scheme)\n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Path != "main.go" {
+		t.Errorf("expected a conflict in main.go, got: %v", result.Conflicts)
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	markers := Markers([]byte(`resources:
+- bases/ship.example.org_frigates.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patchesStrategicMerge:
+  # +kubebuilder:scaffold:crdkustomizewebhookpatch
+`))
+	expected := []string{
+		"# +kubebuilder:scaffold:crdkustomizeresource",
+		"# +kubebuilder:scaffold:crdkustomizewebhookpatch",
+	}
+	if !reflect.DeepEqual(markers, expected) {
+		t.Errorf("got: %v and wanted: %v", markers, expected)
+	}
+}
@@ -165,6 +165,14 @@ type RequiresValidation interface {
 	Validate() error
 }
 
+// HasDelimiters is a file whose template uses custom delimiters, e.g. because the
+// scaffolded file is a template itself
+type HasDelimiters interface {
+	File
+	// Delimiters returns the left and right delimiters of the template
+	Delimiters() (string, string)
+}
+
 // Options are the options for executing scaffold templates
 type Options struct {
 	// BoilerplatePath is the path to the boilerplate file
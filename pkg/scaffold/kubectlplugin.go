@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/kubectlplugin"
+)
+
+// kubectlPluginScaffolder scaffolds a kubectl plugin reading the objects of the tracked resources
+type kubectlPluginScaffolder struct {
+	config *config.Config
+	// name is the name of the plugin, run as kubectl <name>
+	name string
+}
+
+func NewKubectlPluginScaffolder(config *config.Config, name string) Scaffolder {
+	return &kubectlPluginScaffolder{
+		config: config,
+		name:   name,
+	}
+}
+
+func (s *kubectlPluginScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("kubectl plugins are not supported for project version %v", s.config.Version)
+	}
+	if len(s.config.Resources) == 0 {
+		return errors.New("the project has no APIs, create one first with kubebuilder create api")
+	}
+
+	// The first version of each Kind is read, the objects are converted to it by the API server
+	kinds := make([]kubectlplugin.Kind, 0, len(s.config.Resources))
+	imports := make([]kubectlplugin.Import, 0, len(s.config.Resources))
+	seenKinds, seenImports := map[string]bool{}, map[string]bool{}
+	for _, gvk := range s.config.Resources {
+		r := &resource.Resource{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Resource: gvk.Plural,
+			GroupPackage: s.config.GroupPackage(gvk.Group)}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("invalid resource %s/%s, Kind=%s: %v", gvk.Group, gvk.Version, gvk.Kind, err)
+		}
+
+		group := r.QualifiedGroup(s.config.Domain)
+		if seenKinds[group+"/"+r.Kind] {
+			continue
+		}
+		seenKinds[group+"/"+r.Kind] = true
+
+		alias := r.GroupImportSafe + r.Version
+		if !seenImports[alias] {
+			seenImports[alias] = true
+			imports = append(imports, kubectlplugin.Import{Alias: alias, Path: s.apiPackage(r)})
+		}
+		kinds = append(kinds, kubectlplugin.Kind{
+			Alias:      alias,
+			Kind:       r.Kind,
+			Plural:     r.Plural(),
+			Group:      group,
+			Namespaced: !gvk.ClusterScoped,
+		})
+	}
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(s.config.Fs(), boilerplatePath),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := (&Scaffold{Fs: s.config.Fs()}).Execute(
+		universe,
+		input.Options{},
+		&kubectlplugin.Main{Name: s.name},
+		&kubectlplugin.Kinds{Name: s.name, Imports: imports, Kinds: kinds},
+	); err != nil {
+		return err
+	}
+
+	binary := kubectlplugin.BinaryName(s.name)
+	if err := (&scaffoldv2.Makefile{}).AddKubectlPluginTarget(s.config.Fs(), binary); err != nil {
+		return fmt.Errorf("error adding the kubectl-plugin target to the Makefile: %v", err)
+	}
+
+	fmt.Printf(`kubectl plugin scaffolded in %s.
+Run "make kubectl-plugin" to build bin/%s and add it to your PATH to run "kubectl %s get".
+Re-run this command after adding APIs, manual changes to %s/kinds.go are overwritten.
+`, kubectlplugin.Dir(s.name), binary, s.name, kubectlplugin.Dir(s.name))
+
+	return nil
+}
+
+// apiPackage returns the import path of the Go package of the API version of the resource
+func (s *kubectlPluginScaffolder) apiPackage(r *resource.Resource) string {
+	if s.config.MultiGroup {
+		return path.Join(s.config.Repo, "apis", r.Group, r.Version)
+	}
+	return path.Join(s.config.Repo, "api", r.Version)
+}
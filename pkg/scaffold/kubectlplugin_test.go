@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("KubectlPluginScaffolder", func() {
+	It("should read the first version of each Kind and regenerate the Kinds only", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		for _, res := range []*resource.Resource{
+			{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true},
+			{Group: "ship", Version: "v2", Kind: "Frigate", Namespaced: true},
+			{Group: "ship", Version: "v1", Kind: "Destroyer"},
+		} {
+			Expect(scaffold.NewAPIScaffolder(c, res, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		}
+		Expect(scaffold.NewKubectlPluginScaffolder(c, "my-fleet").Scaffold()).To(Succeed())
+
+		mainPath := filepath.Join("cmd", "kubectl-my_fleet", "main.go")
+		content := scaffoldtest.ReadFile(fs, mainPath)
+		Expect(content).To(ContainSubstring(`Use:          "kubectl my-fleet",`))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("cmd", "kubectl-my_fleet", "kinds.go"))
+		Expect(content).To(ContainSubstring(`shipv1 "example.com/project/api/v1"`))
+		Expect(content).NotTo(ContainSubstring("shipv2"))
+		Expect(strings.Count(content, `kind:       "Frigate",`)).To(Equal(1))
+		Expect(content).To(ContainSubstring("return &shipv1.DestroyerList{}"))
+		Expect(content).To(ContainSubstring("namespaced: false,"))
+
+		content = scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(ContainSubstring("go build -o bin/kubectl-my_fleet ./cmd/kubectl-my_fleet\n"))
+
+		// The commands are kept and the Kinds of the new APIs are added
+		Expect(afero.WriteFile(fs, mainPath, []byte("package main\n"), 0644)).To(Succeed())
+		cruiser := &resource.Resource{Group: "ship", Version: "v1", Kind: "Cruiser", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, cruiser, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		Expect(scaffold.NewKubectlPluginScaffolder(c, "my-fleet").Scaffold()).To(Succeed())
+
+		content = scaffoldtest.ReadFile(fs, mainPath)
+		Expect(content).To(Equal("package main\n"))
+		content = scaffoldtest.ReadFile(fs, filepath.Join("cmd", "kubectl-my_fleet", "kinds.go"))
+		Expect(content).To(ContainSubstring("return &shipv1.Cruiser{}"))
+	})
+
+	It("should fail in projects without APIs", func() {
+		_, c := scaffoldtest.NewProject(nil)
+
+		Expect(scaffold.NewKubectlPluginScaffolder(c, "fleet").Scaffold()).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+// manifestPath is where the files scaffolded in the project are recorded, so that the ones that were not modified
+// since can be updated when their templates change
+var manifestPath = filepath.Join(".kubebuilder", "manifest.yaml")
+
+// manifest records how the files of the project were scaffolded
+type manifest struct {
+	// Files are the scaffolded files by their slash-separated path
+	Files map[string]scaffoldedFile `json:"files,omitempty"`
+}
+
+// scaffoldedFile records the template and the contents that a file was scaffolded with, its provenance is stamped
+// in the file itself
+type scaffoldedFile struct {
+	// TemplateHash is the hash of the template of the file, empty for the files added by plugins
+	TemplateHash string `json:"templateHash,omitempty"`
+	// Hash is the hash of the file as it was scaffolded
+	Hash string `json:"hash"`
+}
+
+// readManifest reads the manifest of the project, which is empty for the projects scaffolded before it was recorded
+func readManifest(fs afero.Fs) (*manifest, error) {
+	m := &manifest{Files: make(map[string]scaffoldedFile)}
+
+	content, err := afero.ReadFile(fs, manifestPath)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", manifestPath, err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]scaffoldedFile)
+	}
+	return m, nil
+}
+
+// write writes the manifest to the project
+func (m *manifest) write(fs afero.Fs) error {
+	content, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return (&FileWriter{Fs: fs}).WriteFile(manifestPath, content)
+}
+
+// get returns how the file at path was scaffolded and whether it was recorded
+func (m *manifest) get(path string) (scaffoldedFile, bool) {
+	f, found := m.Files[filepath.ToSlash(path)]
+	return f, found
+}
+
+// record records that the file was scaffolded from template
+func (m *manifest) record(file *model.File, template string) {
+	f := scaffoldedFile{Hash: hash(file.Contents)}
+	if template != "" {
+		f.TemplateHash = hash(template)
+	}
+	m.Files[filepath.ToSlash(file.Path)] = f
+}
+
+// remove forgets the file at path, returning whether it was recorded
+func (m *manifest) remove(path string) bool {
+	if _, found := m.get(path); !found {
+		return false
+	}
+	delete(m.Files, filepath.ToSlash(path))
+	return true
+}
+
+// move records the files under oldDir under newDir instead, returning whether any of them was recorded
+func (m *manifest) move(oldDir, newDir string) bool {
+	oldPrefix, newPrefix := filepath.ToSlash(oldDir)+"/", filepath.ToSlash(newDir)+"/"
+	moved := make(map[string]scaffoldedFile)
+	for path, f := range m.Files {
+		if strings.HasPrefix(path, oldPrefix) {
+			delete(m.Files, path)
+			moved[newPrefix+strings.TrimPrefix(path, oldPrefix)] = f
+		}
+	}
+	for path, f := range moved {
+		m.Files[path] = f
+	}
+	return len(moved) != 0
+}
+
+// hash returns the hex-encoded SHA-256 hash of content
+func hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
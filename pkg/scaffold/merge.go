@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// mergeBaseDir is the directory where the files are recorded as they were scaffolded,
+// so that they can be used as the base of a three-way merge when they are scaffolded again
+var mergeBaseDir = filepath.Join(".kubebuilder", "scaffolds")
+
+// mergeBasePath returns the path where the scaffolded version of the file at path is recorded
+func mergeBasePath(path string) string {
+	return filepath.Join(mergeBaseDir, path)
+}
+
+const (
+	conflictStart = "<<<<<<< current"
+	conflictSep   = "======="
+	conflictEnd   = ">>>>>>> scaffold"
+)
+
+// merge3 merges the changes made to base in current and in scaffolded, like diff3 does.
+// Changes that overlap are kept between conflict markers, and their number is returned.
+func merge3(base, current, scaffolded []byte) ([]byte, int) {
+	o, a, b := splitLines(base), splitLines(current), splitLines(scaffolded)
+	matchA, matchB := matchLines(o, a), matchLines(o, b)
+
+	merged := make([]string, 0, len(a))
+	conflicts := 0
+	i, j, k := 0, 0, 0
+	for i < len(o) || j < len(a) || k < len(b) {
+		// The base line is unchanged in both versions
+		if i < len(o) && matchA[i] == j && matchB[i] == k {
+			merged = append(merged, o[i])
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+
+		// Find the next base line that is unchanged in both versions, the lines before it were changed
+		next := i
+		for next < len(o) && (matchA[next] < 0 || matchB[next] < 0) {
+			next++
+		}
+		nextA, nextB := len(a), len(b)
+		if next < len(o) {
+			nextA, nextB = matchA[next], matchB[next]
+		}
+
+		chunkO, chunkA, chunkB := o[i:next], a[j:nextA], b[k:nextB]
+		switch {
+		case equalLines(chunkA, chunkO):
+			merged = append(merged, chunkB...)
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			merged = append(merged, chunkA...)
+		default:
+			conflicts++
+			merged = append(merged, conflictStart)
+			merged = append(merged, chunkA...)
+			merged = append(merged, conflictSep)
+			merged = append(merged, chunkB...)
+			merged = append(merged, conflictEnd)
+		}
+		i, j, k = next, nextA, nextB
+	}
+
+	if len(merged) == 0 {
+		return nil, conflicts
+	}
+	return []byte(strings.Join(merged, "\n") + "\n"), conflicts
+}
+
+// matchLines returns, for every line of a, the position of the same line in b or -1 if it was removed
+func matchLines(a, b []string) []int {
+	match := make([]int, len(a))
+	for _, line := range diffLines(a, b) {
+		switch line.op {
+		case ' ':
+			match[line.oldLine-1] = line.newLine - 1
+		case '-':
+			match[line.oldLine-1] = -1
+		}
+	}
+	return match
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
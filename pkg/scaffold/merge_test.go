@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       string
+		current    string
+		scaffolded string
+		merged     string
+		conflicts  int
+	}{
+		{
+			name:       "unchanged",
+			base:       "a\nb\nc\n",
+			current:    "a\nb\nc\n",
+			scaffolded: "a\nb\nc\n",
+			merged:     "a\nb\nc\n",
+		},
+		{
+			name:       "changed in the current file only",
+			base:       "a\nb\nc\n",
+			current:    "a\nB\nc\nd\n",
+			scaffolded: "a\nb\nc\n",
+			merged:     "a\nB\nc\nd\n",
+		},
+		{
+			name:       "changed in the scaffold only",
+			base:       "a\nb\nc\n",
+			current:    "a\nb\nc\n",
+			scaffolded: "a\nc\nd\n",
+			merged:     "a\nc\nd\n",
+		},
+		{
+			name:       "changes that don't overlap",
+			base:       "a\nb\nc\nd\ne\n",
+			current:    "a\nB\nc\nd\ne\n",
+			scaffolded: "a\nb\nc\nd\nE\nf\n",
+			merged:     "a\nB\nc\nd\nE\nf\n",
+		},
+		{
+			name:       "the same change on both sides",
+			base:       "a\nb\nc\n",
+			current:    "a\nB\nc\n",
+			scaffolded: "a\nB\nc\n",
+			merged:     "a\nB\nc\n",
+		},
+		{
+			name:       "changes that overlap",
+			base:       "a\nb\nc\n",
+			current:    "a\nmine\nc\n",
+			scaffolded: "a\ntheirs\nc\n",
+			merged:     "a\n<<<<<<< current\nmine\n=======\ntheirs\n>>>>>>> scaffold\nc\n",
+			conflicts:  1,
+		},
+		{
+			name:       "several changes that overlap",
+			base:       "a\nb\nc\nd\ne\n",
+			current:    "a\nB1\nc\nD1\ne\n",
+			scaffolded: "a\nB2\nc\nD2\ne\n",
+			merged: "a\n<<<<<<< current\nB1\n=======\nB2\n>>>>>>> scaffold\nc\n" +
+				"<<<<<<< current\nD1\n=======\nD2\n>>>>>>> scaffold\ne\n",
+			conflicts: 2,
+		},
+		{
+			name:       "an empty base with the same files",
+			base:       "",
+			current:    "a\nb\n",
+			scaffolded: "a\nb\n",
+			merged:     "a\nb\n",
+		},
+		{
+			name:       "an empty base with different files",
+			base:       "",
+			current:    "a\nb\n",
+			scaffolded: "a\nc\n",
+			merged:     "<<<<<<< current\na\nb\n=======\na\nc\n>>>>>>> scaffold\n",
+			conflicts:  1,
+		},
+		{
+			name:       "an empty base and current file",
+			base:       "",
+			current:    "",
+			scaffolded: "a\n",
+			merged:     "a\n",
+		},
+		{
+			name:       "a file removed in the current file",
+			base:       "a\n",
+			current:    "",
+			scaffolded: "a\n",
+			merged:     "",
+		},
+	}
+
+	for _, test := range tests {
+		merged, conflicts := merge3([]byte(test.base), []byte(test.current), []byte(test.scaffolded))
+		if string(merged) != test.merged {
+			t.Errorf("%s: expected\n%q\ngot\n%q", test.name, test.merged, merged)
+		}
+		if conflicts != test.conflicts {
+			t.Errorf("%s: expected %d conflicts, got %d", test.name, test.conflicts, conflicts)
+		}
+	}
+}
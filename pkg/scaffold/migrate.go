@@ -0,0 +1,471 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// v1ReplacedPaths are the v1 project files and directories that are superseded by the v2 layout
+var v1ReplacedPaths = []string{
+	"Makefile",
+	"Dockerfile",
+	"Gopkg.toml",
+	"Gopkg.lock",
+	"vendor",
+	"cmd",
+	"config",
+	filepath.Join("pkg", "apis"),
+	filepath.Join("pkg", "controller"),
+	filepath.Join("pkg", "webhook"),
+}
+
+// v1API is an API found in a v1 project
+type v1API struct {
+	resource *resource.Resource
+	// dir is the directory of the group-version package in the v1 layout
+	dir string
+	// controller indicates that the project has a controller for the API
+	controller bool
+}
+
+// migrateScaffolder re-scaffolds a v1 project with the v2 layout
+type migrateScaffolder struct {
+	config *config.Config
+	// backupDir is the directory where the replaced v1 files are moved to
+	backupDir string
+}
+
+func NewMigrateScaffolder(config *config.Config, backupDir string) Scaffolder {
+	return &migrateScaffolder{
+		config:    config,
+		backupDir: backupDir,
+	}
+}
+
+func (s *migrateScaffolder) Scaffold() error {
+	fmt.Println("Migrating project to version 2...")
+
+	if !s.config.IsV1() {
+		return fmt.Errorf("only version %s projects can be migrated, this project is version %s",
+			modelconfig.Version1, s.config.Version)
+	}
+
+	apis, err := s.findV1APIs()
+	if err != nil {
+		return fmt.Errorf("error finding the APIs of the project: %v", err)
+	}
+
+	if err := s.backup(); err != nil {
+		return fmt.Errorf("error moving the version %s files to %s: %v", modelconfig.Version1, s.backupDir, err)
+	}
+
+	groups := make(map[string]struct{})
+	for _, api := range apis {
+		groups[api.resource.Group] = struct{}{}
+	}
+	s.config.Version = modelconfig.Version2
+	s.config.MultiGroup = len(groups) > 1
+
+	// The boilerplate of the v1 project is kept, so no license is needed
+	if err := NewInitScaffolder(s.config, "none", "", nil, "").Scaffold(); err != nil {
+		return err
+	}
+
+	// Every v1 API package is imported from its v2 path
+	importPaths := make(map[string]string)
+	for _, api := range apis {
+		if err := NewAPIScaffolder(s.config, api.resource, true, api.controller, false, nil, "", nil, nil).Scaffold(); err != nil {
+			return err
+		}
+		importPaths[path.Join(s.config.Repo, filepath.ToSlash(api.dir))] = path.Join(s.config.Repo,
+			filepath.ToSlash(s.apiDir(api.resource)))
+	}
+
+	moved := make(map[string]bool)
+	for _, api := range apis {
+		if moved[api.dir] {
+			continue
+		}
+		if err := s.moveTypes(api, apis); err != nil {
+			return fmt.Errorf("error moving the types of %s: %v", api.dir, err)
+		}
+		moved[api.dir] = true
+	}
+
+	if err := s.rewriteImports(importPaths); err != nil {
+		return fmt.Errorf("error rewriting import paths: %v", err)
+	}
+
+	for _, api := range apis {
+		if api.controller {
+			fmt.Printf("The reconcile logic of %s needs to be ported from %s to %s\n", api.resource.Kind,
+				filepath.Join(s.backupDir, "pkg", "controller", strings.ToLower(api.resource.Kind)),
+				filepath.Join(s.controllersDir(api.resource),
+					fmt.Sprintf("%s_controller.go", strings.ToLower(api.resource.Kind))))
+		}
+	}
+	if exists, err := afero.Exists(s.config.Fs(), filepath.Join(s.backupDir, "pkg", "webhook")); err != nil {
+		return err
+	} else if exists {
+		fmt.Printf("The webhooks need to be re-created with `kubebuilder create webhook`, "+
+			"the version %s ones can be found at %s\n", modelconfig.Version1, filepath.Join(s.backupDir, "pkg", "webhook"))
+	}
+
+	return nil
+}
+
+// apiDir returns the directory of the group-version package of the resource in the v2 layout
+func (s *migrateScaffolder) apiDir(res *resource.Resource) string {
+	if s.config.MultiGroup {
+		return filepath.Join("apis", res.Group, res.Version)
+	}
+	return filepath.Join("api", res.Version)
+}
+
+// controllersDir returns the directory of the controller package of the resource in the v2 layout
+func (s *migrateScaffolder) controllersDir(res *resource.Resource) string {
+	if s.config.MultiGroup {
+		return filepath.Join("controllers", res.Group)
+	}
+	return "controllers"
+}
+
+// findV1APIs finds the Kinds defined in the pkg/apis/<group>/<version> packages of the project
+func (s *migrateScaffolder) findV1APIs() ([]v1API, error) {
+	fs := s.config.Fs()
+	apisDir := filepath.Join("pkg", "apis")
+
+	groups, err := afero.ReadDir(fs, apisDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	apis := make([]v1API, 0)
+	for _, group := range groups {
+		if !group.IsDir() {
+			continue
+		}
+		versions, err := afero.ReadDir(fs, filepath.Join(apisDir, group.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			if !version.IsDir() {
+				continue
+			}
+			dir := filepath.Join(apisDir, group.Name(), version.Name())
+			kinds, err := findKinds(fs, dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, kind := range kinds {
+				kind.resource.Group = group.Name()
+				kind.resource.Version = version.Name()
+				if err := kind.resource.Validate(); err != nil {
+					return nil, fmt.Errorf("invalid resource in %s: %v", dir, err)
+				}
+				kind.dir = dir
+				kind.controller, err = afero.DirExists(fs,
+					filepath.Join("pkg", "controller", strings.ToLower(kind.resource.Kind)))
+				if err != nil {
+					return nil, err
+				}
+				apis = append(apis, kind)
+			}
+		}
+	}
+
+	return apis, nil
+}
+
+// findKinds parses the Go files of a group-version package and returns the types that have a list
+// counterpart and embed metav1.TypeMeta
+func findKinds(fs afero.Fs, dir string) ([]v1API, error) {
+	files, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	objects := make(map[string]bool)
+	for _, file := range files {
+		if file.IsDir() || !isMigratedTypesFile(file.Name()) {
+			continue
+		}
+		content, err := afero.ReadFile(fs, filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, file.Name(), content, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range f.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || !embedsTypeMeta(structType) {
+					continue
+				}
+				// The scope markers may be in the declaration or in the type comments
+				objects[typeSpec.Name.Name] = !isClusterScoped(genDecl.Doc) && !isClusterScoped(typeSpec.Doc)
+			}
+		}
+	}
+
+	kinds := make([]v1API, 0)
+	for name, namespaced := range objects {
+		if _, hasList := objects[name+"List"]; !hasList {
+			continue
+		}
+		kinds = append(kinds, v1API{resource: &resource.Resource{
+			Kind:                       name,
+			Namespaced:                 namespaced,
+			CreateExampleReconcileBody: true,
+		}})
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].resource.Kind < kinds[j].resource.Kind })
+
+	return kinds, nil
+}
+
+// isMigratedTypesFile returns true for the files of a v1 group-version package that are moved to v2,
+// the rest of files are scaffolded again or generated
+func isMigratedTypesFile(name string) bool {
+	return filepath.Ext(name) == ".go" &&
+		!strings.HasSuffix(name, "_test.go") &&
+		!strings.HasPrefix(name, "zz_generated") &&
+		name != "register.go" &&
+		name != "doc.go"
+}
+
+// embedsTypeMeta returns true if the struct has an embedded TypeMeta field
+func embedsTypeMeta(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		if len(field.Names) != 0 {
+			continue
+		}
+		if selector, ok := field.Type.(*ast.SelectorExpr); ok && selector.Sel.Name == "TypeMeta" {
+			return true
+		}
+	}
+	return false
+}
+
+// isClusterScoped returns true if the comments contain a marker for cluster-scoped resources
+func isClusterScoped(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if text == "+genclient:nonNamespaced" {
+			return true
+		}
+		// The scope may be set along with the rest of arguments of the resource marker
+		if strings.HasPrefix(text, "+kubebuilder:resource:") {
+			for _, arg := range strings.Split(strings.TrimPrefix(text, "+kubebuilder:resource:"), ",") {
+				if arg == "scope=Cluster" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// backup moves the v1 files that are replaced by the v2 layout to the backup directory
+func (s *migrateScaffolder) backup() error {
+	fs := s.config.Fs()
+
+	if err := fs.MkdirAll(s.backupDir, 0755); err != nil {
+		return err
+	}
+
+	// The project file is updated in place, keep a copy of the original one
+	projectFile, err := afero.ReadFile(fs, s.config.Path())
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(fs, filepath.Join(s.backupDir, filepath.Base(s.config.Path())),
+		projectFile, 0644); err != nil {
+		return err
+	}
+
+	for _, p := range v1ReplacedPaths {
+		if exists, err := afero.Exists(fs, p); err != nil {
+			return err
+		} else if !exists {
+			continue
+		}
+		if err := moveAll(fs, p, filepath.Join(s.backupDir, p)); err != nil {
+			return err
+		}
+		fmt.Println(filepath.Join(s.backupDir, p))
+	}
+
+	// Remove the pkg directory if nothing else than APIs and controllers were in it
+	if isEmpty, err := afero.IsEmpty(fs, "pkg"); err == nil && isEmpty {
+		return fs.Remove("pkg")
+	}
+
+	return nil
+}
+
+// moveAll moves the file or the directory at oldPath to newPath one file at a time, as not every
+// afero.Fs moves the contents of the directories it renames
+func moveAll(fs afero.Fs, oldPath, newPath string) error {
+	err := afero.Walk(fs, oldPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(oldPath, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(newPath, rel)
+
+		if err := fs.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return fs.Rename(p, target)
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.RemoveAll(oldPath)
+}
+
+// moveTypes copies the types of a v1 group-version package to the v2 package and removes the
+// scaffolded types files that have no v1 counterpart, as they would re-declare the types
+func (s *migrateScaffolder) moveTypes(api v1API, apis []v1API) error {
+	fs := s.config.Fs()
+	dir := s.apiDir(api.resource)
+
+	files, err := afero.ReadDir(fs, filepath.Join(s.backupDir, api.dir))
+	if err != nil {
+		return err
+	}
+
+	movedFiles := make(map[string]bool)
+	for _, file := range files {
+		if file.IsDir() || !isMigratedTypesFile(file.Name()) {
+			continue
+		}
+		content, err := afero.ReadFile(fs, filepath.Join(s.backupDir, api.dir, file.Name()))
+		if err != nil {
+			return err
+		}
+		if err := afero.WriteFile(fs, filepath.Join(dir, file.Name()), content, 0644); err != nil {
+			return err
+		}
+		fmt.Println(filepath.Join(dir, file.Name()))
+		movedFiles[file.Name()] = true
+	}
+
+	for _, other := range apis {
+		if other.dir != api.dir {
+			continue
+		}
+		typesFile := fmt.Sprintf("%s_types.go", strings.ToLower(other.resource.Kind))
+		if movedFiles[typesFile] {
+			continue
+		}
+		if err := fs.Remove(filepath.Join(dir, typesFile)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewriteImports replaces the import paths of the Go files of the project, skipping the backup
+func (s *migrateScaffolder) rewriteImports(importPaths map[string]string) error {
+	if len(importPaths) == 0 {
+		return nil
+	}
+
+	fs := s.config.Fs()
+	return afero.Walk(fs, ".", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p == s.backupDir || p == "vendor" || (p != "." && strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".go" {
+			return nil
+		}
+
+		content, err := afero.ReadFile(fs, p)
+		if err != nil {
+			return err
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, p, content, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %v", p, err)
+		}
+
+		rewritten := false
+		for oldPath, newPath := range importPaths {
+			if astutil.RewriteImport(fset, f, oldPath, newPath) {
+				rewritten = true
+			}
+		}
+		if !rewritten {
+			return nil
+		}
+
+		out := new(bytes.Buffer)
+		if err := format.Node(out, fset, f); err != nil {
+			return fmt.Errorf("error formatting %s: %v", p, err)
+		}
+		return afero.WriteFile(fs, p, out.Bytes(), info.Mode())
+	})
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+const v1Types = `package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type FrigateSpec struct {
+	Hull string ` + "`json:\"hull,omitempty\"`" + `
+}
+
+type Frigate struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+
+	Spec FrigateSpec ` + "`json:\"spec,omitempty\"`" + `
+}
+
+type FrigateList struct {
+	metav1.TypeMeta ` + "`json:\",inline\"`" + `
+	metav1.ListMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Items           []Frigate ` + "`json:\"items\"`" + `
+}
+
+// +kubebuilder:resource:path=destroyers,scope=Cluster
+type Destroyer struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+}
+
+type DestroyerList struct {
+	metav1.TypeMeta ` + "`json:\",inline\"`" + `
+	metav1.ListMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Items           []Destroyer ` + "`json:\"items\"`" + `
+}
+`
+
+var _ = Describe("MigrateScaffolder", func() {
+	const backupDir = "_v1-backup"
+
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		files := map[string]string{
+			"PROJECT":                                   "version: \"1\"\ndomain: example.com\nrepo: example.com/project\n",
+			"Gopkg.toml":                                "required = []\n",
+			"hack/boilerplate.go.txt":                   "/*\nCopyright 2019 The Fleet Authors.\n*/",
+			"pkg/apis/ship/v1/types.go":                 v1Types,
+			"pkg/apis/ship/v1/register.go":              "package v1\n",
+			"pkg/apis/ship/v1/zz_generated.deepcopy.go": "package v1\n",
+			"pkg/controller/frigate/frigate_controller.go": "package frigate\n\n" +
+				`import shipv1 "example.com/project/pkg/apis/ship/v1"` + "\n\nvar _ shipv1.Frigate\n",
+			"internal/fleet/fleet.go": "package fleet\n\n" +
+				`import shipv1 "example.com/project/pkg/apis/ship/v1"` + "\n\nvar _ shipv1.Destroyer\n",
+		}
+		for path, content := range files {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0600)).To(Succeed())
+		}
+
+		var err error
+		c, err = config.LoadFromFs(fs, config.DefaultPath)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should move the types of the v1 APIs to the v2 layout", func() {
+		Expect(scaffold.NewMigrateScaffolder(c, backupDir).Scaffold()).To(Succeed())
+
+		Expect(c.Version).To(Equal(modelconfig.Version2))
+		Expect(c.MultiGroup).To(BeFalse())
+		namespaced, found := c.KindNamespaced("ship", "Frigate")
+		Expect(found).To(BeTrue())
+		Expect(namespaced).To(BeTrue())
+		// The scope is taken from the resource marker of the v1 type
+		namespaced, found = c.KindNamespaced("ship", "Destroyer")
+		Expect(found).To(BeTrue())
+		Expect(namespaced).To(BeFalse())
+
+		// The v1 types replace the scaffolded ones, which would re-declare them
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("api", "v1", "types.go"))).To(Equal(v1Types))
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "frigate_types.go"))).To(BeFalse())
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "destroyer_types.go"))).To(BeFalse())
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "register.go"))).To(BeFalse())
+		Expect(afero.Exists(fs, filepath.Join("api", "v1", "groupversion_info.go"))).To(BeTrue())
+
+		// Only the APIs with a v1 controller get a v2 one
+		Expect(afero.Exists(fs, filepath.Join("controllers", "frigate_controller.go"))).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join("controllers", "destroyer_controller.go"))).To(BeFalse())
+	})
+
+	It("should rewrite the imports of the moved packages outside of the backup", func() {
+		Expect(scaffold.NewMigrateScaffolder(c, backupDir).Scaffold()).To(Succeed())
+
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("internal", "fleet", "fleet.go"))).To(ContainSubstring(
+			`shipv1 "example.com/project/api/v1"`))
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join(backupDir, "pkg", "controller", "frigate",
+			"frigate_controller.go"))).To(ContainSubstring(`shipv1 "example.com/project/pkg/apis/ship/v1"`))
+	})
+
+	It("should move the replaced v1 files to the backup directory", func() {
+		Expect(scaffold.NewMigrateScaffolder(c, backupDir).Scaffold()).To(Succeed())
+
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join(backupDir, "PROJECT"))).To(HavePrefix("version: \"1\"\n"))
+		Expect(afero.Exists(fs, filepath.Join(backupDir, "Gopkg.toml"))).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join(backupDir, "pkg", "apis", "ship", "v1", "types.go"))).To(BeTrue())
+		Expect(afero.Exists(fs, "Gopkg.toml")).To(BeFalse())
+		Expect(afero.Exists(fs, "pkg")).To(BeFalse())
+		Expect(afero.Exists(fs, "main.go")).To(BeTrue())
+	})
+
+	It("should use the multigroup layout for projects with several groups", func() {
+		Expect(afero.WriteFile(fs, "pkg/apis/crew/v1/types.go", []byte(
+			`package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Captain struct {
+	metav1.TypeMeta `+"`json:\",inline\"`"+`
+}
+
+type CaptainList struct {
+	metav1.TypeMeta `+"`json:\",inline\"`"+`
+}
+`), 0600)).To(Succeed())
+
+		Expect(scaffold.NewMigrateScaffolder(c, backupDir).Scaffold()).To(Succeed())
+
+		Expect(c.MultiGroup).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join("apis", "ship", "v1", "types.go"))).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join("apis", "crew", "v1", "types.go"))).To(BeTrue())
+		Expect(afero.Exists(fs, filepath.Join("controllers", "ship", "frigate_controller.go"))).To(BeTrue())
+		Expect(scaffoldtest.ReadFile(fs, filepath.Join("internal", "fleet", "fleet.go"))).To(ContainSubstring(
+			`shipv1 "example.com/project/apis/ship/v1"`))
+	})
+
+	It("should only migrate version 1 projects", func() {
+		c = scaffoldtest.NewConfig()
+
+		Expect(scaffold.NewMigrateScaffolder(c, backupDir).Scaffold()).NotTo(Succeed())
+		Expect(afero.Exists(c.Fs(), backupDir)).To(BeFalse())
+	})
+})
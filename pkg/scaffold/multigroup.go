@@ -0,0 +1,404 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
+)
+
+// multiGroupMigration moves the API packages of a single-group project to the multigroup layout
+type multiGroupMigration struct {
+	config *config.Config
+	// group is the only group of the resources tracked in the project
+	group string
+	// imports maps the import paths of the API packages to their new ones
+	imports map[string]string
+}
+
+func newMultiGroupMigration(c *config.Config) (*multiGroupMigration, error) {
+	m := &multiGroupMigration{config: c, imports: map[string]string{}}
+
+	groups := c.ResourceGroups()
+	switch len(groups) {
+	case 0:
+	case 1:
+		m.group = groups[0]
+	default:
+		return nil, fmt.Errorf("single-group project has resources in groups %s", strings.Join(groups, ", "))
+	}
+
+	return m, nil
+}
+
+// migrate moves api/<version> to apis/<group>/<version> and the controllers to controllers/<group>, and updates
+// the files that refer to their packages. The files are moved in memory and written at once, so that a failure
+// doesn't leave the project half migrated.
+func (m *multiGroupMigration) migrate() error {
+	base := m.config.Fs()
+	staged := NewDryRunFs(base)
+	m.config.SetFs(staged)
+	defer m.config.SetFs(base)
+
+	if err := m.move(); err != nil {
+		return err
+	}
+	return staged.Commit()
+}
+
+// move moves the packages of the project to the multigroup layout
+func (m *multiGroupMigration) move() error {
+	fs := m.config.Fs()
+
+	versions, err := m.apiVersions()
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		oldDir := filepath.Join("api", version)
+		newDir := filepath.Join("apis", m.group, version)
+		exists, err := afero.Exists(fs, newDir)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("unable to move %s, %s already exists", oldDir, newDir)
+		}
+		m.imports[m.config.Repo+"/"+filepath.ToSlash(oldDir)] = m.config.Repo + "/" + filepath.ToSlash(newDir)
+	}
+	controllers, err := m.controllerFiles()
+	if err != nil {
+		return err
+	}
+
+	scaffolded, err := readManifest(fs)
+	if err != nil {
+		return err
+	}
+	moved := false
+	for _, version := range versions {
+		oldDir := filepath.Join("api", version)
+		newDir := filepath.Join("apis", m.group, version)
+		if err := m.moveDir(oldDir, newDir); err != nil {
+			return err
+		}
+		// The merge bases and manifest entries follow the files they were recorded for
+		if err := m.moveDir(mergeBasePath(oldDir), mergeBasePath(newDir)); err != nil {
+			return err
+		}
+		if scaffolded.move(oldDir, newDir) {
+			moved = true
+		}
+	}
+	for _, path := range controllers {
+		newPath := filepath.Join("controllers", m.group, filepath.Base(path))
+		if err := m.moveFile(path, newPath, `filepath.Join("..", `, `filepath.Join("..", "..", `); err != nil {
+			return err
+		}
+		if err := m.moveFile(mergeBasePath(path), mergeBasePath(newPath), "", ""); err != nil &&
+			!os.IsNotExist(err) {
+			return err
+		}
+		if f, found := scaffolded.get(path); found {
+			scaffolded.remove(path)
+			scaffolded.Files[filepath.ToSlash(newPath)] = f
+			moved = true
+		}
+	}
+	if moved {
+		if err := scaffolded.write(fs); err != nil {
+			return err
+		}
+	}
+	if len(versions) != 0 {
+		if err := removeIfEmpty(fs, "api"); err != nil {
+			return err
+		}
+		if err := removeIfEmpty(fs, mergeBasePath("api")); err != nil {
+			return err
+		}
+	}
+
+	if err := m.rewriteImports(); err != nil {
+		return err
+	}
+	if len(controllers) != 0 {
+		if err := m.rewireControllers(); err != nil {
+			return err
+		}
+	}
+
+	return m.updateDockerfile()
+}
+
+// controllerFiles returns the files of the controllers package, which is moved to the directory of the group
+func (m *multiGroupMigration) controllerFiles() ([]string, error) {
+	fs := m.config.Fs()
+
+	infos, err := afero.ReadDir(fs, "controllers")
+	if os.IsNotExist(err) || m.group == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, info := range infos {
+		if !info.IsDir() {
+			paths = append(paths, filepath.Join("controllers", info.Name()))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	newDir := filepath.Join("controllers", m.group)
+	exists, err := afero.Exists(fs, newDir)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("unable to move the controllers, %s already exists", newDir)
+	}
+	return paths, nil
+}
+
+// moveFile moves the file at path to newPath, replacing old by new in its content if it is a go file
+func (m *multiGroupMigration) moveFile(path, newPath, old, new string) error {
+	fs := m.config.Fs()
+
+	info, err := fs.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return err
+	}
+	if old != "" && filepath.Ext(path) == ".go" {
+		content = []byte(strings.Replace(string(content), old, new, -1))
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(fs, newPath, content, info.Mode()); err != nil {
+		return err
+	}
+	if err := fs.Remove(path); err != nil {
+		return fmt.Errorf("error removing %s: %v", path, err)
+	}
+	fmt.Printf("%s -> %s\n", path, newPath)
+	return nil
+}
+
+// controllersSelector matches the references to the controllers package, but not the strings that contain it
+var controllersSelector = regexp.MustCompile(`(^|[^\w"./])controllers\.`)
+
+// rewireControllers imports the controllers of main.go from the package of the group, as the name the multigroup
+// projects import them as
+func (m *multiGroupMigration) rewireControllers() error {
+	fs := m.config.Fs()
+
+	content, err := afero.ReadFile(fs, "main.go")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pkg := m.config.GroupPackage(m.group)
+	if pkg == "" {
+		pkg = templatefuncs.GroupPackageName(m.group)
+	}
+	alias := "controller" + pkg
+	updated := strings.Replace(string(content), fmt.Sprintf(`"%s/controllers"`, m.config.Repo),
+		fmt.Sprintf(`%s "%s/controllers/%s"`, alias, m.config.Repo, m.group), 1)
+	updated = controllersSelector.ReplaceAllString(updated, "${1}"+alias+".")
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := afero.WriteFile(fs, "main.go", []byte(updated), 0644); err != nil {
+		return err
+	}
+	fmt.Println("main.go")
+	return nil
+}
+
+// apiVersions returns the versions that have a package under the api directory
+func (m *multiGroupMigration) apiVersions() ([]string, error) {
+	infos, err := afero.ReadDir(m.config.Fs(), "api")
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, info := range infos {
+		if info.IsDir() {
+			versions = append(versions, info.Name())
+		}
+	}
+	if len(versions) != 0 && m.group == "" {
+		return nil, fmt.Errorf("unable to find the group of the API packages under api, no resources are tracked")
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// moveDir moves the files under oldDir to newDir, the packages are one level deeper so the
+// relative paths to the project root in their files get another parent directory
+func (m *multiGroupMigration) moveDir(oldDir, newDir string) error {
+	fs := m.config.Fs()
+
+	if exists, err := afero.DirExists(fs, oldDir); err != nil || !exists {
+		return err
+	}
+
+	var moved []string
+	err := afero.Walk(fs, oldDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(oldDir, path)
+		if err != nil {
+			return err
+		}
+		newPath := filepath.Join(newDir, rel)
+
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".go" {
+			content = []byte(strings.Replace(string(content),
+				`filepath.Join("..", "..", `, `filepath.Join("..", "..", "..", `, -1))
+		}
+
+		if err := fs.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return err
+		}
+		if err := afero.WriteFile(fs, newPath, content, info.Mode()); err != nil {
+			return err
+		}
+		moved = append(moved, path)
+		fmt.Printf("%s -> %s\n", path, newPath)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error moving %s to %s: %v", oldDir, newDir, err)
+	}
+
+	for _, path := range moved {
+		if err := fs.Remove(path); err != nil {
+			return fmt.Errorf("error removing %s: %v", path, err)
+		}
+	}
+	return fs.RemoveAll(oldDir)
+}
+
+// rewriteImports replaces the import paths of the moved API packages in the go files of the project
+func (m *multiGroupMigration) rewriteImports() error {
+	if len(m.imports) == 0 {
+		return nil
+	}
+
+	fs := m.config.Fs()
+	return afero.Walk(fs, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == "vendor" || path == ".git" || path == "bin" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		updated := string(content)
+		for oldImport, newImport := range m.imports {
+			updated = strings.Replace(updated, `"`+oldImport+`"`, `"`+newImport+`"`, -1)
+		}
+		if updated == string(content) {
+			return nil
+		}
+
+		if err := afero.WriteFile(fs, path, []byte(updated), info.Mode()); err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	})
+}
+
+// updateDockerfile copies the apis directory into the builder image instead of the api one
+func (m *multiGroupMigration) updateDockerfile() error {
+	fs := m.config.Fs()
+
+	content, err := afero.ReadFile(fs, "Dockerfile")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := strings.Replace(string(content), "COPY api/ api/\n", "COPY apis/ apis/\n", 1)
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := afero.WriteFile(fs, "Dockerfile", []byte(updated), 0644); err != nil {
+		return err
+	}
+	fmt.Println("Dockerfile")
+	return nil
+}
+
+// removeIfEmpty removes the directory if it exists and doesn't have any file left
+func removeIfEmpty(fs afero.Fs, dir string) error {
+	if exists, err := afero.DirExists(fs, dir); err != nil || !exists {
+		return err
+	}
+	empty, err := afero.IsEmpty(fs, dir)
+	if err != nil || !empty {
+		return err
+	}
+	return fs.Remove(dir)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	observabilityv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/observability"
+)
+
+var _ ProjectPlugin = ObservabilityPlugin{}
+
+// ObservabilityPlugin is the project plugin provisioning Grafana dashboards and Prometheus alerts for the metrics
+// of the manager, selected with --plugins=go/v2,observability/v1 when initializing the project. They are scaffolded
+// as the observability component of the default overlay, which it enables along with the prometheus one.
+type ObservabilityPlugin struct{}
+
+// Name implements ProjectPlugin
+func (ObservabilityPlugin) Name() string {
+	return "observability.kubebuilder.io"
+}
+
+// Version implements ProjectPlugin
+func (ObservabilityPlugin) Version() string {
+	return "v1"
+}
+
+// Description implements ProjectPlugin
+func (ObservabilityPlugin) Description() string {
+	return "Grafana dashboards and Prometheus alerts for the reconcile latency, errors and workqueue depth"
+}
+
+// InitPlugins implements ProjectPlugin
+func (ObservabilityPlugin) InitPlugins() []Plugin {
+	return []Plugin{PluginFunc(enableObservability)}
+}
+
+// APIPlugins implements ProjectPlugin
+func (ObservabilityPlugin) APIPlugins() []Plugin {
+	return nil
+}
+
+// WebhookPlugins implements ProjectPlugin
+func (ObservabilityPlugin) WebhookPlugins() []Plugin {
+	return nil
+}
+
+// observabilityFiles returns the files of the observability component, besides its kustomization
+func observabilityFiles() []input.File {
+	return []input.File{
+		&observabilityv2.Alerts{},
+		&observabilityv2.GrafanaKustomization{},
+		&observabilityv2.Dashboard{},
+	}
+}
+
+// enableObservability adds the observability component to the files of the project being initialized, and enables
+// it in the default overlay along with the prometheus component scraping the metrics
+func enableObservability(u *model.Universe) error {
+	var kustomization *model.File
+	for _, f := range u.Files {
+		if f.Path == filepath.Join("config", "default", "kustomization.yaml") {
+			kustomization = f
+		}
+	}
+	// The project is initialized in several steps, the component is scaffolded with the default overlay
+	if kustomization == nil {
+		return nil
+	}
+	if !strings.Contains(kustomization.Contents, scaffoldv2.ComponentsScaffoldMarker) {
+		return fmt.Errorf("%s has no components section to enable %s in",
+			kustomization.Path, scaffoldv2.ComponentObservability)
+	}
+
+	files := append([]input.File{&scaffoldv2.Component{Name: scaffoldv2.ComponentObservability}},
+		observabilityFiles()...)
+	inputs := make([]input.Input, len(files))
+	for i, f := range files {
+		var err error
+		if inputs[i], err = f.GetInput(); err != nil {
+			return err
+		}
+	}
+	models, err := renderFiles(files, inputs, os.Stdout)
+	if err != nil {
+		return err
+	}
+	for _, m := range models {
+		m.Plugin = PluginKey(ObservabilityPlugin{})
+		stampProvenance(m)
+	}
+	u.Files = append(u.Files, models...)
+
+	var entries string
+	for _, component := range []string{scaffoldv2.ComponentPrometheus, scaffoldv2.ComponentObservability} {
+		entry := fmt.Sprintf("- ../components/%s\n", component)
+		if !strings.Contains(kustomization.Contents, entry) {
+			entries += entry
+		}
+	}
+	kustomization.Contents = strings.Replace(kustomization.Contents, scaffoldv2.ComponentsScaffoldMarker,
+		entries+scaffoldv2.ComponentsScaffoldMarker, 1)
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+// Pattern is a set of plugins that scaffolds an API following an alternative reconciler pattern, e.g. by
+// replacing the controller template while the types and kustomize scaffolds of the default pattern are reused
+type Pattern interface {
+	// Name is the value of the --pattern flag that selects the pattern
+	Name() string
+	// Description is a short summary of the pattern shown in the help of the --pattern flag
+	Description() string
+	// Plugins returns the plugins that transform the default scaffold, in the order they are run
+	Plugins() []Plugin
+}
+
+// Patterns is a list of patterns that can be selected by name
+type Patterns []Pattern
+
+// Find returns the pattern with the provided name, which is matched case-insensitively
+func (p Patterns) Find(name string) (Pattern, error) {
+	for _, pattern := range p {
+		if strings.EqualFold(pattern.Name(), name) {
+			return pattern, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown pattern %q, must be one of %s", name, strings.Join(p.Names(), ", "))
+}
+
+// Names returns the names of the patterns
+func (p Patterns) Names() []string {
+	names := make([]string, 0, len(p))
+	for _, pattern := range p {
+		names = append(names, pattern.Name())
+	}
+	return names
+}
+
+// PluginFunc is an adapter to use a function as a Plugin
+type PluginFunc func(universe *model.Universe) error
+
+// Pipe implements Plugin
+func (f PluginFunc) Pipe(universe *model.Universe) error {
+	return f(universe)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+type testPattern struct {
+	name    string
+	plugins []scaffold.Plugin
+}
+
+func (p testPattern) Name() string               { return p.name }
+func (p testPattern) Description() string        { return "test pattern" }
+func (p testPattern) Plugins() []scaffold.Plugin { return p.plugins }
+
+var _ = Describe("Patterns", func() {
+	patterns := scaffold.Patterns{testPattern{name: "addon"}, testPattern{name: "job"}}
+
+	It("should find the patterns by name regardless of the case", func() {
+		pattern, err := patterns.Find("Job")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pattern.Name()).To(Equal("job"))
+	})
+
+	It("should fail to find an unknown pattern", func() {
+		_, err := patterns.Find("claim")
+		Expect(err).To(MatchError(`unknown pattern "claim", must be one of addon, job`))
+	})
+
+	It("should run the plugins of the pattern with the scaffold", func() {
+		fs := afero.NewMemMapFs()
+		pattern := testPattern{name: "replace", plugins: []scaffold.Plugin{
+			scaffold.PluginFunc(func(u *model.Universe) error {
+				for _, f := range u.Files {
+					f.Contents = "replaced\n"
+				}
+				return nil
+			}),
+		}}
+
+		s := &scaffold.Scaffold{
+			Fs:                  fs,
+			Plugins:             pattern.Plugins(),
+			BoilerplateOptional: true,
+			ConfigOptional:      true,
+		}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &project.GitIgnore{})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, ".gitignore")
+		Expect(content).To(Equal("replaced\n"))
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/policy"
+)
+
+// policyScaffolder scaffolds the ValidatingAdmissionPolicy of a resource, an alternative to the validating
+// webhooks that doesn't need the webhook server
+type policyScaffolder struct {
+	config   *config.Config
+	resource *resource.Resource
+}
+
+func NewPolicyScaffolder(config *config.Config, resource *resource.Resource) Scaffolder {
+	return &policyScaffolder{
+		config:   config,
+		resource: resource,
+	}
+}
+
+func (s *policyScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("admission policies are not supported for project version %v", s.config.Version)
+	}
+
+	// The rules are derived from the validation markers of the Go types of the Kind
+	_, groupDomain := util.GetResourceInfo(s.resource, s.config.Repo, s.config.Domain, s.config.MultiGroup)
+	apiDir := filepath.Join("api", s.resource.Version)
+	if s.config.MultiGroup {
+		apiDir = filepath.Join("apis", s.resource.Group, s.resource.Version)
+	}
+	kindDoc, err := docs.LoadKind(s.config.Fs(), apiDir, s.resource.Group, s.resource.Version, s.resource.Kind,
+		fmt.Sprintf("%s/%s", groupDomain, s.resource.Version))
+	if err != nil {
+		return fmt.Errorf("error reading the types of %s: %v", s.resource.Kind, err)
+	}
+	rules := policy.NewRules(kindDoc)
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithResource(s.resource, &s.config.Config),
+	)
+	if err != nil {
+		return err
+	}
+
+	kustomizationFile := &policy.Kustomization{Resource: s.resource}
+	if err := (&Scaffold{Fs: s.config.Fs(), BoilerplateOptional: true}).Execute(
+		universe,
+		input.Options{},
+		&policy.Policy{Resource: s.resource, Rules: rules},
+		kustomizationFile,
+		&policy.KustomizeConfig{},
+	); err != nil {
+		return err
+	}
+
+	if err := kustomizationFile.Update(s.config.Fs()); err != nil {
+		return fmt.Errorf("error updating %s: %v", kustomizationFile.Path, err)
+	}
+
+	// The policies are deployed by the default overlay through their own component
+	if err := scaffoldComponent(s.config.Fs(), &s.config.Config, scaffoldv2.ComponentPolicy); err != nil {
+		return err
+	}
+	kustomizeFile := &scaffoldv2.Kustomize{}
+	if err := kustomizeFile.EnableComponents(s.config.Fs(), scaffoldv2.ComponentPolicy); err != nil {
+		fmt.Printf("Warning: %v\nAdd %s to the resources of %s to deploy the policies.\n",
+			err, filepath.Join("..", "policy"), filepath.Join("config", "default", "kustomization.yaml"))
+	}
+
+	fmt.Printf("ValidatingAdmissionPolicy of %s scaffolded with %d rules derived from its validation markers.\n"+
+		"The policies require Kubernetes 1.30 or newer, keep them in sync with the markers when changing them.\n",
+		s.resource.Kind, len(rules))
+
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("PolicyScaffolder", func() {
+	It("should scaffold the ValidatingAdmissionPolicy of a resource from its validation markers", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			ExampleFields: resource.ExampleFieldsRich}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		Expect(scaffold.NewPolicyScaffolder(c, frigate).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("config", "policy", "ship_v1_frigate.yaml"))
+		Expect(content).To(ContainSubstring("kind: ValidatingAdmissionPolicy\n"))
+		Expect(content).To(ContainSubstring(`    - apiGroups: ["ship.example.com"]`))
+		Expect(content).To(ContainSubstring(
+			`!has(object.spec.size) || (object.spec.size in [\"Small\", \"Medium\", \"Large\"])`))
+		Expect(content).To(ContainSubstring(
+			`!has(object.spec.scaling) || (object.spec.scaling.minReplicas <= object.spec.scaling.maxReplicas)`))
+		Expect(content).To(ContainSubstring("  policyName: frigates-v1\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "policy", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("- ship_v1_frigate.yaml\n"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(content).To(ContainSubstring("- ../components/policy\n"))
+		Expect(afero.Exists(fs, filepath.Join("config", "components", "policy", "kustomization.yaml"))).To(BeTrue())
+	})
+})
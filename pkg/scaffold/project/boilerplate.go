@@ -19,6 +19,8 @@ package project
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -30,7 +32,9 @@ var _ input.File = &Boilerplate{}
 type Boilerplate struct {
 	input.Input
 
-	// License is the License type to write
+	// License is the License type to write, one of LicenseApache2, LicenseNone or LicenseSPDXPrefix followed by the
+	// SPDX identifier of the license. The header of custom licenses is set as the Boilerplate, in which the Owner
+	// and Year can be referenced.
 	License string
 
 	// Owner is the copyright owner - e.g. "The Kubernetes Authors"
@@ -46,6 +50,10 @@ func (f *Boilerplate) GetInput() (input.Input, error) {
 		f.Path = filepath.Join("hack", "boilerplate.go.txt")
 	}
 
+	if f.Year == "" {
+		f.Year = fmt.Sprintf("%v", time.Now().Year())
+	}
+
 	// Boilerplate given
 	if len(f.Boilerplate) > 0 {
 		f.TemplateBody = f.Boilerplate
@@ -53,18 +61,57 @@ func (f *Boilerplate) GetInput() (input.Input, error) {
 	}
 
 	// Pick a template boilerplate option
-	if f.Year == "" {
-		f.Year = fmt.Sprintf("%v", time.Now().Year())
-	}
-	switch f.License {
-	case "", "apache2":
+	switch {
+	case f.License == "", f.License == LicenseApache2:
 		f.TemplateBody = apache
-	case "none":
+	case f.License == LicenseNone:
 		f.TemplateBody = none
+	case strings.HasPrefix(f.License, LicenseSPDXPrefix):
+		f.TemplateBody = spdx
 	}
 	return f.Input, nil
 }
 
+// SPDXIdentifier returns the SPDX identifier of SPDX-only licenses
+func (f *Boilerplate) SPDXIdentifier() string {
+	return strings.TrimPrefix(f.License, LicenseSPDXPrefix)
+}
+
+const (
+	// LicenseApache2 is the Apache License 2.0
+	LicenseApache2 = "apache2"
+	// LicenseNone is a header with the copyright only
+	LicenseNone = "none"
+	// LicenseSPDXPrefix prefixes the SPDX identifier of a license whose header is the copyright and the
+	// SPDX-License-Identifier line only, e.g. spdx:MIT
+	LicenseSPDXPrefix = "spdx:"
+)
+
+// spdxIdentifierRegex matches SPDX license expressions, e.g. MIT or Apache-2.0 OR GPL-2.0-or-later
+var spdxIdentifierRegex = regexp.MustCompile(`^[A-Za-z0-9.+-]+( (AND|OR|WITH) [A-Za-z0-9.+-]+)*$`)
+
+// IsCustomLicense returns whether the license is not one of the built-in ones, but the path of a file with the
+// header of the license
+func IsCustomLicense(license string) bool {
+	return license != "" && license != LicenseApache2 && license != LicenseNone &&
+		!strings.HasPrefix(license, LicenseSPDXPrefix)
+}
+
+// ValidateLicense validates that the license is built-in, an SPDX license expression, or a custom license
+func ValidateLicense(license string) error {
+	if license == "" {
+		return fmt.Errorf("license cannot be empty, must be one of '%s', '%s', '%s<identifier>' or the path of a "+
+			"file with the header", LicenseApache2, LicenseNone, LicenseSPDXPrefix)
+	}
+	if strings.HasPrefix(license, LicenseSPDXPrefix) {
+		identifier := strings.TrimPrefix(license, LicenseSPDXPrefix)
+		if !spdxIdentifierRegex.MatchString(identifier) {
+			return fmt.Errorf("invalid SPDX license expression %q, e.g. %sApache-2.0", identifier, LicenseSPDXPrefix)
+		}
+	}
+	return nil
+}
+
 const apache = `/*
 {{ if .Owner }}Copyright {{ .Year }} {{ .Owner }}.
 {{ end }}
@@ -84,3 +131,6 @@ limitations under the License.
 const none = `/*
 {{ if .Owner }}Copyright {{ .Year }} {{ .Owner }}{{ end }}.
 */`
+
+const spdx = `{{ if .Owner }}// Copyright {{ .Year }} {{ .Owner }}.
+{{ end }}// SPDX-License-Identifier: {{ .SPDXIdentifier }}`
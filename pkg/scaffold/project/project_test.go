@@ -61,7 +61,7 @@ var _ = Describe("Project", func() {
 		It("should match the golden file", func() {
 			instance := &project.Boilerplate{Year: year, License: "apache2", Owner: "The Kubernetes authors"}
 			Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
-			Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+			Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 		})
 
 		It("should skip writing boilerplate if the file exists", func() {
@@ -76,13 +76,13 @@ var _ = Describe("Project", func() {
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 				e := strings.Replace(
 					result.Golden, "The Kubernetes authors", "Example Owners", -1)
-				Expect(result.Actual.String()).To(BeEquivalentTo(e))
+				Expect(result.Actual()).To(BeEquivalentTo(e))
 			})
 
 			It("should use apache2 as the default", func() {
 				instance := &project.Boilerplate{Year: year, Owner: "The Kubernetes authors"}
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 
@@ -91,19 +91,45 @@ var _ = Describe("Project", func() {
 				// Scaffold a boilerplate file
 				instance := &project.Boilerplate{Year: year, License: "none", Owner: "Example Owners"}
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
-				Expect(result.Actual.String()).To(BeEquivalentTo(fmt.Sprintf(`/*
+				Expect(result.Actual()).To(BeEquivalentTo(fmt.Sprintf(`/*
 Copyright %s Example Owners.
 */`, year)))
 			})
 		})
 
+		Context("for spdx", func() {
+			It("should write the SPDX license identifier only", func() {
+				instance := &project.Boilerplate{Year: year, License: "spdx:Apache-2.0 OR MIT", Owner: "Example Owners"}
+				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
+				Expect(result.Actual()).To(BeEquivalentTo(fmt.Sprintf(`// Copyright %s Example Owners.
+// SPDX-License-Identifier: Apache-2.0 OR MIT`, year)))
+			})
+
+			It("should reject invalid license expressions", func() {
+				Expect(project.ValidateLicense("spdx:")).NotTo(Succeed())
+				Expect(project.ValidateLicense("spdx:MIT\n*/")).NotTo(Succeed())
+				Expect(project.ValidateLicense("spdx:GPL-2.0-or-later WITH Classpath-exception-2.0")).To(Succeed())
+			})
+		})
+
+		Context("for a custom license", func() {
+			It("should reference the owner and the year from the given header", func() {
+				instance := &project.Boilerplate{Year: year, License: "hack/header.txt", Owner: "Example Owners"}
+				instance.Boilerplate = `/* (c) {{ .Year }} {{ .Owner }} */`
+
+				Expect(project.IsCustomLicense(instance.License)).To(BeTrue())
+				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
+				Expect(result.Actual()).To(BeEquivalentTo(fmt.Sprintf(`/* (c) %s Example Owners */`, year)))
+			})
+		})
+
 		Context("if the boilerplate is given", func() {
 			It("should skip writing Gopkg.toml", func() {
 				instance := &project.Boilerplate{}
 				instance.Boilerplate = `/* Hello World */`
 
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
-				Expect(result.Actual.String()).To(BeEquivalentTo(`/* Hello World */`))
+				Expect(result.Actual()).To(BeEquivalentTo(`/* Hello World */`))
 			})
 		})
 	})
@@ -119,7 +145,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 
@@ -169,7 +195,7 @@ Copyright %s Example Owners.
 				instance.Input.Path = f.Name()
 
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
-				Expect(result.Actual.String()).To(BeEquivalentTo(e))
+				Expect(result.Actual()).To(BeEquivalentTo(e))
 			})
 		})
 
@@ -189,7 +215,7 @@ Copyright %s Example Owners.
 
 				err = s.Execute(&model.Universe{}, input.Options{}, instance)
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -206,7 +232,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -223,7 +249,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -240,7 +266,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -257,7 +283,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -274,7 +300,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -291,7 +317,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -307,7 +333,7 @@ Copyright %s Example Owners.
 				Expect(s.Execute(&model.Universe{}, input.Options{}, instance)).NotTo(HaveOccurred())
 
 				// Verify the contents matches the golden file.
-				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
+				Expect(result.Actual()).To(BeEquivalentTo(result.Golden))
 			})
 		})
 	})
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+// ProjectPlugin is a versioned plugin recorded in the PROJECT file, through which the init, create api and create
+// webhook commands of the project are routed. Its version is bumped whenever the files it scaffolds change in a
+// way that is not compatible with the projects scaffolded by its previous version.
+type ProjectPlugin interface {
+	// Name is the fully qualified name of the plugin, e.g. go.kubebuilder.io
+	Name() string
+	// Version is the version of the plugin, e.g. v2
+	Version() string
+	// Description is a short summary of the plugin shown in the help of the --plugins flag
+	Description() string
+	// InitPlugins, APIPlugins and WebhookPlugins return the plugins that transform the scaffold of the project,
+	// of an API and of a webhook, in the order they are run
+	InitPlugins() []Plugin
+	APIPlugins() []Plugin
+	WebhookPlugins() []Plugin
+}
+
+// PluginKey returns the key of a plugin recorded in the PROJECT file, its name and version, e.g. go.kubebuilder.io/v2
+func PluginKey(p ProjectPlugin) string {
+	return p.Name() + "/" + p.Version()
+}
+
+// shortName returns the first label of the name of a plugin, e.g. go for go.kubebuilder.io
+func shortName(p ProjectPlugin) string {
+	return strings.SplitN(p.Name(), ".", 2)[0]
+}
+
+// ProjectPlugins is a list of project plugins that can be selected by key
+type ProjectPlugins []ProjectPlugin
+
+// Find returns the plugin with the provided key, name/version. The name can be shortened to its first label and,
+// without version, the last version registered is returned, e.g. go/v2 and go select go.kubebuilder.io/v2.
+func (p ProjectPlugins) Find(key string) (ProjectPlugin, error) {
+	name, version := key, ""
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		name, version = key[:i], key[i+1:]
+	}
+
+	var found ProjectPlugin
+	for _, plugin := range p {
+		if plugin.Name() != name && shortName(plugin) != name {
+			continue
+		}
+		if version == "" || plugin.Version() == version {
+			found = plugin
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown plugin %q, must be one of %s", key, strings.Join(p.Keys(), ", "))
+	}
+	return found, nil
+}
+
+// Resolve returns the chain of the plugins with the provided keys, each plugin can only be selected once
+func (p ProjectPlugins) Resolve(keys []string) (PluginChain, error) {
+	chain := make(PluginChain, 0, len(keys))
+	names := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		plugin, err := p.Find(key)
+		if err != nil {
+			return nil, err
+		}
+		if names[plugin.Name()] {
+			return nil, fmt.Errorf("plugin %s is selected more than once", plugin.Name())
+		}
+		names[plugin.Name()] = true
+		chain = append(chain, plugin)
+	}
+	return chain, nil
+}
+
+// Keys returns the keys of the plugins
+func (p ProjectPlugins) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for _, plugin := range p {
+		keys = append(keys, PluginKey(plugin))
+	}
+	return keys
+}
+
+// PluginChain is the ordered list of the project plugins a project is scaffolded with
+type PluginChain []ProjectPlugin
+
+// Keys returns the keys of the plugins of the chain, as recorded in the PROJECT file
+func (c PluginChain) Keys() []string {
+	return ProjectPlugins(c).Keys()
+}
+
+// InitPlugins returns the plugins of the chain that transform the scaffold of the project
+func (c PluginChain) InitPlugins() []Plugin {
+	plugins := make([]Plugin, 0)
+	for _, plugin := range c {
+		plugins = append(plugins, plugin.InitPlugins()...)
+	}
+	return plugins
+}
+
+// APIPlugins returns the plugins of the chain that transform the scaffold of an API
+func (c PluginChain) APIPlugins() []Plugin {
+	plugins := make([]Plugin, 0)
+	for _, plugin := range c {
+		plugins = append(plugins, plugin.APIPlugins()...)
+	}
+	return plugins
+}
+
+// WebhookPlugins returns the plugins of the chain that transform the scaffold of a webhook
+func (c PluginChain) WebhookPlugins() []Plugin {
+	plugins := make([]Plugin, 0)
+	for _, plugin := range c {
+		plugins = append(plugins, plugin.WebhookPlugins()...)
+	}
+	return plugins
+}
+
+var _ ProjectPlugin = GoPlugin{}
+
+// GoPlugin is the plugin scaffolding the Go operators of v2 projects with controller-runtime, the base of their
+// plugin chain. Its files are the default scaffold, which it doesn't transform.
+type GoPlugin struct{}
+
+// Name implements ProjectPlugin
+func (GoPlugin) Name() string {
+	return strings.SplitN(config.PluginGoV2, "/", 2)[0]
+}
+
+// Version implements ProjectPlugin
+func (GoPlugin) Version() string {
+	return strings.SplitN(config.PluginGoV2, "/", 2)[1]
+}
+
+// Description implements ProjectPlugin
+func (GoPlugin) Description() string {
+	return "Go operator built with controller-runtime, the base of the plugin chain"
+}
+
+// InitPlugins implements ProjectPlugin
+func (GoPlugin) InitPlugins() []Plugin {
+	return nil
+}
+
+// APIPlugins implements ProjectPlugin
+func (GoPlugin) APIPlugins() []Plugin {
+	return nil
+}
+
+// WebhookPlugins implements ProjectPlugin
+func (GoPlugin) WebhookPlugins() []Plugin {
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+type testProjectPlugin struct {
+	name    string
+	version string
+	plugins []scaffold.Plugin
+}
+
+func (p testProjectPlugin) Name() string                      { return p.name }
+func (p testProjectPlugin) Version() string                   { return p.version }
+func (p testProjectPlugin) Description() string               { return "test plugin" }
+func (p testProjectPlugin) InitPlugins() []scaffold.Plugin    { return nil }
+func (p testProjectPlugin) APIPlugins() []scaffold.Plugin     { return p.plugins }
+func (p testProjectPlugin) WebhookPlugins() []scaffold.Plugin { return nil }
+
+var _ = Describe("ProjectPlugins", func() {
+	noop := scaffold.PluginFunc(func(*model.Universe) error { return nil })
+	plugins := scaffold.ProjectPlugins{
+		scaffold.GoPlugin{},
+		testProjectPlugin{name: "declarative.kubebuilder.io", version: "v1"},
+		testProjectPlugin{name: "declarative.kubebuilder.io", version: "v2", plugins: []scaffold.Plugin{noop}},
+	}
+
+	It("should find the plugins by key, short name and latest version", func() {
+		for key, expected := range map[string]string{
+			"go.kubebuilder.io/v2":          "go.kubebuilder.io/v2",
+			"go/v2":                         "go.kubebuilder.io/v2",
+			"declarative/v1":                "declarative.kubebuilder.io/v1",
+			"declarative":                   "declarative.kubebuilder.io/v2",
+			"declarative.kubebuilder.io/v2": "declarative.kubebuilder.io/v2",
+		} {
+			plugin, err := plugins.Find(key)
+			Expect(err).NotTo(HaveOccurred(), key)
+			Expect(scaffold.PluginKey(plugin)).To(Equal(expected), key)
+		}
+	})
+
+	It("should fail to find an unknown plugin or version", func() {
+		_, err := plugins.Find("go/v3")
+		Expect(err).To(MatchError(`unknown plugin "go/v3", must be one of go.kubebuilder.io/v2, ` +
+			`declarative.kubebuilder.io/v1, declarative.kubebuilder.io/v2`))
+	})
+
+	It("should resolve a chain with the plugins of each command", func() {
+		chain, err := plugins.Resolve([]string{"go/v2", "declarative/v2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chain.Keys()).To(Equal([]string{"go.kubebuilder.io/v2", "declarative.kubebuilder.io/v2"}))
+		Expect(chain.APIPlugins()).To(HaveLen(1))
+		Expect(chain.WebhookPlugins()).To(BeEmpty())
+	})
+
+	It("should fail to resolve a chain selecting a plugin twice", func() {
+		_, err := plugins.Resolve([]string{"go/v2", "declarative/v1", "declarative/v2"})
+		Expect(err).To(MatchError("plugin declarative.kubebuilder.io is selected more than once"))
+	})
+})
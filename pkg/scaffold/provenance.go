@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// KubebuilderVersion is the version of kubebuilder stamped in the scaffolded files, set by the CLI
+var KubebuilderVersion = "unknown"
+
+// provenanceMarker starts the comment stamped at the end of the scaffolded files, followed by their Provenance
+const provenanceMarker = "kubebuilder:provenance"
+
+// templatePkgPrefix is trimmed from the package of the templates to identify them, e.g. v2/manager.Config
+const templatePkgPrefix = "sigs.k8s.io/kubebuilder/pkg/scaffold/"
+
+// Provenance is how a file of the project was scaffolded, as stamped in the comment at its end
+type Provenance struct {
+	// Path is the slash-separated path of the file in the project
+	Path string
+	// Version is the version of kubebuilder that scaffolded the file
+	Version string
+	// Plugin is the key of the project plugin that scaffolded the file, empty for the projects without plugins
+	Plugin string
+	// Template identifies the template of the file, e.g. v2.Main
+	Template string
+}
+
+// templateName returns the identifier of the template of file, its type qualified by its package
+func templateName(file input.File) string {
+	t := reflect.TypeOf(file)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimPrefix(t.PkgPath(), templatePkgPrefix) + "." + t.Name()
+}
+
+// stampProvenance appends the provenance comment of the file, scaffolded from its template by this version of
+// kubebuilder, to its contents
+func stampProvenance(file *model.File) {
+	p := Provenance{
+		Path:     filepath.ToSlash(file.Path),
+		Version:  KubebuilderVersion,
+		Plugin:   file.Plugin,
+		Template: file.Template,
+	}
+	file.Contents = p.stamp(file.Contents)
+}
+
+// String returns the structured provenance comment, without the comment delimiter
+func (p Provenance) String() string {
+	fields := []string{provenanceMarker, "version=" + p.Version}
+	if p.Plugin != "" {
+		fields = append(fields, "plugin="+p.Plugin)
+	}
+	return strings.Join(append(fields, "template="+p.Template), " ")
+}
+
+// stamp appends the provenance comment to the contents of the file, unless its format has no comments
+func (p Provenance) stamp(contents string) string {
+	prefix := commentPrefix(p.Path)
+	if prefix == "" {
+		return contents
+	}
+	if contents != "" && !strings.HasSuffix(contents, "\n") {
+		contents += "\n"
+	}
+	return fmt.Sprintf("%s\n%s %s\n", contents, prefix, p)
+}
+
+// commentPrefix returns the delimiter of the line comments of the file at path, empty if it is not known to have
+// comments. go.mod is left out as it is rewritten by the go command.
+func commentPrefix(path string) string {
+	switch base := filepath.Base(path); {
+	case filepath.Ext(base) == ".go":
+		return "//"
+	case base == "Makefile", base == "Dockerfile", base == ".gitignore", base == ".dockerignore":
+		return "#"
+	}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".sh":
+		return "#"
+	}
+	return ""
+}
+
+// parseProvenance returns the provenance stamped at the end of the contents of the file at path
+func parseProvenance(path, contents string) (Provenance, bool) {
+	prefix := commentPrefix(path)
+	if prefix == "" {
+		return Provenance{}, false
+	}
+	lines := strings.Split(strings.TrimRight(contents, "\n"), "\n")
+	fields := strings.Fields(strings.TrimPrefix(lines[len(lines)-1], prefix))
+	if len(fields) == 0 || fields[0] != provenanceMarker {
+		return Provenance{}, false
+	}
+
+	p := Provenance{Path: filepath.ToSlash(path)}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "version":
+			p.Version = kv[1]
+		case "plugin":
+			p.Plugin = kv[1]
+		case "template":
+			p.Template = kv[1]
+		}
+	}
+	return p, true
+}
+
+// provenanceSkippedDirs are the directories of a project that don't hold scaffolded files, .kubebuilder keeps the
+// merge bases which are stamped like the files they were scaffolded as
+var provenanceSkippedDirs = map[string]bool{".git": true, ".kubebuilder": true, "bin": true, "vendor": true}
+
+// ReadProvenance returns the provenance of the files of the project that are stamped with it, sorted by path
+func ReadProvenance(fs afero.Fs) ([]Provenance, error) {
+	var provenances []Provenance
+	err := afero.Walk(fs, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if provenanceSkippedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if commentPrefix(path) == "" {
+			return nil
+		}
+		contents, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		if p, found := parseProvenance(path, string(contents)); found {
+			provenances = append(provenances, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(provenances, func(i, j int) bool { return provenances[i].Path < provenances[j].Path })
+	return provenances, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileAction is what happened to a file while scaffolding
+type FileAction string
+
+const (
+	// FileCreated is reported for files that didn't exist
+	FileCreated FileAction = "created"
+	// FileUpdated is reported for existing files that were overwritten, merged or edited
+	FileUpdated FileAction = "updated"
+	// FileSkipped is reported for existing files that were left untouched
+	FileSkipped FileAction = "skipped"
+)
+
+// FileRole is what a file is used for in the project
+type FileRole string
+
+// Roles of the scaffolded files
+const (
+	RoleTypes      FileRole = "types"
+	RoleController FileRole = "controller"
+	RoleWebhook    FileRole = "webhook"
+	RoleTest       FileRole = "test"
+	RoleMain       FileRole = "main"
+	RoleRBAC       FileRole = "rbac"
+	RoleSample     FileRole = "sample"
+	RoleKustomize  FileRole = "kustomize"
+	RoleOther      FileRole = "other"
+)
+
+// FileReport describes what happened to a file while scaffolding
+type FileReport struct {
+	Path   string     `json:"path"`
+	Action FileAction `json:"action"`
+	Role   FileRole   `json:"role"`
+}
+
+// Reporter is notified of the files that are written while scaffolding
+type Reporter interface {
+	// ReportFile reports what happened to the file at path
+	ReportFile(path string, action FileAction)
+}
+
+var _ Reporter = &TextReporter{}
+
+// TextReporter prints the path of the files that were created or updated
+type TextReporter struct {
+	// Out is where the paths are printed, defaults to the standard output
+	Out io.Writer
+}
+
+// ReportFile implements Reporter
+func (r *TextReporter) ReportFile(path string, action FileAction) {
+	if action == FileSkipped {
+		return
+	}
+
+	out := r.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	fmt.Fprintln(out, path)
+}
+
+var _ Reporter = &JSONReporter{}
+
+// JSONReporter collects the reports to write them as a JSON document once scaffolding is done
+type JSONReporter struct {
+	Files []FileReport `json:"files"`
+	// Error is the error that stopped scaffolding, if any
+	Error string `json:"error,omitempty"`
+}
+
+// ReportFile implements Reporter
+func (r *JSONReporter) ReportFile(path string, action FileAction) {
+	// Files are reported once, those created in this run stay created even if they are edited afterwards
+	for i := range r.Files {
+		if r.Files[i].Path == path {
+			if r.Files[i].Action != FileCreated {
+				r.Files[i].Action = action
+			}
+			return
+		}
+	}
+
+	r.Files = append(r.Files, FileReport{Path: path, Action: action, Role: fileRole(path)})
+}
+
+// Write writes the collected reports to w
+func (r *JSONReporter) Write(w io.Writer) error {
+	if r.Files == nil {
+		r.Files = []FileReport{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// fileRole returns the role of a file from its path
+func fileRole(path string) FileRole {
+	base := filepath.Base(path)
+	switch {
+	case strings.HasSuffix(base, "_test.go"):
+		return RoleTest
+	case strings.HasSuffix(base, "_types.go"), base == "groupversion_info.go",
+		base == "register.go", base == "doc.go":
+		return RoleTypes
+	case strings.HasSuffix(base, "_webhook.go"), strings.HasSuffix(base, "_conversion.go"):
+		return RoleWebhook
+	case strings.HasSuffix(base, "_controller.go"), strings.HasSuffix(base, "_metrics.go"),
+		base == "finalizers.go":
+		return RoleController
+	case base == "main.go":
+		return RoleMain
+	case strings.HasPrefix(path, filepath.Join("config", "rbac")+string(filepath.Separator)):
+		return RoleRBAC
+	case strings.HasPrefix(path, filepath.Join("config", "samples")+string(filepath.Separator)):
+		return RoleSample
+	case strings.HasPrefix(path, "config"+string(filepath.Separator)):
+		return RoleKustomize
+	default:
+		return RoleOther
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+var _ = Describe("JSONReporter", func() {
+	It("should report each file once with its role", func() {
+		r := &scaffold.JSONReporter{}
+		r.ReportFile("api/v1/frigate_types.go", scaffold.FileCreated)
+		r.ReportFile("controllers/suite_test.go", scaffold.FileCreated)
+		r.ReportFile("controllers/suite_test.go", scaffold.FileUpdated)
+		r.ReportFile("main.go", scaffold.FileSkipped)
+		r.ReportFile("main.go", scaffold.FileUpdated)
+
+		Expect(r.Files).To(Equal([]scaffold.FileReport{
+			{Path: "api/v1/frigate_types.go", Action: scaffold.FileCreated, Role: scaffold.RoleTypes},
+			{Path: "controllers/suite_test.go", Action: scaffold.FileCreated, Role: scaffold.RoleTest},
+			{Path: "main.go", Action: scaffold.FileUpdated, Role: scaffold.RoleMain},
+		}))
+	})
+
+	It("should write the reports as JSON", func() {
+		r := &scaffold.JSONReporter{}
+		out := &bytes.Buffer{}
+		Expect(r.Write(out)).To(Succeed())
+		Expect(out.String()).To(Equal("{\n  \"files\": []\n}\n"))
+
+		r.ReportFile(filepath.Join("config", "rbac", "frigate_editor_role.yaml"), scaffold.FileCreated)
+		out.Reset()
+		Expect(r.Write(out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"role": "rbac"`))
+	})
+})
@@ -18,10 +18,14 @@ package resource
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gobuffalo/flect"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
 )
 
 // Resource contains the information required to scaffold files for a resource.
@@ -33,25 +37,368 @@ type Resource struct {
 	// It is used to do safe imports.
 	GroupImportSafe string
 
+	// GroupPackage overrides the name the packages of the API group are imported as, which defaults to the group
+	// without its dashes and dots, e.g. to shorten it
+	GroupPackage string
+
 	// Version is the API version - e.g. v1beta1
 	Version string
 
 	// Kind is the API Kind.
 	Kind string
 
-	// Resource is the API Resource.
+	// Resource is the API Resource, i.e. the plural of the Kind. Defaults to DefaultPlural(Kind).
 	Resource string
 
 	// ShortNames is the list of resource shortnames.
 	ShortNames []string
 
+	// Categories are the groups of resources the resource belongs to, e.g. all, listed by kubectl get <category>
+	Categories []string
+
 	// CreateExampleReconcileBody will create a Deployment in the Reconcile example
 	CreateExampleReconcileBody bool
 
 	// Namespaced is true if the resource is namespaced
 	Namespaced bool
+
+	// Conditions is true if the status of the resource reports conditions
+	Conditions bool
+
+	// ConditionsPackage is true if the conditions of the resource are the ones of the pkg/conditions package of the
+	// project, set and summarized with its helpers, instead of a Condition type of its API version
+	ConditionsPackage bool
+
+	// StatusSubresource is true if the status of the resource is served by the status subresource, so that it is
+	// only updated through it. The resources with Conditions always have it, their controller updates it.
+	StatusSubresource bool
+
+	// Scale is the scale subresource of the resource, used by kubectl scale and the HorizontalPodAutoscaler,
+	// nil if the resource has none
+	Scale *ScaleSubresource
+
+	// Suspend is true if the resource has a spec.suspend field that pauses its reconciliation, like a batch/v1 Job
+	Suspend bool
+
+	// ApplyConfiguration is true if the resource has an apply configuration to patch its objects with server-side
+	// apply
+	ApplyConfiguration bool
+
+	// Finalizer is true if the controller of the resource manages a finalizer to clean up external resources
+	Finalizer bool
+
+	// Metrics is true if the controller of the resource is instrumented with Prometheus metrics
+	Metrics bool
+
+	// ControllerOptions is true if the concurrency and the rate limiting of the controller are configured from
+	// flags of the manager
+	ControllerOptions bool
+
+	// FeatureGate is true if the controller of the resource has an experimental reconcile path behind a feature
+	// gate, set with the --feature-gates flag of the manager
+	FeatureGate bool
+
+	// Events is true if the controller of the resource records Kubernetes events about the objects it reconciles
+	Events bool
+
+	// ExternalAPIPath is the Go package that defines the types of a resource that is not defined in the project,
+	// e.g. k8s.io/api/apps/v1. Its last element must be the version.
+	ExternalAPIPath string
+
+	// ExternalAPIDomain is the domain of the API group of a resource that is not defined in the project
+	ExternalAPIDomain string
+
+	// RBACMode is how the editor and viewer roles of the resource are scaffolded, defaults to RBACModeCluster
+	RBACMode string
+
+	// TestStyle is how the controller tests are scaffolded, defaults to TestStyleEnvtest
+	TestStyle string
+
+	// DefaultsMode is how the default values of the resource are set, empty if they are not scaffolded
+	DefaultsMode string
+
+	// ExampleFields is the set of example fields scaffolded in the spec, defaults to ExampleFieldsBasic
+	ExampleFields string
+
+	// StorageVersion is the version of the Kind that is persisted when it is served in multiple versions
+	StorageVersion string
+
+	// WatchLabelSelector is the label selector, e.g. foo=bar, of the objects reconciled by the controller
+	WatchLabelSelector string
+
+	// ReconcilePeriod is the default delay after which the controller reconciles the objects again, to poll a state
+	// that doesn't trigger watch events, zero if they are only reconciled on changes
+	ReconcilePeriod time.Duration
+
+	// Image is the container image deployed by the controller of the resource, with a Deployment and a Service
+	// owned by each object, empty if the controller doesn't deploy an image
+	Image string
+
+	// ImageContainerPort is the port the Image listens on, exposed by the Service
+	ImageContainerPort int32
+
+	// Owns are the resources whose objects are created and owned by the controller
+	Owns []ResourceRef
+
+	// WatchesExternal are the resources, neither owned nor defined by the controller, whose objects are watched
+	// to reconcile the objects that reference them
+	WatchesExternal []ResourceRef
+
+	// PrintColumns are the additional columns shown by kubectl get for the resource
+	PrintColumns []PrintColumn
+}
+
+// PrintColumn is an additional column shown by kubectl get for a resource
+type PrintColumn struct {
+	// Name is the header of the column, e.g. Ready
+	Name string
+
+	// Type is the OpenAPI type of the column, e.g. string or date
+	Type string
+
+	// JSONPath is the path of the field of the objects shown in the column, e.g. .status.phase
+	JSONPath string
+}
+
+var (
+	// PrintColumnReady shows the status of the Ready condition, which requires the resource to have conditions
+	PrintColumnReady = PrintColumn{
+		Name:     "Ready",
+		Type:     "string",
+		JSONPath: `.status.conditions[?(@.type=="Ready")].status`,
+	}
+	// PrintColumnPhase shows the phase of the resource, a high-level summary of its status
+	PrintColumnPhase = PrintColumn{Name: "Phase", Type: "string", JSONPath: ".status.phase"}
+	// PrintColumnAge shows the age of the objects, which kubectl get only shows by default if there are no
+	// additional columns
+	PrintColumnAge = PrintColumn{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"}
+)
+
+// printColumnTypes are the OpenAPI types of the printer columns
+var printColumnTypes = []string{"string", "integer", "number", "boolean", "date"}
+
+// ParsePrintColumn parses a printer column, either ready, phase, age or a custom column in the
+// name[:type]=JSONPath format, e.g. Replicas:integer=.spec.replicas. The type defaults to string.
+func ParsePrintColumn(value string) (PrintColumn, error) {
+	switch strings.ToLower(value) {
+	case "ready":
+		return PrintColumnReady, nil
+	case "phase":
+		return PrintColumnPhase, nil
+	case "age":
+		return PrintColumnAge, nil
+	}
+
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return PrintColumn{}, fmt.Errorf("printer column must be ready, phase, age or in the name[:type]=JSONPath "+
+			"format, e.g. Replicas:integer=.spec.replicas (was %s)", value)
+	}
+
+	column := PrintColumn{Name: parts[0], Type: "string", JSONPath: parts[1]}
+	if i := strings.LastIndex(column.Name, ":"); i != -1 {
+		column.Name, column.Type = column.Name[:i], column.Name[i+1:]
+	}
+	if err := column.Validate(); err != nil {
+		return PrintColumn{}, fmt.Errorf("invalid printer column %s: %v", value, err)
+	}
+	return column, nil
 }
 
+// Validate checks the PrintColumn values to make sure they are valid.
+func (c PrintColumn) Validate() error {
+	if len(c.Name) == 0 {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if strings.ContainsAny(c.Name, `",;`) {
+		return fmt.Errorf("name cannot contain quotes, commas or semicolons (was %s)", c.Name)
+	}
+
+	validType := false
+	for _, t := range printColumnTypes {
+		if c.Type == t {
+			validType = true
+			break
+		}
+	}
+	if !validType {
+		return fmt.Errorf("type must be one of %s (was %s)", strings.Join(printColumnTypes, ", "), c.Type)
+	}
+
+	if !strings.HasPrefix(c.JSONPath, ".") {
+		return fmt.Errorf("JSONPath must start with a dot, e.g. .spec.replicas (was %s)", c.JSONPath)
+	}
+	return nil
+}
+
+// ScaleSubresource is the scale subresource of a resource, which maps the replicas and the label selector of the
+// Scale objects to fields of the resource
+type ScaleSubresource struct {
+	// SpecReplicasPath is the JSONPath of the desired number of replicas, e.g. .spec.replicas
+	SpecReplicasPath string
+
+	// StatusReplicasPath is the JSONPath of the observed number of replicas, e.g. .status.replicas
+	StatusReplicasPath string
+
+	// LabelSelectorPath is the JSONPath of the label selector of the replicas serialized as a string, e.g.
+	// .status.selector, which the HorizontalPodAutoscaler requires. Empty if the resource has none.
+	LabelSelectorPath string
+}
+
+// DefaultScaleSubresource maps the scale subresource to the replicas and selector fields of a Deployment
+var DefaultScaleSubresource = ScaleSubresource{
+	SpecReplicasPath:   ".spec.replicas",
+	StatusReplicasPath: ".status.replicas",
+	LabelSelectorPath:  ".status.selector",
+}
+
+// ParseScaleSubresource parses a scale subresource in the specpath,statuspath[,selectorpath] format,
+// e.g. .spec.replicas,.status.replicas,.status.selector
+func ParseScaleSubresource(value string) (ScaleSubresource, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 && len(parts) != 3 {
+		return ScaleSubresource{}, fmt.Errorf("scale subresource must be in the specpath,statuspath[,selectorpath] "+
+			"format, e.g. .spec.replicas,.status.replicas,.status.selector (was %s)", value)
+	}
+
+	scale := ScaleSubresource{SpecReplicasPath: parts[0], StatusReplicasPath: parts[1]}
+	if len(parts) == 3 {
+		scale.LabelSelectorPath = parts[2]
+	}
+	if err := scale.Validate(); err != nil {
+		return ScaleSubresource{}, fmt.Errorf("invalid scale subresource %s: %v", value, err)
+	}
+	return scale, nil
+}
+
+// Validate checks the ScaleSubresource values to make sure they are valid.
+func (s ScaleSubresource) Validate() error {
+	if !strings.HasPrefix(s.SpecReplicasPath, ".spec.") {
+		return fmt.Errorf("spec path must be a field of the spec, e.g. .spec.replicas (was %s)", s.SpecReplicasPath)
+	}
+	if !strings.HasPrefix(s.StatusReplicasPath, ".status.") {
+		return fmt.Errorf("status path must be a field of the status, e.g. .status.replicas (was %s)",
+			s.StatusReplicasPath)
+	}
+	if len(s.LabelSelectorPath) != 0 && !strings.HasPrefix(s.LabelSelectorPath, ".status.") {
+		return fmt.Errorf("selector path must be a field of the status, e.g. .status.selector (was %s)",
+			s.LabelSelectorPath)
+	}
+	return nil
+}
+
+// SpecReplicasField returns the JSON name of the replicas field of the spec, empty if the path is not a field of
+// the spec itself, e.g. .spec.template.replicas
+func (s ScaleSubresource) SpecReplicasField() string {
+	return topLevelField(s.SpecReplicasPath, ".spec.")
+}
+
+// StatusReplicasField returns the JSON name of the replicas field of the status, empty if the path is not a field
+// of the status itself
+func (s ScaleSubresource) StatusReplicasField() string {
+	return topLevelField(s.StatusReplicasPath, ".status.")
+}
+
+// LabelSelectorField returns the JSON name of the selector field of the status, empty if the path is not a field
+// of the status itself
+func (s ScaleSubresource) LabelSelectorField() string {
+	return topLevelField(s.LabelSelectorPath, ".status.")
+}
+
+// topLevelField returns the name of the field of the JSONPath right under prefix, empty if the path has more
+// elements or is not a plain field
+func topLevelField(path, prefix string) string {
+	field := strings.TrimPrefix(path, prefix)
+	if field == path || !fieldNameRegexp.MatchString(field) {
+		return ""
+	}
+	return field
+}
+
+// fieldNameRegexp matches the JSON names of the fields scaffolded in the types
+var fieldNameRegexp = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+// ResourceRef is a resource related to the controller of another resource, e.g. owned or watched by it
+type ResourceRef struct {
+	// Group is the API Group, e.g. apps or core for the Kubernetes resources. Does not contain the domain.
+	Group string
+
+	// Version is the API version, e.g. v1
+	Version string
+
+	// Kind is the API Kind, e.g. Deployment
+	Kind string
+}
+
+// ImageResources are the resources created and owned by the controller of a resource that deploys an Image
+var ImageResources = []ResourceRef{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "core", Version: "v1", Kind: "Service"},
+}
+
+// ParseResourceRef parses a resource in the group/version/kind format, e.g. apps/v1/Deployment
+func ParseResourceRef(value string) (ResourceRef, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 {
+		return ResourceRef{}, fmt.Errorf("resource must be in the group/version/kind format, "+
+			"e.g. apps/v1/Deployment (was %s)", value)
+	}
+
+	ref := ResourceRef{Group: parts[0], Version: parts[1], Kind: parts[2]}
+	if err := ref.Validate(); err != nil {
+		return ResourceRef{}, fmt.Errorf("invalid resource %s: %v", value, err)
+	}
+	return ref, nil
+}
+
+// Validate checks the ResourceRef values to make sure they are valid.
+func (ref ResourceRef) Validate() error {
+	if err := ValidateGroup(ref.Group); err != nil {
+		return err
+	}
+	if err := ValidateVersion(ref.Version); err != nil {
+		return err
+	}
+	return ValidateKind(ref.Kind)
+}
+
+// String returns the resource in the group/version/kind format
+func (ref ResourceRef) String() string {
+	return ref.Group + "/" + ref.Version + "/" + ref.Kind
+}
+
+const (
+	// RBACModeCluster scaffolds the editor and viewer roles as ClusterRoles
+	RBACModeCluster = "cluster"
+	// RBACModeAggregate scaffolds the editor and viewer roles as ClusterRoles aggregated to the default
+	// admin, edit and view ClusterRoles
+	RBACModeAggregate = "aggregate"
+	// RBACModeNamespaced scaffolds the editor and viewer roles as Roles to be granted per namespace
+	RBACModeNamespaced = "namespaced"
+)
+
+const (
+	// DefaultsModeMarkers sets the default values with +kubebuilder:default markers in the CRD schema
+	DefaultsModeMarkers = "markers"
+	// DefaultsModeWebhook sets the default values from a defaulting webhook
+	DefaultsModeWebhook = "webhook"
+)
+
+const (
+	// ExampleFieldsBasic scaffolds a single example field in the spec
+	ExampleFieldsBasic = "basic"
+	// ExampleFieldsRich also scaffolds example fields with the OpenAPI validation markers, e.g. enums, patterns,
+	// ranges, required fields and CEL validation rules
+	ExampleFieldsRich = "rich"
+)
+
+const (
+	// TestStyleEnvtest scaffolds controller tests that run against a local control plane started with envtest
+	TestStyleEnvtest = "envtest"
+	// TestStyleFake scaffolds table-driven controller unit tests that run against a fake client
+	TestStyleFake = "fake"
+)
+
 // Validate checks the Resource values to make sure they are valid.
 func (r *Resource) Validate() error {
 	if r.isGroupEmpty() {
@@ -63,29 +410,303 @@ func (r *Resource) Validate() error {
 	if r.isKindEmpty() {
 		return fmt.Errorf("kind cannot be empty")
 	}
+
+	// Resources without a group are served in the API group named after the domain
+	if len(r.Group) != 0 {
+		if err := ValidateGroup(r.Group); err != nil {
+			return err
+		}
+	}
+
+	if len(r.GroupPackage) != 0 {
+		if err := ValidateGroupPackage(r.GroupPackage); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateVersion(r.Version); err != nil {
+		return err
+	}
+
+	if err := ValidateKind(r.Kind); err != nil {
+		return err
+	}
+
+	if len(r.ExternalAPIPath) != 0 && path.Base(r.ExternalAPIPath) != r.Version {
+		return fmt.Errorf("external API path must end with the version %s (was %s)", r.Version, r.ExternalAPIPath)
+	}
+
+	if len(r.Resource) != 0 {
+		if err := ValidatePlural(r.Resource); err != nil {
+			return err
+		}
+	}
+
+	if err := validateNames(r.ShortNames, "short name"); err != nil {
+		return err
+	}
+	if err := validateNames(r.Categories, "category"); err != nil {
+		return err
+	}
+
+	if len(r.RBACMode) != 0 {
+		if err := ValidateRBACMode(r.RBACMode); err != nil {
+			return err
+		}
+	}
+
+	if len(r.DefaultsMode) != 0 {
+		if err := ValidateDefaultsMode(r.DefaultsMode); err != nil {
+			return err
+		}
+	}
+
+	if len(r.ExampleFields) != 0 {
+		if err := ValidateExampleFields(r.ExampleFields); err != nil {
+			return err
+		}
+	}
+
+	if len(r.TestStyle) != 0 {
+		if err := ValidateTestStyle(r.TestStyle); err != nil {
+			return err
+		}
+	}
+
+	if len(r.WatchLabelSelector) != 0 {
+		if err := ValidateLabelSelector(r.WatchLabelSelector); err != nil {
+			return err
+		}
+	}
+
+	if r.ReconcilePeriod < 0 {
+		return fmt.Errorf("reconcile period must be positive, got %s", r.ReconcilePeriod)
+	}
+
+	if len(r.Image) != 0 && (r.ImageContainerPort < 1 || r.ImageContainerPort > 65535) {
+		return fmt.Errorf("image container port must be between 1 and 65535 (was %d)", r.ImageContainerPort)
+	}
+
+	// The scaffolded variables and functions are named after the Kind of the related resources
+	if err := validateResourceRefs(r.Owns, "owned"); err != nil {
+		return err
+	}
+	if err := validateResourceRefs(r.WatchesExternal, "watched"); err != nil {
+		return err
+	}
+
+	if r.Scale != nil {
+		if err := r.Scale.Validate(); err != nil {
+			return fmt.Errorf("invalid scale subresource: %v", err)
+		}
+	}
+
+	columns := make(map[string]bool, len(r.PrintColumns))
+	for _, column := range r.PrintColumns {
+		if err := column.Validate(); err != nil {
+			return fmt.Errorf("invalid printer column %s: %v", column.Name, err)
+		}
+		if columns[column.Name] {
+			return fmt.Errorf("printer columns must have different names (%s is repeated)", column.Name)
+		}
+		columns[column.Name] = true
+	}
+
+	// todo: move it for the proper place since they are not validations and then, should not be here
+	// Add in r.Resource the Kind plural
+	if len(r.Resource) == 0 {
+		r.Resource = DefaultPlural(r.Kind)
+	}
+	// Replace the caracter "-" for "" to allow scaffold the go imports
+	r.GroupImportSafe = templatefuncs.GroupPackageName(r.Group)
+	if len(r.GroupPackage) != 0 {
+		r.GroupImportSafe = r.GroupPackage
+	}
+	return nil
+}
+
+// NormalizeGroup returns the API group in lower case and without the domain in case it was included, e.g. my-app
+// for My-App.example.com in the example.com domain
+func NormalizeGroup(group, domain string) string {
+	group = strings.ToLower(group)
+	if len(domain) != 0 {
+		group = strings.TrimSuffix(group, "."+strings.ToLower(domain))
+	}
+	return group
+}
+
+// ValidateGroup checks that the provided value is a valid API Group
+func ValidateGroup(group string) error {
+	if len(group) == 0 {
+		return fmt.Errorf("group cannot be empty")
+	}
+
 	// Check if the Group has a valid value for for it
-	if err := IsDNS1123Subdomain(r.Group); err != nil {
+	if err := IsDNS1123Subdomain(group); err != nil {
 		return fmt.Errorf("group name is invalid: (%v)", err)
 	}
+
+	return nil
+}
+
+// groupPackageRegex matches the names the packages of an API group can be imported as
+var groupPackageRegex = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// ValidateGroupPackage checks that the provided value is a valid name to import the packages of an API group as
+func ValidateGroupPackage(name string) error {
+	if !groupPackageRegex.MatchString(name) {
+		return fmt.Errorf("group package must be lower case alphanumeric characters starting with a letter (was %s)",
+			name)
+	}
+	return nil
+}
+
+// ValidateVersion checks that the provided value is a valid API version
+func ValidateVersion(version string) error {
+	if len(version) == 0 {
+		return fmt.Errorf("version cannot be empty")
+	}
+
 	// Check if the version is a valid value
-	versionMatch := regexp.MustCompile(`^v\d+(alpha\d+|beta\d+)?$`)
-	if !versionMatch.MatchString(r.Version) {
+	if !versionRegexp.MatchString(version) {
 		return fmt.Errorf(
-			"version must match ^v\\d+(alpha\\d+|beta\\d+)?$ (was %s)", r.Version)
+			"version must match %s (was %s)", versionFmt, version)
+	}
+
+	return nil
+}
+
+// ValidateKind checks that the provided value is a valid API Kind
+func ValidateKind(kind string) error {
+	if len(kind) == 0 {
+		return fmt.Errorf("kind cannot be empty")
 	}
+
 	// Check if the Kind is a valid value
-	if r.Kind != flect.Pascalize(r.Kind) {
-		return fmt.Errorf("kind must be PascalCase (expected %s was %s)", flect.Pascalize(r.Kind), r.Kind)
+	if kind != flect.Pascalize(kind) {
+		return fmt.Errorf("kind must be PascalCase (expected %s was %s)", flect.Pascalize(kind), kind)
 	}
 
-	// todo: move it for the proper place since they are not validations and then, should not be here
-	// Add in r.Resource the Kind plural
+	return nil
+}
+
+// ValidatePlural checks that the provided value is a valid API Resource, i.e. the plural of a Kind
+func ValidatePlural(plural string) error {
+	if len(plural) == 0 {
+		return fmt.Errorf("plural cannot be empty")
+	}
+
+	if errs := isDNS1035Label(plural); len(errs) != 0 {
+		return fmt.Errorf("plural is invalid: (%v)", errs)
+	}
+
+	return nil
+}
+
+// DefaultPlural returns the API Resource of a Kind unless a different plural is provided
+func DefaultPlural(kind string) string {
+	return templatefuncs.Plural(kind)
+}
+
+// QualifiedGroup returns the API group of the Resource in the provided domain, e.g. ship.example.org, which is
+// the domain itself for resources without a group
+func (r *Resource) QualifiedGroup(domain string) string {
+	if len(r.Group) == 0 {
+		return domain
+	}
+	return r.Group + "." + domain
+}
+
+// Plural returns the API Resource, defaulting to the plural of the Kind if it was not validated yet
+func (r *Resource) Plural() string {
 	if len(r.Resource) == 0 {
-		r.Resource = flect.Pluralize(strings.ToLower(r.Kind))
+		return DefaultPlural(r.Kind)
+	}
+	return r.Resource
+}
+
+// HasDefaultPlural returns true if the Resource is the plural that kubebuilder and controller-gen default to
+func (r *Resource) HasDefaultPlural() bool {
+	return r.Plural() == DefaultPlural(r.Kind)
+}
+
+// ValidateRBACMode checks that the provided value is a valid RBAC mode
+func ValidateRBACMode(mode string) error {
+	switch mode {
+	case RBACModeCluster, RBACModeAggregate, RBACModeNamespaced:
+		return nil
+	default:
+		return fmt.Errorf("rbac mode must be one of %s, %s or %s (was %s)",
+			RBACModeCluster, RBACModeAggregate, RBACModeNamespaced, mode)
+	}
+}
+
+// ValidateDefaultsMode checks that the provided value is a valid defaults mode
+func ValidateDefaultsMode(mode string) error {
+	switch mode {
+	case DefaultsModeMarkers, DefaultsModeWebhook:
+		return nil
+	default:
+		return fmt.Errorf("defaults mode must be one of %s or %s (was %s)", DefaultsModeMarkers, DefaultsModeWebhook, mode)
+	}
+}
+
+// ValidateExampleFields checks that the provided value is a valid set of example fields
+func ValidateExampleFields(fields string) error {
+	switch fields {
+	case ExampleFieldsBasic, ExampleFieldsRich:
+		return nil
+	default:
+		return fmt.Errorf("example fields must be one of %s or %s (was %s)", ExampleFieldsBasic, ExampleFieldsRich, fields)
+	}
+}
+
+// ValidateTestStyle checks that the provided value is a valid test style
+func ValidateTestStyle(style string) error {
+	switch style {
+	case TestStyleEnvtest, TestStyleFake:
+		return nil
+	default:
+		return fmt.Errorf("test style must be one of %s or %s (was %s)", TestStyleEnvtest, TestStyleFake, style)
+	}
+}
+
+// validateResourceRefs checks that the related resources are valid and have different kinds
+func validateResourceRefs(refs []ResourceRef, relation string) error {
+	kinds := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if err := ref.Validate(); err != nil {
+			return fmt.Errorf("invalid %s resource %s: %v", relation, ref, err)
+		}
+		if kinds[ref.Kind] {
+			return fmt.Errorf("%s resources must have different kinds (%s is %s twice)", relation, ref.Kind, relation)
+		}
+		kinds[ref.Kind] = true
+	}
+	return nil
+}
+
+// validateNames checks that the short names or categories of a resource are valid and different from each other
+func validateNames(names []string, kind string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if errs := isDNS1035Label(name); len(errs) != 0 {
+			return fmt.Errorf("%s %s is invalid: (%v)", kind, name, errs)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s %s is repeated", kind, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// ValidateLabelSelector checks that the provided value is a valid label selector, e.g. foo=bar,!baz
+func ValidateLabelSelector(selector string) error {
+	if !labelSelectorRegexp.MatchString(selector) {
+		return fmt.Errorf("label selector must be a comma-separated list of requirements like "+
+			"key=value, key!=value, key in (v1,v2), key notin (v1,v2), key or !key (was %s)", selector)
 	}
-	// Replace the caracter "-" for "" to allow scaffold the go imports
-	r.GroupImportSafe = strings.Replace(r.Group, "-", "", -1)
-	r.GroupImportSafe = strings.Replace(r.GroupImportSafe, ".", "", -1)
 	return nil
 }
 
@@ -101,12 +722,27 @@ func (r *Resource) isVersionEmpty() bool {
 	return len(r.Version) == 0 || r.Version == "--group" || r.Version == "--kind"
 }
 
-// isVersionEmpty will return true if the --group flag do not be informed
-// NOTE: required check if the flags are assuming the other flags as value
+// isGroupEmpty will return true if the --group flag assumed the other flags as value, as the group of resources
+// served in the API group named after the domain is empty
 func (r *Resource) isGroupEmpty() bool {
-	return len(r.Group) == 0 || r.Group == "--version" || r.Group == "--kind"
+	return r.Group == "--version" || r.Group == "--kind"
 }
 
+const versionFmt string = `^v\d+(alpha\d+|beta\d+)?$`
+
+var versionRegexp = regexp.MustCompile(versionFmt)
+
+const (
+	labelKeyFmt    string = `([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?` + labelValueFmt
+	labelValueFmt  string = `[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?`
+	labelValuesFmt string = `\(\s*(` + labelValueFmt + `)?(\s*,\s*(` + labelValueFmt + `)?)*\s*\)`
+	// labelRequirementFmt matches the existence, equality-based and set-based requirements of a label selector
+	labelRequirementFmt string = `\s*(!\s*` + labelKeyFmt + `|` + labelKeyFmt +
+		`(\s*(==?|!=)\s*(` + labelValueFmt + `)?|\s+(in|notin)\s*` + labelValuesFmt + `)?)\s*`
+)
+
+var labelSelectorRegexp = regexp.MustCompile("^" + labelRequirementFmt + "(," + labelRequirementFmt + ")*$")
+
 // The following code came from "k8s.io/apimachinery/pkg/util/validation"
 // If be required the usage of more funcs from this then please replace it for the import
 // ---------------------------------------
@@ -122,6 +758,30 @@ const (
 
 var dns1123SubdomainRegexp = regexp.MustCompile("^" + dns1123SubdomainFmt + "$")
 
+const (
+	dns1035LabelFmt      string = "[a-z]([-a-z0-9]*[a-z0-9])?"
+	dns1035LabelErrorMsg string = "a DNS-1035 label must consist of lower case alphanumeric characters or '-'," +
+		" start with an alphabetic character, and end with an alphanumeric character"
+
+	// dns1035LabelMaxLength is a label's max length in DNS (RFC 1035)
+	dns1035LabelMaxLength int = 63
+)
+
+var dns1035LabelRegexp = regexp.MustCompile("^" + dns1035LabelFmt + "$")
+
+// isDNS1035Label tests for a string that conforms to the definition of a label in
+// DNS (RFC 1035).
+func isDNS1035Label(value string) []string {
+	var errs []string
+	if len(value) > dns1035LabelMaxLength {
+		errs = append(errs, maxLenError(dns1035LabelMaxLength))
+	}
+	if !dns1035LabelRegexp.MatchString(value) {
+		errs = append(errs, regexError(dns1035LabelErrorMsg, dns1035LabelFmt, "my-name", "abc-123"))
+	}
+	return errs
+}
+
 // IsDNS1123Subdomain tests for a string that conforms to the definition of a
 // subdomain in DNS (RFC 1123).
 func IsDNS1123Subdomain(value string) []string {
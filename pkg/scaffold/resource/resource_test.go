@@ -28,10 +28,17 @@ var _ = Describe("Resource", func() {
 		It("should succeed if the Resource is valid", func() {
 			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate"}
 			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.QualifiedGroup("example.com")).To(Equal("crew.example.com"))
 		})
 
-		It("should fail if the Group is not specified", func() {
+		It("should succeed if the Group is empty", func() {
 			instance := &Resource{Version: "v1", Kind: "FirstMate"}
+			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.QualifiedGroup("example.com")).To(Equal("example.com"))
+		})
+
+		It("should fail if the Group flag assumed the Version flag as value", func() {
+			instance := &Resource{Group: "--version", Version: "v1", Kind: "FirstMate"}
 			Expect(instance.Validate()).NotTo(Succeed())
 			Expect(instance.Validate().Error()).To(ContainSubstring("group cannot be empty"))
 		})
@@ -141,10 +148,239 @@ var _ = Describe("Resource", func() {
 			Expect(instance.GroupImportSafe).To(Equal("examplecom"))
 		})
 
+		It("should import the group as the overridden package", func() {
+			instance := &Resource{Group: "my-project", GroupPackage: "project", Kind: "Cat", Version: "v1"}
+			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.GroupImportSafe).To(Equal("project"))
+		})
+
+		It("should fail if the group package is not a valid package name", func() {
+			instance := &Resource{Group: "my-project", GroupPackage: "my-project", Kind: "Cat", Version: "v1"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("group package must be"))
+		})
+
 		It("should keep the Resource if specified", func() {
 			instance := &Resource{Group: "crew", Kind: "FirstMate", Version: "v1", Resource: "myresource"}
 			Expect(instance.Validate()).To(Succeed())
 			Expect(instance.Resource).To(Equal("myresource"))
 		})
+
+		It("should fail if the Resource is not a valid plural", func() {
+			instance := &Resource{Group: "crew", Kind: "FirstMate", Version: "v1", Resource: "FirstMates"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("plural is invalid"))
+		})
+
+		It("should tell whether the Resource is the default plural", func() {
+			instance := &Resource{Group: "crew", Kind: "Octopus", Version: "v1"}
+			Expect(instance.HasDefaultPlural()).To(BeTrue())
+			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.Resource).To(Equal("octopi"))
+			Expect(instance.HasDefaultPlural()).To(BeTrue())
+
+			instance.Resource = "octopuses"
+			Expect(instance.HasDefaultPlural()).To(BeFalse())
+		})
+
+		It("should fail if the external API path does not end with the version", func() {
+			instance := &Resource{Group: "apps", Version: "v1", Kind: "Deployment", ExternalAPIPath: "k8s.io/api/apps"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("external API path must end with the version"))
+		})
+
+		It("should fail if the RBAC mode is unknown", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate", RBACMode: "global"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("rbac mode must be one of"))
+		})
+
+		It("should fail if the test style is unknown", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate", TestStyle: "mock"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("test style must be one of"))
+		})
+
+		It("should fail if the watch label selector is invalid", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate", WatchLabelSelector: `foo="bar"`}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("label selector must be"))
+		})
+
+		It("should fail if the container port of the image is out of range", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate", Image: "nginx:1.19"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("image container port must be between 1 and 65535"))
+		})
+
+		It("should fail if an owned resource is invalid", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate",
+				Owns: []ResourceRef{{Group: "apps", Version: "1", Kind: "Deployment"}}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("invalid owned resource apps/1/Deployment"))
+		})
+
+		It("should fail if two owned resources have the same kind", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate", Owns: []ResourceRef{
+				{Group: "core", Version: "v1", Kind: "Event"},
+				{Group: "events", Version: "v1beta1", Kind: "Event"},
+			}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("Event is owned twice"))
+		})
+
+		It("should fail if a short name or category is invalid", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate",
+				ShortNames: []string{"FM"}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("short name FM is invalid"))
+
+			instance = &Resource{Group: "crew", Version: "v1", Kind: "FirstMate",
+				ShortNames: []string{"fm"}, Categories: []string{"crew", "crew"}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("category crew is repeated"))
+		})
+
+		It("should fail if two printer columns have the same name", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate",
+				PrintColumns: []PrintColumn{PrintColumnAge, PrintColumnAge}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("Age is repeated"))
+		})
+
+		It("should fail if a watched resource is invalid", func() {
+			instance := &Resource{Group: "crew", Version: "v1", Kind: "FirstMate",
+				WatchesExternal: []ResourceRef{{Group: "core", Version: "v1", Kind: "secret"}}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("invalid watched resource core/v1/secret"))
+		})
+	})
+})
+
+var _ = Describe("Resource field validation", func() {
+	It("should validate the Group on its own", func() {
+		Expect(ValidateGroup("crew")).To(Succeed())
+		Expect(ValidateGroup("")).NotTo(Succeed())
+		Expect(ValidateGroup("Crew")).NotTo(Succeed())
+	})
+
+	It("should normalize the Group", func() {
+		Expect(NormalizeGroup("My-App", "example.com")).To(Equal("my-app"))
+		Expect(NormalizeGroup("My-App.Example.com", "example.com")).To(Equal("my-app"))
+		Expect(NormalizeGroup("my-app.example.org", "example.com")).To(Equal("my-app.example.org"))
+		Expect(NormalizeGroup("", "example.com")).To(Equal(""))
+	})
+
+	It("should validate the group package on its own", func() {
+		Expect(ValidateGroupPackage("myapp")).To(Succeed())
+		Expect(ValidateGroupPackage("")).NotTo(Succeed())
+		Expect(ValidateGroupPackage("my-app")).NotTo(Succeed())
+		Expect(ValidateGroupPackage("1app")).NotTo(Succeed())
+	})
+
+	It("should validate the Version on its own", func() {
+		Expect(ValidateVersion("v1beta1")).To(Succeed())
+		Expect(ValidateVersion("")).NotTo(Succeed())
+		Expect(ValidateVersion("1beta1")).NotTo(Succeed())
+	})
+
+	It("should validate the Kind on its own", func() {
+		Expect(ValidateKind("FirstMate")).To(Succeed())
+		Expect(ValidateKind("")).NotTo(Succeed())
+		Expect(ValidateKind("firstMate")).NotTo(Succeed())
+	})
+
+	It("should validate the plural on its own", func() {
+		Expect(ValidatePlural("firstmates")).To(Succeed())
+		Expect(ValidatePlural("")).NotTo(Succeed())
+		Expect(ValidatePlural("first.mates")).NotTo(Succeed())
+	})
+
+	It("should validate the RBAC mode on its own", func() {
+		Expect(ValidateRBACMode(RBACModeAggregate)).To(Succeed())
+		Expect(ValidateRBACMode("")).NotTo(Succeed())
+		Expect(ValidateRBACMode("global")).NotTo(Succeed())
+	})
+
+	It("should validate the defaults mode on its own", func() {
+		Expect(ValidateDefaultsMode(DefaultsModeMarkers)).To(Succeed())
+		Expect(ValidateDefaultsMode(DefaultsModeWebhook)).To(Succeed())
+		Expect(ValidateDefaultsMode("schema")).NotTo(Succeed())
+	})
+
+	It("should validate the example fields on their own", func() {
+		Expect(ValidateExampleFields(ExampleFieldsBasic)).To(Succeed())
+		Expect(ValidateExampleFields(ExampleFieldsRich)).To(Succeed())
+		Expect(ValidateExampleFields("all")).NotTo(Succeed())
+	})
+
+	It("should validate the test style on its own", func() {
+		Expect(ValidateTestStyle(TestStyleFake)).To(Succeed())
+		Expect(ValidateTestStyle("")).NotTo(Succeed())
+		Expect(ValidateTestStyle("mock")).NotTo(Succeed())
+	})
+
+	It("should validate the label selector on its own", func() {
+		Expect(ValidateLabelSelector("foo=bar")).To(Succeed())
+		Expect(ValidateLabelSelector("app.kubernetes.io/name==web, tier != cache,canary,!legacy")).To(Succeed())
+		Expect(ValidateLabelSelector("env in (prod, staging),zone notin (a)")).To(Succeed())
+		Expect(ValidateLabelSelector("")).NotTo(Succeed())
+		Expect(ValidateLabelSelector("foo=bar,")).NotTo(Succeed())
+		Expect(ValidateLabelSelector(`foo="bar"`)).NotTo(Succeed())
+		Expect(ValidateLabelSelector("env in prod")).NotTo(Succeed())
+	})
+
+	It("should parse the resource references", func() {
+		owned, err := ParseResourceRef("apps/v1/Deployment")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owned).To(Equal(ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment"}))
+		Expect(owned.String()).To(Equal("apps/v1/Deployment"))
+
+		_, err = ParseResourceRef("apps/Deployment")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseResourceRef("core/v1/service")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should parse the printer columns", func() {
+		column, err := ParsePrintColumn("Ready")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(column).To(Equal(PrintColumnReady))
+
+		column, err = ParsePrintColumn("Replicas:integer=.spec.replicas")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(column).To(Equal(PrintColumn{Name: "Replicas", Type: "integer", JSONPath: ".spec.replicas"}))
+
+		column, err = ParsePrintColumn("Image=.spec.image")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(column.Type).To(Equal("string"))
+
+		_, err = ParsePrintColumn("Replicas")
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePrintColumn("Replicas:int=.spec.replicas")
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePrintColumn("Replicas=spec.replicas")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should parse the scale subresource", func() {
+		scale, err := ParseScaleSubresource(".spec.replicas,.status.replicas,.status.selector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scale).To(Equal(DefaultScaleSubresource))
+		Expect(scale.SpecReplicasField()).To(Equal("replicas"))
+		Expect(scale.LabelSelectorField()).To(Equal("selector"))
+
+		scale, err = ParseScaleSubresource(".spec.template.replicas,.status.readyReplicas")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scale.SpecReplicasField()).To(BeEmpty())
+		Expect(scale.StatusReplicasField()).To(Equal("readyReplicas"))
+		Expect(scale.LabelSelectorPath).To(BeEmpty())
+
+		_, err = ParseScaleSubresource(".spec.replicas")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseScaleSubresource(".status.replicas,.status.replicas")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseScaleSubresource(".spec.replicas,.status.replicas,.spec.selector")
+		Expect(err).To(HaveOccurred())
 	})
 })
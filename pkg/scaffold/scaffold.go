@@ -20,19 +20,23 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 
+	"github.com/spf13/afero"
 	"golang.org/x/tools/imports"
 
 	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	scaffolderrors "sigs.k8s.io/kubebuilder/pkg/scaffold/errors"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
 )
 
 var options = imports.Options{
@@ -42,6 +46,9 @@ var options = imports.Options{
 	FormatOnly: true,
 }
 
+// boilerplatePath is the default path of the boilerplate file written by init and added to the scaffolded files
+var boilerplatePath = filepath.Join("hack", "boilerplate.go.txt")
+
 // Scaffold writes Templates to scaffold new files
 type Scaffold struct {
 	// BoilerplatePath is the path to the boilerplate file
@@ -56,22 +63,40 @@ type Scaffold struct {
 	// ConfigPath is the relative path to the project root
 	ConfigPath string
 
-	GetWriter func(path string) (io.Writer, error)
-
 	// Plugins is the list of plugins we should allow to transform our generated scaffolding
 	Plugins []Plugin
 
-	FileExists func(path string) bool
+	// Fs is the filesystem where files are written, defaults to the OS filesystem
+	Fs afero.Fs
 
 	// BoilerplateOptional, if true, skips errors reading the Boilerplate file
 	BoilerplateOptional bool
 
 	// ConfigOptional, if true, skips errors reading the project configuration
 	ConfigOptional bool
+
+	// TemplatesDir is a directory with templates that replace the built-in ones.
+	// The template of a file is looked up at <TemplatesDir>/<package>/<type>.tmpl, where package is relative
+	// to pkg/scaffold, e.g. v2/controller/Controller.tmpl replaces the template of controllerv2.Controller
+	TemplatesDir string
+
+	// Merge, if true, three-way merges the files that already exist instead of failing, using the version
+	// recorded when they were scaffolded as the base. Changes that can't be merged are left as conflicts.
+	Merge bool
+
+	// SkipExisting, if true, leaves the files that already exist untouched whatever their IfExistsAction, to
+	// adopt a project whose files were not scaffolded by kubebuilder
+	SkipExisting bool
+
+	// Reporter, if set, is notified of the files that are created, updated or skipped
+	Reporter Reporter
+
+	// Out is where the messages for the user are printed, defaults to the standard output
+	Out io.Writer
 }
 
 // Plugin is the interface that a plugin must implement
-// We will (later) have an ExecPlugin that implements this by exec-ing a binary
+// ExecPlugin implements it by exec-ing a binary
 type Plugin interface {
 	// Pipe is the core plugin interface, that transforms a UniverseModel
 	Pipe(universe *model.Universe) error
@@ -120,7 +145,7 @@ func validate(file input.File) error {
 func (s *Scaffold) defaultOptions(options *input.Options) error {
 	// Use the default Boilerplate path if unset
 	if options.BoilerplatePath == "" {
-		options.BoilerplatePath = filepath.Join("hack", "boilerplate.go.txt")
+		options.BoilerplatePath = boilerplatePath
 	}
 
 	// Use the default Project path if unset
@@ -131,13 +156,13 @@ func (s *Scaffold) defaultOptions(options *input.Options) error {
 	s.BoilerplatePath = options.BoilerplatePath
 
 	var err error
-	s.Config, err = internalconfig.ReadFrom(options.ProjectPath)
+	s.Config, err = internalconfig.ReadFromFs(s.Fs, options.ProjectPath)
 	if !s.ConfigOptional && err != nil {
 		return err
 	}
 
 	var boilerplateBytes []byte
-	boilerplateBytes, err = ioutil.ReadFile(options.BoilerplatePath) // nolint:gosec
+	boilerplateBytes, err = afero.ReadFile(s.Fs, options.BoilerplatePath)
 	if !s.BoilerplateOptional && err != nil {
 		return err
 	}
@@ -164,108 +189,318 @@ func (s *Scaffold) Execute(
 	options input.Options,
 	files ...input.File,
 ) error {
-	if s.GetWriter == nil {
-		s.GetWriter = (&FileWriter{}).WriteCloser
+	templates, err := s.render(universe, options, files...)
+	if err != nil {
+		return err
 	}
-	if s.FileExists == nil {
-		s.FileExists = func(path string) bool {
-			_, err := os.Stat(path)
-			return err == nil
+
+	// Resolve every file before writing any of them, so that an existing file doesn't leave the rest half written
+	contents := make([]string, len(universe.Files))
+	actions := make([]FileAction, len(universe.Files))
+	for i, f := range universe.Files {
+		var err error
+		if contents[i], actions[i], err = s.resolveFile(f); err != nil {
+			return err
 		}
 	}
 
-	if err := s.defaultOptions(&options); err != nil {
+	// The files, their merge bases and the manifest are staged in memory and written at once, so that failing to
+	// write one of them doesn't leave the rest half written
+	fs := s.Fs
+	staged := NewDryRunFs(fs)
+	s.Fs = staged
+	defer func() { s.Fs = fs }()
+
+	for i, f := range universe.Files {
+		if actions[i] == FileSkipped {
+			s.report(f.Path, FileSkipped)
+			continue
+		}
+		if err := s.writeFile(f, contents[i], actions[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := s.recordFiles(universe.Files, actions, templates); err != nil {
 		return err
 	}
+	return staged.Commit()
+}
+
+// render renders the files into the universe, and returns the template of each of them by path
+func (s *Scaffold) render(
+	universe *model.Universe,
+	options input.Options,
+	files ...input.File,
+) (map[string]string, error) {
+	if s.Fs == nil {
+		s.Fs = afero.NewOsFs()
+	}
+
+	if err := s.defaultOptions(&options); err != nil {
+		return nil, err
+	}
 
 	s.universeDefaults(universe, len(files))
 
 	// Set the repo as the local prefix so that it knows how to group imports
 	imports.LocalPrefix = universe.Config.Repo
 
-	for _, f := range files {
-		m, err := s.buildFileModel(f)
-		if err != nil {
-			return err
+	// The inputs are built one file after the other, as the files may share their resource
+	inputs := make([]input.Input, len(files))
+	templates := make(map[string]string, len(files))
+	for i, f := range files {
+		var err error
+		if inputs[i], err = s.buildFileInput(f); err != nil {
+			return nil, err
 		}
-		universe.Files = append(universe.Files, m)
+		templates[inputs[i].Path] = inputs[i].TemplateBody
 	}
 
+	models, err := renderFiles(files, inputs, s.out())
+	if err != nil {
+		return nil, err
+	}
+	// The templates are those of the base plugin of the project
+	if keys := universe.Config.PluginKeys(); len(keys) != 0 {
+		for _, m := range models {
+			m.Plugin = keys[0]
+		}
+	}
+	// The files of v2 projects are stamped with their provenance
+	if universe.Config.IsV2() {
+		for _, m := range models {
+			stampProvenance(m)
+		}
+	}
+	universe.Files = append(universe.Files, models...)
+
 	for _, plugin := range s.Plugins {
 		if err := plugin.Pipe(universe); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	for _, f := range universe.Files {
-		if err := s.writeFile(f); err != nil {
-			return err
-		}
+	return templates, nil
+}
+
+// recordFiles records the files that were written in the manifest of the project, so that the ones that are not
+// modified can be updated when their templates change
+func (s *Scaffold) recordFiles(files []*model.File, actions []FileAction, templates map[string]string) error {
+	if s.Config == nil || !s.Config.IsV2() {
+		return nil
 	}
 
-	return nil
+	m, err := readManifest(s.Fs)
+	if err != nil {
+		return err
+	}
+	recorded := false
+	for i, f := range files {
+		if actions[i] == FileSkipped {
+			continue
+		}
+		m.record(f, templates[f.Path])
+		recorded = true
+	}
+	if !recorded {
+		return nil
+	}
+	return m.write(s.Fs)
 }
 
-// doFile scaffolds a single file
-func (s *Scaffold) buildFileModel(e input.File) (*model.File, error) {
+// buildFileInput returns the template input params of a single file
+func (s *Scaffold) buildFileInput(e input.File) (input.Input, error) {
 	// Set common fields
 	s.setFields(e)
 
 	// Validate the file scaffold
 	if err := validate(e); err != nil {
-		return nil, err
+		return input.Input{}, err
 	}
 
 	// Get the template input params
 	i, err := e.GetInput()
 	if err != nil {
-		return nil, err
+		return input.Input{}, err
 	}
 
-	m := &model.File{
-		Path: i.Path,
+	// Replace the built-in template if it was overridden
+	if s.TemplatesDir != "" {
+		body, err := afero.ReadFile(s.Fs, s.templateOverridePath(e))
+		switch {
+		case err == nil:
+			i.TemplateBody = string(body)
+		case !os.IsNotExist(err):
+			return input.Input{}, err
+		}
 	}
 
-	b, err := doTemplate(i, e)
-	if err != nil {
-		return nil, err
+	return i, nil
+}
+
+// renderFiles executes the templates of the files concurrently, which formatting the Go files makes the slowest
+// part of scaffolding, and returns their models in the same order as the files. The Go files that can't be
+// formatted are printed to out.
+func renderFiles(files []input.File, inputs []input.Input, out io.Writer) ([]*model.File, error) {
+	models := make([]*model.File, len(files))
+	errs := make([]error, len(files))
+
+	workers := runtime.NumCPU()
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				b, err := doTemplate(inputs[i], files[i], out)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				models[i] = &model.File{
+					Path:           inputs[i].Path,
+					IfExistsAction: inputs[i].IfExistsAction,
+					Contents:       string(b),
+					Template:       templateName(files[i]),
+				}
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
 	}
-	m.Contents = string(b)
+	close(indexes)
+	wg.Wait()
 
-	return m, nil
+	// Return the error of the first file that failed, as if they were rendered one after the other
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return models, nil
 }
 
-func (s *Scaffold) writeFile(file *model.File) error {
+// resolveFile returns the contents that should be written for the file and whether it is created, updated or skipped
+func (s *Scaffold) resolveFile(file *model.File) (string, FileAction, error) {
 	// Check if the file to write already exists
-	if s.FileExists(file.Path) {
-		switch file.IfExistsAction {
-		case input.Overwrite:
-		case input.Skip:
-			return nil
-		case input.Error:
-			return fmt.Errorf("%s already exists", file.Path)
+	exists, err := afero.Exists(s.Fs, file.Path)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		return file.Contents, FileCreated, nil
+	}
+	if s.SkipExisting {
+		return "", FileSkipped, nil
+	}
+
+	switch file.IfExistsAction {
+	case input.Skip:
+		return "", FileSkipped, nil
+	case input.Error:
+		if !s.Merge {
+			return "", "", scaffolderrors.FileExists(file.Path)
 		}
+		merged, err := s.mergeFile(file)
+		if err != nil {
+			return "", "", err
+		}
+		return merged, FileUpdated, nil
+	default:
+		return file.Contents, FileUpdated, nil
 	}
+}
 
-	f, err := s.GetWriter(file.Path)
-	if err != nil {
+func (s *Scaffold) writeFile(file *model.File, contents string, action FileAction) error {
+	if err := (&FileWriter{Fs: s.Fs}).WriteFile(file.Path, []byte(contents)); err != nil {
 		return err
 	}
-	if c, ok := f.(io.Closer); ok {
-		defer func() {
-			if err := c.Close(); err != nil {
-				log.Fatal(err)
-			}
-		}()
+	s.report(file.Path, action)
+
+	// Files owned by the user are recorded as scaffolded to merge them if they are scaffolded again
+	if file.IfExistsAction != input.Error {
+		return nil
 	}
+	record, err := s.recordsMergeBase(file.Path)
+	if err != nil || !record {
+		return err
+	}
+	return (&FileWriter{Fs: s.Fs}).WriteFile(mergeBasePath(file.Path), []byte(file.Contents))
+}
+
+// recordsMergeBase returns whether the file at path is recorded as scaffolded: the projects have to ask for it,
+// the recorded files are kept up to date anyway
+func (s *Scaffold) recordsMergeBase(path string) (bool, error) {
+	if s.Config == nil || !s.Config.IsV2() {
+		return false, nil
+	}
+	if s.Config.MergeBases {
+		return true, nil
+	}
+	return afero.Exists(s.Fs, mergeBasePath(path))
+}
+
+func (s *Scaffold) report(path string, action FileAction) {
+	if s.Reporter != nil {
+		s.Reporter.ReportFile(path, action)
+	}
+}
+
+// out returns where the messages for the user are printed
+func (s *Scaffold) out() io.Writer {
+	if s.Out == nil {
+		return os.Stdout
+	}
+	return s.Out
+}
 
-	_, err = f.Write([]byte(file.Contents))
+// mergeFile three-way merges the existing file with its new scaffold
+func (s *Scaffold) mergeFile(file *model.File) (string, error) {
+	current, err := afero.ReadFile(s.Fs, file.Path)
+	if err != nil {
+		return "", err
+	}
 
-	return err
+	// Without the version the file was scaffolded from, every difference with the new scaffold would be a conflict
+	base, err := afero.ReadFile(s.Fs, mergeBasePath(file.Path))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%s already exists and can't be merged as the version it was scaffolded from "+
+			"wasn't recorded, run kubebuilder edit --merge-bases to record the files scaffolded next", file.Path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	merged, conflicts := merge3(base, current, []byte(file.Contents))
+	if conflicts != 0 {
+		fmt.Fprintf(s.out(), "Warning: %s has %d merge conflict(s) between the current file and the new scaffold\n",
+			file.Path, conflicts)
+	}
+	return string(merged), nil
+}
+
+// templateOverridePath returns the path where a template replacing the built-in one of the file is looked up
+func (s *Scaffold) templateOverridePath(e input.File) string {
+	t := reflect.TypeOf(e)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	pkg := strings.TrimPrefix(t.PkgPath(), "sigs.k8s.io/kubebuilder/")
+	pkg = strings.TrimPrefix(pkg, "pkg/scaffold/")
+
+	return filepath.Join(s.TemplatesDir, filepath.FromSlash(pkg), t.Name()+".tmpl")
 }
 
 // doTemplate executes the template for a file using the input
-func doTemplate(i input.Input, e input.File) ([]byte, error) {
+func doTemplate(i input.Input, e input.File, w io.Writer) ([]byte, error) {
 	temp, err := newTemplate(e).Parse(i.TemplateBody)
 	if err != nil {
 		return nil, err
@@ -282,7 +517,7 @@ func doTemplate(i input.Input, e input.File) ([]byte, error) {
 	if filepath.Ext(i.Path) == ".go" {
 		b, err = imports.Process(i.Path, b, &options)
 		if err != nil {
-			fmt.Printf("%s\n", out.Bytes())
+			fmt.Fprintf(w, "%s\n", out.Bytes())
 			return nil, err
 		}
 	}
@@ -292,8 +527,9 @@ func doTemplate(i input.Input, e input.File) ([]byte, error) {
 
 // newTemplate a new template with common functions
 func newTemplate(t input.File) *template.Template {
-	return template.New(fmt.Sprintf("%T", t)).Funcs(template.FuncMap{
-		"title": strings.Title,
-		"lower": strings.ToLower,
-	})
+	temp := template.New(fmt.Sprintf("%T", t)).Funcs(templatefuncs.FuncMap())
+	if d, ok := t.(input.HasDelimiters); ok {
+		temp = temp.Delims(d.Delimiters())
+	}
+	return temp
 }
@@ -17,9 +17,174 @@ limitations under the License.
 package scaffold_test
 
 import (
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
 )
 
 var _ = Describe("Scaffold", func() {
+	Context("with a templates directory", func() {
+		var (
+			fs afero.Fs
+			s  *scaffold.Scaffold
+		)
+
+		BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+			s = &scaffold.Scaffold{
+				Fs:                  fs,
+				TemplatesDir:        "templates",
+				BoilerplateOptional: true,
+				ConfigOptional:      true,
+			}
+		})
+
+		It("should use the template from the directory if it exists", func() {
+			Expect(fs.MkdirAll(filepath.Join("templates", "project"), 0700)).To(Succeed())
+			Expect(afero.WriteFile(fs, filepath.Join("templates", "project", "GitIgnore.tmpl"),
+				[]byte("/vendor\n"), 0600)).To(Succeed())
+
+			Expect(s.Execute(&model.Universe{}, input.Options{}, &project.GitIgnore{})).To(Succeed())
+
+			content := scaffoldtest.ReadFile(fs, ".gitignore")
+			Expect(content).To(Equal("/vendor\n"))
+		})
+
+		It("should fall back to the built-in template otherwise", func() {
+			Expect(s.Execute(&model.Universe{}, input.Options{}, &project.GitIgnore{})).To(Succeed())
+
+			content := scaffoldtest.ReadFile(fs, ".gitignore")
+			Expect(content).To(ContainSubstring("# Binaries for programs and plugins"))
+		})
+	})
+
+	Context("with files that already exist", func() {
+		var (
+			fs afero.Fs
+			s  *scaffold.Scaffold
+		)
+
+		BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+			s = &scaffold.Scaffold{
+				Fs:                  fs,
+				BoilerplateOptional: true,
+				ConfigOptional:      true,
+			}
+		})
+
+		It("should skip, fail or overwrite them as their IfExistsAction says", func() {
+			Expect(afero.WriteFile(fs, ".gitignore", []byte("existing\n"), 0600)).To(Succeed())
+			Expect(s.Execute(&model.Universe{}, input.Options{}, &project.GitIgnore{})).To(Succeed())
+			content := scaffoldtest.ReadFile(fs, ".gitignore")
+			Expect(content).To(Equal("existing\n"))
+
+			path := filepath.Join("config", "rbac", "kustomization.yaml")
+			Expect(afero.WriteFile(fs, path, []byte("existing\n"), 0600)).To(Succeed())
+			Expect(s.Execute(&model.Universe{}, input.Options{}, &project.KustomizeRBAC{})).
+				To(MatchError(path + " already exists"))
+			content = scaffoldtest.ReadFile(fs, path)
+			Expect(content).To(Equal("existing\n"))
+
+			Expect(afero.WriteFile(fs, "Gopkg.toml", []byte("existing\n"), 0600)).To(Succeed())
+			Expect(s.Execute(&model.Universe{}, input.Options{}, &project.GopkgToml{})).To(Succeed())
+			content = scaffoldtest.ReadFile(fs, "Gopkg.toml")
+			Expect(content).To(ContainSubstring(project.DefaultGopkgHeader))
+		})
+	})
+
+	Context("with several files", func() {
+		It("should keep the files in the order they were provided", func() {
+			fs := afero.NewMemMapFs()
+			s := &scaffold.Scaffold{Fs: fs, BoilerplateOptional: true, ConfigOptional: true}
+
+			universe := &model.Universe{}
+			Expect(s.Execute(universe, input.Options{},
+				&project.GitIgnore{}, &project.KustomizeRBAC{}, &project.KustomizeManager{},
+				&project.AuthProxyRole{}, &project.AuthProxyRoleBinding{})).To(Succeed())
+
+			paths := make([]string, 0, len(universe.Files))
+			for _, f := range universe.Files {
+				paths = append(paths, f.Path)
+			}
+			Expect(paths).To(Equal([]string{
+				".gitignore",
+				filepath.Join("config", "rbac", "kustomization.yaml"),
+				filepath.Join("config", "manager", "kustomization.yaml"),
+				filepath.Join("config", "rbac", "auth_proxy_role.yaml"),
+				filepath.Join("config", "rbac", "auth_proxy_role_binding.yaml"),
+			}))
+		})
+
+		It("should not write any file if a template fails", func() {
+			fs := afero.NewMemMapFs()
+			s := &scaffold.Scaffold{Fs: fs, TemplatesDir: "templates", BoilerplateOptional: true, ConfigOptional: true}
+			Expect(afero.WriteFile(fs, filepath.Join("templates", "project", "KustomizeRBAC.tmpl"),
+				[]byte("{{ .Unknown }}"), 0600)).To(Succeed())
+
+			Expect(s.Execute(&model.Universe{}, input.Options{},
+				&project.GitIgnore{}, &project.KustomizeRBAC{}, &project.KustomizeManager{})).NotTo(Succeed())
+
+			_, err := fs.Stat(".gitignore")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("with a file that fails to be written", func() {
+		It("should not write any file", func() {
+			fs := afero.NewMemMapFs()
+			s := &scaffold.Scaffold{
+				Fs:                  failingFs{Fs: fs, path: filepath.Join("config", "rbac", "kustomization.yaml")},
+				BoilerplateOptional: true,
+				ConfigOptional:      true,
+			}
+
+			Expect(s.Execute(&model.Universe{}, input.Options{},
+				&project.GitIgnore{}, &project.KustomizeRBAC{})).NotTo(Succeed())
+
+			Expect(afero.Exists(fs, ".gitignore")).To(BeFalse())
+			Expect(afero.Exists(fs, filepath.Join("config", "rbac", "kustomization.yaml"))).To(BeFalse())
+		})
+	})
+
+	Context("with a file that already exists", func() {
+		It("should not write any file", func() {
+			fs := afero.NewMemMapFs()
+			s := &scaffold.Scaffold{Fs: fs, BoilerplateOptional: true, ConfigOptional: true}
+			Expect(afero.WriteFile(fs, filepath.Join("config", "rbac", "kustomization.yaml"),
+				[]byte("resources: []\n"), 0600)).To(Succeed())
+
+			Expect(s.Execute(&model.Universe{}, input.Options{},
+				&project.GitIgnore{}, &project.KustomizeRBAC{})).NotTo(Succeed())
+
+			_, err := fs.Stat(".gitignore")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("with a project in the filesystem", func() {
+		It("should read the project configuration and boilerplate from it", func() {
+			fs := afero.NewMemMapFs()
+			Expect(afero.WriteFile(fs, "PROJECT",
+				[]byte("version: \"2\"\nrepo: example.com/project\n"), 0600)).To(Succeed())
+			Expect(afero.WriteFile(fs, filepath.Join("hack", "boilerplate.go.txt"),
+				[]byte("// Boilerplate\n"), 0600)).To(Succeed())
+
+			universe := &model.Universe{}
+			Expect((&scaffold.Scaffold{Fs: fs}).Execute(universe, input.Options{}, &project.GitIgnore{})).To(Succeed())
 
+			Expect(universe.Config.Repo).To(Equal("example.com/project"))
+			Expect(universe.Boilerplate).To(Equal("// Boilerplate\n"))
+			_, err := fs.Stat(".gitignore")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
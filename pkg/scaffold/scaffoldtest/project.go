@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffoldtest
+
+import (
+	"github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+const (
+	// Domain is the domain of the projects scaffolded for testing
+	Domain = "example.com"
+
+	// Repo is the repository of the projects scaffolded for testing
+	Repo = "example.com/project"
+)
+
+// NewConfig returns the configuration of a version 2 project saved to a new in-memory filesystem
+func NewConfig() *config.Config {
+	c := config.New(config.DefaultPath)
+	c.SetFs(afero.NewMemMapFs())
+	c.Domain = Domain
+	c.Repo = Repo
+	return c
+}
+
+// NewProject initializes a version 2 project in a new in-memory filesystem. configure, if not nil, sets the
+// options of the project before it is initialized.
+func NewProject(configure func(c *config.Config)) (afero.Fs, *config.Config) {
+	c := NewConfig()
+	if configure != nil {
+		configure(c)
+	}
+	gomega.Expect(scaffold.NewInitScaffolder(c, "none", "", nil, "").Scaffold()).To(gomega.Succeed())
+	return c.Fs(), c
+}
+
+// NewV2Scaffold returns a Scaffold writing the files of a version 2 project to a new in-memory filesystem, to
+// test the templates one at a time
+func NewV2Scaffold() (*scaffold.Scaffold, afero.Fs) {
+	c := NewConfig()
+	gomega.Expect(c.Save()).To(gomega.Succeed())
+	return &scaffold.Scaffold{Fs: c.Fs(), BoilerplateOptional: true}, c.Fs()
+}
+
+// ReadFile returns the contents of the file at path
+func ReadFile(fs afero.Fs, path string) string {
+	b, err := afero.ReadFile(fs, path)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	return string(b)
+}
@@ -17,16 +17,14 @@ limitations under the License.
 package scaffoldtest
 
 import (
-	"bytes"
 	"go/build"
-	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 
-	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
+	"github.com/spf13/afero"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -34,11 +32,21 @@ import (
 
 // TestResult is the result of running the scaffolding.
 type TestResult struct {
-	// Actual is the bytes written to a scaffolded file.
-	Actual bytes.Buffer
+	// Fs is the in-memory filesystem where the files are scaffolded
+	Fs afero.Fs
 
 	// Golden is the golden file contents read from the controller-tools/testdata package
 	Golden string
+
+	// path is where the scaffolded file is expected to be written
+	path string
+}
+
+// Actual returns the contents written to the scaffolded file.
+func (r *TestResult) Actual() string {
+	b, err := afero.ReadFile(r.Fs, r.path)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	return string(b)
 }
 
 func getProjectRoot() string {
@@ -66,17 +74,11 @@ func Options() input.Options {
 // NewTestScaffold returns a new Scaffold and TestResult instance for testing
 func NewTestScaffold(writeToPath, goldenPath string) (*scaffold.Scaffold, *TestResult) {
 	projRoot := getProjectRoot()
-	r := &TestResult{}
-	// Setup scaffold
+	r := &TestResult{Fs: afero.NewMemMapFs(), path: writeToPath}
+	// Setup scaffold, the project configuration and boilerplate are read from the testdata directory while the
+	// scaffolded files are only written to memory
 	s := &scaffold.Scaffold{
-		GetWriter: func(path string) (io.Writer, error) {
-			defer ginkgo.GinkgoRecover()
-			gomega.Expect(path).To(gomega.Equal(writeToPath))
-			return &r.Actual, nil
-		},
-		FileExists: func(path string) bool {
-			return path != writeToPath
-		},
+		Fs:         afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(afero.NewOsFs()), r.Fs),
 		ConfigPath: filepath.Join(projRoot, "testdata", "gopath", "src", "project"),
 	}
 	oldGoPath := build.Default.GOPATH
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"io"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+)
+
+// stagedScaffolder runs a scaffolder with the writes to the filesystem of the project staged in memory
+type stagedScaffolder struct {
+	config     *config.Config
+	scaffolder Scaffolder
+	// diff receives the staged changes as a unified diff instead of writing them, if set
+	diff io.Writer
+}
+
+// NewStagedScaffolder returns a Scaffolder that writes the changes of the scaffolder to the project once it
+// succeeded, so that a failure halfway leaves the project untouched
+func NewStagedScaffolder(config *config.Config, scaffolder Scaffolder) Scaffolder {
+	return &stagedScaffolder{
+		config:     config,
+		scaffolder: scaffolder,
+	}
+}
+
+// NewDryRunScaffolder returns a Scaffolder that writes the changes of the scaffolder to the project as a unified
+// diff to w, without modifying the project
+func NewDryRunScaffolder(config *config.Config, scaffolder Scaffolder, w io.Writer) Scaffolder {
+	return &stagedScaffolder{
+		config:     config,
+		scaffolder: scaffolder,
+		diff:       w,
+	}
+}
+
+func (s *stagedScaffolder) Scaffold() error {
+	base := s.config.Fs()
+	fs := NewDryRunFs(base)
+	s.config.SetFs(fs)
+	defer s.config.SetFs(base)
+
+	if err := s.scaffolder.Scaffold(); err != nil {
+		return err
+	}
+
+	if s.diff != nil {
+		return fs.Diff(s.diff)
+	}
+	return fs.Commit()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+// scaffolderFunc is a Scaffolder that calls the function
+type scaffolderFunc func() error
+
+func (f scaffolderFunc) Scaffold() error {
+	return f()
+}
+
+var _ = Describe("StagedScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		c = scaffoldtest.NewConfig()
+		fs = c.Fs()
+		Expect(afero.WriteFile(fs, "main.go", []byte("package main\n"), 0600)).To(Succeed())
+	})
+
+	// writeFiles returns a scaffolder that updates main.go and creates a types file in the filesystem of the
+	// project, before failing with err if not nil
+	writeFiles := func(err error) scaffold.Scaffolder {
+		return scaffolderFunc(func() error {
+			Expect(afero.WriteFile(c.Fs(), "main.go", []byte("package main\n\nfunc main() {}\n"), 0600)).
+				To(Succeed())
+			Expect(c.Fs().MkdirAll("api/v1", 0700)).To(Succeed())
+			Expect(afero.WriteFile(c.Fs(), "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+			return err
+		})
+	}
+
+	It("should write the changes to the project once the scaffolder succeeded", func() {
+		Expect(scaffold.NewStagedScaffolder(c, writeFiles(nil)).Scaffold()).To(Succeed())
+
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(Equal("package main\n\nfunc main() {}\n"))
+		Expect(scaffoldtest.ReadFile(fs, "api/v1/types.go")).To(Equal("package v1\n"))
+		Expect(c.Fs()).To(BeIdenticalTo(fs))
+	})
+
+	It("should leave the project untouched if the scaffolder fails halfway", func() {
+		Expect(scaffold.NewStagedScaffolder(c, writeFiles(errors.New("kustomization.yaml not found"))).Scaffold()).
+			To(MatchError("kustomization.yaml not found"))
+
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(Equal("package main\n"))
+		Expect(afero.Exists(fs, "api/v1/types.go")).To(BeFalse())
+		Expect(c.Fs()).To(BeIdenticalTo(fs))
+	})
+
+	It("should only print the changes as a diff with dry-run", func() {
+		out := &bytes.Buffer{}
+		Expect(scaffold.NewDryRunScaffolder(c, writeFiles(nil), out).Scaffold()).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("--- /dev/null\n+++ b/api/v1/types.go\n"))
+		Expect(out.String()).To(ContainSubstring("--- a/main.go\n+++ b/main.go\n"))
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(Equal("package main\n"))
+		Expect(afero.Exists(fs, "api/v1/types.go")).To(BeFalse())
+	})
+})
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// templateUpdateScaffolder scaffolds the project files again with the templates of this version of kubebuilder,
+// updating the files that were not modified since they were scaffolded and reporting the others for review
+type templateUpdateScaffolder struct {
+	config *config.Config
+	// merge indicates that the modified files should be three-way merged with their new scaffold
+	merge bool
+	// diff is where the changes of the new scaffold of the modified files are written, nil to only list them
+	diff io.Writer
+}
+
+func NewTemplateUpdateScaffolder(config *config.Config, merge bool, diff io.Writer) Scaffolder {
+	return &templateUpdateScaffolder{
+		config: config,
+		merge:  merge,
+		diff:   diff,
+	}
+}
+
+func (s *templateUpdateScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("updating the templates is not supported for project version %v", s.config.Version)
+	}
+
+	fs := s.config.Fs()
+	files, err := (&initScaffolder{config: s.config, boilerplatePath: boilerplatePath}).layoutFiles()
+	if err != nil {
+		return err
+	}
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(fs, boilerplatePath),
+	)
+	if err != nil {
+		return err
+	}
+	sc := &Scaffold{Fs: fs, Reporter: &TextReporter{}}
+	templates, err := sc.render(universe, input.Options{ProjectPath: s.config.Path()}, files...)
+	if err != nil {
+		return err
+	}
+
+	m, err := readManifest(fs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Updating the project files to the templates of kubebuilder %s...\n", KubebuilderVersion)
+	var review []string
+	for _, f := range universe.Files {
+		current, err := afero.ReadFile(fs, f.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		recorded, found := m.get(f.Path)
+
+		var contents string
+		var action FileAction
+		switch {
+		case os.IsNotExist(err) && found:
+			// The files that were removed from the project are not restored
+			continue
+		case os.IsNotExist(err):
+			// The file was added to the scaffold after the project was initialized
+			contents, action = f.Contents, FileCreated
+		case string(current) == f.Contents:
+			m.record(f, templates[f.Path])
+			continue
+		case found && recorded.Hash == hash(f.Contents):
+			// The scaffold didn't change, the file only has the modifications made to it since
+			continue
+		case found && recorded.Hash == hash(string(current)):
+			contents, action = f.Contents, FileUpdated
+		default:
+			merged, ok, err := s.mergeFile(sc, f.Path, f.Contents)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				reason := "its template changed"
+				if !found {
+					reason = "it was scaffolded before the files were recorded"
+				} else if recorded.TemplateHash == hash(templates[f.Path]) {
+					reason = "the project configuration changed"
+				}
+				review = append(review, fmt.Sprintf("%s: %s", f.Path, reason))
+				if err := s.writeDiff(f.Path, current, f.Contents); err != nil {
+					return err
+				}
+				continue
+			}
+			contents, action = merged, FileUpdated
+		}
+
+		if err := sc.writeFile(f, contents, action); err != nil {
+			return err
+		}
+		m.record(f, templates[f.Path])
+	}
+
+	if err := m.write(fs); err != nil {
+		return err
+	}
+
+	if len(review) != 0 {
+		fmt.Println("The next files were modified since they were scaffolded and their new scaffold is different, " +
+			"review the changes with --diff or three-way merge them with --merge:")
+		for _, r := range review {
+			fmt.Printf("  %s\n", r)
+		}
+	}
+	return nil
+}
+
+// mergeFile three-way merges the file with its new scaffold if merging was requested and its scaffolded version
+// was recorded as the base, returning whether it was merged
+func (s *templateUpdateScaffolder) mergeFile(sc *Scaffold, path, contents string) (string, bool, error) {
+	if !s.merge {
+		return "", false, nil
+	}
+	exists, err := afero.Exists(s.config.Fs(), mergeBasePath(path))
+	if err != nil || !exists {
+		return "", false, err
+	}
+
+	merged, err := sc.mergeFile(&model.File{Path: path, Contents: contents})
+	return merged, err == nil, err
+}
+
+// writeDiff writes the changes between the current file and its new scaffold if they were requested
+func (s *templateUpdateScaffolder) writeDiff(path string, current []byte, scaffolded string) error {
+	if s.diff == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintf(s.diff, "--- a/%s\n+++ b/%s\n", path, path); err != nil {
+		return err
+	}
+	return writeHunks(s.diff, splitLines(current), splitLines([]byte(scaffolded)))
+}
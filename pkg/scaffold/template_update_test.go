@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"regexp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("TemplateUpdateScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	// recordScaffold records in the manifest that the file at path was scaffolded with contents
+	recordScaffold := func(path, contents string) {
+		manifestPath := filepath.Join(".kubebuilder", "manifest.yaml")
+		manifest, err := afero.ReadFile(fs, manifestPath)
+		Expect(err).NotTo(HaveOccurred())
+		sum := sha256.Sum256([]byte(contents))
+		entry := regexp.MustCompile("(\n  " + regexp.QuoteMeta(path) + ":\n    hash: )[0-9a-f]+")
+		Expect(entry.Match(manifest)).To(BeTrue())
+		manifest = entry.ReplaceAll(manifest, []byte("${1}"+hex.EncodeToString(sum[:])))
+		Expect(afero.WriteFile(fs, manifestPath, manifest, 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fs, c = scaffoldtest.NewProject(nil)
+	})
+
+	It("should update the files that were not modified and report the others", func() {
+		// The Dockerfile was scaffolded by an older template, the Makefile was modified since
+		Expect(afero.WriteFile(fs, "Dockerfile", []byte("FROM old\n"), 0600)).To(Succeed())
+		recordScaffold("Dockerfile", "FROM old\n")
+		Expect(afero.WriteFile(fs, "Makefile", []byte("all:\n"), 0600)).To(Succeed())
+		recordScaffold("Makefile", "old:\n")
+		leaderElectionRolePath := filepath.Join("config", "rbac", "leader_election_role.yaml")
+		Expect(fs.Remove(leaderElectionRolePath)).To(Succeed())
+
+		diff := &bytes.Buffer{}
+		Expect(scaffold.NewTemplateUpdateScaffolder(c, false, diff).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, "Dockerfile")
+		Expect(content).To(ContainSubstring("FROM golang"))
+		content = scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(Equal("all:\n"))
+		Expect(diff.String()).To(ContainSubstring("--- a/Makefile\n+++ b/Makefile\n"))
+		Expect(diff.String()).To(ContainSubstring("\n-all:\n"))
+		Expect(afero.Exists(fs, leaderElectionRolePath)).To(BeFalse())
+	})
+
+	It("should keep the modified files whose scaffold didn't change", func() {
+		Expect(afero.WriteFile(fs, "Makefile", []byte("all:\n"), 0600)).To(Succeed())
+
+		diff := &bytes.Buffer{}
+		Expect(scaffold.NewTemplateUpdateScaffolder(c, true, diff).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, "Makefile")
+		Expect(content).To(Equal("all:\n"))
+		Expect(diff.String()).To(BeEmpty())
+	})
+})
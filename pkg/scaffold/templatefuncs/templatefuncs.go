@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templatefuncs provides the functions available in the templates of the scaffolds, including the
+// templates that replace the built-in ones, so that they name things consistently. Plugins rendering their own
+// templates can register them with FuncMap.
+package templatefuncs
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/gobuffalo/flect"
+)
+
+// FuncMap returns the functions available in the templates of the scaffolds
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"title":            strings.Title,
+		"lower":            strings.ToLower,
+		"lowerCamel":       LowerCamel,
+		"goName":           GoName,
+		"kebab":            Kebab,
+		"plural":           Plural,
+		"groupPackageName": GroupPackageName,
+		"marker":           Marker,
+		"markerArg":        MarkerArg,
+		"scaffoldMarker":   ScaffoldMarker,
+		"goDuration":       GoDuration,
+	}
+}
+
+// LowerCamel returns the name in lower camel case, e.g. firstMate for FirstMate and httpRoute for HTTPRoute
+func LowerCamel(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// initialisms are the words written in upper case in Go identifiers, as listed by golint
+var initialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true, "dns": true, "eof": true, "guid": true,
+	"html": true, "http": true, "https": true, "id": true, "ip": true, "json": true, "lhs": true, "qps": true,
+	"ram": true, "rhs": true, "rpc": true, "sla": true, "smtp": true, "sql": true, "ssh": true, "tcp": true,
+	"tls": true, "ttl": true, "udp": true, "ui": true, "uid": true, "uuid": true, "uri": true, "url": true,
+	"utf8": true, "vm": true, "xml": true, "xmpp": true, "xsrf": true, "xss": true,
+}
+
+// GoName returns the exported Go identifier of a name, e.g. IPAddresses for ipAddresses, with the initialisms in
+// upper case and without the characters that can't be part of an identifier
+func GoName(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, word)
+		if initialisms[strings.ToLower(word)] {
+			words[i] = strings.ToUpper(word)
+		} else if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// Kebab returns the name in lower case with its words separated by dashes, e.g. first-mate for FirstMate
+func Kebab(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// Plural returns the lowercase plural of a Kind, e.g. firstmates for FirstMate, which is the default resource
+// of the Kind
+func Plural(kind string) string {
+	return flect.Pluralize(strings.ToLower(kind))
+}
+
+// GroupPackageName returns the name that the packages of an API group are imported as, e.g. crewexample for
+// crew-example, followed by the version in the imports
+func GroupPackageName(group string) string {
+	return strings.NewReplacer("-", "", ".", "").Replace(group)
+}
+
+// Marker returns a marker with its arguments, e.g. +kubebuilder:printcolumn:name=Age,type=date.
+// The comment prefix, // or #, is written by the template.
+func Marker(name string, args ...string) string {
+	if len(args) == 0 {
+		return "+" + name
+	}
+	return "+" + name + ":" + strings.Join(args, ",")
+}
+
+// MarkerArg returns an argument of a marker, whose values are separated by semicolons, e.g. verbs=get;list
+func MarkerArg(key string, values ...string) string {
+	return key + "=" + strings.Join(values, ";")
+}
+
+// ScaffoldMarker returns the marker below which the scaffolds insert code, e.g. +kubebuilder:scaffold:imports
+func ScaffoldMarker(name string) string {
+	return Marker("kubebuilder:scaffold:" + name)
+}
+
+// durationUnits are the units of the durations written in Go, from the largest
+var durationUnits = []struct {
+	duration time.Duration
+	name     string
+}{
+	{time.Hour, "time.Hour"},
+	{time.Minute, "time.Minute"},
+	{time.Second, "time.Second"},
+	{time.Millisecond, "time.Millisecond"},
+}
+
+// GoDuration returns the Go expression of a duration in its largest whole unit, e.g. 90*time.Second for 1m30s
+func GoDuration(d time.Duration) string {
+	for _, unit := range durationUnits {
+		if d%unit.duration == 0 {
+			return fmt.Sprintf("%d*%s", d/unit.duration, unit.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
+// splitWords splits a name at its separators (-, _, . and spaces) and case changes, keeping acronyms together
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i == len(runes) || isSeparator(runes[i]) {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+
+		// A word starts at an upper case letter following a lower case letter or a digit, e.g. First|Mate,
+		// or at the last letter of an acronym followed by a lower case letter, e.g. HTTP|Route
+		if i > start && unicode.IsUpper(runes[i]) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	return words
+}
+
+func isSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+}
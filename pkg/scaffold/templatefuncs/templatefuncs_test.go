@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templatefuncs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCasing(t *testing.T) {
+	tests := []struct {
+		name       string
+		lowerCamel string
+		kebab      string
+		goName     string
+	}{
+		{"FirstMate", "firstMate", "first-mate", "FirstMate"},
+		{"Frigate", "frigate", "frigate", "Frigate"},
+		{"HTTPRoute", "httpRoute", "http-route", "HTTPRoute"},
+		{"URL", "url", "url", "URL"},
+		{"CronJobV2", "cronJobV2", "cron-job-v2", "CronJobV2"},
+		{"sea-creatures", "seaCreatures", "sea-creatures", "SeaCreatures"},
+		{"ship.example_group", "shipExampleGroup", "ship-example-group", "ShipExampleGroup"},
+		{"ipAddresses", "ipAddresses", "ip-addresses", "IPAddresses"},
+		{"apiVersion", "apiVersion", "api-version", "APIVersion"},
+	}
+
+	for _, test := range tests {
+		if actual := LowerCamel(test.name); actual != test.lowerCamel {
+			t.Errorf("LowerCamel(%q) = %q, expected %q", test.name, actual, test.lowerCamel)
+		}
+		if actual := Kebab(test.name); actual != test.kebab {
+			t.Errorf("Kebab(%q) = %q, expected %q", test.name, actual, test.kebab)
+		}
+		if actual := GoName(test.name); actual != test.goName {
+			t.Errorf("GoName(%q) = %q, expected %q", test.name, actual, test.goName)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	if actual := Plural("FirstMate"); actual != "firstmates" {
+		t.Errorf("Plural(FirstMate) = %q, expected firstmates", actual)
+	}
+	if actual := Plural("Policy"); actual != "policies" {
+		t.Errorf("Plural(Policy) = %q, expected policies", actual)
+	}
+	if actual := GroupPackageName("sea-creatures.ship"); actual != "seacreaturesship" {
+		t.Errorf("GroupPackageName(sea-creatures.ship) = %q, expected seacreaturesship", actual)
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	tests := []struct {
+		actual   string
+		expected string
+	}{
+		{Marker("kubebuilder:object:root=true"), "+kubebuilder:object:root=true"},
+		{
+			Marker("kubebuilder:rbac", MarkerArg("groups", "apps"), MarkerArg("verbs", "get", "list")),
+			"+kubebuilder:rbac:groups=apps,verbs=get;list",
+		},
+		{ScaffoldMarker("imports"), "+kubebuilder:scaffold:imports"},
+	}
+
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("got marker %q, expected %q", test.actual, test.expected)
+		}
+	}
+}
+
+func TestGoDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{5 * time.Minute, "5*time.Minute"},
+		{90 * time.Second, "90*time.Second"},
+		{2 * time.Hour, "2*time.Hour"},
+		{1500 * time.Millisecond, "1500*time.Millisecond"},
+		{time.Microsecond, "time.Duration(1000)"},
+	}
+
+	for _, test := range tests {
+		if actual := GoDuration(test.duration); actual != test.expected {
+			t.Errorf("GoDuration(%s) = %q, expected %q", test.duration, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// universeScaffolder prints the model of the project, i.e. its configuration, its resources and the files
+// scaffolded by init, for the tools that need to introspect the project without parsing it themselves
+type universeScaffolder struct {
+	config *config.Config
+	// contents indicates that the rendered contents of the files should be printed along with their paths
+	contents bool
+	// out is where the model is printed, defaults to the standard output
+	out io.Writer
+}
+
+func NewUniverseScaffolder(config *config.Config, contents bool, out io.Writer) Scaffolder {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &universeScaffolder{
+		config:   config,
+		contents: contents,
+		out:      out,
+	}
+}
+
+func (s *universeScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("exporting the project model is not supported for project version %v", s.config.Version)
+	}
+
+	fs := s.config.Fs()
+	universe, err := model.NewUniverse(
+		model.WithConfig(&s.config.Config),
+		model.WithBoilerplateFromFs(fs, boilerplatePath),
+		model.WithResources(&s.config.Config),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The files are rendered with the templates of this version of kubebuilder but not written
+	files, err := (&initScaffolder{config: s.config, boilerplatePath: boilerplatePath}).layoutFiles()
+	if err != nil {
+		return err
+	}
+	if _, err := (&Scaffold{Fs: fs}).render(universe, input.Options{ProjectPath: s.config.Path()}, files...); err != nil {
+		return err
+	}
+	if !s.contents {
+		for _, f := range universe.Files {
+			f.Contents = ""
+		}
+	}
+
+	out, err := json.MarshalIndent(universe, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.out, string(out))
+	return err
+}
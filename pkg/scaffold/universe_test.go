@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("UniverseScaffolder", func() {
+	It("should print the model of the project as JSON without writing any file", func() {
+		fs, c := scaffoldtest.NewProject(nil)
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(scaffold.NewAPIScaffolder(c, frigate, true, false, false, nil, "", nil, nil).Scaffold()).To(Succeed())
+		Expect(fs.Remove("Makefile")).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(scaffold.NewUniverseScaffolder(c, false, out).Scaffold()).To(Succeed())
+
+		universe := &model.Universe{}
+		Expect(json.Unmarshal(out.Bytes(), universe)).To(Succeed())
+		Expect(universe.Config.Domain).To(Equal("example.com"))
+		Expect(universe.Resources).To(ConsistOf(&model.Resource{
+			Namespaced:  true,
+			Group:       "ship",
+			Version:     "v1",
+			Kind:        "Frigate",
+			Plural:      "frigates",
+			GoPackage:   "example.com/project/api",
+			GroupDomain: "ship.example.com",
+		}))
+
+		paths := make([]string, 0, len(universe.Files))
+		for _, f := range universe.Files {
+			Expect(f.Contents).To(BeEmpty())
+			paths = append(paths, f.Path)
+		}
+		Expect(paths).To(ContainElement("Makefile"))
+		Expect(afero.Exists(fs, "Makefile")).To(BeFalse())
+	})
+})
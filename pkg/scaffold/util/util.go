@@ -26,36 +26,53 @@ import (
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 )
 
+// coreGroups are the Kubernetes API groups whose types are in the k8s.io/api packages, with their domain
+var coreGroups = map[string]string{
+	"apps":                  "",
+	"admission":             "k8s.io",
+	"admissionregistration": "k8s.io",
+	"auditregistration":     "k8s.io",
+	"apiextensions":         "k8s.io",
+	"authentication":        "k8s.io",
+	"authorization":         "k8s.io",
+	"autoscaling":           "",
+	"batch":                 "",
+	"certificates":          "k8s.io",
+	"coordination":          "k8s.io",
+	"core":                  "",
+	"events":                "k8s.io",
+	"extensions":            "",
+	"imagepolicy":           "k8s.io",
+	"networking":            "k8s.io",
+	"node":                  "k8s.io",
+	"metrics":               "k8s.io",
+	"policy":                "",
+	"rbac.authorization":    "k8s.io",
+	"scheduling":            "k8s.io",
+	"setting":               "k8s.io",
+	"storage":               "k8s.io",
+}
+
+// IsCoreGroup returns true if the group is a Kubernetes API group, whose types are not defined in the project
+func IsCoreGroup(group string) bool {
+	_, found := coreGroups[group]
+	return found
+}
+
 func GetResourceInfo(r *resource.Resource,
 	repo string,
 	domain string,
 	isMultiGroup bool,
 ) (resourcePackage, groupDomain string) {
-	// Use the k8s.io/api package for core resources
-	coreGroups := map[string]string{
-		"apps":                  "",
-		"admission":             "k8s.io",
-		"admissionregistration": "k8s.io",
-		"auditregistration":     "k8s.io",
-		"apiextensions":         "k8s.io",
-		"authentication":        "k8s.io",
-		"authorization":         "k8s.io",
-		"autoscaling":           "",
-		"batch":                 "",
-		"certificates":          "k8s.io",
-		"coordination":          "k8s.io",
-		"core":                  "",
-		"events":                "k8s.io",
-		"extensions":            "",
-		"imagepolicy":           "k8s.io",
-		"networking":            "k8s.io",
-		"node":                  "k8s.io",
-		"metrics":               "k8s.io",
-		"policy":                "",
-		"rbac.authorization":    "k8s.io",
-		"scheduling":            "k8s.io",
-		"setting":               "k8s.io",
-		"storage":               "k8s.io",
+	// Use the provided package for resources whose types are not defined in the project
+	if r.ExternalAPIPath != "" {
+		groupDomain = r.Group
+		if r.ExternalAPIDomain != "" {
+			groupDomain = r.Group + "." + r.ExternalAPIDomain
+		} else if domain := coreGroups[r.Group]; domain != "" {
+			groupDomain = r.Group + "." + domain
+		}
+		return path.Dir(r.ExternalAPIPath), groupDomain
 	}
 
 	var resourcePath string
@@ -70,7 +87,8 @@ func GetResourceInfo(r *resource.Resource,
 			// TODO: support apiextensions.k8s.io and metrics.k8s.io.
 			// apiextensions.k8s.io is in k8s.io/apiextensions-apiserver/pkg/apis/apiextensions
 			// metrics.k8s.io is in k8s.io/metrics/pkg/apis/metrics
-			resourcePackage := path.Join("k8s.io", "api", r.Group)
+			// The package is named after the first label of the group, e.g. k8s.io/api/rbac for rbac.authorization
+			resourcePackage := path.Join("k8s.io", "api", strings.Split(r.Group, ".")[0])
 			groupDomain = r.Group
 			if domain != "" {
 				groupDomain = r.Group + "." + domain
@@ -81,7 +99,14 @@ func GetResourceInfo(r *resource.Resource,
 	}
 
 	if isMultiGroup {
-		return path.Join(repo, "apis", r.Group), r.Group + "." + domain
+		return path.Join(repo, "apis", r.Group), r.QualifiedGroup(domain)
 	}
-	return path.Join(repo, "api"), r.Group + "." + domain
+	return path.Join(repo, "api"), r.QualifiedGroup(domain)
+}
+
+// ModuleRoot returns the path of the root of a module relative to the directory of a project in it, e.g. ../.. for
+// the operators/foo directory
+func ModuleRoot(moduleDir string) string {
+	depth := len(strings.Split(path.Clean(moduleDir), "/"))
+	return strings.TrimSuffix(strings.Repeat("../", depth), "/")
 }
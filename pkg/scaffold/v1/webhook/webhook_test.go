@@ -183,7 +183,7 @@ var _ = Describe("Webhook", func() {
 					It(fmt.Sprintf("should write a file matching the golden file %s", f.file), func() {
 						s, result := scaffoldtest.NewTestScaffold(f.file, f.file)
 						Expect(s.Execute(&model.Universe{}, scaffoldtest.Options(), f.instance)).To(Succeed())
-						Expect(result.Actual.String()).To(Equal(result.Golden), result.Actual.String())
+						Expect(result.Actual()).To(Equal(result.Golden), result.Actual())
 					})
 				})
 			}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &APIs{}
+
+// APIs scaffolds the apis/apis.go file of multigroup projects, which registers all their API versions
+type APIs struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *APIs) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("apis", "apis.go")
+	}
+	f.TemplateBody = apisTemplate
+	return f.Input, nil
+}
+
+const apisTemplate = `{{ .Boilerplate }}
+
+// Package apis contains the API groups of the project. All their versions are added to the scheme of
+// the manager with AddToScheme, so that main.go doesn't need to import them.
+package apis
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AddToSchemes registers the API versions of the project, each of them is appended in
+// its addtoscheme_<group>_<version>.go file
+var AddToSchemes runtime.SchemeBuilder
+
+// AddToScheme adds all the API versions of the project to the scheme
+func AddToScheme(s *runtime.Scheme) error {
+	return AddToSchemes.AddToScheme(s)
+}
+`
+
+var _ input.File = &AddToScheme{}
+
+// AddToScheme scaffolds the apis/addtoscheme_<group>_<version>.go file of multigroup projects, which appends
+// an API version to the ones registered by the apis package
+type AddToScheme struct {
+	input.Input
+
+	// Resource is a resource in the API version
+	Resource *resource.Resource
+
+	// ResourcePackage is the package of the Resource
+	ResourcePackage string
+}
+
+// GetInput implements input.File
+func (f *AddToScheme) GetInput() (input.Input, error) {
+	if f.ResourcePackage == "" {
+		f.ResourcePackage = path.Join(f.Repo, "apis", f.Resource.Group)
+	}
+
+	if f.Path == "" {
+		f.Path = filepath.Join("apis",
+			fmt.Sprintf("addtoscheme_%s_%s.go", f.Resource.GroupImportSafe, f.Resource.Version))
+	}
+	f.TemplateBody = addToSchemeTemplate
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *AddToScheme) Validate() error {
+	return f.Resource.Validate()
+}
+
+const addToSchemeTemplate = `{{ .Boilerplate }}
+
+package apis
+
+import (
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+)
+
+func init() {
+	AddToSchemes = append(AddToSchemes, {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.AddToScheme)
+}
+`
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &APIService{}
+
+// APIService scaffolds the APIService registering the API version of a resource in the Kubernetes API
+type APIService struct {
+	input.Input
+
+	// Resource is the resource whose API version is registered
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *APIService) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, fmt.Sprintf("%s_%s_apiservice.yaml", f.Resource.Group, f.Resource.Version))
+	}
+	f.TemplateBody = apiServiceTemplate
+	// The API version is shared by all the kinds of the group and version
+	f.Input.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+const apiServiceTemplate = `apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: {{ .Resource.Version }}.{{ .Resource.QualifiedGroup .Domain }}
+  annotations:
+    cert-manager.io/inject-ca-from: $(CERTIFICATE_NAMESPACE)/$(CERTIFICATE_NAME)
+spec:
+  group: {{ .Resource.QualifiedGroup .Domain }}
+  version: {{ .Resource.Version }}
+  groupPriorityMinimum: 1000
+  versionPriority: 15
+  service:
+    name: $(SERVICE_NAME)
+    namespace: $(SERVICE_NAMESPACE)
+`
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Certificate{}
+
+// Certificate scaffolds the cert-manager Issuer and Certificate of the serving certificate of the
+// aggregated API server
+type Certificate struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Certificate) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "certificate.yaml")
+	}
+	f.TemplateBody = certificateTemplate
+	return f.Input, nil
+}
+
+const certificateTemplate = `# The serving certificate of the API server is issued by a self-signed issuer,
+# cert-manager injects its CA into the APIServices so that the Kubernetes API
+# server trusts it.
+apiVersion: cert-manager.io/v1alpha2
+kind: Issuer
+metadata:
+  name: selfsigned-issuer
+  namespace: system
+spec:
+  selfSigned: {}
+---
+apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: serving-cert  # this name should match the one in the vars of config/default/kustomization.yaml
+  namespace: system
+spec:
+  # $(SERVICE_NAME) and $(SERVICE_NAMESPACE) will be substituted by kustomize
+  dnsNames:
+  - $(SERVICE_NAME).$(SERVICE_NAMESPACE).svc
+  - $(SERVICE_NAME).$(SERVICE_NAMESPACE).svc.cluster.local
+  issuerRef:
+    kind: Issuer
+    name: selfsigned-issuer
+  secretName: apiserver-cert # this secret will not be prefixed, since it's not managed by kustomize
+`
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Deployment{}
+
+// Deployment scaffolds the namespace and the Deployment of the aggregated API server
+type Deployment struct {
+	input.Input
+
+	// Image is the image of the API server
+	Image string
+
+	// Prefix is the prefix of the namespace, defaults to the name of the project directory
+	Prefix string
+
+	// MemoryStorage is true if the resources are kept in memory instead of etcd
+	MemoryStorage bool
+}
+
+// GetInput implements input.File
+func (f *Deployment) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "deployment.yaml")
+	}
+	prefix, err := defaultPrefix(f.Prefix)
+	if err != nil {
+		return input.Input{}, err
+	}
+	f.Prefix = prefix
+	f.TemplateBody = deploymentTemplate
+	return f.Input, nil
+}
+
+const deploymentTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  labels:
+    control-plane: apiserver
+  name: {{ .Prefix }}-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: apiserver
+  namespace: system
+  labels:
+    control-plane: apiserver
+spec:
+  selector:
+    matchLabels:
+      control-plane: apiserver
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        control-plane: apiserver
+    spec:
+{{- if .MemoryStorage }}
+      # The image is built for the PLATFORMS of the Makefile by
+      # make docker-buildx, keep the architectures in sync with them.
+      nodeSelector:
+        kubernetes.io/os: linux
+      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: kubernetes.io/arch
+                operator: In
+                values:
+                - amd64
+                - arm64
+                - ppc64le
+      # Some providers taint their arm64 nodes so that only the pods with
+      # arm64 images are scheduled on them.
+      tolerations:
+      - key: kubernetes.io/arch
+        operator: Equal
+        value: arm64
+        effect: NoSchedule
+{{- else }}
+      # The etcd image is only built for linux/amd64.
+      nodeSelector:
+        kubernetes.io/os: linux
+        kubernetes.io/arch: amd64
+{{- end }}
+      containers:
+      - command:
+        - /manager
+        args:
+        - --secure-port=9443
+        - --tls-cert-file=/tmp/k8s-apiserver/serving-certs/tls.crt
+        - --tls-private-key-file=/tmp/k8s-apiserver/serving-certs/tls.key
+{{- if not .MemoryStorage }}
+        - --etcd-servers=http://127.0.0.1:2379
+{{- end }}
+        image: {{ .Image }}
+        name: apiserver
+        env:
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+        ports:
+        - containerPort: 9443
+          name: https
+          protocol: TCP
+        livenessProbe:
+          httpGet:
+            path: /livez
+            port: 9443
+            scheme: HTTPS
+          initialDelaySeconds: 15
+          periodSeconds: 20
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 9443
+            scheme: HTTPS
+          initialDelaySeconds: 5
+          periodSeconds: 10
+        resources:
+          limits:
+            cpu: 200m
+            memory: 100Mi
+          requests:
+            cpu: 100m
+            memory: 50Mi
+        volumeMounts:
+        - mountPath: /tmp/k8s-apiserver/serving-certs
+          name: cert
+          readOnly: true
+{{- if not .MemoryStorage }}
+      # The etcd sidecar stores the resources in an emptyDir, they are lost when the pod is deleted.
+      # Use a persistent volume or an external etcd cluster set with --etcd-servers in production.
+      - command:
+        - /usr/local/bin/etcd
+        - --listen-client-urls=http://127.0.0.1:2379
+        - --advertise-client-urls=http://127.0.0.1:2379
+        - --data-dir=/var/lib/etcd
+        image: quay.io/coreos/etcd:v3.4.3
+        name: etcd
+        volumeMounts:
+        - mountPath: /var/lib/etcd
+          name: etcd-data
+{{- end }}
+      terminationGracePeriodSeconds: 10
+      volumes:
+      - name: cert
+        secret:
+          defaultMode: 420
+          secretName: apiserver-cert
+{{- if not .MemoryStorage }}
+      - name: etcd-data
+        emptyDir: {}
+{{- end }}
+`
+
+var _ input.File = &Service{}
+
+// Service scaffolds the Service of the aggregated API server, which the APIServices point to
+type Service struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Service) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "service.yaml")
+	}
+	f.TemplateBody = serviceTemplate
+	return f.Input, nil
+}
+
+const serviceTemplate = `apiVersion: v1
+kind: Service
+metadata:
+  name: apiserver-service
+  namespace: system
+spec:
+  ports:
+  - port: 443
+    targetPort: 9443
+  selector:
+    control-plane: apiserver
+`
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// configDir is the directory of the manifests of the aggregated API server
+var configDir = filepath.Join("config", "apiserver")
+
+// defaultPrefix returns the prefix of the names of the cluster-scoped resources, defaulting to the name of
+// the project directory
+func defaultPrefix(prefix string) (string, error) {
+	if prefix != "" {
+		return prefix, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(filepath.Base(dir)), nil
+}
+
+var _ input.File = &Kustomize{}
+
+// Kustomize scaffolds the Kustomization file for the default overlay of the aggregated API server
+type Kustomize struct {
+	input.Input
+
+	// Prefix is the prefix of the namespace, defaults to the name of the project directory
+	Prefix string
+}
+
+// GetInput implements input.File
+func (f *Kustomize) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "default", "kustomization.yaml")
+	}
+	prefix, err := defaultPrefix(f.Prefix)
+	if err != nil {
+		return input.Input{}, err
+	}
+	f.Prefix = prefix
+	f.TemplateBody = kustomizeTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const kustomizeTemplate = `# Adds namespace to all resources.
+namespace: {{ .Prefix }}-system
+
+# The names of the resources aren't prefixed, as the APIServices have to be named after
+# the version and the group they register. The cluster-scoped resources are named after
+# the project instead.
+
+# Labels to add to all resources and selectors.
+#commonLabels:
+#  someName: someValue
+
+bases:
+- ../apiserver
+
+# The certificate of the API server is issued by cert-manager for its service,
+# and its CA is injected into the APIServices.
+vars:
+- name: CERTIFICATE_NAMESPACE # namespace of the certificate CR
+  objref:
+    kind: Certificate
+    group: cert-manager.io
+    version: v1alpha2
+    name: serving-cert # this name should match the one in certificate.yaml
+  fieldref:
+    fieldpath: metadata.namespace
+- name: CERTIFICATE_NAME
+  objref:
+    kind: Certificate
+    group: cert-manager.io
+    version: v1alpha2
+    name: serving-cert # this name should match the one in certificate.yaml
+- name: SERVICE_NAMESPACE # namespace of the service
+  objref:
+    kind: Service
+    version: v1
+    name: apiserver-service
+  fieldref:
+    fieldpath: metadata.namespace
+- name: SERVICE_NAME
+  objref:
+    kind: Service
+    version: v1
+    name: apiserver-service
+`
+
+const apiServicesScaffoldMarker = "# +kubebuilder:scaffold:apiservices"
+
+var _ input.File = &Kustomization{}
+
+// Kustomization scaffolds the Kustomization file of the manifests of the aggregated API server
+type Kustomization struct {
+	input.Input
+
+	// Resource is the resource whose API version is registered by the APIService added by Update
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *Kustomization) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "kustomization.yaml")
+	}
+	f.TemplateBody = kustomizationTemplate
+	return f.Input, nil
+}
+
+// Update adds the APIService of the API version of the resource
+func (f *Kustomization) Update(fs afero.Fs) error {
+	markerAndValues, err := f.Fragments()
+	if err != nil {
+		return err
+	}
+
+	return inject.Update(fs, f.Path, markerAndValues)
+}
+
+// Fragments returns the entries inserted by Update below each marker of the kustomization file
+func (f *Kustomization) Fragments() (map[string][]string, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "kustomization.yaml")
+	}
+
+	apiService := &APIService{Resource: f.Resource}
+	if _, err := apiService.GetInput(); err != nil {
+		return nil, err
+	}
+
+	return map[string][]string{
+		apiServicesScaffoldMarker: {fmt.Sprintf("- %s\n", filepath.Base(apiService.Path))},
+	}, nil
+}
+
+var kustomizationTemplate = fmt.Sprintf(`resources:
+- deployment.yaml
+- service.yaml
+- certificate.yaml
+- role.yaml
+- role_binding.yaml
+- auth_delegator_role_binding.yaml
+# The APIServices register the API versions served by the API server in the Kubernetes API.
+%s
+
+configurations:
+- kustomizeconfig.yaml
+`, apiServicesScaffoldMarker)
+
+var _ input.File = &KustomizeConfig{}
+
+// KustomizeConfig scaffolds the configuration teaching kustomize where the variables of the default
+// overlay are used
+type KustomizeConfig struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *KustomizeConfig) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "kustomizeconfig.yaml")
+	}
+	f.TemplateBody = kustomizeConfigTemplate
+	return f.Input, nil
+}
+
+// nolint:lll
+const kustomizeConfigTemplate = `# This configuration is for teaching kustomize how to update name ref and var substitution
+nameReference:
+- kind: Issuer
+  group: cert-manager.io
+  fieldSpecs:
+  - kind: Certificate
+    group: cert-manager.io
+    path: spec/issuerRef/name
+
+varReference:
+- kind: Certificate
+  group: cert-manager.io
+  path: spec/dnsNames
+- kind: APIService
+  group: apiregistration.k8s.io
+  path: metadata/annotations
+- kind: APIService
+  group: apiregistration.k8s.io
+  path: spec/service/name
+- kind: APIService
+  group: apiregistration.k8s.io
+  path: spec/service/namespace
+`
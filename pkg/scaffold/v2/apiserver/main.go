@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+const (
+	importsScaffoldMarker   = "// +kubebuilder:scaffold:imports"
+	schemeScaffoldMarker    = "// +kubebuilder:scaffold:scheme"
+	resourcesScaffoldMarker = "// +kubebuilder:scaffold:resources"
+)
+
+var _ input.File = &Main{}
+
+// Main scaffolds a main.go to run the aggregated API server
+type Main struct {
+	input.Input
+
+	// MemoryStorage is true if the resources are kept in memory instead of etcd
+	MemoryStorage bool
+}
+
+// GetInput implements input.File
+func (f *Main) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = "main.go"
+	}
+	f.TemplateBody = mainTemplate
+	return f.Input, nil
+}
+
+// Update registers the API version of the resource in the scheme and serves the resource
+func (f *Main) Update(fs afero.Fs, c *config.Config, r *resource.Resource) error {
+	markerAndValues := f.Fragments(c, r)
+	return inject.Update(fs, f.Path, markerAndValues)
+}
+
+// Fragments returns the code fragments inserted by Update below each marker of main.go
+func (f *Main) Fragments(c *config.Config, r *resource.Resource) map[string][]string {
+	if f.Path == "" {
+		f.Path = "main.go"
+	}
+
+	resPkg, _ := util.GetResourceInfo(r, c.Repo, c.Domain, c.MultiGroup)
+	alias := r.GroupImportSafe + r.Version
+
+	apiImport := fmt.Sprintf(`%s "%s/%s"
+`, alias, resPkg, r.Version)
+	addScheme := fmt.Sprintf(`_ = %s.AddToScheme(scheme)
+`, alias)
+	served := fmt.Sprintf(`{
+			GroupVersionResource: %[1]s.GroupVersion.WithResource("%[2]s"),
+			NamespaceScoped: %[3]t,
+			New: func() runtime.Object { return &%[1]s.%[4]s{} },
+			NewList: func() runtime.Object { return &%[1]s.%[4]sList{} },
+		},
+`, alias, r.Plural(), r.Namespaced, r.Kind)
+
+	return map[string][]string{
+		importsScaffoldMarker:   {apiImport},
+		schemeScaffoldMarker:    {addScheme},
+		resourcesScaffoldMarker: {served},
+	}
+}
+
+var mainTemplate = fmt.Sprintf(`{{ .Boilerplate }}
+
+package main
+
+import (
+	"flag"
+	"net"
+	"os"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/klog"
+
+	"{{ .Repo }}/registry"
+	%s
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+
+	// resources are the resources served by the API server
+	resources = []registry.Resource{
+		%s
+	}
+)
+
+func init() {
+	// The generic API server expects the options and the unversioned types in the core group
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+	scheme.AddUnversionedTypes(schema.GroupVersion{Version: "v1"},
+		&metav1.Status{}, &metav1.APIVersions{}, &metav1.APIGroupList{}, &metav1.APIGroup{}, &metav1.APIResourceList{})
+
+	%s
+
+	// The API server converts the objects to the internal version of their group, the objects are
+	// served as is by registering their types as the internal version, so a Kind has a single version
+	for _, r := range resources {
+		internalVersion := schema.GroupVersion{Group: r.GroupVersionResource.Group, Version: runtime.APIVersionInternal}
+		scheme.AddKnownTypes(internalVersion, r.New(), r.NewList())
+	}
+}
+
+func main() {
+	options := genericoptions.NewRecommendedOptions("/registry/{{ .Domain }}",
+		codecs.LegacyCodec(scheme.PrioritizedVersionsAllGroups()...),
+		genericoptions.NewProcessInfo("apiserver", os.Getenv("POD_NAMESPACE")))
+{{- if .MemoryStorage }}
+	// The resources are kept in memory
+	options.Etcd = nil
+{{- end }}
+	// The probes of the kubelet don't need to be authorized by the Kubernetes API server
+	options.Authorization.WithAlwaysAllowPaths("/healthz", "/readyz", "/livez")
+
+	options.AddFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	if err := run(options, genericapiserver.SetupSignalHandler()); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// run serves the resources until stopCh is closed
+func run(options *genericoptions.RecommendedOptions, stopCh <-chan struct{}) error {
+	// A self-signed certificate is generated if none is set with --tls-cert-file and --tls-private-key-file
+	if err := options.SecureServing.MaybeDefaultWithSelfSignedCerts("localhost", nil,
+		[]net.IP{net.ParseIP("127.0.0.1")}); err != nil {
+		return err
+	}
+	if errs := options.Validate(); len(errs) != 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	config := genericapiserver.NewRecommendedConfig(codecs)
+	if err := options.ApplyTo(config); err != nil {
+		return err
+	}
+
+	server, err := config.Complete().New("apiserver", genericapiserver.NewEmptyDelegate())
+	if err != nil {
+		return err
+	}
+
+	// The resources are grouped by API group and version to be installed
+	apiGroups := map[string]*genericapiserver.APIGroupInfo{}
+	for _, r := range resources {
+		gvr := r.GroupVersionResource
+		if _, found := apiGroups[gvr.Group]; !found {
+			apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(gvr.Group, scheme,
+				runtime.NewParameterCodec(scheme), codecs)
+			apiGroups[gvr.Group] = &apiGroupInfo
+		}
+{{ if .MemoryStorage }}
+		storage := registry.NewStorage(r)
+{{- else }}
+		storage, err := registry.NewStorage(scheme, config.RESTOptionsGetter, r)
+		if err != nil {
+			return err
+		}
+{{- end }}
+		versionedStorage := apiGroups[gvr.Group].VersionedResourcesStorageMap
+		if versionedStorage[gvr.Version] == nil {
+			versionedStorage[gvr.Version] = map[string]rest.Storage{}
+		}
+		versionedStorage[gvr.Version][gvr.Resource] = storage
+	}
+	for _, apiGroupInfo := range apiGroups {
+		if err := server.InstallAPIGroup(apiGroupInfo); err != nil {
+			return err
+		}
+	}
+
+	return server.PrepareRun().Run(stopCh)
+}
+`, importsScaffoldMarker, resourcesScaffoldMarker, schemeScaffoldMarker)
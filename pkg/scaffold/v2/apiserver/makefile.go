@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+var _ input.File = &Makefile{}
+
+// Makefile scaffolds the Makefile of the aggregated API server
+type Makefile struct {
+	input.Input
+
+	// Image is the image of the API server
+	Image string
+
+	// ControllerToolsVersion is the version of controller-gen, which generates the deepcopy functions
+	ControllerToolsVersion string
+
+	// MemoryStorage is true if the resources are kept in memory instead of etcd
+	MemoryStorage bool
+
+	// ModuleDir is the directory of the project in the module of a shared go.mod, e.g. operators/foo, whose root
+	// is the build context of the image. Empty if the project has its own go.mod.
+	ModuleDir string
+
+	// ModuleRoot is the path of the root of the module relative to the project, e.g. ../..
+	ModuleRoot string
+}
+
+// GetInput implements input.File
+func (f *Makefile) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = "Makefile"
+	}
+	if f.Image == "" {
+		f.Image = "controller:latest"
+	}
+	if f.ModuleDir != "" {
+		f.ModuleRoot = util.ModuleRoot(f.ModuleDir)
+	}
+	f.TemplateBody = makefileTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const makefileTemplate = `
+# Image URL to use all building/pushing image targets
+IMG ?= {{ .Image }}
+
+# Kubeconfig of the Kubernetes API server the API server delegates the authentication and the authorization to
+KUBECONFIG ?= $(HOME)/.kube/config
+
+# Get the currently used golang install path (in GOPATH/bin, unless GOBIN is set)
+ifeq (,$(shell go env GOBIN))
+GOBIN=$(shell go env GOPATH)/bin
+else
+GOBIN=$(shell go env GOBIN)
+endif
+
+all: manager
+
+# Run tests
+test: generate fmt vet
+	go test ./... -coverprofile cover.out
+
+# Build the API server binary
+manager: generate fmt vet
+	go build -o bin/manager main.go
+
+# Run against the configured Kubernetes cluster in $(KUBECONFIG)
+{{- if not .MemoryStorage }}, storing the resources in the etcd at 127.0.0.1:2379
+{{- end }}
+run: generate fmt vet
+	go run ./main.go --secure-port=9443 --kubeconfig=$(KUBECONFIG) \
+		--authentication-kubeconfig=$(KUBECONFIG) --authorization-kubeconfig=$(KUBECONFIG)
+{{- if not .MemoryStorage }} \
+		--etcd-servers=http://127.0.0.1:2379
+{{- end }}
+
+# Deploy the API server in the configured Kubernetes cluster in ~/.kube/config, requires cert-manager
+deploy:
+	cd config/apiserver && kustomize edit set image controller=${IMG}
+	kustomize build config/default | kubectl apply -f -
+
+# Run go fmt against code
+fmt:
+	go fmt ./...
+
+# Run go vet against code
+vet:
+	go vet ./...
+
+# Generate code
+generate: controller-gen
+	$(CONTROLLER_GEN) object:headerFile={{printf "%q" .BoilerplatePath}} paths="./..."
+
+# Check that the generated code is up to date, e.g. in CI
+verify-generate: generate
+	@if [ -n "$$(git status --porcelain --untracked-files=all -- '*zz_generated.*.go')" ]; then \
+		echo "The generated code is out of date, run make generate and commit it:"; \
+		git status --porcelain --untracked-files=all -- '*zz_generated.*.go'; \
+		exit 1; \
+	fi
+
+# Build the docker image
+docker-build: test
+	docker build {{ if .ModuleDir }}-f Dockerfile -t ${IMG} {{ .ModuleRoot }}{{ else }}. -t ${IMG}{{ end }}
+
+# Push the docker image
+docker-push:
+	docker push ${IMG}
+
+# find or download controller-gen
+# download controller-gen if necessary
+controller-gen:
+ifeq (, $(shell which controller-gen))
+	@{ \
+	set -e ;\
+	CONTROLLER_GEN_TMP_DIR=$$(mktemp -d) ;\
+	cd $$CONTROLLER_GEN_TMP_DIR ;\
+	go mod init tmp ;\
+	go get sigs.k8s.io/controller-tools/cmd/controller-gen@{{.ControllerToolsVersion}} ;\
+	rm -rf $$CONTROLLER_GEN_TMP_DIR ;\
+	}
+CONTROLLER_GEN=$(GOBIN)/controller-gen
+else
+CONTROLLER_GEN=$(shell which controller-gen)
+endif
+`
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Role{}
+
+// Role scaffolds the ClusterRole of the aggregated API server
+type Role struct {
+	input.Input
+
+	// Prefix is the prefix of the name of the ClusterRole, defaults to the name of the project directory
+	Prefix string
+}
+
+// GetInput implements input.File
+func (f *Role) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "role.yaml")
+	}
+	prefix, err := defaultPrefix(f.Prefix)
+	if err != nil {
+		return input.Input{}, err
+	}
+	f.Prefix = prefix
+	f.TemplateBody = roleTemplate
+	return f.Input, nil
+}
+
+const roleTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ .Prefix }}-apiserver-role
+rules:
+# The client CA of the requests proxied by the Kubernetes API server is read from the
+# extension-apiserver-authentication ConfigMap of the kube-system namespace. The
+# permission is granted by a ClusterRole, as a Role would be moved to the namespace
+# of the project by kustomize.
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  resourceNames:
+  - extension-apiserver-authentication
+  verbs:
+  - get
+  - list
+  - watch
+# The admission plugins watch the namespaces and the webhook configurations.
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - admissionregistration.k8s.io
+  resources:
+  - mutatingwebhookconfigurations
+  - validatingwebhookconfigurations
+  verbs:
+  - get
+  - list
+  - watch
+`
+
+var _ input.File = &RoleBinding{}
+
+// RoleBinding scaffolds the ClusterRoleBinding granting the ClusterRole of the aggregated API server
+type RoleBinding struct {
+	input.Input
+
+	// Prefix is the prefix of the names of the ClusterRoleBinding and of the ClusterRole, defaults to the name
+	// of the project directory
+	Prefix string
+}
+
+// GetInput implements input.File
+func (f *RoleBinding) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "role_binding.yaml")
+	}
+	prefix, err := defaultPrefix(f.Prefix)
+	if err != nil {
+		return input.Input{}, err
+	}
+	f.Prefix = prefix
+	f.TemplateBody = roleBindingTemplate
+	return f.Input, nil
+}
+
+const roleBindingTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{ .Prefix }}-apiserver-rolebinding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: {{ .Prefix }}-apiserver-role
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: system
+`
+
+var _ input.File = &AuthDelegatorRoleBinding{}
+
+// AuthDelegatorRoleBinding scaffolds the ClusterRoleBinding allowing the aggregated API server to delegate
+// the authentication and the authorization of the requests to the Kubernetes API server
+type AuthDelegatorRoleBinding struct {
+	input.Input
+
+	// Prefix is the prefix of the name of the ClusterRoleBinding, defaults to the name of the project directory
+	Prefix string
+}
+
+// GetInput implements input.File
+func (f *AuthDelegatorRoleBinding) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(configDir, "auth_delegator_role_binding.yaml")
+	}
+	prefix, err := defaultPrefix(f.Prefix)
+	if err != nil {
+		return input.Input{}, err
+	}
+	f.Prefix = prefix
+	f.TemplateBody = authDelegatorRoleBindingTemplate
+	return f.Input, nil
+}
+
+const authDelegatorRoleBindingTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{ .Prefix }}-apiserver-auth-delegator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: system
+`
@@ -0,0 +1,407 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Registry{}
+
+// Registry scaffolds the storage of the resources served by the aggregated API server
+type Registry struct {
+	input.Input
+
+	// MemoryStorage is true if the resources are kept in memory instead of etcd
+	MemoryStorage bool
+}
+
+// GetInput implements input.File
+func (f *Registry) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("registry", "registry.go")
+	}
+	f.TemplateBody = registryTemplate
+	return f.Input, nil
+}
+
+// nolint:lll
+const registryTemplate = `{{ .Boilerplate }}
+
+package registry
+
+import (
+	"context"
+{{- if .MemoryStorage }}
+	"fmt"
+	"strconv"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+{{- else }}
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+{{- end }}
+)
+
+// Resource describes a resource served by the API server
+type Resource struct {
+	// GroupVersionResource is the API group, the version and the plural name of the resource
+	GroupVersionResource schema.GroupVersionResource
+	// NamespaceScoped is true if the objects of the resource belong to a namespace
+	NamespaceScoped bool
+	// New returns an empty object of the resource
+	New func() runtime.Object
+	// NewList returns an empty list of objects of the resource
+	NewList func() runtime.Object
+}
+{{ if .MemoryStorage }}
+// NewStorage returns the storage of the resource, keeping its objects in memory. The objects are lost
+// when the API server restarts and are not shared between its replicas.
+func NewStorage(r Resource) rest.Storage {
+	return &memoryStorage{
+		TableConvertor: rest.NewDefaultTableConvertor(r.GroupVersionResource.GroupResource()),
+		resource:       r,
+		objects:        map[string]runtime.Object{},
+		broadcaster:    watch.NewBroadcaster(100, watch.WaitIfChannelFull),
+	}
+}
+
+// memoryStorage stores the objects of a resource in a map keyed by their namespace and name
+type memoryStorage struct {
+	rest.TableConvertor
+
+	resource        Resource
+	lock            sync.RWMutex
+	objects         map[string]runtime.Object
+	resourceVersion uint64
+	broadcaster     *watch.Broadcaster
+}
+
+var _ rest.StandardStorage = &memoryStorage{}
+var _ rest.Scoper = &memoryStorage{}
+
+// New implements rest.Storage
+func (s *memoryStorage) New() runtime.Object {
+	return s.resource.New()
+}
+
+// NewList implements rest.Lister
+func (s *memoryStorage) NewList() runtime.Object {
+	return s.resource.NewList()
+}
+
+// NamespaceScoped implements rest.Scoper
+func (s *memoryStorage) NamespaceScoped() bool {
+	return s.resource.NamespaceScoped
+}
+
+// Get implements rest.Getter
+func (s *memoryStorage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	obj, found := s.objects[s.key(ctx, name)]
+	if !found {
+		return nil, apierrors.NewNotFound(s.resource.GroupVersionResource.GroupResource(), name)
+	}
+	return obj.DeepCopyObject(), nil
+}
+
+// List implements rest.Lister
+func (s *memoryStorage) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	namespace := genericapirequest.NamespaceValue(ctx)
+	items := make([]runtime.Object, 0, len(s.objects))
+	for _, obj := range s.objects {
+		if s.matches(obj, namespace, options) {
+			items = append(items, obj.DeepCopyObject())
+		}
+	}
+
+	list := s.resource.NewList()
+	if err := meta.SetList(list, items); err != nil {
+		return nil, err
+	}
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return nil, err
+	}
+	listAccessor.SetResourceVersion(strconv.FormatUint(s.resourceVersion, 10))
+	return list, nil
+}
+
+// Create implements rest.Creater
+func (s *memoryStorage) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc,
+	options *metav1.CreateOptions) (runtime.Object, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if accessor.GetName() == "" && accessor.GetGenerateName() != "" {
+		accessor.SetName(names.SimpleNameGenerator.GenerateName(accessor.GetGenerateName()))
+	}
+	if s.resource.NamespaceScoped {
+		accessor.SetNamespace(genericapirequest.NamespaceValue(ctx))
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := s.key(ctx, accessor.GetName())
+	if _, found := s.objects[key]; found {
+		return nil, apierrors.NewAlreadyExists(s.resource.GroupVersionResource.GroupResource(), accessor.GetName())
+	}
+	if len(options.DryRun) != 0 {
+		return obj, nil
+	}
+	accessor.SetUID(uuid.NewUUID())
+	accessor.SetCreationTimestamp(metav1.Now())
+	s.store(key, obj, watch.Added)
+	return obj.DeepCopyObject(), nil
+}
+
+// Update implements rest.Updater
+func (s *memoryStorage) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo,
+	createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc,
+	forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := s.key(ctx, name)
+	existing, found := s.objects[key]
+	if !found {
+		return nil, false, apierrors.NewNotFound(s.resource.GroupVersionResource.GroupResource(), name)
+	}
+	obj, err := objInfo.UpdatedObject(ctx, existing.DeepCopyObject())
+	if err != nil {
+		return nil, false, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	existingAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	// Updates based on an outdated version of the object are rejected
+	if resourceVersion := accessor.GetResourceVersion(); resourceVersion != "" &&
+		resourceVersion != existingAccessor.GetResourceVersion() {
+		return nil, false, apierrors.NewConflict(s.resource.GroupVersionResource.GroupResource(), name,
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+	if updateValidation != nil {
+		if err := updateValidation(ctx, obj, existing); err != nil {
+			return nil, false, err
+		}
+	}
+	if len(options.DryRun) != 0 {
+		return obj, false, nil
+	}
+	// The fields set on creation can't be changed
+	accessor.SetUID(existingAccessor.GetUID())
+	accessor.SetCreationTimestamp(existingAccessor.GetCreationTimestamp())
+	s.store(key, obj, watch.Modified)
+	return obj.DeepCopyObject(), false, nil
+}
+
+// Delete implements rest.GracefulDeleter
+func (s *memoryStorage) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc,
+	options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := s.key(ctx, name)
+	obj, found := s.objects[key]
+	if !found {
+		return nil, false, apierrors.NewNotFound(s.resource.GroupVersionResource.GroupResource(), name)
+	}
+	if deleteValidation != nil {
+		if err := deleteValidation(ctx, obj); err != nil {
+			return nil, false, err
+		}
+	}
+	if len(options.DryRun) != 0 {
+		return obj, true, nil
+	}
+	delete(s.objects, key)
+	s.broadcaster.Action(watch.Deleted, obj)
+	return obj, true, nil
+}
+
+// DeleteCollection implements rest.CollectionDeleter
+func (s *memoryStorage) DeleteCollection(ctx context.Context, deleteValidation rest.ValidateObjectFunc,
+	options *metav1.DeleteOptions, listOptions *metainternalversion.ListOptions) (runtime.Object, error) {
+	list, err := s.List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := s.Delete(ctx, accessor.GetName(), deleteValidation, options); err != nil &&
+			!apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Watch implements rest.Watcher, only the changes made after the watch started are sent
+func (s *memoryStorage) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+	namespace := genericapirequest.NamespaceValue(ctx)
+	return watch.Filter(s.broadcaster.Watch(), func(in watch.Event) (watch.Event, bool) {
+		return in, s.matches(in.Object, namespace, options)
+	}), nil
+}
+
+// store saves the object with a new resource version and notifies the watchers
+func (s *memoryStorage) store(key string, obj runtime.Object, eventType watch.EventType) {
+	s.resourceVersion++
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetResourceVersion(strconv.FormatUint(s.resourceVersion, 10))
+	}
+	s.objects[key] = obj.DeepCopyObject()
+	s.broadcaster.Action(eventType, obj.DeepCopyObject())
+}
+
+// key returns the key of the object with the provided name in the namespace of the request
+func (s *memoryStorage) key(ctx context.Context, name string) string {
+	if s.resource.NamespaceScoped {
+		return genericapirequest.NamespaceValue(ctx) + "/" + name
+	}
+	return name
+}
+
+// matches returns true if the object belongs to the namespace and matches the label selector of the options
+func (s *memoryStorage) matches(obj runtime.Object, namespace string, options *metainternalversion.ListOptions) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	if namespace != "" && accessor.GetNamespace() != namespace {
+		return false
+	}
+	if options == nil || options.LabelSelector == nil {
+		return true
+	}
+	return options.LabelSelector.Matches(labels.Set(accessor.GetLabels()))
+}
+{{- else }}
+// NewStorage returns the storage of the resource, persisting its objects in etcd
+func NewStorage(scheme *runtime.Scheme, optsGetter generic.RESTOptionsGetter, r Resource) (rest.Storage, error) {
+	s := strategy{
+		ObjectTyper:     scheme,
+		NameGenerator:   names.SimpleNameGenerator,
+		namespaceScoped: r.NamespaceScoped,
+	}
+	store := &genericregistry.Store{
+		NewFunc:                  r.New,
+		NewListFunc:              r.NewList,
+		DefaultQualifiedResource: r.GroupVersionResource.GroupResource(),
+		CreateStrategy:           s,
+		UpdateStrategy:           s,
+		DeleteStrategy:           s,
+		TableConvertor:           rest.NewDefaultTableConvertor(r.GroupVersionResource.GroupResource()),
+	}
+	if err := store.CompleteWithOptions(&generic.StoreOptions{RESTOptions: optsGetter}); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// strategy is the behavior of the storage shared by all the resources, write a strategy for a resource
+// to validate its objects or to set some of their fields when they are created or updated
+type strategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+
+	namespaceScoped bool
+}
+
+var _ rest.RESTCreateStrategy = strategy{}
+var _ rest.RESTUpdateStrategy = strategy{}
+var _ rest.RESTDeleteStrategy = strategy{}
+
+// NamespaceScoped implements rest.RESTCreateStrategy
+func (s strategy) NamespaceScoped() bool {
+	return s.namespaceScoped
+}
+
+// PrepareForCreate implements rest.RESTCreateStrategy
+func (strategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {}
+
+// PrepareForUpdate implements rest.RESTUpdateStrategy
+func (strategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+
+// Validate implements rest.RESTCreateStrategy
+func (strategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return nil
+}
+
+// ValidateUpdate implements rest.RESTUpdateStrategy
+func (strategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return nil
+}
+
+// Canonicalize implements rest.RESTCreateStrategy
+func (strategy) Canonicalize(obj runtime.Object) {}
+
+// AllowCreateOnUpdate implements rest.RESTUpdateStrategy
+func (strategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// AllowUnconditionalUpdate implements rest.RESTUpdateStrategy
+func (strategy) AllowUnconditionalUpdate() bool {
+	return true
+}
+{{- end }}
+`
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &ApplyConfiguration{}
+
+// ApplyConfiguration scaffolds the api/<version>/<kind>_applyconfiguration.go file with the apply configuration of a
+// Kind, which is patched with server-side apply
+type ApplyConfiguration struct {
+	input.Input
+
+	// Resource is the resource to scaffold the apply configuration for
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *ApplyConfiguration) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		fileName := fmt.Sprintf("%s_applyconfiguration.go", strings.ToLower(f.Resource.Kind))
+		if f.MultiGroup {
+			f.Path = filepath.Join("apis", f.Resource.Group, f.Resource.Version, fileName)
+		} else {
+			f.Path = filepath.Join("api", f.Resource.Version, fileName)
+		}
+	}
+	f.TemplateBody = applyConfigurationTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *ApplyConfiguration) Validate() error {
+	return f.Resource.Validate()
+}
+
+// nolint:lll
+const applyConfigurationTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: keep the apply configurations in sync with the fields of {{.Resource.Kind}}Spec.
+
+// {{.Resource.Kind}}ApplyConfiguration is the configuration of a {{.Resource.Kind}} patched with server-side apply.
+// The fields of its spec are pointers so that only the fields that are set are sent, and owned by the field manager
+// of the patch.
+// +kubebuilder:object:generate=false
+type {{.Resource.Kind}}ApplyConfiguration struct {
+	// The type and object meta are not embedded, as controller-gen generates CRDs for the types that embed them
+	APIVersion string            ` + "`" + `json:"apiVersion"` + "`" + `
+	Kind       string            ` + "`" + `json:"kind"` + "`" + `
+	ObjectMeta metav1.ObjectMeta ` + "`" + `json:"metadata"` + "`" + `
+
+	Spec *{{.Resource.Kind}}SpecApplyConfiguration ` + "`" + `json:"spec,omitempty"` + "`" + `
+}
+
+// {{.Resource.Kind}}SpecApplyConfiguration is the apply configuration of a {{.Resource.Kind}}Spec
+// +kubebuilder:object:generate=false
+type {{.Resource.Kind}}SpecApplyConfiguration struct {
+	Foo *string ` + "`" + `json:"foo,omitempty"` + "`" + `
+{{- if eq .Resource.DefaultsMode "markers" }}
+	Replicas *int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+{{- end }}
+{{- if .Resource.Suspend }}
+	Suspend *bool ` + "`" + `json:"suspend,omitempty"` + "`" + `
+{{- end }}
+}
+
+{{ if .Resource.Namespaced -}}
+// Apply{{.Resource.Kind}} returns the apply configuration of the {{.Resource.Kind}} with the given name and namespace
+func Apply{{.Resource.Kind}}(name, namespace string) *{{.Resource.Kind}}ApplyConfiguration {
+{{- else -}}
+// Apply{{.Resource.Kind}} returns the apply configuration of the {{.Resource.Kind}} with the given name
+func Apply{{.Resource.Kind}}(name string) *{{.Resource.Kind}}ApplyConfiguration {
+{{- end }}
+	return &{{.Resource.Kind}}ApplyConfiguration{
+		APIVersion: GroupVersion.String(),
+		Kind:       "{{.Resource.Kind}}",
+{{- if .Resource.Namespaced }}
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+{{- else }}
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+{{- end }}
+	}
+}
+
+// WithSpec sets the spec of the {{.Resource.Kind}}
+func (b *{{.Resource.Kind}}ApplyConfiguration) WithSpec(value *{{.Resource.Kind}}SpecApplyConfiguration) *{{.Resource.Kind}}ApplyConfiguration {
+	b.Spec = value
+	return b
+}
+
+// ToUnstructured returns the object to patch with client.Apply, e.g.
+// r.Patch(ctx, obj, client.Apply, client.FieldOwner("{{ .Resource.Kind | lower }}-controller"), client.ForceOwnership)
+func (b *{{.Resource.Kind}}ApplyConfiguration) ToUnstructured() (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(b)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// Apply{{.Resource.Kind}}Spec returns an empty apply configuration of a {{.Resource.Kind}}Spec
+func Apply{{.Resource.Kind}}Spec() *{{.Resource.Kind}}SpecApplyConfiguration {
+	return &{{.Resource.Kind}}SpecApplyConfiguration{}
+}
+
+// WithFoo sets the foo field of the {{.Resource.Kind}}Spec
+func (b *{{.Resource.Kind}}SpecApplyConfiguration) WithFoo(value string) *{{.Resource.Kind}}SpecApplyConfiguration {
+	b.Foo = &value
+	return b
+}
+{{- if eq .Resource.DefaultsMode "markers" }}
+
+// WithReplicas sets the replicas field of the {{.Resource.Kind}}Spec
+func (b *{{.Resource.Kind}}SpecApplyConfiguration) WithReplicas(value int32) *{{.Resource.Kind}}SpecApplyConfiguration {
+	b.Replicas = &value
+	return b
+}
+{{- end }}
+{{- if .Resource.Suspend }}
+
+// WithSuspend sets the suspend field of the {{.Resource.Kind}}Spec
+func (b *{{.Resource.Kind}}SpecApplyConfiguration) WithSuspend(value bool) *{{.Resource.Kind}}SpecApplyConfiguration {
+	b.Suspend = &value
+	return b
+}
+{{- end }}
+`
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var _ = Describe("ApplyConfiguration", func() {
+	It("should scaffold the apply configuration of the spec without the type metadata", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Suspend: true,
+			ApplyConfiguration: true}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &scaffoldv2.ApplyConfiguration{Resource: frigate})).
+			To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("api", "v1", "frigate_applyconfiguration.go"))
+		Expect(content).To(ContainSubstring("func ApplyFrigate(name string) *FrigateApplyConfiguration {"))
+		Expect(content).To(ContainSubstring(`json:"suspend,omitempty"`))
+		Expect(content).NotTo(ContainSubstring("metav1.TypeMeta"))
+	})
+})
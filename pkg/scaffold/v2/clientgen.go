@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &ClientRegister{}
+
+// ClientRegister scaffolds the api/<version>/register.go file with the SchemeGroupVersion and Resource helpers
+// that the clientset, listers and informers generated by k8s.io/code-generator refer to
+type ClientRegister struct {
+	input.Input
+
+	// Resource is a resource in the API group
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *ClientRegister) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		if f.MultiGroup {
+			f.Path = filepath.Join("apis", f.Resource.Group, f.Resource.Version, "register.go")
+		} else {
+			f.Path = filepath.Join("api", f.Resource.Version, "register.go")
+		}
+	}
+	f.TemplateBody = clientRegisterTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *ClientRegister) Validate() error {
+	return f.Resource.Validate()
+}
+
+const clientRegisterTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the generated clientset, listers and informers
+var SchemeGroupVersion = GroupVersion
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+`
+
+var _ input.File = &UpdateCodegen{}
+
+// UpdateCodegen scaffolds the hack/update-codegen.sh script that generates the typed clientset, listers and
+// informers of the APIs under pkg/client
+type UpdateCodegen struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *UpdateCodegen) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("hack", "update-codegen.sh")
+	}
+	f.TemplateBody = updateCodegenTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// nolint:lll
+const updateCodegenTemplate = `#!/usr/bin/env bash
+
+# Generates the typed clientset, listers and informers of the API versions whose Kinds have the +genclient marker
+# under pkg/client, with the client-gen, lister-gen and informer-gen generators of k8s.io/code-generator.
+# Run make generate-clients, which installs the generators, after changing the types.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+cd "$(dirname "${BASH_SOURCE[0]}")/.."
+
+REPO={{ .Repo }}
+CODE_GENERATOR_BIN=${CODE_GENERATOR_BIN:-$(go env GOPATH)/bin}
+OUTPUT_BASE=$(mktemp -d)
+{{- if .MultiGroup }}
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+{{- else }}
+# client-gen reads the api/<version> packages as the Kubernetes core group, so they are generated through
+# a hack/clientgen/<group> link to the api directory and the imports are rewritten afterwards
+LINK_DIR=hack/clientgen
+trap 'rm -rf "${OUTPUT_BASE}" "${LINK_DIR}"' EXIT
+{{- end }}
+
+inputs=()
+{{- if .MultiGroup }}
+for dir in $(grep -rl --include='*_types.go' '^// +genclient$' apis | xargs -n1 dirname | sort -u); do
+	inputs+=("${REPO}/${dir}")
+done
+{{- else }}
+for dir in $(grep -rl --include='*_types.go' '^// +genclient$' api | xargs -n1 dirname | sort -u); do
+	group=$(sed -n 's|^// +groupName=||p' "${dir}/groupversion_info.go")
+	group=${group%%.*}
+	mkdir -p "${LINK_DIR}"
+	ln -sfn ../../api "${LINK_DIR}/${group}"
+	inputs+=("${REPO}/${LINK_DIR}/${group}/$(basename "${dir}")")
+done
+{{- end }}
+if [ ${#inputs[@]} -eq 0 ]; then
+	echo "No Kind has the +genclient marker"
+	exit 0
+fi
+INPUT_DIRS=$(IFS=,; echo "${inputs[*]}")
+
+"${CODE_GENERATOR_BIN}/client-gen" --clientset-name versioned --input-base "" --input "${INPUT_DIRS}" \
+	--output-package "${REPO}/pkg/client/clientset" --output-base "${OUTPUT_BASE}" --go-header-file hack/boilerplate.go.txt
+"${CODE_GENERATOR_BIN}/lister-gen" --input-dirs "${INPUT_DIRS}" \
+	--output-package "${REPO}/pkg/client/listers" --output-base "${OUTPUT_BASE}" --go-header-file hack/boilerplate.go.txt
+"${CODE_GENERATOR_BIN}/informer-gen" --input-dirs "${INPUT_DIRS}" \
+	--versioned-clientset-package "${REPO}/pkg/client/clientset/versioned" --listers-package "${REPO}/pkg/client/listers" \
+	--output-package "${REPO}/pkg/client/informers" --output-base "${OUTPUT_BASE}" --go-header-file hack/boilerplate.go.txt
+
+rm -rf pkg/client
+mkdir -p pkg
+cp -R "${OUTPUT_BASE}/${REPO}/pkg/client" pkg/
+{{- if not .MultiGroup }}
+for file in $(grep -rl "${REPO}/${LINK_DIR}/" pkg/client); do
+	sed -e "s|${REPO}/${LINK_DIR}/[^/]*/|${REPO}/api/|g" "${file}" > "${file}.tmp"
+	mv "${file}.tmp" "${file}"
+done
+{{- end }}
+`
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Names of the optional features of the default overlay, deployed as kustomize components
+const (
+	ComponentWebhook     = "webhook"
+	ComponentCertManager = "certmanager"
+	ComponentPrometheus  = "prometheus"
+	ComponentProduction  = "production"
+	ComponentWebhookCA   = "webhookca"
+	// ComponentNetworkPolicy restricts the traffic of the manager in clusters denying it by default
+	ComponentNetworkPolicy = "networkpolicy"
+	// ComponentObservability provisions the Grafana dashboards and the alerts of the manager metrics
+	ComponentObservability = "observability"
+	// ComponentPolicy deploys the ValidatingAdmissionPolicies scaffolded by create policy, it is not one of
+	// Components as it can't be enabled before a policy is created
+	ComponentPolicy = "policy"
+)
+
+// Components are the kustomize components that can be enabled in the default overlay
+var Components = []string{
+	ComponentWebhook,
+	ComponentCertManager,
+	ComponentPrometheus,
+	ComponentProduction,
+	ComponentWebhookCA,
+	ComponentNetworkPolicy,
+	ComponentObservability,
+}
+
+var _ input.File = &Component{}
+
+// Component scaffolds the Kustomization file of an optional feature of the default overlay
+type Component struct {
+	input.Input
+
+	// Name is the name of the component, one of Components
+	Name string
+}
+
+// GetInput implements input.File
+func (f *Component) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "components", f.Name, "kustomization.yaml")
+	}
+	f.TemplateBody = componentTemplates[f.Name]
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Component) Validate() error {
+	if _, found := componentTemplates[f.Name]; !found {
+		return fmt.Errorf("unknown component %q", f.Name)
+	}
+	return nil
+}
+
+var componentTemplates = map[string]string{
+	ComponentWebhook:       componentWebhookTemplate,
+	ComponentCertManager:   componentCertManagerTemplate,
+	ComponentPrometheus:    componentPrometheusTemplate,
+	ComponentProduction:    componentProductionTemplate,
+	ComponentWebhookCA:     componentWebhookCATemplate,
+	ComponentNetworkPolicy: componentNetworkPolicyTemplate,
+	ComponentObservability: componentObservabilityTemplate,
+	ComponentPolicy:        componentPolicyTemplate,
+}
+
+const componentWebhookTemplate = `# Serves the admission and conversion webhooks from the manager.
+# The webhook server needs a certificate, provided by the certmanager component.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- ../../webhook
+
+patchesStrategicMerge:
+- manager_webhook_patch.yaml
+`
+
+const componentCertManagerTemplate = `# Issues the certificate of the webhook server with cert-manager and injects
+# its CA in the admission webhooks. It requires the webhook component.
+# Uncomment the [CERTMANAGER] patches in crd/kustomization.yaml to inject the CA in the
+# conversion webhooks.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- ../../certmanager
+
+patchesStrategicMerge:
+- webhookcainjection_patch.yaml
+
+# the following config is for teaching kustomize how to do var substitution
+vars:
+- name: CERTIFICATE_NAMESPACE # namespace of the certificate CR
+  objref:
+    kind: Certificate
+    group: cert-manager.io
+    version: v1alpha2
+    name: serving-cert # this name should match the one in certificate.yaml
+  fieldref:
+    fieldpath: metadata.namespace
+- name: CERTIFICATE_NAME
+  objref:
+    kind: Certificate
+    group: cert-manager.io
+    version: v1alpha2
+    name: serving-cert # this name should match the one in certificate.yaml
+- name: SERVICE_NAMESPACE # namespace of the service
+  objref:
+    kind: Service
+    version: v1
+    name: webhook-service
+  fieldref:
+    fieldpath: metadata.namespace
+- name: SERVICE_NAME
+  objref:
+    kind: Service
+    version: v1
+    name: webhook-service
+`
+
+const componentPrometheusTemplate = `# Scrapes the metrics of the manager with a prometheus ServiceMonitor.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- ../../prometheus
+`
+
+const componentProductionTemplate = `# Hardens the manager Deployment for production clusters: a PriorityClass, a
+# PodDisruptionBudget, two replicas spread across the nodes and larger resource
+# requests and limits.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- priorityclass.yaml
+- pdb.yaml
+
+patchesStrategicMerge:
+- manager_production_patch.yaml
+`
+
+const componentWebhookCATemplate = `# Issues a self-signed certificate for the webhook server and injects its CA in the
+# admission webhooks with a Job, for clusters where cert-manager can't be installed.
+# It requires the webhook component and replaces the certmanager one. The CA of the
+# conversion webhooks has to be set in the caBundle of the CRDs by other means.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- certgen_rbac.yaml
+- certgen_job.yaml
+
+patchesStrategicMerge:
+- manager_webhook_cert_patch.yaml
+
+# the following config is for teaching kustomize how to do var substitution
+vars:
+- name: WEBHOOK_SERVICE_NAMESPACE # namespace of the service
+  objref:
+    kind: Service
+    version: v1
+    name: webhook-service
+  fieldref:
+    fieldpath: metadata.namespace
+- name: WEBHOOK_SERVICE_NAME
+  objref:
+    kind: Service
+    version: v1
+    name: webhook-service
+- name: MUTATING_WEBHOOK_CONFIGURATION_NAME
+  objref:
+    kind: MutatingWebhookConfiguration
+    group: admissionregistration.k8s.io
+    version: v1beta1
+    name: mutating-webhook-configuration
+- name: VALIDATING_WEBHOOK_CONFIGURATION_NAME
+  objref:
+    kind: ValidatingWebhookConfiguration
+    group: admissionregistration.k8s.io
+    version: v1beta1
+    name: validating-webhook-configuration
+`
+
+const componentNetworkPolicyTemplate = `# Restricts the traffic of the manager pods, for clusters denying it by default:
+# only the API server can call the webhook server, and the manager can only call
+# the API server.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- webhook_ingress.yaml
+- apiserver_egress.yaml
+`
+
+const componentObservabilityTemplate = `# Provisions the Grafana dashboards of the manager metrics and alerts on them
+# with a PrometheusRule. The metrics are scraped by the prometheus component.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- ../../grafana
+- alerts.yaml
+`
+
+const componentPolicyTemplate = `# Validates the objects of the resources in the API server with the
+# ValidatingAdmissionPolicies of config/policy, which require Kubernetes 1.30 or newer.
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- ../../policy
+`
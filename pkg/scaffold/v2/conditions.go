@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &Conditions{}
+
+// Conditions scaffolds the api/<version>/condition_types.go file with the conditions shared by the kinds of a version
+type Conditions struct {
+	input.Input
+
+	// Resource is a resource in the API group
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *Conditions) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		if f.MultiGroup {
+			f.Path = filepath.Join("apis", f.Resource.Group, f.Resource.Version, "condition_types.go")
+		} else {
+			f.Path = filepath.Join("api", f.Resource.Version, "condition_types.go")
+		}
+	}
+	f.TemplateBody = conditionsTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Conditions) Validate() error {
+	return f.Resource.Validate()
+}
+
+const conditionsTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionReady indicates that the resource has been reconciled successfully
+	ConditionReady = "Ready"
+)
+
+// Condition contains details for one aspect of the current state of a resource.
+// It follows the same conventions as metav1.Condition.
+type Condition struct {
+	// Type of the condition in CamelCase, e.g. Ready
+	Type string ` + "`" + `json:"type"` + "`" + `
+
+	// Status of the condition, one of True, False or Unknown
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus ` + "`" + `json:"status"` + "`" + `
+
+	// ObservedGeneration is the .metadata.generation that the condition was set based upon
+	// +optional
+	ObservedGeneration int64 ` + "`" + `json:"observedGeneration,omitempty"` + "`" + `
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another
+	LastTransitionTime metav1.Time ` + "`" + `json:"lastTransitionTime"` + "`" + `
+
+	// Reason contains a programmatic identifier in CamelCase indicating the reason for the last transition
+	Reason string ` + "`" + `json:"reason"` + "`" + `
+
+	// Message is a human readable message indicating details about the transition
+	// +optional
+	Message string ` + "`" + `json:"message,omitempty"` + "`" + `
+}
+
+// FindCondition returns the condition with the provided type, or nil if it is not found
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds the provided condition or updates the existing one with the same type.
+// LastTransitionTime is only changed when the status of the condition changes.
+func SetCondition(conditions *[]Condition, newCondition Condition) {
+	if conditions == nil {
+		return
+	}
+
+	existingCondition := FindCondition(*conditions, newCondition.Type)
+	if existingCondition == nil {
+		if newCondition.LastTransitionTime.IsZero() {
+			newCondition.LastTransitionTime = metav1.Now()
+		}
+		*conditions = append(*conditions, newCondition)
+		return
+	}
+
+	if existingCondition.Status != newCondition.Status {
+		existingCondition.Status = newCondition.Status
+		if newCondition.LastTransitionTime.IsZero() {
+			existingCondition.LastTransitionTime = metav1.Now()
+		} else {
+			existingCondition.LastTransitionTime = newCondition.LastTransitionTime
+		}
+	}
+	existingCondition.Reason = newCondition.Reason
+	existingCondition.Message = newCondition.Message
+	existingCondition.ObservedGeneration = newCondition.ObservedGeneration
+}
+
+// RemoveCondition removes the condition with the provided type
+func RemoveCondition(conditions *[]Condition, conditionType string) {
+	if conditions == nil {
+		return
+	}
+
+	filtered := make([]Condition, 0, len(*conditions))
+	for _, condition := range *conditions {
+		if condition.Type != conditionType {
+			filtered = append(filtered, condition)
+		}
+	}
+	*conditions = filtered
+}
+
+// IsConditionTrue returns true if the condition with the provided type has status True
+func IsConditionTrue(conditions []Condition, conditionType string) bool {
+	condition := FindCondition(conditions, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+`
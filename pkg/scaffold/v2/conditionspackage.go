@@ -0,0 +1,229 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &ConditionsPackage{}
+
+// ConditionsPackage scaffolds the pkg/conditions/conditions.go file with the Condition type shared by the resources
+// created with --conditions-package and the helpers setting and summarizing their conditions
+type ConditionsPackage struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *ConditionsPackage) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("pkg", "conditions", "conditions.go")
+	}
+	f.TemplateBody = conditionsPackageTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+const conditionsPackageTemplate = `{{ .Boilerplate }}
+
+// Package conditions defines the Condition type reported in the status of the resources of the project and the
+// helpers that set and summarize the conditions of an object, following the conventions of metav1.Condition.
+// +kubebuilder:object:generate=true
+package conditions
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReadyCondition summarizes the other conditions of an object, see SetSummary
+	ReadyCondition = "Ready"
+)
+
+// Condition contains details for one aspect of the current state of a resource.
+// It follows the same conventions as metav1.Condition.
+type Condition struct {
+	// Type of the condition in CamelCase, e.g. Ready
+	Type string ` + "`" + `json:"type"` + "`" + `
+
+	// Status of the condition, one of True, False or Unknown
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus ` + "`" + `json:"status"` + "`" + `
+
+	// ObservedGeneration is the .metadata.generation that the condition was set based upon
+	// +optional
+	ObservedGeneration int64 ` + "`" + `json:"observedGeneration,omitempty"` + "`" + `
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another
+	LastTransitionTime metav1.Time ` + "`" + `json:"lastTransitionTime"` + "`" + `
+
+	// Reason contains a programmatic identifier in CamelCase indicating the reason for the last transition
+	Reason string ` + "`" + `json:"reason"` + "`" + `
+
+	// Message is a human readable message indicating details about the transition
+	// +optional
+	Message string ` + "`" + `json:"message,omitempty"` + "`" + `
+}
+
+// Getter is an object whose status reports conditions
+// +kubebuilder:object:generate=false
+type Getter interface {
+	metav1.Object
+
+	// GetConditions returns the conditions of the status of the object
+	GetConditions() []Condition
+}
+
+// Setter is an object whose conditions are set by its controller
+// +kubebuilder:object:generate=false
+type Setter interface {
+	Getter
+
+	// SetConditions replaces the conditions of the status of the object
+	SetConditions(conditions []Condition)
+}
+
+// Get returns the condition of the object with the provided type, or nil if it is not found
+func Get(from Getter, conditionType string) *Condition {
+	conditions := from.GetConditions()
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsTrue returns true if the condition of the object with the provided type has status True
+func IsTrue(from Getter, conditionType string) bool {
+	condition := Get(from, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// IsFalse returns true if the condition of the object with the provided type has status False
+func IsFalse(from Getter, conditionType string) bool {
+	condition := Get(from, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionFalse
+}
+
+// Set adds the provided condition to the object or updates the existing one with the same type.
+// LastTransitionTime is only changed when the status of the condition changes, and ObservedGeneration defaults to
+// the generation of the object.
+func Set(to Setter, condition Condition) {
+	if condition.ObservedGeneration == 0 {
+		condition.ObservedGeneration = to.GetGeneration()
+	}
+
+	conditions := to.GetConditions()
+	for i := range conditions {
+		if conditions[i].Type != condition.Type {
+			continue
+		}
+		if conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = conditions[i].LastTransitionTime
+		} else if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		conditions[i] = condition
+		to.SetConditions(conditions)
+		return
+	}
+
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	to.SetConditions(append(conditions, condition))
+}
+
+// MarkTrue sets the condition of the object with the provided type to True
+func MarkTrue(to Setter, conditionType, reason, messageFormat string, messageArgs ...interface{}) {
+	mark(to, conditionType, corev1.ConditionTrue, reason, messageFormat, messageArgs...)
+}
+
+// MarkFalse sets the condition of the object with the provided type to False
+func MarkFalse(to Setter, conditionType, reason, messageFormat string, messageArgs ...interface{}) {
+	mark(to, conditionType, corev1.ConditionFalse, reason, messageFormat, messageArgs...)
+}
+
+// MarkUnknown sets the condition of the object with the provided type to Unknown
+func MarkUnknown(to Setter, conditionType, reason, messageFormat string, messageArgs ...interface{}) {
+	mark(to, conditionType, corev1.ConditionUnknown, reason, messageFormat, messageArgs...)
+}
+
+func mark(to Setter, conditionType string, status corev1.ConditionStatus, reason, messageFormat string,
+	messageArgs ...interface{}) {
+	Set(to, Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
+
+// Delete removes the condition of the object with the provided type
+func Delete(to Setter, conditionType string) {
+	conditions := to.GetConditions()
+	filtered := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		if condition.Type != conditionType {
+			filtered = append(filtered, condition)
+		}
+	}
+	to.SetConditions(filtered)
+}
+
+// SetSummary sets the Ready condition of the object from the conditions with the provided types, all the other
+// conditions of the object if none is provided. Ready is False with the reason and message of the first False
+// condition, else Unknown with the ones of the first missing or Unknown condition, else True.
+func SetSummary(to Setter, conditionTypes ...string) {
+	if len(conditionTypes) == 0 {
+		for _, condition := range to.GetConditions() {
+			if condition.Type != ReadyCondition {
+				conditionTypes = append(conditionTypes, condition.Type)
+			}
+		}
+	}
+
+	var unknown *Condition
+	for _, conditionType := range conditionTypes {
+		condition := Get(to, conditionType)
+		switch {
+		case condition == nil:
+			if unknown == nil {
+				unknown = &Condition{Reason: conditionType + "Missing",
+					Message: fmt.Sprintf("the %s condition is not reported yet", conditionType)}
+			}
+		case condition.Status == corev1.ConditionFalse:
+			MarkFalse(to, ReadyCondition, condition.Reason, "%s", condition.Message)
+			return
+		case condition.Status != corev1.ConditionTrue && unknown == nil:
+			unknown = condition
+		}
+	}
+
+	if unknown != nil {
+		MarkUnknown(to, ReadyCondition, unknown.Reason, "%s", unknown.Message)
+		return
+	}
+	MarkTrue(to, ReadyCondition, "Ready", "all the conditions are true")
+}
+`
@@ -17,13 +17,14 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
-
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 )
 
@@ -44,6 +45,38 @@ type Controller struct {
 
 	// Is the Group + "." + Domain for the Resource
 	GroupDomain string
+
+	// NamespaceScoped is true if the manager is granted a Role in its namespace instead of a ClusterRole
+	NamespaceScoped bool
+
+	// OwnedResources are the resources owned by the Controller
+	OwnedResources []relatedResource
+
+	// WatchedResources are the resources watched by the Controller to reconcile the objects referencing them
+	WatchedResources []relatedResource
+
+	// RelatedImports are the packages of the types of the owned and watched resources, by import alias
+	RelatedImports map[string]string
+
+	// FeatureGate is the name of the feature gate of the experimental reconcile path, if any
+	FeatureGate string
+
+	// Sharding is true if the Controller only reconciles the objects of the shard of the manager
+	Sharding bool
+}
+
+// relatedResource is a resource owned or watched by the Controller
+type relatedResource struct {
+	resource.ResourceRef
+
+	// ImportAlias is the import alias of the package of the types of the resource
+	ImportAlias string
+
+	// GroupDomain is the Group + "." + Domain for the resource
+	GroupDomain string
+
+	// Plural is the plural lowercase of kind
+	Plural string
 }
 
 // GetInput implements input.File
@@ -52,7 +85,29 @@ func (f *Controller) GetInput() (input.Input, error) {
 	f.ResourcePackage, f.GroupDomain = util.GetResourceInfo(f.Resource, f.Repo, f.Domain, f.MultiGroup)
 
 	if f.Plural == "" {
-		f.Plural = flect.Pluralize(strings.ToLower(f.Resource.Kind))
+		f.Plural = f.Resource.Plural()
+	}
+
+	if f.Resource.FeatureGate {
+		f.FeatureGate = FeatureGate(f.Resource)
+	}
+
+	f.RelatedImports = make(map[string]string)
+	f.OwnedResources = make([]relatedResource, 0, len(f.Resource.Owns))
+	for _, owned := range f.Resource.Owns {
+		related, err := f.relatedResource(owned)
+		if err != nil {
+			return input.Input{}, err
+		}
+		f.OwnedResources = append(f.OwnedResources, related)
+	}
+	f.WatchedResources = make([]relatedResource, 0, len(f.Resource.WatchesExternal))
+	for _, watched := range f.Resource.WatchesExternal {
+		related, err := f.relatedResource(watched)
+		if err != nil {
+			return input.Input{}, err
+		}
+		f.WatchedResources = append(f.WatchedResources, related)
 	}
 
 	if f.Path == "" {
@@ -71,17 +126,92 @@ func (f *Controller) GetInput() (input.Input, error) {
 	return f.Input, nil
 }
 
+// relatedResource resolves the package of the types of a resource owned or watched by the Controller,
+// either a Kubernetes resource or a resource of the project, and adds it to the imports
+func (f *Controller) relatedResource(ref resource.ResourceRef) (relatedResource, error) {
+	relatedPackage, groupDomain := util.GetResourceInfo(
+		&resource.Resource{Group: ref.Group, Version: ref.Version, Kind: ref.Kind},
+		f.Repo, f.Domain, f.MultiGroup)
+	relatedImport := path.Join(relatedPackage, ref.Version)
+	alias := templatefuncs.GroupPackageName(ref.Group) + ref.Version
+
+	// The package of the Resource is imported with the same alias
+	if alias == f.Resource.GroupImportSafe+f.Resource.Version {
+		if resourceImport := path.Join(f.ResourcePackage, f.Resource.Version); relatedImport != resourceImport {
+			return relatedResource{}, fmt.Errorf("resource %s conflicts with the import of %s", ref, resourceImport)
+		}
+	} else if existing, found := f.RelatedImports[alias]; found && existing != relatedImport {
+		return relatedResource{}, fmt.Errorf("resource %s conflicts with the import of %s", ref, existing)
+	} else {
+		f.RelatedImports[alias] = relatedImport
+	}
+
+	return relatedResource{
+		ResourceRef: ref,
+		ImportAlias: alias,
+		GroupDomain: groupDomain,
+		Plural:      resource.DefaultPlural(ref.Kind),
+	}, nil
+}
+
+// nolint:lll
 const controllerTemplate = `{{ .Boilerplate }}
 
 package controllers
 
 import (
 	"context"
+{{- if or .Resource.Metrics .Resource.ReconcilePeriod }}
+	"time"
+{{- end }}
 	"github.com/go-logr/logr"
+{{- if and (or (and .Resource.Conditions (not .Resource.ConditionsPackage)) .Resource.Events) (not (index .RelatedImports "corev1")) }}
+	corev1 "k8s.io/api/core/v1"
+{{- end }}
+{{- if or .Resource.WatchLabelSelector .OwnedResources }}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+{{- end }}
+{{- if .Resource.WatchLabelSelector }}
+	"k8s.io/apimachinery/pkg/labels"
+{{- end }}
 	"k8s.io/apimachinery/pkg/runtime"
+{{- if .Resource.Image }}
+	"k8s.io/apimachinery/pkg/util/intstr"
+{{- end }}
+{{- if .Resource.Events }}
+	"k8s.io/client-go/tools/record"
+{{- end }}
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+{{- if .Resource.ControllerOptions }}
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+{{- end }}
+{{- if .Resource.WatchLabelSelector }}
+	"sigs.k8s.io/controller-runtime/pkg/event"
+{{- end }}
+{{- if .WatchedResources }}
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+{{- end }}
+{{- if .Resource.WatchLabelSelector }}
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+{{- end }}
+{{- if .WatchedResources }}
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+{{- end }}
+{{- range $alias, $package := .RelatedImports }}
+	{{ $alias }} "{{ $package }}"
+{{- end }}
 	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+{{- if .Resource.ConditionsPackage }}
+	"{{ .Repo }}/pkg/conditions"
+{{- end }}
+{{- if .FeatureGate }}
+	"{{ .Repo }}/featuregates"
+{{- end }}
+{{- if .Sharding }}
+	"{{ .Repo }}/sharding"
+{{- end }}
 )
 
 // {{ .Resource.Kind }}Reconciler reconciles a {{ .Resource.Kind }} object
@@ -89,23 +219,339 @@ type {{ .Resource.Kind }}Reconciler struct {
 	client.Client
 	Log logr.Logger
 	Scheme *runtime.Scheme
+{{- if .Resource.Events }}
+	Recorder record.EventRecorder
+{{- end }}
+{{- if .Resource.ReconcilePeriod }}
+	// ReconcilePeriod is the delay after which the {{ .Resource.Kind }} objects are reconciled again, set with the
+	// --{{ .Resource.Kind | lower }}-reconcile-period flag of the manager
+	ReconcilePeriod time.Duration
+{{- end }}
 }
-
-// +kubebuilder:rbac:groups={{.GroupDomain}},resources={{ .Plural }},verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups={{.GroupDomain}},resources={{ .Plural }}/status,verbs=get;update;patch
+{{ if .Resource.Finalizer }}
+// {{ .Resource.Kind | lower }}Finalizer is the finalizer used to clean up the external resources of a {{ .Resource.Kind }}
+const {{ .Resource.Kind | lower }}Finalizer = "{{ .GroupDomain }}/{{ .Resource.Kind | lower }}-finalizer"
+{{ end }}
+// +kubebuilder:rbac:groups={{.GroupDomain}},resources={{ .Plural }},verbs=get;list;watch;create;update;patch;delete{{ if and .NamespaceScoped .Resource.Namespaced }},namespace=system{{ end }}
+// +kubebuilder:rbac:groups={{.GroupDomain}},resources={{ .Plural }}/status,verbs=get;update;patch{{ if and .NamespaceScoped .Resource.Namespaced }},namespace=system{{ end }}
+{{- range .OwnedResources }}
+// +kubebuilder:rbac:groups={{ .GroupDomain }},resources={{ .Plural }},verbs=get;list;watch;create;update;patch;delete{{ if $.NamespaceScoped }},namespace=system{{ end }}
+{{- end }}
+{{- range .WatchedResources }}
+// +kubebuilder:rbac:groups={{ .GroupDomain }},resources={{ .Plural }},verbs=get;list;watch{{ if $.NamespaceScoped }},namespace=system{{ end }}
+{{- end }}
+{{- if .Resource.Events }}
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch{{ if .NamespaceScoped }},namespace=system{{ end }}
+{{- end }}
 
 func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+{{- if .Resource.Metrics }}
+	defer observe{{ .Resource.Kind }}Reconcile(time.Now())
+{{ end }}
+{{- if or .Resource.Conditions .Resource.Finalizer .OwnedResources .Resource.Events .Resource.Suspend .Resource.ReconcilePeriod }}
+	ctx := context.Background()
+	// The verbosity is set with the --zap-log-level flag of the manager, e.g. log.V(1).Info is logged at debug
+	log := r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
+
+	instance := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		// The object may have been deleted after the reconcile request was queued
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+{{- if .Resource.ConditionsPackage }}
+	// The status is patched with the changes made since it was read, keeping the ones made by other clients meanwhile
+	statusPatch := client.MergeFrom(instance.DeepCopy())
+{{- end }}
+{{- if .Resource.Finalizer }}
+
+	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, register the finalizer so that the external resources can be cleaned up
+		if !containsFinalizer(instance, {{ .Resource.Kind | lower }}Finalizer) {
+			addFinalizer(instance, {{ .Resource.Kind | lower }}Finalizer)
+			if err := r.Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		// The object is being deleted
+		if containsFinalizer(instance, {{ .Resource.Kind | lower }}Finalizer) {
+			if err := r.deleteExternalResources(instance); err != nil {
+				// Keep the finalizer so that the deletion is retried
+				log.Error(err, "unable to delete the external resources")
+{{- if .Resource.Events }}
+				r.Recorder.Event(instance, corev1.EventTypeWarning, "CleanupFailed", err.Error())
+{{- end }}
+				return ctrl.Result{}, err
+			}
+
+			removeFinalizer(instance, {{ .Resource.Kind | lower }}Finalizer)
+			if err := r.Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Stop reconciling as the object is being deleted
+		return ctrl.Result{}, nil
+	}
+{{- end }}
+{{- if .Resource.Suspend }}
+
+	if instance.Spec.Suspend != nil && *instance.Spec.Suspend {
+		// Skip the reconciliation while the object is suspended, it resumes once spec.suspend is unset
+		log.V(1).Info("{{ .Resource.Kind }} is suspended, skipping reconciliation")
+{{- if .Resource.ConditionsPackage }}
+		conditions.MarkFalse(instance, conditions.ReadyCondition, "Suspended", "{{ .Resource.Kind }} is suspended")
+		if err := r.Status().Patch(ctx, instance, statusPatch); err != nil {
+			log.Error(err, "unable to update {{ .Resource.Kind }} status")
+			return ctrl.Result{}, err
+		}
+{{- else if .Resource.Conditions }}
+		{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.SetCondition(&instance.Status.Conditions, {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.Condition{
+			Type:               {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.ConditionReady,
+			Status:             corev1.ConditionFalse,
+			ObservedGeneration: instance.Generation,
+			Reason:             "Suspended",
+			Message:            "{{ .Resource.Kind }} is suspended",
+		})
+		if err := r.Status().Update(ctx, instance); err != nil {
+			log.Error(err, "unable to update {{ .Resource.Kind }} status")
+			return ctrl.Result{}, err
+		}
+{{- end }}
+		return ctrl.Result{}, nil
+	}
+{{- end }}
+
+	// your logic here
+{{- if .Resource.Image }}
+
+	// The labels of the Pods running the image, selected by the Deployment and the Service
+	podLabels := map[string]string{
+		"app.kubernetes.io/name":     "{{ .Resource.Kind | lower }}",
+		"app.kubernetes.io/instance": instance.Name,
+	}
+{{- end }}
+{{- range .OwnedResources }}
+
+	// Create or update the {{ .Kind }} owned by the {{ $.Resource.Kind }}, it is garbage collected with it
+	{{ .Kind | lower }} := &{{ .ImportAlias }}.{{ .Kind }}{
+		ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace},
+	}
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, {{ .Kind | lower }}, func() error {
+{{- if and $.Resource.Image (eq .Group "apps") (eq .Kind "Deployment") }}
+		// The fields defaulted by the API server are kept, so that the Deployment is only updated on changes
+		deployment.Spec.Replicas = instance.Spec.Replicas
+		// The selector of a Deployment is immutable
+		if deployment.Spec.Selector == nil {
+			deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: podLabels}
+		}
+		deployment.Spec.Template.Labels = podLabels
+		if len(deployment.Spec.Template.Spec.Containers) == 0 {
+			deployment.Spec.Template.Spec.Containers = []corev1.Container{
+				{Name: "{{ $.Resource.Kind | lower }}"},
+			}
+		}
+		container := &deployment.Spec.Template.Spec.Containers[0]
+		container.Image = instance.Spec.Image
+		container.Ports = []corev1.ContainerPort{
+			{ContainerPort: instance.Spec.ContainerPort, Protocol: corev1.ProtocolTCP},
+		}
+{{- else if and $.Resource.Image (eq .Group "core") (eq .Kind "Service") }}
+		service.Spec.Selector = podLabels
+		service.Spec.Ports = []corev1.ServicePort{
+			{
+				Port:       instance.Spec.ContainerPort,
+				TargetPort: intstr.FromInt(int(instance.Spec.ContainerPort)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		}
+{{- else }}
+		// set the desired state of the {{ .Kind }} here, from the spec of the {{ $.Resource.Kind }}
+{{- end }}
+
+		return ctrl.SetControllerReference(instance, {{ .Kind | lower }}, r.Scheme)
+	}); err != nil {
+		log.Error(err, "unable to create or update {{ .Kind }}")
+		return ctrl.Result{}, err
+	}
+{{- end }}
+{{- end }}
+{{- if .Resource.Image }}
+
+	// The {{ .Resource.Kind }} is ready once the Deployment rolled out and all its replicas are available
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+{{- if .Resource.ConditionsPackage }}
+	if deployment.Status.ObservedGeneration >= deployment.Generation && deployment.Status.AvailableReplicas >= replicas {
+		conditions.MarkTrue(instance, "DeploymentAvailable", "Available", "the replicas of the Deployment are available")
+	} else {
+		conditions.MarkFalse(instance, "DeploymentAvailable", "Progressing",
+			"waiting for the replicas of the Deployment to be available")
+	}
+	// Ready summarizes the other conditions, add the ones of the other owned objects to its inputs
+	conditions.SetSummary(instance, "DeploymentAvailable")
+	if err := r.Status().Patch(ctx, instance, statusPatch); err != nil {
+		log.Error(err, "unable to update {{ .Resource.Kind }} status")
+		return ctrl.Result{}, err
+	}
+{{- else }}
+	readyCondition := {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.Condition{
+		Type:               {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.ConditionReady,
+		Status:             corev1.ConditionFalse,
+		ObservedGeneration: instance.Generation,
+		Reason:             "Progressing",
+		Message:            "waiting for the replicas of the Deployment to be available",
+	}
+	if deployment.Status.ObservedGeneration >= deployment.Generation && deployment.Status.AvailableReplicas >= replicas {
+		readyCondition.Status = corev1.ConditionTrue
+		readyCondition.Reason = "Available"
+		readyCondition.Message = "the replicas of the Deployment are available"
+	}
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.SetCondition(&instance.Status.Conditions, readyCondition)
+	if err := r.Status().Update(ctx, instance); err != nil {
+		log.Error(err, "unable to update {{ .Resource.Kind }} status")
+		return ctrl.Result{}, err
+	}
+{{- end }}
+{{- else if .Resource.ConditionsPackage }}
+
+	// Report the result of the reconciliation through the status conditions
+	conditions.MarkTrue(instance, conditions.ReadyCondition, "Reconciled", "{{ .Resource.Kind }} has been reconciled")
+	if err := r.Status().Patch(ctx, instance, statusPatch); err != nil {
+		log.Error(err, "unable to update {{ .Resource.Kind }} status")
+		return ctrl.Result{}, err
+	}
+{{- else if .Resource.Conditions }}
+
+	// Report the result of the reconciliation through the status conditions
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.SetCondition(&instance.Status.Conditions, {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.Condition{
+		Type:               {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.ConditionReady,
+		Status:             corev1.ConditionTrue,
+		ObservedGeneration: instance.Generation,
+		Reason:             "Reconciled",
+		Message:            "{{ .Resource.Kind }} has been reconciled",
+	})
+	if err := r.Status().Update(ctx, instance); err != nil {
+		log.Error(err, "unable to update {{ .Resource.Kind }} status")
+		return ctrl.Result{}, err
+	}
+{{- end }}
+{{- if .Resource.Events }}
+
+	// Record an event about the {{ .Resource.Kind }}, listed by "kubectl describe"
+	r.Recorder.Event(instance, corev1.EventTypeNormal, "Reconciled", "{{ .Resource.Kind }} has been reconciled")
+	log.V(1).Info("{{ .Resource.Kind }} has been reconciled")
+{{- end }}
+{{- if not (or .Resource.Conditions .Resource.Finalizer .OwnedResources .Resource.Events .Resource.Suspend .Resource.ReconcilePeriod) }}
 	_ = context.Background()
+	// The verbosity is set with the --zap-log-level flag of the manager, e.g. log.V(1).Info is logged at debug
 	_ = r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
 
 	// your logic here
+{{- end }}
+{{- if .FeatureGate }}
+
+	if featuregates.Enabled(featuregates.{{ .FeatureGate }}) {
+		// your experimental logic here, enabled with the --feature-gates={{ .FeatureGate }}=true flag of the manager
+		r.Log.V(1).Info("reconciling with the experimental path", "featureGate", featuregates.{{ .FeatureGate }})
+	}
+{{- end }}
+{{- if .Resource.ApplyConfiguration }}
+
+	// Server-side apply sends only the fields set in the apply configuration, which are then owned by the field
+	// manager of the controller without conflicting with the fields owned by other managers, e.g.:
+	//
+	// applied, err := {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.Apply{{ .Resource.Kind }}({{ if .Resource.Namespaced }}req.Name, req.Namespace{{ else }}req.Name{{ end }}).
+	// 	WithSpec({{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.Apply{{ .Resource.Kind }}Spec().WithFoo("bar")).
+	// 	ToUnstructured()
+	// if err != nil {
+	// 	return ctrl.Result{}, err
+	// }
+	// if err := r.Patch({{ if or .Resource.Conditions .Resource.Finalizer .OwnedResources .Resource.Events .Resource.Suspend .Resource.ReconcilePeriod }}ctx{{ else }}context.Background(){{ end }}, applied, client.Apply, client.FieldOwner("{{ .Resource.Kind | lower }}-controller"), client.ForceOwnership); err != nil {
+	// 	return ctrl.Result{}, err
+	// }
+{{- end }}
+{{- if .Resource.ReconcilePeriod }}
+
+	// Reconcile the {{ .Resource.Kind }} again after the reconcile period, to poll the state it depends on that doesn't
+	// trigger watch events, e.g. an external system. Any change of the {{ .Resource.Kind }} still reconciles it
+	// right away.
+	log.V(1).Info("requeuing {{ .Resource.Kind }}", "after", r.ReconcilePeriod)
+	return ctrl.Result{RequeueAfter: r.ReconcilePeriod}, nil
+{{- else }}
 
 	return ctrl.Result{}, nil
+{{- end }}
+}
+
+{{- if .Resource.Finalizer }}
+
+// deleteExternalResources deletes the resources outside of the cluster that are associated with the {{ .Resource.Kind }}.
+// It needs to be idempotent as it can be called several times for the same object.
+func (r *{{ .Resource.Kind }}Reconciler) deleteExternalResources(instance *{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}) error {
+	// delete the external resources associated with the {{ .Resource.Kind }} here
+
+	return nil
 }
+{{- end }}
 
+{{- range .WatchedResources }}
+
+// requestsFor{{ .Kind }} maps a {{ .Kind }} to the reconcile requests of the {{ $.Resource.Kind }} objects that reference it,
+// so that they are reconciled when it changes
+func (r *{{ $.Resource.Kind }}Reconciler) requestsFor{{ .Kind }}(obj handler.MapObject) []reconcile.Request {
+	// list the {{ $.Resource.Kind }} objects referencing the {{ .Kind }} obj.Meta here, e.g. with a field index,
+	// and return a request with the NamespacedName of each of them
+
+	return nil
+}
+{{- end }}
+{{ if .Resource.ControllerOptions }}
+// SetupWithManager sets up the controller with the options, e.g. the number of concurrent reconciles,
+// set with the flags of the manager
+func (r *{{ .Resource.Kind }}Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+{{- else }}
 func (r *{{ .Resource.Kind }}Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+{{- end }}
+{{- if .Resource.WatchLabelSelector }}
+	selector, err := labels.Parse("{{ .Resource.WatchLabelSelector }}")
+	if err != nil {
+		return err
+	}
+	matches := func(meta metav1.Object) bool { return selector.Matches(labels.Set(meta.GetLabels())) }
+{{ end }}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}).
+{{- if .Resource.ControllerOptions }}
+		WithOptions(options).
+{{- end }}
+{{- range .OwnedResources }}
+		Owns(&{{ .ImportAlias }}.{{ .Kind }}{}).
+{{- end }}
+{{- range .WatchedResources }}
+		Watches(&source.Kind{Type: &{{ .ImportAlias }}.{{ .Kind }}{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.requestsFor{{ .Kind }}),
+		}).
+{{- end }}
+{{- if .Resource.WatchLabelSelector }}
+		// The event filters apply to all the watches of the controller.
+		// Only the changes of the spec, which increment the generation, trigger a reconciliation, not the status updates.
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		// Only the objects with labels matching the selector are reconciled
+		WithEventFilter(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return matches(e.Meta) },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return matches(e.MetaNew) },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return matches(e.Meta) },
+			GenericFunc: func(e event.GenericEvent) bool { return matches(e.Meta) },
+		}).
+{{- end }}
+{{- if .Sharding }}
+		// Only the objects of the shard of the manager are reconciled, the objects created for a {{ .Resource.Kind }}
+		// must be assigned to its shard with sharding.CopyLabel so that their events are received
+		WithEventFilter(sharding.Predicate()).
+{{- end }}
 		Complete(r)
 }
 `
@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
@@ -70,6 +73,7 @@ import (
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	// +kubebuilder:scaffold:imports
@@ -87,7 +91,7 @@ func TestAPIs(t *testing.T) {
 
 	RunSpecsWithDefaultAndCustomReporters(t,
 	"Controller Suite",
-	[]Reporter{envtest.NewlineReporter{}})
+	[]Reporter{printer.NewlineReporter{}})
 }
 
 var _ = BeforeSuite(func(done Done) {
@@ -121,7 +125,30 @@ var _ = AfterSuite(func() {
 
 // Update updates given file (suite_test.go) with code fragments required for
 // adding import paths and code setup for new types.
-func (f *SuiteTest) Update() error {
+func (f *SuiteTest) Update(fs afero.Fs) error {
+	return inject.Update(fs, f.Path, f.Fragments())
+}
+
+// Fragments returns the code fragments inserted by Update below each marker of suite_test.go
+func (f *SuiteTest) Fragments() map[string][]string {
+	ctrlImportCodeFragment, apiImportCodeFragment, addschemeCodeFragment := f.codeFragments()
+
+	return map[string][]string{
+		scaffoldv2.APIPkgImportScaffoldMarker: {ctrlImportCodeFragment, apiImportCodeFragment},
+		scaffoldv2.APISchemeScaffoldMarker:    {addschemeCodeFragment},
+	}
+}
+
+// Remove removes from the given file (suite_test.go) the scheme registration
+// for the types of the resource. Unused imports are dropped when formatting.
+func (f *SuiteTest) Remove(fs afero.Fs) error {
+	_, _, addschemeCodeFragment := f.codeFragments()
+
+	return internal.RemoveStringsFromFile(fs, f.Path, addschemeCodeFragment)
+}
+
+// codeFragments returns the controller import, API import and scheme registration code fragments
+func (f *SuiteTest) codeFragments() (string, string, string) {
 
 	resourcePackage, _ := util.GetResourceInfo(f.Resource, f.Repo, f.Domain, f.MultiGroup)
 
@@ -135,14 +162,5 @@ Expect(err).NotTo(HaveOccurred())
 
 `, f.Resource.GroupImportSafe, f.Resource.Version)
 
-	err := internal.InsertStringsInFile(f.Path,
-		map[string][]string{
-			scaffoldv2.APIPkgImportScaffoldMarker: {ctrlImportCodeFragment, apiImportCodeFragment},
-			scaffoldv2.APISchemeScaffoldMarker:    {addschemeCodeFragment},
-		})
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return ctrlImportCodeFragment, apiImportCodeFragment, addschemeCodeFragment
 }
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/controller"
+)
+
+var _ = Describe("Controller", func() {
+	var frigate *resource.Resource
+
+	BeforeEach(func() {
+		frigate = &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			CreateExampleReconcileBody: true}
+	})
+
+	// scaffoldController returns the controller scaffolded by f
+	scaffoldController := func(f *controller.Controller) string {
+		Expect(f.Resource.Validate()).To(Succeed())
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, f)).To(Succeed())
+		return scaffoldtest.ReadFile(fs, filepath.Join("controllers", "frigate_controller.go"))
+	}
+
+	It("should gate the experimental reconcile path behind the feature gate of the controller", func() {
+		frigate.FeatureGate = true
+		content := scaffoldController(&controller.Controller{Resource: frigate})
+
+		Expect(content).To(ContainSubstring("if featuregates.Enabled(featuregates.ExperimentalFrigateReconcile) {"))
+	})
+
+	It("should requeue the objects after the reconcile period", func() {
+		frigate.ReconcilePeriod = 5 * time.Minute
+		content := scaffoldController(&controller.Controller{Resource: frigate})
+
+		Expect(content).To(ContainSubstring("return ctrl.Result{RequeueAfter: r.ReconcilePeriod}, nil"))
+	})
+
+	It("should deploy the image with a Deployment and a Service", func() {
+		frigate.Image = "nginx:1.19"
+		frigate.ImageContainerPort = 80
+		frigate.Conditions = true
+		frigate.Owns = resource.ImageResources
+		content := scaffoldController(&controller.Controller{Resource: frigate})
+
+		Expect(content).To(ContainSubstring("container.Image = instance.Spec.Image"))
+		Expect(content).To(ContainSubstring("service.Spec.Selector = podLabels"))
+		Expect(content).To(ContainSubstring("readyCondition.Status = corev1.ConditionTrue"))
+	})
+
+	It("should show an example of server-side apply with the apply configuration", func() {
+		frigate.Namespaced = false
+		frigate.ApplyConfiguration = true
+		content := scaffoldController(&controller.Controller{Resource: frigate})
+
+		Expect(content).To(ContainSubstring("// applied, err := shipv1.ApplyFrigate(req.Name)."))
+		Expect(content).To(ContainSubstring(`client.FieldOwner("frigate-controller"), client.ForceOwnership)`))
+	})
+
+	It("should only reconcile the objects of the shard of the manager", func() {
+		content := scaffoldController(&controller.Controller{Resource: frigate, Sharding: true})
+
+		Expect(content).To(ContainSubstring("\t\tWithEventFilter(sharding.Predicate()).\n"))
+	})
+})
+
+var _ = Describe("FeatureGates", func() {
+	It("should register the feature gate of the controller disabled by default", func() {
+		fragments := (&controller.FeatureGates{}).Fragments(&resource.Resource{Kind: "Frigate"})
+
+		var all []string
+		for _, f := range fragments {
+			all = append(all, f...)
+		}
+		Expect(all).To(ContainElement(ContainSubstring(
+			`ExperimentalFrigateReconcile Feature = "ExperimentalFrigateReconcile"`)))
+		Expect(all).To(ContainElement(ContainSubstring(
+			"ExperimentalFrigateReconcile: {Default: false, PreRelease: Alpha},")))
+	})
+})
@@ -0,0 +1,379 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+var _ input.File = &ControllerTest{}
+
+// ControllerTest scaffolds the <kind>_controller_test.go file to test a Controller
+type ControllerTest struct {
+	input.Input
+
+	// Resource is the Resource to make the Controller for
+	Resource *resource.Resource
+
+	// ResourcePackage is the package of the Resource
+	ResourcePackage string
+}
+
+// GetInput implements input.File
+func (f *ControllerTest) GetInput() (input.Input, error) {
+	f.ResourcePackage, _ = util.GetResourceInfo(f.Resource, f.Repo, f.Domain, f.MultiGroup)
+
+	if f.Path == "" {
+		if f.MultiGroup {
+			f.Path = filepath.Join("controllers", f.Resource.Group,
+				strings.ToLower(f.Resource.Kind)+"_controller_test.go")
+		} else {
+			f.Path = filepath.Join("controllers", strings.ToLower(f.Resource.Kind)+"_controller_test.go")
+		}
+	}
+	if f.Resource.TestStyle == resource.TestStyleFake {
+		f.TemplateBody = fakeControllerTestTemplate
+	} else {
+		f.TemplateBody = controllerTestTemplate
+	}
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *ControllerTest) Validate() error {
+	return f.Resource.Validate()
+}
+
+// nolint:lll
+const controllerTestTemplate = `{{ .Boilerplate }}
+
+package controllers
+
+import (
+	"context"
+{{- if .Resource.ReconcilePeriod }}
+	"time"
+{{- end }}
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+{{- if .Resource.Image }}
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+{{- end }}
+{{- if .Resource.Finalizer }}
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+{{- end }}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+{{- if .Resource.Events }}
+	"k8s.io/client-go/tools/record"
+{{- end }}
+	ctrl "sigs.k8s.io/controller-runtime"
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+{{- if and .Resource.Image .Resource.ConditionsPackage }}
+	"{{ .Repo }}/pkg/conditions"
+{{- end }}
+)
+
+var _ = Describe("{{ .Resource.Kind }} controller", func() {
+{{- if .Resource.Finalizer }}
+	It("should add the finalizer and remove it once the {{ .Resource.Kind }} is deleted", func() {
+		ctx := context.Background()
+		key := types.NamespacedName{Name: "test-{{ .Resource.Kind | lower }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}}
+		reconciler := &{{ .Resource.Kind }}Reconciler{
+			Client: k8sClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+			Scheme: scheme.Scheme,
+{{- if .Resource.Events }}
+			Recorder: record.NewFakeRecorder(10),
+{{- end }}
+{{- if .Resource.ReconcilePeriod }}
+			ReconcilePeriod: {{ goDuration .Resource.ReconcilePeriod }},
+{{- end }}
+		}
+
+		instance := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, instance)).To(Succeed())
+		Expect(instance.GetFinalizers()).To(ContainElement({{ .Resource.Kind | lower }}Finalizer))
+
+		Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+		_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.Get(ctx, key, instance)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+{{- end }}
+{{- if .Resource.ReconcilePeriod }}
+
+	It("should requeue the {{ .Resource.Kind }} after the reconcile period", func() {
+		ctx := context.Background()
+		key := types.NamespacedName{Name: "test-{{ .Resource.Kind | lower }}-requeue"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}}
+		reconciler := &{{ .Resource.Kind }}Reconciler{
+			Client:          k8sClient,
+			Log:             ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+			Scheme:          scheme.Scheme,
+{{- if .Resource.Events }}
+			Recorder:        record.NewFakeRecorder(10),
+{{- end }}
+			ReconcilePeriod: {{ goDuration .Resource.ReconcilePeriod }},
+		}
+
+		instance := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal({{ goDuration .Resource.ReconcilePeriod }}))
+
+		// The {{ .Resource.Kind }} objects that no longer exist are not requeued
+		Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+		Eventually(func() (time.Duration, error) {
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			return result.RequeueAfter, err
+		}).Should(BeZero())
+	})
+{{- end }}
+{{- if .Resource.Image }}
+
+	It("should deploy the image with a Deployment and a Service", func() {
+		ctx := context.Background()
+		key := types.NamespacedName{Name: "test-{{ .Resource.Kind | lower }}-image", Namespace: "default"}
+		reconciler := &{{ .Resource.Kind }}Reconciler{
+			Client: k8sClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+			Scheme: scheme.Scheme,
+{{- if .Resource.Events }}
+			Recorder: record.NewFakeRecorder(10),
+{{- end }}
+{{- if .Resource.ReconcilePeriod }}
+			ReconcilePeriod: {{ goDuration .Resource.ReconcilePeriod }},
+{{- end }}
+		}
+
+		instance := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}Spec{
+				Image:         {{ printf "%q" .Resource.Image }},
+				ContainerPort: {{ .Resource.ImageContainerPort }},
+			},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		deployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal({{ printf "%q" .Resource.Image }}))
+
+		service := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(service.Spec.Ports).To(HaveLen(1))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32({{ .Resource.ImageContainerPort }})))
+
+		// envtest doesn't run the controllers of Kubernetes, so the Pods of the Deployment are never available
+		Expect(k8sClient.Get(ctx, key, instance)).To(Succeed())
+{{- if .Resource.ConditionsPackage }}
+		Expect(conditions.IsFalse(instance, conditions.ReadyCondition)).To(BeTrue())
+{{- else }}
+		condition := {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.FindCondition(instance.Status.Conditions, {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.ConditionReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+{{- end }}
+	})
+{{- end }}
+})
+`
+
+// nolint:lll
+const fakeControllerTestTemplate = `{{ .Boilerplate }}
+
+package controllers
+
+import (
+{{- if or .Resource.Finalizer .Resource.Image }}
+	"context"
+{{- end }}
+	"testing"
+{{- if .Resource.ReconcilePeriod }}
+	"time"
+{{- end }}
+
+{{- if .Resource.Image }}
+	appsv1 "k8s.io/api/apps/v1"
+{{- end }}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+{{- if .Resource.Owns }}
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+{{- end }}
+{{- if .Resource.Events }}
+	"k8s.io/client-go/tools/record"
+{{- end }}
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+)
+
+// Test{{ .Resource.Kind }}Reconcile runs the reconciler against a fake client, so it doesn't need envtest.
+// Run it on its own with "go test -run Test{{ .Resource.Kind }}Reconcile".
+func Test{{ .Resource.Kind }}Reconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+{{- if .Resource.Owns }}
+	// The Kubernetes types of the owned resources
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+{{- end }}
+
+	key := types.NamespacedName{Name: "test-{{ .Resource.Kind | lower }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}}
+{{- if .Resource.Finalizer }}
+	now := metav1.Now()
+{{- end }}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		wantErr bool
+{{- if .Resource.ReconcilePeriod }}
+		// wantRequeueAfter is the delay after which the {{ .Resource.Kind }} is expected to be reconciled again
+		wantRequeueAfter time.Duration
+{{- end }}
+		check   func(t *testing.T, c client.Client)
+	}{
+		{
+			name: "{{ .Resource.Kind }} not found",
+		},
+		{
+			name: "{{ .Resource.Kind }} exists",
+			objects: []runtime.Object{
+				&{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+{{- if .Resource.Image }}
+					Spec: {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}Spec{
+						Image:         {{ printf "%q" .Resource.Image }},
+						ContainerPort: {{ .Resource.ImageContainerPort }},
+					},
+{{- end }}
+				},
+			},
+{{- if .Resource.ReconcilePeriod }}
+			wantRequeueAfter: {{ goDuration .Resource.ReconcilePeriod }},
+{{- end }}
+{{- if or .Resource.Finalizer .Resource.Image }}
+			check: func(t *testing.T, c client.Client) {
+{{- if .Resource.Finalizer }}
+				instance := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+				if err := c.Get(context.Background(), key, instance); err != nil {
+					t.Fatal(err)
+				}
+				if !containsFinalizer(instance, {{ .Resource.Kind | lower }}Finalizer) {
+					t.Errorf("expected the finalizer %s to be added", {{ .Resource.Kind | lower }}Finalizer)
+				}
+{{- end }}
+{{- if .Resource.Image }}
+				deployment := &appsv1.Deployment{}
+				if err := c.Get(context.Background(), key, deployment); err != nil {
+					t.Fatal(err)
+				}
+				if containers := deployment.Spec.Template.Spec.Containers; len(containers) != 1 ||
+					containers[0].Image != {{ printf "%q" .Resource.Image }} {
+					t.Errorf("expected the Deployment to run the image {{ .Resource.Image }}, got %v", containers)
+				}
+{{- end }}
+			},
+{{- end }}
+		},
+{{- if .Resource.Finalizer }}
+		{
+			name: "{{ .Resource.Kind }} being deleted",
+			objects: []runtime.Object{
+				&{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              key.Name,
+						Namespace:         key.Namespace,
+						DeletionTimestamp: &now,
+						Finalizers:        []string{ {{- .Resource.Kind | lower }}Finalizer},
+					},
+				},
+			},
+			check: func(t *testing.T, c client.Client) {
+				instance := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+				if err := c.Get(context.Background(), key, instance); err != nil {
+					t.Fatal(err)
+				}
+				if containsFinalizer(instance, {{ .Resource.Kind | lower }}Finalizer) {
+					t.Errorf("expected the finalizer %s to be removed", {{ .Resource.Kind | lower }}Finalizer)
+				}
+			},
+		},
+{{- end }}
+		// TODO(user): Add the cases of your reconcile logic
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(s, tt.objects...)
+			reconciler := &{{ .Resource.Kind }}Reconciler{
+				Client: c,
+				Log:    ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+				Scheme: s,
+{{- if .Resource.Events }}
+				Recorder: record.NewFakeRecorder(10),
+{{- end }}
+{{- if .Resource.ReconcilePeriod }}
+				ReconcilePeriod: {{ goDuration .Resource.ReconcilePeriod }},
+{{- end }}
+			}
+
+			{{ if .Resource.ReconcilePeriod }}result{{ else }}_{{ end }}, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+{{- if .Resource.ReconcilePeriod }}
+			if result.RequeueAfter != tt.wantRequeueAfter {
+				t.Errorf("Reconcile() requeues after %s, want %s", result.RequeueAfter, tt.wantRequeueAfter)
+			}
+{{- end }}
+			if tt.check != nil {
+				tt.check(t, c)
+			}
+		})
+	}
+}
+`
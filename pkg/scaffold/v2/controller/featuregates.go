@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+const (
+	featureScaffoldMarker     = "// +kubebuilder:scaffold:features"
+	featureSpecScaffoldMarker = "// +kubebuilder:scaffold:featurespecs"
+)
+
+var _ input.File = &FeatureGates{}
+
+// FeatureGates scaffolds the featuregates package, whose gates are set with the --feature-gates flag of the
+// manager, to ship the experimental reconcile paths of the controllers disabled by default
+type FeatureGates struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *FeatureGates) GetInput() (input.Input, error) {
+	// The gates of the controllers of all the groups are registered in the same package
+	if f.Path == "" {
+		f.Path = filepath.Join("featuregates", "featuregates.go")
+	}
+	f.TemplateBody = featureGatesTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// FeatureGate returns the name of the feature gate of the experimental reconcile path of the controller of a resource
+func FeatureGate(r *resource.Resource) string {
+	return fmt.Sprintf("Experimental%sReconcile", r.Kind)
+}
+
+// Fragments returns the code fragments registering the feature gate of the controller of a resource, inserted in
+// the featuregates package
+func (f *FeatureGates) Fragments(r *resource.Resource) map[string][]string {
+	gate := FeatureGate(r)
+	return map[string][]string{
+		featureScaffoldMarker: {fmt.Sprintf(`// %s gates the experimental reconcile path of the %s controller
+%s Feature = "%s"
+`, gate, r.Kind, gate, gate)},
+		featureSpecScaffoldMarker: {fmt.Sprintf(`// The experimental reconcile path of the %s controller is disabled by default
+%s: {Default: false, PreRelease: Alpha},
+`, r.Kind, gate)},
+	}
+}
+
+// nolint:lll
+const featureGatesTemplate = `{{ .Boilerplate }}
+
+// Package featuregates gates the experimental features of the controllers. The gates are set with the
+// --feature-gates flag of the manager, e.g. --feature-gates=Foo=true,Bar=false.
+package featuregates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is the name of a feature gate
+type Feature string
+
+const (
+	// Alpha features are disabled by default and may change or be removed
+	Alpha = "ALPHA"
+	// Beta features are usually enabled by default and kept once they graduate
+	Beta = "BETA"
+
+	// +kubebuilder:scaffold:features
+)
+
+// FeatureSpec describes a feature gate
+type FeatureSpec struct {
+	// Default is whether the feature is enabled when its gate is not set
+	Default bool
+	// PreRelease is the maturity of the feature, Alpha or Beta
+	PreRelease string
+}
+
+// defaultFeatureGates are the known feature gates
+var defaultFeatureGates = map[Feature]FeatureSpec{
+	// +kubebuilder:scaffold:featurespecs
+}
+
+// Gates are the feature gates set with the --feature-gates flag of the manager
+var Gates = &FeatureGate{enabled: make(map[Feature]bool)}
+
+// FeatureGate is the state of the known feature gates, it implements flag.Value
+type FeatureGate struct {
+	enabled map[Feature]bool
+}
+
+// String implements flag.Value
+func (g *FeatureGate) String() string {
+	pairs := make([]string, 0, len(g.enabled))
+	for feature, enabled := range g.enabled {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", feature, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, it parses a comma-separated list of Feature=true|false pairs
+func (g *FeatureGate) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		feature := Feature(strings.TrimSpace(kv[0]))
+		if _, found := defaultFeatureGates[feature]; !found {
+			return fmt.Errorf("unknown feature gate %s", feature)
+		}
+		if len(kv) != 2 {
+			return fmt.Errorf("missing the value of the feature gate %s", feature)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return fmt.Errorf("invalid value of the feature gate %s: %v", feature, err)
+		}
+		g.enabled[feature] = enabled
+	}
+	return nil
+}
+
+// Enabled returns whether the feature is enabled, by its gate or by default
+func (g *FeatureGate) Enabled(feature Feature) bool {
+	if enabled, found := g.enabled[feature]; found {
+		return enabled
+	}
+	return defaultFeatureGates[feature].Default
+}
+
+// Enabled returns whether the feature is enabled by the --feature-gates flag of the manager
+func Enabled(feature Feature) bool {
+	return Gates.Enabled(feature)
+}
+
+// Usage returns the usage of the --feature-gates flag, listing the known feature gates
+func Usage() string {
+	features := make([]string, 0, len(defaultFeatureGates))
+	for feature, spec := range defaultFeatureGates {
+		features = append(features, fmt.Sprintf("%s=true|false (%s - default=%t)", feature, spec.PreRelease, spec.Default))
+	}
+	sort.Strings(features)
+	return "Comma-separated list of Feature=true|false pairs that enable or disable the experimental features. " +
+		"Known features: " + strings.Join(features, ", ")
+}
+`
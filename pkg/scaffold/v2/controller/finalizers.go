@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &Finalizers{}
+
+// Finalizers scaffolds the finalizers.go file with the helpers shared by the controllers that manage finalizers
+type Finalizers struct {
+	input.Input
+
+	// Resource is the Resource to make the Controller for
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *Finalizers) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		if f.MultiGroup {
+			f.Path = filepath.Join("controllers", f.Resource.Group, "finalizers.go")
+		} else {
+			f.Path = filepath.Join("controllers", "finalizers.go")
+		}
+	}
+	f.TemplateBody = finalizersTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Finalizers) Validate() error {
+	return f.Resource.Validate()
+}
+
+const finalizersTemplate = `{{ .Boilerplate }}
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// containsFinalizer returns true if the object has the provided finalizer
+func containsFinalizer(obj metav1.Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// addFinalizer adds the provided finalizer to the object if it is not present
+func addFinalizer(obj metav1.Object, finalizer string) {
+	if !containsFinalizer(obj, finalizer) {
+		obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	}
+}
+
+// removeFinalizer removes the provided finalizer from the object
+func removeFinalizer(obj metav1.Object, finalizer string) {
+	finalizers := make([]string, 0, len(obj.GetFinalizers()))
+	for _, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	obj.SetFinalizers(finalizers)
+}
+`
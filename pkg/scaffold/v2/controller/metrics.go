@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &Metrics{}
+
+// Metrics scaffolds the Prometheus metrics that instrument the Controller of a Resource
+type Metrics struct {
+	input.Input
+
+	// Resource is the Resource to make the Controller for
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *Metrics) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		fileName := fmt.Sprintf("%s_metrics.go", strings.ToLower(f.Resource.Kind))
+		if f.MultiGroup {
+			f.Path = filepath.Join("controllers", f.Resource.Group, fileName)
+		} else {
+			f.Path = filepath.Join("controllers", fileName)
+		}
+	}
+	f.TemplateBody = metricsTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Metrics) Validate() error {
+	return f.Resource.Validate()
+}
+
+// nolint:lll
+const metricsTemplate = `{{ .Boilerplate }}
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// {{ .Resource.Kind | lower }}Reconciles counts the reconciliations of {{ .Resource.Kind }} objects
+	{{ .Resource.Kind | lower }}Reconciles = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "{{ .Resource.GroupImportSafe }}_{{ .Resource.Kind | lower }}_reconciles_total",
+		Help: "Total number of reconciliations of {{ .Resource.Kind }} objects",
+	})
+
+	// {{ .Resource.Kind | lower }}ReconcileDuration measures how long the reconciliations of {{ .Resource.Kind }} objects take
+	{{ .Resource.Kind | lower }}ReconcileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "{{ .Resource.GroupImportSafe }}_{{ .Resource.Kind | lower }}_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliations of {{ .Resource.Kind }} objects in seconds",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	// Register the metrics so that they are served by the metrics endpoint of the manager
+	metrics.Registry.MustRegister({{ .Resource.Kind | lower }}Reconciles, {{ .Resource.Kind | lower }}ReconcileDuration)
+}
+
+// observe{{ .Resource.Kind }}Reconcile records a reconciliation of a {{ .Resource.Kind }} that started at the given time
+func observe{{ .Resource.Kind }}Reconcile(start time.Time) {
+	{{ .Resource.Kind | lower }}Reconciles.Inc()
+	{{ .Resource.Kind | lower }}ReconcileDuration.Observe(time.Since(start).Seconds())
+}
+`
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Options{}
+
+// Options scaffolds the controllers/options.go file with the options, configured from flags of the manager,
+// shared by the controllers whose concurrency and rate limiting are tunable
+type Options struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Options) GetInput() (input.Input, error) {
+	// The flags are bound once, so the options are shared by the controllers of all the groups
+	if f.Path == "" {
+		f.Path = filepath.Join("controllers", "options.go")
+	}
+	f.TemplateBody = optionsTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+const optionsTemplate = `{{ .Boilerplate }}
+
+package controllers
+
+import (
+	"flag"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// DefaultMaxConcurrentReconciles is the default number of objects reconciled concurrently by each controller
+	DefaultMaxConcurrentReconciles = 1
+
+	// DefaultReconcileBaseDelay is the default delay before requeuing an object whose reconciliation failed,
+	// doubled after each consecutive failure
+	DefaultReconcileBaseDelay = 5 * time.Millisecond
+
+	// DefaultReconcileMaxDelay is the default maximum delay before requeuing an object whose reconciliation failed
+	DefaultReconcileMaxDelay = 1000 * time.Second
+)
+
+// Options configure the concurrency and the rate limiting of the controllers
+type Options struct {
+	// MaxConcurrentReconciles is the number of objects reconciled concurrently by each controller
+	MaxConcurrentReconciles int
+
+	// ReconcileBaseDelay and ReconcileMaxDelay bound the exponential backoff of the objects whose reconciliation failed
+	ReconcileBaseDelay time.Duration
+	ReconcileMaxDelay  time.Duration
+}
+
+// BindFlags binds the flags of the manager that configure the controllers
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.IntVar(&o.MaxConcurrentReconciles, "max-concurrent-reconciles", DefaultMaxConcurrentReconciles,
+		"The number of objects reconciled concurrently by each controller.")
+	fs.DurationVar(&o.ReconcileBaseDelay, "reconcile-base-delay", DefaultReconcileBaseDelay,
+		"The delay before requeuing an object whose reconciliation failed, doubled after each consecutive failure.")
+	fs.DurationVar(&o.ReconcileMaxDelay, "reconcile-max-delay", DefaultReconcileMaxDelay,
+		"The maximum delay before requeuing an object whose reconciliation failed.")
+}
+
+// ControllerOptions returns the options of a controller
+func (o Options) ControllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		// Like the default rate limiter, the exponential backoff of each object is combined with an overall limit
+		// of 10 requeues per second, with bursts of 100
+		RateLimiter: workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(o.ReconcileBaseDelay, o.ReconcileMaxDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+		),
+	}
+}
+`
@@ -19,9 +19,6 @@ package crd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
@@ -35,12 +32,15 @@ type EnableCAInjectionPatch struct {
 
 	// Resource is the Resource to make the EnableCAInjectionPatch for
 	Resource *resource.Resource
+
+	// CRDVersion is the API version of the CustomResourceDefinition to patch
+	CRDVersion string
 }
 
 // GetInput implements input.File
 func (f *EnableCAInjectionPatch) GetInput() (input.Input, error) {
 	if f.Path == "" {
-		plural := flect.Pluralize(strings.ToLower(f.Resource.Kind))
+		plural := f.Resource.Plural()
 		f.Path = filepath.Join("config", "crd", "patches",
 			fmt.Sprintf("cainjection_in_%s.yaml", plural))
 	}
@@ -54,11 +54,15 @@ func (f *EnableCAInjectionPatch) Validate() error {
 }
 
 const EnableCAInjectionPatchTemplate = `# The following patch adds a directive for certmanager to inject CA into the CRD
+{{- if eq .CRDVersion "v1" }}
+apiVersion: apiextensions.k8s.io/v1
+{{- else }}
 # CRD conversion requires k8s 1.13 or later.
 apiVersion: apiextensions.k8s.io/v1beta1
+{{- end }}
 kind: CustomResourceDefinition
 metadata:
   annotations:
     cert-manager.io/inject-ca-from: $(CERTIFICATE_NAMESPACE)/$(CERTIFICATE_NAME)
-  name: {{ .Resource.Resource }}.{{ .Resource.Group }}.{{ .Domain }}
+  name: {{ .Resource.Resource }}.{{ .Resource.QualifiedGroup .Domain }}
 `
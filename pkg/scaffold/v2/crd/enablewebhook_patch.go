@@ -19,9 +19,6 @@ package crd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
@@ -35,12 +32,15 @@ type EnableWebhookPatch struct {
 
 	// Resource is the Resource to make the EnableWebhookPatch for
 	Resource *resource.Resource
+
+	// CRDVersion is the API version of the CustomResourceDefinition to patch
+	CRDVersion string
 }
 
 // GetInput implements input.File
 func (f *EnableWebhookPatch) GetInput() (input.Input, error) {
 	if f.Path == "" {
-		plural := flect.Pluralize(strings.ToLower(f.Resource.Kind))
+		plural := f.Resource.Plural()
 		f.Path = filepath.Join("config", "crd", "patches",
 			fmt.Sprintf("webhook_in_%s.yaml", plural))
 	}
@@ -54,12 +54,35 @@ func (f *EnableWebhookPatch) Validate() error {
 }
 
 const enableWebhookPatchTemplate = `# The following patch enables conversion webhook for CRD
+{{- if eq .CRDVersion "v1" }}
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: {{ .Resource.Resource }}.{{ .Resource.QualifiedGroup .Domain }}
+spec:
+  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        # this is "\n" used as a placeholder, otherwise it will be rejected by the apiserver for being blank,
+        # but we're going to set it later using the cert-manager (or potentially a patch if not using cert-manager)
+        caBundle: Cg==
+        service:
+          namespace: system
+          name: webhook-service
+          path: /convert
+      # the conversion webhook served by controller-runtime understands v1beta1 ConversionReviews
+      conversionReviewVersions:
+      - v1beta1
+{{- else }}
 # CRD conversion requires k8s 1.13 or later.
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
-  name: {{ .Resource.Resource }}.{{ .Resource.Group }}.{{ .Domain }}
+  name: {{ .Resource.Resource }}.{{ .Resource.QualifiedGroup .Domain }}
 spec:
+  # the apiserver only calls conversion webhooks for CRDs that prune unknown fields
+  preserveUnknownFields: false
   conversion:
     strategy: Webhook
     webhookClientConfig:
@@ -70,4 +93,5 @@ spec:
         namespace: system
         name: webhook-service
         path: /convert
+{{- end }}
 `
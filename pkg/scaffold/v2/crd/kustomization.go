@@ -21,8 +21,9 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
+	"github.com/spf13/afero"
 
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
@@ -53,25 +54,86 @@ func (f *Kustomization) GetInput() (input.Input, error) {
 	return f.Input, nil
 }
 
-func (f *Kustomization) Update() error {
+func (f *Kustomization) Update(fs afero.Fs) error {
+	markerAndValues := f.Fragments()
+	return inject.Update(fs, f.Path, markerAndValues)
+}
+
+// Fragments returns the entries inserted by Update below each marker of the kustomization file, the patches
+// being commented out
+func (f *Kustomization) Fragments() map[string][]string {
 	if f.Path == "" {
 		f.Path = filepath.Join("config", "crd", "kustomization.yaml")
 	}
 
-	// TODO(directxman12): not technically valid if something changes from the default
-	// (we'd need to parse the markers)
-	plural := flect.Pluralize(strings.ToLower(f.Resource.Kind))
+	resourceFragment, webhookPatchFragment, caInjectionPatchFragment := f.codeFragments()
+
+	return map[string][]string{
+		kustomizeResourceScaffoldMarker:         {resourceFragment},
+		kustomizeWebhookPatchScaffoldMarker:     {"#" + webhookPatchFragment},
+		kustomizeCAInjectionPatchScaffoldMarker: {"#" + caInjectionPatchFragment},
+	}
+}
+
+// EnableConversion uncomments the webhook and CA injection patches of the resource so that the CRD
+// is served by the conversion webhook
+func (f *Kustomization) EnableConversion(fs afero.Fs) error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "crd", "kustomization.yaml")
+	}
+
+	_, webhookPatchFragment, caInjectionPatchFragment := f.codeFragments()
+
+	for _, target := range []string{"#" + webhookPatchFragment, "#" + caInjectionPatchFragment} {
+		if err := internal.UncommentCodeInFile(fs, f.Path, target, "#"); err != nil {
+			return fmt.Errorf("error enabling conversion in %s: %v", f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// Remove removes the entries added by Update, whether the patches were enabled or not
+func (f *Kustomization) Remove(fs afero.Fs) error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "crd", "kustomization.yaml")
+	}
+
+	resourceFragment, webhookPatchFragment, caInjectionPatchFragment := f.codeFragments()
+
+	return internal.RemoveStringsFromFile(fs, f.Path,
+		resourceFragment,
+		webhookPatchFragment,
+		"#"+webhookPatchFragment,
+		caInjectionPatchFragment,
+		"#"+caInjectionPatchFragment,
+	)
+}
+
+// HasResource returns whether the CRD of the resource is listed in the kustomization file
+func (f *Kustomization) HasResource(fs afero.Fs) (bool, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "crd", "kustomization.yaml")
+	}
+
+	content, err := afero.ReadFile(fs, f.Path)
+	if err != nil {
+		return false, err
+	}
+
+	// The entry has to be at the beginning of a line, otherwise it is commented out
+	resourceFragment, _, _ := f.codeFragments()
+	return strings.HasPrefix(string(content), resourceFragment) ||
+		strings.Contains(string(content), "\n"+resourceFragment), nil
+}
 
-	kustomizeResourceCodeFragment := fmt.Sprintf("- bases/%s.%s_%s.yaml\n", f.Resource.Group, f.Domain, plural)
-	kustomizeWebhookPatchCodeFragment := fmt.Sprintf("#- patches/webhook_in_%s.yaml\n", plural)
-	kustomizeCAInjectionPatchCodeFragment := fmt.Sprintf("#- patches/cainjection_in_%s.yaml\n", plural)
+// codeFragments returns the resource, webhook patch and CA injection patch entries for the resource
+func (f *Kustomization) codeFragments() (string, string, string) {
+	plural := f.Resource.Plural()
 
-	return internal.InsertStringsInFile(f.Path,
-		map[string][]string{
-			kustomizeResourceScaffoldMarker:         {kustomizeResourceCodeFragment},
-			kustomizeWebhookPatchScaffoldMarker:     {kustomizeWebhookPatchCodeFragment},
-			kustomizeCAInjectionPatchScaffoldMarker: {kustomizeCAInjectionPatchCodeFragment},
-		})
+	return fmt.Sprintf("- bases/%s_%s.yaml\n", f.Resource.QualifiedGroup(f.Domain), plural),
+		fmt.Sprintf("- patches/webhook_in_%s.yaml\n", plural),
+		fmt.Sprintf("- patches/cainjection_in_%s.yaml\n", plural)
 }
 
 var kustomizationTemplate = fmt.Sprintf(`# This kustomization.yaml is not intended to be run by itself,
@@ -27,6 +27,9 @@ var _ input.File = &KustomizeConfig{}
 // KustomizeConfig scaffolds the kustomizeconfig file in crd folder.
 type KustomizeConfig struct {
 	input.Input
+
+	// CRDVersion is the API version of the CustomResourceDefinitions in the crd folder
+	CRDVersion string
 }
 
 // GetInput implements input.File
@@ -46,12 +49,12 @@ nameReference:
   fieldSpecs:
   - kind: CustomResourceDefinition
     group: apiextensions.k8s.io
-    path: spec/conversion/webhookClientConfig/service/name
+    path: spec/conversion/{{ if eq .CRDVersion "v1" }}webhook/clientConfig{{ else }}webhookClientConfig{{ end }}/service/name
 
 namespace:
 - kind: CustomResourceDefinition
   group: apiextensions.k8s.io
-  path: spec/conversion/webhookClientConfig/service/namespace
+  path: spec/conversion/{{ if eq .CRDVersion "v1" }}webhook/clientConfig{{ else }}webhookClientConfig{{ end }}/service/namespace
   create: false
 
 varReference:
@@ -52,12 +52,21 @@ func (f *CRDEditorRole) Validate() error {
 
 const crdRoleEditorTemplate = `# permissions for end users to edit {{ .Resource.Resource }}.
 apiVersion: rbac.authorization.k8s.io/v1
+{{- if eq .Resource.RBACMode "namespaced" }}
+kind: Role
+{{- else }}
 kind: ClusterRole
+{{- end }}
 metadata:
   name: {{ lower .Resource.Kind }}-editor-role
+{{- if eq .Resource.RBACMode "aggregate" }}
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-admin: "true"
+    rbac.authorization.k8s.io/aggregate-to-edit: "true"
+{{- end }}
 rules:
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ .Resource.QualifiedGroup .Domain }}
   resources:
   - {{ .Resource.Resource }}
   verbs:
@@ -69,7 +78,7 @@ rules:
   - update
   - watch
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ .Resource.QualifiedGroup .Domain }}
   resources:
   - {{ .Resource.Resource }}/status
   verbs:
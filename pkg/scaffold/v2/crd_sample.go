@@ -21,10 +21,36 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/spf13/afero"
+
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
 )
 
+const samplesKustomizeResourceScaffoldMarker = "# +kubebuilder:scaffold:sampleskustomizeresource"
+
+// samplesDir is the directory of the samples, applied with kubectl apply -k config/samples
+var samplesDir = filepath.Join("config", "samples")
+
+// SamplePath returns the path of the sample of a resource, in the directory of its group in multigroup projects.
+// The samples of the resources without a group are under config/samples.
+func SamplePath(r *resource.Resource, multiGroup bool) string {
+	if multiGroup {
+		return filepath.Join(samplesDir, r.Group, fmt.Sprintf("%s_%s.yaml", r.Version, strings.ToLower(r.Kind)))
+	}
+	return filepath.Join(samplesDir, fmt.Sprintf("%s_%s_%s.yaml", r.Group, r.Version, strings.ToLower(r.Kind)))
+}
+
+// SamplePaths returns the paths the sample of a resource may be found at, the samples of multigroup projects
+// being scaffolded under config/samples before they were grouped
+func SamplePaths(r *resource.Resource, multiGroup bool) []string {
+	if multiGroup {
+		return []string{SamplePath(r, true), SamplePath(r, false)}
+	}
+	return []string{SamplePath(r, false)}
+}
+
 var _ input.File = &CRDSample{}
 
 // CRDSample scaffolds a manifest for CRD sample.
@@ -38,8 +64,7 @@ type CRDSample struct {
 // GetInput implements input.File
 func (f *CRDSample) GetInput() (input.Input, error) {
 	if f.Path == "" {
-		f.Path = filepath.Join("config", "samples", fmt.Sprintf(
-			"%s_%s_%s.yaml", f.Resource.Group, f.Resource.Version, strings.ToLower(f.Resource.Kind)))
+		f.Path = SamplePath(f.Resource, f.MultiGroup)
 	}
 
 	f.IfExistsAction = input.Error
@@ -52,11 +77,85 @@ func (f *CRDSample) Validate() error {
 	return f.Resource.Validate()
 }
 
-const crdSampleTemplate = `apiVersion: {{ .Resource.Group }}.{{ .Domain }}/{{ .Resource.Version }}
+var _ input.File = &SamplesKustomization{}
+
+// SamplesKustomization scaffolds the kustomization listing the samples, so that they are applied with
+// kubectl apply -k config/samples. The one of multigroup projects lists the directories of the groups, which
+// have their own kustomization listing their samples.
+type SamplesKustomization struct {
+	input.Input
+
+	// Group is the group whose samples are listed, empty for the kustomization of config/samples
+	Group string
+}
+
+// GetInput implements input.File
+func (f *SamplesKustomization) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(samplesDir, f.Group, "kustomization.yaml")
+	}
+	f.TemplateBody = samplesKustomizationTemplate
+	return f.Input, nil
+}
+
+// Fragments returns the entry of the sample of a resource inserted in the kustomization, or the one of the
+// directory of its group in the kustomization of config/samples of multigroup projects
+func (f *SamplesKustomization) Fragments(r *resource.Resource) map[string][]string {
+	if f.Path == "" {
+		f.Path = filepath.Join(samplesDir, f.Group, "kustomization.yaml")
+	}
+	return map[string][]string{
+		samplesKustomizeResourceScaffoldMarker: {f.codeFragment(r)},
+	}
+}
+
+// Remove removes the entry added for a resource
+func (f *SamplesKustomization) Remove(fs afero.Fs, r *resource.Resource) error {
+	if f.Path == "" {
+		f.Path = filepath.Join(samplesDir, f.Group, "kustomization.yaml")
+	}
+	return internal.RemoveStringsFromFile(fs, f.Path, f.codeFragment(r))
+}
+
+// codeFragment returns the entry of the sample of the resource, or the one of its group directory, relative
+// to the kustomization
+func (f *SamplesKustomization) codeFragment(r *resource.Resource) string {
+	if f.MultiGroup && f.Group == "" && r.Group != "" {
+		return fmt.Sprintf("- %s\n", r.Group)
+	}
+	return fmt.Sprintf("- %s\n", filepath.Base(SamplePath(r, f.MultiGroup)))
+}
+
+var samplesKustomizationTemplate = fmt.Sprintf(`# The samples applied with: kubectl apply -k config/samples
+# The ones of the new resources are appended by kubebuilder create api.
+resources:
+%s
+`, samplesKustomizeResourceScaffoldMarker)
+
+const crdSampleTemplate = `
+{{- if .Resource.ShortNames -}}
+# Once applied, list it with: kubectl get {{ index .Resource.ShortNames 0 }}
+{{ end -}}
+{{- range .Resource.Categories -}}
+# It is also listed by: kubectl get {{ . }}
+{{ end -}}
+apiVersion: {{ .Resource.QualifiedGroup .Domain }}/{{ .Resource.Version }}
 kind: {{ .Resource.Kind }}
 metadata:
   name: {{ lower .Resource.Kind }}-sample
 spec:
   # Add fields here
   foo: bar
+{{- if .Resource.Image }}
+  image: {{ .Resource.Image }}
+  replicas: 1
+  containerPort: {{ .Resource.ImageContainerPort }}
+{{- end }}
+{{- if eq .Resource.ExampleFields "rich" }}
+  size: Medium
+  hostname: {{ lower .Resource.Kind }}-sample
+  scaling:
+    minReplicas: 1
+    maxReplicas: 3
+{{- end }}
 `
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var _ = Describe("CRDSample", func() {
+	// scaffoldSample returns the sample of the resource
+	scaffoldSample := func(res *resource.Resource) string {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &scaffoldv2.CRDSample{Resource: res})).To(Succeed())
+		return scaffoldtest.ReadFile(fs, filepath.Join("config", "samples", "ship_v1_frigate.yaml"))
+	}
+
+	It("should set the image and the port of the container of the resources deploying an image", func() {
+		content := scaffoldSample(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			Image: "nginx:1.19", ImageContainerPort: 80})
+
+		Expect(content).To(ContainSubstring("image: nginx:1.19\n"))
+		Expect(content).To(ContainSubstring("containerPort: 80\n"))
+	})
+
+	It("should set the rich example fields", func() {
+		content := scaffoldSample(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			ExampleFields: resource.ExampleFieldsRich})
+
+		Expect(content).To(ContainSubstring("  scaling:\n    minReplicas: 1\n    maxReplicas: 3\n"))
+	})
+})
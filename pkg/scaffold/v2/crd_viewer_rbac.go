@@ -52,12 +52,20 @@ func (f *CRDViewerRole) Validate() error {
 
 const crdRoleViewerTemplate = `# permissions for end users to view {{ .Resource.Resource }}.
 apiVersion: rbac.authorization.k8s.io/v1
+{{- if eq .Resource.RBACMode "namespaced" }}
+kind: Role
+{{- else }}
 kind: ClusterRole
+{{- end }}
 metadata:
   name: {{ lower .Resource.Kind }}-viewer-role
+{{- if eq .Resource.RBACMode "aggregate" }}
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-view: "true"
+{{- end }}
 rules:
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ .Resource.QualifiedGroup .Domain }}
   resources:
   - {{ .Resource.Resource }}
   verbs:
@@ -65,7 +73,7 @@ rules:
   - list
   - watch
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ .Resource.QualifiedGroup .Domain }}
   resources:
   - {{ .Resource.Resource }}/status
   verbs:
@@ -0,0 +1,296 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crdimport generates the Go types of the Kinds of existing CustomResourceDefinitions, e.g. from another
+// project, so that they are adopted by the project: the types are scaffolded with the json tags of the fields of
+// their OpenAPI schema and the validation markers that controller-gen turns back into the same schema.
+package crdimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// CRD is the part of a CustomResourceDefinition, of API version apiextensions.k8s.io/v1 or v1beta1, that the types
+// of its Kind are generated from
+type CRD struct {
+	APIVersion string  `json:"apiVersion"`
+	Kind       string  `json:"kind"`
+	Spec       CRDSpec `json:"spec"`
+}
+
+// CRDSpec is the spec of a CustomResourceDefinition
+type CRDSpec struct {
+	Group    string    `json:"group"`
+	Names    Names     `json:"names"`
+	Scope    string    `json:"scope"`
+	Versions []Version `json:"versions,omitempty"`
+
+	// Version, Validation, Subresources and AdditionalPrinterColumns are the fields of v1beta1 CRDs shared by all
+	// their versions
+	Version                  string          `json:"version,omitempty"`
+	Validation               *Validation     `json:"validation,omitempty"`
+	Subresources             *Subresources   `json:"subresources,omitempty"`
+	AdditionalPrinterColumns []PrinterColumn `json:"additionalPrinterColumns,omitempty"`
+}
+
+// Names are the names of the Kind served by a CustomResourceDefinition
+type Names struct {
+	Kind       string   `json:"kind"`
+	Plural     string   `json:"plural"`
+	ShortNames []string `json:"shortNames,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// Version is a version of a CustomResourceDefinition
+type Version struct {
+	Name                     string          `json:"name"`
+	Served                   bool            `json:"served"`
+	Storage                  bool            `json:"storage"`
+	Schema                   *Validation     `json:"schema,omitempty"`
+	Subresources             *Subresources   `json:"subresources,omitempty"`
+	AdditionalPrinterColumns []PrinterColumn `json:"additionalPrinterColumns,omitempty"`
+}
+
+// Validation holds the schema of a version
+type Validation struct {
+	OpenAPIV3Schema *Schema `json:"openAPIV3Schema,omitempty"`
+}
+
+// Subresources are the subresources of a version
+type Subresources struct {
+	Status *struct{} `json:"status,omitempty"`
+	Scale  *Scale    `json:"scale,omitempty"`
+}
+
+// Scale is the scale subresource of a version
+type Scale struct {
+	SpecReplicasPath   string `json:"specReplicasPath"`
+	StatusReplicasPath string `json:"statusReplicasPath"`
+	LabelSelectorPath  string `json:"labelSelectorPath,omitempty"`
+}
+
+// PrinterColumn is a column shown by kubectl get. The path is named JSONPath in v1beta1 CRDs, which matches the
+// json tag as it is case-insensitive.
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty"`
+	JSONPath    string `json:"jsonPath"`
+}
+
+// ValidationRule is a CEL rule of a schema
+type ValidationRule struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message,omitempty"`
+}
+
+// Schema is the part of an OpenAPI v3 schema that is turned into Go types and validation markers
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *SchemaOrBool      `json:"additionalProperties,omitempty"`
+	Enum                 []json.RawMessage  `json:"enum,omitempty"`
+	Default              json.RawMessage    `json:"default,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              json.Number        `json:"minimum,omitempty"`
+	Maximum              json.Number        `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           json.Number        `json:"multipleOf,omitempty"`
+	MinLength            *int64             `json:"minLength,omitempty"`
+	MaxLength            *int64             `json:"maxLength,omitempty"`
+	MinItems             *int64             `json:"minItems,omitempty"`
+	MaxItems             *int64             `json:"maxItems,omitempty"`
+	MinProperties        *int64             `json:"minProperties,omitempty"`
+	MaxProperties        *int64             `json:"maxProperties,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+
+	XPreserveUnknownFields bool             `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XEmbeddedResource      bool             `json:"x-kubernetes-embedded-resource,omitempty"`
+	XIntOrString           bool             `json:"x-kubernetes-int-or-string,omitempty"`
+	XListType              string           `json:"x-kubernetes-list-type,omitempty"`
+	XListMapKeys           []string         `json:"x-kubernetes-list-map-keys,omitempty"`
+	XMapType               string           `json:"x-kubernetes-map-type,omitempty"`
+	XValidations           []ValidationRule `json:"x-kubernetes-validations,omitempty"`
+}
+
+// SchemaOrBool is the value of additionalProperties, either a schema or a boolean
+type SchemaOrBool struct {
+	Allows bool
+	Schema *Schema
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.Allows); err == nil {
+		return nil
+	}
+	s.Allows = true
+	return json.Unmarshal(data, &s.Schema)
+}
+
+// Versions returns the versions of the CRD, the one of spec.version for v1beta1 CRDs without versions, with
+// the schema, subresources and printer columns shared by the versions of v1beta1 CRDs
+func (c *CRD) Versions() []Version {
+	versions := c.Spec.Versions
+	if len(versions) == 0 && c.Spec.Version != "" {
+		versions = []Version{{Name: c.Spec.Version, Served: true, Storage: true}}
+	}
+
+	result := make([]Version, 0, len(versions))
+	for _, v := range versions {
+		if v.Schema == nil {
+			v.Schema = c.Spec.Validation
+		}
+		if v.Subresources == nil {
+			v.Subresources = c.Spec.Subresources
+		}
+		if v.AdditionalPrinterColumns == nil {
+			v.AdditionalPrinterColumns = c.Spec.AdditionalPrinterColumns
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// Resources returns the resources of the versions of the CRD, whose API group has to be in the domain of the
+// project
+func (c *CRD) Resources(domain string) ([]*resource.Resource, error) {
+	group := c.Spec.Group
+	switch {
+	case strings.EqualFold(group, domain):
+		group = ""
+	case strings.HasSuffix(strings.ToLower(group), "."+strings.ToLower(domain)):
+		group = resource.NormalizeGroup(group, domain)
+	default:
+		return nil, c.foreignGroupError(domain)
+	}
+
+	versions := c.Versions()
+	var storageVersion string
+	if len(versions) > 1 {
+		storageVersion = c.storageVersion()
+	}
+
+	resources := make([]*resource.Resource, 0, len(versions))
+	for _, v := range versions {
+		r := &resource.Resource{
+			Group:          group,
+			Version:        v.Name,
+			Kind:           c.Spec.Names.Kind,
+			Resource:       c.Spec.Names.Plural,
+			ShortNames:     c.Spec.Names.ShortNames,
+			Categories:     c.Spec.Names.Categories,
+			Namespaced:     c.Spec.Scope != "Cluster",
+			StorageVersion: storageVersion,
+		}
+		if v.Subresources != nil {
+			r.StatusSubresource = v.Subresources.Status != nil
+			if scale := v.Subresources.Scale; scale != nil {
+				r.Scale = &resource.ScaleSubresource{
+					SpecReplicasPath:   scale.SpecReplicasPath,
+					StatusReplicasPath: scale.StatusReplicasPath,
+					LabelSelectorPath:  scale.LabelSelectorPath,
+				}
+			}
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+// storageVersion returns the version of the CRD that objects are stored as
+func (c *CRD) storageVersion() string {
+	var storageVersion string
+	for _, v := range c.Versions() {
+		if v.Storage {
+			storageVersion = v.Name
+		}
+	}
+	return storageVersion
+}
+
+// foreignGroupError returns the error of a CRD whose group is not in the domain of the project, telling how its
+// Kind can be reconciled instead: the Go types of the group would be registered with the domain of the project, so
+// they are either imported from the project that defines them or generated in a project of their domain
+func (c *CRD) foreignGroupError(domain string) error {
+	group, groupDomain := c.Spec.Group, ""
+	if i := strings.Index(group, "."); i >= 0 {
+		group, groupDomain = c.Spec.Group[:i], c.Spec.Group[i+1:]
+	}
+	return fmt.Errorf("the group %s of %s is not in the domain %s of the project, the Go types of the Kinds of "+
+		"other domains can't be generated: reconcile them with kubebuilder create controller --group %s "+
+		"--version %s --kind %s --external-api-path <package of their Go types> --external-api-domain %s, "+
+		"or generate them in a project initialized with --domain %s",
+		c.Spec.Group, c.Spec.Names.Kind, domain, group, c.storageVersion(), c.Spec.Names.Kind, groupDomain,
+		groupDomain)
+}
+
+// documentSeparator separates the documents of a YAML stream
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// Load returns the CustomResourceDefinitions of the YAML content, which may have several documents, e.g. the
+// output of kubectl get crd -o yaml, the other objects are ignored
+func Load(content []byte) ([]*CRD, error) {
+	var crds []*CRD
+	for _, document := range documentSeparator.Split(string(content), -1) {
+		if len(bytes.TrimSpace([]byte(document))) == 0 {
+			continue
+		}
+
+		var object struct {
+			Kind  string            `json:"kind"`
+			Items []json.RawMessage `json:"items,omitempty"`
+		}
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil {
+			return nil, err
+		}
+		documents := []json.RawMessage{json.RawMessage(document)}
+		if object.Kind == "List" {
+			documents = object.Items
+		}
+
+		for _, d := range documents {
+			crd := &CRD{}
+			if err := yaml.Unmarshal(d, crd); err != nil {
+				return nil, err
+			}
+			if crd.Kind != "CustomResourceDefinition" || !strings.HasPrefix(crd.APIVersion, "apiextensions.k8s.io/") {
+				continue
+			}
+			if crd.Spec.Names.Kind == "" {
+				return nil, fmt.Errorf("the CustomResourceDefinition has no kind in spec.names")
+			}
+			crds = append(crds, crd)
+		}
+	}
+	return crds, nil
+}
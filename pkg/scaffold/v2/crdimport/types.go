@@ -0,0 +1,778 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/gobuffalo/flect"
+	"golang.org/x/tools/imports"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+const (
+	metav1Import  = `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`
+	runtimeImport = `"k8s.io/apimachinery/pkg/runtime"`
+	intstrImport  = `"k8s.io/apimachinery/pkg/util/intstr"`
+)
+
+// Plugin replaces the types scaffolded for the versions of the Kind of a CRD with the ones generated from their
+// schema, and the example spec of their samples with the required fields of the schema. It implements
+// scaffold.Plugin.
+type Plugin struct {
+	CRD *CRD
+}
+
+// Pipe implements scaffold.Plugin
+func (p Plugin) Pipe(u *model.Universe) error {
+	if u.Resource == nil || u.Resource.Kind != p.CRD.Spec.Names.Kind {
+		return nil
+	}
+
+	typesPath := u.TypesPath()
+	samplePath := scaffoldv2.SamplePath(&resource.Resource{
+		Group:   u.Resource.Group,
+		Version: u.Resource.Version,
+		Kind:    u.Resource.Kind,
+	}, u.Config != nil && u.Config.MultiGroup)
+	for i, f := range u.Files {
+		switch f.Path {
+		case typesPath:
+			contents, warnings, err := p.CRD.GoTypes(f.Path, u.Resource.Version, u.Boilerplate)
+			if err != nil {
+				return err
+			}
+			for _, warning := range warnings {
+				fmt.Printf("Warning: %s\n", warning)
+			}
+			u.Files[i] = &model.File{Path: f.Path, Contents: contents, IfExistsAction: f.IfExistsAction}
+		case samplePath:
+			spec, err := p.CRD.SampleSpec(u.Resource.Version)
+			if err != nil {
+				return err
+			}
+			u.Files[i] = &model.File{
+				Path:           f.Path,
+				Contents:       strings.Replace(f.Contents, sampleSpec, spec, 1),
+				IfExistsAction: f.IfExistsAction,
+				Template:       f.Template,
+				Plugin:         f.Plugin,
+			}
+		}
+	}
+	return nil
+}
+
+// sampleSpec is the example spec of the samples, as scaffolded by the CRDSample template
+const sampleSpec = "spec:\n  # Add fields here\n  foo: bar\n"
+
+// SampleSpec returns the spec of the sample of a version of the CRD in YAML, with the required fields of its schema
+// set to their default, their first allowed value or the zero value of their type. It is empty if the schema has
+// no spec.
+func (c *CRD) SampleSpec(version string) (string, error) {
+	v, err := c.version(version)
+	if err != nil {
+		return "", err
+	}
+	if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+		return "", nil
+	}
+	spec, found := v.Schema.OpenAPIV3Schema.Properties["spec"]
+	if !found {
+		return "", nil
+	}
+
+	content, err := yaml.Marshal(map[string]interface{}{"spec": sampleValue(spec)})
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// sampleValue returns a value that the schema accepts, the objects only having their required properties
+func sampleValue(s *Schema) interface{} {
+	if len(s.Default) != 0 {
+		var value interface{}
+		if err := json.Unmarshal(s.Default, &value); err == nil {
+			return value
+		}
+	}
+	if len(s.Enum) != 0 {
+		var value interface{}
+		if err := json.Unmarshal(s.Enum[0], &value); err == nil {
+			return value
+		}
+	}
+
+	switch {
+	case s.XIntOrString:
+		return 0
+	case s.Type == "object":
+		value := map[string]interface{}{}
+		for _, name := range s.Required {
+			if property, found := s.Properties[name]; found {
+				value[name] = sampleValue(property)
+			}
+		}
+		return value
+	case s.Type == "array":
+		value := []interface{}{}
+		if s.MinItems != nil && *s.MinItems > 0 && s.Items != nil {
+			value = append(value, sampleValue(s.Items))
+		}
+		return value
+	case s.Type == "integer", s.Type == "number":
+		minimum, err := s.Minimum.Float64()
+		if s.Minimum == "" || err != nil {
+			return 0
+		}
+		if s.ExclusiveMinimum {
+			minimum++
+		}
+		return minimum
+	case s.Type == "boolean":
+		return false
+	case s.Type == "string" && s.Format == "date-time":
+		return "1970-01-01T00:00:00Z"
+	case s.Type == "string":
+		return ""
+	}
+	return map[string]interface{}{}
+}
+
+// GoTypes returns the Go source of the types file at path of a version of the CRD, with the warnings about the
+// parts of the schema that the types don't reproduce
+func (c *CRD) GoTypes(path, version, boilerplate string) (string, []string, error) {
+	v, err := c.version(version)
+	if err != nil {
+		return "", nil, err
+	}
+
+	b := &typesBuilder{
+		kind:    c.Spec.Names.Kind,
+		names:   map[string]bool{c.Spec.Names.Kind: true, c.Spec.Names.Kind + "List": true},
+		structs: map[string]bool{},
+		imports: map[string]bool{metav1Import: true},
+	}
+	root := b.rootType(c, v)
+
+	file := typesFile{
+		Boilerplate: boilerplate,
+		Version:     version,
+		Kind:        c.Spec.Names.Kind,
+		Types:       append(b.types, root),
+	}
+	for i := range b.imports {
+		file.Imports = append(file.Imports, i)
+	}
+	sort.Strings(file.Imports)
+
+	out := &bytes.Buffer{}
+	if err := typesTemplate.Execute(out, file); err != nil {
+		return "", nil, err
+	}
+	contents, err := imports.Process(path, out.Bytes(), formatOptions)
+	if err != nil {
+		return "", nil, fmt.Errorf("error formatting the types of %s: %v", c.Spec.Names.Kind, err)
+	}
+	return string(contents), b.warnings, nil
+}
+
+// formatOptions only format the types, whose imports are the ones they use
+var formatOptions = &imports.Options{Comments: true, TabIndent: true, TabWidth: 8, FormatOnly: true}
+
+// version returns the version of the CRD with the name
+func (c *CRD) version(name string) (*Version, error) {
+	for _, v := range c.Versions() {
+		if v.Name == name {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no version %s", c.Spec.Names.Kind, name)
+}
+
+// typesFile is the model of the types file of a version
+type typesFile struct {
+	Boilerplate string
+	Version     string
+	Kind        string
+	Imports     []string
+	Types       []*goType
+}
+
+// goType is a type declaration of the types file
+type goType struct {
+	Name string
+	// Underlying is the type of the named scalar types, empty for structs
+	Underlying string
+	// Comments are the lines of the comment above the declaration, with its markers
+	Comments []string
+	Fields   []*goField
+}
+
+// goField is a field of a struct, whose Name is empty if it is embedded
+type goField struct {
+	Name     string
+	Type     string
+	Tag      string
+	Comments []string
+	// Separate is true if a blank line separates the field from the previous one, e.g. if it has comments
+	Separate bool
+}
+
+// typesBuilder collects the type declarations of a schema
+type typesBuilder struct {
+	kind string
+	// types are the declarations other than the one of the Kind, parents first
+	types []*goType
+	// names are the names of the declared types, structs the ones that are structs
+	names   map[string]bool
+	structs map[string]bool
+	imports map[string]bool
+
+	warnings []string
+}
+
+// rootType returns the declaration of the Kind, declaring the types of its fields
+func (b *typesBuilder) rootType(c *CRD, v *Version) *goType {
+	schema := &Schema{Type: "object", XPreserveUnknownFields: true}
+	if v.Schema != nil && v.Schema.OpenAPIV3Schema != nil {
+		schema = v.Schema.OpenAPIV3Schema
+	}
+
+	markers := []string{"+kubebuilder:object:root=true"}
+	if !v.Served {
+		markers = append(markers, "+kubebuilder:unservedversion")
+	}
+	if v.Storage && len(c.Versions()) > 1 {
+		markers = append(markers, "+kubebuilder:storageversion")
+	}
+	if v.Subresources != nil && v.Subresources.Status != nil {
+		markers = append(markers, "+kubebuilder:subresource:status")
+	}
+	if v.Subresources != nil && v.Subresources.Scale != nil {
+		scale := v.Subresources.Scale
+		args := []string{"specpath=" + scale.SpecReplicasPath, "statuspath=" + scale.StatusReplicasPath}
+		if scale.LabelSelectorPath != "" {
+			args = append(args, "selectorpath="+scale.LabelSelectorPath)
+		}
+		markers = append(markers, templatefuncs.Marker("kubebuilder:subresource:scale", args...))
+	}
+	args := []string{"path=" + c.Spec.Names.Plural}
+	if c.Spec.Scope == "Cluster" {
+		args = append(args, "scope=Cluster")
+	}
+	if len(c.Spec.Names.ShortNames) != 0 {
+		args = append(args, templatefuncs.MarkerArg("shortName", c.Spec.Names.ShortNames...))
+	}
+	if len(c.Spec.Names.Categories) != 0 {
+		args = append(args, templatefuncs.MarkerArg("categories", c.Spec.Names.Categories...))
+	}
+	markers = append(markers, templatefuncs.Marker("kubebuilder:resource", args...))
+	for _, column := range v.AdditionalPrinterColumns {
+		args := []string{"name=" + strconv.Quote(column.Name), "type=" + strconv.Quote(column.Type),
+			"JSONPath=" + strconv.Quote(column.JSONPath)}
+		if column.Format != "" {
+			args = append(args, "format="+strconv.Quote(column.Format))
+		}
+		if column.Priority != 0 {
+			args = append(args, fmt.Sprintf("priority=%d", column.Priority))
+		}
+		if column.Description != "" {
+			args = append(args, "description="+strconv.Quote(column.Description))
+		}
+		markers = append(markers, templatefuncs.Marker("kubebuilder:printcolumn", args...))
+	}
+	markers = append(markers, b.markers(schema, "the schema of "+b.kind)...)
+
+	doc := schema.Description
+	if doc == "" {
+		doc = fmt.Sprintf("%s is the Schema for the %s API", b.kind, c.Spec.Names.Plural)
+	}
+	root := &goType{Name: b.kind, Comments: append(comments(markers...), append([]string{""}, docLines(doc)...)...)}
+	root.Fields = []*goField{
+		{Type: "metav1.TypeMeta", Tag: jsonTag("", true)},
+		{Type: "metav1.ObjectMeta", Tag: jsonTag("metadata", true)},
+	}
+	for i, name := range rootProperties(schema) {
+		prop := schema.Properties[name]
+		field := b.field(b.kind, name, prop, isRequired(schema, name), "."+name)
+		if name == "spec" || name == "status" {
+			// The spec and the status are values, like in the scaffolded types, and optional unless required
+			field.Type = strings.TrimPrefix(field.Type, "*")
+			field.Comments = removeString(field.Comments, "// +optional")
+		}
+		field.Separate = i == 0 || len(field.Comments) != 0
+		root.Fields = append(root.Fields, field)
+	}
+	return root
+}
+
+// field returns the field of the property of a struct, declaring its type
+func (b *typesBuilder) field(parent, name string, s *Schema, required bool, path string) *goField {
+	goName := fieldName(name)
+	f := &goField{Name: goName, Tag: jsonTag(name, !required), Comments: docLines(s.Description)}
+
+	switch {
+	case name == "metadata" && s.Type == "object" && len(s.Properties) == 0:
+		// The metadata of embedded objects, e.g. the Pod template of a Deployment
+		f.Type = "metav1.ObjectMeta"
+	case path == ".spec" || path == ".status":
+		f.Type = b.typeOf(s, []string{b.kind + goName}, path)
+	default:
+		f.Type = b.typeOf(s, []string{b.kind + goName, parent + goName}, path)
+	}
+
+	f.Comments = append(f.Comments, comments(b.markers(s, path)...)...)
+	if !required {
+		f.Comments = append(f.Comments, "// +optional")
+		if b.structs[f.Type] || pointerTypes[f.Type] {
+			f.Type = "*" + f.Type
+		}
+	}
+	f.Separate = len(f.Comments) != 0
+	return f
+}
+
+// pointerTypes are the struct types of the libraries that optional fields point to
+var pointerTypes = map[string]bool{
+	"metav1.Time":          true,
+	"runtime.RawExtension": true,
+	"intstr.IntOrString":   true,
+}
+
+// typeOf returns the Go type of the schema at path, declaring it with the first of the names that is available
+// if needed
+func (b *typesBuilder) typeOf(s *Schema, names []string, path string) string {
+	switch {
+	case s.XIntOrString:
+		b.imports[intstrImport] = true
+		return "intstr.IntOrString"
+	case s.XEmbeddedResource, s.Type == "", s.Type == "object" && len(s.Properties) == 0 &&
+		s.AdditionalProperties == nil && s.XPreserveUnknownFields:
+		b.imports[runtimeImport] = true
+		return "runtime.RawExtension"
+	}
+
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "metav1.Time"
+		case "byte":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		b.warnf("%s is a number, whose float64 field requires controller-gen to be run with "+
+			"crd:allowDangerousTypes=true", path)
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items := s.Items
+		if items == nil {
+			items = &Schema{}
+		}
+		return "[]" + b.elemType(items, singular(names), path+"[*]")
+	case "object":
+		if len(s.Properties) == 0 && s.AdditionalProperties != nil {
+			values := s.AdditionalProperties.Schema
+			if values == nil {
+				values = &Schema{}
+			}
+			return "map[string]" + b.elemType(values, singular(names), path+"[*]")
+		}
+		return b.structType(s, names, path)
+	}
+
+	b.warnf("%s has the unknown type %s, replaced by runtime.RawExtension", path, s.Type)
+	b.imports[runtimeImport] = true
+	return "runtime.RawExtension"
+}
+
+// elemType returns the type of the items of a list or the values of a map. The scalars with validations are
+// declared as named types holding their markers, e.g. a string type with an Enum marker.
+func (b *typesBuilder) elemType(s *Schema, names []string, path string) string {
+	t := b.typeOf(s, names, path)
+	markers := b.markers(s, path)
+	if len(markers) == 0 || !scalarTypes[t] {
+		return t
+	}
+
+	named := &goType{Name: b.uniqueName(names), Underlying: t}
+	named.Comments = append(docLines(s.Description), comments(markers...)...)
+	if s.Description == "" {
+		named.Comments = append([]string{fmt.Sprintf("// %s is an item of %s", named.Name, path)}, named.Comments...)
+	}
+	b.types = append(b.types, named)
+	return named.Name
+}
+
+// scalarTypes are the types that the named types of the items with validations are declared with
+var scalarTypes = map[string]bool{"string": true, "int32": true, "int64": true, "float64": true, "bool": true}
+
+// structType declares the struct of the properties of an object and returns its name
+func (b *typesBuilder) structType(s *Schema, names []string, path string) string {
+	t := &goType{Name: b.uniqueName(names)}
+	switch path {
+	case ".spec":
+		t.Comments = []string{fmt.Sprintf("// %s defines the desired state of %s", t.Name, b.kind)}
+	case ".status":
+		t.Comments = []string{fmt.Sprintf("// %s defines the observed state of %s", t.Name, b.kind)}
+	default:
+		t.Comments = []string{fmt.Sprintf("// %s is generated from the schema of %s", t.Name, path)}
+	}
+	b.types = append(b.types, t)
+	b.structs[t.Name] = true
+
+	for _, name := range sortedProperties(s) {
+		t.Fields = append(t.Fields, b.field(t.Name, name, s.Properties[name], isRequired(s, name), path+"."+name))
+	}
+	return t.Name
+}
+
+// uniqueName returns the first of the names that isn't taken, or the last one followed by a number
+func (b *typesBuilder) uniqueName(names []string) string {
+	name := names[len(names)-1]
+	for _, candidate := range names {
+		if !b.names[candidate] {
+			name = candidate
+			break
+		}
+	}
+	for i := 2; b.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", names[len(names)-1], i)
+	}
+	b.names[name] = true
+	return name
+}
+
+// markers returns the validation markers of the schema at path
+func (b *typesBuilder) markers(s *Schema, path string) []string {
+	var markers []string
+	add := func(format string, args ...interface{}) {
+		markers = append(markers, "+"+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Enum) != 0 {
+		values := make([]string, 0, len(s.Enum))
+		for _, raw := range s.Enum {
+			value, ok := markerValue(raw)
+			if !ok {
+				b.warnf("the enum of %s isn't scaffolded as it has values that aren't scalars", path)
+				values = nil
+				break
+			}
+			values = append(values, value)
+		}
+		if len(values) != 0 {
+			add("kubebuilder:validation:Enum=%s", strings.Join(values, ";"))
+		}
+	}
+	if s.Format != "" && !impliedFormats[s.Format] {
+		add("kubebuilder:validation:Format=%s", s.Format)
+	}
+	if s.Pattern != "" {
+		add("kubebuilder:validation:Pattern=%s", quotePattern(s.Pattern))
+	}
+	if s.Minimum != "" {
+		add("kubebuilder:validation:Minimum=%s", s.Minimum)
+	}
+	if s.ExclusiveMinimum {
+		add("kubebuilder:validation:ExclusiveMinimum=true")
+	}
+	if s.Maximum != "" {
+		add("kubebuilder:validation:Maximum=%s", s.Maximum)
+	}
+	if s.ExclusiveMaximum {
+		add("kubebuilder:validation:ExclusiveMaximum=true")
+	}
+	if s.MultipleOf != "" {
+		add("kubebuilder:validation:MultipleOf=%s", s.MultipleOf)
+	}
+	for _, limit := range []struct {
+		name  string
+		value *int64
+	}{
+		{"MinLength", s.MinLength}, {"MaxLength", s.MaxLength},
+		{"MinItems", s.MinItems}, {"MaxItems", s.MaxItems},
+		{"MinProperties", s.MinProperties}, {"MaxProperties", s.MaxProperties},
+	} {
+		if limit.value != nil {
+			add("kubebuilder:validation:%s=%d", limit.name, *limit.value)
+		}
+	}
+	if s.UniqueItems {
+		add("kubebuilder:validation:UniqueItems=true")
+	}
+	if s.XListType != "" {
+		add("listType=%s", s.XListType)
+	}
+	for _, key := range s.XListMapKeys {
+		add("listMapKey=%s", key)
+	}
+	if s.XMapType != "" {
+		add("mapType=%s", s.XMapType)
+	}
+	if s.Nullable {
+		add("nullable")
+	}
+	if len(s.Default) != 0 {
+		if value, ok := markerValue(s.Default); ok {
+			add("kubebuilder:default=%s", value)
+		} else {
+			b.warnf("the default value of %s isn't scaffolded, add its +kubebuilder:default marker", path)
+		}
+	}
+	if s.XEmbeddedResource {
+		add("kubebuilder:validation:EmbeddedResource")
+	}
+	if s.XPreserveUnknownFields {
+		add("kubebuilder:pruning:PreserveUnknownFields")
+	}
+	for _, rule := range s.XValidations {
+		args := []string{"rule=" + strconv.Quote(rule.Rule)}
+		if rule.Message != "" {
+			args = append(args, "message="+strconv.Quote(rule.Message))
+		}
+		add("kubebuilder:validation:XValidation:%s", strings.Join(args, ","))
+	}
+	return markers
+}
+
+// impliedFormats are the formats that the Go types of the fields already set in the schema
+var impliedFormats = map[string]bool{
+	"date-time": true, "byte": true, "int32": true, "int64": true, "int-or-string": true,
+}
+
+func (b *typesBuilder) warnf(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+// simpleValue matches the strings that can be written in markers without quotes
+var simpleValue = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_./-]*$`)
+
+// markerValue returns a JSON value in the syntax of the marker arguments: scalars, lists of scalars and empty
+// objects. Strings are quoted unless they can't be mistaken for another type.
+func markerValue(raw json.RawMessage) (string, bool) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		if simpleValue.MatchString(v) && v != "true" && v != "false" {
+			return v, true
+		}
+		return strconv.Quote(v), true
+	case float64, bool:
+		return string(bytes.TrimSpace(raw)), true
+	case map[string]interface{}:
+		return "{}", len(v) == 0
+	case []interface{}:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return "", false
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			value, ok := markerValue(item)
+			if !ok || value == "{}" {
+				return "", false
+			}
+			values = append(values, value)
+		}
+		return "{" + strings.Join(values, ",") + "}", true
+	}
+	return "", false
+}
+
+// quotePattern returns a regular expression as a raw string unless it has a backquote
+func quotePattern(pattern string) string {
+	if strings.Contains(pattern, "`") {
+		return strconv.Quote(pattern)
+	}
+	return "`" + pattern + "`"
+}
+
+// fieldName returns the Go name of the field of a property
+func fieldName(name string) string {
+	goName := templatefuncs.GoName(name)
+	if goName == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(goName)[0]) {
+		return "X" + goName
+	}
+	return goName
+}
+
+// singular returns the names of the items of a list or map from the names of its type, e.g. FrigatePort for
+// FrigatePorts. The names ending with an initialism are kept.
+func singular(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if runes := []rune(name); unicode.IsLower(runes[len(runes)-1]) {
+			name = flect.Singularize(name)
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// rootProperties returns the properties of the Kind other than the ones of its TypeMeta and ObjectMeta, the spec
+// and the status first
+func rootProperties(s *Schema) []string {
+	properties := make([]string, 0, len(s.Properties))
+	for _, name := range []string{"spec", "status"} {
+		if _, found := s.Properties[name]; found {
+			properties = append(properties, name)
+		}
+	}
+	for _, name := range sortedProperties(s) {
+		switch name {
+		case "apiVersion", "kind", "metadata", "spec", "status":
+			continue
+		}
+		properties = append(properties, name)
+	}
+	return properties
+}
+
+func sortedProperties(s *Schema) []string {
+	properties := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		properties = append(properties, name)
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+func isRequired(s *Schema, name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// jsonTag returns the json tag of a field, which is inlined if it has no name
+func jsonTag(name string, omitEmpty bool) string {
+	switch {
+	case name == "":
+		return "`json:\",inline\"`"
+	case omitEmpty:
+		return fmt.Sprintf("`json:\"%s,omitempty\"`", name)
+	}
+	return fmt.Sprintf("`json:\"%s\"`", name)
+}
+
+// docLines returns the comment lines of a description
+func docLines(description string) []string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		lines = append(lines, strings.TrimRight("// "+line, " \t"))
+	}
+	return lines
+}
+
+// comments returns the comment lines of markers
+func comments(markers ...string) []string {
+	lines := make([]string, 0, len(markers))
+	for _, marker := range markers {
+		lines = append(lines, "// "+marker)
+	}
+	return lines
+}
+
+var typesTemplate = template.Must(template.New("types").Parse(`{{ .Boilerplate }}
+
+package {{ .Version }}
+
+import (
+{{- range .Imports }}
+	{{ . }}
+{{- end }}
+)
+
+// The types of {{ .Kind }} were generated from the schema of its CustomResourceDefinition, edit them like the
+// scaffolded ones and run make to regenerate the CRD from them.
+{{ range .Types }}
+{{ range .Comments }}{{ . }}
+{{ end -}}
+{{ if .Underlying -}}
+type {{ .Name }} {{ .Underlying }}
+{{ else -}}
+type {{ .Name }} struct {
+{{- range $i, $field := .Fields }}
+{{ if and $i $field.Separate }}
+{{ end -}}
+{{ range $field.Comments }}{{ . }}
+{{ end -}}
+{{ $field.Name }} {{ $field.Type }} {{ $field.Tag }}
+{{- end }}
+}
+{{ end -}}
+{{ end }}
+// +kubebuilder:object:root=true
+
+// {{ .Kind }}List contains a list of {{ .Kind }}
+type {{ .Kind }}List struct {
+	metav1.TypeMeta ` + "`" + `json:",inline"` + "`" + `
+	metav1.ListMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+	Items           []{{ .Kind }} ` + "`" + `json:"items"` + "`" + `
+}
+
+func init() {
+	SchemeBuilder.Register(&{{ .Kind }}{}, &{{ .Kind }}List{})
+}
+`))
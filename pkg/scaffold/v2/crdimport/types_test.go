@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdimport
+
+import (
+	"strings"
+	"testing"
+)
+
+const frigateCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: frigates.ship.example.com
+spec:
+  group: ship.example.com
+  names:
+    kind: Frigate
+    plural: frigates
+    shortNames: [fr]
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    subresources:
+      status: {}
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          spec:
+            type: object
+            required: [size]
+            properties:
+              size:
+                description: Size of the frigate
+                type: string
+                enum: [Small, Large]
+                default: Small
+              replicas:
+                type: integer
+                format: int32
+                minimum: 1
+              ipAddresses:
+                type: array
+                items:
+                  type: string
+                  format: ipv4
+              ports:
+                type: array
+                x-kubernetes-list-type: map
+                x-kubernetes-list-map-keys: [name]
+                items:
+                  type: object
+                  required: [name]
+                  properties:
+                    name:
+                      type: string
+              timeout:
+                x-kubernetes-int-or-string: true
+          status:
+            type: object
+            properties:
+              lastUpdate:
+                type: string
+                format: date-time
+`
+
+func TestGoTypes(t *testing.T) {
+	crds, err := Load([]byte(frigateCRD))
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(crds) != 1 {
+		t.Fatalf("Load() returned %d CRDs, expected 1", len(crds))
+	}
+
+	contents, warnings, err := crds[0].GoTypes("frigate_types.go", "v1", "")
+	if err != nil {
+		t.Fatalf("GoTypes() failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("GoTypes() returned the warnings %v", warnings)
+	}
+	for _, expected := range []string{
+		"// +kubebuilder:object:root=true\n// +kubebuilder:subresource:status\n" +
+			"// +kubebuilder:resource:path=frigates,shortName=fr\n\n// Frigate is the Schema for the frigates API\n",
+		"\tSpec   FrigateSpec   `json:\"spec,omitempty\"`\n\tStatus FrigateStatus `json:\"status,omitempty\"`\n",
+		"\t// Size of the frigate\n\t// +kubebuilder:validation:Enum=Small;Large\n\t// +kubebuilder:default=Small\n" +
+			"\tSize string `json:\"size\"`\n",
+		"\t// +kubebuilder:validation:Minimum=1\n\t// +optional\n\tReplicas int32 `json:\"replicas,omitempty\"`\n",
+		"\tIPAddresses []FrigateIPAddress `json:\"ipAddresses,omitempty\"`\n",
+		"// +kubebuilder:validation:Format=ipv4\ntype FrigateIPAddress string\n",
+		"\t// +listType=map\n\t// +listMapKey=name\n\t// +optional\n\tPorts []FrigatePort `json:\"ports,omitempty\"`\n",
+		"type FrigatePort struct {\n\tName string `json:\"name\"`\n}\n",
+		"\tTimeout *intstr.IntOrString `json:\"timeout,omitempty\"`\n",
+		"\tLastUpdate *metav1.Time `json:\"lastUpdate,omitempty\"`\n",
+		"\tSchemeBuilder.Register(&Frigate{}, &FrigateList{})\n",
+	} {
+		if !strings.Contains(contents, expected) {
+			t.Errorf("GoTypes() returned\n%s\nwithout\n%s", contents, expected)
+		}
+	}
+
+	spec, err := crds[0].SampleSpec("v1")
+	if err != nil {
+		t.Fatalf("SampleSpec() failed: %v", err)
+	}
+	if spec != "spec:\n  size: Small\n" {
+		t.Errorf("SampleSpec() returned %q", spec)
+	}
+}
+
+const destroyerCRDs = `apiVersion: v1
+kind: List
+items:
+- apiVersion: apiextensions.k8s.io/v1beta1
+  kind: CustomResourceDefinition
+  spec:
+    group: example.com
+    names:
+      kind: Destroyer
+      plural: destroyers
+    scope: Cluster
+    versions:
+    - name: v1beta1
+      served: true
+      storage: false
+    - name: v1
+      served: true
+      storage: true
+    validation:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              ratio:
+                type: number
+- apiVersion: v1
+  kind: ConfigMap
+`
+
+func TestResources(t *testing.T) {
+	crds, err := Load([]byte(destroyerCRDs))
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(crds) != 1 {
+		t.Fatalf("Load() returned %d CRDs, expected 1", len(crds))
+	}
+
+	resources, err := crds[0].Resources("example.com")
+	if err != nil {
+		t.Fatalf("Resources() failed: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Resources() returned %d resources, expected 2", len(resources))
+	}
+	for _, r := range resources {
+		if r.Group != "" || r.Kind != "Destroyer" || r.Namespaced || r.StorageVersion != "v1" {
+			t.Errorf("Resources() returned %+v", r)
+		}
+	}
+
+	// The versions of v1beta1 CRDs share the schema
+	contents, warnings, err := crds[0].GoTypes("destroyer_types.go", "v1beta1", "")
+	if err != nil {
+		t.Fatalf("GoTypes() failed: %v", err)
+	}
+	if !strings.Contains(contents, "\tRatio float64 `json:\"ratio,omitempty\"`\n") {
+		t.Errorf("GoTypes() returned\n%s\nwithout the ratio field", contents)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("GoTypes() returned the warnings %v, expected the one of the number", warnings)
+	}
+
+	_, err = crds[0].Resources("example.org")
+	if err == nil {
+		t.Fatal("Resources() succeeded for a group outside of the domain")
+	}
+	// The error tells how to reconcile the Kind with its existing types
+	if !strings.Contains(err.Error(), "kubebuilder create controller --group example --version v1 --kind Destroyer "+
+		"--external-api-path <package of their Go types> --external-api-domain com") {
+		t.Errorf("Resources() returned the error %q without the controller command", err)
+	}
+}
@@ -17,6 +17,13 @@ limitations under the License.
 package v2
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
@@ -25,6 +32,26 @@ var _ input.File = &Dockerfile{}
 // Dockerfile scaffolds a Dockerfile for building a main
 type Dockerfile struct {
 	input.Input
+
+	// APIServer is true if the main runs an aggregated API server instead of the controllers
+	APIServer bool
+
+	// BaseImage is the base image of the manager image, one of config.BaseImageDistroless (default),
+	// config.BaseImageScratch or config.BaseImageUBI
+	BaseImage string
+
+	// ComponentConfig is true if the manager loads its options with the managerconfig package
+	ComponentConfig bool
+
+	// ModuleDir is the directory of the project in the module of a go.mod shared with the rest of a monorepo,
+	// e.g. operators/foo, whose root is the build context of the image. Empty if the project has its own go.mod.
+	ModuleDir string
+
+	// Vendor is true if the dependencies are vendored, they are copied from vendor/ instead of being downloaded
+	Vendor bool
+
+	// Sharding is true if the manager imports the sharding package
+	Sharding bool
 }
 
 // GetInput implements input.File
@@ -32,28 +59,117 @@ func (f *Dockerfile) GetInput() (input.Input, error) {
 	if f.Path == "" {
 		f.Path = "Dockerfile"
 	}
+	if f.BaseImage == "" {
+		f.BaseImage = config.BaseImageDistroless
+	}
 	f.TemplateBody = dockerfileTemplate
 	return f.Input, nil
 }
 
+// CopyPackages copies the directories of the Go packages scaffolded outside of the api and controllers ones,
+// e.g. featuregates, into the builder image. It returns true if the Dockerfile was modified.
+func (f *Dockerfile) CopyPackages(fs afero.Fs, dirs ...string) (bool, error) {
+	if f.Path == "" {
+		f.Path = "Dockerfile"
+	}
+
+	content, err := afero.ReadFile(fs, f.Path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	updated := string(content)
+	for _, dir := range dirs {
+		instruction := fmt.Sprintf("COPY %s/ %s/\n", dir, dir)
+		if strings.Contains(updated, instruction) || strings.Contains(updated, dockerfileCopyModule) {
+			continue
+		}
+		if !strings.Contains(updated, dockerfileCopyMain) {
+			return false, fmt.Errorf("unable to copy %s in %s, %q not found", dir, f.Path, dockerfileCopyMain)
+		}
+		updated = strings.Replace(updated, dockerfileCopyMain, dockerfileCopyMain+instruction, 1)
+	}
+	if updated == string(content) {
+		return false, nil
+	}
+
+	return true, afero.WriteFile(fs, f.Path, []byte(updated), 0644)
+}
+
+// dockerfileCopyMain is the instruction the packages are copied after
+const dockerfileCopyMain = "COPY main.go main.go\n"
+
+// dockerfileCopyModule is the instruction copying the whole module of a shared go.mod, packages included
+const dockerfileCopyModule = "COPY . .\n"
+
+// nolint:lll
 const dockerfileTemplate = `# Build the manager binary
 FROM golang:1.13 as builder
+# Set by docker buildx to the platform of the image, make docker-buildx builds
+# the image for multiple platforms.
+ARG TARGETOS
+ARG TARGETARCH
 
 WORKDIR /workspace
 # Copy the Go Modules manifests
 COPY go.mod go.mod
 COPY go.sum go.sum
+{{- if .Vendor }}
+{{- if not .ModuleDir }}
+# Copy the vendored dependencies, the image is built without downloading modules
+COPY vendor/ vendor/
+{{- end }}
+{{- else }}
 # cache deps before building and copying source so that we don't need to re-download as much
 # and so that source changes don't invalidate our downloaded layer
 RUN go mod download
+{{- end }}
+{{- if .ModuleDir }}
+
+# Copy the go source of the whole module, the project may import the other packages of the repository
+COPY . .
+{{- else }}
 
 # Copy the go source
 COPY main.go main.go
+{{- if .ComponentConfig }}
+COPY managerconfig/ managerconfig/
+{{- end }}
+{{- if .Sharding }}
+COPY sharding/ sharding/
+{{- end }}
 COPY api/ api/
+{{- if .APIServer }}
+COPY registry/ registry/
+{{- else }}
 COPY controllers/ controllers/
+{{- end }}
+{{- end }}
 
 # Build
-RUN CGO_ENABLED=0 GOOS=linux GOARCH=amd64 GO111MODULE=on go build -a -o manager main.go
+RUN CGO_ENABLED=0 GOOS=${TARGETOS:-linux} GOARCH=${TARGETARCH:-amd64} GO111MODULE=on go build{{ if .Vendor }} -mod=vendor{{ end }} -a -o manager {{ if .ModuleDir }}./{{ .ModuleDir }}{{ else }}main.go{{ end }}
+{{- if eq .BaseImage "scratch" }}
+
+# Use scratch as empty base image to package the manager binary, with the CA
+# certificates of the builder to reach TLS endpoints outside of the cluster
+FROM scratch
+WORKDIR /
+COPY --from=builder /etc/ssl/certs/ca-certificates.crt /etc/ssl/certs/
+COPY --from=builder /workspace/manager .
+# There is no /etc/passwd, so the user is numeric
+USER 65532:65532
+{{- else if eq .BaseImage "ubi" }}
+
+# Use the Red Hat Universal Base Image minimal to package the manager binary
+# Refer to https://catalog.redhat.com/software/containers/ubi8/ubi-minimal for more details
+FROM registry.access.redhat.com/ubi8/ubi-minimal:latest
+WORKDIR /
+COPY --from=builder /workspace/manager .
+USER 65532:65532
+{{- else }}
 
 # Use distroless as minimal base image to package the manager binary
 # Refer to https://github.com/GoogleContainerTools/distroless for more details
@@ -61,6 +177,7 @@ FROM gcr.io/distroless/static:nonroot
 WORKDIR /
 COPY --from=builder /workspace/manager .
 USER nonroot:nonroot
+{{- end }}
 
 ENTRYPOINT ["/manager"]
 `
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Index{}
+
+// Index scaffolds the entry point of the API reference, linking to the reference of every Kind
+type Index struct {
+	input.Input
+
+	// Dir is the directory where the reference is generated
+	Dir string
+
+	// Format is either FormatMarkdown or FormatHTML
+	Format string
+
+	// Docs are the references of the Kinds
+	Docs []*KindDoc
+}
+
+// GetInput implements input.File
+func (f *Index) GetInput() (input.Input, error) {
+	if f.Dir == "" {
+		f.Dir = DefaultDir
+	}
+	if f.Format == FormatHTML {
+		if f.Path == "" {
+			f.Path = filepath.Join(f.Dir, "index.html")
+		}
+		f.TemplateBody = htmlIndexTemplate
+	} else {
+		if f.Path == "" {
+			f.Path = filepath.Join(f.Dir, "README.md")
+		}
+		f.TemplateBody = markdownIndexTemplate
+	}
+	f.Input.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Index) Validate() error {
+	return validateFormat(f.Format)
+}
+
+const markdownIndexTemplate = `<!-- Generated by "kubebuilder docs" from the API types, DO NOT EDIT. -->
+
+# API Reference
+
+| Kind | API Version |
+| --- | --- |
+{{- range .Docs }}
+| [{{ .Kind }}]({{ .Group }}_{{ .Version }}_{{ lower .Kind }}.md) | ` + "`{{ .APIVersion }}`" + ` |
+{{- end }}
+`
+
+// nolint:lll
+const htmlIndexTemplate = `<!DOCTYPE html>
+<!-- Generated by "kubebuilder docs" from the API types, DO NOT EDIT. -->
+<html>
+<head>
+<meta charset="utf-8">
+<title>API Reference</title>
+</head>
+<body>
+<h1>API Reference</h1>
+<table>
+<thead>
+<tr><th>Kind</th><th>API Version</th></tr>
+</thead>
+<tbody>
+{{- range .Docs }}
+<tr><td><a href="{{ .Group }}_{{ .Version }}_{{ lower .Kind }}.html">{{ .Kind }}</a></td><td><code>{{ .APIVersion }}</code></td></tr>
+{{- end }}
+</tbody>
+</table>
+</body>
+</html>
+`
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// KindDoc is the reference of a Kind, made of its own type and the types of the package that it uses
+type KindDoc struct {
+	// Group, Version and Kind identify the resource
+	Group   string
+	Version string
+	Kind    string
+
+	// APIVersion is the group with its domain and the version, e.g. ship.example.org/v1
+	APIVersion string
+
+	// Description is the documentation of the Kind type
+	Description string
+
+	// Types are the Kind type and the types of the package that it uses, in the order they are referenced
+	Types []*TypeDoc
+}
+
+// TypeDoc is the reference of a struct type
+type TypeDoc struct {
+	Name        string
+	Description string
+	Fields      []*FieldDoc
+	// Validations are the validation markers of the type, e.g. XValidation:rule="self.a < self.b"
+	Validations []string
+}
+
+// Anchor returns the identifier used to link to the type
+func (t *TypeDoc) Anchor() string {
+	return strings.ToLower(t.Name)
+}
+
+// FieldDoc is the reference of a serialized field
+type FieldDoc struct {
+	// Name is the name of the field in its serialized form
+	Name string
+	// Type is the Go type of the field without pointers
+	Type string
+	// TypeLink is the anchor of the type of the field if it is documented with the Kind
+	TypeLink string
+	// Description is the documentation of the field
+	Description string
+	// Required is true if the field can't be omitted
+	Required bool
+	// Validations are the validation markers of the field, e.g. Minimum=1
+	Validations []string
+}
+
+// LoadKind parses the Go package in dir and returns the reference of the Kind
+func LoadKind(fs afero.Fs, dir, group, version, kind, apiVersion string) (*KindDoc, error) {
+	specs, err := parseStructs(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, found := specs[kind]; !found {
+		return nil, fmt.Errorf("unable to find type %s in %s", kind, dir)
+	}
+
+	doc := &KindDoc{Group: group, Version: version, Kind: kind, APIVersion: apiVersion}
+
+	// Document the types that the Kind is made of, breadth first
+	queue, seen := []string{kind}, map[string]bool{kind: true}
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		spec := specs[name]
+		typeDoc := &TypeDoc{Name: name}
+		var markers []string
+		typeDoc.Description, markers = splitComment(spec.doc)
+		for _, marker := range markers {
+			if strings.HasPrefix(marker, "kubebuilder:validation:") {
+				typeDoc.Validations = append(typeDoc.Validations, strings.TrimPrefix(marker, "kubebuilder:validation:"))
+			}
+		}
+		for _, field := range spec.fields.List {
+			fieldDocs, ref := newFieldDocs(field, specs, name == kind, apiVersion, kind)
+			typeDoc.Fields = append(typeDoc.Fields, fieldDocs...)
+			if ref != "" && !seen[ref] {
+				seen[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+		doc.Types = append(doc.Types, typeDoc)
+	}
+	doc.Description = doc.Types[0].Description
+
+	return doc, nil
+}
+
+// structSpec is a struct type declared in the package
+type structSpec struct {
+	doc    *ast.CommentGroup
+	fields *ast.FieldList
+}
+
+// parseStructs returns the struct types declared in the Go files of dir
+func parseStructs(fs afero.Fs, dir string) (map[string]structSpec, error) {
+	infos, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	specs := make(map[string]structSpec)
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") ||
+			strings.HasPrefix(name, "zz_generated") {
+			continue
+		}
+
+		src, err := afero.ReadFile(fs, filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range genDecl.Specs {
+				typeSpec := s.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				// The comment of single type declarations belongs to the declaration
+				doc := typeSpec.Doc
+				if doc == nil {
+					doc = genDecl.Doc
+				}
+				specs[typeSpec.Name.Name] = structSpec{doc: doc, fields: structType.Fields}
+			}
+		}
+	}
+
+	return specs, nil
+}
+
+// newFieldDocs returns the reference of a field and the name of its type if it is a struct of the package
+func newFieldDocs(
+	field *ast.Field,
+	specs map[string]structSpec,
+	root bool,
+	apiVersion, kind string,
+) ([]*FieldDoc, string) {
+	typeName := strings.Replace(types.ExprString(field.Type), "*", "", -1)
+	ref := localType(field.Type)
+	if _, found := specs[ref]; !found {
+		ref = ""
+	}
+
+	tag := ""
+	if field.Tag != nil {
+		tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+	}
+	jsonName, options := tag, ""
+	if i := strings.Index(tag, ","); i != -1 {
+		jsonName, options = tag[:i], tag[i+1:]
+	}
+	if jsonName == "-" {
+		return nil, ""
+	}
+
+	if len(field.Names) == 0 {
+		// The type meta of the Kind is documented with the values it takes
+		if root && typeName == "metav1.TypeMeta" {
+			return []*FieldDoc{
+				{Name: "apiVersion", Type: "string", Description: apiVersion, Required: true},
+				{Name: "kind", Type: "string", Description: kind, Required: true},
+			}, ""
+		}
+		if jsonName == "" && strings.Contains(options, "inline") {
+			jsonName = "(inline)"
+		}
+	} else if !field.Names[0].IsExported() {
+		return nil, ""
+	}
+	if jsonName == "" && len(field.Names) != 0 {
+		jsonName = field.Names[0].Name
+	}
+
+	description, markers := splitComment(field.Doc)
+	fieldDoc := &FieldDoc{
+		Name:        jsonName,
+		Type:        typeName,
+		Description: description,
+		Required:    !strings.Contains(options, "omitempty"),
+	}
+	if ref != "" {
+		fieldDoc.TypeLink = strings.ToLower(ref)
+	}
+	for _, marker := range markers {
+		switch {
+		case marker == "optional", marker == "kubebuilder:validation:Optional":
+			fieldDoc.Required = false
+		case marker == "kubebuilder:validation:Required":
+			fieldDoc.Required = true
+		case strings.HasPrefix(marker, "kubebuilder:validation:"):
+			fieldDoc.Validations = append(fieldDoc.Validations, strings.TrimPrefix(marker, "kubebuilder:validation:"))
+		case strings.HasPrefix(marker, "kubebuilder:default="):
+			fieldDoc.Validations = append(fieldDoc.Validations, "Default="+strings.TrimPrefix(marker, "kubebuilder:default="))
+		}
+	}
+
+	return []*FieldDoc{fieldDoc}, ref
+}
+
+// localType returns the name of the type of the package that an expression refers to, if any
+func localType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return localType(t.X)
+	case *ast.ArrayType:
+		return localType(t.Elt)
+	case *ast.MapType:
+		return localType(t.Value)
+	default:
+		return ""
+	}
+}
+
+// splitComment returns the text of a comment without its markers, and the markers without their "+" prefix
+func splitComment(comment *ast.CommentGroup) (string, []string) {
+	if comment == nil {
+		return "", nil
+	}
+
+	var text, markers []string
+	for _, line := range strings.Split(comment.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "+") {
+			markers = append(markers, strings.TrimPrefix(line, "+"))
+			continue
+		}
+		text = append(text, line)
+	}
+
+	return strings.TrimSpace(strings.Join(text, "\n")), markers
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+const frigateTypes = `package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// FrigateSpec defines the desired state of Frigate
+type FrigateSpec struct {
+	// Replicas is the number of replicas
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	Replicas *int32 ` + "`json:\"replicas\"`" + `
+
+	// Crew of the frigate
+	// +optional
+	Crew []CrewMember ` + "`json:\"crew\"`" + `
+
+	// Ignored is not serialized
+	Ignored string ` + "`json:\"-\"`" + `
+
+	internal string
+}
+
+// CrewMember is a member of the crew
+// +kubebuilder:validation:XValidation:rule="self.name != 'captain'"
+type CrewMember struct {
+	Name string ` + "`json:\"name,omitempty\"`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// Frigate is the Schema for the frigates API
+type Frigate struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+
+	Spec FrigateSpec ` + "`json:\"spec,omitempty\"`" + `
+}
+`
+
+func TestLoadKind(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "api/v1/frigate_types.go", []byte(frigateTypes), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, "api/v1/zz_generated.deepcopy.go", []byte("package v1\n\nfunc ("), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := LoadKind(fs, "api/v1", "ship", "v1", "Frigate", "ship.example.org/v1")
+	if err != nil {
+		t.Fatalf("LoadKind() returned an error: %v", err)
+	}
+
+	expected := &KindDoc{
+		Group:       "ship",
+		Version:     "v1",
+		Kind:        "Frigate",
+		APIVersion:  "ship.example.org/v1",
+		Description: "Frigate is the Schema for the frigates API",
+		Types: []*TypeDoc{
+			{
+				Name:        "Frigate",
+				Description: "Frigate is the Schema for the frigates API",
+				Fields: []*FieldDoc{
+					{Name: "apiVersion", Type: "string", Description: "ship.example.org/v1", Required: true},
+					{Name: "kind", Type: "string", Description: "Frigate", Required: true},
+					{Name: "metadata", Type: "metav1.ObjectMeta"},
+					{Name: "spec", Type: "FrigateSpec", TypeLink: "frigatespec"},
+				},
+			},
+			{
+				Name:        "FrigateSpec",
+				Description: "FrigateSpec defines the desired state of Frigate",
+				Fields: []*FieldDoc{
+					{
+						Name:        "replicas",
+						Type:        "int32",
+						Description: "Replicas is the number of replicas",
+						Required:    true,
+						Validations: []string{"Minimum=1", "Default=3"},
+					},
+					{Name: "crew", Type: "[]CrewMember", TypeLink: "crewmember", Description: "Crew of the frigate"},
+				},
+			},
+			{
+				Name:        "CrewMember",
+				Description: "CrewMember is a member of the crew",
+				Fields:      []*FieldDoc{{Name: "name", Type: "string"}},
+				Validations: []string{`XValidation:rule="self.name != 'captain'"`},
+			},
+		},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		for i, typeDoc := range doc.Types {
+			for j, field := range typeDoc.Fields {
+				t.Logf("type %d field %d: %+v", i, j, field)
+			}
+		}
+		t.Errorf("LoadKind() returned an unexpected reference")
+	}
+
+	if _, err := LoadKind(fs, "api/v1", "ship", "v1", "Destroyer", "ship.example.org/v1"); err == nil {
+		t.Errorf("LoadKind() should fail for a Kind that is not declared")
+	}
+}
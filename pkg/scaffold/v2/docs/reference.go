@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+const (
+	// FormatMarkdown renders the reference as Markdown
+	FormatMarkdown = "markdown"
+	// FormatHTML renders the reference as HTML
+	FormatHTML = "html"
+)
+
+// DefaultDir is the directory where the reference is generated by default
+var DefaultDir = filepath.Join("docs", "api")
+
+var _ input.File = &Reference{}
+
+// Reference scaffolds the API reference of a Kind
+type Reference struct {
+	input.Input
+
+	// Dir is the directory where the reference is generated
+	Dir string
+
+	// Format is either FormatMarkdown or FormatHTML
+	Format string
+
+	// Doc is the reference of the Kind
+	Doc *KindDoc
+}
+
+// GetInput implements input.File
+func (f *Reference) GetInput() (input.Input, error) {
+	if f.Dir == "" {
+		f.Dir = DefaultDir
+	}
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Dir, fmt.Sprintf("%s_%s_%s%s",
+			f.Doc.Group, f.Doc.Version, strings.ToLower(f.Doc.Kind), extension(f.Format)))
+	}
+	if f.Format == FormatHTML {
+		f.Doc = f.Doc.escaped(htmlText, htmlText)
+		f.TemplateBody = htmlReferenceTemplate
+	} else {
+		f.Doc = f.Doc.escaped(markdownCell, strings.TrimSpace)
+		f.TemplateBody = markdownReferenceTemplate
+	}
+	// The reference is generated from the API types, so it is regenerated every time
+	f.Input.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Reference) Validate() error {
+	if f.Doc == nil {
+		return errors.New("missing reference of the Kind")
+	}
+	return validateFormat(f.Format)
+}
+
+func validateFormat(format string) error {
+	if format != FormatMarkdown && format != FormatHTML {
+		return fmt.Errorf("unsupported format %q, must be %q or %q", format, FormatMarkdown, FormatHTML)
+	}
+	return nil
+}
+
+func extension(format string) string {
+	if format == FormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+// escaped returns a copy of the reference with the text of the table cells and of the descriptions escaped
+func (d *KindDoc) escaped(cell, text func(string) string) *KindDoc {
+	out := *d
+	out.Description = text(d.Description)
+	out.Types = make([]*TypeDoc, 0, len(d.Types))
+	for _, t := range d.Types {
+		typeDoc := &TypeDoc{Name: t.Name, Description: text(t.Description)}
+		for _, field := range t.Fields {
+			fieldDoc := *field
+			fieldDoc.Name = cell(field.Name)
+			fieldDoc.Type = cell(field.Type)
+			fieldDoc.Description = cell(field.Description)
+			fieldDoc.Validations = make([]string, 0, len(field.Validations))
+			for _, validation := range field.Validations {
+				fieldDoc.Validations = append(fieldDoc.Validations, cell(validation))
+			}
+			typeDoc.Fields = append(typeDoc.Fields, &fieldDoc)
+		}
+		out.Types = append(out.Types, typeDoc)
+	}
+	return &out
+}
+
+// markdownCell makes the text fit in a single table cell
+func markdownCell(s string) string {
+	return strings.Replace(strings.Join(strings.Fields(s), " "), "|", `\|`, -1)
+}
+
+// htmlText escapes the text and joins its lines
+func htmlText(s string) string {
+	return html.EscapeString(strings.Join(strings.Fields(s), " "))
+}
+
+// nolint:lll
+const markdownReferenceTemplate = `<!-- Generated by "kubebuilder docs" from the API types, DO NOT EDIT. -->
+
+# {{ .Doc.Kind }}
+
+` + "`{{ .Doc.APIVersion }}`" + `
+{{ range .Doc.Types }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- if .Fields }}
+| Field | Type | Required | Description |
+| --- | --- | --- | --- |
+{{- range .Fields }}
+| ` + "`{{ .Name }}`" + ` | {{ if .TypeLink }}[{{ .Type }}](#{{ .TypeLink }}){{ else }}` + "`{{ .Type }}`" + `{{ end }} | {{ if .Required }}Yes{{ else }}No{{ end }} | {{ .Description }}{{ range .Validations }}<br>` + "`{{ . }}`" + `{{ end }} |
+{{- end }}
+{{ end }}
+{{- end -}}
+`
+
+// nolint:lll
+const htmlReferenceTemplate = `<!DOCTYPE html>
+<!-- Generated by "kubebuilder docs" from the API types, DO NOT EDIT. -->
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Doc.Kind }}</title>
+</head>
+<body>
+<h1>{{ .Doc.Kind }}</h1>
+<p><code>{{ .Doc.APIVersion }}</code></p>
+{{- range .Doc.Types }}
+<h2 id="{{ .Anchor }}">{{ .Name }}</h2>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+{{- if .Fields }}
+<table>
+<thead>
+<tr><th>Field</th><th>Type</th><th>Required</th><th>Description</th></tr>
+</thead>
+<tbody>
+{{- range .Fields }}
+<tr><td><code>{{ .Name }}</code></td><td>{{ if .TypeLink }}<a href="#{{ .TypeLink }}">{{ .Type }}</a>{{ else }}<code>{{ .Type }}</code>{{ end }}</td><td>{{ if .Required }}Yes{{ else }}No{{ end }}</td><td>{{ .Description }}{{ range .Validations }}<br><code>{{ . }}</code>{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var _ input.File = &APITest{}
+
+// APITest scaffolds the e2e test of an API, which creates the sample of the resource in the cluster
+type APITest struct {
+	input.Input
+
+	// Resource is the resource of the API
+	Resource *resource.Resource
+
+	// Controller is true if the API has a controller, which sets the conditions of the sample
+	Controller bool
+
+	// SampleFile is the path of the sample of the resource under config/samples, split in its elements
+	SampleFile []string
+}
+
+// GetInput implements input.File
+func (f *APITest) GetInput() (input.Input, error) {
+	name := fmt.Sprintf("%s_%s_%s", f.Resource.Group, f.Resource.Version, strings.ToLower(f.Resource.Kind))
+	if f.Path == "" {
+		f.Path = filepath.Join("test", "e2e", name+"_test.go")
+	}
+	sampleFile, err := filepath.Rel(filepath.Join("config", "samples"), scaffoldv2.SamplePath(f.Resource, f.MultiGroup))
+	if err != nil {
+		return input.Input{}, err
+	}
+	f.SampleFile = strings.Split(filepath.ToSlash(sampleFile), "/")
+	f.TemplateBody = apiTestTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *APITest) Validate() error {
+	return f.Resource.Validate()
+}
+
+const apiTestTemplate = `// +build e2e
+
+{{ .Boilerplate }}
+
+package e2e
+
+import (
+	"context"
+	"path/filepath"
+{{- if and .Controller .Resource.Conditions }}
+	"time"
+{{- end }}
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("{{ .Resource.Kind }} {{ .Resource.Version }}", func() {
+	ctx := context.Background()
+
+	var sample *unstructured.Unstructured
+
+	BeforeEach(func() {
+		var err error
+		sample, err = loadSample(filepath.Join(projectDir, "config", "samples"{{ range .SampleFile }}, "{{ . }}"{{ end }}))
+		Expect(err).NotTo(HaveOccurred())
+{{- if .Resource.Namespaced }}
+
+		// The sample is created in the namespace of the manager, which may be the only one it watches
+		namespace, err := managerNamespace(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		sample.SetNamespace(namespace)
+{{- end }}
+	})
+
+	AfterEach(func() {
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, sample))).To(Succeed())
+	})
+
+	It("should accept the sample", func() {
+		Expect(k8sClient.Create(ctx, sample)).To(Succeed())
+{{- if and .Controller .Resource.Conditions }}
+
+		By("waiting for the sample to be reconciled")
+		Eventually(func() (string, error) {
+			if err := k8sClient.Get(ctx, client.ObjectKey{
+				Namespace: sample.GetNamespace(),
+				Name:      sample.GetName(),
+			}, sample); err != nil {
+				return "", err
+			}
+			return conditionStatus(sample, "Ready")
+		}, 2*time.Minute, time.Second).Should(Equal("True"))
+{{- end }}
+
+		// TODO(user): Check the state of the cluster once the sample was reconciled
+	})
+})
+`
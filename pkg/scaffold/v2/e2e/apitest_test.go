@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/e2e"
+)
+
+var _ = Describe("APITest", func() {
+	It("should create the sample in a namespace and wait for its conditions when it has a controller", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true, Conditions: true}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &e2e.APITest{Resource: frigate, Controller: true})).
+			To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("test", "e2e", "ship_v1_frigate_test.go"))
+		Expect(content).To(ContainSubstring(`"config", "samples", "ship_v1_frigate.yaml"`))
+		Expect(content).To(ContainSubstring("sample.SetNamespace(namespace)"))
+		Expect(content).To(ContainSubstring(`conditionStatus(sample, "Ready")`))
+	})
+
+	It("should only create the sample of cluster-scoped resources without a controller", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer", Conditions: true}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &e2e.APITest{Resource: destroyer})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("test", "e2e", "ship_v1_destroyer_test.go"))
+		Expect(content).NotTo(ContainSubstring("SetNamespace"))
+		Expect(content).NotTo(ContainSubstring("conditionStatus"))
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &SmokeTest{}
+
+// SmokeTest scaffolds the smoke_test.go file to check that the deployed manager is running
+type SmokeTest struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *SmokeTest) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("test", "e2e", "smoke_test.go")
+	}
+	f.TemplateBody = smokeTestTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const smokeTestTemplate = `// +build e2e
+
+{{ .Boilerplate }}
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// managerLabels select the manager Deployment and its Pods, whatever the name prefix and namespace of the project
+var managerLabels = client.MatchingLabels{"control-plane": "controller-manager"}
+
+var _ = Describe("Manager", func() {
+	ctx := context.Background()
+
+	It("should become available", func() {
+		Eventually(func() error {
+			deployments := &appsv1.DeploymentList{}
+			if err := k8sClient.List(ctx, deployments, managerLabels); err != nil {
+				return err
+			}
+			if len(deployments.Items) == 0 {
+				return fmt.Errorf("the manager Deployment was not found")
+			}
+			for _, deployment := range deployments.Items {
+				if deployment.Status.AvailableReplicas == 0 {
+					return fmt.Errorf("the manager Deployment %s/%s has no available replicas",
+						deployment.Namespace, deployment.Name)
+				}
+			}
+			return nil
+		}, 2*time.Minute, time.Second).Should(Succeed())
+	})
+
+	It("should keep running", func() {
+		Consistently(func() error {
+			pods := &corev1.PodList{}
+			if err := k8sClient.List(ctx, pods, managerLabels); err != nil {
+				return err
+			}
+			for _, pod := range pods.Items {
+				for _, status := range pod.Status.ContainerStatuses {
+					if status.RestartCount != 0 {
+						return fmt.Errorf("the container %s of the manager Pod %s/%s restarted %d times",
+							status.Name, pod.Namespace, pod.Name, status.RestartCount)
+					}
+				}
+			}
+			return nil
+		}, 10*time.Second, time.Second).Should(Succeed())
+	})
+
+	// TODO(user): Add the e2e tests of your controllers
+})
+`
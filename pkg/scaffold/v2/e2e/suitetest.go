@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &SuiteTest{}
+
+// SuiteTest scaffolds the e2e_suite_test.go file to setup the e2e tests against a cluster
+type SuiteTest struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *SuiteTest) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("test", "e2e", "e2e_suite_test.go")
+	}
+	f.TemplateBody = e2eSuiteTestTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const e2eSuiteTestTemplate = `// +build e2e
+
+{{ .Boilerplate }}
+
+package e2e
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// These tests run against the manager deployed in a cluster, e.g. "make test-e2e" deploys it in a kind
+// cluster before running them. They are built with the e2e tag only, so "go test ./..." skips them.
+
+var kubeContext = flag.String("context", "", "kubeconfig context of the cluster the manager is deployed in")
+
+var k8sClient client.Client
+
+func TestE2E(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "E2E Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.LoggerTo(GinkgoWriter, true))
+
+	cfg, err := config.GetConfigWithContext(*kubeContext)
+	Expect(err).ToNot(HaveOccurred())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(k8sClient).ToNot(BeNil())
+})
+`
@@ -26,6 +26,14 @@ var _ input.File = &GoMod{}
 type GoMod struct {
 	input.Input
 	ControllerRuntimeVersion string
+	// APIServerVersion is the version of k8s.io/apiserver required by aggregated API servers
+	APIServerVersion string
+	// KubernetesVersion is the version k8s.io/client-go and k8s.io/apimachinery are pinned to, consistent with the
+	// ones required by controller-runtime, not pinned if empty
+	KubernetesVersion string
+	// Vendor is true if the dependencies are vendored, go 1.14 builds with vendor/ by default when it is consistent
+	// with go.mod
+	Vendor bool
 }
 
 // GetInput implements input.File
@@ -41,9 +49,16 @@ func (f *GoMod) GetInput() (input.Input, error) {
 const goModTemplate = `
 module {{ .Repo }}
 
-go 1.13
+go {{ if .Vendor }}1.14{{ else }}1.13{{ end }}
 
 require (
+{{- if .APIServerVersion }}
+	k8s.io/apiserver {{ .APIServerVersion }}
+{{- end }}
+{{- if .KubernetesVersion }}
+	k8s.io/apimachinery {{ .KubernetesVersion }}
+	k8s.io/client-go {{ .KubernetesVersion }}
+{{- end }}
 	sigs.k8s.io/controller-runtime {{ .ControllerRuntimeVersion }}
 )
 `
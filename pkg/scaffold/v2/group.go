@@ -56,7 +56,7 @@ const groupTemplate = `{{ .Boilerplate }}
 
 // Package {{.Resource.Version}} contains API Schema definitions for the {{ .Resource.GroupImportSafe }} {{.Resource.Version}} API group
 // +kubebuilder:object:generate=true
-// +groupName={{ .Resource.Group }}.{{ .Domain }}
+// +groupName={{ .Resource.QualifiedGroup .Domain }}
 package {{ .Resource.Version }}
 
 import (
@@ -66,7 +66,7 @@ import (
 
 var (
 	// GroupVersion is group version used to register these objects
-	GroupVersion = schema.GroupVersion{Group: "{{ .Resource.Group }}.{{ .Domain }}", Version: "{{ .Resource.Version }}"}
+	GroupVersion = schema.GroupVersion{Group: "{{ .Resource.QualifiedGroup .Domain }}", Version: "{{ .Resource.Version }}"}
 
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// The chart templates are Helm templates themselves, so they are scaffolded with different delimiters
+const (
+	leftDelim  = "[["
+	rightDelim = "]]"
+)
+
+// ChartDir returns the directory of the chart with the given name
+func ChartDir(name string) string {
+	return filepath.Join("charts", name)
+}
+
+// DefaultChartName returns the name of the chart of the project, the directory name like the kustomize prefix
+func DefaultChartName() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(filepath.Base(dir)), nil
+}
+
+func validateChartName(name string) error {
+	if name == "" {
+		return errors.New("chart name cannot be empty")
+	}
+	return nil
+}
+
+var _ input.File = &Chart{}
+
+// Chart scaffolds the Chart.yaml file with the metadata of the chart
+type Chart struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+}
+
+// GetInput implements input.File
+func (f *Chart) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "Chart.yaml")
+	}
+	f.TemplateBody = chartTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Chart) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+const chartTemplate = `apiVersion: v2
+name: {{ .ChartName }}
+description: A Helm chart to deploy the {{ .ChartName }} controller manager
+type: application
+version: 0.1.0
+appVersion: latest
+`
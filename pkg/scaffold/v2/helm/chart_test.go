@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/helm"
+)
+
+var _ = Describe("Chart", func() {
+	It("should name the chart after the project", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &helm.Chart{ChartName: "fleet"})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("charts", "fleet", "Chart.yaml"))
+		Expect(content).To(ContainSubstring("apiVersion: v2\nname: fleet\n"))
+	})
+
+	It("should require a chart name", func() {
+		s, _ := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &helm.Chart{})).NotTo(Succeed())
+	})
+
+	It("should not overwrite an existing chart", func() {
+		s, _ := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &helm.Chart{ChartName: "fleet"})).To(Succeed())
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &helm.Chart{ChartName: "fleet"})).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("RBAC", func() {
+	It("should keep the Helm actions of the template and fill in the chart name", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &helm.RBAC{ChartName: "fleet"})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("charts", "fleet", "templates", "rbac.yaml"))
+		Expect(content).To(HavePrefix(`{{- $fullname := include "fleet.fullname" . -}}`))
+		Expect(content).To(ContainSubstring("name: {{ $fullname }}-manager-role\n"))
+		Expect(content).To(ContainSubstring(`{{- $role := .Files.Get "files/role.yaml" | fromYaml }}`))
+	})
+})
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Deployment{}
+var _ input.HasDelimiters = &Deployment{}
+
+// Deployment scaffolds the Deployment of the controller manager
+type Deployment struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+
+	// NamespaceScoped is true if the manager watches the namespace it is deployed to only
+	NamespaceScoped bool
+
+	// SecureDefaults is true if the manager pods run with a restricted security context
+	SecureDefaults bool
+
+	// WebhookPort is the port the webhook server of the manager listens on
+	WebhookPort int
+
+	// MetricsAddress is the address the metrics endpoint of the manager binds to behind the auth proxy,
+	// defaults to 127.0.0.1:8080
+	MetricsAddress string
+
+	// IPv6 is true if the auth proxy listens on the IPv6 and IPv4 addresses of the pod instead of the IPv4 ones only
+	IPv6 bool
+}
+
+// GetInput implements input.File
+func (f *Deployment) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "templates", "deployment.yaml")
+	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
+	if f.MetricsAddress == "" {
+		f.MetricsAddress = "127.0.0.1:8080"
+	}
+	f.TemplateBody = deploymentTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *Deployment) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// Validate validates the values
+func (f *Deployment) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+const deploymentTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "[[ .ChartName ]].fullname" . }}-controller-manager
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  selector:
+    matchLabels:
+      {{- include "[[ .ChartName ]].selectorLabels" . | nindent 6 }}
+  template:
+    metadata:
+      labels:
+        {{- include "[[ .ChartName ]].selectorLabels" . | nindent 8 }}
+    spec:
+      serviceAccountName: {{ include "[[ .ChartName ]].fullname" . }}-controller-manager
+      {{- with .Values.nodeSelector }}
+      nodeSelector:
+        {{- toYaml . | nindent 8 }}
+      {{- end }}
+      {{- with .Values.affinity }}
+      affinity:
+        {{- toYaml . | nindent 8 }}
+      {{- end }}
+      {{- with .Values.tolerations }}
+      tolerations:
+        {{- toYaml . | nindent 8 }}
+      {{- end }}
+      [[- if .SecureDefaults ]]
+      securityContext:
+        runAsNonRoot: true
+        runAsUser: 65532
+        seccompProfile:
+          type: RuntimeDefault
+      [[- end ]]
+      containers:
+      - name: kube-rbac-proxy
+        image: {{ .Values.kubeRBACProxy.image }}
+        args:
+        - "--secure-listen-address=[[ if .IPv6 ]][::][[ else ]]0.0.0.0[[ end ]]:8443"
+        - "--upstream=http://[[ .MetricsAddress ]]/"
+        - "--logtostderr=true"
+        - "--v=10"
+        ports:
+        - containerPort: 8443
+          name: https
+        [[- if .SecureDefaults ]]
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+        [[- end ]]
+      - name: manager
+        image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+        imagePullPolicy: {{ .Values.image.pullPolicy }}
+        command:
+        - /manager
+        args:
+        - "--metrics-bind-address=[[ .MetricsAddress ]]"
+        {{- if .Values.leaderElection }}
+        - "--enable-leader-election"
+        {{- end }}
+        [[- if .NamespaceScoped ]]
+        env:
+        - name: WATCH_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+        {{- if not .Values.webhook.enabled }}
+        - name: ENABLE_WEBHOOKS
+          value: "false"
+        {{- end }}
+        [[- end ]]
+        {{- if .Values.webhook.enabled }}
+        ports:
+        - containerPort: [[ .WebhookPort ]]
+          name: webhook-server
+          protocol: TCP
+        volumeMounts:
+        - mountPath: /tmp/k8s-webhook-server/serving-certs
+          name: cert
+          readOnly: true
+        [[- if not .NamespaceScoped ]]
+        {{- else }}
+        env:
+        - name: ENABLE_WEBHOOKS
+          value: "false"
+        [[- end ]]
+        {{- end }}
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8081
+          initialDelaySeconds: 15
+          periodSeconds: 20
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 8081
+          initialDelaySeconds: 5
+          periodSeconds: 10
+        resources:
+          {{- toYaml .Values.resources | nindent 10 }}
+        [[- if .SecureDefaults ]]
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+        [[- end ]]
+      terminationGracePeriodSeconds: 10
+      {{- if .Values.webhook.enabled }}
+      volumes:
+      - name: cert
+        secret:
+          defaultMode: 420
+          secretName: {{ include "[[ .ChartName ]].fullname" . }}-webhook-server-cert
+      {{- end }}
+`
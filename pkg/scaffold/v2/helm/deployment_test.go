@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/helm"
+)
+
+var _ = Describe("Deployment", func() {
+	// scaffoldDeployment returns the deployment template scaffolded by f
+	scaffoldDeployment := func(f *helm.Deployment) string {
+		f.ChartName = "fleet"
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, f)).To(Succeed())
+		return scaffoldtest.ReadFile(fs, filepath.Join("charts", "fleet", "templates", "deployment.yaml"))
+	}
+
+	It("should serve the metrics and the webhooks on the ports of the project", func() {
+		content := scaffoldDeployment(&helm.Deployment{WebhookPort: 9444, MetricsAddress: "127.0.0.1:8080"})
+
+		Expect(content).To(ContainSubstring(`- "--secure-listen-address=0.0.0.0:8443"`))
+		Expect(content).To(ContainSubstring(`- "--upstream=http://127.0.0.1:8080/"`))
+		Expect(content).To(ContainSubstring("- containerPort: 9444\n          name: webhook-server\n"))
+		Expect(content).NotTo(ContainSubstring("WATCH_NAMESPACE"))
+		Expect(content).NotTo(ContainSubstring("runAsNonRoot"))
+	})
+
+	It("should listen on IPv6 addresses", func() {
+		content := scaffoldDeployment(&helm.Deployment{WebhookPort: 9443, MetricsAddress: "[::1]:8080", IPv6: true})
+
+		Expect(content).To(ContainSubstring(`- "--secure-listen-address=[::]:8443"`))
+		Expect(content).To(ContainSubstring(`- "--upstream=http://[::1]:8080/"`))
+	})
+
+	It("should watch the namespace of the release for namespace-scoped projects", func() {
+		content := scaffoldDeployment(&helm.Deployment{WebhookPort: 9443, NamespaceScoped: true})
+
+		Expect(content).To(ContainSubstring("- name: WATCH_NAMESPACE\n"))
+		Expect(content).To(ContainSubstring("fieldPath: metadata.namespace\n"))
+	})
+
+	It("should harden the pod with the secure defaults", func() {
+		content := scaffoldDeployment(&helm.Deployment{WebhookPort: 9443, SecureDefaults: true})
+
+		Expect(content).To(ContainSubstring("runAsNonRoot: true\n"))
+		Expect(content).To(ContainSubstring("readOnlyRootFilesystem: true\n"))
+	})
+})
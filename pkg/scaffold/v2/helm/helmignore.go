@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &HelmIgnore{}
+
+// HelmIgnore scaffolds the patterns ignored when packaging the chart
+type HelmIgnore struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+}
+
+// GetInput implements input.File
+func (f *HelmIgnore) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), ".helmignore")
+	}
+	f.TemplateBody = helmIgnoreTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *HelmIgnore) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+const helmIgnoreTemplate = `# Patterns to ignore when building packages.
+.DS_Store
+.git/
+.gitignore
+*.swp
+*.bak
+*.tmp
+*~
+`
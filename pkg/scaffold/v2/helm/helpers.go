@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Helpers{}
+var _ input.HasDelimiters = &Helpers{}
+
+// Helpers scaffolds the named templates shared by the rest of templates of the chart
+type Helpers struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+}
+
+// GetInput implements input.File
+func (f *Helpers) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "templates", "_helpers.tpl")
+	}
+	f.TemplateBody = helpersTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *Helpers) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// Validate validates the values
+func (f *Helpers) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+const helpersTemplate = `{{/*
+Expand the name of the chart.
+*/}}
+{{- define "[[ .ChartName ]].name" -}}
+{{- default .Chart.Name .Values.nameOverride | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+*/}}
+{{- define "[[ .ChartName ]].fullname" -}}
+{{- if .Values.fullnameOverride -}}
+{{- .Values.fullnameOverride | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- $name := default .Chart.Name .Values.nameOverride -}}
+{{- if contains $name .Release.Name -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- printf "%s-%s" .Release.Name $name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+Common labels.
+*/}}
+{{- define "[[ .ChartName ]].labels" -}}
+helm.sh/chart: {{ printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" }}
+{{ include "[[ .ChartName ]].selectorLabels" . }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end -}}
+
+{{/*
+Selector labels of the controller manager.
+*/}}
+{{- define "[[ .ChartName ]].selectorLabels" -}}
+app.kubernetes.io/name: {{ include "[[ .ChartName ]].name" . }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+control-plane: controller-manager
+{{- end -}}
+`
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &MetricsService{}
+var _ input.HasDelimiters = &MetricsService{}
+
+// MetricsService scaffolds the Service that exposes the metrics through the auth proxy
+type MetricsService struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+
+	// DualStack is true if the Service is assigned IPv4 and IPv6 addresses where the cluster supports it
+	DualStack bool
+}
+
+// GetInput implements input.File
+func (f *MetricsService) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "templates", "metrics_service.yaml")
+	}
+	f.TemplateBody = metricsServiceTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *MetricsService) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// Validate validates the values
+func (f *MetricsService) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+const metricsServiceTemplate = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "[[ .ChartName ]].fullname" . }}-controller-manager-metrics-service
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+spec:
+  [[- if .DualStack ]]
+  ipFamilyPolicy: PreferDualStack
+  [[- end ]]
+  ports:
+  - name: https
+    port: 8443
+    targetPort: https
+  selector:
+    {{- include "[[ .ChartName ]].selectorLabels" . | nindent 4 }}
+`
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &RBAC{}
+var _ input.HasDelimiters = &RBAC{}
+
+// RBAC scaffolds the ServiceAccount of the controller manager and its roles
+type RBAC struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+}
+
+// GetInput implements input.File
+func (f *RBAC) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "templates", "rbac.yaml")
+	}
+	f.TemplateBody = rbacTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *RBAC) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// Validate validates the values
+func (f *RBAC) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+// nolint:lll
+const rbacTemplate = `{{- $fullname := include "[[ .ChartName ]].fullname" . -}}
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ $fullname }}-controller-manager
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+---
+# permissions to do leader election.
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: {{ $fullname }}-leader-election-role
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - ""
+  resources:
+  - configmaps/status
+  verbs:
+  - get
+  - update
+  - patch
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: {{ $fullname }}-leader-election-rolebinding
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: {{ $fullname }}-leader-election-role
+subjects:
+- kind: ServiceAccount
+  name: {{ $fullname }}-controller-manager
+  namespace: {{ .Release.Namespace }}
+---
+# permissions of the controller manager, generated from the RBAC markers with "make chart"
+{{- $role := .Files.Get "files/role.yaml" | fromYaml }}
+{{- $roleKind := default "ClusterRole" $role.kind }}
+apiVersion: rbac.authorization.k8s.io/v1
+kind: {{ $roleKind }}
+metadata:
+  name: {{ $fullname }}-manager-role
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+rules:
+{{- toYaml (default (list) $role.rules) | nindent 0 }}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: {{ $roleKind }}Binding
+metadata:
+  name: {{ $fullname }}-manager-rolebinding
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: {{ $roleKind }}
+  name: {{ $fullname }}-manager-role
+subjects:
+- kind: ServiceAccount
+  name: {{ $fullname }}-controller-manager
+  namespace: {{ .Release.Namespace }}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ $fullname }}-proxy-role
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+rules:
+- apiGroups: ["authentication.k8s.io"]
+  resources:
+  - tokenreviews
+  verbs: ["create"]
+- apiGroups: ["authorization.k8s.io"]
+  resources:
+  - subjectaccessreviews
+  verbs: ["create"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{ $fullname }}-proxy-rolebinding
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: {{ $fullname }}-proxy-role
+subjects:
+- kind: ServiceAccount
+  name: {{ $fullname }}-controller-manager
+  namespace: {{ .Release.Namespace }}
+`
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Values{}
+
+// Values scaffolds the default values of the chart
+type Values struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+}
+
+// GetInput implements input.File
+func (f *Values) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "values.yaml")
+	}
+	f.TemplateBody = valuesTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Values) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+const valuesTemplate = `# Default values for {{ .ChartName }}.
+
+replicaCount: 1
+
+image:
+  repository: controller
+  tag: latest
+  pullPolicy: IfNotPresent
+
+# leaderElection ensures there is only one active controller manager
+leaderElection: true
+
+resources:
+  limits:
+    cpu: 100m
+    memory: 30Mi
+  requests:
+    cpu: 100m
+    memory: 20Mi
+
+# kubeRBACProxy protects the metrics endpoint by performing RBAC authorization against the Kubernetes API
+kubeRBACProxy:
+  image: gcr.io/kubebuilder/kube-rbac-proxy:v0.4.1
+
+webhook:
+  # enabled serves the webhooks, it requires cert-manager to issue the webhook server certificate
+  enabled: false
+
+# nodeSelector, affinity and tolerations schedule the manager on the nodes of the platforms the image is built for
+# by make docker-buildx
+nodeSelector:
+  kubernetes.io/os: linux
+
+affinity:
+  nodeAffinity:
+    requiredDuringSchedulingIgnoredDuringExecution:
+      nodeSelectorTerms:
+      - matchExpressions:
+        - key: kubernetes.io/arch
+          operator: In
+          values:
+          - amd64
+          - arm64
+          - ppc64le
+
+# Some providers taint their arm64 nodes so that only the pods with arm64 images are scheduled on them
+tolerations:
+- key: kubernetes.io/arch
+  operator: Equal
+  value: arm64
+  effect: NoSchedule
+`
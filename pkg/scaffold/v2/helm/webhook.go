@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Webhook{}
+var _ input.HasDelimiters = &Webhook{}
+
+// Webhook scaffolds the webhook Service, its cert-manager certificate and the webhook configurations
+type Webhook struct {
+	input.Input
+
+	// ChartName is the name of the chart
+	ChartName string
+
+	// WebhookPort is the port the webhook server of the manager listens on
+	WebhookPort int
+
+	// DualStack is true if the Service is assigned IPv4 and IPv6 addresses where the cluster supports it
+	DualStack bool
+}
+
+// GetInput implements input.File
+func (f *Webhook) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(ChartDir(f.ChartName), "templates", "webhook.yaml")
+	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
+	f.TemplateBody = webhookTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *Webhook) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// Validate validates the values
+func (f *Webhook) Validate() error {
+	return validateChartName(f.ChartName)
+}
+
+// nolint:lll
+const webhookTemplate = `{{- if .Values.webhook.enabled }}
+{{- $fullname := include "[[ .ChartName ]].fullname" . -}}
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ $fullname }}-webhook-service
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+spec:
+  [[- if .DualStack ]]
+  ipFamilyPolicy: PreferDualStack
+  [[- end ]]
+  ports:
+    - port: 443
+      targetPort: [[ .WebhookPort ]]
+  selector:
+    {{- include "[[ .ChartName ]].selectorLabels" . | nindent 4 }}
+---
+apiVersion: cert-manager.io/v1alpha2
+kind: Issuer
+metadata:
+  name: {{ $fullname }}-selfsigned-issuer
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+spec:
+  selfSigned: {}
+---
+apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: {{ $fullname }}-serving-cert
+  labels:
+    {{- include "[[ .ChartName ]].labels" . | nindent 4 }}
+spec:
+  dnsNames:
+  - {{ $fullname }}-webhook-service.{{ .Release.Namespace }}.svc
+  - {{ $fullname }}-webhook-service.{{ .Release.Namespace }}.svc.cluster.local
+  issuerRef:
+    kind: Issuer
+    name: {{ $fullname }}-selfsigned-issuer
+  secretName: {{ $fullname }}-webhook-server-cert
+{{- /* webhook configurations generated from the webhook markers with "make chart" */ -}}
+{{- with .Files.Get "files/webhook_manifests.yaml" }}
+{{ . | replace "creationTimestamp: null" (printf "annotations:\n    cert-manager.io/inject-ca-from: %s/%s-serving-cert" $.Release.Namespace $fullname) | replace "name: mutating-webhook-configuration" (printf "name: %s-mutating-webhook-configuration" $fullname) | replace "name: validating-webhook-configuration" (printf "name: %s-validating-webhook-configuration" $fullname) | replace "name: webhook-service" (printf "name: %s-webhook-service" $fullname) | replace "namespace: system" (printf "namespace: %s" $.Release.Namespace) }}
+{{- end }}
+{{- end }}
+`
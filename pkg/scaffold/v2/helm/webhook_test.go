@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/helm"
+)
+
+var _ = Describe("Webhook", func() {
+	It("should route the webhook service to the webhook port of the manager", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{},
+			&helm.Webhook{ChartName: "fleet", WebhookPort: 9444, DualStack: true})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("charts", "fleet", "templates", "webhook.yaml"))
+		Expect(content).To(HavePrefix("{{- if .Values.webhook.enabled }}\n"))
+		Expect(content).To(ContainSubstring("targetPort: 9444\n"))
+		Expect(content).To(ContainSubstring("ipFamilyPolicy: PreferDualStack\n"))
+	})
+})
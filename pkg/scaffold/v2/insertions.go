@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"github.com/spf13/afero"
+
+	scaffolderrors "sigs.k8s.io/kubebuilder/pkg/scaffold/errors"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
+)
+
+// Insertions collects the code fragments inserted before the markers of the scaffolded files, so that a file
+// updated for several resources is only rewritten, and formatted, once
+type Insertions struct {
+	// paths keeps the files in the order they were first updated
+	paths  []string
+	values map[string]inject.Fragments
+}
+
+// Add records the values to insert before each marker of the file, skipping the ones already recorded
+func (i *Insertions) Add(path string, markerAndValues map[string][]string) {
+	if i.values == nil {
+		i.values = make(map[string]inject.Fragments)
+	}
+	if _, found := i.values[path]; !found {
+		i.paths = append(i.paths, path)
+		i.values[path] = make(inject.Fragments)
+	}
+	inject.Merge(i.values[path], markerAndValues)
+}
+
+// Apply inserts the recorded values in the files and returns the paths of the files that were updated
+func (i *Insertions) Apply(fs afero.Fs) ([]string, error) {
+	for _, path := range i.paths {
+		if err := inject.Update(fs, path, i.values[path]); err != nil {
+			return nil, scaffolderrors.PostUpdate(path, "updating "+path, err)
+		}
+	}
+	return i.paths, nil
+}
@@ -18,69 +18,82 @@ package internal
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/spf13/afero"
 	"golang.org/x/tools/imports"
 )
 
-// insertStrings reads content from given reader and insert string below the
-// line containing marker string. So for ex. in insertStrings(r, {'m1':
-// [v1], 'm2': [v2]})
-// v1 will be inserted below the lines containing m1 string and v2 will be inserted
-// below line containing m2 string.
-func insertStrings(r io.Reader, markerAndValues map[string][]string) (io.Reader, error) {
-	// reader clone is needed since we will be reading twice from the given reader
-	buf := new(bytes.Buffer)
-	rClone := io.TeeReader(r, buf)
-
-	err := filterExistingValues(rClone, markerAndValues)
-	if err != nil {
+// removeStrings reads content from given reader and removes every occurrence of
+// the given values. Multi-line values are only removed if all their lines are
+// found consecutively. Lines are compared ignoring whitespace as the content
+// may have been re-formatted after the values were inserted.
+func removeStrings(r io.Reader, values []string) (io.Reader, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
 
-	out := new(bytes.Buffer)
+	for _, val := range values {
+		valLines := make([]string, 0)
+		for _, valLine := range strings.Split(val, "\n") {
+			if normalized := normalizeLine(valLine); normalized != "" {
+				valLines = append(valLines, normalized)
+			}
+		}
+		if len(valLines) == 0 {
+			continue
+		}
 
-	scanner := bufio.NewScanner(buf)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		for marker, vals := range markerAndValues {
-			if strings.TrimSpace(line) == strings.TrimSpace(marker) {
-				for _, val := range vals {
-					_, err := out.WriteString(val)
-					if err != nil {
-						return nil, err
-					}
+		for i := 0; i+len(valLines) <= len(lines); {
+			matches := true
+			for j, valLine := range valLines {
+				if normalizeLine(lines[i+j]) != valLine {
+					matches = false
+					break
 				}
 			}
+			if matches {
+				lines = append(lines[:i], lines[i+len(valLines):]...)
+				continue
+			}
+			i++
 		}
-		_, err := out.WriteString(line + "\n")
-		if err != nil {
+	}
+
+	out := new(bytes.Buffer)
+	for _, line := range lines {
+		if _, err := out.WriteString(line + "\n"); err != nil {
 			return nil, err
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
 	return out, nil
 }
 
-func InsertStringsInFile(path string, markerAndValues map[string][]string) error {
-	isGoFile := false
-	if ext := filepath.Ext(path); ext == ".go" {
-		isGoFile = true
-	}
+// normalizeLine collapses all the whitespace in a line so that formatting
+// changes don't prevent matching
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
 
-	f, err := os.Open(path)
+// RemoveStringsFromFile removes the provided values from the file at the given
+// path. It is the inverse operation of inject.Update.
+func RemoveStringsFromFile(fs afero.Fs, path string, values ...string) error {
+	f, err := fs.Open(path)
 	if err != nil {
 		return err
 	}
 
-	r, err := insertStrings(f, markerAndValues)
+	r, err := removeStrings(f, values)
 	if err != nil {
 		return err
 	}
@@ -95,40 +108,161 @@ func InsertStringsInFile(path string, markerAndValues map[string][]string) error
 		return err
 	}
 
-	formattedContent := content
-	if isGoFile {
-		formattedContent, err = imports.Process(path, content, nil)
+	// use Go import process to format the content and drop the unused imports
+	if filepath.Ext(path) == ".go" {
+		content, err = imports.Process(path, content, nil)
 		if err != nil {
 			return err
 		}
 	}
 
-	// use Go import process to format the content
-	err = ioutil.WriteFile(path, formattedContent, os.ModePerm)
+	return afero.WriteFile(fs, path, content, os.ModePerm)
+}
+
+// uncommentCode reads content from given reader and removes the prefix from
+// every line of the target, which must be found as is in the content. It is a
+// no-op if the target was already uncommented.
+func uncommentCode(r io.Reader, target, prefix string) (io.Reader, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	targetLines := strings.Split(target, "\n")
+	for i, line := range targetLines {
+		targetLines[i] = strings.TrimPrefix(line, prefix)
+	}
+	uncommented := strings.Join(targetLines, "\n")
+
+	if !bytes.Contains(content, []byte(target)) {
+		if bytes.Contains(content, []byte(uncommented)) {
+			return bytes.NewBuffer(content), nil
+		}
+		return nil, fmt.Errorf("unable to find the code to uncomment:\n%s", target)
+	}
+
+	return bytes.NewBuffer(bytes.Replace(content, []byte(target), []byte(uncommented), 1)), nil
+}
+
+// UncommentCodeInFile removes the prefix from every line of the target in the
+// file at the given path, e.g. to enable optional sections of a kustomization.
+func UncommentCodeInFile(fs afero.Fs, path, target, prefix string) error {
+	f, err := fs.Open(path)
+	if err != nil {
+		return err
+	}
+
+	r, err := uncommentCode(f, target, prefix)
+	if err != nil {
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
 
-	return err
+	return afero.WriteFile(fs, path, content, os.ModePerm)
 }
 
-// filterExistingValues removes the single-line values that already exists in
-// the given reader. Multi-line values are ignore currently simply because we
-// don't have a use-case for it.
-func filterExistingValues(r io.Reader, markerAndValues map[string][]string) error {
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		line := scanner.Text()
-		for marker, vals := range markerAndValues {
-			for i, val := range vals {
-				if strings.TrimSpace(line) == strings.TrimSpace(val) {
-					markerAndValues[marker] = append(vals[:i], vals[i+1:]...)
-				}
+// setTypeMarker reads Go code from given reader and adds or removes the marker
+// from the comments of the type declaration. Markers are added right after the
+// +kubebuilder:object:root marker of the type if any, or above its declaration
+// otherwise. It returns whether the content changed.
+func setTypeMarker(r io.Reader, typeName, marker string, set bool) (io.Reader, bool, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	decl := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "type "+typeName+" struct") {
+			decl = i
+			break
+		}
+	}
+	if decl == -1 {
+		return nil, false, fmt.Errorf("unable to find the declaration of type %s", typeName)
+	}
+
+	// The markers of a type are in its doc comment and in the comment block above it
+	first, blanks := decl, 0
+	for i := decl - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			if blanks++; blanks > 1 {
+				break
 			}
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
 		}
+		first = i
 	}
-	if err := scanner.Err(); err != nil {
-		return err
+
+	at, found := decl, -1
+	for i := first; i < decl; i++ {
+		switch strings.TrimSpace(lines[i]) {
+		case marker:
+			found = i
+		case "// +kubebuilder:object:root=true":
+			at = i + 1
+		}
+	}
+
+	switch {
+	case set && found == -1:
+		lines = append(lines[:at], append([]string{marker}, lines[at:]...)...)
+	case !set && found != -1:
+		lines = append(lines[:found], lines[found+1:]...)
+	default:
+		return bytes.NewBuffer(content), false, nil
+	}
+
+	return bytes.NewBufferString(strings.Join(lines, "\n")), true, nil
+}
+
+// SetTypeMarkerInFile adds or removes the marker from the comments of the type
+// declaration in the file at the given path, returning whether it changed.
+func SetTypeMarkerInFile(fs afero.Fs, path, typeName, marker string, set bool) (bool, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return false, err
+	}
+
+	r, changed, err := setTypeMarker(bytes.NewBuffer(content), typeName, marker, set)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	content, err = ioutil.ReadAll(r)
+	if err != nil {
+		return false, err
+	}
+
+	return true, afero.WriteFile(fs, path, content, os.ModePerm)
+}
+
+// HasTypeMarkerInFile returns whether the marker is in the comments of the
+// type declaration in the file at the given path.
+func HasTypeMarkerInFile(fs afero.Fs, path, typeName, marker string) (bool, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return false, err
+	}
+
+	// Setting the marker is a no-op only if it is already there
+	_, changed, err := setTypeMarker(bytes.NewBuffer(content), typeName, marker, true)
+	if err != nil {
+		return false, err
 	}
-	return nil
+	return !changed, nil
 }
@@ -21,72 +21,262 @@ import (
 	"testing"
 )
 
-type insertStrTest struct {
-	input         string
-	markerNValues map[string][]string
-	expected      string
+type removeStrTest struct {
+	input    string
+	values   []string
+	expected string
 }
 
-func TestInsertStrBelowMarker(t *testing.T) {
+func TestRemoveStr(t *testing.T) {
 
-	tests := []insertStrTest{
+	tests := []removeStrTest{
 		{
 			input: `
 v1beta1.AddToScheme(scheme)
+v1.AddToScheme(scheme)
 // +kubebuilder:scaffold:apis-add-scheme
 `,
-			markerNValues: map[string][]string{
-				"// +kubebuilder:scaffold:apis-add-scheme": {
-					"v1.AddToScheme(scheme)\n", "somefunc()\n",
-				},
-			},
+			values: []string{"v1.AddToScheme(scheme)\n"},
 			expected: `
 v1beta1.AddToScheme(scheme)
-v1.AddToScheme(scheme)
-somefunc()
 // +kubebuilder:scaffold:apis-add-scheme
 `,
 		},
-		{ // avoid duplicates
+		{ // multi-line values re-formatted after insertion
 			input: `
-v1beta1.AddToScheme(scheme)
-// +kubebuilder:scaffold:apis-add-scheme
+	if err = (&controllers.FooReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		os.Exit(1)
+	}
+	// +kubebuilder:scaffold:builder
 `,
-			markerNValues: map[string][]string{
-				"// +kubebuilder:scaffold:apis-add-scheme": {
-					"v1beta1.AddToScheme(scheme)\n", "v1.AddToScheme(scheme)\n",
-				},
-			},
+			values: []string{`if err = (&controllers.FooReconciler{
+Client: mgr.GetClient(),
+Scheme:   mgr.GetScheme(),
+}).SetupWithManager(mgr); err != nil {
+	os.Exit(1)
+}
+`},
 			expected: `
-v1beta1.AddToScheme(scheme)
-v1.AddToScheme(scheme)
-// +kubebuilder:scaffold:apis-add-scheme
+	// +kubebuilder:scaffold:builder
 `,
 		},
+		{ // partial matches of multi-line values are kept
+			input: `
+foo()
+baz()
+`,
+			values: []string{"foo()\nbar()\n"},
+			expected: `
+foo()
+baz()
+`,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := removeStrings(bytes.NewBufferString(test.input), test.values)
+		if err != nil {
+			t.Errorf("error %v", err)
+		}
+
+		b, err := ioutil.ReadAll(result)
+		if err != nil {
+			t.Errorf("error: %v", err)
+		}
+
+		if string(b) != test.expected {
+			t.Errorf("got: %s and wanted: %s", string(b), test.expected)
+		}
+	}
+}
+
+type uncommentCodeTest struct {
+	input    string
+	target   string
+	expected string
+	err      bool
+}
+
+func TestUncommentCode(t *testing.T) {
+
+	tests := []uncommentCodeTest{
 		{
-			// string with literal format
 			input: `
-v1beta1.AddToScheme(scheme)
-// +kubebuilder:scaffold:apis-add-scheme
+bases:
+- ../crd
+#- ../webhook
+#- ../prometheus
 `,
-			markerNValues: map[string][]string{
-				"// +kubebuilder:scaffold:apis-add-scheme": {
-					`v1.AddToScheme(scheme)
+			target: "#- ../webhook\n",
+			expected: `
+bases:
+- ../crd
+- ../webhook
+#- ../prometheus
+`,
+		},
+		{ // multi-line targets
+			input: `
+vars:
+#- name: SERVICE_NAME
+#  objref:
+#    kind: Service
+`,
+			target: `#- name: SERVICE_NAME
+#  objref:
+#    kind: Service
 `,
-				},
-			},
 			expected: `
-v1beta1.AddToScheme(scheme)
-v1.AddToScheme(scheme)
-// +kubebuilder:scaffold:apis-add-scheme
+vars:
+- name: SERVICE_NAME
+  objref:
+    kind: Service
+`,
+		},
+		{ // already uncommented targets are kept
+			input: `
+- ../webhook
+`,
+			target: "#- ../webhook\n",
+			expected: `
+- ../webhook
+`,
+		},
+		{ // missing targets fail
+			input: `
+- ../crd
+`,
+			target: "#- ../webhook\n",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := uncommentCode(bytes.NewBufferString(test.input), test.target, "#")
+		if test.err {
+			if err == nil {
+				t.Errorf("expected error for target %q", test.target)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("error %v", err)
+			continue
+		}
+
+		b, err := ioutil.ReadAll(result)
+		if err != nil {
+			t.Errorf("error: %v", err)
+		}
+
+		if string(b) != test.expected {
+			t.Errorf("got: %s and wanted: %s", string(b), test.expected)
+		}
+	}
+}
+
+type setTypeMarkerTest struct {
+	input    string
+	set      bool
+	expected string
+	changed  bool
+}
+
+func TestSetTypeMarker(t *testing.T) {
+
+	tests := []setTypeMarkerTest{
+		{ // added after the root marker
+			input: `
+// +kubebuilder:object:root=true
+
+// Frigate is the Schema for the frigates API
+type Frigate struct {
+}
+
+// +kubebuilder:object:root=true
+
+// FrigateList contains a list of Frigate
+type FrigateList struct {
+}
+`,
+			set: true,
+			expected: `
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+
+// Frigate is the Schema for the frigates API
+type Frigate struct {
+}
+
+// +kubebuilder:object:root=true
+
+// FrigateList contains a list of Frigate
+type FrigateList struct {
+}
+`,
+			changed: true,
+		},
+		{ // added above the declaration without root marker
+			input: `
+// Frigate is the Schema for the frigates API
+type Frigate struct {
+}
+`,
+			set: true,
+			expected: `
+// Frigate is the Schema for the frigates API
+// +kubebuilder:storageversion
+type Frigate struct {
+}
+`,
+			changed: true,
+		},
+		{ // removed
+			input: `
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+
+// Frigate is the Schema for the frigates API
+type Frigate struct {
+}
+`,
+			set: false,
+			expected: `
+// +kubebuilder:object:root=true
+
+// Frigate is the Schema for the frigates API
+type Frigate struct {
+}
+`,
+			changed: true,
+		},
+		{ // already set
+			input: `
+// +kubebuilder:storageversion
+type Frigate struct {
+}
+`,
+			set: true,
+			expected: `
+// +kubebuilder:storageversion
+type Frigate struct {
+}
 `,
 		},
 	}
 
 	for _, test := range tests {
-		result, err := insertStrings(bytes.NewBufferString(test.input), test.markerNValues)
+		result, changed, err := setTypeMarker(bytes.NewBufferString(test.input), "Frigate",
+			"// +kubebuilder:storageversion", test.set)
 		if err != nil {
 			t.Errorf("error %v", err)
+			continue
+		}
+		if changed != test.changed {
+			t.Errorf("got changed: %v and wanted: %v", changed, test.changed)
 		}
 
 		b, err := ioutil.ReadAll(result)
@@ -98,4 +288,9 @@ v1.AddToScheme(scheme)
 			t.Errorf("got: %s and wanted: %s", string(b), test.expected)
 		}
 	}
+
+	if _, _, err := setTypeMarker(bytes.NewBufferString("type Boat struct {\n}\n"), "Frigate",
+		"// +kubebuilder:storageversion", true); err == nil {
+		t.Errorf("expected error for missing type")
+	}
 }
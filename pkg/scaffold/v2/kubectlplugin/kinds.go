@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlplugin
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Kinds{}
+
+// Kinds scaffolds the cmd/kubectl-<name>/kinds.go file with the scheme of the plugin and the Kinds it reads,
+// regenerated every time the plugin is created so that it includes the APIs added since
+type Kinds struct {
+	input.Input
+
+	// Name is the name of the plugin, run as kubectl <name>
+	Name string
+
+	// Imports are the packages of the API versions of the Kinds, whose types are added to the scheme
+	Imports []Import
+
+	// Kinds are the Kinds read by the get and describe commands
+	Kinds []Kind
+}
+
+// Import is the package of an API version
+type Import struct {
+	// Alias is the name the package is imported as, e.g. shipv1beta1
+	Alias string
+
+	// Path is the import path of the package
+	Path string
+}
+
+// Kind is a Kind read by the plugin
+type Kind struct {
+	// Alias is the name of the package of the types of the Kind
+	Alias string
+
+	// Kind is the API Kind, e.g. Frigate
+	Kind string
+
+	// Plural is the API Resource, e.g. frigates
+	Plural string
+
+	// Group is the fully qualified API group, e.g. ship.example.org
+	Group string
+
+	// Namespaced is true if the objects of the Kind belong to a namespace
+	Namespaced bool
+}
+
+// GetInput implements input.File
+func (f *Kinds) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir(f.Name), "kinds.go")
+	}
+	f.TemplateBody = kindsTemplate
+	f.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Kinds) Validate() error {
+	return ValidateName(f.Name)
+}
+
+const kindsTemplate = `{{ .Boilerplate }}
+
+// Code generated by kubebuilder create kubectl-plugin. DO NOT EDIT.
+// Run kubebuilder create kubectl-plugin again after adding APIs to the project.
+
+package main
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+{{- if .Imports }}
+{{ range .Imports }}
+	{{ .Alias }} "{{ .Path }}"
+{{- end }}
+{{- end }}
+)
+
+// scheme contains the types the objects are read into
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+{{- range .Imports }}
+	_ = {{ .Alias }}.AddToScheme(scheme)
+{{- end }}
+}
+
+// kinds are the Kinds of the project read by the get and describe commands
+var kinds = []kind{
+{{- range .Kinds }}
+	{
+		kind:       "{{ .Kind }}",
+		plural:     "{{ .Plural }}",
+		group:      "{{ .Group }}",
+		namespaced: {{ .Namespaced }},
+		newObject:  func() runtime.Object { return &{{ .Alias }}.{{ .Kind }}{} },
+		newList:    func() runtime.Object { return &{{ .Alias }}.{{ .Kind }}List{} },
+	},
+{{- end }}
+}
+`
@@ -0,0 +1,337 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlplugin
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// BinaryName returns the name of the binary of the plugin run by kubectl <name>. kubectl splits the names of the
+// plugin binaries on dashes to find the subcommands, so the dashes of the name are replaced by underscores.
+func BinaryName(name string) string {
+	return "kubectl-" + strings.ReplaceAll(name, "-", "_")
+}
+
+// Dir returns the directory of the main package of the plugin
+func Dir(name string) string {
+	return filepath.Join("cmd", BinaryName(name))
+}
+
+// ValidateName checks that the plugin can be run as kubectl <name>
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("plugin name cannot be empty")
+	}
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("plugin name must consist of lower case alphanumeric characters or '-', and start and "+
+			"end with an alphanumeric character (was %s)", name)
+	}
+	return nil
+}
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+var _ input.File = &Main{}
+
+// Main scaffolds the cmd/kubectl-<name>/main.go file of a kubectl plugin with the get and describe commands of the
+// Kinds of the project
+type Main struct {
+	input.Input
+
+	// Name is the name of the plugin, run as kubectl <name>
+	Name string
+}
+
+// GetInput implements input.File
+func (f *Main) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir(f.Name), "main.go")
+	}
+	f.TemplateBody = mainTemplate
+	// The commands are owned by the user, only the Kinds are regenerated
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Main) Validate() error {
+	return ValidateName(f.Name)
+}
+
+const mainTemplate = `{{ .Boilerplate }}
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// kind is a Kind of the project read by the get and describe commands, the Kinds are listed in kinds.go
+type kind struct {
+	kind       string
+	plural     string
+	group      string
+	namespaced bool
+	newObject  func() runtime.Object
+	newList    func() runtime.Object
+}
+
+// options are the flags shared by the commands
+type options struct {
+	kubeconfig    string
+	context       string
+	namespace     string
+	allNamespaces bool
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	o := &options{}
+
+	cmd := &cobra.Command{
+		Use:          "kubectl {{ .Name }}",
+		Short:        "Read the objects of the APIs of {{ .Name }}",
+		SilenceUsage: true,
+	}
+	cmd.PersistentFlags().StringVar(&o.kubeconfig, "kubeconfig", "",
+		"path to the kubeconfig file, defaults to $KUBECONFIG or ~/.kube/config")
+	cmd.PersistentFlags().StringVar(&o.context, "context", "",
+		"kubeconfig context to use, defaults to the current context")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "",
+		"namespace of the objects, defaults to the one of the kubeconfig context")
+
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "List the objects of a Kind or get one of them by name",
+	}
+	getCmd.PersistentFlags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"list the objects of all the namespaces")
+
+	describeCmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Show the details of an object",
+	}
+
+	for _, k := range kinds {
+		k := k
+		aliases := []string{strings.ToLower(k.kind), k.plural + "." + k.group}
+
+		getCmd.AddCommand(&cobra.Command{
+			Use:     k.plural + " [NAME]",
+			Aliases: aliases,
+			Short:   fmt.Sprintf("List the %s or get one of them by name", k.plural),
+			Args:    cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.get(cmd.OutOrStdout(), k, args)
+			},
+		})
+
+		describeCmd.AddCommand(&cobra.Command{
+			Use:     k.plural + " NAME",
+			Aliases: aliases,
+			Short:   fmt.Sprintf("Show the details of a %s", k.kind),
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.describe(cmd.OutOrStdout(), k, args[0])
+			},
+		})
+	}
+
+	cmd.AddCommand(getCmd, describeCmd)
+	return cmd
+}
+
+// client returns a client reading the objects into the types of the scheme, and the namespace of the objects
+func (o *options) client() (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: o.context})
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	namespace := o.namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	return c, namespace, err
+}
+
+// get prints the objects of the Kind, or the one with the provided name, as a table
+func (o *options) get(out io.Writer, k kind, args []string) error {
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+	if !k.namespaced {
+		namespace = ""
+	}
+
+	var objects []runtime.Object
+	if len(args) == 1 {
+		obj := k.newObject()
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: args[0]}, obj); err != nil {
+			return err
+		}
+		objects = append(objects, obj)
+	} else {
+		if o.allNamespaces {
+			namespace = ""
+		}
+		list := k.newList()
+		if err := c.List(context.Background(), list, client.InNamespace(namespace)); err != nil {
+			return err
+		}
+		if objects, err = meta.ExtractList(list); err != nil {
+			return err
+		}
+	}
+
+	if len(objects) == 0 {
+		fmt.Fprintf(out, "No %s found.\n", k.plural)
+		return nil
+	}
+
+	showNamespace := k.namespaced && o.allNamespaces && len(args) == 0
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tAGE")
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", accessor.GetNamespace())
+		}
+		fmt.Fprintf(w, "%s\t%s\n", accessor.GetName(), age(accessor.GetCreationTimestamp().Time))
+	}
+	return w.Flush()
+}
+
+// describe prints the metadata, spec and status of the object of the Kind with the provided name
+func (o *options) describe(out io.Writer, k kind, name string) error {
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+	if !k.namespaced {
+		namespace = ""
+	}
+
+	obj := k.newObject()
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+
+	created := accessor.GetCreationTimestamp().Time
+	fmt.Fprintf(out, "Name:         %s\n", accessor.GetName())
+	if k.namespaced {
+		fmt.Fprintf(out, "Namespace:    %s\n", accessor.GetNamespace())
+	}
+	fmt.Fprintf(out, "Labels:       %s\n", formatMap(accessor.GetLabels()))
+	fmt.Fprintf(out, "Annotations:  %s\n", formatMap(accessor.GetAnnotations()))
+	fmt.Fprintf(out, "API Version:  %s\n", gvk.GroupVersion())
+	fmt.Fprintf(out, "Kind:         %s\n", gvk.Kind)
+	fmt.Fprintf(out, "Created:      %s (%s ago)\n", created.Format(time.RFC3339), age(created))
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	for _, field := range []string{"spec", "status"} {
+		value, found := content[field]
+		if !found {
+			continue
+		}
+		section, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s:\n", strings.Title(field))
+		for _, line := range strings.Split(strings.TrimSuffix(string(section), "\n"), "\n") {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// formatMap returns the sorted key=value pairs of labels or annotations
+func formatMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\n              ")
+}
+
+// age returns how long ago the provided time was, like the AGE column of kubectl get
+func age(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+`
@@ -17,11 +17,17 @@ limitations under the License.
 package v2
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
 )
 
 var _ input.File = &Kustomize{}
@@ -32,6 +38,12 @@ type Kustomize struct {
 
 	// Prefix to use for name prefix customization
 	Prefix string
+
+	// NamespaceScoped is true if the manager watches its own namespace only
+	NamespaceScoped bool
+
+	// SecureDefaults is true if the manager pods run with a restricted security context
+	SecureDefaults bool
 }
 
 // GetInput implements input.File
@@ -52,51 +64,89 @@ func (f *Kustomize) GetInput() (input.Input, error) {
 	return f.Input, nil
 }
 
-const kustomizeTemplate = `# Adds namespace to all resources.
-namespace: {{.Prefix}}-system
+// EnableWebhook enables the webhook component along with the one providing the certificate of the webhook
+// server: certmanager for cert-manager, webhookca for the certificate generation Job, or none if it is manual
+func (f *Kustomize) EnableWebhook(fs afero.Fs, certProvider string) error {
+	switch certProvider {
+	case config.CertProviderManual:
+		return f.EnableComponents(fs, ComponentWebhook)
+	case config.CertProviderWebhookCA:
+		return f.EnableComponents(fs, ComponentWebhook, ComponentWebhookCA)
+	default:
+		return f.EnableComponents(fs, ComponentWebhook, ComponentCertManager)
+	}
+}
 
-# Value of this field is prepended to the
-# names of all resources, e.g. a deployment named
-# "wordpress" becomes "alices-wordpress".
-# Note that it should also match with the prefix (text before '-') of the namespace
-# field above.
-namePrefix: {{.Prefix}}-
+// EnablePrometheus enables the prometheus component so that the metrics are scraped through the ServiceMonitor
+func (f *Kustomize) EnablePrometheus(fs afero.Fs) error {
+	return f.EnableComponents(fs, ComponentPrometheus)
+}
 
-# Labels to add to all resources and selectors.
-#commonLabels:
-#  someName: someValue
+// EnableComponents adds the components to the default overlay. Projects scaffolded before the optional
+// features were kustomize components have their commented [WEBHOOK], [CERTMANAGER] and [PROMETHEUS]
+// sections uncommented instead, the other components can't be enabled in them.
+func (f *Kustomize) EnableComponents(fs afero.Fs, components ...string) error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "default", "kustomization.yaml")
+	}
 
-bases:
-- ../crd
-- ../rbac
-- ../manager
-# [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in 
-# crd/kustomization.yaml
-#- ../webhook
-# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'. 'WEBHOOK' components are required.
-#- ../certmanager
-# [PROMETHEUS] To enable prometheus monitor, uncomment all sections with 'PROMETHEUS'. 
-#- ../prometheus
+	hasMarker, err := afero.FileContainsBytes(fs, f.Path, []byte(ComponentsScaffoldMarker))
+	if err != nil {
+		return err
+	}
 
-patchesStrategicMerge:
-  # Protect the /metrics endpoint by putting it behind auth.
-  # If you want your controller-manager to expose the /metrics
-  # endpoint w/o any authn/z, please comment the following line.
-- manager_auth_proxy_patch.yaml
+	if hasMarker {
+		entries := make([]string, 0, len(components))
+		for _, component := range components {
+			if _, found := componentTemplates[component]; !found {
+				return fmt.Errorf("unknown component %q", component)
+			}
+			entries = append(entries, fmt.Sprintf("- ../components/%s\n", component))
+		}
+		err = inject.Update(fs, f.Path, map[string][]string{ComponentsScaffoldMarker: entries})
+		if err != nil {
+			return fmt.Errorf("error enabling %s in %s: %v", strings.Join(components, ", "), f.Path, err)
+		}
+		return nil
+	}
 
-# [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in 
-# crd/kustomization.yaml
-#- manager_webhook_patch.yaml
+	for _, component := range components {
+		fragments, found := legacyComponentFragments[component]
+		if !found {
+			return fmt.Errorf("%s can't be enabled in %s, it has no components section", component, f.Path)
+		}
+		for _, target := range fragments {
+			if err := internal.UncommentCodeInFile(fs, f.Path, target, "#"); err != nil {
+				return fmt.Errorf("error enabling %s in %s: %v", component, f.Path, err)
+			}
+		}
+	}
 
-# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'.
-# Uncomment 'CERTMANAGER' sections in crd/kustomization.yaml to enable the CA injection in the admission webhooks.
-# 'CERTMANAGER' needs to be enabled to use ca injection
-#- webhookcainjection_patch.yaml
+	return nil
+}
+
+// ComponentsScaffoldMarker is the marker of the default overlay before which the components are enabled
+const ComponentsScaffoldMarker = "# +kubebuilder:scaffold:components"
+
+// legacyComponentFragments are the commented sections of each component in the default overlay
+// of the projects scaffolded before the optional features were kustomize components
+var legacyComponentFragments = map[string][]string{
+	ComponentWebhook: {kustomizeWebhookBaseFragment, kustomizeWebhookPatchFragment},
+	ComponentCertManager: {
+		kustomizeCertManagerBaseFragment,
+		kustomizeCAInjectionPatchFragment,
+		kustomizeCertManagerVarsFragment,
+	},
+	ComponentPrometheus: {kustomizePrometheusBaseFragment},
+}
 
-# the following config is for teaching kustomize how to do var substitution
-vars:
-# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER' prefix.
-#- name: CERTIFICATE_NAMESPACE # namespace of the certificate CR
+const (
+	kustomizePrometheusBaseFragment   = "#- ../prometheus\n"
+	kustomizeWebhookBaseFragment      = "#- ../webhook\n"
+	kustomizeCertManagerBaseFragment  = "#- ../certmanager\n"
+	kustomizeWebhookPatchFragment     = "#- manager_webhook_patch.yaml\n"
+	kustomizeCAInjectionPatchFragment = "#- webhookcainjection_patch.yaml\n"
+	kustomizeCertManagerVarsFragment  = `#- name: CERTIFICATE_NAMESPACE # namespace of the certificate CR
 #  objref:
 #    kind: Certificate
 #    group: cert-manager.io
@@ -123,3 +173,52 @@ vars:
 #    version: v1
 #    name: webhook-service
 `
+)
+
+var kustomizeTemplate = fmt.Sprintf(`# Adds namespace to all resources.
+namespace: {{.Prefix}}-system
+
+# Value of this field is prepended to the
+# names of all resources, e.g. a deployment named
+# "wordpress" becomes "alices-wordpress".
+# Note that it should also match with the prefix (text before '-') of the namespace
+# field above.
+namePrefix: {{.Prefix}}-
+
+# Labels to add to all resources and selectors.
+#commonLabels:
+#  someName: someValue
+
+bases:
+- ../crd
+- ../rbac
+- ../manager
+
+# Optional features, enable them with "kubebuilder edit --enable=<component>[,<component>...]":
+# - webhook: serves the admission and conversion webhooks from the manager.
+# - certmanager: issues the certificate of the webhook server with cert-manager, requires webhook.
+# - prometheus: scrapes the metrics of the manager with a prometheus ServiceMonitor.
+# - production: adds a PriorityClass, a PodDisruptionBudget, replicas and resources to the manager.
+# - webhookca: issues the certificate of the webhook server with a Job instead of cert-manager, requires webhook.
+# - networkpolicy: only allows the API server to call the webhooks and the manager to call the API server.
+# - observability: provisions Grafana dashboards and alerts for the metrics of the manager, requires prometheus.
+# Components require kustomize v3.7.0+.
+components:
+%s
+
+patchesStrategicMerge:
+  # Protect the /metrics endpoint by putting it behind auth.
+  # If you want your controller-manager to expose the /metrics
+  # endpoint w/o any authn/z, please comment the following line.
+- manager_auth_proxy_patch.yaml
+{{- if .NamespaceScoped }}
+
+# Restrict the manager to watch the namespace it is deployed to, where its Roles are granted.
+- manager_namespace_patch.yaml
+{{- end }}
+{{- if .SecureDefaults }}
+
+# Relax the restricted security context of the manager if it needs more privileges.
+#- manager_relax_security_patch.yaml
+{{- end }}
+`, ComponentsScaffoldMarker)
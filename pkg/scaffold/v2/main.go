@@ -18,11 +18,17 @@ package v2
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
 
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
 )
@@ -31,6 +37,8 @@ const (
 	APIPkgImportScaffoldMarker    = "// +kubebuilder:scaffold:imports"
 	APISchemeScaffoldMarker       = "// +kubebuilder:scaffold:scheme"
 	ReconcilerSetupScaffoldMarker = "// +kubebuilder:scaffold:builder"
+	// FlagsScaffoldMarker is where the flags configuring the controllers are bound, before parsing them
+	FlagsScaffoldMarker = "// +kubebuilder:scaffold:flags"
 )
 
 var _ input.File = &Main{}
@@ -38,6 +46,24 @@ var _ input.File = &Main{}
 // Main scaffolds a main.go to run Controllers
 type Main struct {
 	input.Input
+
+	// NamespaceScoped is true if the manager watches the namespaces set with WATCH_NAMESPACE only
+	NamespaceScoped bool
+
+	// WebhookPort is the default port of the webhook server, the one of the manifests
+	WebhookPort int
+
+	// WebhookHost is the default address the webhook server binds to, all the addresses of the pod if empty
+	WebhookHost string
+
+	// MetricsAddress is the default address the metrics endpoint binds to, e.g. [::]:8080
+	MetricsAddress string
+
+	// ComponentConfig is true if the options of the manager can be loaded from a ControllerManagerConfiguration file
+	ComponentConfig bool
+
+	// Sharding is true if the controllers only reconcile the objects of the shard of the manager
+	Sharding bool
 }
 
 // GetInput implements input.File
@@ -45,95 +71,332 @@ func (f *Main) GetInput() (input.Input, error) {
 	if f.Path == "" {
 		f.Path = filepath.Join("main.go")
 	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
+	if f.MetricsAddress == "" {
+		f.MetricsAddress = config.DefaultMetricsBindAddress
+	}
 	f.TemplateBody = mainTemplate
 	return f.Input, nil
 }
 
-// Update updates main.go with code fragments required to wire a new
-// resource/controller.
-func (f *Main) Update(opts *MainUpdateOptions) error {
-	path := "main.go"
+// mainCodeFragments contains the code fragments that are injected in main.go
+type mainCodeFragments struct {
+	apiImport string
+	// schemeImport and addScheme register the API version in the scheme, through the apis package in multigroup projects
+	schemeImport string
+	addScheme    string
+	// versionAddScheme registers the API version on its own, used to remove it
+	versionAddScheme string
+	ctrlImport       string
+	reconcilerSetup  string
+	// reconcilerSetups are the reconciler setups with and without event recorder and controller options,
+	// used to remove it
+	reconcilerSetups []string
+	// optionsImport and bindOptions bind the flags of the options shared by the controllers
+	optionsImport string
+	bindOptions   string
+	// featureGatesImport and bindFeatureGates bind the --feature-gates flag of the experimental reconcile paths
+	featureGatesImport string
+	bindFeatureGates   string
+	// bindReconcilePeriod binds the flag of the delay after which the controller reconciles the objects again
+	bindReconcilePeriod string
+	webhookSetup        string
+	// legacyWebhookSetup is the webhook setup without the ENABLE_WEBHOOKS guard, used to remove it from older projects
+	legacyWebhookSetup string
+	// coreWebhookImport and coreWebhookSetup register the webhooks of a Kubernetes built-in type
+	coreWebhookImport string
+	coreWebhookSetup  string
+}
 
+func newMainCodeFragments(opts *MainUpdateOptions) mainCodeFragments {
 	resPkg, _ := util.GetResourceInfo(opts.Resource, opts.Config.Repo, opts.Config.Domain, opts.Config.MultiGroup)
 
 	// generate all the code fragments
-	apiImportCodeFragment := fmt.Sprintf(`%s%s "%s/%s"
+	fragments := mainCodeFragments{}
+
+	// The controllers whose concurrency and rate limiting are tunable are given the options set with the flags
+	setupArgsVariants := []string{"mgr", "mgr, controllerOptions.ControllerOptions()"}
+
+	// The controllers recording events are given a recorder named after them
+	recorderField := fmt.Sprintf(`
+		Recorder: mgr.GetEventRecorderFor("%s-controller"),`, strings.ToLower(opts.Resource.Kind))
+
+	// The controllers polling their objects are given the reconcile period set with their flag
+	reconcilePeriodVar := strings.ToLower(opts.Resource.Kind) + "ReconcilePeriod"
+	periodField := fmt.Sprintf(`
+		ReconcilePeriod: %s,`, reconcilePeriodVar)
+
+	fragments.apiImport = fmt.Sprintf(`%s%s "%s/%s"
 `, opts.Resource.GroupImportSafe, opts.Resource.Version, resPkg, opts.Resource.Version)
 
-	addschemeCodeFragment := fmt.Sprintf(`_ = %s%s.AddToScheme(scheme)
+	fragments.versionAddScheme = fmt.Sprintf(`_ = %s%s.AddToScheme(scheme)
 `, opts.Resource.GroupImportSafe, opts.Resource.Version)
 
-	var reconcilerSetupCodeFragment, ctrlImportCodeFragment string
+	fragments.schemeImport, fragments.addScheme = fragments.apiImport, fragments.versionAddScheme
+	if opts.Config.MultiGroup && resPkg == path.Join(opts.Config.Repo, "apis", opts.Resource.Group) {
+		fragments.schemeImport = fmt.Sprintf(`"%s/apis"
+`, opts.Config.Repo)
+		fragments.addScheme = `_ = apis.AddToScheme(scheme)
+`
+	}
 
 	if opts.Config.MultiGroup {
 
-		ctrlImportCodeFragment = fmt.Sprintf(`controller%s "%s/controllers/%s"
-`, opts.Resource.GroupImportSafe, opts.Config.Repo, opts.Resource.Group)
+		fragments.ctrlImport = fmt.Sprintf(`controller%s "%s"
+`, opts.Resource.GroupImportSafe, path.Join(opts.Config.Repo, "controllers", opts.Resource.Group))
 
-		reconcilerSetupCodeFragment = fmt.Sprintf(`if err = (&controller%s.%sReconciler{
+		for _, setupArgs := range setupArgsVariants {
+			for _, recorder := range []string{"", recorderField} {
+				for _, period := range []string{"", periodField} {
+					fragments.reconcilerSetups = append(fragments.reconcilerSetups, fmt.Sprintf(`if err = (&controller%s.%sReconciler{
 		Client: mgr.GetClient(),
 		Log: ctrl.Log.WithName("controllers").WithName("%s"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+		Scheme: mgr.GetScheme(),%s%s
+	}).SetupWithManager(%s); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "%s")
 		os.Exit(1)
 	}
-`, opts.Resource.GroupImportSafe, opts.Resource.Kind, opts.Resource.Kind, opts.Resource.Kind)
+`, opts.Resource.GroupImportSafe, opts.Resource.Kind, opts.Resource.Kind, recorder, period, setupArgs,
+						opts.Resource.Kind))
+				}
+			}
+		}
 	} else {
 
-		ctrlImportCodeFragment = fmt.Sprintf(`"%s/controllers"
+		fragments.ctrlImport = fmt.Sprintf(`"%s/controllers"
 `, opts.Config.Repo)
 
-		reconcilerSetupCodeFragment = fmt.Sprintf(`if err = (&controllers.%sReconciler{
+		for _, setupArgs := range setupArgsVariants {
+			for _, recorder := range []string{"  ", recorderField} {
+				for _, period := range []string{"", periodField} {
+					fragments.reconcilerSetups = append(fragments.reconcilerSetups, fmt.Sprintf(`if err = (&controllers.%sReconciler{
 		Client: mgr.GetClient(),
 		Log: ctrl.Log.WithName("controllers").WithName("%s"),
-		Scheme: mgr.GetScheme(),  
-	}).SetupWithManager(mgr); err != nil {
+		Scheme: mgr.GetScheme(),%s%s
+	}).SetupWithManager(%s); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "%s")
 		os.Exit(1)
 	}
-`, opts.Resource.Kind, opts.Resource.Kind, opts.Resource.Kind)
+`, opts.Resource.Kind, opts.Resource.Kind, recorder, period, setupArgs, opts.Resource.Kind))
+				}
+			}
+		}
+	}
+
+	// The setups are ordered by controller options, then event recorder, then reconcile period
+	setupIndex := 0
+	if opts.Resource.ControllerOptions {
+		setupIndex += 4
+	}
+	if opts.Resource.Events {
+		setupIndex += 2
+	}
+	if opts.Resource.ReconcilePeriod != 0 {
+		setupIndex++
+	}
+	fragments.reconcilerSetup = fragments.reconcilerSetups[setupIndex]
+
+	// The options of the controllers are shared by all the groups
+	fragments.optionsImport = fmt.Sprintf(`"%s/controllers"
+`, opts.Config.Repo)
+	fragments.bindOptions = `controllerOptions := controllers.Options{}
+	controllerOptions.BindFlags(flag.CommandLine)
+`
+
+	// The feature gates are shared by all the controllers
+	fragments.featureGatesImport = fmt.Sprintf(`"%s/featuregates"
+`, opts.Config.Repo)
+	fragments.bindFeatureGates = `flag.Var(featuregates.Gates, "feature-gates", featuregates.Usage())
+`
+
+	// The default reconcile period is the one the controller was scaffolded with
+	if opts.Resource.ReconcilePeriod != 0 {
+		fragments.bindReconcilePeriod = fmt.Sprintf(`var %s time.Duration
+	flag.DurationVar(&%s, "%s-reconcile-period", %s,
+		"The delay after which the %s objects are reconciled again.")
+`, reconcilePeriodVar, reconcilePeriodVar, strings.ToLower(opts.Resource.Kind),
+			templatefuncs.GoDuration(opts.Resource.ReconcilePeriod), opts.Resource.Kind)
+	}
 
+	// The webhook server needs certificates, ENABLE_WEBHOOKS=false allows running the manager locally without them
+	fragments.webhookSetup = fmt.Sprintf(`if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&%s%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "%s")
+			os.Exit(1)
+		}
 	}
+`, opts.Resource.GroupImportSafe, opts.Resource.Version, opts.Resource.Kind, opts.Resource.Kind)
 
-	webhookSetupCodeFragment := fmt.Sprintf(`if err = (&%s%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
+	fragments.legacyWebhookSetup = fmt.Sprintf(`if err = (&%s%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "%s")
 		os.Exit(1)
 	}
 `, opts.Resource.GroupImportSafe, opts.Resource.Version, opts.Resource.Kind, opts.Resource.Kind)
 
+	fragments.coreWebhookImport = fmt.Sprintf(`webhook%s%s "%s/webhooks/%s/%s"
+`, opts.Resource.GroupImportSafe, opts.Resource.Version, opts.Config.Repo, opts.Resource.Group, opts.Resource.Version)
+
+	fragments.coreWebhookSetup = fmt.Sprintf(`if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhook%s%s.Setup%sWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "%s")
+			os.Exit(1)
+		}
+	}
+`, opts.Resource.GroupImportSafe, opts.Resource.Version, opts.Resource.Kind, opts.Resource.Kind)
+
+	return fragments
+}
+
+// Update updates main.go with code fragments required to wire a new
+// resource/controller.
+func (f *Main) Update(opts *MainUpdateOptions) error {
+	markerAndValues, err := f.Fragments(opts)
+	if err != nil || len(markerAndValues) == 0 {
+		return err
+	}
+
+	return inject.Update(opts.fs(), "main.go", markerAndValues)
+}
+
+// Fragments returns the code fragments inserted by Update below each marker of main.go
+func (f *Main) Fragments(opts *MainUpdateOptions) (map[string][]string, error) {
+	fragments := newMainCodeFragments(opts)
+
+	markerAndValues := make(map[string][]string)
 	if opts.WireResource {
-		err := internal.InsertStringsInFile(path,
-			map[string][]string{
-				APIPkgImportScaffoldMarker: {apiImportCodeFragment},
-				APISchemeScaffoldMarker:    {addschemeCodeFragment},
-			})
-		if err != nil {
-			return err
+		inject.Merge(markerAndValues, map[string][]string{
+			APIPkgImportScaffoldMarker: {fragments.schemeImport},
+			APISchemeScaffoldMarker:    {fragments.addScheme},
+		})
+	}
+
+	if opts.WireController && opts.Resource.ControllerOptions {
+		if err := requireFlagsMarker(opts, "the flags of the controller options"); err != nil {
+			return nil, err
 		}
+
+		inject.Merge(markerAndValues, map[string][]string{
+			APIPkgImportScaffoldMarker: {fragments.optionsImport},
+			FlagsScaffoldMarker:        {fragments.bindOptions},
+		})
 	}
 
-	if opts.WireController {
-		return internal.InsertStringsInFile(path,
-			map[string][]string{
-				APIPkgImportScaffoldMarker:    {apiImportCodeFragment, ctrlImportCodeFragment},
-				APISchemeScaffoldMarker:       {addschemeCodeFragment},
-				ReconcilerSetupScaffoldMarker: {reconcilerSetupCodeFragment},
-			})
+	if opts.WireController && opts.Resource.FeatureGate {
+		if err := requireFlagsMarker(opts, "the --feature-gates flag"); err != nil {
+			return nil, err
+		}
+
+		inject.Merge(markerAndValues, map[string][]string{
+			APIPkgImportScaffoldMarker: {fragments.featureGatesImport},
+			FlagsScaffoldMarker:        {fragments.bindFeatureGates},
+		})
 	}
 
-	if opts.WireWebhook {
-		return internal.InsertStringsInFile(path,
-			map[string][]string{
-				APIPkgImportScaffoldMarker:    {apiImportCodeFragment, ctrlImportCodeFragment},
-				APISchemeScaffoldMarker:       {addschemeCodeFragment},
-				ReconcilerSetupScaffoldMarker: {webhookSetupCodeFragment},
-			})
+	if opts.WireController && opts.Resource.ReconcilePeriod != 0 {
+		if err := requireFlagsMarker(opts, "the reconcile period flag"); err != nil {
+			return nil, err
+		}
+
+		inject.Merge(markerAndValues, map[string][]string{
+			FlagsScaffoldMarker: {fragments.bindReconcilePeriod},
+		})
+	}
+
+	switch {
+	case opts.WireController:
+		inject.Merge(markerAndValues, map[string][]string{
+			APIPkgImportScaffoldMarker:    {fragments.schemeImport, fragments.ctrlImport},
+			APISchemeScaffoldMarker:       {fragments.addScheme},
+			ReconcilerSetupScaffoldMarker: {fragments.reconcilerSetup},
+		})
+	case opts.WireWebhook:
+		// The webhook setup refers to the API version package, which may not be the one registering it
+		imports := []string{fragments.apiImport, fragments.ctrlImport}
+		if fragments.schemeImport != fragments.apiImport {
+			imports = append(imports, fragments.schemeImport)
+		}
+		inject.Merge(markerAndValues, map[string][]string{
+			APIPkgImportScaffoldMarker:    imports,
+			APISchemeScaffoldMarker:       {fragments.addScheme},
+			ReconcilerSetupScaffoldMarker: {fragments.webhookSetup},
+		})
+	case opts.WireCoreWebhook:
+		// The types of the Kubernetes built-in types are registered with the client-go scheme
+		inject.Merge(markerAndValues, map[string][]string{
+			APIPkgImportScaffoldMarker:    {fragments.coreWebhookImport},
+			ReconcilerSetupScaffoldMarker: {fragments.coreWebhookSetup},
+		})
 	}
 
+	return markerAndValues, nil
+}
+
+// requireFlagsMarker checks that main.go has the marker below which the flags are bound
+func requireFlagsMarker(opts *MainUpdateOptions, flags string) error {
+	content, err := afero.ReadFile(opts.fs(), "main.go")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(content), FlagsScaffoldMarker) {
+		return fmt.Errorf("main.go is missing the %q marker, add it before flag.Parse() to bind %s",
+			FlagsScaffoldMarker, flags)
+	}
 	return nil
 }
 
+// Remove removes from main.go the code fragments that were used to wire a
+// resource/controller/webhook. Unused imports are dropped when formatting.
+func (f *Main) Remove(opts *MainUpdateOptions) error {
+	path := "main.go"
+
+	fragments := newMainCodeFragments(opts)
+
+	values := make([]string, 0, 5)
+	// The apis package registration is shared by all the API versions of multigroup projects
+	if opts.WireResource {
+		values = append(values, fragments.versionAddScheme)
+	}
+	if opts.WireController {
+		values = append(values, fragments.reconcilerSetups...)
+	}
+	if opts.WireWebhook {
+		values = append(values, fragments.webhookSetup, fragments.legacyWebhookSetup)
+	}
+	if opts.WireCoreWebhook {
+		values = append(values, fragments.coreWebhookSetup)
+	}
+
+	return internal.RemoveStringsFromFile(opts.fs(), path, values...)
+}
+
+// IsWired returns whether main.go registers the API version in the scheme and sets up the controller of the resource
+func (f *Main) IsWired(opts *MainUpdateOptions) (bool, bool, error) {
+	content, err := afero.ReadFile(opts.fs(), "main.go")
+	if err != nil {
+		return false, false, err
+	}
+	main := string(content)
+
+	fragments := newMainCodeFragments(opts)
+	registered := strings.Contains(main, fragments.versionAddScheme)
+	// The API versions registered through the apis package need their addtoscheme file
+	if !registered && fragments.addScheme != fragments.versionAddScheme && strings.Contains(main, fragments.addScheme) {
+		addToScheme := &AddToScheme{Resource: opts.Resource}
+		if _, err := addToScheme.GetInput(); err != nil {
+			return false, false, err
+		}
+		if registered, err = afero.Exists(opts.fs(), addToScheme.Path); err != nil {
+			return false, false, err
+		}
+	}
+	// The fields of the reconciler may have been changed, so only its type is looked up
+	setUp := strings.Contains(main, fmt.Sprintf(".%sReconciler{", opts.Resource.Kind))
+
+	return registered, setUp, nil
+}
+
 // MainUpdateOptions contains info required for wiring an API/Controller in
 // main.go.
 type MainUpdateOptions struct {
@@ -147,6 +410,18 @@ type MainUpdateOptions struct {
 	WireResource   bool
 	WireController bool
 	WireWebhook    bool
+	// WireCoreWebhook wires the webhooks of a Kubernetes built-in type, scaffolded under the webhooks directory
+	WireCoreWebhook bool
+
+	// Fs is the filesystem where main.go is updated, defaults to the OS filesystem
+	Fs afero.Fs
+}
+
+func (opts *MainUpdateOptions) fs() afero.Fs {
+	if opts.Fs == nil {
+		return afero.NewOsFs()
+	}
+	return opts.Fs
 }
 
 var mainTemplate = fmt.Sprintf(`{{ .Boilerplate }}
@@ -154,13 +429,43 @@ var mainTemplate = fmt.Sprintf(`{{ .Boilerplate }}
 package main
 
 import (
+{{- if .NamespaceScoped }}
+	"context"
+{{- end }}
 	"flag"
 	"os"
+{{- if .NamespaceScoped }}
+	"strings"
+{{- end }}
+	"time"
+{{- if .NamespaceScoped }}
+	"k8s.io/apimachinery/pkg/api/meta"
+{{- end }}
 	"k8s.io/apimachinery/pkg/runtime"
+{{- if .NamespaceScoped }}
+	"k8s.io/apimachinery/pkg/runtime/schema"
+{{- end }}
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+{{- if .NamespaceScoped }}
+	"k8s.io/client-go/rest"
+{{- end }}
 	ctrl "sigs.k8s.io/controller-runtime"
+{{- if .NamespaceScoped }}
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+{{- end }}
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+{{- if .ComponentConfig }}
+
+	configv1alpha1 "{{ .Repo }}/managerconfig/v1alpha1"
+{{- end }}
+{{- if .Sharding }}
+
+	"{{ .Repo }}/sharding"
+{{- end }}
 	%s
 )
 
@@ -177,23 +482,120 @@ func init() {
 
 func main() {
 	var metricsAddr string
+	var probeAddr string
 	var enableLeaderElection bool
-	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
+	var webhookHost, webhookCertDir string
+	var webhookPort int
+{{- if .ComponentConfig }}
+	var configFile string
+{{- end }}
+{{- if .NamespaceScoped }}
+	var namespace string
+{{- end }}
+	flag.StringVar(&metricsAddr, "metrics-bind-address", "{{ .MetricsAddress }}",
+		"The address the metric endpoint binds to, e.g. [::]:8080 for all the IPv4 and IPv6 addresses of the pod.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081",
+		"The address the liveness (/healthz) and readiness (/readyz) probe endpoints bind to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. " +
 		"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"Duration that non-leader candidates wait after the last renewal to force acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"Duration that the leader retries refreshing leadership before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"Duration that the leader election clients wait between tries of actions.")
+	flag.StringVar(&webhookHost, "webhook-host", "{{ .WebhookHost }}",
+		"The address the webhook server binds to, all the addresses of the pod if empty.")
+	flag.IntVar(&webhookPort, "webhook-port", {{ .WebhookPort }},
+		"The port the webhook server listens on, e.g. a free port of the nodes when the pods use the host network.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"The directory of the tls.crt and tls.key files of the webhook server.")
+{{- if .ComponentConfig }}
+	flag.StringVar(&configFile, "config", "",
+		"The path of the ControllerManagerConfiguration file, the options it sets override the ones of the flags.")
+{{- end }}
+{{- if .NamespaceScoped }}
+	flag.StringVar(&namespace, "namespace", os.Getenv("WATCH_NAMESPACE"),
+		"Comma-separated list of the namespaces watched by the controller manager. " +
+		"Defaults to the WATCH_NAMESPACE environment variable.")
+{{- end }}
+	%s
+	// The logger is configured with the --zap-devel, --zap-encoder, --zap-log-level and --zap-stacktrace-level flags,
+	// e.g. --zap-devel=false --zap-log-level=info logs JSON at the info level for production
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(func(o *zap.Options) {
-		o.Development = true
-	}))
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+{{ if .Sharding }}
+	// The controllers only reconcile the objects of the shard of the pod, all of them unless SHARD_COUNT is set
+	shard, err := sharding.FromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to get the shard of the manager")
+		os.Exit(1)
+	}
+	sharding.Current = shard
+	setupLog.Info("reconciling the objects of the shard", "index", shard.Index, "count", shard.Count)
+{{ end }}
+{{- if .NamespaceScoped }}
+	// The controller manager is only granted permissions in the namespaces it watches
+	if namespace == "" {
+		setupLog.Error(nil, "a namespace to watch is required, set it with --namespace or WATCH_NAMESPACE")
+		os.Exit(1)
+	}
+{{ end }}
+{{- if or .NamespaceScoped .ComponentConfig }}
+	options := ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: metricsAddr,
+		HealthProbeBindAddress: probeAddr,
+		LeaderElection:     enableLeaderElection,
+		LeaseDuration:      &leaseDuration,
+		RenewDeadline:      &renewDeadline,
+		RetryPeriod:        &retryPeriod,
+		Host:               webhookHost,
+		Port:               webhookPort,
+		CertDir:            webhookCertDir,
+	}
+{{- if .NamespaceScoped }}
+	if namespaces := strings.Split(namespace, ","); len(namespaces) > 1 {
+		options.NewCache = newMultiNamespaceCache(namespaces)
+	} else {
+		options.Namespace = namespace
+	}
+{{- end }}
+{{- if .ComponentConfig }}
+
+	// The options set in the ControllerManagerConfiguration file override the ones of the flags
+	if configFile != "" {
+		managerConfig, err := configv1alpha1.Load(configFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load the config file", "path", configFile)
+			os.Exit(1)
+		}
+		options = managerConfig.AndFrom(options)
+	}
+{{- end }}
 
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+{{- else }}
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
+		HealthProbeBindAddress: probeAddr,
 		LeaderElection:     enableLeaderElection,
-		Port:               9443, 
+		LeaseDuration:      &leaseDuration,
+		RenewDeadline:      &renewDeadline,
+		RetryPeriod:        &retryPeriod,
+		Host:               webhookHost,
+		Port:               webhookPort,
+		CertDir:            webhookCertDir,
 	})
+{{- end }}
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -201,10 +603,130 @@ func main() {
 
 	%s
 
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
-`, APIPkgImportScaffoldMarker, APISchemeScaffoldMarker, ReconcilerSetupScaffoldMarker)
+{{- if .NamespaceScoped }}
+
+// newMultiNamespaceCache returns a builder of the cache of the manager watching several namespaces. The objects of
+// cluster-scoped resources, which the multi-namespace cache doesn't serve, are cached from the whole cluster.
+func newMultiNamespaceCache(namespaces []string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		namespaced, err := cache.MultiNamespacedCacheBuilder(namespaces)(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		opts.Namespace = ""
+		clusterScoped, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &multiNamespaceCache{
+			Cache:         namespaced,
+			clusterScoped: clusterScoped,
+			scheme:        opts.Scheme,
+			mapper:        opts.Mapper,
+		}, nil
+	}
+}
+
+// multiNamespaceCache reads the objects of namespaced resources from the caches of the watched namespaces and the
+// objects of cluster-scoped resources from the cache of the cluster
+type multiNamespaceCache struct {
+	cache.Cache
+	clusterScoped cache.Cache
+	scheme        *runtime.Scheme
+	mapper        meta.RESTMapper
+}
+
+func (c *multiNamespaceCache) cacheForKind(gvk schema.GroupVersionKind) (cache.Cache, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return c.clusterScoped, nil
+	}
+	return c.Cache, nil
+}
+
+func (c *multiNamespaceCache) cacheFor(obj runtime.Object) (cache.Cache, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	// The lists are cached with the objects of their kind
+	if meta.IsListType(obj) {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return c.cacheForKind(gvk)
+}
+
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	objCache, err := c.cacheFor(obj)
+	if err != nil {
+		return err
+	}
+	return objCache.Get(ctx, key, obj)
+}
+
+func (c *multiNamespaceCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	listCache, err := c.cacheFor(list)
+	if err != nil {
+		return err
+	}
+	return listCache.List(ctx, list, opts...)
+}
+
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	objCache, err := c.cacheFor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return objCache.GetInformer(obj)
+}
+
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	kindCache, err := c.cacheForKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	return kindCache.GetInformerForKind(gvk)
+}
+
+func (c *multiNamespaceCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	objCache, err := c.cacheFor(obj)
+	if err != nil {
+		return err
+	}
+	return objCache.IndexField(obj, field, extractValue)
+}
+
+func (c *multiNamespaceCache) Start(stop <-chan struct{}) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.clusterScoped.Start(stop)
+	}()
+	if err := c.Cache.Start(stop); err != nil {
+		return err
+	}
+	return <-errs
+}
+
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return c.Cache.WaitForCacheSync(stop) && c.clusterScoped.WaitForCacheSync(stop)
+}
+{{- end }}
+`, APIPkgImportScaffoldMarker, APISchemeScaffoldMarker, FlagsScaffoldMarker, ReconcilerSetupScaffoldMarker)
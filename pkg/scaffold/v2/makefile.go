@@ -17,7 +17,14 @@ limitations under the License.
 package v2
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 )
 
 var _ input.File = &Makefile{}
@@ -29,6 +36,15 @@ type Makefile struct {
 	Image string
 	// Controller tools version to use in the project
 	ControllerToolsVersion string
+	// CRDVersion is the API version of the CustomResourceDefinitions generated by controller-gen
+	CRDVersion string
+	// ModuleDir is the directory of the project in the module of a shared go.mod, e.g. operators/foo, whose root
+	// is the build context of the image. Empty if the project has its own go.mod.
+	ModuleDir string
+	// ModuleRoot is the path of the root of the module relative to the project, e.g. ../..
+	ModuleRoot string
+	// Sharding is true if the manager can be deployed as a StatefulSet of shards with the config/sharding overlay
+	Sharding bool
 }
 
 // GetInput implements input.File
@@ -39,17 +55,79 @@ func (f *Makefile) GetInput() (input.Input, error) {
 	if f.Image == "" {
 		f.Image = "controller:latest"
 	}
+	f.ModuleRoot = "."
+	if f.ModuleDir != "" {
+		f.ModuleRoot = util.ModuleRoot(f.ModuleDir)
+	}
 	f.TemplateBody = makefileTemplate
 	f.Input.IfExistsAction = input.Error
 	return f.Input, nil
 }
 
+// AddChartTarget appends a chart target that copies the generated manifests into the Helm chart at chartDir
+// It is a no-op if the Makefile already has a chart target
+func (f *Makefile) AddChartTarget(fs afero.Fs, chartDir string) error {
+	return f.addTarget(fs, "chart", fmt.Sprintf(makefileChartTarget, chartDir))
+}
+
+// AddBundleTarget appends a bundle-build target that builds the OLM bundle image
+// It is a no-op if the Makefile already has a bundle-build target
+func (f *Makefile) AddBundleTarget(fs afero.Fs) error {
+	return f.addTarget(fs, "bundle-build", makefileBundleTarget)
+}
+
+// AddClientGenTarget appends a generate-clients target that runs hack/update-codegen.sh with the generators of
+// the provided k8s.io/code-generator version
+// It is a no-op if the Makefile already has a generate-clients target
+func (f *Makefile) AddClientGenTarget(fs afero.Fs, codeGeneratorVersion string) error {
+	return f.addTarget(fs, "generate-clients", fmt.Sprintf(makefileClientGenTarget, codeGeneratorVersion))
+}
+
+// AddKubectlPluginTarget appends a kubectl-plugin target that builds the kubectl plugin binary from cmd/<binary>
+// It is a no-op if the Makefile already has a kubectl-plugin target
+func (f *Makefile) AddKubectlPluginTarget(fs afero.Fs, binary string) error {
+	return f.addTarget(fs, "kubectl-plugin", fmt.Sprintf(makefileKubectlPluginTarget, binary))
+}
+
+// addTarget appends the provided fragment unless the Makefile already defines the target
+func (f *Makefile) addTarget(fs afero.Fs, target, fragment string) error {
+	if f.Path == "" {
+		f.Path = "Makefile"
+	}
+
+	content, err := afero.ReadFile(fs, f.Path)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(content), fmt.Sprintf("\n%s:", target)) {
+		return nil
+	}
+
+	content = append(content, fragment...)
+	return afero.WriteFile(fs, f.Path, content, os.ModePerm)
+}
+
 // nolint:lll
 const makefileTemplate = `
 # Image URL to use all building/pushing image targets
 IMG ?= {{ .Image }}
+# Platforms of the multi-arch image built by docker-buildx, keep them in sync
+# with the architectures the manager is scheduled on in config/manager
+PLATFORMS ?= linux/amd64,linux/arm64,linux/ppc64le
+# Name of the docker buildx builder of the multi-arch image
+BUILDX_BUILDER ?= controller-builder
+{{- if eq .CRDVersion "v1" }}
+# Produce apiextensions.k8s.io/v1 CRDs with structural schemas, which require Kubernetes 1.16+
+CRD_OPTIONS ?= "crd:crdVersions=v1"
+{{- else }}
 # Produce CRDs that work back to Kubernetes 1.11 (no version conversion)
 CRD_OPTIONS ?= "crd:trivialVersions=true"
+{{- end }}
+
+# Name of the kind cluster to deploy the controller in
+KIND_CLUSTER ?= kind
+# Image loaded into the kind cluster, it isn't tagged latest so that the nodes don't try to pull it
+KIND_IMG ?= controller:kind
 
 # Get the currently used golang install path (in GOPATH/bin, unless GOBIN is set)
 ifeq (,$(shell go env GOBIN))
@@ -84,6 +162,13 @@ uninstall: manifests
 deploy: manifests
 	cd config/manager && kustomize edit set image controller=${IMG}
 	kustomize build config/default | kubectl apply -f -
+{{- if .Sharding }}
+
+# Deploy controller as a StatefulSet of shards in the configured Kubernetes cluster in ~/.kube/config
+deploy-sharding: manifests
+	cd config/sharding && kustomize edit set image controller=${IMG}
+	kustomize build config/sharding | kubectl apply -f -
+{{- end }}
 
 # Generate manifests e.g. CRD, RBAC etc.
 manifests: controller-gen
@@ -101,14 +186,48 @@ vet:
 generate: controller-gen
 	$(CONTROLLER_GEN) object:headerFile={{printf "%q" .BoilerplatePath}} paths="./..."
 
+# Check that the generated code is up to date, e.g. in CI
+verify-generate: generate
+	@if [ -n "$$(git status --porcelain --untracked-files=all -- '*zz_generated.*.go')" ]; then \
+		echo "The generated code is out of date, run make generate and commit it:"; \
+		git status --porcelain --untracked-files=all -- '*zz_generated.*.go'; \
+		exit 1; \
+	fi
+
 # Build the docker image
 docker-build: test
-	docker build . -t ${IMG}
+	docker build {{ if .ModuleDir }}-f Dockerfile -t ${IMG} {{ .ModuleRoot }}{{ else }}. -t ${IMG}{{ end }}
 
 # Push the docker image
 docker-push:
 	docker push ${IMG}
 
+# Build and push the docker image for every platform in PLATFORMS, the builder stage runs
+# on the platform of the host and cross-compiles the manager instead of being emulated
+docker-buildx: test
+	sed -e 's/^FROM golang/FROM --platform=$${BUILDPLATFORM} golang/' Dockerfile > Dockerfile.cross
+	docker buildx inspect $(BUILDX_BUILDER) >/dev/null 2>&1 || docker buildx create --name $(BUILDX_BUILDER)
+	docker buildx build --builder $(BUILDX_BUILDER) --push --platform $(PLATFORMS) -t ${IMG} -f Dockerfile.cross {{ .ModuleRoot }} ; \
+	status=$$? ; rm -f Dockerfile.cross ; exit $$status
+
+# Create the kind cluster to deploy the controller in, unless it already exists
+kind-create:
+	kind get clusters | grep -qx $(KIND_CLUSTER) || kind create cluster --name $(KIND_CLUSTER)
+
+# Build the docker image and load it into the kind cluster
+kind-load:
+	$(MAKE) docker-build IMG=$(KIND_IMG)
+	kind load docker-image $(KIND_IMG) --name $(KIND_CLUSTER)
+
+# Deploy controller in the kind cluster
+deploy-kind: kind-create manifests kind-load
+	cd config/manager && kustomize edit set image controller=$(KIND_IMG)
+	kustomize build config/default | kubectl --context kind-$(KIND_CLUSTER) apply -f -
+
+# Run the e2e tests under test/e2e against the controller deployed in the kind cluster, which is created if needed
+test-e2e: deploy-kind
+	go test -tags e2e ./test/e2e/... -v -args -context kind-$(KIND_CLUSTER)
+
 # find or download controller-gen
 # download controller-gen if necessary
 controller-gen:
@@ -126,3 +245,51 @@ else
 CONTROLLER_GEN=$(shell which controller-gen)
 endif
 `
+
+// nolint:lll
+const makefileChartTarget = `
+# Copy the generated CRDs, RBAC and webhook manifests into the Helm chart
+chart: manifests
+	mkdir -p %[1]s/crds %[1]s/files
+	if [ -d config/crd/bases ]; then cp config/crd/bases/*.yaml %[1]s/crds/; fi
+	if [ -f config/rbac/role.yaml ]; then cp config/rbac/role.yaml %[1]s/files/role.yaml; fi
+	if [ -f config/webhook/manifests.yaml ]; then cp config/webhook/manifests.yaml %[1]s/files/webhook_manifests.yaml; fi
+`
+
+const makefileBundleTarget = `
+# Bundle image URL to use when building/pushing the OLM bundle image
+BUNDLE_IMG ?= controller-bundle:latest
+
+# Build the OLM bundle image
+bundle-build:
+	docker build -f bundle.Dockerfile -t ${BUNDLE_IMG} .
+`
+
+const makefileKubectlPluginTarget = `
+# Build the kubectl plugin binary, run as kubectl <name> once it is in the PATH
+kubectl-plugin: generate fmt vet
+	go build -o bin/%[1]s ./cmd/%[1]s
+`
+
+// nolint:lll
+const makefileClientGenTarget = `
+# Generate the typed clientset, listers and informers of the APIs under pkg/client
+generate-clients: code-generator
+	CODE_GENERATOR_BIN=$(CODE_GENERATOR_BIN) bash hack/update-codegen.sh
+
+# find or download the client-gen, lister-gen and informer-gen generators of k8s.io/code-generator
+code-generator:
+ifeq (, $(shell which client-gen))
+	@{ \
+	set -e ;\
+	CODE_GENERATOR_TMP_DIR=$$(mktemp -d) ;\
+	cd $$CODE_GENERATOR_TMP_DIR ;\
+	go mod init tmp ;\
+	go get k8s.io/code-generator/cmd/client-gen@%[1]s k8s.io/code-generator/cmd/lister-gen@%[1]s k8s.io/code-generator/cmd/informer-gen@%[1]s ;\
+	rm -rf $$CODE_GENERATOR_TMP_DIR ;\
+	}
+CODE_GENERATOR_BIN=$(GOBIN)
+else
+CODE_GENERATOR_BIN=$(shell dirname $(shell which client-gen))
+endif
+`
@@ -29,6 +29,13 @@ type Config struct {
 	input.Input
 	// Image is controller manager image name
 	Image string
+
+	// SecureDefaults is true if the manager pods run with a restricted security context
+	SecureDefaults bool
+
+	// ComponentConfig is true if the manager loads its options from the ControllerManagerConfiguration file of
+	// a ConfigMap
+	ComponentConfig bool
 }
 
 // GetInput implements input.File
@@ -64,13 +71,63 @@ spec:
       labels:
         control-plane: controller-manager
     spec:
+      # The manager image is built for the PLATFORMS of the Makefile by
+      # make docker-buildx, keep the architectures in sync with them.
+      nodeSelector:
+        kubernetes.io/os: linux
+      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: kubernetes.io/arch
+                operator: In
+                values:
+                - amd64
+                - arm64
+                - ppc64le
+      # Some providers taint their arm64 nodes so that only the pods with
+      # arm64 images are scheduled on them.
+      tolerations:
+      - key: kubernetes.io/arch
+        operator: Equal
+        value: arm64
+        effect: NoSchedule
+{{- if .SecureDefaults }}
+      # Relax the security context with the manager_relax_security_patch.yaml
+      # patch of the default overlay if the manager needs more privileges.
+      securityContext:
+        runAsNonRoot: true
+        # The nonroot user of the distroless image, numeric so that it can be
+        # verified to be non-root.
+        runAsUser: 65532
+        # Requires Kubernetes 1.19+.
+        seccompProfile:
+          type: RuntimeDefault
+{{- end }}
       containers:
       - command:
         - /manager
         args:
+{{- if .ComponentConfig }}
+        - --config=/etc/manager/controller_manager_config.yaml
+{{- else }}
         - --enable-leader-election
+{{- end }}
         image: {{ .Image }}
         name: manager
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8081
+          initialDelaySeconds: 15
+          periodSeconds: 20
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 8081
+          initialDelaySeconds: 5
+          periodSeconds: 10
         resources:
           limits:
             cpu: 100m
@@ -78,5 +135,25 @@ spec:
           requests:
             cpu: 100m
             memory: 20Mi
+{{- if .SecureDefaults }}
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+{{- end }}
+{{- if .ComponentConfig }}
+        volumeMounts:
+        - name: manager-config
+          mountPath: /etc/manager
+          readOnly: true
+{{- end }}
       terminationGracePeriodSeconds: 10
+{{- if .ComponentConfig }}
+      volumes:
+      - name: manager-config
+        configMap:
+          name: manager-config
+{{- end }}
 `
@@ -27,6 +27,10 @@ var _ input.File = &Kustomization{}
 // Kustomization scaffolds the Kustomization file in manager folder.
 type Kustomization struct {
 	input.Input
+
+	// ComponentConfig is true if the manager loads its options from the ControllerManagerConfiguration file of
+	// a ConfigMap
+	ComponentConfig bool
 }
 
 // GetInput implements input.File
@@ -41,4 +45,15 @@ func (f *Kustomization) GetInput() (input.Input, error) {
 
 const kustomizeManagerTemplate = `resources:
 - manager.yaml
+{{- if .ComponentConfig }}
+
+generatorOptions:
+  disableNameSuffixHash: true
+
+# The ControllerManagerConfiguration file of the manager, mounted in /etc/manager.
+configMapGenerator:
+- name: manager-config
+  files:
+  - controller_manager_config.yaml
+{{- end }}
 `
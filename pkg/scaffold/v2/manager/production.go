@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// productionComponentDir is the directory of the production component of the default overlay
+var productionComponentDir = filepath.Join("config", "components", "production")
+
+var _ input.File = &PriorityClass{}
+
+// PriorityClass scaffolds the PriorityClass of the manager pods in the production component
+type PriorityClass struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *PriorityClass) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(productionComponentDir, "priorityclass.yaml")
+	}
+	f.TemplateBody = priorityClassTemplate
+	return f.Input, nil
+}
+
+const priorityClassTemplate = `# Schedules the manager pods before the workloads they manage, and lets them
+# preempt pods of lower priority when the cluster is full.
+apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: controller-manager
+value: 1000000
+globalDefault: false
+description: "Priority of the controller manager pods."
+`
+
+var _ input.File = &PodDisruptionBudget{}
+
+// PodDisruptionBudget scaffolds the PodDisruptionBudget of the manager pods in the production component
+type PodDisruptionBudget struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *PodDisruptionBudget) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(productionComponentDir, "pdb.yaml")
+	}
+	f.TemplateBody = podDisruptionBudgetTemplate
+	return f.Input, nil
+}
+
+const podDisruptionBudgetTemplate = `# Keeps a manager pod running while the nodes are drained.
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+`
+
+var _ input.File = &ProductionPatch{}
+
+// ProductionPatch scaffolds the patch of the manager Deployment in the production component
+type ProductionPatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *ProductionPatch) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(productionComponentDir, "manager_production_patch.yaml")
+	}
+	f.TemplateBody = productionPatchTemplate
+	return f.Input, nil
+}
+
+const productionPatchTemplate = `# Runs a standby manager, elected leader if the active one is lost, on another node
+# than the active one when possible, and sets the priority and resources of the pods.
+# Adjust the resources to the number of objects watched by the manager.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  replicas: 2
+  template:
+    spec:
+      priorityClassName: controller-manager
+      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: kubernetes.io/hostname
+        whenUnsatisfiable: ScheduleAnyway
+        labelSelector:
+          matchLabels:
+            control-plane: controller-manager
+      containers:
+      - name: manager
+        resources:
+          limits:
+            cpu: 500m
+            memory: 256Mi
+          requests:
+            cpu: 100m
+            memory: 64Mi
+`
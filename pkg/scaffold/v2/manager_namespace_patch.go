@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &ManagerNamespacePatch{}
+
+// ManagerNamespacePatch scaffolds the patch that restricts the manager to watch its own namespace
+type ManagerNamespacePatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *ManagerNamespacePatch) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "default", "manager_namespace_patch.yaml")
+	}
+	f.TemplateBody = managerNamespacePatchTemplate
+	return f.Input, nil
+}
+
+const managerNamespacePatchTemplate = `# This patch makes the manager watch the namespace it is deployed to,
+# where its Roles are granted. Set WATCH_NAMESPACE to a comma-separated list
+# of namespaces to watch several of them, granting the Roles in each of them.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        env:
+        - name: WATCH_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+`
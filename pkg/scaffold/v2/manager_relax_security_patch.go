@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &ManagerRelaxSecurityPatch{}
+
+// ManagerRelaxSecurityPatch scaffolds the patch that relaxes the restricted security context of the manager pods
+type ManagerRelaxSecurityPatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *ManagerRelaxSecurityPatch) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "default", "manager_relax_security_patch.yaml")
+	}
+	f.TemplateBody = managerRelaxSecurityPatchTemplate
+	return f.Input, nil
+}
+
+const managerRelaxSecurityPatchTemplate = `# This patch relaxes the security context of the manager, e.g. for a manager
+# writing to its filesystem or needing a capability. Keep the settings that it
+# doesn't need relaxed, and prefer mounting an emptyDir volume over making the
+# root filesystem writable.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        securityContext:
+          readOnlyRootFilesystem: false
+          # capabilities:
+          #   add:
+          #   - NET_BIND_SERVICE
+`
@@ -0,0 +1,279 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managerconfig
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Dir is the directory of the package of the ControllerManagerConfiguration type, outside of the api one so that
+// it isn't taken for an API version
+const Dir = "managerconfig"
+
+// FileName is the name of the ControllerManagerConfiguration file in the ConfigMap mounted in the manager pods
+const FileName = "controller_manager_config.yaml"
+
+var _ input.File = &Types{}
+
+// Types scaffolds the ControllerManagerConfiguration type, loaded from the file set with the --config flag of the
+// manager to override the options set with the other flags
+type Types struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Types) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "v1alpha1", "controllermanagerconfiguration_types.go")
+	}
+	f.TemplateBody = typesTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// nolint:lll
+const typesTemplate = `{{ .Boilerplate }}
+
+// Package v1alpha1 contains the v1alpha1 version of the configuration of the controller manager
+package v1alpha1
+
+import (
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// GroupVersion is the API version of the ControllerManagerConfiguration files
+var GroupVersion = schema.GroupVersion{Group: "config.{{ .Domain }}", Version: "v1alpha1"}
+
+// ControllerManagerConfiguration configures the controller manager. The options it sets override the ones of
+// the flags of the manager, the others keep the values of the flags.
+type ControllerManagerConfiguration struct {
+	metav1.TypeMeta ` + "`" + `json:",inline"` + "`" + `
+
+	// SyncPeriod is the period after which all the watched objects are reconciled again
+	// +optional
+	SyncPeriod *metav1.Duration ` + "`" + `json:"syncPeriod,omitempty"` + "`" + `
+
+	// LeaderElection configures the leader election between the replicas of the manager
+	// +optional
+	LeaderElection *LeaderElectionConfiguration ` + "`" + `json:"leaderElection,omitempty"` + "`" + `
+
+	// Metrics configures the endpoint serving the metrics
+	// +optional
+	Metrics ControllerMetrics ` + "`" + `json:"metrics,omitempty"` + "`" + `
+
+	// Health configures the endpoints of the liveness and readiness probes
+	// +optional
+	Health ControllerHealth ` + "`" + `json:"health,omitempty"` + "`" + `
+
+	// Webhook configures the webhook server
+	// +optional
+	Webhook ControllerWebhook ` + "`" + `json:"webhook,omitempty"` + "`" + `
+}
+
+// LeaderElectionConfiguration configures the leader election between the replicas of the manager
+type LeaderElectionConfiguration struct {
+	// LeaderElect enables the leader election, so that only one replica runs the controllers at a time
+	// +optional
+	LeaderElect *bool ` + "`" + `json:"leaderElect,omitempty"` + "`" + `
+
+	// ResourceName is the name of the ConfigMap holding the leader lock
+	// +optional
+	ResourceName string ` + "`" + `json:"resourceName,omitempty"` + "`" + `
+
+	// ResourceNamespace is the namespace of the ConfigMap holding the leader lock, defaults to the one of the pod
+	// +optional
+	ResourceNamespace string ` + "`" + `json:"resourceNamespace,omitempty"` + "`" + `
+
+	// LeaseDuration is the duration the other replicas wait after the last renewal to acquire the leadership
+	// +optional
+	LeaseDuration *metav1.Duration ` + "`" + `json:"leaseDuration,omitempty"` + "`" + `
+
+	// RenewDeadline is the duration the leader retries to renew the leadership before giving it up
+	// +optional
+	RenewDeadline *metav1.Duration ` + "`" + `json:"renewDeadline,omitempty"` + "`" + `
+
+	// RetryPeriod is the duration the replicas wait between tries of the leader election actions
+	// +optional
+	RetryPeriod *metav1.Duration ` + "`" + `json:"retryPeriod,omitempty"` + "`" + `
+}
+
+// ControllerMetrics configures the endpoint serving the metrics
+type ControllerMetrics struct {
+	// BindAddress is the address the metrics endpoint binds to, "0" disables it
+	// +optional
+	BindAddress string ` + "`" + `json:"bindAddress,omitempty"` + "`" + `
+}
+
+// ControllerHealth configures the endpoints of the liveness and readiness probes
+type ControllerHealth struct {
+	// HealthProbeBindAddress is the address the probe endpoints bind to
+	// +optional
+	HealthProbeBindAddress string ` + "`" + `json:"healthProbeBindAddress,omitempty"` + "`" + `
+}
+
+// ControllerWebhook configures the webhook server
+type ControllerWebhook struct {
+	// Port is the port the webhook server listens on
+	// +optional
+	Port *int ` + "`" + `json:"port,omitempty"` + "`" + `
+
+	// Host is the address the webhook server binds to
+	// +optional
+	Host string ` + "`" + `json:"host,omitempty"` + "`" + `
+
+	// CertDir is the directory of the tls.crt and tls.key files of the webhook server
+	// +optional
+	CertDir string ` + "`" + `json:"certDir,omitempty"` + "`" + `
+}
+
+// Load reads the ControllerManagerConfiguration of a file
+func Load(path string) (*ControllerManagerConfiguration, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	config := &ControllerManagerConfiguration{}
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(config); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %v", path, err)
+	}
+	if config.APIVersion != GroupVersion.String() || config.Kind != "ControllerManagerConfiguration" {
+		return nil, fmt.Errorf("%s is a %s %s, expected a %s ControllerManagerConfiguration",
+			path, config.APIVersion, config.Kind, GroupVersion)
+	}
+
+	return config, nil
+}
+
+// AndFrom returns the options of the manager overridden with the ones set in the configuration
+func (c *ControllerManagerConfiguration) AndFrom(options ctrl.Options) ctrl.Options {
+	if c.SyncPeriod != nil {
+		options.SyncPeriod = &c.SyncPeriod.Duration
+	}
+
+	if leaderElection := c.LeaderElection; leaderElection != nil {
+		if leaderElection.LeaderElect != nil {
+			options.LeaderElection = *leaderElection.LeaderElect
+		}
+		if leaderElection.ResourceName != "" {
+			options.LeaderElectionID = leaderElection.ResourceName
+		}
+		if leaderElection.ResourceNamespace != "" {
+			options.LeaderElectionNamespace = leaderElection.ResourceNamespace
+		}
+		if leaderElection.LeaseDuration != nil {
+			options.LeaseDuration = &leaderElection.LeaseDuration.Duration
+		}
+		if leaderElection.RenewDeadline != nil {
+			options.RenewDeadline = &leaderElection.RenewDeadline.Duration
+		}
+		if leaderElection.RetryPeriod != nil {
+			options.RetryPeriod = &leaderElection.RetryPeriod.Duration
+		}
+	}
+
+	if c.Metrics.BindAddress != "" {
+		options.MetricsBindAddress = c.Metrics.BindAddress
+	}
+	if c.Health.HealthProbeBindAddress != "" {
+		options.HealthProbeBindAddress = c.Health.HealthProbeBindAddress
+	}
+
+	if c.Webhook.Port != nil {
+		options.Port = *c.Webhook.Port
+	}
+	if c.Webhook.Host != "" {
+		options.Host = c.Webhook.Host
+	}
+	if c.Webhook.CertDir != "" {
+		options.CertDir = c.Webhook.CertDir
+	}
+
+	return options
+}
+`
+
+var _ input.File = &ConfigFile{}
+
+// ConfigFile scaffolds the ControllerManagerConfiguration file of the manager, mounted from the ConfigMap generated
+// by the kustomization of the manager
+type ConfigFile struct {
+	input.Input
+
+	// WebhookPort is the port the webhook server of the manager listens on
+	WebhookPort int
+
+	// WebhookHost is the address the webhook server of the manager binds to, all the addresses of the pod if empty
+	WebhookHost string
+
+	// MetricsAddress is the address the metrics endpoint of the manager binds to behind the auth proxy,
+	// defaults to 127.0.0.1:8080
+	MetricsAddress string
+
+	// LeaderElectionID is the name of the ConfigMap holding the leader lock, defaults to <project>.<domain>
+	LeaderElectionID string
+}
+
+// GetInput implements input.File
+func (f *ConfigFile) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "manager", FileName)
+	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
+	if f.MetricsAddress == "" {
+		f.MetricsAddress = "127.0.0.1:8080"
+	}
+	if f.LeaderElectionID == "" {
+		f.LeaderElectionID = fmt.Sprintf("%s.%s", path.Base(f.Repo), f.Domain)
+	}
+	f.TemplateBody = configFileTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const configFileTemplate = `# The options of the manager, loaded with --config. The options that are not set keep the
+# values of the other flags of the manager.
+apiVersion: config.{{ .Domain }}/v1alpha1
+kind: ControllerManagerConfiguration
+health:
+  healthProbeBindAddress: :8081
+metrics:
+  # Served through the auth proxy, set to the port alone, e.g. :8080, to expose it without authentication.
+  bindAddress: {{ .MetricsAddress }}
+webhook:
+{{- if .WebhookHost }}
+  host: "{{ .WebhookHost }}"
+{{- end }}
+  port: {{ .WebhookPort }}
+leaderElection:
+  leaderElect: true
+  resourceName: {{ .LeaderElectionID }}
+`
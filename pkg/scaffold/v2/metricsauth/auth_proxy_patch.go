@@ -28,6 +28,20 @@ var _ input.File = &AuthProxyPatch{}
 // prometheus metrics for manager Pod.
 type AuthProxyPatch struct {
 	input.Input
+
+	// SecureDefaults is true if the manager pods run with a restricted security context
+	SecureDefaults bool
+
+	// ComponentConfig is true if the manager loads its options, including the address of the metrics endpoint,
+	// from the ControllerManagerConfiguration file of a ConfigMap
+	ComponentConfig bool
+
+	// MetricsAddress is the address the metrics endpoint of the manager binds to behind the auth proxy,
+	// defaults to 127.0.0.1:8080
+	MetricsAddress string
+
+	// IPv6 is true if the auth proxy listens on the IPv6 and IPv4 addresses of the pod instead of the IPv4 ones only
+	IPv6 bool
 }
 
 // GetInput implements input.File
@@ -35,6 +49,9 @@ func (f *AuthProxyPatch) GetInput() (input.Input, error) {
 	if f.Path == "" {
 		f.Path = filepath.Join("config", "default", "manager_auth_proxy_patch.yaml")
 	}
+	if f.MetricsAddress == "" {
+		f.MetricsAddress = "127.0.0.1:8080"
+	}
 	f.TemplateBody = kustomizeAuthProxyPatchTemplate
 	f.Input.IfExistsAction = input.Error
 	return f.Input, nil
@@ -54,15 +71,27 @@ spec:
       - name: kube-rbac-proxy
         image: gcr.io/kubebuilder/kube-rbac-proxy:v0.4.1
         args:
-        - "--secure-listen-address=0.0.0.0:8443"
-        - "--upstream=http://127.0.0.1:8080/"
+        - "--secure-listen-address={{ if .IPv6 }}[::]{{ else }}0.0.0.0{{ end }}:8443"
+        - "--upstream=http://{{ .MetricsAddress }}/"
         - "--logtostderr=true"
         - "--v=10"
         ports:
         - containerPort: 8443
           name: https
+{{- if .SecureDefaults }}
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+{{- end }}
       - name: manager
         args:
-        - "--metrics-addr=127.0.0.1:8080"
+{{- if .ComponentConfig }}
+        - "--config=/etc/manager/controller_manager_config.yaml"
+{{- else }}
+        - "--metrics-bind-address={{ .MetricsAddress }}"
         - "--enable-leader-election"
+{{- end }}
 `
@@ -27,6 +27,9 @@ var _ input.File = &AuthProxyService{}
 // AuthProxyService scaffolds the config/rbac/auth_proxy_service.yaml file
 type AuthProxyService struct {
 	input.Input
+
+	// DualStack is true if the Service is assigned IPv4 and IPv6 addresses where the cluster supports it
+	DualStack bool
 }
 
 // GetInput implements input.File
@@ -46,6 +49,9 @@ metadata:
   name: controller-manager-metrics-service
   namespace: system
 spec:
+{{- if .DualStack }}
+  ipFamilyPolicy: PreferDualStack
+{{- end }}
   ports:
   - name: https
     port: 8443
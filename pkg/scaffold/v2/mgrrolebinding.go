@@ -19,6 +19,9 @@ package v2
 import (
 	"path/filepath"
 
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
@@ -27,6 +30,9 @@ var _ input.File = &ManagerRoleBinding{}
 // ManagerRoleBinding scaffolds the config/rbac/role_binding.yaml file
 type ManagerRoleBinding struct {
 	input.Input
+
+	// NamespaceScoped is true if the manager is granted a Role instead of a ClusterRole
+	NamespaceScoped bool
 }
 
 // GetInput implements input.File
@@ -39,9 +45,48 @@ func (f *ManagerRoleBinding) GetInput() (input.Input, error) {
 }
 
 const managerBindingTemplate = `apiVersion: rbac.authorization.k8s.io/v1
-kind: ClusterRoleBinding
+kind: {{ if .NamespaceScoped }}RoleBinding{{ else }}ClusterRoleBinding{{ end }}
 metadata:
   name: manager-rolebinding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: {{ if .NamespaceScoped }}Role{{ else }}ClusterRole{{ end }}
+  name: manager-role
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: system
+`
+
+var _ input.File = &ManagerClusterRoleBinding{}
+
+// ManagerClusterRoleBinding scaffolds the config/rbac/cluster_role_binding.yaml file, which grants the manager of a
+// namespace-scoped project the ClusterRole with the permissions on the cluster-scoped resources it reconciles
+type ManagerClusterRoleBinding struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *ManagerClusterRoleBinding) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "rbac", "cluster_role_binding.yaml")
+	}
+	f.TemplateBody = managerClusterBindingTemplate
+	f.IfExistsAction = input.Skip
+	return f.Input, nil
+}
+
+// AddToKustomization lists the binding in the resources of config/rbac/kustomization.yaml, next to the binding of
+// the Role of the manager
+func (f *ManagerClusterRoleBinding) AddToKustomization(fs afero.Fs) error {
+	return inject.Update(fs, filepath.Join("config", "rbac", "kustomization.yaml"),
+		map[string][]string{"- leader_election_role.yaml": {"- cluster_role_binding.yaml\n"}})
+}
+
+const managerClusterBindingTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: manager-clusterrolebinding
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
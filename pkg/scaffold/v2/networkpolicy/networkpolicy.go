@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// componentDir is the directory of the networkpolicy component of the default overlay
+var componentDir = filepath.Join("config", "components", "networkpolicy")
+
+var _ input.File = &WebhookIngress{}
+
+// WebhookIngress scaffolds the NetworkPolicy allowing the API server to call the webhook server of the manager
+type WebhookIngress struct {
+	input.Input
+
+	// WebhookPort is the port the webhook server of the manager listens on
+	WebhookPort int
+}
+
+// GetInput implements input.File
+func (f *WebhookIngress) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(componentDir, "webhook_ingress.yaml")
+	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
+	f.TemplateBody = webhookIngressTemplate
+	return f.Input, nil
+}
+
+const webhookIngressTemplate = `# Only allows the API server to call the webhook server of the manager pods.
+# The API server usually runs outside of the pod network and can't be selected by
+# labels, restrict the sources to the addresses of the control plane with an ipBlock
+# if they are known.
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: webhook-ingress
+  namespace: system
+spec:
+  podSelector:
+    matchLabels:
+      control-plane: controller-manager
+  policyTypes:
+  - Ingress
+  ingress:
+  - ports:
+    - protocol: TCP
+      port: {{ .WebhookPort }}
+  # Uncomment to let Prometheus, running in the namespaces labeled metrics=enabled,
+  # scrape the metrics of the manager through the auth proxy.
+  #- from:
+  #  - namespaceSelector:
+  #      matchLabels:
+  #        metrics: enabled
+  #  ports:
+  #  - protocol: TCP
+  #    port: 8443
+`
+
+var _ input.File = &APIServerEgress{}
+
+// APIServerEgress scaffolds the NetworkPolicy allowing the manager to call the API server
+type APIServerEgress struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *APIServerEgress) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(componentDir, "apiserver_egress.yaml")
+	}
+	f.TemplateBody = apiServerEgressTemplate
+	return f.Input, nil
+}
+
+const apiServerEgressTemplate = `# Only allows the manager pods to call the API server, through the kubernetes
+# Service (443) or directly (6443) as most network plugins apply the policies after
+# translating the Service address. Add the other destinations of the manager, e.g.
+# the DNS servers or external APIs, as new egress rules.
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: apiserver-egress
+  namespace: system
+spec:
+  podSelector:
+    matchLabels:
+      control-plane: controller-manager
+  policyTypes:
+  - Egress
+  egress:
+  - ports:
+    - protocol: TCP
+      port: 443
+    - protocol: TCP
+      port: 6443
+`
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// componentDir is the directory of the observability component of the default overlay
+var componentDir = filepath.Join("config", "components", "observability")
+
+// leftDelim and rightDelim are the delimiters of the templates, which contain the ones of Prometheus and Grafana
+const (
+	leftDelim  = "[["
+	rightDelim = "]]"
+)
+
+var _ input.File = &Alerts{}
+var _ input.HasDelimiters = &Alerts{}
+
+// Alerts scaffolds the PrometheusRule alerting on the reconcile errors, latency and workqueue depth of the manager
+type Alerts struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Alerts) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(componentDir, "alerts.yaml")
+	}
+	f.TemplateBody = alertsTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *Alerts) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// nolint:lll
+const alertsTemplate = `# Alerts on the metrics of the controllers of the manager, scraped by the prometheus component.
+# Label the rule with the ruleSelector of your Prometheus so that it is loaded, and tune the
+# thresholds to the load of your controllers.
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: controller-manager-alerts
+  namespace: system
+spec:
+  groups:
+  - name: controller-manager
+    rules:
+    - alert: ControllerReconcileErrors
+      expr: |
+        sum by (namespace, controller) (rate(controller_runtime_reconcile_errors_total[5m]))
+          / sum by (namespace, controller) (rate(controller_runtime_reconcile_total[5m])) > 0.1
+      for: 15m
+      labels:
+        severity: warning
+      annotations:
+        summary: More than 10% of the reconciles of a controller fail.
+        description: '{{ $value | humanizePercentage }} of the reconciles of the {{ $labels.controller }} controller in {{ $labels.namespace }} failed in the last 5 minutes.'
+    - alert: ControllerReconcileSlow
+      expr: |
+        histogram_quantile(0.99,
+          sum by (namespace, controller, le) (rate(controller_runtime_reconcile_time_seconds_bucket[5m]))) > 10
+      for: 15m
+      labels:
+        severity: warning
+      annotations:
+        summary: The reconciles of a controller take more than 10s.
+        description: 'The 99th percentile of the reconcile time of the {{ $labels.controller }} controller in {{ $labels.namespace }} is {{ $value | humanizeDuration }}.'
+    - alert: ControllerWorkqueueBacklog
+      expr: |
+        sum by (namespace, name) (workqueue_depth) > 100
+      for: 15m
+      labels:
+        severity: warning
+      annotations:
+        summary: The workqueue of a controller is backing up.
+        description: 'The workqueue of the {{ $labels.name }} controller in {{ $labels.namespace }} holds {{ $value }} objects waiting to be reconciled.'
+`
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// grafanaDir is the directory of the Grafana dashboards, deployed by the observability component
+var grafanaDir = filepath.Join("config", "grafana")
+
+var _ input.File = &GrafanaKustomization{}
+
+// GrafanaKustomization scaffolds the kustomization generating the ConfigMap of the Grafana dashboards
+type GrafanaKustomization struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *GrafanaKustomization) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(grafanaDir, "kustomization.yaml")
+	}
+	f.TemplateBody = grafanaKustomizationTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const grafanaKustomizationTemplate = `# Provisions the dashboards in Grafana with a ConfigMap labeled for the dashboards sidecar of
+# the Grafana helm chart, which has to search the namespace of the manager, e.g. with
+# sidecar.dashboards.searchNamespace=ALL. Add the dashboards of your own metrics to the files.
+configMapGenerator:
+- name: grafana-dashboards
+  files:
+  - controller-runtime-metrics.json
+  options:
+    disableNameSuffixHash: true
+    labels:
+      grafana_dashboard: "1"
+`
+
+var _ input.File = &Dashboard{}
+var _ input.HasDelimiters = &Dashboard{}
+
+// Dashboard scaffolds the Grafana dashboard of the reconcile latency, error rate and workqueue depth of the
+// controllers of the manager
+type Dashboard struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Dashboard) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(grafanaDir, "controller-runtime-metrics.json")
+	}
+	f.TemplateBody = dashboardTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Delimiters implements input.HasDelimiters
+func (f *Dashboard) Delimiters() (string, string) {
+	return leftDelim, rightDelim
+}
+
+// nolint:lll
+const dashboardTemplate = `{
+  "title": "Controller Runtime",
+  "uid": "controller-runtime",
+  "tags": ["kubebuilder"],
+  "editable": true,
+  "schemaVersion": 22,
+  "time": {
+    "from": "now-1h",
+    "to": "now"
+  },
+  "refresh": "30s",
+  "templating": {
+    "list": [
+      {
+        "name": "datasource",
+        "label": "Data source",
+        "type": "datasource",
+        "query": "prometheus"
+      },
+      {
+        "name": "namespace",
+        "label": "Namespace",
+        "type": "query",
+        "datasource": "$datasource",
+        "query": "label_values(controller_runtime_reconcile_total, namespace)",
+        "refresh": 2
+      },
+      {
+        "name": "controller",
+        "label": "Controller",
+        "type": "query",
+        "datasource": "$datasource",
+        "query": "label_values(controller_runtime_reconcile_total{namespace=\"$namespace\"}, controller)",
+        "refresh": 2,
+        "multi": true,
+        "includeAll": true
+      }
+    ]
+  },
+  "panels": [
+    {
+      "title": "Reconciles per second",
+      "type": "graph",
+      "datasource": "$datasource",
+      "gridPos": {"h": 8, "w": 12, "x": 0, "y": 0},
+      "targets": [
+        {
+          "expr": "sum by (controller, result) (rate(controller_runtime_reconcile_total{namespace=\"$namespace\", controller=~\"$controller\"}[5m]))",
+          "legendFormat": "{{controller}} {{result}}"
+        }
+      ],
+      "yaxes": [
+        {"format": "ops", "min": 0},
+        {"format": "short", "show": false}
+      ]
+    },
+    {
+      "title": "Reconcile errors",
+      "type": "graph",
+      "datasource": "$datasource",
+      "gridPos": {"h": 8, "w": 12, "x": 12, "y": 0},
+      "targets": [
+        {
+          "expr": "sum by (controller) (rate(controller_runtime_reconcile_errors_total{namespace=\"$namespace\", controller=~\"$controller\"}[5m])) / sum by (controller) (rate(controller_runtime_reconcile_total{namespace=\"$namespace\", controller=~\"$controller\"}[5m]))",
+          "legendFormat": "{{controller}}"
+        }
+      ],
+      "yaxes": [
+        {"format": "percentunit", "min": 0},
+        {"format": "short", "show": false}
+      ]
+    },
+    {
+      "title": "Reconcile latency",
+      "type": "graph",
+      "datasource": "$datasource",
+      "gridPos": {"h": 8, "w": 12, "x": 0, "y": 8},
+      "targets": [
+        {
+          "expr": "histogram_quantile(0.99, sum by (controller, le) (rate(controller_runtime_reconcile_time_seconds_bucket{namespace=\"$namespace\", controller=~\"$controller\"}[5m])))",
+          "legendFormat": "{{controller}} p99"
+        },
+        {
+          "expr": "histogram_quantile(0.5, sum by (controller, le) (rate(controller_runtime_reconcile_time_seconds_bucket{namespace=\"$namespace\", controller=~\"$controller\"}[5m])))",
+          "legendFormat": "{{controller}} p50"
+        }
+      ],
+      "yaxes": [
+        {"format": "s", "min": 0},
+        {"format": "short", "show": false}
+      ]
+    },
+    {
+      "title": "Workqueue depth",
+      "type": "graph",
+      "datasource": "$datasource",
+      "gridPos": {"h": 8, "w": 12, "x": 12, "y": 8},
+      "targets": [
+        {
+          "expr": "sum by (name) (workqueue_depth{namespace=\"$namespace\", name=~\"$controller\"})",
+          "legendFormat": "{{name}}"
+        }
+      ],
+      "yaxes": [
+        {"format": "short", "min": 0},
+        {"format": "short", "show": false}
+      ]
+    }
+  ]
+}
+`
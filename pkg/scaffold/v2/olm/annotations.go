@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm
+
+import (
+	"errors"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Annotations{}
+
+// Annotations scaffolds the metadata of the bundle
+type Annotations struct {
+	input.Input
+
+	// PackageName is the name of the OLM package
+	PackageName string
+
+	// Channels are the channels the bundle is published in
+	Channels []string
+
+	// DefaultChannel is the channel to subscribe to by default, defaults to the first channel
+	DefaultChannel string
+}
+
+// GetInput implements input.File
+func (f *Annotations) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(BundleDir, "metadata", "annotations.yaml")
+	}
+	if f.DefaultChannel == "" && len(f.Channels) != 0 {
+		f.DefaultChannel = f.Channels[0]
+	}
+	f.TemplateBody = annotationsTemplate
+	f.Input.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Annotations) Validate() error {
+	return validateBundleMetadata(f.PackageName, f.Channels)
+}
+
+func validateBundleMetadata(packageName string, channels []string) error {
+	if packageName == "" {
+		return errors.New("package name cannot be empty")
+	}
+	if len(channels) == 0 {
+		return errors.New("at least one channel is required")
+	}
+	return nil
+}
+
+// nolint:lll
+const annotationsTemplate = `annotations:
+  operators.operatorframework.io.bundle.mediatype.v1: registry+v1
+  operators.operatorframework.io.bundle.manifests.v1: manifests/
+  operators.operatorframework.io.bundle.metadata.v1: metadata/
+  operators.operatorframework.io.bundle.package.v1: {{ .PackageName }}
+  operators.operatorframework.io.bundle.channels.v1: {{ range $i, $c := .Channels }}{{ if $i }},{{ end }}{{ $c }}{{ end }}
+  operators.operatorframework.io.bundle.channel.default.v1: {{ .DefaultChannel }}
+`
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/olm"
+)
+
+var _ = Describe("Annotations", func() {
+	It("should publish the bundle in the channels and default to the first one", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{},
+			&olm.Annotations{PackageName: "fleet", Channels: []string{"alpha", "stable"}})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("bundle", "metadata", "annotations.yaml"))
+		Expect(content).To(ContainSubstring("operators.operatorframework.io.bundle.package.v1: fleet\n"))
+		Expect(content).To(ContainSubstring("operators.operatorframework.io.bundle.channels.v1: alpha,stable\n"))
+		Expect(content).To(ContainSubstring("operators.operatorframework.io.bundle.channel.default.v1: alpha\n"))
+	})
+
+	It("should require a channel", func() {
+		s, _ := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &olm.Annotations{PackageName: "fleet"})).NotTo(Succeed())
+	})
+})
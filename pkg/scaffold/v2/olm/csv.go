@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// BundleDir is the directory where the bundle manifests and metadata are written
+const BundleDir = "bundle"
+
+var _ input.File = &ClusterServiceVersion{}
+
+// ClusterServiceVersion scaffolds the ClusterServiceVersion that describes the operator to OLM
+type ClusterServiceVersion struct {
+	input.Input
+
+	// PackageName is the name of the OLM package
+	PackageName string
+
+	// Version is the version of the operator, without the leading "v"
+	Version string
+
+	// Image is the controller manager image
+	Image string
+
+	// Resources are the APIs owned by the operator
+	Resources []*resource.Resource
+
+	// ALMExamples are the JSON encoded samples of the owned APIs
+	ALMExamples string
+
+	// ClusterRules are the YAML encoded RBAC rules of the controller manager
+	ClusterRules string
+}
+
+// GetInput implements input.File
+func (f *ClusterServiceVersion) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(BundleDir, "manifests", fmt.Sprintf("%s.clusterserviceversion.yaml", f.PackageName))
+	}
+	if f.Image == "" {
+		f.Image = "controller:latest"
+	}
+	if f.ALMExamples == "" {
+		f.ALMExamples = "[]"
+	}
+	// Both are embedded in the YAML document as nested blocks
+	f.ALMExamples = indent(f.ALMExamples, 6)
+	f.ClusterRules = indent(f.ClusterRules, 8)
+	f.TemplateBody = csvTemplate
+	f.Input.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *ClusterServiceVersion) Validate() error {
+	if f.PackageName == "" {
+		return errors.New("package name cannot be empty")
+	}
+	if f.Version == "" {
+		return errors.New("version cannot be empty")
+	}
+	return nil
+}
+
+// indent prefixes every non-empty line with n spaces
+func indent(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = strings.Repeat(" ", n) + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+const csvTemplate = `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: {{ .PackageName }}.v{{ .Version }}
+  namespace: placeholder
+  annotations:
+    capabilities: Basic Install
+    alm-examples: |-
+{{ .ALMExamples }}
+spec:
+  displayName: {{ .PackageName }}
+  description: TODO(user) describe the operator
+  version: {{ .Version }}
+  maturity: alpha
+  provider:
+    name: TODO(user)
+  keywords:
+  - {{ .PackageName }}
+  installModes:
+  - supported: true
+    type: OwnNamespace
+  - supported: true
+    type: SingleNamespace
+  - supported: false
+    type: MultiNamespace
+  - supported: true
+    type: AllNamespaces
+  customresourcedefinitions:
+{{- if .Resources }}
+    owned:
+{{- range .Resources }}
+    - name: {{ .Resource }}.{{ .QualifiedGroup $.Domain }}
+      version: {{ .Version }}
+      kind: {{ .Kind }}
+      displayName: {{ .Kind }}
+      description: {{ .Kind }} is the Schema for the {{ .Resource }} API
+{{- end }}
+{{- else }}
+    owned: []
+{{- end }}
+  install:
+    strategy: deployment
+    spec:
+      clusterPermissions:
+      - serviceAccountName: default
+{{- if .ClusterRules }}
+        rules:
+{{ .ClusterRules }}
+{{- else }}
+        rules: []
+{{- end }}
+      permissions:
+      # permissions to do leader election.
+      - serviceAccountName: default
+        rules:
+        - apiGroups:
+          - ""
+          resources:
+          - configmaps
+          verbs:
+          - get
+          - list
+          - watch
+          - create
+          - update
+          - patch
+          - delete
+        - apiGroups:
+          - ""
+          resources:
+          - configmaps/status
+          verbs:
+          - get
+          - update
+          - patch
+        - apiGroups:
+          - ""
+          resources:
+          - events
+          verbs:
+          - create
+      deployments:
+      - name: {{ .PackageName }}-controller-manager
+        spec:
+          replicas: 1
+          selector:
+            matchLabels:
+              control-plane: controller-manager
+          template:
+            metadata:
+              labels:
+                control-plane: controller-manager
+            spec:
+              containers:
+              - name: manager
+                image: {{ .Image }}
+                command:
+                - /manager
+                args:
+                - --enable-leader-election
+                env:
+                - name: ENABLE_WEBHOOKS
+                  value: "false"
+                resources:
+                  limits:
+                    cpu: 100m
+                    memory: 30Mi
+                  requests:
+                    cpu: 100m
+                    memory: 20Mi
+              terminationGracePeriodSeconds: 10
+`
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/olm"
+)
+
+var _ = Describe("ClusterServiceVersion", func() {
+	// scaffoldCSV returns the ClusterServiceVersion scaffolded by f
+	scaffoldCSV := func(f *olm.ClusterServiceVersion) string {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{}, f)).To(Succeed())
+		return scaffoldtest.ReadFile(fs, filepath.Join("bundle", "manifests", "fleet.clusterserviceversion.yaml"))
+	}
+
+	It("should own the CRDs of the resources and embed the examples and the rules", func() {
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		Expect(frigate.Validate()).To(Succeed())
+		content := scaffoldCSV(&olm.ClusterServiceVersion{
+			PackageName:  "fleet",
+			Version:      "0.2.0",
+			Image:        "fleet:v0.2.0",
+			Resources:    []*resource.Resource{frigate},
+			ALMExamples:  "[\n  {\n    \"kind\": \"Frigate\"\n  }\n]",
+			ClusterRules: "- apiGroups:\n  - ship.example.com\n  resources:\n  - frigates\n",
+		})
+
+		Expect(content).To(ContainSubstring("  name: fleet.v0.2.0\n"))
+		Expect(content).To(ContainSubstring("    alm-examples: |-\n      [\n        {\n          \"kind\": \"Frigate\"\n"))
+		Expect(content).To(ContainSubstring("    owned:\n    - name: frigates.ship.example.com\n" +
+			"      version: v1\n      kind: Frigate\n"))
+		Expect(content).To(ContainSubstring("        rules:\n        - apiGroups:\n          - ship.example.com\n" +
+			"          resources:\n          - frigates\n"))
+		Expect(content).To(ContainSubstring("                image: fleet:v0.2.0\n"))
+	})
+
+	It("should default the image and leave the lists empty without resources or rules", func() {
+		content := scaffoldCSV(&olm.ClusterServiceVersion{PackageName: "fleet", Version: "0.1.0"})
+
+		Expect(content).To(ContainSubstring("    alm-examples: |-\n      []\n"))
+		Expect(content).To(ContainSubstring("    owned: []\n"))
+		Expect(content).To(ContainSubstring("        rules: []\n"))
+		Expect(content).To(ContainSubstring("                image: controller:latest\n"))
+	})
+
+	It("should require the version of the operator", func() {
+		s, _ := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{},
+			&olm.ClusterServiceVersion{PackageName: "fleet"})).NotTo(Succeed())
+	})
+})
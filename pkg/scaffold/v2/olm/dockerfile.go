@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Dockerfile{}
+
+// Dockerfile scaffolds the Dockerfile to build the bundle image
+type Dockerfile struct {
+	input.Input
+
+	// PackageName is the name of the OLM package
+	PackageName string
+
+	// Channels are the channels the bundle is published in
+	Channels []string
+
+	// DefaultChannel is the channel to subscribe to by default, defaults to the first channel
+	DefaultChannel string
+}
+
+// GetInput implements input.File
+func (f *Dockerfile) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = "bundle.Dockerfile"
+	}
+	if f.DefaultChannel == "" && len(f.Channels) != 0 {
+		f.DefaultChannel = f.Channels[0]
+	}
+	f.TemplateBody = dockerfileTemplate
+	f.Input.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Dockerfile) Validate() error {
+	return validateBundleMetadata(f.PackageName, f.Channels)
+}
+
+// nolint:lll
+const dockerfileTemplate = `FROM scratch
+
+LABEL operators.operatorframework.io.bundle.mediatype.v1=registry+v1
+LABEL operators.operatorframework.io.bundle.manifests.v1=manifests/
+LABEL operators.operatorframework.io.bundle.metadata.v1=metadata/
+LABEL operators.operatorframework.io.bundle.package.v1={{ .PackageName }}
+LABEL operators.operatorframework.io.bundle.channels.v1={{ range $i, $c := .Channels }}{{ if $i }},{{ end }}{{ $c }}{{ end }}
+LABEL operators.operatorframework.io.bundle.channel.default.v1={{ .DefaultChannel }}
+
+COPY bundle/manifests /manifests/
+COPY bundle/metadata /metadata/
+`
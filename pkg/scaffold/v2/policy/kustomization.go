@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+const kustomizeResourceScaffoldMarker = "# +kubebuilder:scaffold:policyresource"
+
+var _ input.File = &Kustomization{}
+
+// Kustomization scaffolds the kustomization file in policy folder
+type Kustomization struct {
+	input.Input
+
+	// Resource is the resource whose policy is added by Update
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (f *Kustomization) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "policy", "kustomization.yaml")
+	}
+	f.TemplateBody = kustomizationTemplate
+	return f.Input, nil
+}
+
+// Update adds the policy of the resource to the kustomization file
+func (f *Kustomization) Update(fs afero.Fs) error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "policy", "kustomization.yaml")
+	}
+
+	return inject.Update(fs, f.Path, map[string][]string{
+		kustomizeResourceScaffoldMarker: {fmt.Sprintf("- %s_%s_%s.yaml\n",
+			f.Resource.Group, f.Resource.Version, strings.ToLower(f.Resource.Kind))},
+	})
+}
+
+var kustomizationTemplate = fmt.Sprintf(`# This kustomization.yaml is not intended to be run by itself,
+# it is enabled in config/default through the policy component.
+resources:
+%s
+
+# the following config is for teaching kustomize how to update the name of the policies in their bindings.
+configurations:
+- kustomizeconfig.yaml
+`, kustomizeResourceScaffoldMarker)
+
+var _ input.File = &KustomizeConfig{}
+
+// KustomizeConfig scaffolds the kustomizeconfig file in policy folder
+type KustomizeConfig struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *KustomizeConfig) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "policy", "kustomizeconfig.yaml")
+	}
+	f.TemplateBody = kustomizeConfigTemplate
+	return f.Input, nil
+}
+
+const kustomizeConfigTemplate = `# This file is for teaching kustomize how to substitute the name of the policies in their bindings
+nameReference:
+- kind: ValidatingAdmissionPolicy
+  group: admissionregistration.k8s.io
+  fieldSpecs:
+  - kind: ValidatingAdmissionPolicyBinding
+    group: admissionregistration.k8s.io
+    path: spec/policyName
+`
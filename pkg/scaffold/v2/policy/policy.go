@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &Policy{}
+
+// Policy scaffolds the ValidatingAdmissionPolicy of a resource and its binding, validating the objects
+// in the API server with CEL instead of a validating webhook
+type Policy struct {
+	input.Input
+
+	// Resource is the resource validated by the policy
+	Resource *resource.Resource
+
+	// Rules are the CEL expressions validating the objects, derived from the validation markers of the Kind
+	Rules []Rule
+}
+
+// GetInput implements input.File
+func (f *Policy) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "policy", fmt.Sprintf("%s_%s_%s.yaml",
+			f.Resource.Group, f.Resource.Version, strings.ToLower(f.Resource.Kind)))
+	}
+	f.TemplateBody = policyTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// nolint:lll
+const policyTemplate = `# Validates the {{ .Resource.Kind }} objects in the API server, without a webhook.
+# The validations are derived from the validation markers of the {{ .Resource.Kind }} type,
+# add the rules that can't be expressed in the CRD schema, e.g. ones depending on
+# the old object (oldObject) or on the parameters of the binding (params).
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: {{ .Resource.Resource }}-{{ .Resource.Version }}
+spec:
+  failurePolicy: Fail
+  matchConstraints:
+    resourceRules:
+    - apiGroups: ["{{ .Resource.QualifiedGroup .Domain }}"]
+      apiVersions: ["{{ .Resource.Version }}"]
+      operations: ["CREATE", "UPDATE"]
+      resources: ["{{ .Resource.Resource }}"]
+  validations:
+{{- range .Rules }}
+  - expression: {{ printf "%q" .Expression }}
+    message: {{ printf "%q" .Message }}
+{{- else }}
+  # TODO(user): validate the objects with CEL, e.g. object.spec.replicas <= 5
+  - expression: "true"
+{{- end }}
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  name: {{ .Resource.Resource }}-{{ .Resource.Version }}
+spec:
+  policyName: {{ .Resource.Resource }}-{{ .Resource.Version }}
+  validationActions: [Deny]
+`
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+)
+
+// Rule is a CEL expression that the objects have to satisfy to be admitted
+type Rule struct {
+	Expression string
+	Message    string
+}
+
+// selfPattern matches the references to the validated value in the CEL rules of the CRD schema
+var selfPattern = regexp.MustCompile(`\bself\b`)
+
+// NewRules returns the CEL expressions enforcing the validation markers of the spec of the Kind, which the
+// ValidatingAdmissionPolicy starts from, e.g. object.spec.size in ["Small", "Large"] for an Enum marker.
+// The fields of the structs of the package are validated recursively, but not the items of lists and maps.
+func NewRules(doc *docs.KindDoc) []Rule {
+	types := make(map[string]*docs.TypeDoc, len(doc.Types))
+	for _, t := range doc.Types {
+		types[t.Anchor()] = t
+	}
+
+	b := &rulesBuilder{types: types}
+	for _, field := range doc.Types[0].Fields {
+		if field.Name == "spec" {
+			b.addField(field, "object", nil)
+		}
+	}
+	return b.rules
+}
+
+type rulesBuilder struct {
+	types map[string]*docs.TypeDoc
+	rules []Rule
+}
+
+// addField adds the rules of a field of the value at path, which are only checked if every guard is true
+func (b *rulesBuilder) addField(field *docs.FieldDoc, path string, guards []string) {
+	fieldPath := path
+	if field.Name != "(inline)" {
+		fieldPath = path + "." + field.Name
+	}
+	name := strings.TrimPrefix(fieldPath, "object.")
+
+	if field.Required {
+		b.add(guards, fmt.Sprintf("has(%s)", fieldPath), fmt.Sprintf("%s is required", name))
+	} else {
+		guards = append(guards, fmt.Sprintf("has(%s)", fieldPath))
+	}
+
+	exclusiveMinimum, exclusiveMaximum := false, false
+	for _, validation := range field.Validations {
+		switch validation {
+		case "ExclusiveMinimum=true":
+			exclusiveMinimum = true
+		case "ExclusiveMaximum=true":
+			exclusiveMaximum = true
+		}
+	}
+
+	for _, validation := range field.Validations {
+		marker, value := validation, ""
+		if i := strings.Index(validation, "="); i != -1 {
+			marker, value = validation[:i], validation[i+1:]
+		}
+
+		switch marker {
+		case "Enum":
+			values := strings.Split(value, ";")
+			literals := make([]string, 0, len(values))
+			for _, v := range values {
+				if field.Type == "string" {
+					v = strconv.Quote(unquote(v))
+				}
+				literals = append(literals, v)
+			}
+			b.add(guards, fmt.Sprintf("%s in [%s]", fieldPath, strings.Join(literals, ", ")),
+				fmt.Sprintf("%s must be one of %s", name, strings.Join(literals, ", ")))
+		case "Pattern":
+			pattern := unquote(value)
+			b.add(guards, fmt.Sprintf("%s.matches(%s)", fieldPath, strconv.Quote(pattern)),
+				fmt.Sprintf("%s must match %s", name, pattern))
+		case "MinLength":
+			b.add(guards, fmt.Sprintf("size(%s) >= %s", fieldPath, value),
+				fmt.Sprintf("%s must be at least %s characters long", name, value))
+		case "MaxLength":
+			b.add(guards, fmt.Sprintf("size(%s) <= %s", fieldPath, value),
+				fmt.Sprintf("%s must be at most %s characters long", name, value))
+		case "MinItems":
+			b.add(guards, fmt.Sprintf("size(%s) >= %s", fieldPath, value),
+				fmt.Sprintf("%s must have at least %s items", name, value))
+		case "MaxItems":
+			b.add(guards, fmt.Sprintf("size(%s) <= %s", fieldPath, value),
+				fmt.Sprintf("%s must have at most %s items", name, value))
+		case "Minimum":
+			operator := ">="
+			if exclusiveMinimum {
+				operator = ">"
+			}
+			b.add(guards, fmt.Sprintf("%s %s %s", fieldPath, operator, value),
+				fmt.Sprintf("%s must be %s %s", name, operator, value))
+		case "Maximum":
+			operator := "<="
+			if exclusiveMaximum {
+				operator = "<"
+			}
+			b.add(guards, fmt.Sprintf("%s %s %s", fieldPath, operator, value),
+				fmt.Sprintf("%s must be %s %s", name, operator, value))
+		case "XValidation":
+			b.addXValidation(value, fieldPath, guards)
+		}
+	}
+
+	// The rules of the struct fields are checked on the struct itself, not on the items of lists and maps
+	if t, found := b.types[field.TypeLink]; found && field.Type == t.Name {
+		for _, validation := range t.Validations {
+			if strings.HasPrefix(validation, "XValidation:") {
+				b.addXValidation(strings.TrimPrefix(validation, "XValidation:"), fieldPath, guards)
+			}
+		}
+		for _, nested := range t.Fields {
+			b.addField(nested, fieldPath, guards)
+		}
+	}
+}
+
+// addXValidation adds a CEL rule of the CRD schema, with the validated value at path
+func (b *rulesBuilder) addXValidation(args, path string, guards []string) {
+	values := parseArgs(args)
+	rule := values["rule"]
+	if rule == "" {
+		return
+	}
+	message := values["message"]
+	if message == "" {
+		message = fmt.Sprintf("%s failed rule: %s", strings.TrimPrefix(path, "object."), rule)
+	}
+	b.add(guards, selfPattern.ReplaceAllString(rule, path), message)
+}
+
+// add adds a rule whose expression is only checked if every guard is true
+func (b *rulesBuilder) add(guards []string, expression, message string) {
+	if len(guards) != 0 {
+		negated := make([]string, 0, len(guards)+1)
+		for _, guard := range guards {
+			negated = append(negated, "!"+guard)
+		}
+		expression = strings.Join(append(negated, fmt.Sprintf("(%s)", expression)), " || ")
+	}
+	b.rules = append(b.rules, Rule{Expression: expression, Message: message})
+}
+
+// parseArgs parses the arguments of a marker, e.g. rule="self > 0",message="must be positive"
+func parseArgs(args string) map[string]string {
+	values := make(map[string]string)
+	for args != "" {
+		i := strings.Index(args, "=")
+		if i == -1 {
+			break
+		}
+		key, rest := args[:i], args[i+1:]
+
+		end := strings.Index(rest, ",")
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			// Find the closing quote, skipping the escaped ones
+			end = 1
+			for end < len(rest) && rest[end] != '"' {
+				if rest[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end++
+		case strings.HasPrefix(rest, "`"):
+			end = strings.Index(rest[1:], "`") + 2
+		}
+		if end == -1 || end > len(rest) {
+			end = len(rest)
+		}
+
+		values[key] = unquote(rest[:end])
+		args = strings.TrimPrefix(rest[end:], ",")
+	}
+	return values
+}
+
+// unquote returns the value of a marker argument, which may be a quoted or raw string
+func unquote(value string) string {
+	if strings.HasPrefix(value, "`") && strings.HasSuffix(value, "`") && len(value) > 1 {
+		return value[1 : len(value)-1]
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
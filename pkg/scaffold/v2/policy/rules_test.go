@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+)
+
+func TestNewRules(t *testing.T) {
+	doc := &docs.KindDoc{Types: []*docs.TypeDoc{
+		{Name: "Frigate", Fields: []*docs.FieldDoc{
+			{Name: "metadata", Type: "metav1.ObjectMeta"},
+			{Name: "spec", Type: "FrigateSpec", TypeLink: "frigatespec"},
+		}},
+		{Name: "FrigateSpec", Fields: []*docs.FieldDoc{
+			{Name: "replicas", Type: "int32", Required: true,
+				Validations: []string{"Minimum=0", "ExclusiveMinimum=true", "Default=3"}},
+			{Name: "crew", Type: "[]CrewMember", TypeLink: "crewmember", Validations: []string{"MaxItems=10"}},
+		}},
+		{Name: "CrewMember", Validations: []string{`XValidation:rule="self.name != 'captain'"`}, Fields: []*docs.FieldDoc{
+			{Name: "name", Type: "string", Validations: []string{`Pattern="^[a-z]+$"`}},
+		}},
+	}}
+
+	expected := []Rule{
+		{
+			Expression: "!has(object.spec) || (has(object.spec.replicas))",
+			Message:    "spec.replicas is required",
+		},
+		{
+			Expression: "!has(object.spec) || (object.spec.replicas > 0)",
+			Message:    "spec.replicas must be > 0",
+		},
+		{
+			Expression: "!has(object.spec) || !has(object.spec.crew) || (size(object.spec.crew) <= 10)",
+			Message:    "spec.crew must have at most 10 items",
+		},
+	}
+	if rules := NewRules(doc); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("NewRules() returned %+v, expected %+v", rules, expected)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	values := parseArgs(`rule="self.name.startsWith(\"a,b\")",message="must start with a,b"`)
+	expected := map[string]string{"rule": `self.name.startsWith("a,b")`, "message": "must start with a,b"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("parseArgs() returned %v, expected %v", values, expected)
+	}
+
+	values = parseArgs("rule=`self > 0`")
+	if values["rule"] != "self > 0" {
+		t.Errorf("parseArgs() returned %v for a raw string", values)
+	}
+}
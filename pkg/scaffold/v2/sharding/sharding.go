@@ -0,0 +1,421 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Dir is the directory of the sharding package, outside of the controllers one so that main.go can set the shard
+const Dir = "sharding"
+
+// overlayDir is the directory of the overlay deploying the manager as a StatefulSet of shards
+var overlayDir = filepath.Join("config", "sharding")
+
+var _ input.File = &Package{}
+
+// Package scaffolds the sharding package, whose predicate filters out the events of the objects of the other shards
+// in the controllers
+type Package struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Package) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "sharding.go")
+	}
+	f.TemplateBody = packageTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+var _ input.File = &Kustomization{}
+
+// Kustomization scaffolds the overlay replacing the Deployment of the manager of the default overlay with a
+// StatefulSet, whose replicas are the shards
+type Kustomization struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Kustomization) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(overlayDir, "kustomization.yaml")
+	}
+	f.TemplateBody = kustomizationTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+var _ input.File = &StatefulSet{}
+
+// StatefulSet scaffolds the StatefulSet of the shards of the manager, whose pods get their shard from their ordinal
+type StatefulSet struct {
+	input.Input
+
+	// Prefix is the name prefix of the default overlay, the StatefulSet is added after it is applied
+	Prefix string
+
+	// Image is controller manager image name
+	Image string
+
+	// Shards is the number of shards, the replicas of the StatefulSet
+	Shards int
+
+	// NamespaceScoped is true if the manager watches the namespaces set with WATCH_NAMESPACE only
+	NamespaceScoped bool
+
+	// SecureDefaults is true if the manager pods run with a restricted security context
+	SecureDefaults bool
+
+	// MetricsAddress is the loopback address the metrics endpoint binds to, proxied by kube-rbac-proxy
+	MetricsAddress string
+
+	// IPv6 is true if the auth proxy listens on the IPv6 and IPv4 addresses of the pod instead of the IPv4 ones only
+	IPv6 bool
+}
+
+// GetInput implements input.File
+func (f *StatefulSet) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(overlayDir, "manager_statefulset.yaml")
+	}
+	if err := setPrefix(&f.Prefix); err != nil {
+		return input.Input{}, err
+	}
+	if f.Shards == 0 {
+		f.Shards = 2
+	}
+	if f.MetricsAddress == "" {
+		f.MetricsAddress = "127.0.0.1:8080"
+	}
+	f.TemplateBody = statefulSetTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+var _ input.File = &DeploymentPatch{}
+
+// DeploymentPatch scaffolds the patch deleting the Deployment of the manager of the default overlay
+type DeploymentPatch struct {
+	input.Input
+
+	// Prefix is the name prefix of the default overlay
+	Prefix string
+}
+
+// GetInput implements input.File
+func (f *DeploymentPatch) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(overlayDir, "delete_manager_deployment_patch.yaml")
+	}
+	if err := setPrefix(&f.Prefix); err != nil {
+		return input.Input{}, err
+	}
+	f.TemplateBody = deploymentPatchTemplate
+	f.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// setPrefix defaults the prefix to the directory name, like the name prefix of the default overlay
+func setPrefix(prefix *string) error {
+	if *prefix != "" {
+		return nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	*prefix = strings.ToLower(filepath.Base(dir))
+	return nil
+}
+
+// nolint:lll
+const packageTemplate = `{{ .Boilerplate }}
+
+// Package sharding splits the objects reconciled by the controllers between the replicas of the manager, for the
+// APIs with more objects than a single manager can keep up with. The manager is then run as the StatefulSet of the
+// config/sharding overlay, whose pods are the shards, e.g. with make deploy-sharding.
+//
+// Each shard only reconciles its own objects: the ones whose {{ .Domain }} shard label is set to its index, or,
+// for the objects without the label, the ones whose namespace and name hash to it. The label pins the objects to
+// a shard when the number of shards changes, e.g. set it from the clients or a defaulting webhook.
+//
+// The events of the objects created by the controllers, e.g. the ones they own, must be received by the shard of
+// their owner: copy the label of the owner with CopyLabel when creating them.
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Label assigns an object to the shard of its value, e.g. "2" for the third replica of the StatefulSet
+const Label = "sharding.{{ .Domain }}/shard"
+
+const (
+	// CountEnv is the environment variable of the number of shards, the replicas of the StatefulSet
+	CountEnv = "SHARD_COUNT"
+	// IndexEnv is the environment variable of the index of the shard, the ordinal of the pod name by default
+	IndexEnv = "SHARD_INDEX"
+	// PodNameEnv is the environment variable of the name of the pod of the StatefulSet, e.g. controller-manager-2
+	PodNameEnv = "POD_NAME"
+)
+
+// Shard is the subset of the objects reconciled by a replica of the manager
+type Shard struct {
+	// Index is the index of the shard, from 0 to Count-1
+	Index int
+	// Count is the number of shards, all the objects are reconciled by a single shard if it is 1
+	Count int
+}
+
+// Current is the shard of the manager, set in main.go. All the objects are reconciled by default.
+var Current = Shard{Count: 1}
+
+// FromEnv returns the shard set by the environment variables of the StatefulSet. All the objects are reconciled
+// if SHARD_COUNT isn't set, e.g. when running the manager with make run.
+func FromEnv() (Shard, error) {
+	count := os.Getenv(CountEnv)
+	if count == "" {
+		return Shard{Count: 1}, nil
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return Shard{}, fmt.Errorf("invalid %s %q, must be a positive number", CountEnv, count)
+	}
+
+	index := os.Getenv(IndexEnv)
+	if index == "" {
+		podName := os.Getenv(PodNameEnv)
+		index = podName[strings.LastIndex(podName, "-")+1:]
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 || i >= n {
+		return Shard{}, fmt.Errorf("invalid shard index %q, must be between 0 and %d, set %s or %s", index, n-1,
+			IndexEnv, PodNameEnv)
+	}
+	return Shard{Index: i, Count: n}, nil
+}
+
+// Of returns the index of the shard of the object, the one of its label or the hash of its namespace and name
+func (s Shard) Of(obj metav1.Object) int {
+	if value, found := obj.GetLabels()[Label]; found {
+		if i, err := strconv.Atoi(value); err == nil && i >= 0 && i < s.Count {
+			return i
+		}
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(obj.GetNamespace() + "/" + obj.GetName()))
+	return int(h.Sum32() % uint32(s.Count))
+}
+
+// Owns returns whether the object is reconciled by the shard
+func (s Shard) Owns(obj metav1.Object) bool {
+	return s.Count <= 1 || s.Of(obj) == s.Index
+}
+
+// Predicate filters out the events of the objects of the other shards, set with WithEventFilter in the
+// SetupWithManager of the controllers
+func Predicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return Current.Owns(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return Current.Owns(e.MetaNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return Current.Owns(e.Meta) },
+		GenericFunc: func(e event.GenericEvent) bool { return Current.Owns(e.Meta) },
+	}
+}
+
+// CopyLabel assigns an object created by a controller to the shard of its owner, so that the events of the object
+// are received by the shard reconciling the owner. The owners without the label are assigned to their current shard.
+func CopyLabel(owner, obj metav1.Object) {
+	value, found := owner.GetLabels()[Label]
+	if !found {
+		if Current.Count <= 1 {
+			return
+		}
+		value = strconv.Itoa(Current.Of(owner))
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[Label] = value
+	obj.SetLabels(labels)
+}
+`
+
+const kustomizationTemplate = `# Deploys the manager as a StatefulSet whose replicas are the shards of the
+# objects reconciled by the controllers, instead of the Deployment of the
+# default overlay. The shard of each pod is the ordinal of its name.
+#
+# The names of the default overlay are already prefixed, keep the ones of the
+# StatefulSet and of the patch in sync with its namePrefix and namespace.
+bases:
+- ../default
+
+resources:
+- manager_statefulset.yaml
+
+patchesStrategicMerge:
+- delete_manager_deployment_patch.yaml
+
+images:
+- name: controller
+  newName: controller
+  newTag: latest
+`
+
+const statefulSetTemplate = `# The shards of the manager, SHARD_COUNT must be kept equal to the replicas.
+# The objects are reassigned to the shards when their number changes, except
+# the ones pinned to a shard by its label.
+#
+# The pods run the manager of config/manager with the patches of the default
+# overlay, without leader election: each shard reconciles its own objects.
+# Keep them in sync, e.g. add the webhook certificate volume of
+# config/default/manager_webhook_patch.yaml if the webhooks are enabled.
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Prefix }}-controller-manager-shards
+  namespace: {{ .Prefix }}-system
+  labels:
+    control-plane: controller-manager
+spec:
+  # Headless service governing the network identity of the shards
+  clusterIP: None
+  selector:
+    control-plane: controller-manager
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .Prefix }}-controller-manager
+  namespace: {{ .Prefix }}-system
+  labels:
+    control-plane: controller-manager
+spec:
+  serviceName: {{ .Prefix }}-controller-manager-shards
+  # The shards are independent, they are started and stopped together
+  podManagementPolicy: Parallel
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+  replicas: {{ .Shards }}
+  template:
+    metadata:
+      labels:
+        control-plane: controller-manager
+    spec:
+      nodeSelector:
+        kubernetes.io/os: linux
+{{- if .SecureDefaults }}
+      securityContext:
+        runAsNonRoot: true
+        runAsUser: 65532
+        seccompProfile:
+          type: RuntimeDefault
+{{- end }}
+      containers:
+      - name: kube-rbac-proxy
+        image: gcr.io/kubebuilder/kube-rbac-proxy:v0.4.1
+        args:
+        - "--secure-listen-address={{ if .IPv6 }}[::]{{ else }}0.0.0.0{{ end }}:8443"
+        - "--upstream=http://{{ .MetricsAddress }}/"
+        - "--logtostderr=true"
+        - "--v=10"
+        ports:
+        - containerPort: 8443
+          name: https
+{{- if .SecureDefaults }}
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+{{- end }}
+      - name: manager
+        command:
+        - /manager
+        args:
+        - "--metrics-bind-address={{ .MetricsAddress }}"
+        image: {{ .Image }}
+        env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+        - name: SHARD_COUNT
+          value: "{{ .Shards }}"
+{{- if .NamespaceScoped }}
+        - name: WATCH_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+{{- end }}
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8081
+          initialDelaySeconds: 15
+          periodSeconds: 20
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 8081
+          initialDelaySeconds: 5
+          periodSeconds: 10
+        resources:
+          limits:
+            cpu: 100m
+            memory: 30Mi
+          requests:
+            cpu: 100m
+            memory: 20Mi
+{{- if .SecureDefaults }}
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+{{- end }}
+      terminationGracePeriodSeconds: 10
+`
+
+const deploymentPatchTemplate = `# Deletes the Deployment of the manager of the default overlay, replaced by
+# the StatefulSet of the shards.
+$patch: delete
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Prefix }}-controller-manager
+  namespace: {{ .Prefix }}-system
+`
@@ -19,10 +19,14 @@ package v2
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/spf13/afero"
+
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
 )
 
 var _ input.File = &Types{}
@@ -33,6 +37,30 @@ type Types struct {
 
 	// Resource is the resource to scaffold the types_test.go file for
 	Resource *resource.Resource
+
+	// ResourceMarker are the arguments of the +kubebuilder:resource marker of resources with short names or
+	// categories, which have to be set along with the path and scope in a single marker
+	ResourceMarker string
+
+	// PrintColumnMarkers are the arguments of the +kubebuilder:printcolumn markers of the printer columns
+	PrintColumnMarkers []string
+
+	// Phase is true if the status has a phase, shown in the Phase printer column
+	Phase bool
+
+	// ScaleMarker are the arguments of the +kubebuilder:subresource:scale marker, empty if the resource has no scale
+	// subresource
+	ScaleMarker string
+
+	// SpecReplicas, StatusReplicas and StatusSelector are the JSON names of the fields of the scale subresource
+	// scaffolded in the spec and the status, empty if they are not scaffolded
+	SpecReplicas   string
+	StatusReplicas string
+	StatusSelector string
+
+	// ClientGen is true if the typed clientset, listers and informers of the Kind are generated with
+	// k8s.io/code-generator
+	ClientGen bool
 }
 
 // GetInput implements input.File
@@ -41,6 +69,48 @@ func (f *Types) GetInput() (input.Input, error) {
 		f.Path = filepath.Join("pkg", "apis", f.Resource.Group, f.Resource.Version,
 			fmt.Sprintf("%s_types.go", strings.ToLower(f.Resource.Kind)))
 	}
+	if len(f.Resource.ShortNames) != 0 || len(f.Resource.Categories) != 0 {
+		args := []string{"path=" + f.Resource.Plural()}
+		if !f.Resource.Namespaced {
+			args = append(args, "scope=Cluster")
+		}
+		if len(f.Resource.ShortNames) != 0 {
+			args = append(args, "shortName="+strings.Join(f.Resource.ShortNames, ";"))
+		}
+		if len(f.Resource.Categories) != 0 {
+			args = append(args, "categories="+strings.Join(f.Resource.Categories, ";"))
+		}
+		f.ResourceMarker = strings.Join(args, ",")
+	}
+
+	f.PrintColumnMarkers = nil
+	f.Phase = false
+	for _, column := range f.Resource.PrintColumns {
+		f.PrintColumnMarkers = append(f.PrintColumnMarkers, fmt.Sprintf("name=%s,type=%s,JSONPath=%s",
+			strconv.Quote(column.Name), strconv.Quote(column.Type), strconv.Quote(column.JSONPath)))
+		if column.JSONPath == resource.PrintColumnPhase.JSONPath {
+			f.Phase = true
+		}
+	}
+
+	f.ScaleMarker, f.SpecReplicas, f.StatusReplicas, f.StatusSelector = "", "", "", ""
+	if scale := f.Resource.Scale; scale != nil {
+		args := []string{"specpath=" + scale.SpecReplicasPath, "statuspath=" + scale.StatusReplicasPath}
+		if len(scale.LabelSelectorPath) != 0 {
+			args = append(args, "selectorpath="+scale.LabelSelectorPath)
+		}
+		f.ScaleMarker = strings.Join(args, ",")
+
+		// The spec of the resources deploying an image or with defaulted fields already has a replicas field
+		f.SpecReplicas = scale.SpecReplicasField()
+		if f.SpecReplicas == "replicas" &&
+			(len(f.Resource.Image) != 0 || f.Resource.DefaultsMode == resource.DefaultsModeMarkers) {
+			f.SpecReplicas = ""
+		}
+		f.StatusReplicas = scale.StatusReplicasField()
+		f.StatusSelector = scale.LabelSelectorField()
+	}
+
 	f.TemplateBody = typesTemplate
 	f.IfExistsAction = input.Error
 	return f.Input, nil
@@ -51,12 +121,59 @@ func (f *Types) Validate() error {
 	return f.Resource.Validate()
 }
 
+// SetStorageVersion adds or removes the +kubebuilder:storageversion marker of the Kind in an existing types file,
+// returning whether the file changed
+func (f *Types) SetStorageVersion(fs afero.Fs, storage bool) (bool, error) {
+	changed, err := internal.SetTypeMarkerInFile(fs, f.Path, f.Resource.Kind, storageVersionMarker, storage)
+	if err != nil {
+		return false, fmt.Errorf("error updating the storage version marker in %s: %v", f.Path, err)
+	}
+	return changed, nil
+}
+
+// IsStorageVersion returns whether the Kind has the +kubebuilder:storageversion marker in an existing types file
+func (f *Types) IsStorageVersion(fs afero.Fs) (bool, error) {
+	return internal.HasTypeMarkerInFile(fs, f.Path, f.Resource.Kind, storageVersionMarker)
+}
+
+const storageVersionMarker = "// +kubebuilder:storageversion"
+
+// SetClientGen adds the markers that generate the typed clientset, listers and informers of the Kind to an existing
+// types file, returning whether the file changed
+func (f *Types) SetClientGen(fs afero.Fs) (bool, error) {
+	markers := []string{clientGenMarker}
+	// The markers are inserted below the root marker, so the last one ends up first
+	if !f.Resource.Namespaced {
+		markers = []string{clientGenNonNamespacedMarker, clientGenMarker}
+	}
+
+	changed := false
+	for _, marker := range markers {
+		set, err := internal.SetTypeMarkerInFile(fs, f.Path, f.Resource.Kind, marker, true)
+		if err != nil {
+			return false, fmt.Errorf("error adding the %s marker in %s: %v", marker, f.Path, err)
+		}
+		changed = changed || set
+	}
+	return changed, nil
+}
+
+const (
+	clientGenMarker              = "// +genclient"
+	clientGenNonNamespacedMarker = "// +genclient:nonNamespaced"
+)
+
+// nolint:lll
 const typesTemplate = `{{ .Boilerplate }}
 
 package {{ .Resource.Version }}
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+{{- if .Resource.ConditionsPackage }}
+
+	"{{ .Repo }}/pkg/conditions"
+{{- end }}
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -67,18 +184,158 @@ type {{.Resource.Kind}}Spec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+{{- if eq .Resource.DefaultsMode "markers" }}
+
+	// The +kubebuilder:default markers set the default values in the CRD schema. The API server applies them when
+	// the objects are created or read, before calling the mutating webhooks, so a defaulting webhook only sees the
+	// fields without a default marker unset. Use the webhook for the defaults that depend on other fields.
+
+	// Foo is an example field of {{.Resource.Kind}}. Edit {{.Resource.Kind}}_types.go to remove/update
+	// +kubebuilder:default=bar
+	// +optional
+	Foo string ` + "`" + `json:"foo,omitempty"` + "`" + `
+{{- if not .Resource.Image }}
+
+	// Replicas is an example of a defaulted field, it is a pointer to tell an omitted field apart from a zero value
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+{{- end }}
+{{- else }}
+
 	// Foo is an example field of {{.Resource.Kind}}. Edit {{.Resource.Kind}}_types.go to remove/update
 	Foo string ` + "`" + `json:"foo,omitempty"` + "`" + `
+{{- end }}
+{{- if .Resource.Image }}
+
+	// Image is the container image deployed by the {{.Resource.Kind}}, e.g. {{ .Resource.Image }}
+	// +kubebuilder:validation:MinLength=1
+	Image string ` + "`" + `json:"image"` + "`" + `
+
+	// Replicas is the number of Pods running the image, defaults to 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+
+	// ContainerPort is the port the image listens on, exposed by the Service of the {{.Resource.Kind}}
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	ContainerPort int32 ` + "`" + `json:"containerPort"` + "`" + `
+{{- end }}
+{{- if .Resource.Suspend }}
+
+	// Suspend tells the controller to stop reconciling the {{.Resource.Kind}}, e.g. during maintenance.
+	// The reconciliation resumes once it is unset or false.
+	// +optional
+	Suspend *bool ` + "`" + `json:"suspend,omitempty"` + "`" + `
+{{- end }}
+{{- if eq .Resource.ExampleFields "rich" }}
+
+	// The next fields show the OpenAPI validation markers, which the API server checks when the objects are created
+	// or updated. See https://book.kubebuilder.io/reference/markers/crd-validation.html for the other markers.
+
+	// Size is an example of an enum, any other value is rejected
+	// +kubebuilder:validation:Enum=Small;Medium;Large
+	// +optional
+	Size string ` + "`" + `json:"size,omitempty"` + "`" + `
+
+	// Hostname is an example of a string validated with a regular expression and a maximum length
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Hostname string ` + "`" + `json:"hostname,omitempty"` + "`" + `
+
+	// Scaling is an example of an optional struct with required fields, which only need to be set with the struct
+	// +optional
+	Scaling *{{.Resource.Kind}}Scaling ` + "`" + `json:"scaling,omitempty"` + "`" + `
+{{- end }}
+{{- if .SpecReplicas }}
+
+	// {{ .SpecReplicas | title }} is the desired number of replicas, set through the scale subresource by kubectl scale
+	// and the HorizontalPodAutoscaler
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	{{ .SpecReplicas | title }} *int32 ` + "`" + `json:"{{ .SpecReplicas }},omitempty"` + "`" + `
+{{- end }}
 }
+{{- if eq .Resource.ExampleFields "rich" }}
+
+// {{.Resource.Kind}}Scaling is an example of a struct whose fields are validated together by a CEL rule.
+// The rule is only added to the CRD schema by controller-gen v0.9.0 or newer and enforced by Kubernetes 1.25 or newer.
+// +kubebuilder:validation:XValidation:rule="self.minReplicas <= self.maxReplicas",message="minReplicas must not be greater than maxReplicas"
+type {{.Resource.Kind}}Scaling struct {
+	// MinReplicas is an example of a required integer with a minimum
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 ` + "`" + `json:"minReplicas"` + "`" + `
+
+	// MaxReplicas is an example of a required integer within a range
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxReplicas int32 ` + "`" + `json:"maxReplicas"` + "`" + `
+}
+{{- end }}
 
 // {{.Resource.Kind}}Status defines the observed state of {{.Resource.Kind}}
 type {{.Resource.Kind}}Status struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+{{- if .Phase }}
+
+	// Phase is a simple, high-level summary of where the {{.Resource.Kind}} is in its lifecycle
+	// +optional
+	Phase string ` + "`" + `json:"phase,omitempty"` + "`" + `
+{{- end }}
+{{- if .Resource.Conditions }}
+
+	// Conditions represent the latest available observations of the state of the {{.Resource.Kind}}
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []{{ if .Resource.ConditionsPackage }}conditions.{{ end }}Condition ` + "`" + `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"` + "`" + `
+{{- end }}
+{{- if .StatusReplicas }}
+
+	// {{ .StatusReplicas | title }} is the observed number of replicas, read through the scale subresource
+	// +optional
+	{{ .StatusReplicas | title }} int32 ` + "`" + `json:"{{ .StatusReplicas }},omitempty"` + "`" + `
+{{- end }}
+{{- if .StatusSelector }}
+
+	// {{ .StatusSelector | title }} is the label selector of the replicas serialized as a string, e.g. with
+	// metav1.FormatLabelSelector, which the HorizontalPodAutoscaler uses to find their Pods
+	// +optional
+	{{ .StatusSelector | title }} string ` + "`" + `json:"{{ .StatusSelector }},omitempty"` + "`" + `
+{{- end }}
 }
 
 // +kubebuilder:object:root=true
-{{ if not .Resource.Namespaced }} // +kubebuilder:resource:scope=Cluster {{ end }}
+{{- if .ClientGen }}
+// +genclient
+{{- if not .Resource.Namespaced }}
+// +genclient:nonNamespaced
+{{- end }}
+{{- end }}
+{{- if eq .Resource.StorageVersion .Resource.Version }}
+// +kubebuilder:storageversion
+{{- end }}
+{{- if or .Resource.StatusSubresource .Resource.Conditions }}
+// +kubebuilder:subresource:status
+{{- end }}
+{{- if .ScaleMarker }}
+// +kubebuilder:subresource:scale:{{ .ScaleMarker }}
+{{- end }}
+{{- if .ResourceMarker }}
+// +kubebuilder:resource:{{ .ResourceMarker }}
+{{- else if not .Resource.HasDefaultPlural }}
+// +kubebuilder:resource:path={{ .Resource.Resource }}{{ if not .Resource.Namespaced }},scope=Cluster{{ end }}
+{{- end }}
+{{- range .PrintColumnMarkers }}
+// +kubebuilder:printcolumn:{{ . }}
+{{- end }}
+{{ if and .Resource.HasDefaultPlural (not .Resource.Namespaced) (not .ResourceMarker) }} // +kubebuilder:resource:scope=Cluster {{ end }}
 
 // {{.Resource.Kind}} is the Schema for the {{ .Resource.Resource }} API
 type {{.Resource.Kind}} struct {
@@ -88,6 +345,18 @@ type {{.Resource.Kind}} struct {
 	Spec   {{.Resource.Kind}}Spec   ` + "`" + `json:"spec,omitempty"` + "`" + `
 	Status {{.Resource.Kind}}Status ` + "`" + `json:"status,omitempty"` + "`" + `
 }
+{{- if .Resource.ConditionsPackage }}
+
+// GetConditions implements conditions.Getter
+func (in *{{.Resource.Kind}}) GetConditions() []conditions.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions implements conditions.Setter
+func (in *{{.Resource.Kind}}) SetConditions(newConditions []conditions.Condition) {
+	in.Status.Conditions = newConditions
+}
+{{- end }}
 
 // +kubebuilder:object:root=true
 
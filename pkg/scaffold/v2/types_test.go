@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var _ = Describe("Types", func() {
+	typesPath := filepath.Join("api", "v1", "frigate_types.go")
+
+	// scaffoldTypes returns the types of the resource
+	scaffoldTypes := func(res *resource.Resource) string {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		Expect(s.Execute(&model.Universe{}, input.Options{},
+			&scaffoldv2.Types{Input: input.Input{Path: typesPath}, Resource: res})).To(Succeed())
+		return scaffoldtest.ReadFile(fs, typesPath)
+	}
+
+	It("should scaffold the fields of the scale subresource along with its marker", func() {
+		scale := resource.DefaultScaleSubresource
+		content := scaffoldTypes(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			StatusSubresource: true, Scale: &scale})
+
+		Expect(content).To(ContainSubstring("// +kubebuilder:subresource:status\n"))
+		Expect(content).To(ContainSubstring("// +kubebuilder:subresource:scale:specpath=.spec.replicas," +
+			"statuspath=.status.replicas,selectorpath=.status.selector\n"))
+		Expect(content).To(ContainSubstring("Replicas *int32 `json:\"replicas,omitempty\"`"))
+		Expect(content).To(ContainSubstring("Replicas int32 `json:\"replicas,omitempty\"`"))
+		Expect(content).To(ContainSubstring("Selector string `json:\"selector,omitempty\"`"))
+	})
+
+	It("should not scaffold the replicas field of the spec twice", func() {
+		scale := resource.DefaultScaleSubresource
+		content := scaffoldTypes(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			DefaultsMode: resource.DefaultsModeMarkers, Scale: &scale})
+
+		Expect(strings.Count(content, "Replicas *int32")).To(Equal(1))
+		Expect(content).NotTo(ContainSubstring("// +kubebuilder:subresource:status\n"))
+	})
+
+	It("should scaffold the image and the port of the container of the resources deploying an image", func() {
+		content := scaffoldTypes(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			Image: "nginx:1.19", ImageContainerPort: 80})
+
+		Expect(content).To(ContainSubstring("Image string `json:\"image\"`"))
+		Expect(content).To(ContainSubstring("ContainerPort int32 `json:\"containerPort\"`"))
+	})
+
+	It("should scaffold the rich example fields with their validation markers", func() {
+		content := scaffoldTypes(&resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true,
+			ExampleFields: resource.ExampleFieldsRich})
+
+		Expect(content).To(ContainSubstring("// +kubebuilder:validation:Enum=Small;Medium;Large\n"))
+		Expect(content).To(ContainSubstring("// +kubebuilder:validation:Pattern="))
+		Expect(content).To(ContainSubstring(
+			`// +kubebuilder:validation:XValidation:rule="self.minReplicas <= self.maxReplicas"`))
+		Expect(content).To(ContainSubstring("type FrigateScaling struct {"))
+	})
+})
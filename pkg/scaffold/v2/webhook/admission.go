@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/inject"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// Failure policies of the admission webhooks, set in their +kubebuilder:webhook markers
+const (
+	FailurePolicyFail   = "fail"
+	FailurePolicyIgnore = "ignore"
+)
+
+// SideEffects are the side effects that the admission webhooks can declare
+var SideEffects = []string{"None", "NoneOnDryRun", "Some", "Unknown"}
+
+// AdmissionOptions are the settings of the defaulting and validating webhooks of a resource in the webhook
+// configurations. controller-gen only supports the failure policy in the +kubebuilder:webhook markers, the
+// side effects and the timeout are set by a kustomize patch of the generated configurations.
+type AdmissionOptions struct {
+	// FailurePolicy is whether the requests are rejected (fail) or admitted (ignore) when the webhook can't be
+	// called, defaults to fail for the types of the project and to ignore for the Kubernetes built-in types
+	FailurePolicy string
+	// SideEffects is whether the webhooks have side effects, one of SideEffects, unset if empty
+	SideEffects string
+	// TimeoutSeconds is how long the API server waits for the webhooks, between 1 and 30, unset if 0
+	TimeoutSeconds int
+}
+
+// Validate validates the values
+func (o AdmissionOptions) Validate() error {
+	switch o.FailurePolicy {
+	case "", FailurePolicyFail, FailurePolicyIgnore:
+	default:
+		return fmt.Errorf("unknown failure policy %q, must be either %q or %q",
+			o.FailurePolicy, FailurePolicyFail, FailurePolicyIgnore)
+	}
+
+	if o.SideEffects != "" {
+		found := false
+		for _, sideEffects := range SideEffects {
+			found = found || sideEffects == o.SideEffects
+		}
+		if !found {
+			return fmt.Errorf("unknown side effects %q, must be one of %q", o.SideEffects, SideEffects)
+		}
+	}
+
+	if o.TimeoutSeconds < 0 || o.TimeoutSeconds > 30 {
+		return fmt.Errorf("timeout must be between 1 and 30 seconds (was %d)", o.TimeoutSeconds)
+	}
+
+	return nil
+}
+
+// IsSet returns true if any of the options is set
+func (o AdmissionOptions) IsSet() bool {
+	return o.FailurePolicy != "" || o.SideEffects != "" || o.TimeoutSeconds != 0
+}
+
+const kustomizePatchScaffoldMarker = "# +kubebuilder:scaffold:webhookpatch"
+
+var _ input.File = &AdmissionPatch{}
+
+// AdmissionPatch scaffolds the patch setting the side effects and the timeout of the defaulting and validating
+// webhooks of a resource in the configurations generated by controller-gen
+type AdmissionPatch struct {
+	input.Input
+
+	// Resource is the Resource of the webhooks
+	Resource *resource.Resource
+
+	// Options are the side effects and the timeout of the webhooks
+	Options AdmissionOptions
+
+	// If patch the defaulting webhook
+	Defaulting bool
+	// If patch the validating webhook
+	Validating bool
+}
+
+// GetInput implements input.File
+func (f *AdmissionPatch) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "webhook", "patches", fmt.Sprintf("options_in_%s.yaml", f.Resource.Plural()))
+	}
+	f.TemplateBody = admissionPatchTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Update adds the patch to the kustomization of the webhook configurations, it returns false if it has no marker,
+// as in the projects scaffolded before the webhook options could be set
+func (f *AdmissionPatch) Update(fs afero.Fs) (bool, error) {
+	kustomizationPath := filepath.Join("config", "webhook", "kustomization.yaml")
+	exists, err := afero.Exists(fs, kustomizationPath)
+	if err != nil || !exists {
+		return false, err
+	}
+	hasMarker, err := afero.FileContainsBytes(fs, kustomizationPath, []byte(kustomizePatchScaffoldMarker))
+	if err != nil || !hasMarker {
+		return false, err
+	}
+
+	return true, inject.Update(fs, kustomizationPath, map[string][]string{
+		kustomizePatchScaffoldMarker: {fmt.Sprintf("- patches/options_in_%s.yaml\n", f.Resource.Plural())},
+	})
+}
+
+// NeedsPatch returns true if the options have to be set by an AdmissionPatch
+func (f *AdmissionPatch) NeedsPatch() bool {
+	return (f.Options.SideEffects != "" || f.Options.TimeoutSeconds != 0) && (f.Defaulting || f.Validating)
+}
+
+const admissionPatchTemplate = `# The following patch sets the side effects and the timeout of the webhooks of
+# {{ .Resource.Kind }}, which controller-gen can't set from the +kubebuilder:webhook markers
+{{- if .Defaulting }}
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: mutating-webhook-configuration
+webhooks:
+- name: m{{ lower .Resource.Kind }}.kb.io
+{{- if .Options.SideEffects }}
+  sideEffects: {{ .Options.SideEffects }}
+{{- end }}
+{{- if .Options.TimeoutSeconds }}
+  timeoutSeconds: {{ .Options.TimeoutSeconds }}
+{{- end }}
+{{- end }}
+{{- if and .Defaulting .Validating }}
+---
+{{- end }}
+{{- if .Validating }}
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validating-webhook-configuration
+webhooks:
+- name: v{{ lower .Resource.Kind }}.kb.io
+{{- if .Options.SideEffects }}
+  sideEffects: {{ .Options.SideEffects }}
+{{- end }}
+{{- if .Options.TimeoutSeconds }}
+  timeoutSeconds: {{ .Options.TimeoutSeconds }}
+{{- end }}
+{{- end }}
+`
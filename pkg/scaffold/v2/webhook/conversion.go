@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+var _ input.File = &Conversion{}
+
+// Conversion scaffolds the conversion.Hub or conversion.Convertible implementation for a Resource
+type Conversion struct {
+	input.Input
+
+	// Resource is the Resource to make the Conversion for
+	Resource *resource.Resource
+
+	// ResourcePackage is the package of the Resource
+	ResourcePackage string
+
+	// HubVersion is the version every other version of the Kind converts to and from
+	HubVersion string
+}
+
+// GetInput implements input.File
+func (f *Conversion) GetInput() (input.Input, error) {
+
+	f.ResourcePackage, _ = util.GetResourceInfo(f.Resource, f.Repo, f.Domain, f.MultiGroup)
+
+	if f.HubVersion == "" {
+		f.HubVersion = f.Resource.Version
+	}
+
+	if f.Path == "" {
+		if f.MultiGroup {
+			f.Path = filepath.Join("apis", f.Resource.Group, f.Resource.Version,
+				fmt.Sprintf("%s_conversion.go", strings.ToLower(f.Resource.Kind)))
+		} else {
+			f.Path = filepath.Join("api", f.Resource.Version,
+				fmt.Sprintf("%s_conversion.go", strings.ToLower(f.Resource.Kind)))
+		}
+	}
+
+	if f.IsHub() {
+		f.TemplateBody = hubTemplate
+	} else {
+		f.TemplateBody = convertibleTemplate
+	}
+
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *Conversion) Validate() error {
+	return f.Resource.Validate()
+}
+
+// IsHub returns true if the Resource version is the hub version
+func (f *Conversion) IsHub() bool {
+	return f.HubVersion == f.Resource.Version
+}
+
+const (
+	hubTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Hub = &{{ .Resource.Kind }}{}
+
+// Hub marks this type as a conversion hub. Every other version of {{ .Resource.Kind }} must
+// implement conversion.Convertible converting to and from this version.
+func (*{{ .Resource.Kind }}) Hub() {}
+`
+
+	convertibleTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	{{ .Resource.GroupImportSafe }}{{ .HubVersion }} "{{ .ResourcePackage }}/{{ .HubVersion }}"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+
+var _ conversion.Convertible = &{{ .Resource.Kind }}{}
+
+// ConvertTo converts this {{ .Resource.Kind }} to the Hub version ({{ .HubVersion }}).
+func (src *{{ .Resource.Kind }}) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*{{ .Resource.GroupImportSafe }}{{ .HubVersion }}.{{ .Resource.Kind }})
+
+	// ObjectMeta
+	dst.ObjectMeta = src.ObjectMeta
+
+	// TODO(user): convert the Spec and Status fields.
+	return nil
+}
+
+// ConvertFrom converts from the Hub version ({{ .HubVersion }}) to this version.
+func (dst *{{ .Resource.Kind }}) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*{{ .Resource.GroupImportSafe }}{{ .HubVersion }}.{{ .Resource.Kind }})
+
+	// ObjectMeta
+	dst.ObjectMeta = src.ObjectMeta
+
+	// TODO(user): convert the Spec and Status fields.
+	return nil
+}
+`
+)
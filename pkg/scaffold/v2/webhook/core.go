@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+var _ input.File = &CoreWebhook{}
+
+// CoreWebhook scaffolds the webhooks of a Kubernetes built-in type, such as Pod, whose Go type is not defined
+// in the project. The defaulting and validating logic is implemented in an unexported type served through
+// admission handlers, as the webhook.Defaulter and webhook.Validator interfaces need the methods on the Go type.
+type CoreWebhook struct {
+	input.Input
+
+	// Resource is the Resource to make the Webhook for
+	Resource *resource.Resource
+
+	// ResourcePackage is the package of the Resource
+	ResourcePackage string
+
+	// Plural is the plural lowercase of kind
+	Plural string
+
+	// MarkerGroup is the API group of the Resource in the webhook markers, quoted if it is the empty core group
+	MarkerGroup string
+
+	// PathGroup is the API group of the Resource in the webhook paths, with dashes instead of dots
+	PathGroup string
+
+	// TypeName is the name of the type implementing the webhooks, the Kind in lower camel case
+	TypeName string
+
+	// If scaffold the defaulting webhook
+	Defaulting bool
+	// If scaffold the validating webhook
+	Validating bool
+
+	// FailurePolicy is the failure policy of the webhooks, defaults to ignore
+	FailurePolicy string
+}
+
+// GetInput implements input.File
+func (f *CoreWebhook) GetInput() (input.Input, error) {
+	var groupDomain string
+	f.ResourcePackage, groupDomain = util.GetResourceInfo(f.Resource, f.Repo, f.Domain, f.MultiGroup)
+	// The core group is the empty API group
+	f.MarkerGroup = `""`
+	if f.Resource.Group != "core" {
+		f.MarkerGroup = groupDomain
+		f.PathGroup = strings.Replace(groupDomain, ".", "-", -1)
+	}
+
+	f.TypeName = strings.ToLower(f.Resource.Kind[:1]) + f.Resource.Kind[1:]
+
+	if f.Plural == "" {
+		f.Plural = f.Resource.Plural()
+	}
+
+	if f.FailurePolicy == "" {
+		f.FailurePolicy = FailurePolicyIgnore
+	}
+
+	if f.Path == "" {
+		f.Path = CoreWebhookPath(f.Resource)
+	}
+	f.TemplateBody = coreWebhookTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *CoreWebhook) Validate() error {
+	if err := f.Resource.Validate(); err != nil {
+		return err
+	}
+
+	if !util.IsCoreGroup(f.Resource.Group) {
+		return fmt.Errorf("%s is not a Kubernetes API group", f.Resource.Group)
+	}
+
+	return nil
+}
+
+// CoreWebhookPath returns the path of the webhooks of a Kubernetes built-in type
+func CoreWebhookPath(r *resource.Resource) string {
+	return filepath.Join("webhooks", r.Group, r.Version, fmt.Sprintf("%s_webhook.go", strings.ToLower(r.Kind)))
+}
+
+// nolint:lll
+const coreWebhookTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"context"
+	{{- if .Defaulting }}
+	"encoding/json"
+	{{- end }}
+	"net/http"
+
+	{{- if .Validating }}
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	{{- end }}
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var {{ lower .Resource.Kind }}log = logf.Log.WithName("{{ lower .Resource.Kind }}-webhook")
+
+// {{ .TypeName }}Webhook defaults and validates the {{ .Resource.Kind }} objects of the admission requests
+type {{ .TypeName }}Webhook struct {
+	decoder *admission.Decoder
+}
+
+// Setup{{ .Resource.Kind }}WebhookWithManager registers the webhooks of {{ .Resource.Kind }} in the webhook server of the manager
+func Setup{{ .Resource.Kind }}WebhookWithManager(mgr ctrl.Manager) error {
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	w := &{{ .TypeName }}Webhook{decoder: decoder}
+
+	server := mgr.GetWebhookServer()
+	{{- if .Defaulting }}
+	server.Register("/mutate-{{ .PathGroup }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }}",
+		&webhook.Admission{Handler: admission.HandlerFunc(w.handleDefault)})
+	{{- end }}
+	{{- if .Validating }}
+	server.Register("/validate-{{ .PathGroup }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }}",
+		&webhook.Admission{Handler: admission.HandlerFunc(w.handleValidate)})
+	{{- end }}
+	return nil
+}
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+
+{{- if eq .FailurePolicy "ignore" }}
+
+// The webhooks are called for the {{ .Plural }} of every namespace, failurePolicy=ignore admits them while the
+// webhook server is unavailable, e.g. while the manager itself is being deployed.
+// TODO(user): change it to failurePolicy=fail once the objects the manager depends on are not sent to the webhooks.
+{{- end }}
+{{- if .Defaulting }}
+
+// +kubebuilder:webhook:path=/mutate-{{ .PathGroup }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy={{ .FailurePolicy }},groups={{ .MarkerGroup }},resources={{ .Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
+
+// Default sets the default values of the {{ .Resource.Kind }}
+func (w *{{ .TypeName }}Webhook) Default(ctx context.Context, obj *{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}) error {
+	{{ lower .Resource.Kind }}log.Info("default", "name", obj.Name)
+
+	// TODO(user): fill in your defaulting logic.
+	return nil
+}
+
+// handleDefault patches the {{ .Resource.Kind }} of the request with its default values
+func (w *{{ .TypeName }}Webhook) handleDefault(ctx context.Context, req admission.Request) admission.Response {
+	obj := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+	if err := w.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := w.Default(ctx, obj); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+{{- end }}
+{{- if .Validating }}
+
+// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .PathGroup }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy={{ .FailurePolicy }},groups={{ .MarkerGroup }},resources={{ .Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
+
+// ValidateCreate validates the {{ .Resource.Kind }} upon creation
+func (w *{{ .TypeName }}Webhook) ValidateCreate(ctx context.Context, obj *{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}) error {
+	{{ lower .Resource.Kind }}log.Info("validate create", "name", obj.Name)
+
+	// TODO(user): fill in your validation logic upon object creation.
+	return nil
+}
+
+// ValidateUpdate validates the {{ .Resource.Kind }} upon update
+func (w *{{ .TypeName }}Webhook) ValidateUpdate(ctx context.Context, oldObj, obj *{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}) error {
+	{{ lower .Resource.Kind }}log.Info("validate update", "name", obj.Name)
+
+	// TODO(user): fill in your validation logic upon object update.
+	return nil
+}
+
+// ValidateDelete validates the {{ .Resource.Kind }} upon deletion
+func (w *{{ .TypeName }}Webhook) ValidateDelete(ctx context.Context, obj *{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}) error {
+	{{ lower .Resource.Kind }}log.Info("validate delete", "name", obj.Name)
+
+	// TODO(user): fill in your validation logic upon object deletion.
+	return nil
+}
+
+// handleValidate admits the {{ .Resource.Kind }} of the request if it is valid for the operation
+func (w *{{ .TypeName }}Webhook) handleValidate(ctx context.Context, req admission.Request) admission.Response {
+	obj := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+
+	var err error
+	switch req.Operation {
+	case admissionv1beta1.Create:
+		if err := w.decoder.Decode(req, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = w.ValidateCreate(ctx, obj)
+	case admissionv1beta1.Update:
+		oldObj := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+		if err := w.decoder.Decode(req, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := w.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = w.ValidateUpdate(ctx, oldObj, obj)
+	case admissionv1beta1.Delete:
+		// The object being deleted is only sent as the old object
+		if err := w.decoder.DecodeRaw(req.OldObject, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = w.ValidateDelete(ctx, obj)
+	}
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	return admission.Allowed("")
+}
+{{- end }}
+`
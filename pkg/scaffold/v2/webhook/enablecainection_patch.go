@@ -24,7 +24,7 @@ import (
 
 var _ input.File = &InjectCAPatch{}
 
-// InjectCAPatch scaffolds the InjectCAPatch file in manager folder.
+// InjectCAPatch scaffolds the InjectCAPatch file in the certmanager component.
 type InjectCAPatch struct {
 	input.Input
 }
@@ -32,7 +32,7 @@ type InjectCAPatch struct {
 // GetInput implements input.File
 func (f *InjectCAPatch) GetInput() (input.Input, error) {
 	if f.Path == "" {
-		f.Path = filepath.Join("config", "default", "webhookcainjection_patch.yaml")
+		f.Path = filepath.Join("config", "components", "certmanager", "webhookcainjection_patch.yaml")
 	}
 	f.TemplateBody = injectCAPatchTemplate
 	f.Input.IfExistsAction = input.Error
@@ -17,6 +17,7 @@ limitations under the License.
 package webhook
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -39,10 +40,14 @@ func (f *Kustomization) GetInput() (input.Input, error) {
 	return f.Input, nil
 }
 
-const KustomizeWebhookTemplate = `resources:
+var KustomizeWebhookTemplate = fmt.Sprintf(`resources:
 - manifests.yaml
 - service.yaml
 
+patchesStrategicMerge:
+# patches here set the side effects and the timeout of the webhooks of each resource
+%s
+
 configurations:
 - kustomizeconfig.yaml
-`
+`, kustomizePatchScaffoldMarker)
@@ -19,6 +19,7 @@ package webhook
 import (
 	"path/filepath"
 
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
@@ -27,6 +28,12 @@ var _ input.File = &Service{}
 // Service scaffolds the Service file in manager folder.
 type Service struct {
 	input.Input
+
+	// WebhookPort is the port the webhook server of the manager listens on
+	WebhookPort int
+
+	// DualStack is true if the Service is assigned IPv4 and IPv6 addresses where the cluster supports it
+	DualStack bool
 }
 
 // GetInput implements input.File
@@ -34,6 +41,9 @@ func (f *Service) GetInput() (input.Input, error) {
 	if f.Path == "" {
 		f.Path = filepath.Join("config", "webhook", "service.yaml")
 	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
 	f.TemplateBody = ServiceTemplate
 	f.Input.IfExistsAction = input.Error
 	return f.Input, nil
@@ -46,9 +56,12 @@ metadata:
   name: webhook-service
   namespace: system
 spec:
+{{- if .DualStack }}
+  ipFamilyPolicy: PreferDualStack
+{{- end }}
   ports:
     - port: 443
-      targetPort: 9443
+      targetPort: {{ .WebhookPort }}
   selector:
     control-plane: controller-manager
 `
@@ -21,8 +21,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
-
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
@@ -50,6 +48,9 @@ type Webhook struct {
 	Defaulting bool
 	// If scaffold the validating webhook
 	Validating bool
+
+	// FailurePolicy is the failure policy of the defaulting and validating webhooks, defaults to fail
+	FailurePolicy string
 }
 
 // GetInput implements input.File
@@ -60,7 +61,11 @@ func (f *Webhook) GetInput() (input.Input, error) {
 	f.GroupDomainWithDash = strings.Replace(f.GroupDomain, ".", "-", -1)
 
 	if f.Plural == "" {
-		f.Plural = flect.Pluralize(strings.ToLower(f.Resource.Kind))
+		f.Plural = f.Resource.Plural()
+	}
+
+	if f.FailurePolicy == "" {
+		f.FailurePolicy = FailurePolicyFail
 	}
 
 	if f.Path == "" {
@@ -82,7 +87,12 @@ func (f *Webhook) GetInput() (input.Input, error) {
 	}
 
 	f.TemplateBody = webhookTemplate
-	f.Input.IfExistsAction = input.Error
+	if f.Defaulting || f.Validating {
+		f.Input.IfExistsAction = input.Error
+	} else {
+		// Conversion webhooks only need the webhook setup, which an existing file already has
+		f.Input.IfExistsAction = input.Skip
+	}
 	return f.Input, nil
 }
 
@@ -99,8 +109,10 @@ package {{ .Resource.Version }}
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
-	{{- if or .Validating .Defaulting }}
+	{{- if .Validating }}
 	"k8s.io/apimachinery/pkg/runtime"
+	{{- end }}
+	{{- if or .Validating .Defaulting }}
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	{{- end }}
 )
@@ -119,7 +131,7 @@ func (r *{{.Resource.Kind}}) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 	// nolint:lll
 	DefaultingWebhookTemplate = `
-// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy=fail,groups={{ .GroupDomain }},resources={{ .Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
+// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy={{ .FailurePolicy }},groups={{ .GroupDomain }},resources={{ .Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
 
 var _ webhook.Defaulter = &{{ .Resource.Kind }}{}
 
@@ -133,7 +145,7 @@ func (r *{{ .Resource.Kind }}) Default() {
 	// nolint:lll
 	ValidatingWebhookTemplate = `
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
-// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy=fail,groups={{ .GroupDomain }},resources={{ .Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
+// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy={{ .FailurePolicy }},groups={{ .GroupDomain }},resources={{ .Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
 
 var _ webhook.Validator = &{{ .Resource.Kind }}{}
 
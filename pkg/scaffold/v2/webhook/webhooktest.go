@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &WebhookTest{}
+
+// WebhookTest scaffolds the <kind>_webhook_test.go file to test the webhooks of a Resource
+type WebhookTest struct {
+	input.Input
+
+	// Resource is the Resource to test the Webhook for
+	Resource *resource.Resource
+
+	// If test the defaulting webhook
+	Defaulting bool
+	// If test the validating webhook
+	Validating bool
+}
+
+// GetInput implements input.File
+func (f *WebhookTest) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		if f.MultiGroup {
+			f.Path = filepath.Join("apis", f.Resource.Group, f.Resource.Version,
+				fmt.Sprintf("%s_webhook_test.go", strings.ToLower(f.Resource.Kind)))
+		} else {
+			f.Path = filepath.Join("api", f.Resource.Version,
+				fmt.Sprintf("%s_webhook_test.go", strings.ToLower(f.Resource.Kind)))
+		}
+	}
+	f.TemplateBody = webhookTestTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+// Validate validates the values
+func (f *WebhookTest) Validate() error {
+	return f.Resource.Validate()
+}
+
+// nolint:lll
+const webhookTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The webhooks fail closed, so every request below goes through the webhook server of the suite.
+var _ = Describe("{{ .Resource.Kind }} webhook", func() {
+{{- if .Defaulting }}
+	It("should default the {{ .Resource.Kind }} on creation", func() {
+		ctx := context.Background()
+		instance := &{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaulted-{{ lower .Resource.Kind }}", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+		// TODO(user): check the fields set by Default, instance holds the {{ .Resource.Kind }} stored by the API server.
+
+		Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+	})
+{{- end }}
+{{- if .Validating }}
+{{- if .Defaulting }}
+{{ end }}
+	It("should admit a valid {{ .Resource.Kind }}", func() {
+		ctx := context.Background()
+		instance := &{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: "valid-{{ lower .Resource.Kind }}", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+	})
+
+	// TODO(user): Add the cases rejected by your validation logic, e.g.
+	// Expect(k8sClient.Create(ctx, invalid)).NotTo(Succeed())
+{{- end }}
+})
+
+// The table-driven cases below call the webhook methods directly, without going through the API server.
+var _ = Describe("{{ .Resource.Kind }} webhook methods", func() {
+{{- if .Defaulting }}
+	DescribeTable("Default",
+		func(instance, expected *{{ .Resource.Kind }}) {
+			instance.Default()
+			Expect(instance).To(Equal(expected))
+		},
+		Entry("keeps an empty {{ .Resource.Kind }} unchanged", &{{ .Resource.Kind }}{}, &{{ .Resource.Kind }}{}),
+		// TODO(user): Add the cases of your defaulting logic, with the {{ .Resource.Kind }} to default and the expected one.
+	)
+{{- end }}
+{{- if .Validating }}
+{{- if .Defaulting }}
+{{ end }}
+	DescribeTable("ValidateCreate",
+		func(instance *{{ .Resource.Kind }}, expectedErr string) {
+			expect{{ .Resource.Kind }}Error(instance.ValidateCreate(), expectedErr)
+		},
+		Entry("admits a valid {{ .Resource.Kind }}", &{{ .Resource.Kind }}{}, ""),
+		// TODO(user): Add the cases rejected by your validation logic, with a substring of the expected error, e.g.
+		// Entry("rejects an invalid {{ .Resource.Kind }}", &{{ .Resource.Kind }}{...}, "spec.foo: Invalid value"),
+	)
+
+	DescribeTable("ValidateUpdate",
+		func(old, instance *{{ .Resource.Kind }}, expectedErr string) {
+			expect{{ .Resource.Kind }}Error(instance.ValidateUpdate(old), expectedErr)
+		},
+		Entry("admits an unchanged {{ .Resource.Kind }}", &{{ .Resource.Kind }}{}, &{{ .Resource.Kind }}{}, ""),
+		// TODO(user): Add the updates rejected by your validation logic, e.g. of the immutable fields.
+	)
+
+	DescribeTable("ValidateDelete",
+		func(instance *{{ .Resource.Kind }}, expectedErr string) {
+			expect{{ .Resource.Kind }}Error(instance.ValidateDelete(), expectedErr)
+		},
+		Entry("admits the deletion of a {{ .Resource.Kind }}", &{{ .Resource.Kind }}{}, ""),
+	)
+{{- end }}
+})
+{{- if .Validating }}
+
+// expect{{ .Resource.Kind }}Error expects err to contain expectedErr, or to be nil if expectedErr is empty
+func expect{{ .Resource.Kind }}Error(err error, expectedErr string) {
+	if expectedErr == "" {
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return
+	}
+	ExpectWithOffset(1, err).To(MatchError(ContainSubstring(expectedErr)))
+}
+{{- end }}
+`
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var _ = Describe("WebhookTest", func() {
+	It("should scaffold table-driven tests of the webhook methods", func() {
+		s, fs := scaffoldtest.NewV2Scaffold()
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(s.Execute(&model.Universe{}, input.Options{},
+			&webhook.WebhookTest{Resource: frigate, Defaulting: true, Validating: true})).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("api", "v1", "frigate_webhook_test.go"))
+		Expect(content).To(ContainSubstring(`DescribeTable("Default",`))
+		for _, method := range []string{"ValidateCreate()", "ValidateUpdate(old)", "ValidateDelete()"} {
+			Expect(content).To(ContainSubstring("expectFrigateError(instance." + method + ", expectedErr)"))
+		}
+	})
+})
@@ -19,6 +19,7 @@ package v2
 import (
 	"path/filepath"
 
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
@@ -27,12 +28,18 @@ var _ input.File = &ManagerWebhookPatch{}
 // CRDWebhookPatch scaffolds a CRDWebhookPatch for a Resource
 type ManagerWebhookPatch struct {
 	input.Input
+
+	// WebhookPort is the port the webhook server of the manager listens on
+	WebhookPort int
 }
 
 // GetInput implements input.File
 func (f *ManagerWebhookPatch) GetInput() (input.Input, error) {
 	if f.Path == "" {
-		f.Path = filepath.Join("config", "default", "manager_webhook_patch.yaml")
+		f.Path = filepath.Join("config", "components", ComponentWebhook, "manager_webhook_patch.yaml")
+	}
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
 	}
 	f.TemplateBody = ManagerWebhookPatchTemplate
 	return f.Input, nil
@@ -49,7 +56,7 @@ spec:
       containers:
       - name: manager
         ports:
-        - containerPort: 9443
+        - containerPort: {{ .WebhookPort }}
           name: webhook-server
           protocol: TCP
         volumeMounts:
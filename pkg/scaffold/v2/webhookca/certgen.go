@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhookca
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// CertGenImage is the image that generates the certificate of the webhook server and injects its CA
+const CertGenImage = "jettech/kube-webhook-certgen:v1.5.0"
+
+// componentDir is the directory of the webhookca component of the default overlay
+var componentDir = filepath.Join("config", "components", "webhookca")
+
+var _ input.File = &CertGenJob{}
+
+// CertGenJob scaffolds the Job that generates the certificate of the webhook server in the webhookca component
+type CertGenJob struct {
+	input.Input
+
+	// Image is the image of the Job, defaults to CertGenImage
+	Image string
+}
+
+// GetInput implements input.File
+func (f *CertGenJob) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(componentDir, "certgen_job.yaml")
+	}
+	if f.Image == "" {
+		f.Image = CertGenImage
+	}
+	f.TemplateBody = certGenJobTemplate
+	return f.Input, nil
+}
+
+const certGenJobTemplate = `# Generates a self-signed certificate for the webhook server in the webhook-server-cert Secret,
+# unless the Secret already exists, and sets its CA as the caBundle of the admission webhooks.
+# $(WEBHOOK_SERVICE_NAME), $(WEBHOOK_SERVICE_NAMESPACE) and the names of the webhook
+# configurations will be substituted by kustomize.
+# Applying the webhook configurations again resets their caBundle, delete the Job before
+# deploying the manifests to run it again.
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: webhook-certgen
+  namespace: system
+spec:
+  template:
+    spec:
+      serviceAccountName: webhook-certgen
+      restartPolicy: OnFailure
+      initContainers:
+      - name: create
+        image: {{ .Image }}
+        args:
+        - create
+        - --host=$(WEBHOOK_SERVICE_NAME),$(WEBHOOK_SERVICE_NAME).$(WEBHOOK_SERVICE_NAMESPACE).svc
+        - --namespace=$(WEBHOOK_SERVICE_NAMESPACE)
+        - --secret-name=webhook-server-cert
+      containers:
+      - name: patch-mutating
+        image: {{ .Image }}
+        args:
+        - patch
+        - --webhook-name=$(MUTATING_WEBHOOK_CONFIGURATION_NAME)
+        - --namespace=$(WEBHOOK_SERVICE_NAMESPACE)
+        - --secret-name=webhook-server-cert
+        - --patch-validating=false
+      - name: patch-validating
+        image: {{ .Image }}
+        args:
+        - patch
+        - --webhook-name=$(VALIDATING_WEBHOOK_CONFIGURATION_NAME)
+        - --namespace=$(WEBHOOK_SERVICE_NAMESPACE)
+        - --secret-name=webhook-server-cert
+        - --patch-mutating=false
+`
+
+var _ input.File = &CertGenRBAC{}
+
+// CertGenRBAC scaffolds the ServiceAccount of the certificate generation Job and its permissions
+// in the webhookca component
+type CertGenRBAC struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *CertGenRBAC) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(componentDir, "certgen_rbac.yaml")
+	}
+	f.TemplateBody = certGenRBACTemplate
+	return f.Input, nil
+}
+
+const certGenRBACTemplate = `# Lets the webhook-certgen Job store the certificate in its Secret and
+# update the caBundle of the admission webhooks.
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: webhook-certgen
+  namespace: system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: webhook-certgen
+  namespace: system
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - create
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: webhook-certgen
+  namespace: system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: webhook-certgen
+subjects:
+- kind: ServiceAccount
+  name: webhook-certgen
+  namespace: system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: webhook-certgen
+rules:
+- apiGroups:
+  - admissionregistration.k8s.io
+  resources:
+  - mutatingwebhookconfigurations
+  - validatingwebhookconfigurations
+  verbs:
+  - get
+  - update
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: webhook-certgen
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: webhook-certgen
+subjects:
+- kind: ServiceAccount
+  name: webhook-certgen
+  namespace: system
+`
+
+var _ input.File = &ManagerCertPatch{}
+
+// ManagerCertPatch scaffolds the patch that mounts the generated certificate in the manager
+// in the webhookca component
+type ManagerCertPatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *ManagerCertPatch) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(componentDir, "manager_webhook_cert_patch.yaml")
+	}
+	f.TemplateBody = managerCertPatchTemplate
+	return f.Input, nil
+}
+
+const managerCertPatchTemplate = `# Mounts the certificate of the webhook-certgen Job, stored under the cert and key
+# keys of its Secret, with the file names expected by the webhook server.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      volumes:
+      - name: cert
+        secret:
+          secretName: webhook-server-cert
+          items:
+          - key: cert
+            path: tls.crt
+          - key: key
+            path: tls.key
+`
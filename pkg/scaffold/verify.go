@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+)
+
+// verifyScaffolder checks that the files of the project are consistent with the resources tracked in the
+// project configuration, fixing the inconsistencies that can be fixed if requested
+type verifyScaffolder struct {
+	config *config.Config
+	// fix indicates that the inconsistencies should be fixed instead of only reported
+	fix bool
+}
+
+func NewVerifyScaffolder(config *config.Config, fix bool) Scaffolder {
+	return &verifyScaffolder{
+		config: config,
+		fix:    fix,
+	}
+}
+
+// drift is an inconsistency between a tracked resource and the files of the project
+type drift struct {
+	resource *resource.Resource
+	problem  string
+	// fix fixes the inconsistency, nil if it has to be fixed by hand
+	fix func() error
+}
+
+func (d drift) String() string {
+	return fmt.Sprintf("%s/%s, Kind=%s: %s", d.resource.Group, d.resource.Version, d.resource.Kind, d.problem)
+}
+
+func (s *verifyScaffolder) Scaffold() error {
+	if !s.config.IsV2() {
+		return fmt.Errorf("verifying the project is not supported for project version %v", s.config.Version)
+	}
+
+	fmt.Printf("Verifying the project files against %s...\n", s.config.Path())
+
+	var drifts []drift
+	for _, gvk := range s.config.Resources {
+		res := &resource.Resource{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Resource: gvk.Plural,
+			GroupPackage: s.config.GroupPackage(gvk.Group)}
+		if err := res.Validate(); err != nil {
+			return fmt.Errorf("invalid resource %s/%s, Kind=%s: %v", gvk.Group, gvk.Version, gvk.Kind, err)
+		}
+
+		resourceDrifts, err := s.verify(res)
+		if err != nil {
+			return err
+		}
+		drifts = append(drifts, resourceDrifts...)
+	}
+
+	remaining := 0
+	for _, d := range drifts {
+		if s.fix && d.fix != nil {
+			if err := d.fix(); err != nil {
+				return fmt.Errorf("error fixing %s: %v", d, err)
+			}
+			fmt.Printf("Fixed %s\n", d)
+			continue
+		}
+		remaining++
+		fmt.Println(d)
+	}
+
+	if remaining != 0 {
+		if !s.fix {
+			return fmt.Errorf("found %d problem(s), run with --fix to fix the ones that can be fixed", remaining)
+		}
+		return fmt.Errorf("found %d problem(s) that have to be fixed by hand", remaining)
+	}
+
+	fmt.Println("The project files are consistent with the tracked resources.")
+	return nil
+}
+
+// verify checks that the types of the resource exist, that its API version is registered and its controller
+// set up in main.go, and that its CRD is listed in the kustomization file of the CRDs
+func (s *verifyScaffolder) verify(res *resource.Resource) ([]drift, error) {
+	fs := s.config.Fs()
+	kind := strings.ToLower(res.Kind)
+
+	apiDir := filepath.Join("api", res.Version)
+	controllersDir := "controllers"
+	if s.config.MultiGroup {
+		apiDir = filepath.Join("apis", res.Group, res.Version)
+		controllersDir = filepath.Join("controllers", res.Group)
+	}
+
+	drifts := make([]drift, 0)
+
+	typesPath := filepath.Join(apiDir, fmt.Sprintf("%s_types.go", kind))
+	exists, err := afero.Exists(fs, typesPath)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		drifts = append(drifts, drift{
+			resource: res,
+			problem:  fmt.Sprintf("%s does not exist, create the API again or remove it from %s", typesPath, s.config.Path()),
+		})
+	}
+
+	mainOptions := &scaffoldv2.MainUpdateOptions{Config: &s.config.Config, Resource: res, Fs: fs}
+	registered, setUp, err := (&scaffoldv2.Main{}).IsWired(mainOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error reading main.go: %v", err)
+	}
+	if !registered {
+		drifts = append(drifts, drift{
+			resource: res,
+			problem:  "the API version is not registered in the scheme of the manager in main.go",
+			fix:      func() error { return s.register(res) },
+		})
+	}
+
+	// Controllers are optional, but the existing ones have to be set up with the manager
+	controllerPath := filepath.Join(controllersDir, fmt.Sprintf("%s_controller.go", kind))
+	controller, err := afero.ReadFile(fs, controllerPath)
+	if err == nil && !setUp {
+		// The reconcilers recording events are given a recorder by the manager
+		res.Events = strings.Contains(string(controller), "record.EventRecorder")
+		drifts = append(drifts, drift{
+			resource: res,
+			problem:  fmt.Sprintf("the controller in %s is not set up with the manager in main.go", controllerPath),
+			fix: func() error {
+				return (&scaffoldv2.Main{}).Update(&scaffoldv2.MainUpdateOptions{
+					Config:         &s.config.Config,
+					Resource:       res,
+					WireController: true,
+					Fs:             fs,
+				})
+			},
+		})
+	}
+
+	kustomizationFile := &crdv2.Kustomization{Input: input.Input{Domain: s.config.Domain}, Resource: res}
+	listed, err := kustomizationFile.HasResource(fs)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the kustomization file of the CRDs: %v", err)
+	}
+	if !listed {
+		drifts = append(drifts, drift{
+			resource: res,
+			problem:  fmt.Sprintf("the CRD is not listed in %s", kustomizationFile.Path),
+			fix:      func() error { return kustomizationFile.Update(fs) },
+		})
+	}
+
+	return drifts, nil
+}
+
+// register registers the API version of the resource in the scheme of the manager, through the apis package
+// in multigroup projects
+func (s *verifyScaffolder) register(res *resource.Resource) error {
+	resPkg, _ := util.GetResourceInfo(res, s.config.Repo, s.config.Domain, s.config.MultiGroup)
+	if s.config.MultiGroup && resPkg == path.Join(s.config.Repo, "apis", res.Group) {
+		universe, err := model.NewUniverse(model.WithConfig(&s.config.Config))
+		if err != nil {
+			return err
+		}
+		if err := (&Scaffold{Fs: s.config.Fs()}).Execute(
+			universe,
+			input.Options{ProjectPath: s.config.Path()},
+			&scaffoldv2.APIs{},
+			&scaffoldv2.AddToScheme{Resource: res},
+		); err != nil {
+			return err
+		}
+	}
+
+	return (&scaffoldv2.Main{}).Update(&scaffoldv2.MainUpdateOptions{
+		Config:       &s.config.Config,
+		Resource:     res,
+		WireResource: true,
+		Fs:           s.config.Fs(),
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+)
+
+var _ = Describe("VerifyScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		c = scaffoldtest.NewConfig()
+		fs = c.Fs()
+		c.Resources = []modelconfig.GVK{{Group: "crew", Version: "v1", Kind: "Captain"}}
+
+		files := map[string]string{
+			"api/v1/captain_types.go":           "package v1\n",
+			"controllers/captain_controller.go": "package controllers\n",
+			"main.go": `package main
+
+import (
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	// +kubebuilder:scaffold:imports
+)
+
+func init() {
+	// +kubebuilder:scaffold:scheme
+}
+
+func main() {
+	// The variables of the reconciler setup are declared so that they aren't resolved as packages
+	var mgr, setupLog, err interface{}
+	ctrl.SetLogger(nil)
+	// +kubebuilder:scaffold:builder
+	os.Exit(0)
+}
+`,
+			"config/crd/kustomization.yaml": "resources:\n# +kubebuilder:scaffold:crdkustomizeresource\n",
+		}
+		for path, content := range files {
+			Expect(afero.WriteFile(fs, path, []byte(content), 0600)).To(Succeed())
+		}
+	})
+
+	It("should report the resources that are not wired without changing the files", func() {
+		Expect(scaffold.NewVerifyScaffolder(c, false).Scaffold()).NotTo(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).NotTo(ContainSubstring("crewv1"))
+		Expect(scaffoldtest.ReadFile(fs, "config/crd/kustomization.yaml")).NotTo(ContainSubstring("crew.example.com_captains"))
+	})
+
+	It("should wire the resources when fixing the project", func() {
+		Expect(scaffold.NewVerifyScaffolder(c, true).Scaffold()).To(Succeed())
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(ContainSubstring("_ = crewv1.AddToScheme(scheme)"))
+		Expect(scaffoldtest.ReadFile(fs, "main.go")).To(ContainSubstring("&controllers.CaptainReconciler{"))
+		Expect(scaffoldtest.ReadFile(fs, "config/crd/kustomization.yaml")).To(ContainSubstring("- bases/crew.example.com_captains.yaml"))
+
+		Expect(scaffold.NewVerifyScaffolder(c, false).Scaffold()).To(Succeed())
+	})
+
+	It("should not fix the resources whose types are missing", func() {
+		Expect(fs.Remove("api/v1/captain_types.go")).To(Succeed())
+
+		Expect(scaffold.NewVerifyScaffolder(c, true).Scaffold()).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultKubernetesVersion is the Kubernetes minor version of the dependencies of the projects that don't
+// record one
+const DefaultKubernetesVersion = "1.17"
+
+// DependencyVersions are versions of the dependencies of a project that are consistent with each other
+type DependencyVersions struct {
+	// ControllerRuntime is the version of sigs.k8s.io/controller-runtime
+	ControllerRuntime string
+
+	// ControllerTools is the version of controller-gen installed by the Makefile
+	ControllerTools string
+
+	// Kubernetes is the version of the Kubernetes libraries required by controller-runtime, i.e. k8s.io/client-go,
+	// k8s.io/apimachinery, k8s.io/apiserver and k8s.io/code-generator
+	Kubernetes string
+}
+
+// kubernetesVersions is the compatibility matrix of the dependencies for each Kubernetes minor version, the
+// controller-runtime releases must keep the API of the scaffolded code
+var kubernetesVersions = map[string]DependencyVersions{
+	"1.17": {
+		ControllerRuntime: ControllerRuntimeVersion,
+		ControllerTools:   ControllerToolsVersion,
+		Kubernetes:        APIServerVersion,
+	},
+	"1.18": {
+		ControllerRuntime: "v0.6.0",
+		ControllerTools:   "v0.3.0",
+		Kubernetes:        "v0.18.2",
+	},
+}
+
+// KubernetesVersions returns the Kubernetes minor versions of the compatibility matrix, in ascending order
+func KubernetesVersions() []string {
+	versions := make([]string, 0, len(kubernetesVersions))
+	for version := range kubernetesVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// KubernetesDependencyVersions returns the versions of the dependencies for the Kubernetes minor version, e.g.
+// 1.18, the ones of DefaultKubernetesVersion if empty
+func KubernetesDependencyVersions(kubernetesVersion string) (DependencyVersions, error) {
+	if kubernetesVersion == "" {
+		kubernetesVersion = DefaultKubernetesVersion
+	}
+	versions, found := kubernetesVersions[kubernetesVersion]
+	if !found {
+		return DependencyVersions{}, fmt.Errorf("unsupported Kubernetes version %q, must be one of %s",
+			kubernetesVersion, strings.Join(KubernetesVersions(), ", "))
+	}
+	return versions, nil
+}
@@ -18,28 +18,56 @@ package scaffold
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
-	"strings"
 
+	"github.com/spf13/afero"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	managerv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/manager"
 	webhookv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/webhook"
 	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
 	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+	webhookcav2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhookca"
 )
 
 type webhookScaffolder struct {
 	config   *config.Config
 	resource *resource.Resource
+	// fs is the filesystem where the files are written
+	fs afero.Fs
 	// v1
 	server      string
 	webhookType string
 	operations  []string
 	// v2
 	defaulting, validation, conversion bool
+	// hubVersion is the version that the rest of versions of the Kind convert to and from
+	hubVersion string
+	// certProvider is how the certificate of the webhook server is provided, empty to keep the project one
+	certProvider string
+	// admission are the failure policy, side effects and timeout of the defaulting and validating webhooks
+	admission webhookv2.AdmissionOptions
+	// plugins transform the scaffolded files, e.g. the ones of the plugin chain of the project
+	plugins []Plugin
+	// projectConfig records the certificate provider
+	projectConfig *internalconfig.Config
+	// templatesDir is a directory with templates that replace the built-in ones
+	templatesDir string
+	// reporter is notified of the files that are written
+	reporter Reporter
+	// out is where the messages for the user are printed
+	out io.Writer
+	// insertions are the code fragments wiring the webhooks in the shared files, e.g. main.go, which are shared
+	// with the other scaffolders of a batch
+	insertions *scaffoldv2.Insertions
 }
 
 func NewV1WebhookScaffolder(
@@ -55,42 +83,100 @@ func NewV1WebhookScaffolder(
 		server:      server,
 		webhookType: webhookType,
 		operations:  operations,
+		out:         os.Stdout,
 	}
 }
 
 func NewV2WebhookScaffolder(
-	config *config.Config,
+	config *internalconfig.Config,
 	resource *resource.Resource,
 	defaulting bool,
 	validation bool,
 	conversion bool,
+	hubVersion string,
+	certProvider string,
+	admission webhookv2.AdmissionOptions,
+	plugins []Plugin,
+	templatesDir string,
+	reporter Reporter,
+	out io.Writer,
 ) Scaffolder {
+	if reporter == nil {
+		reporter = &TextReporter{}
+	}
+	if out == nil {
+		out = os.Stdout
+	}
 	return &webhookScaffolder{
-		config:     config,
-		resource:   resource,
-		defaulting: defaulting,
-		validation: validation,
-		conversion: conversion,
+		config:        &config.Config,
+		fs:            config.Fs(),
+		resource:      resource,
+		defaulting:    defaulting,
+		validation:    validation,
+		conversion:    conversion,
+		hubVersion:    hubVersion,
+		certProvider:  certProvider,
+		admission:     admission,
+		plugins:       plugins,
+		projectConfig: config,
+		templatesDir:  templatesDir,
+		reporter:      reporter,
+		out:           out,
+	}
+}
+
+// ValidateCertProvider checks that the certificate of the webhook server can be provided by certProvider in
+// the project, which can't change once it has been recorded
+func ValidateCertProvider(c *config.Config, certProvider string) error {
+	switch certProvider {
+	case "":
+		return nil
+	case config.CertProviderCertManager, config.CertProviderManual, config.CertProviderWebhookCA:
+	default:
+		return fmt.Errorf("unknown certificate provider %q, must be one of %q, %q or %q", certProvider,
+			config.CertProviderCertManager, config.CertProviderManual, config.CertProviderWebhookCA)
+	}
+
+	if c.IsHelm() && certProvider != config.CertProviderCertManager {
+		return fmt.Errorf("the Helm chart only supports the %s certificate provider", config.CertProviderCertManager)
 	}
+
+	if c.CertProvider != "" && c.CertProvider != certProvider {
+		return fmt.Errorf("the webhooks of the project already use the %s certificate provider", c.CertProvider)
+	}
+
+	return nil
 }
 
 func (s *webhookScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	fmt.Fprintln(s.out, "Writing scaffold for you to edit...")
 
+	// The code fragments are inserted once the webhooks were scaffolded, or by the batch they belong to
+	batched := s.insertions != nil
+	if !batched {
+		s.insertions = &scaffoldv2.Insertions{}
+	}
+
+	var err error
 	switch {
 	case s.config.IsV1():
-		return s.scaffoldV1()
+		err = s.scaffoldV1()
 	case s.config.IsV2():
-		return s.scaffoldV2()
+		err = s.scaffoldV2()
 	default:
-		return fmt.Errorf("unknown project version %v", s.config.Version)
+		err = fmt.Errorf("unknown project version %v", s.config.Version)
+	}
+	if err != nil || batched {
+		return err
 	}
+
+	return applyInsertions(s.fs, s.insertions, s.reporter)
 }
 
 func (s *webhookScaffolder) scaffoldV1() error {
 	universe, err := model.NewUniverse(
 		model.WithConfig(s.config),
-		// TODO(adirio): missing model.WithBoilerplate[From], needs boilerplate or path
+		model.WithBoilerplateFromFs(s.fs, boilerplatePath),
 		model.WithResource(s.resource, s.config),
 	)
 	if err != nil {
@@ -99,7 +185,7 @@ func (s *webhookScaffolder) scaffoldV1() error {
 
 	webhookConfig := webhookv1.Config{Server: s.server, Type: s.webhookType, Operations: s.operations}
 
-	return (&Scaffold{}).Execute(
+	return (&Scaffold{Out: s.out}).Execute(
 		universe,
 		input.Options{},
 		&managerv1.Webhook{},
@@ -113,22 +199,38 @@ func (s *webhookScaffolder) scaffoldV1() error {
 }
 
 func (s *webhookScaffolder) scaffoldV2() error {
-	if s.config.MultiGroup {
-		fmt.Println(filepath.Join("apis", s.resource.Group, s.resource.Version,
-			fmt.Sprintf("%s_webhook.go", strings.ToLower(s.resource.Kind))))
-	} else {
-		fmt.Println(filepath.Join("api", s.resource.Version,
-			fmt.Sprintf("%s_webhook.go", strings.ToLower(s.resource.Kind))))
+	// The Go types of the Kubernetes built-in types are not in the project
+	if !s.config.HasResource(s.resource) && util.IsCoreGroup(s.resource.Group) {
+		return s.scaffoldCoreV2()
 	}
 
+	var conversionFile *webhookv2.Conversion
 	if s.conversion {
-		fmt.Println(`Webhook server has been set up for you.
-You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
+		// Default the hub to the first version of the Kind that was created
+		hubVersion := s.hubVersion
+		if hubVersion == "" {
+			if versions := s.config.KindVersions(s.resource.Group, s.resource.Kind); len(versions) != 0 {
+				hubVersion = versions[0]
+			} else {
+				hubVersion = s.resource.Version
+			}
+		}
+		conversionFile = &webhookv2.Conversion{Resource: s.resource, HubVersion: hubVersion}
+
+		if conversionFile.IsHub() {
+			fmt.Fprintf(s.out, `Webhook server has been set up for you.
+%s is the conversion hub, every other version of %s needs to implement conversion.Convertible.
+`, s.resource.Version, s.resource.Kind)
+		} else {
+			fmt.Fprintf(s.out, `Webhook server has been set up for you.
+You need to implement the conversion from and to the hub version (%s) in the generated conversion.Convertible.
+`, hubVersion)
+		}
 	}
 
 	universe, err := model.NewUniverse(
 		model.WithConfig(s.config),
-		// TODO(adirio): missing model.WithBoilerplate[From], needs boilerplate or path
+		model.WithBoilerplateFromFs(s.fs, boilerplatePath),
 		model.WithResource(s.resource, s.config),
 	)
 	if err != nil {
@@ -136,29 +238,260 @@ You need to implement the conversion.Hub and conversion.Convertible interfaces f
 	}
 
 	webhookScaffolder := &webhookv2.Webhook{
-		Resource:   s.resource,
-		Defaulting: s.defaulting,
-		Validating: s.validation,
+		Resource:      s.resource,
+		Defaulting:    s.defaulting,
+		Validating:    s.validation,
+		FailurePolicy: s.admission.FailurePolicy,
+	}
+	files := []input.File{webhookScaffolder}
+	// The defaulting and validating webhooks are tested against envtest
+	var suiteTestFile *webhookv2.SuiteTest
+	if (s.defaulting || s.validation) && !s.config.SkipTests {
+		suiteTestFile = &webhookv2.SuiteTest{Resource: s.resource}
+		files = append(files,
+			suiteTestFile,
+			&webhookv2.WebhookTest{Resource: s.resource, Defaulting: s.defaulting, Validating: s.validation},
+		)
+	}
+	admissionPatch := s.admissionPatch()
+	if admissionPatch.NeedsPatch() {
+		files = append(files, admissionPatch)
+	}
+	if conversionFile != nil {
+		// The patches are scaffolded with the API, but older projects may be missing them
+		files = append(files,
+			conversionFile,
+			&crdv2.EnableWebhookPatch{Resource: s.resource, CRDVersion: s.config.CRDVersion},
+			&crdv2.EnableCAInjectionPatch{Resource: s.resource, CRDVersion: s.config.CRDVersion},
+		)
 	}
-	if err := (&Scaffold{}).Execute(
+	if err := (&Scaffold{
+		Plugins:      s.plugins,
+		Fs:           s.fs,
+		TemplatesDir: s.templatesDir,
+		Reporter:     s.reporter,
+		Out:          s.out,
+	}).Execute(
 		universe,
 		input.Options{},
-		webhookScaffolder,
+		files...,
 	); err != nil {
 		return err
 	}
 
-	if err := (&scaffoldv2.Main{}).Update(
+	mainFragments, err := (&scaffoldv2.Main{}).Fragments(
 		&scaffoldv2.MainUpdateOptions{
 			Config:         s.config,
 			WireResource:   false,
 			WireController: false,
 			WireWebhook:    true,
 			Resource:       s.resource,
+			Fs:             s.fs,
 		},
+	)
+	if err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+	s.insertions.Add("main.go", mainFragments)
+
+	if suiteTestFile != nil {
+		s.insertions.Add(suiteTestFile.Path, suiteTestFile.Fragments())
+	}
+
+	if err := s.enableWebhook(); err != nil {
+		return err
+	}
+
+	if err := s.enableAdmissionPatch(admissionPatch); err != nil {
+		return err
+	}
+
+	// Record the webhooks of the resource, the ones of the Kubernetes built-in types are not tracked
+	if s.config.UpdateResource(s.resource, config.ResourceState{Webhooks: config.Webhooks{
+		Defaulting: s.defaulting,
+		Validation: s.validation,
+		Conversion: s.conversion,
+	}}) {
+		if err := s.projectConfig.Save(); err != nil {
+			return fmt.Errorf("error updating project file with webhook information: %v", err)
+		}
+		s.reporter.ReportFile(internalconfig.DefaultPath, FileUpdated)
+	}
+
+	// The CRD needs to point to the conversion webhook
+	if s.conversion {
+		kustomizationFile := &crdv2.Kustomization{
+			Input:    input.Input{Domain: s.config.Domain},
+			Resource: s.resource,
+		}
+		if err := kustomizationFile.EnableConversion(s.fs); err != nil {
+			fmt.Fprintf(s.out, "Warning: %v\nUncomment the [WEBHOOK] and [CERTMANAGER] patches of %s in %s.\n",
+				err, s.resource.Resource, filepath.Join("config", "crd", "kustomization.yaml"))
+		} else {
+			s.reporter.ReportFile(kustomizationFile.Path, FileUpdated)
+		}
+	}
+
+	switch s.config.WebhookCertProvider() {
+	case config.CertProviderManual:
+		fmt.Fprintf(s.out, "Create the %s Secret with the tls.crt and tls.key of the webhook server certificate in the "+
+			"namespace of the manager, and set its CA as the caBundle of the webhook configurations.\n", webhookCertSecret)
+	case config.CertProviderWebhookCA:
+		if s.conversion {
+			fmt.Fprintln(s.out, "The webhookca component doesn't inject the CA in the CRDs, "+
+				"set the caBundle of the conversion webhook of the CRD to the one of the webhook-certgen Job.")
+		}
+	}
+	fmt.Fprintln(s.out, "Run the manager with ENABLE_WEBHOOKS=false to disable the webhooks when running it locally.")
+	if s.config.IsHelm() {
+		fmt.Fprintln(s.out, "Run `make chart` to update the Helm chart and install it with --set webhook.enabled=true.")
+	}
+
+	return nil
+}
+
+// scaffoldCoreV2 scaffolds the webhooks of a Kubernetes built-in type under the webhooks directory
+func (s *webhookScaffolder) scaffoldCoreV2() error {
+	if s.conversion {
+		return fmt.Errorf("conversion webhooks can only be created for the types of the project, %s/%s is a "+
+			"Kubernetes built-in type", s.resource.Group, s.resource.Kind)
+	}
+
+	universe, err := model.NewUniverse(
+		model.WithConfig(s.config),
+		model.WithBoilerplateFromFs(s.fs, boilerplatePath),
+		model.WithResource(s.resource, s.config),
+	)
+	if err != nil {
+		return err
+	}
+
+	files := []input.File{&webhookv2.CoreWebhook{
+		Resource:      s.resource,
+		Defaulting:    s.defaulting,
+		Validating:    s.validation,
+		FailurePolicy: s.admission.FailurePolicy,
+	}}
+	admissionPatch := s.admissionPatch()
+	if admissionPatch.NeedsPatch() {
+		files = append(files, admissionPatch)
+	}
+	if err := (&Scaffold{
+		Plugins:      s.plugins,
+		Fs:           s.fs,
+		TemplatesDir: s.templatesDir,
+		Reporter:     s.reporter,
+		Out:          s.out,
+	}).Execute(
+		universe,
+		input.Options{},
+		files...,
 	); err != nil {
+		return err
+	}
+
+	mainFragments, err := (&scaffoldv2.Main{}).Fragments(
+		&scaffoldv2.MainUpdateOptions{
+			Config:          s.config,
+			WireCoreWebhook: true,
+			Resource:        s.resource,
+			Fs:              s.fs,
+		},
+	)
+	if err != nil {
 		return fmt.Errorf("error updating main.go: %v", err)
 	}
+	s.insertions.Add("main.go", mainFragments)
+
+	if err := s.enableWebhook(); err != nil {
+		return err
+	}
+
+	if err := s.enableAdmissionPatch(admissionPatch); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Implement the webhooks of %s in %s and run `make manifests` to generate the webhook "+
+		"configurations.\n", s.resource.Kind, webhookv2.CoreWebhookPath(s.resource))
+	fmt.Fprintln(s.out, "Run the manager with ENABLE_WEBHOOKS=false to disable the webhooks when running it locally.")
+
+	return nil
+}
+
+// webhookCertSecret is the Secret of the certificate of the webhook server mounted by the manager
+const webhookCertSecret = "webhook-server-cert"
+
+// enableWebhook enables the webhook in the default overlay along with the provider of its certificate,
+// which is recorded in the project configuration for the next webhooks
+func (s *webhookScaffolder) enableWebhook() error {
+	if s.certProvider != "" && s.certProvider != s.config.CertProvider {
+		// cert-manager is the default, projects that use it keep an empty provider
+		if s.certProvider != config.CertProviderCertManager || s.config.CertProvider != "" {
+			s.config.CertProvider = s.certProvider
+			if err := s.projectConfig.Save(); err != nil {
+				return err
+			}
+		}
+	}
+
+	certProvider := s.config.WebhookCertProvider()
+	if certProvider == config.CertProviderWebhookCA {
+		if err := scaffoldComponent(s.fs, s.config, scaffoldv2.ComponentWebhookCA,
+			&webhookcav2.CertGenJob{},
+			&webhookcav2.CertGenRBAC{},
+			&webhookcav2.ManagerCertPatch{},
+		); err != nil {
+			return err
+		}
+	}
+
+	kustomizeFile := &scaffoldv2.Kustomize{}
+	if err := kustomizeFile.EnableWebhook(s.fs, certProvider); err != nil {
+		sections := "[WEBHOOK] and [CERTMANAGER] sections"
+		if certProvider != config.CertProviderCertManager {
+			sections = "[WEBHOOK] sections"
+		}
+		fmt.Fprintf(s.out, "Warning: %v\nUncomment the %s in %s to deploy the webhook.\n",
+			err, sections, filepath.Join("config", "default", "kustomization.yaml"))
+	} else {
+		s.reporter.ReportFile(kustomizeFile.Path, FileUpdated)
+	}
+
+	return nil
+}
+
+// admissionPatch returns the patch setting the side effects and the timeout of the defaulting and validating
+// webhooks, which only has to be scaffolded if any of them is set
+func (s *webhookScaffolder) admissionPatch() *webhookv2.AdmissionPatch {
+	return &webhookv2.AdmissionPatch{
+		Resource:   s.resource,
+		Options:    s.admission,
+		Defaulting: s.defaulting,
+		Validating: s.validation,
+	}
+}
+
+// enableAdmissionPatch adds the patch of the webhooks to the kustomization of the webhook configurations
+func (s *webhookScaffolder) enableAdmissionPatch(patch *webhookv2.AdmissionPatch) error {
+	if !patch.NeedsPatch() {
+		return nil
+	}
+
+	kustomizationPath := filepath.Join("config", "webhook", "kustomization.yaml")
+	updated, err := patch.Update(s.fs)
+	if err != nil {
+		return fmt.Errorf("error updating %s: %v", kustomizationPath, err)
+	}
+	if !updated {
+		fmt.Fprintf(s.out, "Warning: %s has no webhook patches section.\nAdd %s to its patchesStrategicMerge to set the "+
+			"side effects and the timeout of the webhooks.\n", kustomizationPath, patch.Path)
+	} else {
+		s.reporter.ReportFile(kustomizationPath, FileUpdated)
+	}
+	if s.config.IsHelm() {
+		fmt.Fprintf(s.out, "The Helm chart uses the webhook configurations generated from the markers, the side effects "+
+			"and the timeout of the webhooks of %s are only set in the kustomize manifests.\n", s.resource.Kind)
+	}
 
 	return nil
 }
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/scaffoldtest"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var _ = Describe("WebhookScaffolder", func() {
+	var (
+		fs afero.Fs
+		c  *config.Config
+	)
+
+	BeforeEach(func() {
+		c = scaffoldtest.NewConfig()
+		fs = c.Fs()
+		Expect(c.Save()).To(Succeed())
+		Expect(afero.WriteFile(fs, filepath.Join("hack", "boilerplate.go.txt"), []byte("/* Boilerplate */"), 0600)).
+			To(Succeed())
+
+		Expect(afero.WriteFile(fs, "main.go", []byte(`package main
+
+import (
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	// +kubebuilder:scaffold:imports
+)
+
+func main() {
+	// The variables of the webhook setup are declared so that they aren't resolved as packages
+	var mgr, setupLog, err interface{}
+	ctrl.SetLogger(nil)
+	// +kubebuilder:scaffold:builder
+	os.Exit(0)
+}
+`), 0600)).To(Succeed())
+	})
+
+	It("should scaffold and register the webhooks of a Kubernetes built-in type", func() {
+		pod := &resource.Resource{Group: "core", Version: "v1", Kind: "Pod", Resource: "pods"}
+		Expect(scaffold.NewV2WebhookScaffolder(c, pod, true, false, false, "", "", webhookv2.AdmissionOptions{}, nil, "",
+			nil, nil).Scaffold()).To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("webhooks", "core", "v1", "pod_webhook.go"))
+		Expect(content).To(ContainSubstring("type podWebhook struct"))
+		Expect(content).To(ContainSubstring("path=/mutate--v1-pod,mutating=true"))
+		Expect(content).NotTo(ContainSubstring("handleValidate"))
+
+		content = scaffoldtest.ReadFile(fs, "main.go")
+		Expect(content).To(ContainSubstring(`webhookcorev1 "example.com/project/webhooks/core/v1"`))
+		Expect(content).To(ContainSubstring("webhookcorev1.SetupPodWebhookWithManager(mgr)"))
+	})
+
+	It("should set the failure policy, side effects and timeout of the webhooks", func() {
+		webhookKustomizationPath := filepath.Join("config", "webhook", "kustomization.yaml")
+		Expect(afero.WriteFile(fs, webhookKustomizationPath, []byte(webhookv2.KustomizeWebhookTemplate), 0600)).
+			To(Succeed())
+
+		pod := &resource.Resource{Group: "core", Version: "v1", Kind: "Pod", Resource: "pods"}
+		admission := webhookv2.AdmissionOptions{FailurePolicy: "fail", SideEffects: "None", TimeoutSeconds: 5}
+		Expect(scaffold.NewV2WebhookScaffolder(c, pod, true, false, false, "", "", admission, nil, "", nil, nil).Scaffold()).
+			To(Succeed())
+
+		content := scaffoldtest.ReadFile(fs, filepath.Join("webhooks", "core", "v1", "pod_webhook.go"))
+		Expect(content).To(ContainSubstring("mutating=true,failurePolicy=fail,"))
+		Expect(content).NotTo(ContainSubstring("TODO(user): change it to failurePolicy=fail"))
+
+		content = scaffoldtest.ReadFile(fs, filepath.Join("config", "webhook", "patches", "options_in_pods.yaml"))
+		Expect(content).To(ContainSubstring(
+			"- name: mpod.kb.io\n  sideEffects: None\n  timeoutSeconds: 5\n"))
+		Expect(content).NotTo(ContainSubstring("ValidatingWebhookConfiguration"))
+
+		content = scaffoldtest.ReadFile(fs, webhookKustomizationPath)
+		Expect(content).To(ContainSubstring("- patches/options_in_pods.yaml\n"))
+	})
+
+	It("should not scaffold conversion webhooks for Kubernetes built-in types", func() {
+		pod := &resource.Resource{Group: "core", Version: "v1", Kind: "Pod", Resource: "pods"}
+		Expect(scaffold.NewV2WebhookScaffolder(c, pod, false, false, true, "", "", webhookv2.AdmissionOptions{}, nil, "",
+			nil, nil).Scaffold()).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("ValidateCertProvider", func() {
+	It("should accept the known providers in projects without webhooks", func() {
+		c := &modelconfig.Config{Version: modelconfig.Version2}
+		for _, provider := range []string{"", "cert-manager", "manual", "webhookca"} {
+			Expect(scaffold.ValidateCertProvider(c, provider)).To(Succeed())
+		}
+	})
+
+	It("should reject unknown providers", func() {
+		Expect(scaffold.ValidateCertProvider(&modelconfig.Config{}, "vault")).NotTo(Succeed())
+	})
+
+	It("should reject a provider other than the one of the existing webhooks", func() {
+		c := &modelconfig.Config{CertProvider: modelconfig.CertProviderWebhookCA}
+		Expect(scaffold.ValidateCertProvider(c, "webhookca")).To(Succeed())
+		Expect(scaffold.ValidateCertProvider(c, "cert-manager")).NotTo(Succeed())
+	})
+
+	It("should only accept cert-manager in projects deployed with Helm", func() {
+		c := &modelconfig.Config{Deploy: modelconfig.DeployHelm}
+		Expect(scaffold.ValidateCertProvider(c, "cert-manager")).To(Succeed())
+		Expect(scaffold.ValidateCertProvider(c, "manual")).NotTo(Succeed())
+	})
+})
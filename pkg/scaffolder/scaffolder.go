@@ -0,0 +1,317 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scaffolder exposes the kubebuilder scaffolders as a Go API, so that other tools can embed them instead
+// of shelling out to the kubebuilder binary.
+//
+// The project is scaffolded to the filesystem set in the options, e.g. afero.NewMemMapFs() keeps it in memory and
+// afero.NewBasePathFs() writes it to another directory. Only version 2 projects are supported. Unlike the
+// kubebuilder commands, the scaffolders don't prompt for missing values and don't run go mod tidy or make, which
+// is left to the caller.
+//
+// The failures to scaffold are reported with the codes of the sigs.k8s.io/kubebuilder/pkg/scaffold/errors package,
+// e.g. errors.Is(err, scaffolderrors.ErrFileExists) when a file already exists and Force is not set.
+package scaffolder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+const (
+	// LicenseApache2 is the Apache 2.0 license boilerplate
+	LicenseApache2 = "apache2"
+	// LicenseNone is an empty boilerplate
+	LicenseNone = "none"
+	// LicenseSPDXPrefix prefixes the SPDX license expression of a boilerplate with the SPDX-License-Identifier
+	// line only, e.g. spdx:MIT
+	LicenseSPDXPrefix = project.LicenseSPDXPrefix
+
+	defaultDomain = "my.domain"
+)
+
+// InitOptions configure the project scaffolded by NewInitScaffolder
+type InitOptions struct {
+	// Fs is the filesystem where the project is scaffolded, defaults to the OS filesystem
+	Fs afero.Fs
+
+	// Repo is the Go module of the project, e.g. github.com/example/project
+	Repo string
+	// Domain is the domain of the API groups, defaults to my.domain
+	Domain string
+	// NamespaceScoped restricts the manager to the namespaces set with WATCH_NAMESPACE and grants it Roles
+	// instead of ClusterRoles
+	NamespaceScoped bool
+	// CRDVersion is the API version of the generated CustomResourceDefinitions, defaults to v1
+	CRDVersion string
+
+	// License is the license of the boilerplate, LicenseApache2 (default), LicenseNone, LicenseSPDXPrefix followed
+	// by an SPDX license expression, or the path in Fs of a file with a custom header
+	License string
+	// Owner is the copyright owner of the boilerplate
+	Owner string
+
+	// Plugins transform the scaffolded files, e.g. the init plugins of a scaffold.PluginChain
+	Plugins []scaffold.Plugin
+	// TemplatesDir is a directory of Fs with templates that replace the built-in ones
+	TemplatesDir string
+}
+
+// NewInitScaffolder returns a Scaffolder that initializes a project in the filesystem of the options
+func NewInitScaffolder(options InitOptions) (scaffold.Scaffolder, error) {
+	fs := options.Fs
+	if fs == nil {
+		fs = afero.NewOsFs()
+	}
+
+	if options.Repo == "" {
+		return nil, errors.New("repo is required")
+	}
+	if options.Domain == "" {
+		options.Domain = defaultDomain
+	}
+	if errs := resource.IsDNS1123Subdomain(options.Domain); errs != nil {
+		return nil, fmt.Errorf("invalid domain %q: %v", options.Domain, errs)
+	}
+	if options.CRDVersion == "" {
+		options.CRDVersion = modelconfig.CRDVersionV1
+	}
+	switch options.CRDVersion {
+	case modelconfig.CRDVersionV1, modelconfig.CRDVersionV1beta1:
+	default:
+		return nil, fmt.Errorf("unknown CRD version %q, must be one of %q or %q",
+			options.CRDVersion, modelconfig.CRDVersionV1, modelconfig.CRDVersionV1beta1)
+	}
+	if options.License == "" {
+		options.License = LicenseApache2
+	}
+	if err := project.ValidateLicense(options.License); err != nil {
+		return nil, err
+	}
+
+	if _, err := fs.Stat(config.DefaultPath); err == nil {
+		return nil, errors.New("already initialized")
+	}
+
+	c := config.New(config.DefaultPath)
+	c.SetFs(fs)
+	c.Repo = options.Repo
+	c.Domain = options.Domain
+	c.NamespaceScoped = options.NamespaceScoped
+	c.CRDVersion = options.CRDVersion
+
+	return scaffold.NewInitScaffolder(c, options.License, options.Owner, options.Plugins, options.TemplatesDir), nil
+}
+
+// APIOptions configure the API scaffolded by NewAPIScaffolder
+type APIOptions struct {
+	// Fs is the filesystem of the project, defaults to the OS filesystem
+	Fs afero.Fs
+
+	// Resource is the API to scaffold, its plural defaults to the one of the existing versions of the Kind
+	Resource *resource.Resource
+	// DoResource scaffolds the types of the resource
+	DoResource bool
+	// DoController scaffolds the controller of the resource
+	DoController bool
+	// Force three-way merges the files of an existing resource with the new scaffold instead of failing
+	Force bool
+
+	// Plugins transform the scaffolded files, e.g. the ones of a scaffold.Pattern
+	Plugins []scaffold.Plugin
+	// TemplatesDir is a directory of Fs with templates that replace the built-in ones
+	TemplatesDir string
+	// Reporter is notified of the files that are created, updated or skipped, defaults to printing their paths
+	Reporter scaffold.Reporter
+	// Out is where the messages for the user are printed, defaults to the standard output
+	Out io.Writer
+}
+
+// NewAPIScaffolder returns a Scaffolder that creates an API in the project of the options
+func NewAPIScaffolder(options APIOptions) (scaffold.Scaffolder, error) {
+	c, err := loadConfig(options.Fs)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Resource == nil {
+		return nil, errors.New("resource is required")
+	}
+	r := options.Resource
+	r.Group = resource.NormalizeGroup(r.Group, c.Domain)
+	if err := c.SetGroupPackage(r); err != nil {
+		return nil, err
+	}
+	if err := defaultPlural(c, r); err != nil {
+		return nil, err
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if options.DoResource {
+		if !options.Force && c.HasResource(r) {
+			return nil, errors.New("API resource already exists")
+		}
+		if !c.MultiGroup {
+			for _, group := range c.ResourceGroups() {
+				if !strings.EqualFold(r.Group, group) {
+					return nil, errors.New("multiple groups are not allowed unless the multigroup layout is enabled")
+				}
+			}
+		}
+		if r.StorageVersion != "" && r.StorageVersion != r.Version &&
+			!c.HasResource(&resource.Resource{Group: r.Group, Version: r.StorageVersion, Kind: r.Kind}) {
+			return nil, fmt.Errorf("storage version %s of %s does not exist", r.StorageVersion, r.Kind)
+		}
+	}
+
+	return scaffold.NewAPIScaffolder(c, r, options.DoResource, options.DoController, options.Force,
+		options.Plugins, options.TemplatesDir, options.Reporter, options.Out), nil
+}
+
+// WebhookOptions configure the webhooks scaffolded by NewWebhookScaffolder
+type WebhookOptions struct {
+	// Fs is the filesystem of the project, defaults to the OS filesystem
+	Fs afero.Fs
+
+	// Resource is the API of the webhooks, its plural defaults to the one of the existing versions of the Kind
+	Resource *resource.Resource
+	// Defaulting scaffolds the defaulting webhook
+	Defaulting bool
+	// Validation scaffolds the validating webhook
+	Validation bool
+	// Conversion scaffolds the conversion webhook
+	Conversion bool
+	// HubVersion is the version that the rest of versions of the Kind convert to and from
+	HubVersion string
+	// CertProvider is how the certificate of the webhook server is provided, among cert-manager, manual and
+	// webhookca, defaults to the one recorded in the project
+	CertProvider string
+	// Admission are the failure policy, side effects and timeout of the defaulting and validating webhooks
+	Admission webhookv2.AdmissionOptions
+
+	// Plugins transform the scaffolded files, e.g. the webhook plugins of a scaffold.PluginChain
+	Plugins []scaffold.Plugin
+	// TemplatesDir is a directory of Fs with templates that replace the built-in ones
+	TemplatesDir string
+	// Reporter is notified of the files that are created, updated or skipped, defaults to printing their paths
+	Reporter scaffold.Reporter
+	// Out is where the messages for the user are printed, defaults to the standard output
+	Out io.Writer
+}
+
+// NewWebhookScaffolder returns a Scaffolder that creates the webhooks of an API in the project of the options
+func NewWebhookScaffolder(options WebhookOptions) (scaffold.Scaffolder, error) {
+	c, err := loadConfig(options.Fs)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Resource == nil {
+		return nil, errors.New("resource is required")
+	}
+	r := options.Resource
+	r.Group = resource.NormalizeGroup(r.Group, c.Domain)
+	if err := c.SetGroupPackage(r); err != nil {
+		return nil, err
+	}
+	if err := defaultPlural(c, r); err != nil {
+		return nil, err
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !options.Defaulting && !options.Validation && !options.Conversion {
+		return nil, errors.New("at least one of the defaulting, validating or conversion webhooks is required")
+	}
+	if options.HubVersion != "" {
+		if !options.Conversion {
+			return nil, errors.New("the hub version can only be set for the conversion webhook")
+		}
+		hub := &resource.Resource{Group: r.Group, Version: options.HubVersion, Kind: r.Kind}
+		if !c.HasResource(hub) {
+			return nil, fmt.Errorf("hub version %s of %s does not exist", options.HubVersion, r.Kind)
+		}
+	}
+
+	if err := scaffold.ValidateCertProvider(&c.Config, options.CertProvider); err != nil {
+		return nil, err
+	}
+
+	if err := options.Admission.Validate(); err != nil {
+		return nil, err
+	}
+	if options.Admission.IsSet() && !options.Defaulting && !options.Validation {
+		return nil, errors.New("the admission options can only be set for the defaulting and validating webhooks")
+	}
+
+	return scaffold.NewV2WebhookScaffolder(c, r, options.Defaulting, options.Validation, options.Conversion,
+		options.HubVersion, options.CertProvider, options.Admission, options.Plugins, options.TemplatesDir,
+		options.Reporter, options.Out), nil
+}
+
+// loadConfig loads the configuration of the version 2 project in fs
+func loadConfig(fs afero.Fs) (*config.Config, error) {
+	if fs == nil {
+		fs = afero.NewOsFs()
+	}
+
+	c, err := config.LoadFromFs(fs, config.DefaultPath)
+	if os.IsNotExist(err) {
+		return nil, errors.New("unable to find configuration file, project must be initialized")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsV2() {
+		return nil, fmt.Errorf("project version %s is not supported", c.Version)
+	}
+
+	return c, nil
+}
+
+// defaultPlural checks that every version of a Kind is served under the same plural, defaulting it to the
+// plural of the versions that were already created
+func defaultPlural(c *config.Config, r *resource.Resource) error {
+	if len(c.KindVersions(r.Group, r.Kind)) == 0 {
+		return nil
+	}
+	plural := c.KindPlural(r.Group, r.Kind)
+	if plural == "" {
+		plural = resource.DefaultPlural(r.Kind)
+	}
+	if r.Resource == "" {
+		r.Resource = plural
+	} else if r.Resource != plural {
+		return fmt.Errorf("plural must be the same in every version of %s (%s)", r.Kind, plural)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolder_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	scaffolderrors "sigs.k8s.io/kubebuilder/pkg/scaffold/errors"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffolder"
+)
+
+var _ = Describe("Scaffolder", func() {
+	var (
+		fs       afero.Fs
+		reporter *scaffold.JSONReporter
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		reporter = &scaffold.JSONReporter{}
+	})
+
+	readFile := func(path string) string {
+		content, err := afero.ReadFile(fs, path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	initProject := func() {
+		s, err := scaffolder.NewInitScaffolder(scaffolder.InitOptions{
+			Fs:     fs,
+			Repo:   "example.com/project",
+			Domain: "example.org",
+			Owner:  "The Authors",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+	}
+
+	newCaptain := func(version string) *resource.Resource {
+		return &resource.Resource{
+			Group:                      "crew",
+			Version:                    version,
+			Kind:                       "Captain",
+			Namespaced:                 true,
+			CreateExampleReconcileBody: true,
+			RBACMode:                   resource.RBACModeCluster,
+			TestStyle:                  resource.TestStyleEnvtest,
+		}
+	}
+
+	It("should scaffold a project in memory", func() {
+		initProject()
+		Expect(readFile("PROJECT")).To(ContainSubstring("repo: example.com/project"))
+		Expect(readFile("hack/boilerplate.go.txt")).To(ContainSubstring("The Authors"))
+		Expect(readFile("main.go")).To(ContainSubstring("The Authors"))
+
+		s, err := scaffolder.NewAPIScaffolder(scaffolder.APIOptions{
+			Fs:           fs,
+			Resource:     newCaptain("v1"),
+			DoResource:   true,
+			DoController: true,
+			Reporter:     reporter,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+		Expect(readFile("api/v1/captain_types.go")).To(ContainSubstring("type Captain struct"))
+		Expect(readFile("controllers/captain_controller.go")).To(ContainSubstring("type CaptainReconciler struct"))
+		Expect(readFile("main.go")).To(ContainSubstring(`crewv1 "example.com/project/api/v1"`))
+		Expect(readFile("PROJECT")).To(ContainSubstring("kind: Captain"))
+		Expect(reporter.Files).To(ContainElement(scaffold.FileReport{
+			Path: "api/v1/captain_types.go", Action: scaffold.FileCreated, Role: scaffold.RoleTypes,
+		}))
+
+		s, err = scaffolder.NewWebhookScaffolder(scaffolder.WebhookOptions{
+			Fs:         fs,
+			Resource:   newCaptain("v1"),
+			Defaulting: true,
+			Reporter:   reporter,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+		Expect(readFile("api/v1/captain_webhook.go")).To(ContainSubstring("func (r *Captain) Default()"))
+		Expect(readFile("config/default/kustomization.yaml")).To(ContainSubstring("- ../components/webhook\n"))
+
+		// Nothing is written to the working directory
+		_, err = os.Stat("PROJECT")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should fail to initialize a project without a repo", func() {
+		_, err := scaffolder.NewInitScaffolder(scaffolder.InitOptions{Fs: fs})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to initialize a project twice", func() {
+		initProject()
+		_, err := scaffolder.NewInitScaffolder(scaffolder.InitOptions{Fs: fs, Repo: "example.com/project"})
+		Expect(err).To(MatchError("already initialized"))
+	})
+
+	It("should fail to create an API before initializing the project", func() {
+		_, err := scaffolder.NewAPIScaffolder(scaffolder.APIOptions{Fs: fs, Resource: newCaptain("v1")})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to create an API that already exists", func() {
+		initProject()
+		options := scaffolder.APIOptions{Fs: fs, Resource: newCaptain("v1"), DoResource: true, Reporter: reporter}
+		s, err := scaffolder.NewAPIScaffolder(options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+
+		options.Resource = newCaptain("v1")
+		_, err = scaffolder.NewAPIScaffolder(options)
+		Expect(err).To(MatchError("API resource already exists"))
+	})
+
+	It("should normalize the group and import it as the recorded package", func() {
+		initProject()
+		captain := newCaptain("v1")
+		captain.Group, captain.GroupPackage = "Ship-Crew.example.org", "crew"
+		s, err := scaffolder.NewAPIScaffolder(scaffolder.APIOptions{
+			Fs: fs, Resource: captain, DoResource: true, DoController: true, Reporter: reporter,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+		Expect(readFile("api/v1/groupversion_info.go")).To(ContainSubstring(`Group: "ship-crew.example.org"`))
+		Expect(readFile("main.go")).To(ContainSubstring(`crewv1 "example.com/project/api/v1"`))
+		Expect(readFile("PROJECT")).To(ContainSubstring("groupPackage: crew"))
+
+		// The other versions of the group are imported as the same package
+		s, err = scaffolder.NewAPIScaffolder(scaffolder.APIOptions{
+			Fs: fs, Resource: &resource.Resource{Group: "ship-crew", Version: "v2", Kind: "Captain", Namespaced: true},
+			DoResource: true, Reporter: reporter,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+		Expect(readFile("main.go")).To(ContainSubstring(`crewv2 "example.com/project/api/v2"`))
+
+		_, err = scaffolder.NewAPIScaffolder(scaffolder.APIOptions{
+			Fs:       fs,
+			Resource: &resource.Resource{Group: "ship-crew", GroupPackage: "ship", Version: "v3", Kind: "Captain"},
+			Reporter: reporter,
+		})
+		Expect(err).To(MatchError("the packages of group ship-crew are already imported as crew"))
+	})
+
+	It("should fail with ErrFileExists to scaffold a file that already exists", func() {
+		initProject()
+		options := scaffolder.APIOptions{Fs: fs, Resource: newCaptain("v1"), DoController: true, Reporter: reporter}
+		s, err := scaffolder.NewAPIScaffolder(options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Scaffold()).To(Succeed())
+
+		options.Resource = newCaptain("v1")
+		s, err = scaffolder.NewAPIScaffolder(options)
+		Expect(err).NotTo(HaveOccurred())
+		err = s.Scaffold()
+		Expect(errors.Is(err, scaffolderrors.ErrFileExists)).To(BeTrue())
+		var scaffoldErr *scaffolderrors.Error
+		Expect(errors.As(err, &scaffoldErr)).To(BeTrue())
+		Expect(scaffoldErr.Path).To(Equal("controllers/captain_controller.go"))
+	})
+
+	It("should fail to create webhooks without any of them", func() {
+		initProject()
+		_, err := scaffolder.NewWebhookScaffolder(scaffolder.WebhookOptions{Fs: fs, Resource: newCaptain("v1")})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,9 +1,6 @@
 package addon
 
 import (
-	"path/filepath"
-	"strings"
-
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
@@ -18,7 +15,7 @@ func ReplaceController(u *model.Universe) error {
 	}
 
 	m := &model.File{
-		Path:           filepath.Join("controllers", strings.ToLower(u.Resource.Kind)+"_controller.go"),
+		Path:           u.ControllerPath(),
 		Contents:       contents,
 		IfExistsAction: input.Error,
 	}
@@ -3,20 +3,16 @@ package addon
 import (
 	"bytes"
 	"fmt"
-	"strings"
 	"text/template"
 
-	"github.com/gobuffalo/flect"
-
 	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templatefuncs"
 )
 
 // This file gathers functions that are likely to be useful to other
 // plugins.  Once we have validated they are used in more than one
 // place, we can promote them to a shared location.
 
-type PluginFunc func(u *model.Universe) error
-
 // AddFile adds the specified file to the model.
 // If the file exists the function returns false and does not modify the Universe
 // If the file does not exist, the function returns true and adds the file to the Universe
@@ -66,11 +62,7 @@ func ReplaceFile(u *model.Universe, add *model.File) error {
 }
 
 func DefaultTemplateFunctions() template.FuncMap {
-	return template.FuncMap{
-		"title":  strings.Title,
-		"lower":  strings.ToLower,
-		"plural": flect.Pluralize,
-	}
+	return templatefuncs.FuncMap()
 }
 
 func RunTemplate(templateName, templateValue string, data interface{}, funcMap template.FuncMap) (string, error) {
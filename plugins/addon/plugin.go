@@ -2,25 +2,81 @@ package addon
 
 import (
 	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 )
 
-type Plugin struct {
+var _ scaffold.Pattern = Pattern{}
+
+// Pattern scaffolds an API whose controller follows the declarative addon pattern, reconciling the
+// manifests of a channel with sigs.k8s.io/kubebuilder-declarative-pattern
+type Pattern struct{}
+
+// Name implements scaffold.Pattern
+func (Pattern) Name() string {
+	return "addon"
 }
 
-func (p *Plugin) Pipe(u *model.Universe) error {
-	functions := []PluginFunc{
-		ExampleManifest,
-		ExampleChannel,
-		ReplaceController,
-		ReplaceTypes,
+// Description implements scaffold.Pattern
+func (Pattern) Description() string {
+	return "declarative reconciler that applies the manifests of a channel"
+}
+
+// Plugins implements scaffold.Pattern
+func (Pattern) Plugins() []scaffold.Plugin {
+	return []scaffold.Plugin{
+		scaffold.PluginFunc(ExampleManifest),
+		scaffold.PluginFunc(ExampleChannel),
+		scaffold.PluginFunc(ReplaceController),
+		scaffold.PluginFunc(ReplaceTypes),
 	}
+}
 
-	for _, fn := range functions {
-		if err := fn(u); err != nil {
+// Plugin runs the plugins of the addon Pattern as a single plugin
+type Plugin struct {
+}
+
+func (p *Plugin) Pipe(u *model.Universe) error {
+	for _, plugin := range (Pattern{}).Plugins() {
+		if err := plugin.Pipe(u); err != nil {
 			return err
 		}
-
 	}
 
 	return nil
 }
+
+var _ scaffold.ProjectPlugin = DeclarativePlugin{}
+
+// DeclarativePlugin is the project plugin whose APIs follow the declarative addon Pattern, selected with
+// --plugins=go/v2,declarative/v1 when initializing the project
+type DeclarativePlugin struct{}
+
+// Name implements scaffold.ProjectPlugin
+func (DeclarativePlugin) Name() string {
+	return "declarative.kubebuilder.io"
+}
+
+// Version implements scaffold.ProjectPlugin
+func (DeclarativePlugin) Version() string {
+	return "v1"
+}
+
+// Description implements scaffold.ProjectPlugin
+func (DeclarativePlugin) Description() string {
+	return "APIs whose controllers apply the manifests of a channel, like the addon pattern"
+}
+
+// InitPlugins implements scaffold.ProjectPlugin
+func (DeclarativePlugin) InitPlugins() []scaffold.Plugin {
+	return nil
+}
+
+// APIPlugins implements scaffold.ProjectPlugin
+func (DeclarativePlugin) APIPlugins() []scaffold.Plugin {
+	return Pattern{}.Plugins()
+}
+
+// WebhookPlugins implements scaffold.ProjectPlugin
+func (DeclarativePlugin) WebhookPlugins() []scaffold.Plugin {
+	return nil
+}
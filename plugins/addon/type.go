@@ -2,8 +2,6 @@ package addon
 
 import (
 	"fmt"
-	"path/filepath"
-	"strings"
 
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -18,15 +16,8 @@ func ReplaceTypes(u *model.Universe) error {
 		return err
 	}
 
-	var path string
-	if u.Config.MultiGroup {
-		path = filepath.Join("apis", u.Resource.Version, strings.ToLower(u.Resource.Kind)+"_types.go")
-	} else {
-		path = filepath.Join("api", u.Resource.Version, strings.ToLower(u.Resource.Kind)+"_types.go")
-	}
-
 	m := &model.File{
-		Path:           path,
+		Path:           u.TypesPath(),
 		Contents:       contents,
 		IfExistsAction: input.Error,
 	}
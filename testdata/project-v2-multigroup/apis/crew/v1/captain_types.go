@@ -39,6 +39,7 @@ type CaptainStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // Captain is the Schema for the captains API
 type Captain struct {
@@ -61,3 +62,5 @@ type CaptainList struct {
 func init() {
 	SchemeBuilder.Register(&Captain{}, &CaptainList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
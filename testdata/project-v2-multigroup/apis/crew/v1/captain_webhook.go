@@ -73,3 +73,5 @@ func (r *Captain) ValidateDelete() error {
 	// TODO(user): fill in your validation logic upon object deletion.
 	return nil
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/webhook.Webhook
@@ -0,0 +1,268 @@
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// These tests use Ginkgo (BDD-style Go testing framework). Refer to
+// http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
+
+var cfg *rest.Config
+var k8sClient client.Client
+var testEnv *envtest.Environment
+var certDir string
+var stopMgr chan struct{}
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecsWithDefaultAndCustomReporters(t,
+		"Webhook Suite",
+		[]Reporter{printer.NewlineReporter{}})
+}
+
+var _ = BeforeSuite(func(done Done) {
+	logf.SetLogger(zap.LoggerTo(GinkgoWriter, true))
+
+	By("bootstrapping test environment")
+	// The default flags of the API server admit every request without calling the webhooks
+	apiServerFlags := []string{"--enable-admission-plugins=MutatingAdmissionWebhook,ValidatingAdmissionWebhook"}
+	for _, flag := range envtest.DefaultKubeAPIServerFlags {
+		if !strings.HasPrefix(flag, "--admission-control") {
+			apiServerFlags = append(apiServerFlags, flag)
+		}
+	}
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:  []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		KubeAPIServerFlags: apiServerFlags,
+	}
+
+	var err error
+	cfg, err = testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cfg).ToNot(BeNil())
+
+	scheme := runtime.NewScheme()
+	err = AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = admissionregistrationv1beta1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(k8sClient).ToNot(BeNil())
+
+	By("starting the webhook server")
+	certDir, err = ioutil.TempDir("", "webhook-certs")
+	Expect(err).NotTo(HaveOccurred())
+	caBundle, err := writeServingCert(certDir)
+	Expect(err).NotTo(HaveOccurred())
+	port, err := freePort()
+	Expect(err).NotTo(HaveOccurred())
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Host:               "127.0.0.1",
+		Port:               port,
+		CertDir:            certDir,
+		MetricsBindAddress: "0",
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	err = (&Captain{}).SetupWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	// +kubebuilder:scaffold:webhook
+
+	stopMgr = make(chan struct{})
+	go func() {
+		defer GinkgoRecover()
+		err := mgr.Start(stopMgr)
+		Expect(err).NotTo(HaveOccurred())
+	}()
+
+	// The API server rejects the requests until the webhook server is up, as the webhooks fail closed
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	Eventually(func() error {
+		conn, err := tls.Dial("tcp", address, &tls.Config{InsecureSkipVerify: true}) // nolint:gosec
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).Should(Succeed())
+
+	By("installing the webhook configurations")
+	err = installWebhookConfigurations(
+		filepath.Join("..", "..", "..", "config", "webhook", "manifests.yaml"),
+		scheme, "https://"+address, caBundle)
+	Expect(err).NotTo(HaveOccurred())
+
+	close(done)
+}, 60)
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+	if stopMgr != nil {
+		close(stopMgr)
+	}
+	err := testEnv.Stop()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(os.RemoveAll(certDir)).To(Succeed())
+})
+
+// writeServingCert writes a self-signed certificate for 127.0.0.1 in dir, where the webhook server
+// looks for it, and returns it PEM encoded for the API server to trust it.
+func writeServingCert(dir string) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "webhook-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.crt"), certPEM, 0600); err != nil {
+		return nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.key"), keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	return certPEM, nil
+}
+
+// freePort returns a port that is free on 127.0.0.1
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// installWebhookConfigurations creates the webhook configurations generated by "make manifests",
+// calling the webhook server at url instead of the webhook service.
+func installWebhookConfigurations(path string, scheme *runtime.Scheme, url string, caBundle []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return err
+		}
+		switch config := obj.(type) {
+		case *admissionregistrationv1beta1.MutatingWebhookConfiguration:
+			for i := range config.Webhooks {
+				config.Webhooks[i].ClientConfig = testClientConfig(config.Webhooks[i].ClientConfig, url, caBundle)
+			}
+			err = k8sClient.Create(context.Background(), config)
+		case *admissionregistrationv1beta1.ValidatingWebhookConfiguration:
+			for i := range config.Webhooks {
+				config.Webhooks[i].ClientConfig = testClientConfig(config.Webhooks[i].ClientConfig, url, caBundle)
+			}
+			err = k8sClient.Create(context.Background(), config)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// testClientConfig returns the client config calling the path of the webhook service at url
+func testClientConfig(
+	config admissionregistrationv1beta1.WebhookClientConfig,
+	url string,
+	caBundle []byte,
+) admissionregistrationv1beta1.WebhookClientConfig {
+	if config.Service != nil && config.Service.Path != nil {
+		url += *config.Service.Path
+	}
+	return admissionregistrationv1beta1.WebhookClientConfig{URL: &url, CABundle: caBundle}
+}
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/webhook.SuiteTest
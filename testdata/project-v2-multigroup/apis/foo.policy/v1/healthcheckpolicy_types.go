@@ -39,6 +39,7 @@ type HealthCheckPolicyStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // HealthCheckPolicy is the Schema for the healthcheckpolicies API
 type HealthCheckPolicy struct {
@@ -61,3 +62,5 @@ type HealthCheckPolicyList struct {
 func init() {
 	SchemeBuilder.Register(&HealthCheckPolicy{}, &HealthCheckPolicyList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
@@ -39,6 +39,7 @@ type KrakenStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // Kraken is the Schema for the krakens API
 type Kraken struct {
@@ -61,3 +62,5 @@ type KrakenList struct {
 func init() {
 	SchemeBuilder.Register(&Kraken{}, &KrakenList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
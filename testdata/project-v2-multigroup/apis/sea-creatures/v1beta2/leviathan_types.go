@@ -39,6 +39,7 @@ type LeviathanStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // Leviathan is the Schema for the leviathans API
 type Leviathan struct {
@@ -61,3 +62,5 @@ type LeviathanList struct {
 func init() {
 	SchemeBuilder.Register(&Leviathan{}, &LeviathanList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
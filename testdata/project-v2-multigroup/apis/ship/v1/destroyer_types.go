@@ -39,6 +39,7 @@ type DestroyerStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 
 // Destroyer is the Schema for the destroyers API
@@ -62,3 +63,5 @@ type DestroyerList struct {
 func init() {
 	SchemeBuilder.Register(&Destroyer{}, &DestroyerList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
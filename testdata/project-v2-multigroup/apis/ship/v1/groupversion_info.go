@@ -34,3 +34,5 @@ var (
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
 )
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Group
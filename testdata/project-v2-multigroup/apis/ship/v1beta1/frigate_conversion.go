@@ -0,0 +1,29 @@
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Hub = &Frigate{}
+
+// Hub marks this type as a conversion hub. Every other version of Frigate must
+// implement conversion.Convertible converting to and from this version.
+func (*Frigate) Hub() {}
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/webhook.Conversion
@@ -39,6 +39,7 @@ type FrigateStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // Frigate is the Schema for the frigates API
 type Frigate struct {
@@ -61,3 +62,5 @@ type FrigateList struct {
 func init() {
 	SchemeBuilder.Register(&Frigate{}, &FrigateList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
@@ -31,3 +31,5 @@ func (r *Frigate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 }
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/webhook.Webhook
@@ -39,6 +39,7 @@ type CruiserStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 
 // Cruiser is the Schema for the cruisers API
@@ -62,3 +63,5 @@ type CruiserList struct {
 func init() {
 	SchemeBuilder.Register(&Cruiser{}, &CruiserList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
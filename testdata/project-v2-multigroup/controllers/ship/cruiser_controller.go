@@ -39,6 +39,7 @@ type CruiserReconciler struct {
 
 func (r *CruiserReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
+	// The verbosity is set with the --zap-log-level flag of the manager, e.g. log.V(1).Info is logged at debug
 	_ = r.Log.WithValues("cruiser", req.NamespacedName)
 
 	// your logic here
@@ -51,3 +52,5 @@ func (r *CruiserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&shipv2alpha1.Cruiser{}).
 		Complete(r)
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/controller.Controller
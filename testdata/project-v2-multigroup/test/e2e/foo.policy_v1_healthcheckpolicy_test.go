@@ -0,0 +1,59 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("HealthCheckPolicy v1", func() {
+	ctx := context.Background()
+
+	var sample *unstructured.Unstructured
+
+	BeforeEach(func() {
+		var err error
+		sample, err = loadSample(filepath.Join(projectDir, "config", "samples", "foo.policy", "v1_healthcheckpolicy.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		// The sample is created in the namespace of the manager, which may be the only one it watches
+		namespace, err := managerNamespace(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		sample.SetNamespace(namespace)
+	})
+
+	AfterEach(func() {
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, sample))).To(Succeed())
+	})
+
+	It("should accept the sample", func() {
+		Expect(k8sClient.Create(ctx, sample)).To(Succeed())
+
+		// TODO(user): Check the state of the cluster once the sample was reconciled
+	})
+})
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/e2e.APITest
@@ -0,0 +1,78 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// projectDir is the root of the project, relative to the directory of the e2e tests
+const projectDir = "../.."
+
+// loadSample reads an object from a YAML file, e.g. one of the samples under config/samples
+func loadSample(path string) (*unstructured.Unstructured, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(content, &obj.Object); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+	return obj, nil
+}
+
+// managerNamespace returns the namespace the manager is deployed in
+func managerNamespace(ctx context.Context) (string, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := k8sClient.List(ctx, deployments, managerLabels); err != nil {
+		return "", err
+	}
+	if len(deployments.Items) == 0 {
+		return "", fmt.Errorf("the manager Deployment was not found")
+	}
+	return deployments.Items[0].Namespace, nil
+}
+
+// conditionStatus returns the status of the condition with the provided type in the status of obj, or an empty
+// string if it isn't set
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) (string, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return "", err
+	}
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if ok && condition["type"] == conditionType {
+			status, _ := condition["status"].(string)
+			return status, nil
+		}
+	}
+	return "", nil
+}
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/e2e.Utils
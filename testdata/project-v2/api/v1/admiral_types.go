@@ -39,6 +39,7 @@ type AdmiralStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 
 // Admiral is the Schema for the admirals API
@@ -62,3 +63,5 @@ type AdmiralList struct {
 func init() {
 	SchemeBuilder.Register(&Admiral{}, &AdmiralList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
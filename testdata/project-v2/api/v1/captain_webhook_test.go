@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The webhooks fail closed, so every request below goes through the webhook server of the suite.
+var _ = Describe("Captain webhook", func() {
+	It("should default the Captain on creation", func() {
+		ctx := context.Background()
+		instance := &Captain{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaulted-captain", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+		// TODO(user): check the fields set by Default, instance holds the Captain stored by the API server.
+
+		Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+	})
+
+	It("should admit a valid Captain", func() {
+		ctx := context.Background()
+		instance := &Captain{
+			ObjectMeta: metav1.ObjectMeta{Name: "valid-captain", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+	})
+
+	// TODO(user): Add the cases rejected by your validation logic, e.g.
+	// Expect(k8sClient.Create(ctx, invalid)).NotTo(Succeed())
+})
+
+// The table-driven cases below call the webhook methods directly, without going through the API server.
+var _ = Describe("Captain webhook methods", func() {
+	DescribeTable("Default",
+		func(instance, expected *Captain) {
+			instance.Default()
+			Expect(instance).To(Equal(expected))
+		},
+		Entry("keeps an empty Captain unchanged", &Captain{}, &Captain{}),
+		// TODO(user): Add the cases of your defaulting logic, with the Captain to default and the expected one.
+	)
+
+	DescribeTable("ValidateCreate",
+		func(instance *Captain, expectedErr string) {
+			expectCaptainError(instance.ValidateCreate(), expectedErr)
+		},
+		Entry("admits a valid Captain", &Captain{}, ""),
+		// TODO(user): Add the cases rejected by your validation logic, with a substring of the expected error, e.g.
+		// Entry("rejects an invalid Captain", &Captain{...}, "spec.foo: Invalid value"),
+	)
+
+	DescribeTable("ValidateUpdate",
+		func(old, instance *Captain, expectedErr string) {
+			expectCaptainError(instance.ValidateUpdate(old), expectedErr)
+		},
+		Entry("admits an unchanged Captain", &Captain{}, &Captain{}, ""),
+		// TODO(user): Add the updates rejected by your validation logic, e.g. of the immutable fields.
+	)
+
+	DescribeTable("ValidateDelete",
+		func(instance *Captain, expectedErr string) {
+			expectCaptainError(instance.ValidateDelete(), expectedErr)
+		},
+		Entry("admits the deletion of a Captain", &Captain{}, ""),
+	)
+})
+
+// expectCaptainError expects err to contain expectedErr, or to be nil if expectedErr is empty
+func expectCaptainError(err error, expectedErr string) {
+	if expectedErr == "" {
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return
+	}
+	ExpectWithOffset(1, err).To(MatchError(ContainSubstring(expectedErr)))
+}
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/webhook.WebhookTest
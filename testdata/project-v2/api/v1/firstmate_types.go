@@ -39,6 +39,7 @@ type FirstMateStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // FirstMate is the Schema for the firstmates API
 type FirstMate struct {
@@ -61,3 +62,5 @@ type FirstMateList struct {
 func init() {
 	SchemeBuilder.Register(&FirstMate{}, &FirstMateList{})
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2.Types
@@ -39,6 +39,7 @@ type AdmiralReconciler struct {
 
 func (r *AdmiralReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
+	// The verbosity is set with the --zap-log-level flag of the manager, e.g. log.V(1).Info is logged at debug
 	_ = r.Log.WithValues("admiral", req.NamespacedName)
 
 	// your logic here
@@ -51,3 +52,5 @@ func (r *AdmiralReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&crewv1.Admiral{}).
 		Complete(r)
 }
+
+// kubebuilder:provenance version=unknown plugin=go.kubebuilder.io/v2 template=v2/controller.Controller